@@ -0,0 +1,76 @@
+package certs
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"tictactoe-server/config"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Setup builds the TLS configuration shared by the HTTP and gRPC servers, or returns nil when TLS is off.
+// A certificate comes either from TLS_CERT_FILE and TLS_KEY_FILE or from Let's Encrypt for TLS_AUTOCERT_DOMAINS.
+// The returned handler is for the plain HTTP port: it redirects to HTTPS and, with Let's Encrypt,
+// answers http-01 challenges. It is nil when TLS is off or HTTP_REDIRECT_PORT is "0".
+func Setup(cfg *config.Config) (*tls.Config, http.Handler, error) {
+	var tlsConfig *tls.Config
+	var redirect http.Handler
+
+	switch {
+	case (cfg.TLSCertFile != "" || cfg.TLSKeyFile != "") && len(cfg.AutocertDomains) > 0:
+		return nil, nil, errors.New("set TLS_CERT_FILE and TLS_KEY_FILE or TLS_AUTOCERT_DOMAINS, not both")
+
+	case cfg.TLSCertFile != "" || cfg.TLSKeyFile != "":
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return nil, nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"h2", "http/1.1"},
+		}
+		redirect = RedirectHandler(cfg.Port)
+
+	case len(cfg.AutocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+			Email:      cfg.AutocertEmail,
+		}
+		tlsConfig = manager.TLSConfig() // Offers h2 and answers tls-alpn-01 challenges
+		redirect = manager.HTTPHandler(RedirectHandler(cfg.Port))
+
+	default:
+		return nil, nil, nil
+	}
+
+	tlsConfig.MinVersion = tls.VersionTLS12
+	if cfg.HTTPRedirectPort == "0" {
+		redirect = nil
+	}
+	return tlsConfig, redirect, nil
+}
+
+// RedirectHandler sends plain HTTP requests to the same host and path over HTTPS on httpsPort
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		// 308 keeps the method and body, so API POSTs survive the redirect
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,93 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tictactoe-server/config"
+)
+
+// writeSelfSigned writes a throwaway certificate and key for localhost and returns their paths
+func writeSelfSigned(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+func TestSetup(t *testing.T) {
+	if tlsConfig, redirect, err := Setup(&config.Config{Port: "8443"}); tlsConfig != nil || redirect != nil || err != nil {
+		t.Errorf("no certificate configured: %v, %v, %v; want TLS off", tlsConfig, redirect, err)
+	}
+
+	certFile, keyFile := writeSelfSigned(t)
+	tlsConfig, redirect, err := Setup(&config.Config{Port: "8443", TLSCertFile: certFile, TLSKeyFile: keyFile, HTTPRedirectPort: "80"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tlsConfig.Certificates) != 1 || tlsConfig.NextProtos[0] != "h2" || redirect == nil {
+		t.Errorf("tls config = %+v, redirect = %v", tlsConfig, redirect)
+	}
+
+	_, redirect, _ = Setup(&config.Config{Port: "8443", TLSCertFile: certFile, TLSKeyFile: keyFile, HTTPRedirectPort: "0"})
+	if redirect != nil {
+		t.Error("HTTP_REDIRECT_PORT=0 still redirects")
+	}
+
+	for name, cfg := range map[string]*config.Config{
+		"key missing":  {TLSCertFile: certFile},
+		"both sources": {TLSCertFile: certFile, TLSKeyFile: keyFile, AutocertDomains: []string{"example.com"}},
+		"bad files":    {TLSCertFile: keyFile, TLSKeyFile: certFile},
+	} {
+		if _, _, err := Setup(cfg); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	for httpsPort, want := range map[string]string{
+		"443":  "https://play.example.com/api/players/p1?points=10",
+		"8443": "https://play.example.com:8443/api/players/p1?points=10",
+	} {
+		recorder := httptest.NewRecorder()
+		RedirectHandler(httpsPort).ServeHTTP(recorder,
+			httptest.NewRequest(http.MethodPost, "http://play.example.com:80/api/players/p1?points=10", nil))
+		if recorder.Code != http.StatusPermanentRedirect || recorder.Header().Get("Location") != want {
+			t.Errorf("port %s: %d to %q, want %q", httpsPort, recorder.Code, recorder.Header().Get("Location"), want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package clock
+
+import "time"
+
+// Clock is the source of time for game and server logic
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	AfterFunc(d time.Duration, f func()) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a pending AfterFunc call
+type Timer interface {
+	// Stop prevents the call from running; it reports false if it already ran or was stopped
+	Stop() bool
+}
+
+// Ticker delivers ticks at a fixed interval
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is the wall clock backed by the time package
+type Real struct{}
+
+// New returns the wall clock
+func New() Clock {
+	return Real{}
+}
+
+// Now returns the current time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t
+func (Real) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// AfterFunc calls f in its own goroutine after d
+func (Real) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// NewTicker returns a ticker firing every d
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+// realTicker adapts time.Ticker, whose channel is a field, to the Ticker interface
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
@@ -0,0 +1,155 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a manually advanced clock for tests. Timers and tickers only fire
+// from Advance, so timeout logic runs deterministically without real sleeps.
+type Fake struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending timer or ticker
+type fakeWaiter struct {
+	clock    *Fake
+	deadline time.Time
+	interval time.Duration // Non-zero for tickers
+	f        func()        // Set for timers
+	ch       chan time.Time
+}
+
+// NewFake returns a fake clock set to start
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake current time
+func (c *Fake) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// Since returns the fake time elapsed since t
+func (c *Fake) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// AfterFunc schedules f to run when the clock is advanced past d
+func (c *Fake) AfterFunc(d time.Duration, f func()) Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	w := &fakeWaiter{clock: c, deadline: c.now.Add(d), f: f}
+	c.waiters = append(c.waiters, w)
+	return fakeTimer{w}
+}
+
+// NewTicker returns a ticker that fires each time the clock passes another d
+func (c *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	w := &fakeWaiter{clock: c, deadline: c.now.Add(d), interval: d, ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	return fakeTicker{w}
+}
+
+// Advance moves the clock forward by d, firing due timers and tickers in
+// deadline order. Timer functions run synchronously on the caller's goroutine,
+// so the caller must not hold locks they take.
+func (c *Fake) Advance(d time.Duration) {
+	c.mutex.Lock()
+	target := c.now.Add(d)
+	c.mutex.Unlock()
+
+	for {
+		c.mutex.Lock()
+		w := c.nextDue(target)
+		if w == nil {
+			c.now = target
+			c.mutex.Unlock()
+			return
+		}
+		c.now = w.deadline
+		if w.interval > 0 {
+			w.deadline = w.deadline.Add(w.interval)
+			// Like time.Ticker, drop ticks for slow receivers
+			select {
+			case w.ch <- c.now:
+			default:
+			}
+			c.mutex.Unlock()
+			continue
+		}
+		c.remove(w)
+		c.mutex.Unlock()
+		w.f()
+	}
+}
+
+// PendingTimers reports how many timers and tickers are scheduled
+func (c *Fake) PendingTimers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.waiters)
+}
+
+// nextDue returns the earliest waiter due by target. Caller must hold c.mutex.
+func (c *Fake) nextDue(target time.Time) *fakeWaiter {
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+	if len(c.waiters) == 0 || c.waiters[0].deadline.After(target) {
+		return nil
+	}
+	return c.waiters[0]
+}
+
+// remove unschedules w, reporting whether it was pending. Caller must hold c.mutex.
+func (c *Fake) remove(w *fakeWaiter) bool {
+	for i, pending := range c.waiters {
+		if pending == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer is the Timer returned by Fake.AfterFunc
+type fakeTimer struct {
+	waiter *fakeWaiter
+}
+
+// Stop cancels the timer
+func (t fakeTimer) Stop() bool {
+	c := t.waiter.clock
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.remove(t.waiter)
+}
+
+// fakeTicker is the Ticker returned by Fake.NewTicker
+type fakeTicker struct {
+	waiter *fakeWaiter
+}
+
+// C returns the tick channel
+func (t fakeTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+// Stop cancels the ticker
+func (t fakeTicker) Stop() {
+	c := t.waiter.clock
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.remove(t.waiter)
+}
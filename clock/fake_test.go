@@ -0,0 +1,76 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeAfterFunc(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	var fired []string
+	c.AfterFunc(2*time.Second, func() { fired = append(fired, "late") })
+	c.AfterFunc(time.Second, func() { fired = append(fired, "early") })
+	stopped := c.AfterFunc(time.Second, func() { fired = append(fired, "stopped") })
+	if !stopped.Stop() {
+		t.Fatal("Stop on a pending timer returned false")
+	}
+
+	c.Advance(1500 * time.Millisecond)
+	if len(fired) != 1 || fired[0] != "early" {
+		t.Fatalf("after 1.5s fired %v, want [early]", fired)
+	}
+	if got := c.Since(start); got != 1500*time.Millisecond {
+		t.Errorf("Since = %v, want 1.5s", got)
+	}
+
+	c.Advance(time.Second)
+	if len(fired) != 2 || fired[1] != "late" {
+		t.Fatalf("after 2.5s fired %v, want [early late]", fired)
+	}
+	if c.PendingTimers() != 0 {
+		t.Errorf("%d timers still pending", c.PendingTimers())
+	}
+}
+
+func TestFakeTimerSeesDeadlineAsNow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	var at time.Time
+	c.AfterFunc(time.Second, func() { at = c.Now() })
+	c.Advance(time.Minute)
+	if want := start.Add(time.Second); !at.Equal(want) {
+		t.Errorf("timer ran at %v, want %v", at, want)
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	c := NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ticker := c.NewTicker(time.Second)
+
+	c.Advance(500 * time.Millisecond)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticked early")
+	default:
+	}
+
+	// Several intervals pass but an unread channel holds one tick
+	c.Advance(3 * time.Second)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Fatal("ticks were queued for a slow receiver")
+	default:
+	}
+
+	ticker.Stop()
+	c.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Fatal("stopped ticker fired")
+	default:
+	}
+}
@@ -0,0 +1,411 @@
+// Command loadtest simulates many concurrent players against a running server.
+//
+// Each simulated player connects over WebSocket, joins a queue, plays random
+// legal moves, and after each game may drop and resume its session. Latency
+// percentiles and error counts are printed periodically and at the end.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// options are the command-line flags
+type options struct {
+	server        string
+	clients       int
+	rampUp        time.Duration
+	duration      time.Duration
+	mode          string
+	moveDelay     time.Duration
+	reconnectRate float64
+	reportEvery   time.Duration
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.server, "server", "ws://localhost:8080/ws", "WebSocket URL of the game server")
+	flag.IntVar(&opts.clients, "clients", 100, "number of simulated players")
+	flag.DurationVar(&opts.rampUp, "ramp-up", 10*time.Second, "time over which clients connect")
+	flag.DurationVar(&opts.duration, "duration", time.Minute, "how long to run after ramp-up starts")
+	flag.StringVar(&opts.mode, "mode", models.MODE_CASUAL, "queue to join: rated or casual")
+	flag.DurationVar(&opts.moveDelay, "move-delay", 200*time.Millisecond, "think time before each move")
+	flag.Float64Var(&opts.reconnectRate, "reconnect-rate", 0.1, "chance of dropping and resuming the session after each game")
+	flag.DurationVar(&opts.reportEvery, "report", 5*time.Second, "interval between progress reports")
+	flag.Parse()
+
+	if opts.clients <= 0 {
+		log.Fatal("--clients must be positive")
+	}
+
+	run(opts)
+}
+
+// latencies collects durations for percentile reporting
+type latencies struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func (l *latencies) add(d time.Duration) {
+	l.mu.Lock()
+	l.samples = append(l.samples, d)
+	l.mu.Unlock()
+}
+
+// summary formats count and p50/p90/p99/max
+func (l *latencies) summary() string {
+	l.mu.Lock()
+	sorted := append([]time.Duration(nil), l.samples...)
+	l.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return "n=0"
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	return fmt.Sprintf("n=%d p50=%v p90=%v p99=%v max=%v", len(sorted),
+		percentile(0.50).Round(time.Microsecond), percentile(0.90).Round(time.Microsecond),
+		percentile(0.99).Round(time.Microsecond), sorted[len(sorted)-1].Round(time.Microsecond))
+}
+
+// stats are shared across all simulated players
+type stats struct {
+	connected     atomic.Int64 // Currently open connections
+	connects      atomic.Int64
+	connectErrors atomic.Int64
+	reconnects    atomic.Int64
+	gamesFinished atomic.Int64
+	movesSent     atomic.Int64
+	serverErrors  atomic.Int64 // error messages sent by the server
+	dropped       atomic.Int64 // Connections lost without the client asking
+
+	connectLatency latencies // Dial and upgrade
+	matchLatency   latencies // join_queue to game_found
+	moveLatency    latencies // make_move to the game_update showing it
+}
+
+func (s *stats) report(elapsed time.Duration) {
+	sent := s.movesSent.Load()
+	errorRate := 0.0
+	if sent > 0 {
+		errorRate = 100 * float64(s.serverErrors.Load()) / float64(sent)
+	}
+
+	fmt.Printf("[%v] connected=%d connects=%d connect_errors=%d reconnects=%d dropped=%d games=%d moves=%d server_errors=%d (%.2f%%)\n",
+		elapsed.Round(time.Second), s.connected.Load(), s.connects.Load(), s.connectErrors.Load(), s.reconnects.Load(),
+		s.dropped.Load(), s.gamesFinished.Load(), sent, s.serverErrors.Load(), errorRate)
+	fmt.Printf("    connect %s\n", s.connectLatency.summary())
+	fmt.Printf("    match   %s\n", s.matchLatency.summary())
+	fmt.Printf("    move    %s\n", s.moveLatency.summary())
+}
+
+func run(opts options) {
+	st := &stats{}
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	start := time.Now()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		spacing := opts.rampUp / time.Duration(opts.clients)
+		for i := 0; i < opts.clients; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				p := &simPlayer{opts: opts, stats: st, stop: stop, name: "load-" + strconv.Itoa(id)}
+				p.run()
+			}(i)
+			time.Sleep(spacing)
+		}
+	}()
+
+	ticker := time.NewTicker(opts.reportEvery)
+	defer ticker.Stop()
+	deadline := time.After(opts.duration)
+
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			st.report(time.Since(start))
+		case <-deadline:
+			break loop
+		case <-interrupt:
+			break loop
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+
+	fmt.Println("\nFinal results:")
+	st.report(time.Since(start))
+}
+
+// gameState is the part of a game update the simulated player needs
+type gameState struct {
+	GameID    string          `json:"gameId"`
+	Board     []models.Symbol `json:"board"`
+	Status    string          `json:"status"`
+	IsMyTurn  bool            `json:"isMyTurn"`
+	MoveCount int             `json:"moveCount"`
+	MySymbol  models.Symbol   `json:"mySymbol"`
+	Seq       int             `json:"seq"`
+}
+
+// simPlayer is one simulated player; its connection is only used from its own goroutine
+type simPlayer struct {
+	opts  options
+	stats *stats
+	stop  chan struct{}
+	name  string
+
+	conn     *websocket.Conn
+	playerID string
+	token    string
+
+	queuedAt   time.Time
+	moveSentAt time.Time
+	moveCount  int // moveCount of the game after our pending move lands
+
+	current *gameState // Latest state of the game being played, kept up to date by deltas
+}
+
+// run connects, plays until stopped, and reconnects when asked or when dropped
+func (p *simPlayer) run() {
+	for {
+		if !p.connect() {
+			// Back off so a struggling server isn't hammered with dials
+			select {
+			case <-p.stop:
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+
+		reconnect := p.play()
+		p.conn.Close()
+		p.stats.connected.Add(-1)
+
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+		if reconnect {
+			p.stats.reconnects.Add(1)
+		}
+	}
+}
+
+// connect dials the server, resuming the session if there is one
+func (p *simPlayer) connect() bool {
+	serverURL, err := url.Parse(p.opts.server)
+	if err != nil {
+		log.Fatalf("invalid server URL: %v", err)
+	}
+	query := serverURL.Query()
+	query.Set("v", strconv.Itoa(models.PROTOCOL_VERSION_CURRENT))
+	query.Set("name", p.name)
+	if p.playerID != "" {
+		query.Set("playerId", p.playerID)
+		query.Set("token", p.token)
+	}
+	serverURL.RawQuery = query.Encode()
+
+	started := time.Now()
+	conn, _, err := websocket.DefaultDialer.Dial(serverURL.String(), nil)
+	if err != nil {
+		p.stats.connectErrors.Add(1)
+		return false
+	}
+	p.stats.connectLatency.add(time.Since(started))
+	p.stats.connects.Add(1)
+	p.stats.connected.Add(1)
+	p.conn = conn
+	return true
+}
+
+// play handles messages until the connection ends; returns true for a deliberate reconnect
+func (p *simPlayer) play() bool {
+	// Closing the socket is the only way to interrupt a blocked read
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-p.stop:
+			p.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			p.conn.Close()
+		case <-done:
+		}
+	}()
+
+	p.joinQueue()
+	for {
+		var msg models.GameMessage
+		if err := p.conn.ReadJSON(&msg); err != nil {
+			select {
+			case <-p.stop:
+			default:
+				p.stats.dropped.Add(1)
+			}
+			return false
+		}
+
+		switch msg.Type {
+		case models.MSG_SESSION:
+			var session struct {
+				PlayerID string `json:"playerId"`
+				Token    string `json:"token"`
+			}
+			if json.Unmarshal(msg.Data, &session) == nil {
+				p.playerID, p.token = session.PlayerID, session.Token
+			}
+
+		case models.MSG_ERROR:
+			p.stats.serverErrors.Add(1)
+
+		case models.MSG_MOVE_ACK:
+			var ack models.MoveAck
+			if json.Unmarshal(msg.Data, &ack) == nil && !ack.OK {
+				p.stats.serverErrors.Add(1)
+			}
+
+		case models.MSG_GAME_FOUND, models.MSG_GAME_UPDATE:
+			var state gameState
+			if err := json.Unmarshal(msg.Data, &state); err != nil {
+				p.stats.serverErrors.Add(1)
+				continue
+			}
+			if msg.Type == models.MSG_GAME_FOUND && !p.queuedAt.IsZero() {
+				p.stats.matchLatency.add(time.Since(p.queuedAt))
+				p.queuedAt = time.Time{}
+			}
+			p.current = &state
+			if p.onState(&state) {
+				return true
+			}
+
+		case models.MSG_MATCH_PROPOSED:
+			var proposal models.MatchProposal
+			if json.Unmarshal(msg.Data, &proposal) == nil {
+				p.send(models.NewGameMessage(models.MSG_ACCEPT_MATCH, models.MatchPayload{MatchID: proposal.MatchID}))
+			}
+
+		case models.MSG_MATCH_CANCELLED:
+			var cancelled models.MatchCancelled
+			if json.Unmarshal(msg.Data, &cancelled) == nil && !cancelled.Requeued {
+				p.joinQueue()
+			}
+
+		case models.MSG_GAME_DELTA:
+			var delta models.GameDelta
+			if err := json.Unmarshal(msg.Data, &delta); err != nil {
+				p.stats.serverErrors.Add(1)
+				continue
+			}
+			state := p.current
+			if state == nil || state.GameID != delta.GameID {
+				continue
+			}
+			if delta.Seq != state.Seq+1 {
+				p.send(models.NewGameMessageForGame(models.MSG_RESYNC, delta.GameID, models.GamePayload{GameID: delta.GameID}))
+				continue
+			}
+			delta.ApplyBoard(state.Board)
+			state.Seq = delta.Seq
+			state.Status = delta.Status
+			state.MoveCount = delta.MoveCount
+			state.IsMyTurn = delta.Status == models.STATUS_PLAYING && delta.CurrentTurn == state.MySymbol
+			if p.onState(state) {
+				return true
+			}
+		}
+	}
+}
+
+// onState records move latency and plays on from a game state; returns true for a deliberate reconnect
+func (p *simPlayer) onState(state *gameState) bool {
+	if !p.moveSentAt.IsZero() && state.MoveCount >= p.moveCount {
+		p.stats.moveLatency.add(time.Since(p.moveSentAt))
+		p.moveSentAt = time.Time{}
+	}
+
+	switch state.Status {
+	case models.STATUS_FINISHED, models.STATUS_ABORTED, models.STATUS_ABANDONED:
+		p.stats.gamesFinished.Add(1)
+		p.moveSentAt = time.Time{}
+		if rand.Float64() < p.opts.reconnectRate {
+			return true
+		}
+		p.joinQueue()
+	case models.STATUS_PLAYING:
+		if state.IsMyTurn {
+			p.move(state)
+		}
+	}
+	return false
+}
+
+// joinQueue enters the configured queue and starts the matchmaking timer
+func (p *simPlayer) joinQueue() {
+	p.queuedAt = time.Now()
+	p.send(models.NewGameMessage(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: p.opts.mode}))
+}
+
+// move plays a random empty cell after the think time
+func (p *simPlayer) move(state *gameState) {
+	empty := make([]int, 0, len(state.Board))
+	for position, cell := range state.Board {
+		if cell == "" {
+			empty = append(empty, position)
+		}
+	}
+	if len(empty) == 0 {
+		return
+	}
+
+	time.Sleep(p.opts.moveDelay)
+	position := empty[rand.Intn(len(empty))]
+	p.moveSentAt = time.Now()
+	p.moveCount = state.MoveCount + 1
+	p.stats.movesSent.Add(1)
+	p.send(models.NewGameMessageForGame(models.MSG_MAKE_MOVE, state.GameID,
+		models.MakeMovePayload{GameID: state.GameID, Position: &position}))
+}
+
+// send writes a message; failures surface as a read error on the next loop
+func (p *simPlayer) send(msg *models.GameMessage) {
+	p.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	p.conn.WriteJSON(msg)
+}
@@ -0,0 +1,438 @@
+// Command ttt-cli plays Tic-Tac-Toe against the server from a terminal.
+//
+// By default a human plays by typing cell numbers, 1-9 on a 3x3 board and
+// 1-25 in trio games. With --bot the client plays by itself, which is handy
+// for load tests, integration tests and watching the protocol go by with
+// --verbose.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"tictactoe-server/game"
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// gameState is the per-player view sent in game_found and game_update messages
+type gameState struct {
+	GameID       string          `json:"gameId"`
+	Board        []models.Symbol `json:"board"`
+	Size         int             `json:"size"`
+	CurrentTurn  models.Symbol   `json:"currentTurn"`
+	Status       string          `json:"status"`
+	Winner       models.Result   `json:"winner"`
+	MySymbol     models.Symbol   `json:"mySymbol"`
+	OpponentName string          `json:"opponentName"`
+	IsMyTurn     bool            `json:"isMyTurn"`
+	Rated        bool            `json:"rated"`
+	Variant      string          `json:"variant"`
+	CanSwap      bool            `json:"canSwap"` // Pie rule: this player may swap sides instead of moving
+	Seq          int             `json:"seq"`
+}
+
+// options are the command-line flags
+type options struct {
+	server    string
+	name      string
+	mode      string
+	bot       bool
+	games     int
+	moveDelay time.Duration
+	verbose   bool
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.server, "server", "ws://localhost:8080/ws", "WebSocket URL of the game server")
+	flag.StringVar(&opts.name, "name", "", "player name (default \"cli\" or \"cli-bot\")")
+	flag.StringVar(&opts.mode, "mode", models.MODE_RATED, "queue to join: rated, casual, team, misere, trio or blitz")
+	flag.BoolVar(&opts.bot, "bot", false, "play automatically instead of reading moves from stdin")
+	flag.IntVar(&opts.games, "games", 1, "number of games to play before exiting; 0 plays forever")
+	flag.DurationVar(&opts.moveDelay, "move-delay", 0, "pause before each bot move")
+	flag.BoolVar(&opts.verbose, "verbose", false, "print every message received")
+	flag.Parse()
+
+	if opts.name == "" {
+		opts.name = "cli"
+		if opts.bot {
+			opts.name = "cli-bot"
+		}
+	}
+
+	if err := run(opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// client is a connection to the server plus what it knows about the current game
+type client struct {
+	opts   options
+	conn   *websocket.Conn
+	engine *game.GameEngine
+	played int
+
+	current *gameState // Latest state of the game being played, kept up to date by deltas
+}
+
+func run(opts options) error {
+	serverURL, err := url.Parse(opts.server)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+	query := serverURL.Query()
+	query.Set("v", strconv.Itoa(models.PROTOCOL_VERSION_CURRENT))
+	query.Set("name", opts.name)
+	serverURL.RawQuery = query.Encode()
+
+	conn, _, err := websocket.DefaultDialer.Dial(serverURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", opts.server, err)
+	}
+	defer conn.Close()
+
+	c := &client{opts: opts, conn: conn, engine: game.NewGameEngine()}
+
+	messages := make(chan *models.GameMessage)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var msg models.GameMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				readErr <- err
+				return
+			}
+			messages <- &msg
+		}
+	}()
+
+	var input <-chan string
+	if !opts.bot {
+		input = readLines()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	if err := c.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: opts.mode}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case msg := <-messages:
+			done, err := c.handle(msg)
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+
+		case line, ok := <-input:
+			if !ok {
+				return nil
+			}
+			c.handleInput(c.current, line)
+
+		case err := <-readErr:
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return fmt.Errorf("connection lost: %w", err)
+
+		case <-interrupt:
+			conn.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return nil
+		}
+	}
+}
+
+// handle reacts to one server message and reports whether the client is finished
+func (c *client) handle(msg *models.GameMessage) (bool, error) {
+	if c.opts.verbose {
+		fmt.Printf("<- %s %s\n", msg.Type, msg.Data)
+	}
+
+	switch msg.Type {
+	case models.MSG_SESSION:
+		var session struct {
+			PlayerID string `json:"playerId"`
+		}
+		json.Unmarshal(msg.Data, &session)
+		fmt.Printf("Connected as %s (%s)\n", c.opts.name, session.PlayerID)
+
+	case models.MSG_QUEUE_JOINED:
+		fmt.Printf("Waiting for an opponent in the %s queue...\n", c.opts.mode)
+
+	case models.MSG_MATCH_PROPOSED:
+		// Whoever runs the CLI is waiting at the terminal, so matches are accepted for them
+		var proposal models.MatchProposal
+		json.Unmarshal(msg.Data, &proposal)
+		fmt.Println("Match found, accepting...")
+		c.send(models.MSG_ACCEPT_MATCH, models.MatchPayload{MatchID: proposal.MatchID})
+
+	case models.MSG_MATCH_CANCELLED:
+		var cancelled models.MatchCancelled
+		json.Unmarshal(msg.Data, &cancelled)
+		fmt.Printf("Match cancelled (%s), back in the queue\n", cancelled.Reason)
+		if !cancelled.Requeued {
+			c.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: c.opts.mode})
+		}
+
+	case models.MSG_GAME_FOUND, models.MSG_GAME_UPDATE:
+		var state gameState
+		if err := json.Unmarshal(msg.Data, &state); err != nil {
+			return false, fmt.Errorf("bad %s message: %w", msg.Type, err)
+		}
+		if msg.Type == models.MSG_GAME_FOUND {
+			fmt.Printf("\nGame found against %s, you are %s\n", state.OpponentName, state.MySymbol)
+		}
+		c.current = &state
+		return c.showState(&state), nil
+
+	case models.MSG_GAME_DELTA:
+		var delta models.GameDelta
+		if err := json.Unmarshal(msg.Data, &delta); err != nil {
+			return false, fmt.Errorf("bad %s message: %w", msg.Type, err)
+		}
+		return c.applyDelta(&delta), nil
+
+	case models.MSG_OPPONENT_DISCONNECTED:
+		fmt.Println("Opponent disconnected, waiting for them to return...")
+
+	case models.MSG_OPPONENT_RECONNECTED:
+		fmt.Println("Opponent reconnected")
+
+	case models.MSG_TAKEBACK_REQUESTED:
+		fmt.Println("Opponent asked for a takeback (type \"accept\" or \"decline\")")
+
+	case models.MSG_HINT:
+		var hint models.Hint
+		json.Unmarshal(msg.Data, &hint)
+		fmt.Printf("Hint: play %d (%d hints left)\n", hint.Position+1, hint.HintsLeft)
+
+	case models.MSG_MOVE_PROPOSED:
+		var proposal models.MoveProposal
+		json.Unmarshal(msg.Data, &proposal)
+		fmt.Printf("Your team proposed %d, waiting for everyone to agree\n", proposal.Position+1)
+
+	case models.MSG_CLOCK_UPDATE:
+		var update models.ClockUpdate
+		json.Unmarshal(msg.Data, &update)
+		fmt.Printf("Clock: X %.1fs, O %.1fs\n", float64(update.TimeLeftMs[models.SYMBOL_X])/1000, float64(update.TimeLeftMs[models.SYMBOL_O])/1000)
+
+	case models.MSG_ANNOUNCEMENT:
+		fmt.Printf("Announcement: %s\n", msg.Data)
+
+	case models.MSG_ERROR:
+		var body struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(msg.Data, &body)
+		fmt.Printf("Server error: %s\n", body.Error)
+
+	case models.MSG_MOVE_ACK:
+		var ack models.MoveAck
+		json.Unmarshal(msg.Data, &ack)
+		if !ack.OK {
+			fmt.Printf("Move refused: %s\n", ack.Error)
+		}
+
+	case models.MSG_DISCONNECT:
+		var reason models.CloseReason
+		json.Unmarshal(msg.Data, &reason)
+		return true, fmt.Errorf("disconnected by server: %s (code %d)", reason.Reason, reason.Code)
+	}
+
+	return false, nil
+}
+
+// applyDelta updates the current game from a delta, asking for the full state if an update was missed
+func (c *client) applyDelta(delta *models.GameDelta) bool {
+	state := c.current
+	if state == nil || state.GameID != delta.GameID {
+		// A late update for a game we have already moved on from
+		return false
+	}
+	if delta.Seq != state.Seq+1 {
+		c.send(models.MSG_RESYNC, models.GamePayload{GameID: delta.GameID})
+		return false
+	}
+
+	delta.ApplyBoard(state.Board)
+	state.Seq = delta.Seq
+	state.CurrentTurn = delta.CurrentTurn
+	state.Status = delta.Status
+	state.Winner = delta.Winner
+	state.IsMyTurn = delta.Status == models.STATUS_PLAYING && delta.CurrentTurn == state.MySymbol
+	state.CanSwap = delta.SwapPending && state.MySymbol == models.SYMBOL_O
+	return c.showState(state)
+}
+
+// showState prints the board, moves for the bot, and reports whether the client is finished
+func (c *client) showState(state *gameState) bool {
+	fmt.Println(renderBoard(state.Board, state.Size))
+
+	switch state.Status {
+	case models.STATUS_FINISHED, models.STATUS_ABORTED, models.STATUS_ABANDONED:
+		switch {
+		case state.Status == models.STATUS_ABORTED:
+			fmt.Println("Game aborted")
+		case state.Status == models.STATUS_ABANDONED:
+			fmt.Println("Game abandoned")
+		case state.Winner == models.RESULT_DRAW:
+			fmt.Println("Draw!")
+		case state.Winner.WinningSymbol() == state.MySymbol:
+			fmt.Println("You win!")
+		default:
+			fmt.Println("You lose.")
+		}
+
+		c.played++
+		if c.opts.games > 0 && c.played >= c.opts.games {
+			return true
+		}
+		c.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: c.opts.mode})
+
+	case models.STATUS_PAUSED:
+		fmt.Println("Game paused")
+
+	case models.STATUS_PLAYING:
+		if !state.IsMyTurn {
+			fmt.Println("Opponent's turn...")
+			break
+		}
+		if c.opts.bot {
+			time.Sleep(c.opts.moveDelay)
+			c.move(state.GameID, c.botMove(state))
+		} else if state.CanSwap {
+			fmt.Print("Your move (1-9), or \"swap\" to take over X's move: ")
+		} else {
+			fmt.Printf("Your move (1-%d): ", len(state.Board))
+		}
+	}
+	return false
+}
+
+// handleInput interprets a line typed by a human player
+func (c *client) handleInput(state *gameState, line string) {
+	line = strings.TrimSpace(line)
+	if state == nil {
+		fmt.Println("No game in progress yet")
+		return
+	}
+
+	switch line {
+	case "":
+		return
+	case "undo":
+		c.send(models.MSG_REQUEST_TAKEBACK, models.GamePayload{GameID: state.GameID})
+		return
+	case "accept":
+		c.send(models.MSG_ACCEPT_TAKEBACK, models.GamePayload{GameID: state.GameID})
+		return
+	case "decline":
+		c.send(models.MSG_DECLINE_TAKEBACK, models.GamePayload{GameID: state.GameID})
+		return
+	case "hint":
+		c.send(models.MSG_REQUEST_HINT, models.GamePayload{GameID: state.GameID})
+		return
+	case "swap":
+		c.send(models.MSG_SWAP_DECISION, models.SwapDecisionPayload{GameID: state.GameID, Swap: true})
+		return
+	}
+
+	cell, err := strconv.Atoi(line)
+	if err != nil || cell < 1 || cell > len(state.Board) {
+		fmt.Printf("Enter a cell number 1-%d: ", len(state.Board))
+		return
+	}
+	c.move(state.GameID, cell-1)
+}
+
+// botMove picks the bot's move: perfect play on a 3x3 board, a random empty cell on bigger ones
+func (c *client) botMove(state *gameState) int {
+	if state.Size == 3 {
+		var board [9]models.Symbol
+		copy(board[:], state.Board)
+		return c.engine.BotMove(board, state.MySymbol, state.Variant)
+	}
+
+	empty := make([]int, 0, len(state.Board))
+	for position, cell := range state.Board {
+		if cell == models.SYMBOL_NONE {
+			empty = append(empty, position)
+		}
+	}
+	return empty[rand.Intn(len(empty))]
+}
+
+// move sends a make_move for a board position, or a propose_move in team games
+func (c *client) move(gameID string, position int) {
+	msgType := models.MSG_MAKE_MOVE
+	if c.opts.mode == models.MODE_TEAM {
+		msgType = models.MSG_PROPOSE_MOVE
+	}
+	c.send(msgType, models.MakeMovePayload{GameID: gameID, Position: &position})
+}
+
+// send writes a message to the server
+func (c *client) send(msgType string, payload interface{}) error {
+	return c.conn.WriteJSON(models.NewGameMessage(msgType, payload))
+}
+
+// renderBoard draws a size x size board, numbering empty cells from 1
+func renderBoard(board []models.Symbol, size int) string {
+	width := len(strconv.Itoa(len(board))) // Cell numbers on a 5x5 board take two characters
+	divider := strings.Repeat("-", width+2)
+	for col := 1; col < size; col++ {
+		divider += "+" + strings.Repeat("-", width+2)
+	}
+
+	var b strings.Builder
+	for row := 0; row < size; row++ {
+		if row > 0 {
+			b.WriteString(divider + "\n")
+		}
+		for col := 0; col < size; col++ {
+			position := row*size + col
+			cell := string(board[position])
+			if cell == "" {
+				cell = strconv.Itoa(position + 1)
+			}
+			if col > 0 {
+				b.WriteString("|")
+			}
+			b.WriteString(fmt.Sprintf(" %*s ", width, cell))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// readLines streams lines typed on stdin
+func readLines() <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
@@ -0,0 +1,19 @@
+// Command vapid-keys generates a VAPID key pair for Web Push notifications.
+//
+// The output can be appended to .env as is.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"tictactoe-server/push"
+)
+
+func main() {
+	public, private, err := push.GenerateKeys()
+	if err != nil {
+		log.Fatalf("Generating keys: %v", err)
+	}
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\nVAPID_PRIVATE_KEY=%s\n", public, private)
+}
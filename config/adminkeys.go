@@ -0,0 +1,46 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// Roles of admin API keys, from least to most privileged; each may do everything the ones before it may
+const (
+	ROLE_VIEWER    = "viewer"    // Reads games, connections, metrics, flags, reports and maintenance and drain status
+	ROLE_MODERATOR = "moderator" // Also kicks, bans and mutes players, ends games, reviews reports and announces
+	ROLE_ADMIN     = "admin"     // Everything else: ratings, maintenance, drains, config, the audit log and keys
+)
+
+// ROLES lists the admin roles from least to most privileged
+var ROLES = []string{ROLE_VIEWER, ROLE_MODERATOR, ROLE_ADMIN}
+
+// AdminKey is an API key for the /admin API
+type AdminKey struct {
+	Name string // Who holds the key, recorded in the audit log
+	Role string // One of ROLES
+	Key  string
+}
+
+// RoleAllows reports whether a role has at least the privileges of the required one
+func RoleAllows(role, required string) bool {
+	rank := slices.Index(ROLES, role)
+	return rank >= 0 && rank >= slices.Index(ROLES, required)
+}
+
+// getAdminKeys reads a comma-separated list of name:role:key entries, dropping malformed ones and repeated names
+func getAdminKeys(key string) []AdminKey {
+	var keys []AdminKey
+	for _, entry := range getList(key) {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" || !slices.Contains(ROLES, parts[1]) ||
+			slices.ContainsFunc(keys, func(k AdminKey) bool { return k.Name == parts[0] }) {
+			// Only the name is logged, never the key
+			rejectInvalid("Ignoring invalid admin key %q in %s, expected name:role:key with a role of %s",
+				parts[0], key, strings.Join(ROLES, ", "))
+			continue
+		}
+		keys = append(keys, AdminKey{Name: parts[0], Role: parts[1], Key: parts[2]})
+	}
+	return keys
+}
@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestGetAdminKeys(t *testing.T) {
+	t.Setenv("ADMIN_KEYS", "vera:viewer:v-secret, mo:moderator:m:secret, bad:root:x, vera:admin:again, nokey:admin:")
+	keys := getAdminKeys("ADMIN_KEYS")
+	if len(keys) != 2 || keys[0] != (AdminKey{"vera", ROLE_VIEWER, "v-secret"}) || keys[1] != (AdminKey{"mo", ROLE_MODERATOR, "m:secret"}) {
+		t.Errorf("keys = %+v, want vera and mo only", keys)
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	for _, tt := range []struct {
+		role, required string
+		want           bool
+	}{
+		{ROLE_ADMIN, ROLE_VIEWER, true},
+		{ROLE_MODERATOR, ROLE_MODERATOR, true},
+		{ROLE_MODERATOR, ROLE_ADMIN, false},
+		{ROLE_VIEWER, ROLE_MODERATOR, false},
+		{"root", ROLE_VIEWER, false},
+	} {
+		if got := RoleAllows(tt.role, tt.required); got != tt.want {
+			t.Errorf("RoleAllows(%s, %s) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds server settings loaded from environment variables
+type Config struct {
+	Port                  string
+	GRPCPort              string        // Port for the gRPC API; "0" disables it
+	TLSCertFile           string        // PEM certificate served on Port and GRPCPort; set with TLSKeyFile to enable TLS
+	TLSKeyFile            string        // PEM private key of TLSCertFile
+	AutocertDomains       []string      // Domains to obtain Let's Encrypt certificates for, instead of TLSCertFile
+	AutocertCacheDir      string        // Where Let's Encrypt certificates and the account key are kept between restarts
+	AutocertEmail         string        // Contact address given to Let's Encrypt; optional
+	HTTPRedirectPort      string        // Plain HTTP port redirected to HTTPS while TLS is on; "0" disables it
+	AllowedOrigins        Origins       // Origins the REST API answers cross-origin requests from
+	WebSocketOrigins      Origins       // Origins browsers may open WebSocket connections from; requests without an Origin are always allowed
+	TrustedProxies        Proxies       // Reverse proxies whose X-Forwarded-For and X-Real-IP headers name the real client
+	StaticFrontend        string        // STATIC_EMBEDDED, a directory of frontend files served from /, or empty to serve none
+	DisconnectGracePeriod time.Duration // How long a disconnected player has to return before forfeiting
+	ResumeWindow          time.Duration // How long messages pushed to a player are kept to replay when they reconnect; 0 disables
+	AdminToken            string        // Bearer token for the /admin API with the admin role, shared by its holders
+	BotBackfillAfter      time.Duration // Queue wait before matching against a bot; 0 disables bots
+	EventRetention        time.Duration // How long game event logs are kept after their last event
+	IdleTimeout           time.Duration // Connections that send nothing for this long are closed; 0 disables
+	DuplicateLoginPolicy  string        // What to do when a player's session connects twice
+
+	RecentOpponentWindow   time.Duration // How long a pairing counts as recent
+	RecentOpponentPolicy   string        // How strictly recent pairings are avoided
+	RecentOpponentMinQueue int           // Below this queue size recent opponents may always be paired
+
+	AbandonAfter          time.Duration // How long a game may run with neither player connected
+	AbandonedGamePolicy   string        // How abandoned games are resolved
+	FinishedGameRetention time.Duration // How long ended games stay in memory for late viewers
+
+	StateFile     string        // Where queues and games in progress are saved at shutdown and restored from; empty disables
+	RestoreWindow time.Duration // How old saved state may be to restore, and how long restored queue places are held
+
+	MaxConnections int // Open connections allowed at once; 0 is unlimited
+	MaxActiveGames int // Games in progress allowed at once; further matches wait in the queue; 0 is unlimited
+	MaxQueueLength int // Players allowed in each matchmaking queue; 0 is unlimited
+
+	ScaleUpPercent   int // Use of the busiest limit at which /api/capacity recommends scaling up
+	ScaleDownPercent int // Use of the busiest limit below which /api/capacity recommends scaling down
+
+	MaintenanceLead time.Duration // How long before a scheduled maintenance window matchmaking stops
+
+	AdminAuditFile string // File admin operations are appended to as JSON lines; empty keeps them in memory only
+
+	AdminKeys []AdminKey // Named /admin API keys with their roles; with no keys and no AdminToken the API is disabled
+
+	MaxConnectionsPerIP      int           // Open connections allowed from one address; 0 is unlimited
+	ConnectAttemptsPerMinute int           // Connection attempts allowed from one address per minute; 0 is unlimited
+	ThrottleBanDuration      time.Duration // How long an address over the attempt limit is refused; 0 only refuses the excess
+
+	FastMoveThreshold time.Duration // Replies quicker than this count toward a fast-move streak
+	FastMoveStreak    int           // Consecutive fast replies that flag a rated game; 0 disables
+	SameIPPolicy      string        // Whether rated games between players on one IP are flagged
+	SpectatorDelay    time.Duration // How far spectators of rated games lag behind the players; 0 shows moves live
+
+	SeasonLength     time.Duration // How long a ranked season lasts; 0 means one endless season
+	SeasonResetKeep  int           // Percent of a rating's distance from the default kept at rollover
+	PlacementGames   int           // Rated games per season played with the placement K-factor
+	PlacementKFactor int           // Elo K-factor during placement games
+
+	RatingSystem string // How rated games move ratings, RATING_ELO or RATING_GLICKO2
+
+	ProvisionalGames   int    // Rated games before a player's rating is established; 0 makes every rating established
+	ProvisionalKFactor int    // Elo K-factor while a player is provisional
+	LeaderboardPolicy  string // Who leaderboards and season standings rank
+
+	EmotesEnabled bool          // Whether players may send emotes; independent of chat
+	EmoteCooldown time.Duration // Minimum time between a player's emotes in one game
+
+	HintsPerGame int // Hints each player may request per casual game; 0 disables hints
+
+	ReplayMoveInterval time.Duration // Time between moves of a replay at normal speed; 0 sends them all at once
+
+	InviteTTL time.Duration // How long an invite link stays usable
+
+	MatchLatencyTolerance time.Duration // Players whose round trips differ by at most this are preferred as opponents; 0 ignores latency
+	QueueIdleTimeout      time.Duration // Queued players who send nothing for this long are taken out of the queue; 0 disables
+	MatchAcceptTimeout    time.Duration // How long matched players have to accept before the match is cancelled; 0 starts games at once
+
+	LobbyStatsInterval time.Duration // How often changed lobby statistics are pushed to clients; 0 disables them
+
+	TeamMoveTimeout time.Duration // How long a team has to agree on a move before its first proposal is played
+
+	PieRule bool // Whether O may swap sides instead of replying to X's first move in matched games
+
+	TrioWinLength int // Marks in a row that win a trio game on its 5x5 board, 3 or 4
+
+	BlitzTime time.Duration // Total thinking time each player gets for a whole blitz game
+
+	UniqueNames          bool     // Whether new players get a numeric suffix when an online player has their name
+	BlockedWords         []string // Words refused in names and masked in chat
+	ModerationWebhookURL string   // External service that moderates names and chat; empty disables it
+
+	ReportBanThreshold int           // Verified reports by different players that ban a player from matchmaking; 0 disables bans
+	ReportBanWindow    time.Duration // How far back verified reports count toward a ban
+	ReportBanDuration  time.Duration // How long a matchmaking ban lasts
+
+	LeaverPenaltyThreshold int           // Games left within LeaverWindow that start escalating queue cooldowns; 0 disables them
+	LeaverWindow           time.Duration // How long a game left by forfeit or abandonment counts toward cooldowns
+
+	TurnReminderAfter time.Duration // How long a turn may run before the player to move gets a turn_reminder; 0 disables reminders
+
+	StaleGameAfter   time.Duration // How long an untimed game may go without a move before it is adjudicated; 0 disables expiry
+	StaleGameWarning time.Duration // How long before adjudication the players are sent a game_expiring notice
+	StaleGamePolicy  string        // How stale games are adjudicated, see STALE_GAMES_*
+
+	CorrespondenceMoveTime time.Duration // Time allowed for each move of a correspondence game before it is forfeited
+	MaxCorrespondenceGames int           // Correspondence games a player may have in progress at once; 0 means no limit
+
+	LobbyTTL time.Duration // How long a lobby stays open without anyone joining
+
+	ChallengeTTL time.Duration // How long a challenge's latest proposal waits for an answer
+
+	OTLPEndpoint string // OTLP/gRPC collector that receives traces; empty disables tracing
+
+	WebhookURLs        []string      // Subscribers posted game and player events; empty disables webhooks
+	WebhookSecret      string        // Key for the HMAC-SHA256 signature on each delivery; empty leaves them unsigned
+	WebhookMaxAttempts int           // Deliveries tried per subscriber before an event is dropped
+	WebhookBackoff     time.Duration // Wait before the first retry, doubled for each later one
+	StatsReportURLs    []string      // Operator URLs posted each day's and week's stats summary; empty disables reports
+	HeatmapInterval    time.Duration // How often the move heatmaps are aggregated from finished games; 0 disables them
+
+	VAPIDPublicKey  string        // Web Push VAPID public key, base64url; empty disables push notifications
+	VAPIDPrivateKey string        // Web Push VAPID private key, base64url
+	VAPIDSubject    string        // Contact push services can reach the operator at, a mailto: or https URL
+	PushTTL         time.Duration // How long push services hold a notification for a browser that is offline
+
+	DiscordWebhookURL          string        // Discord channel webhook that receives results and leaderboards; empty disables posting
+	DiscordResults             string        // Which finished games are posted to Discord, see DISCORD_RESULTS_*
+	DiscordLeaderboardInterval time.Duration // How often the leaderboard is posted to Discord; 0 disables it
+	DiscordPublicKey           string        // Hex public key of the Discord application; empty disables slash commands
+	DiscordAPIURL              string        // Base URL slash commands use to reach this server's REST API
+
+	OAuthClients    map[string]OAuthClient // OAuth apps players can log in with, by provider; providers without one are off
+	OAuthBaseURL    string                 // Public URL of this server providers send players back to; empty uses the request's host
+	OAuthSuccessURL string                 // Page players land on after logging in, their session in the fragment; empty answers with JSON
+
+	WSCompression         bool // Whether WebSocket clients may negotiate permessage-deflate
+	WSCompressionLevel    int  // Deflate level of compressed frames, 1 (fastest) to 9 (smallest)
+	WSCompressionMinBytes int  // Frames smaller than this are sent uncompressed, where deflate saves little
+}
+
+// Same-IP policies
+const (
+	SAME_IP_FLAG = "flag" // Flag the game and make it unrated
+	SAME_IP_OFF  = "off"  // Ignore shared IPs, e.g. for LAN events
+)
+
+// Rating systems
+const (
+	RATING_ELO     = "elo"     // Elo with placement K-factors
+	RATING_GLICKO2 = "glicko2" // Glicko-2, which also tracks how certain each rating is
+)
+
+// Leaderboard policies; either way players still in placement are left off
+const (
+	LEADERBOARD_ESTABLISHED = "established" // Only players whose ratings are no longer provisional
+	LEADERBOARD_PLAYED      = "played"      // Anyone with a rated game this season, provisional or not
+)
+
+// STATIC_EMBEDDED serves the frontend compiled into the binary from web/dist
+const STATIC_EMBEDDED = "embedded"
+
+// Abandoned game policies
+const (
+	ABANDONED_GAMES_VOID = "void" // End the game as abandoned, without a result
+	ABANDONED_GAMES_DRAW = "draw" // Finalize the game as a draw
+)
+
+// Stale game policies
+const (
+	STALE_GAMES_DRAW    = "draw"    // Finalize the game as a draw
+	STALE_GAMES_FORFEIT = "forfeit" // The side to move loses
+)
+
+// Recent opponent policies
+const (
+	RECENT_OPPONENTS_OFF    = "off"    // Pair strictly in queue order
+	RECENT_OPPONENTS_PREFER = "prefer" // Skip recent opponents when someone else is waiting
+	RECENT_OPPONENTS_STRICT = "strict" // Never repeat a recent pairing unless the queue is small
+)
+
+// Which finished games are posted to Discord
+const (
+	DISCORD_RESULTS_RATED = "rated" // Only rated games
+	DISCORD_RESULTS_ALL   = "all"   // Every game with a result
+	DISCORD_RESULTS_OFF   = "off"   // None; only leaderboards are posted
+)
+
+// Duplicate login policies
+const (
+	DUPLICATE_LOGIN_REJECT   = "reject"   // Refuse the new connection
+	DUPLICATE_LOGIN_TRANSFER = "transfer" // Close the old connection and move the session to the new one
+)
+
+// load reads configuration through lookup, falling back to defaults for missing and invalid settings
+func load() *Config {
+	cfg := &Config{
+		Port:                  getEnv("PORT", "8080"),
+		GRPCPort:              getEnv("GRPC_PORT", "9090"),
+		TLSCertFile:           lookup("TLS_CERT_FILE"),
+		TLSKeyFile:            lookup("TLS_KEY_FILE"),
+		AutocertDomains:       getList("TLS_AUTOCERT_DOMAINS"),
+		AutocertCacheDir:      getEnv("TLS_AUTOCERT_CACHE_DIR", "autocert-cache"),
+		AutocertEmail:         lookup("TLS_AUTOCERT_EMAIL"),
+		HTTPRedirectPort:      getEnv("HTTP_REDIRECT_PORT", "80"),
+		AllowedOrigins:        getOrigins("FRONTEND_URL", Origins{"http://localhost:3000"}), // Default for local development
+		WebSocketOrigins:      getOrigins("WS_ALLOWED_ORIGINS", Origins{ANY_ORIGIN}),
+		TrustedProxies:        getProxies("TRUSTED_PROXIES"),
+		StaticFrontend:        lookup("STATIC_FRONTEND"),
+		DisconnectGracePeriod: getDuration("DISCONNECT_GRACE_SECONDS", 30*time.Second),
+		ResumeWindow:          getDuration("RESUME_WINDOW_SECONDS", 30*time.Second),
+		AdminToken:            lookup("ADMIN_TOKEN"),
+		BotBackfillAfter:      getDuration("BOT_BACKFILL_SECONDS", 0),
+		EventRetention:        getDuration("EVENT_RETENTION_SECONDS", 7*24*time.Hour),
+		IdleTimeout:           getDuration("IDLE_TIMEOUT_SECONDS", 10*time.Minute),
+		DuplicateLoginPolicy: getChoice("DUPLICATE_LOGIN_POLICY", DUPLICATE_LOGIN_TRANSFER,
+			DUPLICATE_LOGIN_REJECT, DUPLICATE_LOGIN_TRANSFER),
+
+		RecentOpponentWindow: getDuration("RECENT_OPPONENT_WINDOW_SECONDS", 5*time.Minute),
+		RecentOpponentPolicy: getChoice("RECENT_OPPONENT_POLICY", RECENT_OPPONENTS_PREFER,
+			RECENT_OPPONENTS_OFF, RECENT_OPPONENTS_PREFER, RECENT_OPPONENTS_STRICT),
+		RecentOpponentMinQueue: getInt("RECENT_OPPONENT_MIN_QUEUE", 3),
+
+		AbandonAfter: getDuration("ABANDON_AFTER_SECONDS", time.Minute),
+		AbandonedGamePolicy: getChoice("ABANDONED_GAME_POLICY", ABANDONED_GAMES_VOID,
+			ABANDONED_GAMES_VOID, ABANDONED_GAMES_DRAW),
+		FinishedGameRetention: getDuration("FINISHED_GAME_RETENTION_SECONDS", 5*time.Minute),
+
+		StateFile:     lookup("STATE_FILE"),
+		RestoreWindow: getDuration("RESTORE_WINDOW_SECONDS", 2*time.Minute),
+
+		MaxConnections: getInt("MAX_CONNECTIONS", 0),
+		MaxActiveGames: getInt("MAX_ACTIVE_GAMES", 0),
+		MaxQueueLength: getInt("MAX_QUEUE_LENGTH", 0),
+
+		ScaleUpPercent:   getInt("SCALE_UP_PERCENT", 80),
+		ScaleDownPercent: getInt("SCALE_DOWN_PERCENT", 30),
+
+		MaintenanceLead: getDuration("MAINTENANCE_LEAD_SECONDS", 10*time.Minute),
+
+		AdminAuditFile: lookup("ADMIN_AUDIT_FILE"),
+
+		AdminKeys: getAdminKeys("ADMIN_KEYS"),
+
+		MaxConnectionsPerIP:      getInt("MAX_CONNECTIONS_PER_IP", 20),
+		ConnectAttemptsPerMinute: getInt("CONNECT_ATTEMPTS_PER_MINUTE", 60),
+		ThrottleBanDuration:      getDuration("THROTTLE_BAN_SECONDS", 5*time.Minute),
+
+		FastMoveThreshold: getMillis("FAST_MOVE_THRESHOLD_MS", 100*time.Millisecond),
+		FastMoveStreak:    getInt("FAST_MOVE_STREAK", 3),
+		SameIPPolicy:      getChoice("SAME_IP_POLICY", SAME_IP_FLAG, SAME_IP_FLAG, SAME_IP_OFF),
+		SpectatorDelay:    getDuration("SPECTATOR_DELAY_SECONDS", 5*time.Second),
+
+		SeasonLength:     getDuration("SEASON_LENGTH_SECONDS", 28*24*time.Hour),
+		SeasonResetKeep:  getInt("SEASON_RESET_KEEP_PERCENT", 50),
+		PlacementGames:   getInt("PLACEMENT_GAMES", 5),
+		PlacementKFactor: getInt("PLACEMENT_K_FACTOR", 64),
+
+		RatingSystem: getChoice("RATING_SYSTEM", RATING_ELO, RATING_ELO, RATING_GLICKO2),
+
+		ProvisionalGames:   getInt("PROVISIONAL_GAMES", 10),
+		ProvisionalKFactor: getInt("PROVISIONAL_K_FACTOR", 48),
+		LeaderboardPolicy:  getChoice("LEADERBOARD_POLICY", LEADERBOARD_ESTABLISHED, LEADERBOARD_ESTABLISHED, LEADERBOARD_PLAYED),
+
+		EmotesEnabled: getChoice("EMOTES", "on", "on", "off") == "on",
+		EmoteCooldown: getMillis("EMOTE_COOLDOWN_MS", time.Second),
+
+		HintsPerGame: getInt("HINTS_PER_GAME", 3),
+
+		ReplayMoveInterval: getMillis("REPLAY_MOVE_INTERVAL_MS", time.Second),
+
+		InviteTTL: getDuration("INVITE_TTL_SECONDS", 10*time.Minute),
+
+		MatchLatencyTolerance: getMillis("MATCH_LATENCY_TOLERANCE_MS", 50*time.Millisecond),
+		QueueIdleTimeout:      getDuration("QUEUE_IDLE_SECONDS", 5*time.Minute),
+		MatchAcceptTimeout:    getDuration("MATCH_ACCEPT_SECONDS", 10*time.Second),
+
+		LobbyStatsInterval: getDuration("LOBBY_STATS_SECONDS", 5*time.Second),
+
+		TeamMoveTimeout: getDuration("TEAM_MOVE_SECONDS", 15*time.Second),
+
+		PieRule: getChoice("PIE_RULE", "off", "on", "off") == "on",
+
+		TrioWinLength: getInt("TRIO_WIN_LENGTH", 4),
+
+		BlitzTime: getDuration("BLITZ_SECONDS", 60*time.Second),
+
+		UniqueNames:          getChoice("UNIQUE_NAMES", "off", "on", "off") == "on",
+		BlockedWords:         getList("BLOCKED_WORDS"),
+		ModerationWebhookURL: lookup("MODERATION_WEBHOOK_URL"),
+
+		ReportBanThreshold: getInt("REPORT_BAN_THRESHOLD", 3),
+		ReportBanWindow:    getDuration("REPORT_BAN_WINDOW_SECONDS", 7*24*time.Hour),
+		ReportBanDuration:  getDuration("REPORT_BAN_SECONDS", 24*time.Hour),
+
+		LeaverPenaltyThreshold: getInt("LEAVER_PENALTY_THRESHOLD", 3),
+		LeaverWindow:           getDuration("LEAVER_WINDOW_SECONDS", 24*time.Hour),
+
+		TurnReminderAfter: getDuration("TURN_REMINDER_SECONDS", 30*time.Second),
+
+		StaleGameAfter:   getDuration("STALE_GAME_SECONDS", 30*time.Minute),
+		StaleGameWarning: getDuration("STALE_GAME_WARNING_SECONDS", 5*time.Minute),
+		StaleGamePolicy:  getChoice("STALE_GAME_POLICY", STALE_GAMES_DRAW, STALE_GAMES_DRAW, STALE_GAMES_FORFEIT),
+
+		CorrespondenceMoveTime: getDuration("CORRESPONDENCE_MOVE_SECONDS", 72*time.Hour),
+		MaxCorrespondenceGames: getInt("MAX_CORRESPONDENCE_GAMES", 10),
+
+		LobbyTTL: getDuration("LOBBY_TTL_SECONDS", 10*time.Minute),
+
+		ChallengeTTL: getDuration("CHALLENGE_TTL_SECONDS", 2*time.Minute),
+
+		OTLPEndpoint: lookup("OTEL_EXPORTER_OTLP_ENDPOINT"),
+
+		WebhookURLs:        getList("WEBHOOK_URLS"),
+		WebhookSecret:      lookup("WEBHOOK_SECRET"),
+		WebhookMaxAttempts: getInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		WebhookBackoff:     getMillis("WEBHOOK_BACKOFF_MS", time.Second),
+		StatsReportURLs:    getList("STATS_REPORT_URLS"),
+		HeatmapInterval:    getDuration("STATS_HEATMAP_SECONDS", time.Hour),
+
+		VAPIDPublicKey:  lookup("VAPID_PUBLIC_KEY"),
+		VAPIDPrivateKey: lookup("VAPID_PRIVATE_KEY"),
+		VAPIDSubject:    lookup("VAPID_SUBJECT"),
+		PushTTL:         getDuration("PUSH_TTL_SECONDS", 5*time.Minute),
+
+		DiscordWebhookURL: lookup("DISCORD_WEBHOOK_URL"),
+		DiscordResults: getChoice("DISCORD_RESULTS", DISCORD_RESULTS_RATED,
+			DISCORD_RESULTS_RATED, DISCORD_RESULTS_ALL, DISCORD_RESULTS_OFF),
+		DiscordLeaderboardInterval: getDuration("DISCORD_LEADERBOARD_SECONDS", 24*time.Hour),
+		DiscordPublicKey:           lookup("DISCORD_PUBLIC_KEY"),
+
+		OAuthClients:    getOAuthClients(),
+		OAuthBaseURL:    strings.TrimSuffix(lookup("OAUTH_BASE_URL"), "/"),
+		OAuthSuccessURL: lookup("OAUTH_SUCCESS_URL"),
+
+		WSCompression:         getChoice("WS_COMPRESSION", "on", "on", "off") == "on",
+		WSCompressionLevel:    getInt("WS_COMPRESSION_LEVEL", 1),
+		WSCompressionMinBytes: getInt("WS_COMPRESSION_MIN_BYTES", 256),
+	}
+	cfg.DiscordAPIURL = getEnv("DISCORD_API_URL", "http://localhost:"+cfg.Port)
+
+	if cfg.SeasonResetKeep > 100 {
+		rejectInvalid("Invalid SEASON_RESET_KEEP_PERCENT=%d, using 100", cfg.SeasonResetKeep)
+		cfg.SeasonResetKeep = 100
+	}
+
+	if cfg.TrioWinLength != 3 && cfg.TrioWinLength != 4 {
+		rejectInvalid("Invalid TRIO_WIN_LENGTH=%d, using 4", cfg.TrioWinLength)
+		cfg.TrioWinLength = 4
+	}
+
+	if cfg.CorrespondenceMoveTime == 0 {
+		rejectInvalid("Invalid CORRESPONDENCE_MOVE_SECONDS=0, using 259200")
+		cfg.CorrespondenceMoveTime = 72 * time.Hour
+	}
+
+	if cfg.WebhookMaxAttempts == 0 {
+		rejectInvalid("Invalid WEBHOOK_MAX_ATTEMPTS=0, using 1")
+		cfg.WebhookMaxAttempts = 1
+	}
+
+	if cfg.WSCompressionLevel < 1 || cfg.WSCompressionLevel > 9 {
+		rejectInvalid("Invalid WS_COMPRESSION_LEVEL=%d, using 1", cfg.WSCompressionLevel)
+		cfg.WSCompressionLevel = 1
+	}
+
+	return cfg
+}
+
+// getEnv returns an environment variable or a default value
+func getEnv(key, fallback string) string {
+	if value := lookup(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getList reads a comma-separated list from an environment variable, dropping blank entries
+func getList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(lookup(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getInt reads a non-negative integer from an environment variable
+func getInt(key string, fallback int) int {
+	value := lookup(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		rejectInvalid("Invalid %s=%q, using default %d", key, value, fallback)
+		return fallback
+	}
+	return number
+}
+
+// getChoice reads an environment variable that must be one of the allowed values
+func getChoice(key, fallback string, allowed ...string) string {
+	value := getEnv(key, fallback)
+	for _, choice := range allowed {
+		if value == choice {
+			return value
+		}
+	}
+
+	rejectInvalid("Invalid %s=%q, using default %q", key, value, fallback)
+	return fallback
+}
+
+// getDuration reads a whole number of seconds from an environment variable
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := lookup(key)
+	if value == "" {
+		return fallback
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		rejectInvalid("Invalid %s=%q, using default %v", key, value, fallback)
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// getMillis reads a whole number of milliseconds from an environment variable
+func getMillis(key string, fallback time.Duration) time.Duration {
+	value := lookup(key)
+	if value == "" {
+		return fallback
+	}
+
+	millis, err := strconv.Atoi(value)
+	if err != nil || millis < 0 {
+		rejectInvalid("Invalid %s=%q, using default %v", key, value, fallback)
+		return fallback
+	}
+	return time.Duration(millis) * time.Millisecond
+}
@@ -0,0 +1,36 @@
+package config
+
+import "strings"
+
+// OAuth providers players can log in with
+const (
+	OAUTH_GOOGLE  = "google"
+	OAUTH_GITHUB  = "github"
+	OAUTH_DISCORD = "discord"
+)
+
+// OAUTH_PROVIDERS lists the OAuth providers
+var OAUTH_PROVIDERS = []string{OAUTH_GOOGLE, OAUTH_GITHUB, OAUTH_DISCORD}
+
+// OAuthClient is the OAuth app registered with a provider
+type OAuthClient struct {
+	ID     string
+	Secret string
+}
+
+// getOAuthClients reads OAUTH_<PROVIDER>_CLIENT_ID and OAUTH_<PROVIDER>_CLIENT_SECRET for each of OAUTH_PROVIDERS,
+// leaving out providers without both
+func getOAuthClients() map[string]OAuthClient {
+	clients := make(map[string]OAuthClient)
+	for _, provider := range OAUTH_PROVIDERS {
+		prefix := "OAUTH_" + strings.ToUpper(provider)
+		client := OAuthClient{ID: lookup(prefix + "_CLIENT_ID"), Secret: lookup(prefix + "_CLIENT_SECRET")}
+		switch {
+		case client.ID != "" && client.Secret != "":
+			clients[provider] = client
+		case client.ID != "" || client.Secret != "":
+			rejectInvalid("Ignoring %s login: set both %s_CLIENT_ID and %s_CLIENT_SECRET", provider, prefix, prefix)
+		}
+	}
+	return clients
+}
@@ -0,0 +1,13 @@
+package config
+
+import "testing"
+
+func TestGetOAuthClients(t *testing.T) {
+	t.Setenv("OAUTH_GITHUB_CLIENT_ID", "gh-id")
+	t.Setenv("OAUTH_GITHUB_CLIENT_SECRET", "gh-secret")
+	t.Setenv("OAUTH_GOOGLE_CLIENT_ID", "no-secret")
+	clients := getOAuthClients()
+	if len(clients) != 1 || clients[OAUTH_GITHUB] != (OAuthClient{"gh-id", "gh-secret"}) {
+		t.Errorf("clients = %+v, want GitHub only", clients)
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ANY_ORIGIN in an origin list allows every origin
+const ANY_ORIGIN = "*"
+
+// Origins lists the browser origins allowed to reach the server
+// Entries are exact origins such as https://app.example.com, wildcard subdomains such as
+// https://*.example.com (any depth of subdomain, not the bare domain), or ANY_ORIGIN
+type Origins []string
+
+// Allows reports whether a request's Origin header matches one of the entries
+func (o Origins) Allows(origin string) bool {
+	scheme, host, ok := splitOrigin(origin)
+	if !ok {
+		return false
+	}
+
+	for _, entry := range o {
+		if entry == ANY_ORIGIN {
+			return true
+		}
+		entryScheme, entryHost, _ := splitOrigin(entry)
+		if entryScheme != scheme {
+			continue
+		}
+		if suffix, wildcard := strings.CutPrefix(entryHost, "*"); wildcard {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if entryHost == host {
+			return true
+		}
+	}
+	return false
+}
+
+// splitOrigin lowercases an origin and splits it into scheme and host with any port
+func splitOrigin(origin string) (scheme, host string, ok bool) {
+	parsed, err := url.Parse(strings.ToLower(strings.TrimSuffix(origin, "/")))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" || (parsed.Path != "" && parsed.Path != "/") {
+		return "", "", false
+	}
+	return parsed.Scheme, parsed.Host, true
+}
+
+// getOrigins reads a comma-separated origin list, dropping entries that aren't origins or origin patterns
+func getOrigins(key string, fallback Origins) Origins {
+	entries := getList(key)
+	if len(entries) == 0 {
+		return fallback
+	}
+
+	var origins Origins
+	for _, entry := range entries {
+		if entry == ANY_ORIGIN || validOriginPattern(entry) {
+			origins = append(origins, strings.ToLower(strings.TrimSuffix(entry, "/")))
+			continue
+		}
+		rejectInvalid("Ignoring invalid origin %q in %s", entry, key)
+	}
+	if len(origins) == 0 {
+		rejectInvalid("No valid origins in %s, using default %v", key, fallback)
+		return fallback
+	}
+	return origins
+}
+
+// validOriginPattern accepts scheme://host[:port] where the host may start with a "*." wildcard label
+func validOriginPattern(entry string) bool {
+	_, host, ok := splitOrigin(strings.Replace(entry, "://*.", "://wildcard.", 1))
+	return ok && !strings.Contains(host, "*")
+}
@@ -0,0 +1,42 @@
+package config
+
+import "testing"
+
+func TestOriginsAllows(t *testing.T) {
+	origins := Origins{"https://app.example.com", "https://*.myapp.dev", "http://localhost:3000"}
+	for origin, want := range map[string]bool{
+		"https://app.example.com":      true,
+		"https://APP.example.com/":     true,
+		"http://app.example.com":       false, // Scheme must match
+		"https://preview.myapp.dev":    true,
+		"https://a.b.myapp.dev":        true,
+		"https://myapp.dev":            false, // The wildcard needs a subdomain
+		"https://evilmyapp.dev":        false,
+		"https://myapp.dev.evil.com":   false,
+		"http://localhost:3000":        true,
+		"http://localhost:3001":        false,
+		"null":                         false,
+		"https://app.example.com/path": false,
+	} {
+		if got := origins.Allows(origin); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", origin, got, want)
+		}
+	}
+
+	if !(Origins{ANY_ORIGIN}).Allows("https://anything.example") {
+		t.Error("* did not allow every origin")
+	}
+}
+
+func TestGetOrigins(t *testing.T) {
+	t.Setenv("FRONTEND_URL", "https://app.example.com/, https://*.myapp.dev, not-an-origin, https://a*b.com")
+	origins := getOrigins("FRONTEND_URL", Origins{"http://localhost:3000"})
+	if len(origins) != 2 || origins[0] != "https://app.example.com" || origins[1] != "https://*.myapp.dev" {
+		t.Errorf("origins = %v", origins)
+	}
+
+	t.Setenv("FRONTEND_URL", "garbage")
+	if origins := getOrigins("FRONTEND_URL", Origins{"http://localhost:3000"}); len(origins) != 1 || origins[0] != "http://localhost:3000" {
+		t.Errorf("all-invalid list gave %v, want the default", origins)
+	}
+}
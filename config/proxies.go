@@ -0,0 +1,39 @@
+package config
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// Proxies lists the reverse proxies whose forwarding headers are believed, as IP ranges
+type Proxies []netip.Prefix
+
+// Trusts reports whether an address belongs to a trusted proxy
+func (p Proxies) Trusts(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// getProxies reads a comma-separated list of IPs and CIDR ranges, dropping entries that are neither
+func getProxies(key string) Proxies {
+	var proxies Proxies
+	for _, entry := range getList(key) {
+		if !strings.Contains(entry, "/") {
+			if ip, err := netip.ParseAddr(entry); err == nil {
+				ip = ip.Unmap()
+				proxies = append(proxies, netip.PrefixFrom(ip, ip.BitLen()))
+				continue
+			}
+		} else if prefix, err := netip.ParsePrefix(entry); err == nil {
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+		rejectInvalid("Ignoring invalid proxy %q in %s", entry, key)
+	}
+	return proxies
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestGetProxies(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10, ::1, not-an-ip, 172.16.5.4/12")
+	proxies := getProxies("TRUSTED_PROXIES")
+	if len(proxies) != 4 {
+		t.Fatalf("proxies = %v, want 4 entries", proxies)
+	}
+
+	for ip, want := range map[string]bool{
+		"10.200.0.1":          true,
+		"192.168.1.10":        true,
+		"192.168.1.11":        false,
+		"::1":                 true,
+		"::ffff:192.168.1.10": true, // IPv4-mapped addresses match their IPv4 entries
+		"172.31.255.255":      true, // Host bits of a range are ignored
+		"203.0.113.1":         false,
+	} {
+		if got := proxies.Trusts(netip.MustParseAddr(ip)); got != want {
+			t.Errorf("Trusts(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"tictactoe-server/models"
+)
+
+// CONFIG_FILE, if set in the environment, names a file of KEY=VALUE lines like .env.example whose settings
+// take precedence over the environment's; it is read at startup and again on every reload
+
+var (
+	loadMutex sync.Mutex  // Serializes loads, which share lookup and rejected
+	lookup    = os.Getenv // Where the load in progress reads settings from
+	rejected  []string    // Settings the load in progress found invalid
+)
+
+// RELOADABLE are the settings a reload applies to a running server: timers, rate limits, matchmaking parameters
+// and allowed origins; the rest take a restart
+var RELOADABLE = []string{
+	"DisconnectGracePeriod", "AbandonAfter", "FinishedGameRetention", "TurnReminderAfter",
+	"StaleGameAfter", "StaleGameWarning", "LobbyTTL", "ChallengeTTL", "InviteTTL", "TeamMoveTimeout",
+	"SpectatorDelay", "EmoteCooldown", "MaintenanceLead",
+
+	"MaxConnections", "MaxActiveGames", "MaxQueueLength",
+	"MaxConnectionsPerIP", "ConnectAttemptsPerMinute", "ThrottleBanDuration",
+	"LeaverPenaltyThreshold", "LeaverWindow",
+
+	"RecentOpponentWindow", "RecentOpponentPolicy", "RecentOpponentMinQueue",
+	"MatchLatencyTolerance", "MatchAcceptTimeout", "QueueIdleTimeout", "BotBackfillAfter",
+
+	"AllowedOrigins", "WebSocketOrigins",
+}
+
+// Load reads configuration from the environment and CONFIG_FILE, falling back to defaults
+func Load() *Config {
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			log.Printf("Ignoring CONFIG_FILE: %v", err)
+		} else {
+			useConfigFile(values)
+			defer func() { lookup = os.Getenv }()
+		}
+	}
+	return load()
+}
+
+// Reload reads configuration again like Load, but fails instead of falling back to defaults
+// when CONFIG_FILE can't be read or a setting is invalid
+func Reload() (*Config, error) {
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		useConfigFile(values)
+		defer func() { lookup = os.Getenv }()
+	}
+
+	rejected = nil
+	cfg := load()
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(rejected, "; "))
+	}
+	return cfg, nil
+}
+
+// useConfigFile makes the load in progress read a config file's settings ahead of the environment's
+func useConfigFile(values map[string]string) {
+	lookup = func(key string) string {
+		if value, set := values[key]; set {
+			return value
+		}
+		return os.Getenv(key)
+	}
+}
+
+// readConfigFile parses a file of KEY=VALUE lines; blank lines and # comments are skipped,
+// and a value may be wrapped in quotes
+func readConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// rejectInvalid logs a setting the load in progress can't use and remembers it for Reload
+func rejectInvalid(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Print(message)
+	rejected = append(rejected, message)
+}
+
+// Apply copies the RELOADABLE settings of next into c and returns the ones that changed
+// Call it wherever c is read, as it changes c in place
+func (c *Config) Apply(next *Config) []models.SettingChange {
+	current, updated := reflect.ValueOf(c).Elem(), reflect.ValueOf(next).Elem()
+	var changes []models.SettingChange
+	for _, name := range RELOADABLE {
+		from, to := current.FieldByName(name), updated.FieldByName(name)
+		if reflect.DeepEqual(from.Interface(), to.Interface()) {
+			continue
+		}
+		changes = append(changes, models.SettingChange{
+			Setting: name,
+			From:    fmt.Sprint(from.Interface()),
+			To:      fmt.Sprint(to.Interface()),
+		})
+		from.Set(to)
+	}
+	return changes
+}
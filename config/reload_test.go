@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes a CONFIG_FILE for the test and points the environment at it
+func writeConfigFile(t *testing.T, contents string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "server.env")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+}
+
+func TestReloadReadsConfigFile(t *testing.T) {
+	t.Setenv("MAX_QUEUE_LENGTH", "7")
+	t.Setenv("MAX_CONNECTIONS", "5")
+	writeConfigFile(t, "# Limits\nMAX_CONNECTIONS=50\n\nWS_ALLOWED_ORIGINS=\"https://play.example.com\"\n")
+
+	cfg, err := Reload()
+	if err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	if cfg.MaxConnections != 50 || cfg.MaxQueueLength != 7 {
+		t.Errorf("limits = %d connections, %d per queue; want the file's 50 and the environment's 7", cfg.MaxConnections, cfg.MaxQueueLength)
+	}
+	if !cfg.WebSocketOrigins.Allows("https://play.example.com") || cfg.WebSocketOrigins.Allows("https://other.example.com") {
+		t.Errorf("WebSocket origins = %v", cfg.WebSocketOrigins)
+	}
+}
+
+func TestReloadRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		contents string
+		want     string
+	}{
+		{"MAX_CONNECTIONS=lots\n", "MAX_CONNECTIONS"},
+		{"SAME_IP_POLICY=sometimes\n", "SAME_IP_POLICY"},
+		{"FRONTEND_URL=not a url\n", "FRONTEND_URL"},
+		{"just some words\n", "expected KEY=VALUE"},
+	}
+	for _, tt := range tests {
+		writeConfigFile(t, tt.contents)
+		if _, err := Reload(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Reload() with %q = %v, want an error about %s", tt.contents, err, tt.want)
+		}
+		// Load carries on with defaults
+		if cfg := Load(); cfg == nil {
+			t.Errorf("Load() with %q = nil", tt.contents)
+		}
+	}
+}
+
+func TestApplyCopiesOnlyReloadableSettings(t *testing.T) {
+	current := &Config{Port: "8080", MaxConnections: 10, AbandonAfter: time.Minute}
+	next := &Config{Port: "9000", MaxConnections: 20, AbandonAfter: time.Minute}
+
+	changes := current.Apply(next)
+	if len(changes) != 1 || changes[0].Setting != "MaxConnections" || changes[0].From != "10" || changes[0].To != "20" {
+		t.Errorf("changes = %+v, want MaxConnections 10 -> 20 only", changes)
+	}
+	if current.MaxConnections != 20 || current.Port != "8080" {
+		t.Errorf("after Apply: MaxConnections %d, Port %q", current.MaxConnections, current.Port)
+	}
+}
@@ -0,0 +1,152 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	queueSize       = 64              // Messages waiting to be posted; later ones are dropped while it is full
+	maxPostAttempts = 3               // Tries per message when Discord rate-limits the webhook
+	maxRetryAfter   = 5 * time.Second // Longest rate-limit wait honored before a message is dropped
+	maxContentRunes = 2000            // Discord's limit on a message's content
+)
+
+// Client posts messages to a Discord channel through one of its incoming webhooks
+type Client struct {
+	URL    string
+	Client *http.Client
+
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewClient creates a client for the webhook at url and starts posting
+func NewClient(url string) *Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan string, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Post queues a message for the channel without waiting for Discord
+func (c *Client) Post(content string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.queue <- truncate(content):
+	default:
+		log.Printf("Discord queue full, dropping message")
+	}
+}
+
+// Close stops accepting messages and waits for queued ones to be posted
+// When ctx ends first, the remaining messages are dropped
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		c.cancel()
+		<-c.done
+		return ctx.Err()
+	}
+}
+
+// run posts queued messages in order so the channel reads chronologically
+func (c *Client) run() {
+	defer close(c.done)
+	for content := range c.queue {
+		if err := c.post(content); err != nil {
+			log.Printf("Discord post failed: %v", err)
+		}
+	}
+}
+
+// post sends one message, waiting out rate limits
+func (c *Client) post(content string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"content":          content,
+		"allowed_mentions": map[string][]string{"parse": {}}, // Player names must never ping anyone
+	})
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= maxPostAttempts {
+			return fmt.Errorf("discord returned %s", resp.Status)
+		}
+
+		wait := retryAfter(resp)
+		if wait > maxRetryAfter {
+			return fmt.Errorf("discord rate limit of %v is too long to wait", wait)
+		}
+		select {
+		case <-time.After(wait):
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		}
+	}
+}
+
+// retryAfter reads how long Discord asked us to back off, in seconds that may be fractional
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+	if err != nil || seconds < 0 {
+		return time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// truncate keeps content within Discord's message limit
+func truncate(content string) string {
+	runes := []rune(content)
+	if len(runes) <= maxContentRunes {
+		return content
+	}
+	return string(runes[:maxContentRunes-1]) + "…"
+}
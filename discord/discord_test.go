@@ -0,0 +1,121 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestClientPostsAndWaitsOutRateLimits(t *testing.T) {
+	var mu sync.Mutex
+	var posted []string
+	limited := false
+	channel := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !limited {
+			limited = true
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		var message struct{ Content string }
+		json.NewDecoder(r.Body).Decode(&message)
+		posted = append(posted, message.Content)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer channel.Close()
+
+	client := NewClient(channel.URL)
+	client.Post("first")
+	client.Post("second")
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posted) != 2 || posted[0] != "first" || posted[1] != "second" {
+		t.Errorf("posted %q, want first then second", posted)
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	game := models.NewGame()
+	game.Players = []*models.Player{{Name: "al*ice"}, {Name: "bob"}}
+	game.Rated = true
+	game.Winner = "O"
+	game.Moves = make([]models.MoveRecord, 6)
+	if got, want := FormatResult(game), `🏆 **bob** (O) beat **al\*ice** (X) in a rated game · 6 moves`; got != want {
+		t.Errorf("win = %q, want %q", got, want)
+	}
+
+	game.Winner = "draw"
+	if got := FormatResult(game); !strings.HasPrefix(got, `🤝 **al\*ice** (X) and **bob** (O) drew`) {
+		t.Errorf("draw = %q", got)
+	}
+}
+
+func TestInteractionHandler(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/players/p1" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(models.PlayerProfile{
+			Player:      &models.Player{Name: "alice", Ratings: map[string]int{models.RATING_POOL_STANDARD: 1234}, Wins: 3, Losses: 1},
+			GamesPlayed: 4,
+			WinRate:     0.75,
+		})
+	}))
+	defer api.Close()
+
+	handler, err := NewInteractionHandler(hex.EncodeToString(publicKey), api.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(body string, sign bool) (int, interactionResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/integrations/discord", bytes.NewBufferString(body))
+		req.Header.Set("X-Signature-Timestamp", "1700000000")
+		signature := make([]byte, ed25519.SignatureSize)
+		if sign {
+			signature = ed25519.Sign(privateKey, []byte("1700000000"+body))
+		}
+		req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		var response interactionResponse
+		json.Unmarshal(recorder.Body.Bytes(), &response)
+		return recorder.Code, response
+	}
+
+	if code, _ := call(`{"type":1}`, false); code != http.StatusUnauthorized {
+		t.Errorf("unsigned ping answered %d", code)
+	}
+	if _, response := call(`{"type":1}`, true); response.Type != RESPONSE_PONG {
+		t.Errorf("ping answered %+v", response)
+	}
+
+	_, response := call(`{"type":2,"data":{"name":"stats","options":[{"name":"player","value":"p1"}]}}`, true)
+	if response.Type != RESPONSE_CHANNEL_MESSAGE || !strings.Contains(response.Data.Content, "**alice** · rating 1234") ||
+		!strings.Contains(response.Data.Content, "75% wins") {
+		t.Errorf("stats answered %+v", response.Data)
+	}
+
+	_, response = call(`{"type":2,"data":{"name":"stats","options":[{"name":"player","value":"nobody"}]}}`, true)
+	if response.Data == nil || response.Data.Content != "No player has that ID" {
+		t.Errorf("unknown player answered %+v", response.Data)
+	}
+}
@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"tictactoe-server/models"
+)
+
+// FormatResult describes a finished game for the channel
+func FormatResult(game *models.Game) string {
+	kind := "casual"
+	if game.Rated {
+		kind = "rated"
+	}
+
+	var winner *models.Player
+	var others []string
+	for i, player := range game.Players {
+		if models.Symbols[i] == game.Winner.WinningSymbol() {
+			winner = player
+			continue
+		}
+		others = append(others, playerLabel(player, models.Symbols[i]))
+	}
+
+	moves := fmt.Sprintf("%d moves", len(game.Moves))
+	if winner == nil {
+		return fmt.Sprintf("🤝 %s drew a %s game · %s", joinNames(others), kind, moves)
+	}
+	return fmt.Sprintf("🏆 %s beat %s in a %s game · %s",
+		playerLabel(winner, game.Winner.WinningSymbol()), joinNames(others), kind, moves)
+}
+
+// FormatLeaderboard lists a season's top players with their ratings and records
+func FormatLeaderboard(season int, players []*models.Player) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📊 **Season %d leaderboard**", season)
+	for i, player := range players {
+		fmt.Fprintf(&b, "\n%d. %s · %d (%dW %dL %dD)",
+			i+1, escape(player.Name), player.Rating(models.RATING_POOL_STANDARD), player.Wins, player.Losses, player.Draws)
+	}
+	return b.String()
+}
+
+// FormatStats summarizes a player's profile as a slash command reply
+func FormatStats(profile *models.PlayerProfile) string {
+	player := profile.Player
+	stats := fmt.Sprintf("**%s** · rating %d\n%d games · %dW %dL %dD · %.0f%% wins · streak %d (best %d)",
+		escape(player.Name), player.Rating(models.RATING_POOL_STANDARD), profile.GamesPlayed, player.Wins, player.Losses, player.Draws,
+		profile.WinRate*100, profile.CurrentStreak, profile.LongestStreak)
+	if profile.PlacementGamesLeft > 0 {
+		stats += fmt.Sprintf("\nUnranked: %d placement games left in season %d", profile.PlacementGamesLeft, profile.Season)
+	}
+	return stats
+}
+
+// playerLabel formats a player with the symbol they played, e.g. **alice** (X)
+func playerLabel(player *models.Player, symbol models.Symbol) string {
+	return fmt.Sprintf("**%s** (%s)", escape(player.Name), symbol)
+}
+
+// joinNames lists names as "a", "a and b" or "a, b and c"
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// markdownEscaper neutralizes Discord markdown in player-chosen names
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`, ">", `\>`,
+)
+
+// escape makes a player name render literally
+func escape(name string) string {
+	return markdownEscaper.Replace(name)
+}
@@ -0,0 +1,191 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// Interaction and response types from the Discord API
+const (
+	INTERACTION_PING                = 1
+	INTERACTION_APPLICATION_COMMAND = 2
+
+	RESPONSE_PONG            = 1
+	RESPONSE_CHANNEL_MESSAGE = 4
+)
+
+// Slash commands answered by the interaction handler
+const (
+	COMMAND_STATS       = "stats"  // /stats player:<id> replies with the player's profile summary
+	OPTION_STATS_PLAYER = "player" // Player ID option of /stats
+)
+
+// maxInteractionSize bounds the request bodies read from Discord
+const maxInteractionSize = 64 * 1024
+
+// errPlayerNotFound is returned when the REST API has no such player
+var errPlayerNotFound = errors.New("player not found")
+
+// interaction is the part of a Discord interaction the handler reads
+type interaction struct {
+	Type int `json:"type"`
+	Data struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string          `json:"name"`
+			Value json.RawMessage `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+// interactionResponse is the reply sent back to Discord
+type interactionResponse struct {
+	Type int                      `json:"type"`
+	Data *interactionResponseData `json:"data,omitempty"`
+}
+
+type interactionResponseData struct {
+	Content         string              `json:"content"`
+	AllowedMentions map[string][]string `json:"allowed_mentions"`
+}
+
+// InteractionHandler is the interactions endpoint of a Discord application
+// It answers slash commands by querying the server's REST API, as any other client would
+type InteractionHandler struct {
+	PublicKey ed25519.PublicKey
+	APIURL    string // Base URL of the REST API, e.g. http://localhost:8080
+	Client    *http.Client
+}
+
+// NewInteractionHandler creates a handler for the application with the given hex-encoded public key
+func NewInteractionHandler(publicKey, apiURL string) (*InteractionHandler, error) {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("discord public key must be 64 hex characters")
+	}
+	return &InteractionHandler{
+		PublicKey: key,
+		APIURL:    strings.TrimSuffix(apiURL, "/"),
+		Client:    &http.Client{Timeout: 2 * time.Second}, // Discord gives up on replies after 3 seconds
+	}, nil
+}
+
+// ServeHTTP verifies that a request was signed by Discord and answers it
+func (h *InteractionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInteractionSize))
+	if err != nil {
+		http.Error(w, "unreadable body", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var request interaction
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, "malformed interaction", http.StatusBadRequest)
+		return
+	}
+
+	var response interactionResponse
+	switch request.Type {
+	case INTERACTION_PING:
+		response = interactionResponse{Type: RESPONSE_PONG}
+	case INTERACTION_APPLICATION_COMMAND:
+		response = reply(h.runCommand(&request))
+	default:
+		http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// verify checks Discord's Ed25519 signature over the timestamp and body
+func (h *InteractionHandler) verify(signature, timestamp string, body []byte) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize || timestamp == "" {
+		return false
+	}
+	return ed25519.Verify(h.PublicKey, append([]byte(timestamp), body...), sig)
+}
+
+// runCommand answers a slash command with the text to post
+func (h *InteractionHandler) runCommand(request *interaction) string {
+	if request.Data.Name != COMMAND_STATS {
+		return "Unknown command"
+	}
+
+	var playerID string
+	for _, option := range request.Data.Options {
+		if option.Name == OPTION_STATS_PLAYER {
+			json.Unmarshal(option.Value, &playerID)
+		}
+	}
+	playerID = strings.TrimSpace(playerID)
+	if playerID == "" {
+		return "Usage: /stats player:<player id>"
+	}
+
+	profile, err := h.fetchProfile(playerID)
+	switch {
+	case errors.Is(err, errPlayerNotFound):
+		return "No player has that ID"
+	case err != nil:
+		log.Printf("Discord /stats lookup failed: %v", err)
+		return "Stats are unavailable right now"
+	}
+	return FormatStats(profile)
+}
+
+// fetchProfile reads a player's profile from GET /api/players/{id}
+func (h *InteractionHandler) fetchProfile(playerID string) (*models.PlayerProfile, error) {
+	resp, err := h.Client.Get(h.APIURL + "/api/players/" + url.PathEscape(playerID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errPlayerNotFound
+	default:
+		return nil, fmt.Errorf("player API returned %s", resp.Status)
+	}
+
+	var profile models.PlayerProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("malformed profile: %v", err)
+	}
+	if profile.Player == nil {
+		return nil, errors.New("profile without a player")
+	}
+	return &profile, nil
+}
+
+// reply wraps text as a channel message that mentions nobody
+func reply(content string) interactionResponse {
+	return interactionResponse{
+		Type: RESPONSE_CHANNEL_MESSAGE,
+		Data: &interactionResponseData{Content: truncate(content), AllowedMentions: map[string][]string{"parse": {}}},
+	}
+}
@@ -0,0 +1,198 @@
+package game
+
+import (
+	"sync"
+
+	"tictactoe-server/models"
+)
+
+// solution holds the value of every position reachable with X moving first under one variant, for the player to move
+type solution struct {
+	once   sync.Once
+	values map[[9]models.Symbol]int
+}
+
+// solutions are solved on first use, one per variant
+var solutions = map[string]*solution{
+	models.VARIANT_STANDARD: {},
+	models.VARIANT_MISERE:   {},
+}
+
+// lineValue scores a finished line for the player to move, who did not complete it: -1 normally, 1 under misère rules
+func lineValue(variant string) int {
+	if variant == models.VARIANT_MISERE {
+		return 1
+	}
+	return -1
+}
+
+// positionValue scores the board under perfect play for the player to move: 1 win, 0 draw, -1 loss
+func (ge *GameEngine) positionValue(board [9]models.Symbol, toMove models.Symbol, variant string) int {
+	solved, known := solutions[variant]
+	if !known {
+		solved = solutions[models.VARIANT_STANDARD]
+	}
+	solved.once.Do(func() {
+		solved.values = make(map[[9]models.Symbol]int)
+		ge.solve(solved.values, [9]models.Symbol{}, models.SYMBOL_X, variant)
+	})
+	if value, exists := solved.values[board]; exists {
+		return value
+	}
+	return ge.minimax(board, toMove, variant)
+}
+
+// solve scores the board like minimax, filling values as it goes
+func (ge *GameEngine) solve(values map[[9]models.Symbol]int, board [9]models.Symbol, toMove models.Symbol, variant string) int {
+	if value, solved := values[board]; solved {
+		return value
+	}
+
+	value := -2
+	switch {
+	case ge.CheckWinner(board) != models.SYMBOL_NONE:
+		value = lineValue(variant)
+	case ge.IsBoardFull(board):
+		value = 0
+	default:
+		for position, cell := range board {
+			if cell != models.SYMBOL_NONE {
+				continue
+			}
+			board[position] = toMove
+			score := -ge.solve(values, board, otherSymbol(toMove), variant)
+			board[position] = models.SYMBOL_NONE
+			if score > value {
+				value = score
+			}
+		}
+	}
+
+	values[board] = value
+	return value
+}
+
+// scoreMoves scores every empty cell for symbol under perfect play and lists the cells with the best score
+func (ge *GameEngine) scoreMoves(board [9]models.Symbol, symbol models.Symbol, variant string) (map[int]int, []int) {
+	scores := make(map[int]int)
+	best := -2
+	var bestMoves []int
+	for position, cell := range board {
+		if cell != models.SYMBOL_NONE {
+			continue
+		}
+		board[position] = symbol
+		score := -ge.positionValue(board, otherSymbol(symbol), variant)
+		board[position] = models.SYMBOL_NONE
+		scores[position] = score
+
+		if score > best {
+			best = score
+			bestMoves = bestMoves[:0]
+		}
+		if score == best {
+			bestMoves = append(bestMoves, position)
+		}
+	}
+	return scores, bestMoves
+}
+
+// BestMove returns the lowest-numbered cell that keeps the best result for symbol, or -1 if the board is full
+func (ge *GameEngine) BestMove(board [9]models.Symbol, symbol models.Symbol, variant string) int {
+	_, bestMoves := ge.scoreMoves(board, symbol, variant)
+	if len(bestMoves) == 0 {
+		return -1
+	}
+	return bestMoves[0]
+}
+
+// GradeMove judges a move against perfect play and returns the moves that would have kept the best result
+func (ge *GameEngine) GradeMove(board [9]models.Symbol, symbol models.Symbol, position int, variant string) (string, []int) {
+	scores, bestMoves := ge.scoreMoves(board, symbol, variant)
+	played, best := scores[position], -2
+	if len(bestMoves) > 0 {
+		best = scores[bestMoves[0]]
+	}
+
+	switch {
+	case played == best:
+		return models.MOVE_OPTIMAL, bestMoves
+	case played == -1:
+		return models.MOVE_BLUNDER, bestMoves
+	default:
+		return models.MOVE_MISTAKE, bestMoves
+	}
+}
+
+// AnalyzeGame replays a game's moves and grades each one against perfect play under the game's variant
+// Returns nil unless the game is two players on a 3x3 board, the only games the solver covers
+func (ge *GameEngine) AnalyzeGame(game *models.Game) *models.GameAnalysis {
+	if !game.IsClassic() {
+		return nil
+	}
+
+	analysis := &models.GameAnalysis{
+		Moves: make([]models.MoveAnalysis, 0, len(game.Moves)),
+		Players: []models.PlayerAnalysis{
+			{Symbol: models.SYMBOL_X},
+			{Symbol: models.SYMBOL_O},
+		},
+	}
+	if game.PlayerX() != nil {
+		analysis.Players[0].PlayerID = game.PlayerX().ID
+	}
+	if game.PlayerO() != nil {
+		analysis.Players[1].PlayerID = game.PlayerO().ID
+	}
+
+	var board [9]models.Symbol
+	for _, move := range game.Moves {
+		quality, bestMoves := ge.GradeMove(board, move.Symbol, move.Position, game.Variant)
+		board[move.Position] = move.Symbol
+		analysis.Moves = append(analysis.Moves, models.MoveAnalysis{
+			Symbol:    move.Symbol,
+			Position:  move.Position,
+			Quality:   quality,
+			BestMoves: bestMoves,
+		})
+
+		player := &analysis.Players[0]
+		if move.Symbol == models.SYMBOL_O {
+			player = &analysis.Players[1]
+		}
+		player.Moves++
+		switch quality {
+		case models.MOVE_OPTIMAL:
+			player.Optimal++
+		case models.MOVE_MISTAKE:
+			player.Mistakes++
+		case models.MOVE_BLUNDER:
+			player.Blunders++
+		}
+	}
+
+	for i := range analysis.Players {
+		if player := &analysis.Players[i]; player.Moves > 0 {
+			player.Accuracy = float64(player.Optimal) / float64(player.Moves)
+		}
+	}
+	return analysis
+}
+
+// RecordAnalysis adds a game's move grades to both players' running totals
+// Like RecordResult, call it once per finished game
+func (ge *GameEngine) RecordAnalysis(game *models.Game, analysis *models.GameAnalysis) {
+	if analysis == nil {
+		return
+	}
+	for i, player := range []*models.Player{game.PlayerX(), game.PlayerO()} {
+		if player == nil {
+			continue
+		}
+		totals := analysis.Players[i]
+		player.AnalyzedMoves += totals.Moves
+		player.OptimalMoves += totals.Optimal
+		player.Mistakes += totals.Mistakes
+		player.Blunders += totals.Blunders
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestGradeMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    [9]models.Symbol
+		symbol   models.Symbol
+		position int
+		want     string
+	}{
+		{"center opening", [9]models.Symbol{}, "X", 4, models.MOVE_OPTIMAL},
+		{"edge reply to a center opening", [9]models.Symbol{"", "", "", "", "X", "", "", "", ""}, "O", 1, models.MOVE_BLUNDER},
+		{"corner reply to a center opening", [9]models.Symbol{"", "", "", "", "X", "", "", "", ""}, "O", 0, models.MOVE_OPTIMAL},
+		{"letting a won position draw", [9]models.Symbol{"X", "O", "X", "", "", "O", "", "", ""}, "X", 7, models.MOVE_MISTAKE},
+		{"failing to block", [9]models.Symbol{"X", "X", "", "", "O", "", "", "", ""}, "O", 5, models.MOVE_BLUNDER},
+	}
+
+	ge := NewGameEngine()
+	for _, tt := range tests {
+		if got, _ := ge.GradeMove(tt.board, tt.symbol, tt.position, models.VARIANT_STANDARD); got != tt.want {
+			t.Errorf("%s: GradeMove = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBestMove(t *testing.T) {
+	ge := NewGameEngine()
+	if got := ge.BestMove([9]models.Symbol{"X", "X", "", "", "O", "", "", "", ""}, "O", models.VARIANT_STANDARD); got != 2 {
+		t.Errorf("BestMove = %d, want the block at 2", got)
+	}
+	if got := ge.BestMove([9]models.Symbol{"X", "O", "X", "X", "O", "O", "O", "X", "X"}, "X", models.VARIANT_STANDARD); got != -1 {
+		t.Errorf("BestMove on a full board = %d", got)
+	}
+}
+
+func TestAnalyzeGame(t *testing.T) {
+	x, o := models.NewPlayer("x"), models.NewPlayer("o")
+	game := models.NewGame()
+	game.Players = []*models.Player{x, o}
+
+	// O answers the center with an edge and X forks its way to the left column
+	for i, position := range []int{4, 1, 0, 8, 6, 2, 3} {
+		move := models.MoveRecord{PlayerID: x.ID, Symbol: "X", Position: position}
+		if i%2 == 1 {
+			move = models.MoveRecord{PlayerID: o.ID, Symbol: "O", Position: position}
+		}
+		game.Moves = append(game.Moves, move)
+	}
+
+	ge := NewGameEngine()
+	analysis := ge.AnalyzeGame(game)
+	if len(analysis.Moves) != 7 || analysis.Moves[1].Quality != models.MOVE_BLUNDER {
+		t.Fatalf("moves = %+v", analysis.Moves)
+	}
+	xTotals, oTotals := analysis.Players[0], analysis.Players[1]
+	if xTotals.PlayerID != x.ID || xTotals.Moves != 4 || xTotals.Optimal != 4 || xTotals.Accuracy != 1 {
+		t.Errorf("X analysis = %+v", xTotals)
+	}
+	if oTotals.Moves != 3 || oTotals.Blunders != 1 || oTotals.Optimal != 2 {
+		t.Errorf("O analysis = %+v", oTotals)
+	}
+
+	ge.RecordAnalysis(game, analysis)
+	if x.AnalyzedMoves != 4 || x.OptimalMoves != 4 || o.Blunders != 1 {
+		t.Errorf("player totals: X %+v, O %+v", x, o)
+	}
+}
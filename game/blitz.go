@@ -0,0 +1,48 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// StartClock gives every player total time for the whole game and starts the clock of the player to move
+func (ge *GameEngine) StartClock(game *models.Game, total time.Duration) {
+	game.TimeLeft = make(map[models.Symbol]time.Duration, len(game.Players))
+	for _, symbol := range game.SeatSymbols() {
+		game.TimeLeft[symbol] = total
+	}
+	game.TurnStartedAt = ge.clock.Now()
+}
+
+// StopClock banks the player to move's think time so far, charging it to their clock in blitz games;
+// call it before a game stops playing
+func (ge *GameEngine) StopClock(game *models.Game) {
+	now := ge.clock.Now()
+	if !game.TurnStartedAt.IsZero() {
+		game.TurnThought += now.Sub(game.TurnStartedAt)
+	}
+	if game.TimeLeft != nil {
+		game.TimeLeft[game.CurrentTurn] = game.RemainingTime(game.CurrentTurn, now)
+	}
+	game.TurnStartedAt = now
+}
+
+// ResumeClock starts timing the player to move's turn once a game plays, or plays again after a pause
+func (ge *GameEngine) ResumeClock(game *models.Game) {
+	game.TurnStartedAt = ge.clock.Now()
+}
+
+// TimeOut ends a blitz game whose player to move has run out of time, in their opponent's favor
+func (ge *GameEngine) TimeOut(game *models.Game) error {
+	if game.TimeLeft == nil || game.Status != models.STATUS_PLAYING {
+		return errors.New("no clock is running")
+	}
+	if game.RemainingTime(game.CurrentTurn, ge.clock.Now()) > 0 {
+		return errors.New("time has not run out")
+	}
+
+	ge.StopClock(game)
+	return ge.Forfeit(game, game.SidePlayers(game.CurrentTurn)[0].ID)
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"math/rand"
+	"slices"
+
+	"tictactoe-server/models"
+)
+
+// ErrUnknownPersonality is returned for bots whose personality has no registered strategy
+var ErrUnknownPersonality = models.NewError(models.ERR_NOT_ALLOWED, "unknown bot personality")
+
+// blunderChance is how often the blunderer moves at random instead of taking a win or block
+const blunderChance = 0.5
+
+// Cells the opening personalities prefer
+var (
+	cornerCells = []int{0, 2, 6, 8}
+	centerCells = []int{4}
+)
+
+// BotStrategy picks a bot's moves; each bot personality is one
+type BotStrategy interface {
+	// ChooseMove returns the position the bot plays for symbol, or -1 if the board has no empty cells
+	ChooseMove(board [9]models.Symbol, symbol models.Symbol, variant string) int
+}
+
+// RegisterBotStrategy makes bots of a personality play by the given strategy, replacing any registered before
+func (ge *GameEngine) RegisterBotStrategy(personality string, strategy BotStrategy) {
+	ge.botStrategies[personality] = strategy
+}
+
+// BotStrategyFor returns the strategy of a bot personality; bots without one play perfectly
+func (ge *GameEngine) BotStrategyFor(personality string) (BotStrategy, error) {
+	if personality == "" {
+		personality = models.BOT_PERFECT
+	}
+	strategy, exists := ge.botStrategies[personality]
+	if !exists {
+		return nil, ErrUnknownPersonality
+	}
+	return strategy, nil
+}
+
+// BotMove picks a move for the given symbol and variant using minimax, choosing randomly among equally good moves
+// Returns -1 if the board has no empty cells
+func (ge *GameEngine) BotMove(board [9]models.Symbol, symbol models.Symbol, variant string) int {
+	bestScore := -2
+	bestMoves := make([]int, 0, 9)
+
+	for position, cell := range board {
+		if cell != models.SYMBOL_NONE {
+			continue
+		}
+
+		board[position] = symbol
+		score := -ge.minimax(board, otherSymbol(symbol), variant)
+		board[position] = models.SYMBOL_NONE
+
+		if score > bestScore {
+			bestScore = score
+			bestMoves = bestMoves[:0]
+		}
+		if score == bestScore {
+			bestMoves = append(bestMoves, position)
+		}
+	}
+
+	if len(bestMoves) == 0 {
+		return -1
+	}
+	return bestMoves[rand.Intn(len(bestMoves))]
+}
+
+// minimax scores the board from the perspective of the player to move: 1 win, 0 draw, -1 loss
+func (ge *GameEngine) minimax(board [9]models.Symbol, toMove models.Symbol, variant string) int {
+	if winner := ge.CheckWinner(board); winner != models.SYMBOL_NONE {
+		// The previous mover completed a line
+		return lineValue(variant)
+	}
+	if ge.IsBoardFull(board) {
+		return 0
+	}
+
+	best := -2
+	for position, cell := range board {
+		if cell != models.SYMBOL_NONE {
+			continue
+		}
+		board[position] = toMove
+		score := -ge.minimax(board, otherSymbol(toMove), variant)
+		board[position] = models.SYMBOL_NONE
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// perfectBot always plays one of the best moves
+type perfectBot struct {
+	ge *GameEngine
+}
+
+func (b perfectBot) ChooseMove(board [9]models.Symbol, symbol models.Symbol, variant string) int {
+	return b.ge.BotMove(board, symbol, variant)
+}
+
+// openingBot makes its first move on one of its preferred cells when any is free, then plays perfectly
+type openingBot struct {
+	ge        *GameEngine
+	preferred []int
+}
+
+func (b openingBot) ChooseMove(board [9]models.Symbol, symbol models.Symbol, variant string) int {
+	if !slices.Contains(board[:], symbol) {
+		free := make([]int, 0, len(b.preferred))
+		for _, position := range b.preferred {
+			if board[position] == models.SYMBOL_NONE {
+				free = append(free, position)
+			}
+		}
+		if len(free) > 0 {
+			return free[rand.Intn(len(free))]
+		}
+	}
+	return b.ge.BotMove(board, symbol, variant)
+}
+
+// blundererBot plays the best move only some of the time and any empty cell otherwise
+type blundererBot struct {
+	ge *GameEngine
+}
+
+func (b blundererBot) ChooseMove(board [9]models.Symbol, symbol models.Symbol, variant string) int {
+	if rand.Float64() >= blunderChance {
+		return b.ge.BotMove(board, symbol, variant)
+	}
+
+	empty := make([]int, 0, 9)
+	for position, cell := range board {
+		if cell == models.SYMBOL_NONE {
+			empty = append(empty, position)
+		}
+	}
+	if len(empty) == 0 {
+		return -1
+	}
+	return empty[rand.Intn(len(empty))]
+}
+
+// otherSymbol returns the opposing symbol
+func otherSymbol(symbol models.Symbol) models.Symbol {
+	if symbol == models.SYMBOL_X {
+		return models.SYMBOL_O
+	}
+	return models.SYMBOL_X
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestBotMove(t *testing.T) {
+	tests := []struct {
+		name   string
+		board  [9]models.Symbol
+		symbol models.Symbol
+		want   int
+	}{
+		{"takes the win", [9]models.Symbol{"O", "O", "", "X", "X", "", "", "", ""}, "O", 2},
+		{"blocks a loss", [9]models.Symbol{"X", "X", "", "", "O", "", "", "", ""}, "O", 2},
+		{"prefers winning to blocking", [9]models.Symbol{"X", "X", "", "O", "O", "", "", "", ""}, "X", 2},
+		{"full board", [9]models.Symbol{"X", "O", "X", "X", "O", "O", "O", "X", "X"}, "X", -1},
+	}
+
+	ge := NewGameEngine()
+	for _, tt := range tests {
+		if got := ge.BotMove(tt.board, tt.symbol, models.VARIANT_STANDARD); got != tt.want {
+			t.Errorf("%s: BotMove = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBotNeverLosesToItself(t *testing.T) {
+	ge := NewGameEngine()
+	for i := 0; i < 10; i++ {
+		var board [9]models.Symbol
+		symbol := models.SYMBOL_X
+		for ge.CheckWinner(board) == "" && !ge.IsBoardFull(board) {
+			board[ge.BotMove(board, symbol, models.VARIANT_STANDARD)] = symbol
+			symbol = otherSymbol(symbol)
+		}
+		if winner := ge.CheckWinner(board); winner != "" {
+			t.Fatalf("perfect play should draw, %s won: %v", winner, board)
+		}
+	}
+}
+
+func TestBotMoveMisere(t *testing.T) {
+	ge := NewGameEngine()
+
+	// Taking the top row, or blocking O with a fork, wins normally; under misère rules only the centre avoids a loss
+	board := [9]models.Symbol{"X", "X", "", "", "", "", "", "O", "O"}
+	if got := ge.BotMove(board, "X", models.VARIANT_STANDARD); got != 2 && got != 6 {
+		t.Errorf("standard BotMove = %d, want 2 or 6", got)
+	}
+	if got := ge.BotMove(board, "X", models.VARIANT_MISERE); got != 4 {
+		t.Errorf("misère BotMove = %d, want 4", got)
+	}
+
+	if value := ge.positionValue([9]models.Symbol{}, "X", models.VARIANT_MISERE); value != 0 {
+		t.Errorf("misère opening value = %d, want a draw", value)
+	}
+}
+
+func TestBotPersonalities(t *testing.T) {
+	ge := NewGameEngine()
+
+	corner, _ := ge.BotStrategyFor(models.BOT_CORNER_OPENER)
+	centre, _ := ge.BotStrategyFor(models.BOT_CENTER_FIRST)
+	for i := 0; i < 20; i++ {
+		if got := corner.ChooseMove([9]models.Symbol{}, "X", models.VARIANT_STANDARD); got != 0 && got != 2 && got != 6 && got != 8 {
+			t.Fatalf("corner opener opened at %d", got)
+		}
+		if got := centre.ChooseMove([9]models.Symbol{"X"}, "O", models.VARIANT_STANDARD); got != 4 {
+			t.Fatalf("centre-first bot replied at %d", got)
+		}
+	}
+
+	// After the opening both play perfectly: O must block
+	board := [9]models.Symbol{"X", "X", "", "", "O", "", "", "", ""}
+	if got := corner.ChooseMove(board, "O", models.VARIANT_STANDARD); got != 2 {
+		t.Errorf("corner opener after its opening = %d, want the block at 2", got)
+	}
+
+	// The blunderer sometimes takes the win and sometimes doesn't
+	blunderer, _ := ge.BotStrategyFor(models.BOT_BLUNDERER)
+	board = [9]models.Symbol{"O", "O", "", "X", "X", "", "", "", ""}
+	wins := 0
+	for i := 0; i < 200; i++ {
+		if blunderer.ChooseMove(board, "O", models.VARIANT_STANDARD) == 2 {
+			wins++
+		}
+	}
+	if wins == 0 || wins == 200 {
+		t.Errorf("blunderer took the win %d times in 200", wins)
+	}
+
+	if perfect, err := ge.BotStrategyFor(""); err != nil || perfect.ChooseMove(board, "O", models.VARIANT_STANDARD) != 2 {
+		t.Errorf("bots without a personality should play perfectly")
+	}
+	if _, err := ge.BotStrategyFor("grandmaster"); err != ErrUnknownPersonality {
+		t.Errorf("unknown personality error = %v", err)
+	}
+}
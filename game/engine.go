@@ -2,43 +2,138 @@ package game
 
 import (
 	"errors"
+	"math"
+	"math/rand"
+	"slices"
+
+	"tictactoe-server/clock"
 	"tictactoe-server/models"
+	"tictactoe-server/rating"
+)
+
+// Reasons a move or other game action is refused
+var (
+	ErrNotPlaying       = models.NewError(models.ERR_NOT_PLAYING, "game is not in playing state")
+	ErrNotInProgress    = models.NewError(models.ERR_NOT_PLAYING, "game is not in progress")
+	ErrInvalidPosition  = models.NewError(models.ERR_INVALID_POSITION, "invalid position")
+	ErrPositionOccupied = models.NewError(models.ERR_POSITION_OCCUPIED, "position already occupied")
+	ErrNotInGame        = models.NewError(models.ERR_NOT_IN_GAME, "player not in this game")
+	ErrNotYourTurn      = models.NewError(models.ERR_NOT_YOUR_TURN, "not your turn")
+	ErrOutOfTime        = models.NewError(models.ERR_OUT_OF_TIME, "out of time")
 )
 
+// DEFAULT_K_FACTOR is the Elo K-factor of established players once their placement games are done
+const DEFAULT_K_FACTOR = 32
+
 // GameEngine handles the game logic
-type GameEngine struct{}
+type GameEngine struct {
+	clock            clock.Clock // Timestamps moves
+	placementGames   int         // Rated games per season that use placementKFactor
+	placementKFactor int
+	glicko           bool // Rate with Glicko-2 instead of Elo
+	transitionHooks  []TransitionHook
+	ruleSets         map[string]RuleSet     // Variant -> its rules, see rules.go
+	botStrategies    map[string]BotStrategy // Bot personality -> how it plays, see bot.go
 
-// NewGameEngine creates a new game engine
+	provisionalGames   int // Rated games, over all pools, before a player's rating is established
+	provisionalKFactor int
+}
+
+// NewGameEngine creates a new game engine using the wall clock
 func NewGameEngine() *GameEngine {
-	return &GameEngine{}
+	return NewGameEngineWithClock(clock.New())
+}
+
+// NewGameEngineWithClock creates a game engine that reads time from clk
+func NewGameEngineWithClock(clk clock.Clock) *GameEngine {
+	ge := &GameEngine{
+		clock: clk,
+		ruleSets: map[string]RuleSet{
+			models.VARIANT_STANDARD: classicRules{},
+			models.VARIANT_MISERE:   misereRules{},
+		},
+	}
+	ge.botStrategies = map[string]BotStrategy{
+		models.BOT_PERFECT:       perfectBot{ge},
+		models.BOT_CORNER_OPENER: openingBot{ge, cornerCells},
+		models.BOT_CENTER_FIRST:  openingBot{ge, centerCells},
+		models.BOT_BLUNDERER:     blundererBot{ge},
+	}
+	return ge
+}
+
+// SetPlacement makes a player's first games of each season move their rating by kFactor instead of DEFAULT_K_FACTOR
+func (ge *GameEngine) SetPlacement(games, kFactor int) {
+	ge.placementGames = games
+	ge.placementKFactor = kFactor
+}
+
+// SetProvisional makes a player's first games ever move their rating by kFactor instead of DEFAULT_K_FACTOR
+// Where a player is both provisional and in placement the larger of the two K-factors applies
+func (ge *GameEngine) SetProvisional(games, kFactor int) {
+	ge.provisionalGames = games
+	ge.provisionalKFactor = kFactor
+}
+
+// SetGlicko switches rated games between Glicko-2 and Elo
+// Placement K-factors only apply to Elo; Glicko-2 moves uncertain ratings further by itself
+func (ge *GameEngine) SetGlicko(enabled bool) {
+	ge.glicko = enabled
+}
+
+// InPlacement reports whether a player is still playing placement games this season
+func (ge *GameEngine) InPlacement(player *models.Player) bool {
+	return player.SeasonGames < ge.placementGames
+}
+
+// IsProvisional reports whether a player has yet to play enough rated games for their rating to be established
+func (ge *GameEngine) IsProvisional(player *models.Player) bool {
+	return player.RatedGames() < ge.provisionalGames
+}
+
+// SeatPlayers seats the players in random order, X first, so nobody always moves first
+func (ge *GameEngine) SeatPlayers(game *models.Game, players ...*models.Player) {
+	seated := append([]*models.Player(nil), players...)
+	rand.Shuffle(len(seated), func(i, j int) { seated[i], seated[j] = seated[j], seated[i] })
+	ge.SeatPlayersInOrder(game, seated...)
+}
+
+// SeatPlayersInOrder seats players in the order given, the first playing X
+func (ge *GameEngine) SeatPlayersInOrder(game *models.Game, players ...*models.Player) {
+	game.Players = players
+	for i, player := range players {
+		player.Symbol = models.Symbols[i]
+	}
+	game.FirstMoverID = players[0].ID // X always moves first
 }
 
 // IsValidMove checks if a move is valid
 func (ge *GameEngine) IsValidMove(game *models.Game, playerID string, position int) error {
 	if game.Status != models.STATUS_PLAYING {
-		return errors.New("game is not in playing state")
+		return ErrNotPlaying
 	}
 
-	if position < 0 || position > 8 {
-		return errors.New("invalid position")
+	rules, err := ge.rulesFor(game)
+	if err != nil {
+		return err
 	}
-
-	if game.Board[position] != "" {
-		return errors.New("position already occupied")
+	if err := rules.ValidateMove(game, position); err != nil {
+		return err
 	}
 
 	// Check if it's the player's turn
-	var playerSymbol string
-	if game.PlayerX != nil && game.PlayerX.ID == playerID {
-		playerSymbol = "X"
-	} else if game.PlayerO != nil && game.PlayerO.ID == playerID {
-		playerSymbol = "O"
-	} else {
-		return errors.New("player not in this game")
+	var playerSymbol models.Symbol
+	for i, seated := range game.Players {
+		if seated.ID == playerID {
+			playerSymbol = models.Symbols[i]
+		}
+	}
+	if playerSymbol == models.SYMBOL_NONE {
+		return ErrNotInGame
 	}
 
 	if game.CurrentTurn != playerSymbol {
-		return errors.New("not your turn")
+		return ErrNotYourTurn
 	}
 
 	return nil
@@ -49,34 +144,215 @@ func (ge *GameEngine) MakeMove(game *models.Game, playerID string, position int)
 	if err := ge.IsValidMove(game, playerID, position); err != nil {
 		return err
 	}
+	rules, _ := ge.rulesFor(game) // IsValidMove already found them
+	now := ge.clock.Now()
+	thinkTime := game.ThinkTime(now)
+
+	// In blitz games the move stops the mover's clock; one made after their time ran out doesn't count
+	if game.TimeLeft != nil {
+		ge.StopClock(game)
+		if game.TimeLeft[game.CurrentTurn] <= 0 {
+			return ErrOutOfTime
+		}
+	}
 
 	// Make the move
-	game.Board[position] = game.CurrentTurn
+	rules.ApplyMove(game, position)
+	game.Moves = append(game.Moves, models.MoveRecord{
+		PlayerID:  playerID,
+		Symbol:    game.CurrentTurn,
+		Position:  position,
+		Timestamp: now,
+		ThinkMs:   thinkTime.Milliseconds(),
+	})
+	game.TurnStartedAt, game.TurnThought = now, 0
+	game.TakebackRequestedBy = ""
+	// Under the pie rule O's first reply may be a swap instead; any move by O settles it
+	game.SwapPending = game.PieRule && len(game.Moves) == 1
 
-	// Check for winner
-	winner := ge.CheckWinner(game.Board)
-	if winner != "" {
-		game.Status = models.STATUS_FINISHED
+	if winner, over := rules.CheckTerminal(game); over {
 		game.Winner = winner
-		ge.updatePlayerStats(game)
-	} else if ge.IsBoardFull(game.Board) {
-		game.Status = models.STATUS_FINISHED
-		game.Winner = "draw"
-		ge.updatePlayerStats(game)
-	} else {
-		// Switch turns
-		if game.CurrentTurn == "X" {
-			game.CurrentTurn = "O"
-		} else {
-			game.CurrentTurn = "X"
+		return ge.Transition(game, models.STATUS_FINISHED)
+	}
+	game.CurrentTurn = nextTurn(game)
+
+	return nil
+}
+
+// nextTurn returns the symbol that moves after the current one, skipping anyone eliminated
+func nextTurn(game *models.Game) models.Symbol {
+	symbols := game.SeatSymbols()
+	current := slices.Index(symbols, game.CurrentTurn)
+	for step := 1; step <= len(symbols); step++ {
+		next := symbols[(current+step)%len(symbols)]
+		if !game.IsEliminated(next) {
+			return next
+		}
+	}
+	return game.CurrentTurn
+}
+
+// Forfeit makes the given player lose
+// With more than one opponent left, as in trio games, the player is eliminated and the others play on;
+// a game paused for them resumes. Otherwise the game ends with the last player standing winning
+func (ge *GameEngine) Forfeit(game *models.Game, loserID string) error {
+	if game.Status != models.STATUS_PLAYING && game.Status != models.STATUS_PAUSED {
+		return ErrNotInProgress
+	}
+
+	// In team games any member can lose for their side
+	side := game.SideOf(loserID)
+	if side == models.SYMBOL_NONE || game.IsEliminated(side) {
+		return ErrNotInGame
+	}
+
+	remaining := slices.DeleteFunc(game.ActiveSymbols(), func(symbol models.Symbol) bool { return symbol == side })
+	if len(remaining) > 1 {
+		game.Eliminated = append(game.Eliminated, side)
+		if game.CurrentTurn == side {
+			game.CurrentTurn = nextTurn(game)
+		}
+		if game.DisconnectedPlayerID == loserID {
+			game.DisconnectedPlayerID = ""
+			return ge.Transition(game, models.STATUS_PLAYING)
 		}
+		return nil
+	}
+	game.Winner = models.WinFor(remaining[0])
+	game.DisconnectedPlayerID = ""
+
+	return ge.Transition(game, models.STATUS_FINISHED)
+}
+
+// EndGame finishes an in-progress game with the given result: a win for a seated symbol or a draw
+func (ge *GameEngine) EndGame(game *models.Game, winner models.Result) error {
+	if game.Status != models.STATUS_PLAYING && game.Status != models.STATUS_PAUSED {
+		return ErrNotInProgress
 	}
 
+	if winner != models.RESULT_DRAW && !slices.Contains(game.SeatSymbols(), winner.WinningSymbol()) {
+		return errors.New("invalid winner")
+	}
+
+	game.Winner = winner
+	game.DisconnectedPlayerID = ""
+
+	return ge.Transition(game, models.STATUS_FINISHED)
+}
+
+// UndoLastMove takes back the player's most recent move, along with the opponent's reply if there was one
+func (ge *GameEngine) UndoLastMove(game *models.Game, playerID string) error {
+	if game.Rated {
+		return models.NewError(models.ERR_NOT_ALLOWED, "takebacks are not allowed in rated games")
+	}
+
+	if game.Status != models.STATUS_PLAYING {
+		return ErrNotPlaying
+	}
+
+	// Find the player's last move; only the opponent's reply may follow it
+	last := -1
+	for i := len(game.Moves) - 1; i >= 0 && i >= len(game.Moves)-2; i-- {
+		if game.Moves[i].PlayerID == playerID {
+			last = i
+			break
+		}
+	}
+	if last == -1 {
+		return models.NewError(models.ERR_NOTHING_PENDING, "no move to take back")
+	}
+
+	symbol := game.Moves[last].Symbol
+	for _, move := range game.Moves[last:] {
+		game.Board[move.Position] = models.SYMBOL_NONE
+	}
+	game.Moves = game.Moves[:last]
+	game.CurrentTurn = symbol
+	game.TurnStartedAt, game.TurnThought = ge.clock.Now(), 0
+	game.TakebackRequestedBy = ""
+	game.SwapPending = false // The swap was only on offer for the original first move
+
 	return nil
 }
 
-// CheckWinner checks if there's a winner on the board
-func (ge *GameEngine) CheckWinner(board [9]string) string {
+// SwapSides applies the pie rule: O takes over X's first move and the other player continues as O
+func (ge *GameEngine) SwapSides(game *models.Game, playerID string) error {
+	if err := ge.checkSwapDecision(game, playerID); err != nil {
+		return err
+	}
+
+	game.Players[0], game.Players[1] = game.Players[1], game.Players[0]
+	game.Players[0].Symbol = models.SYMBOL_X
+	game.Players[1].Symbol = models.SYMBOL_O
+	game.FirstMoverID = game.Players[0].ID
+	game.Moves[0].PlayerID = game.Players[0].ID
+	game.SwapPending = false
+	game.TakebackRequestedBy = ""
+	// The turn stays with O, now played by whoever made the first move
+	return nil
+}
+
+// DeclineSwap keeps the sides as they are; O then replies to X's first move as usual
+func (ge *GameEngine) DeclineSwap(game *models.Game, playerID string) error {
+	if err := ge.checkSwapDecision(game, playerID); err != nil {
+		return err
+	}
+	game.SwapPending = false
+	return nil
+}
+
+// checkSwapDecision checks that the player may decide on a pending swap
+func (ge *GameEngine) checkSwapDecision(game *models.Game, playerID string) error {
+	if game.Status != models.STATUS_PLAYING {
+		return ErrNotPlaying
+	}
+	if !game.SwapPending {
+		return models.NewError(models.ERR_NOTHING_PENDING, "no swap is on offer")
+	}
+	if playerO := game.PlayerO(); playerO == nil || playerO.ID != playerID {
+		return models.NewError(models.ERR_NOT_ALLOWED, "only O may swap sides")
+	}
+	return nil
+}
+
+// FindLine returns the symbol with winLength marks in a row, column or diagonal on a size x size board, or SYMBOL_NONE
+// That symbol wins under standard rules; each variant's rule set interprets it
+func (ge *GameEngine) FindLine(board []models.Symbol, size, winLength int) models.Symbol {
+	if cells := findLine(board, size, winLength); cells != nil {
+		return board[cells[0]]
+	}
+	return models.SYMBOL_NONE
+}
+
+// findLine returns the cells of the first line of winLength matching marks on a size x size board, or nil
+func findLine(board []models.Symbol, size, winLength int) []int {
+	directions := [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} // Right, down and both diagonals
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			symbol := board[row*size+col]
+			if symbol == models.SYMBOL_NONE {
+				continue
+			}
+			for _, dir := range directions {
+				cells := []int{row*size + col}
+				for r, c := row+dir[0], col+dir[1]; len(cells) < winLength && r >= 0 && r < size && c >= 0 && c < size; r, c = r+dir[0], c+dir[1] {
+					if board[r*size+c] != symbol {
+						break
+					}
+					cells = append(cells, r*size+c)
+				}
+				if len(cells) == winLength {
+					return cells
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckWinner returns the symbol with three in a row on a 3x3 board, or SYMBOL_NONE
+// The solver uses this fixed-size form; see FindLine for other boards
+func (ge *GameEngine) CheckWinner(board [9]models.Symbol) models.Symbol {
 	// Winning combinations
 	winningCombos := [][]int{
 		{0, 1, 2}, {3, 4, 5}, {6, 7, 8}, // Rows
@@ -85,94 +361,232 @@ func (ge *GameEngine) CheckWinner(board [9]string) string {
 	}
 
 	for _, combo := range winningCombos {
-		if board[combo[0]] != "" &&
+		if board[combo[0]] != models.SYMBOL_NONE &&
 			board[combo[0]] == board[combo[1]] &&
 			board[combo[1]] == board[combo[2]] {
 			return board[combo[0]]
 		}
 	}
 
-	return ""
+	return models.SYMBOL_NONE
 }
 
 // IsBoardFull checks if the board is full
-func (ge *GameEngine) IsBoardFull(board [9]string) bool {
+func (ge *GameEngine) IsBoardFull(board [9]models.Symbol) bool {
 	for _, cell := range board {
-		if cell == "" {
+		if cell == models.SYMBOL_NONE {
 			return false
 		}
 	}
 	return true
 }
 
-// updatePlayerStats updates player statistics after a game
-func (ge *GameEngine) updatePlayerStats(game *models.Game) {
-	if game.PlayerX == nil || game.PlayerO == nil {
+// RecordResult updates both players' statistics and ratings for a finished game
+// MakeMove, Forfeit and EndGame only settle the game itself, since players are shared between games
+// and are guarded by whoever owns them; call this once per finished game
+func (ge *GameEngine) RecordResult(game *models.Game) {
+	if game.Status != models.STATUS_FINISHED || len(game.Players) < 2 {
 		return
 	}
 
+	// Casual and trio games are tallied separately and never move ratings
+	if !game.Rated || len(game.Players) != 2 {
+		ge.updateCasualStats(game)
+		return
+	}
+
+	// Each variant and mode is rated separately
+	pool := game.RatingPool()
+	playerX, playerO := game.PlayerX(), game.PlayerO()
+	ratingX, ratingO := playerX.Rating(pool), playerO.Rating(pool)
+	// Ratings move before the game is tallied, so K-factors and Glicko-2 conversions see the games played before it
 	switch game.Winner {
-	case "X":
-		game.PlayerX.Wins++
-		game.PlayerO.Losses++
-		ge.updateRating(game.PlayerX, game.PlayerO, 1.0) // X wins
-	case "O":
-		game.PlayerO.Wins++
-		game.PlayerX.Losses++
-		ge.updateRating(game.PlayerX, game.PlayerO, 0.0) // O wins
-	case "draw":
-		game.PlayerX.Draws++
-		game.PlayerO.Draws++
-		ge.updateRating(game.PlayerX, game.PlayerO, 0.5) // Draw
+	case models.WinFor(models.SYMBOL_X):
+		ge.rateGame(pool, playerX, playerO, 1.0) // X wins
+		playerX.Wins++
+		playerO.Losses++
+		ge.updateStreaks(playerX, playerO)
+	case models.WinFor(models.SYMBOL_O):
+		ge.rateGame(pool, playerX, playerO, 0.0) // O wins
+		playerO.Wins++
+		playerX.Losses++
+		ge.updateStreaks(playerO, playerX)
+	case models.RESULT_DRAW:
+		ge.rateGame(pool, playerX, playerO, 0.5) // Draw
+		playerX.Draws++
+		playerO.Draws++
+		playerX.CurrentStreak = 0
+		playerO.CurrentStreak = 0
+	}
+
+	// Players compensated for a game the server dropped keep what they gain but lose nothing
+	if game.IsRatingProtected(playerX.ID) {
+		floorRating(pool, playerX, ratingX)
+	}
+	if game.IsRatingProtected(playerO.ID) {
+		floorRating(pool, playerO, ratingO)
 	}
+
+	playerX.SeasonGames++
+	playerO.SeasonGames++
 }
 
-// updateRating updates player ratings using a simplified ELO system
-func (ge *GameEngine) updateRating(playerX, playerO *models.Player, score float64) {
-	const K = 32 // ELO K-factor
+// updateCasualStats updates the casual win/loss tallies after an unrated game
+// In team games every member of a side shares its result; in trio games everyone but the winner loses
+func (ge *GameEngine) updateCasualStats(game *models.Game) {
+	switch game.Winner {
+	case models.RESULT_NONE:
+	case models.RESULT_DRAW:
+		for _, player := range game.AllPlayers() {
+			player.CasualDraws++
+		}
+	default:
+		for _, player := range game.AllPlayers() {
+			if game.SideOf(player.ID) == game.Winner.WinningSymbol() {
+				player.CasualWins++
+			} else {
+				player.CasualLosses++
+			}
+		}
+	}
+}
 
-	expectedX := 1.0 / (1.0 + float64(10.0^((playerO.Rating-playerX.Rating)/400.0)))
+// updateStreaks extends the winner's win streak and resets the loser's
+func (ge *GameEngine) updateStreaks(winner, loser *models.Player) {
+	winner.CurrentStreak++
+	if winner.CurrentStreak > winner.LongestStreak {
+		winner.LongestStreak = winner.CurrentStreak
+	}
+	loser.CurrentStreak = 0
+}
 
-	ratingChangeX := int(K * (score - expectedX))
-	ratingChangeO := int(K * ((1.0 - score) - (1.0 - expectedX)))
+// kFactor returns how far a player's rating may move in one game
+func (ge *GameEngine) kFactor(player *models.Player) float64 {
+	kFactor := DEFAULT_K_FACTOR
+	if ge.InPlacement(player) {
+		kFactor = ge.placementKFactor
+	}
+	if ge.IsProvisional(player) {
+		kFactor = max(kFactor, ge.provisionalKFactor)
+	}
+	return float64(kFactor)
+}
 
-	playerX.Rating += ratingChangeX
-	playerO.Rating += ratingChangeO
+// rateGame moves both players' ratings in a rating pool by X's score, with the configured rating system
+func (ge *GameEngine) rateGame(pool string, playerX, playerO *models.Player, score float64) {
+	if ge.glicko {
+		ge.updateGlicko(pool, playerX, playerO, score)
+	} else {
+		ge.updateRating(pool, playerX, playerO, score)
+	}
+}
 
-	// Ensure ratings don't go below 0
-	if playerX.Rating < 0 {
-		playerX.Rating = 0
+// floorRating raises a player's rating in a pool back to floor if the game lowered it
+func floorRating(pool string, player *models.Player, floor int) {
+	if player.Rating(pool) >= floor {
+		return
+	}
+	if glicko, tracked := player.Glicko[pool]; tracked {
+		glicko.Rating = float64(floor)
+		player.SetGlicko(pool, glicko)
+		return
 	}
-	if playerO.Rating < 0 {
-		playerO.Rating = 0
+	player.SetRating(pool, floor)
+}
+
+// updateGlicko updates player ratings in a rating pool using Glicko-2, each game being a rating period of its own
+func (ge *GameEngine) updateGlicko(pool string, playerX, playerO *models.Player, score float64) {
+	glickoX, glickoO := glickoFor(pool, playerX), glickoFor(pool, playerO)
+	playerX.SetGlicko(pool, glickoX.Update(rating.Result{Opponent: glickoO, Score: score}))
+	playerO.SetGlicko(pool, glickoO.Update(rating.Result{Opponent: glickoX, Score: 1 - score}))
+}
+
+// glickoFor returns a player's Glicko-2 parameters in a pool
+// A player first rated with Glicko-2 there has their Elo rating converted, as certain as their rated games make it
+func glickoFor(pool string, player *models.Player) rating.Glicko {
+	if glicko, tracked := player.Glicko[pool]; tracked {
+		return glicko
 	}
+	return rating.FromElo(player.Rating(pool), player.RatedGames())
+}
+
+// updateRating updates player ratings in a rating pool using a simplified ELO system
+func (ge *GameEngine) updateRating(pool string, playerX, playerO *models.Player, score float64) {
+	ratingX, ratingO := playerX.Rating(pool), playerO.Rating(pool)
+	expectedX := 1.0 / (1.0 + math.Pow(10, float64(ratingO-ratingX)/400.0))
+
+	ratingChangeX := int(ge.kFactor(playerX) * (score - expectedX))
+	ratingChangeO := int(ge.kFactor(playerO) * ((1.0 - score) - (1.0 - expectedX)))
+
+	// Ensure ratings don't go below 0
+	playerX.SetRating(pool, max(ratingX+ratingChangeX, 0))
+	playerO.SetRating(pool, max(ratingO+ratingChangeO, 0))
 }
 
 // GetGameStateForPlayer returns the game state from a player's perspective
-func (ge *GameEngine) GetGameStateForPlayer(game *models.Game, playerID string) map[string]interface{} {
-	var mySymbol string
-	var opponentName string
-
-	if game.PlayerX != nil && game.PlayerX.ID == playerID {
-		mySymbol = "X"
-		if game.PlayerO != nil {
-			opponentName = game.PlayerO.Name
-		}
-	} else if game.PlayerO != nil && game.PlayerO.ID == playerID {
-		mySymbol = "O"
-		if game.PlayerX != nil {
-			opponentName = game.PlayerX.Name
-		}
+func (ge *GameEngine) GetGameStateForPlayer(game *models.Game, playerID string) *models.GameStateView {
+	mySymbol := game.SideOf(playerID)
+	var opponent *models.Player
+	switch mySymbol {
+	case models.SYMBOL_X:
+		opponent = game.PlayerO()
+	case models.SYMBOL_O:
+		opponent = game.PlayerX()
+	}
+	if len(game.Players) > 2 {
+		opponent = nil // Trio games list every seat instead
 	}
 
-	return map[string]interface{}{
-		"gameId":       game.ID,
-		"board":        game.Board,
-		"currentTurn":  game.CurrentTurn,
-		"status":       game.Status,
-		"winner":       game.Winner,
-		"mySymbol":     mySymbol,
-		"opponentName": opponentName,
-		"isMyTurn":     game.CurrentTurn == mySymbol && game.Status == models.STATUS_PLAYING,
+	state := &models.GameStateView{
+		GameID:              game.ID,
+		Board:               append([]models.Symbol(nil), game.Board...), // A copy, since the state may outlive this turn
+		CurrentTurn:         game.CurrentTurn,
+		Status:              game.Status,
+		Winner:              game.Winner,
+		MySymbol:            mySymbol,
+		IsMyTurn:            game.CurrentTurn == mySymbol && game.Status == models.STATUS_PLAYING,
+		Rated:               game.Rated,
+		MoveCount:           len(game.Moves),
+		TakebackRequestedBy: game.TakebackRequestedBy,
+		Variant:             game.Variant,
+		Size:                game.Size,
+		WinLength:           game.WinLength,
+		Teams:               game.Teams,
+		MoveDeadline:        game.MoveDeadline,
+		Settings:            game.Settings,
+		RatingProtected:     game.IsRatingProtected(playerID),
+	}
+	if opponent != nil {
+		state.OpponentName = opponent.Name
+		state.OpponentIsBot = opponent.IsBot
+	}
+	if game.Status == models.STATUS_FINISHED {
+		state.WinningLine = findLine(game.Board, game.Size, game.WinLength)
+	}
+	if len(game.Players) > 2 {
+		state.Seats = seatViews(game)
+	}
+	if game.TimeLeft != nil {
+		state.TimeLeftMs = game.TimeLeftMs(ge.clock.Now())
+	}
+	if game.PieRule {
+		state.PieRule = true
+		state.SwapPending = game.SwapPending
+		state.CanSwap = game.SwapPending && mySymbol == models.SYMBOL_O
+	}
+	return state
+}
+
+// seatViews describes who plays each symbol in a game with more than two seats
+func seatViews(game *models.Game) []models.SeatView {
+	seats := make([]models.SeatView, 0, len(game.Players))
+	for i, player := range game.Players {
+		seats = append(seats, models.SeatView{
+			Symbol:     models.Symbols[i],
+			Name:       player.Name,
+			IsBot:      player.IsBot,
+			Eliminated: game.IsEliminated(models.Symbols[i]),
+		})
 	}
+	return seats
 }
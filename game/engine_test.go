@@ -0,0 +1,710 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+	"tictactoe-server/rating"
+)
+
+// newTestGame returns a game in progress with X and O seated in that order
+func newTestGame(rated bool) (*models.Game, *models.Player, *models.Player) {
+	x := models.NewPlayer("x")
+	o := models.NewPlayer("o")
+	x.Symbol, o.Symbol = "X", "O"
+
+	g := models.NewGame()
+	g.Players = []*models.Player{x, o}
+	g.FirstMoverID = x.ID
+	g.Status = models.STATUS_PLAYING
+	g.Rated = rated
+	return g, x, o
+}
+
+// playMoves plays positions in turn order starting with X, failing on the first rejected move
+func playMoves(t *testing.T, ge *GameEngine, g *models.Game, positions ...int) {
+	t.Helper()
+	for _, position := range positions {
+		mover := g.SidePlayers(g.CurrentTurn)[0]
+		if err := ge.MakeMove(g, mover.ID, position); err != nil {
+			t.Fatalf("move %d: %v", position, err)
+		}
+	}
+}
+
+func TestCheckWinner(t *testing.T) {
+	lines := [][3]int{
+		{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+		{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+		{0, 4, 8}, {2, 4, 6},
+	}
+
+	ge := NewGameEngine()
+	for _, symbol := range []models.Symbol{models.SYMBOL_X, models.SYMBOL_O} {
+		for _, line := range lines {
+			var board [9]models.Symbol
+			for _, position := range line {
+				board[position] = symbol
+			}
+			if got := ge.CheckWinner(board); got != symbol {
+				t.Errorf("line %v of %s: CheckWinner = %q, want %q", line, symbol, got, symbol)
+			}
+		}
+	}
+
+	tests := []struct {
+		name  string
+		board [9]models.Symbol
+		want  models.Symbol
+	}{
+		{"empty", [9]models.Symbol{}, ""},
+		{"two in a row", [9]models.Symbol{"X", "X", "", "", "", "", "", "", ""}, ""},
+		{"mixed line", [9]models.Symbol{"X", "O", "X", "", "", "", "", "", ""}, ""},
+		{"full draw", [9]models.Symbol{"X", "O", "X", "X", "O", "O", "O", "X", "X"}, ""},
+	}
+	for _, tt := range tests {
+		if got := ge.CheckWinner(tt.board); got != tt.want {
+			t.Errorf("%s: CheckWinner = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMakeMoveOutcomes(t *testing.T) {
+	tests := []struct {
+		name      string
+		moves     []int
+		status    string
+		winner    models.Result
+		nextTurn  models.Symbol
+		moveCount int
+	}{
+		{"in progress", []int{4, 0}, models.STATUS_PLAYING, "", "X", 2},
+		{"X wins top row", []int{0, 3, 1, 4, 2}, models.STATUS_FINISHED, "X", "X", 5},
+		{"O wins diagonal", []int{1, 0, 2, 4, 5, 8}, models.STATUS_FINISHED, "O", "O", 6},
+		{"draw", []int{0, 1, 2, 4, 3, 5, 7, 6, 8}, models.STATUS_FINISHED, "draw", "X", 9},
+	}
+
+	ge := NewGameEngine()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _, _ := newTestGame(false)
+			playMoves(t, ge, g, tt.moves...)
+
+			if g.Status != tt.status || g.Winner != tt.winner {
+				t.Errorf("status/winner = %s/%q, want %s/%q", g.Status, g.Winner, tt.status, tt.winner)
+			}
+			if g.CurrentTurn != tt.nextTurn {
+				t.Errorf("CurrentTurn = %s, want %s", g.CurrentTurn, tt.nextTurn)
+			}
+			if len(g.Moves) != tt.moveCount {
+				t.Errorf("len(Moves) = %d, want %d", len(g.Moves), tt.moveCount)
+			}
+		})
+	}
+}
+
+func TestIsValidMove(t *testing.T) {
+	ge := NewGameEngine()
+	g, x, o := newTestGame(false)
+	playMoves(t, ge, g, 4)
+
+	finished, fx, _ := newTestGame(false)
+	finished.Status = models.STATUS_FINISHED
+
+	paused, px, _ := newTestGame(false)
+	paused.Status = models.STATUS_PAUSED
+
+	tests := []struct {
+		name     string
+		game     *models.Game
+		playerID string
+		position int
+		wantErr  bool
+	}{
+		{"valid", g, o.ID, 0, false},
+		{"occupied", g, o.ID, 4, true},
+		{"below range", g, o.ID, -1, true},
+		{"above range", g, o.ID, 9, true},
+		{"not your turn", g, x.ID, 0, true},
+		{"not in game", g, "stranger", 0, true},
+		{"finished game", finished, fx.ID, 0, true},
+		{"paused game", paused, px.ID, 0, true},
+	}
+
+	for _, tt := range tests {
+		err := ge.IsValidMove(tt.game, tt.playerID, tt.position)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMakeMoveRejectsWithoutChangingState(t *testing.T) {
+	ge := NewGameEngine()
+	g, x, _ := newTestGame(false)
+	playMoves(t, ge, g, 0)
+
+	before := slices.Clone(g.Board)
+	if err := ge.MakeMove(g, x.ID, 1); err == nil {
+		t.Fatal("X moved twice in a row")
+	}
+	if !slices.Equal(g.Board, before) || g.CurrentTurn != "O" || len(g.Moves) != 1 {
+		t.Errorf("rejected move changed the game: board=%v turn=%s moves=%d", g.Board, g.CurrentTurn, len(g.Moves))
+	}
+}
+
+func TestUpdateRating(t *testing.T) {
+	tests := []struct {
+		name         string
+		ratingX      int
+		ratingO      int
+		score        float64
+		wantX, wantO int
+	}{
+		{"equal, X wins", 1000, 1000, 1.0, 1016, 984},
+		{"equal, O wins", 1000, 1000, 0.0, 984, 1016},
+		{"equal, draw", 1000, 1000, 0.5, 1000, 1000},
+		{"favorite wins", 1200, 1000, 1.0, 1207, 993},
+		{"underdog wins", 1000, 1200, 1.0, 1024, 1176},
+		{"draw favors underdog", 1000, 1200, 0.5, 1008, 1192},
+		{"floor at zero", 10, 2000, 0.0, 10, 2000},
+	}
+
+	ge := NewGameEngine()
+	for _, tt := range tests {
+		x := &models.Player{Ratings: map[string]int{models.RATING_POOL_STANDARD: tt.ratingX}}
+		o := &models.Player{Ratings: map[string]int{models.RATING_POOL_STANDARD: tt.ratingO}}
+		ge.updateRating(models.RATING_POOL_STANDARD, x, o, tt.score)
+		if gotX, gotO := x.Rating(models.RATING_POOL_STANDARD), o.Rating(models.RATING_POOL_STANDARD); gotX != tt.wantX || gotO != tt.wantO {
+			t.Errorf("%s: ratings = %d/%d, want %d/%d", tt.name, gotX, gotO, tt.wantX, tt.wantO)
+		}
+	}
+}
+
+func TestPlacementKFactor(t *testing.T) {
+	ge := NewGameEngine()
+	ge.SetPlacement(2, 64)
+
+	// A placed player meeting a newcomer moves at the normal rate while the newcomer moves twice as far
+	placed := &models.Player{SeasonGames: 2}
+	newcomer := &models.Player{}
+	ge.updateRating(models.RATING_POOL_STANDARD, placed, newcomer, 1.0)
+	if got, want := placed.Ratings, (map[string]int{models.RATING_POOL_STANDARD: 1016}); !maps.Equal(got, want) {
+		t.Errorf("placed ratings = %v, want %v", got, want)
+	}
+	if got := newcomer.Rating(models.RATING_POOL_STANDARD); got != 968 {
+		t.Errorf("newcomer rating = %d, want 968", got)
+	}
+
+	g, x, o := newTestGame(true)
+	playMoves(t, ge, g, 0, 3, 1, 4, 2)
+	ge.RecordResult(g)
+	if x.SeasonGames != 1 || o.SeasonGames != 1 || !ge.InPlacement(x) {
+		t.Errorf("season games = %d/%d, in placement %v", x.SeasonGames, o.SeasonGames, ge.InPlacement(x))
+	}
+
+	casual, cx, _ := newTestGame(false)
+	playMoves(t, ge, casual, 0, 3, 1, 4, 2)
+	ge.RecordResult(casual)
+	if cx.SeasonGames != 0 {
+		t.Errorf("casual game counted toward the season")
+	}
+}
+
+func TestProvisionalKFactor(t *testing.T) {
+	ge := NewGameEngine()
+	ge.SetProvisional(2, 48)
+
+	// An established player meeting a provisional one moves at the normal rate while the other moves further
+	established := &models.Player{Wins: 2}
+	provisional := &models.Player{Losses: 1}
+	ge.updateRating(models.RATING_POOL_STANDARD, established, provisional, 1.0)
+	if got := established.Rating(models.RATING_POOL_STANDARD); got != 1016 {
+		t.Errorf("established rating = %d, want 1016", got)
+	}
+	if got := provisional.Rating(models.RATING_POOL_STANDARD); got != 976 {
+		t.Errorf("provisional rating = %d, want 976", got)
+	}
+
+	// A player in placement and provisional too moves by the larger K-factor
+	ge.SetPlacement(5, 40)
+	if got := ge.kFactor(provisional); got != 48 {
+		t.Errorf("provisional player in placement K-factor = %v, want 48", got)
+	}
+
+	// The game being recorded still counts as provisional; the next one doesn't
+	g, x, _ := newTestGame(true)
+	x.Wins = 1
+	playMoves(t, ge, g, 0, 3, 1, 4, 2)
+	ge.RecordResult(g)
+	if got := x.Rating(models.RATING_POOL_STANDARD); got != 1024 || ge.IsProvisional(x) {
+		t.Errorf("X after their second game: rating %d, provisional %v; want 1024 and established", got, ge.IsProvisional(x))
+	}
+}
+
+func TestRatedAndCasualStats(t *testing.T) {
+	ge := NewGameEngine()
+
+	rated, rx, ro := newTestGame(true)
+	playMoves(t, ge, rated, 0, 3, 1, 4, 2)
+	if rx.Wins != 0 || rx.Rating(models.RATING_POOL_STANDARD) != models.DEFAULT_RATING {
+		t.Fatalf("finishing move touched player stats before the result was recorded: X %+v", rx)
+	}
+	ge.RecordResult(rated)
+	if rx.Wins != 1 || ro.Losses != 1 || rx.Rating(models.RATING_POOL_STANDARD) <= models.DEFAULT_RATING ||
+		ro.Rating(models.RATING_POOL_STANDARD) >= models.DEFAULT_RATING {
+		t.Errorf("rated win not recorded: X %+v, O %+v", rx, ro)
+	}
+	if rx.CurrentStreak != 1 || rx.LongestStreak != 1 || ro.CurrentStreak != 0 {
+		t.Errorf("streaks = %d/%d/%d, want 1/1/0", rx.CurrentStreak, rx.LongestStreak, ro.CurrentStreak)
+	}
+
+	casual, cx, co := newTestGame(false)
+	playMoves(t, ge, casual, 0, 3, 1, 4, 2)
+	ge.RecordResult(casual)
+	if cx.CasualWins != 1 || co.CasualLosses != 1 || cx.Wins != 0 || cx.Rating(models.RATING_POOL_STANDARD) != models.DEFAULT_RATING {
+		t.Errorf("casual win leaked into rated stats: X %+v, O %+v", cx, co)
+	}
+}
+
+func TestForfeitAndEndGame(t *testing.T) {
+	ge := NewGameEngine()
+
+	g, x, o := newTestGame(true)
+	g.Status = models.STATUS_PAUSED
+	if err := ge.Forfeit(g, x.ID); err != nil {
+		t.Fatal(err)
+	}
+	ge.RecordResult(g)
+	if g.Status != models.STATUS_FINISHED || g.Winner != "O" || o.Wins != 1 {
+		t.Errorf("forfeit: status=%s winner=%q O wins=%d", g.Status, g.Winner, o.Wins)
+	}
+	if err := ge.Forfeit(g, x.ID); err == nil {
+		t.Error("forfeited a finished game")
+	}
+
+	g2, _, _ := newTestGame(true)
+	if err := ge.EndGame(g2, "nobody"); err == nil {
+		t.Error("EndGame accepted an invalid winner")
+	}
+	if err := ge.EndGame(g2, "draw"); err != nil || g2.Winner != "draw" {
+		t.Errorf("EndGame draw: err=%v winner=%q", err, g2.Winner)
+	}
+}
+
+func TestTransitionHooks(t *testing.T) {
+	ge := NewGameEngine()
+	var seen []string
+	ge.OnTransition(func(g *models.Game, from, to string) {
+		seen = append(seen, from+"→"+to)
+	})
+
+	g, x, _ := newTestGame(false)
+	if err := ge.Transition(g, models.STATUS_PAUSED); err != nil {
+		t.Fatal(err)
+	}
+	if err := ge.Transition(g, models.STATUS_PLAYING); err != nil {
+		t.Fatal(err)
+	}
+	playMoves(t, ge, g, 0, 3, 1, 4, 2)
+	if err := ge.Forfeit(g, x.ID); err == nil {
+		t.Error("forfeited a finished game")
+	}
+	if err := ge.Transition(g, models.STATUS_PAUSED); !errors.Is(err, models.ErrIllegalTransition) {
+		t.Errorf("pausing a finished game: err = %v", err)
+	}
+
+	want := []string{"playing→paused", "paused→playing", "playing→finished"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("transitions = %v, want %v", seen, want)
+	}
+}
+
+func TestUndoLastMove(t *testing.T) {
+	ge := NewGameEngine()
+
+	g, x, o := newTestGame(false)
+	playMoves(t, ge, g, 4, 0)
+
+	// X's takeback removes O's reply too
+	if err := ge.UndoLastMove(g, x.ID); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(g.Board, make([]models.Symbol, 9)) || g.CurrentTurn != "X" || len(g.Moves) != 0 {
+		t.Errorf("after undo: board=%v turn=%s moves=%d", g.Board, g.CurrentTurn, len(g.Moves))
+	}
+	if err := ge.UndoLastMove(g, o.ID); err == nil {
+		t.Error("undid a move that was never played")
+	}
+
+	rated, rx, _ := newTestGame(true)
+	playMoves(t, ge, rated, 4)
+	if err := ge.UndoLastMove(rated, rx.ID); err == nil {
+		t.Error("takeback allowed in a rated game")
+	}
+}
+
+func TestSeatPlayers(t *testing.T) {
+	ge := NewGameEngine()
+	a, b := models.NewPlayer("a"), models.NewPlayer("b")
+
+	for i := 0; i < 20; i++ {
+		g := models.NewGame()
+		ge.SeatPlayers(g, a, b)
+		if g.PlayerX().Symbol != "X" || g.PlayerO().Symbol != "O" || g.PlayerX() == g.PlayerO() {
+			t.Fatalf("bad seating: X=%s O=%s", g.PlayerX().Name, g.PlayerO().Name)
+		}
+		if g.FirstMoverID != g.PlayerX().ID {
+			t.Fatalf("FirstMoverID = %s, want X %s", g.FirstMoverID, g.PlayerX().ID)
+		}
+	}
+}
+
+func TestMoveTimestampsUseClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	ge := NewGameEngineWithClock(clk)
+
+	g, _, _ := newTestGame(false)
+	playMoves(t, ge, g, 4)
+	clk.Advance(3 * time.Second)
+	playMoves(t, ge, g, 0)
+
+	if !g.Moves[0].Timestamp.Equal(start) || !g.Moves[1].Timestamp.Equal(start.Add(3*time.Second)) {
+		t.Errorf("move timestamps = %v, %v", g.Moves[0].Timestamp, g.Moves[1].Timestamp)
+	}
+}
+
+func TestTeamResultsShared(t *testing.T) {
+	ge := NewGameEngine()
+
+	g, x, o := newTestGame(false)
+	xMate, oMate := models.NewPlayer("x2"), models.NewPlayer("o2")
+	g.Teams = []*models.Team{{Symbol: "X", Players: []*models.Player{x, xMate}}, {Symbol: "O", Players: []*models.Player{o, oMate}}}
+
+	g.Status = models.STATUS_PAUSED
+	if err := ge.Forfeit(g, oMate.ID); err != nil {
+		t.Fatal(err)
+	}
+	ge.RecordResult(g)
+	if g.Winner != "X" || x.CasualWins != 1 || xMate.CasualWins != 1 || o.CasualLosses != 1 || oMate.CasualLosses != 1 {
+		t.Errorf("team forfeit: winner=%q X %+v/%+v O %+v/%+v", g.Winner, x, xMate, o, oMate)
+	}
+
+	if state := ge.GetGameStateForPlayer(g, xMate.ID); state.MySymbol != models.SYMBOL_X || state.OpponentName != "o" {
+		t.Errorf("teammate state = %+v", state)
+	}
+}
+
+func TestPieRule(t *testing.T) {
+	ge := NewGameEngine()
+
+	g, x, o := newTestGame(false)
+	g.PieRule = true
+	playMoves(t, ge, g, 4)
+	if !g.SwapPending {
+		t.Fatal("no swap offered after X's first move")
+	}
+	if err := ge.SwapSides(g, x.ID); err == nil {
+		t.Error("X swapped sides")
+	}
+	if err := ge.SwapSides(g, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	if g.PlayerX() != o || g.PlayerO() != x || o.Symbol != "X" || g.FirstMoverID != o.ID || g.Moves[0].PlayerID != o.ID {
+		t.Errorf("after swap: X=%s O=%s first=%s move by %s", g.PlayerX().Name, g.PlayerO().Name, g.FirstMoverID, g.Moves[0].PlayerID)
+	}
+	if g.CurrentTurn != "O" || g.SwapPending {
+		t.Errorf("after swap: turn=%s pending=%v", g.CurrentTurn, g.SwapPending)
+	}
+	if err := ge.MakeMove(g, x.ID, 0); err != nil {
+		t.Errorf("first mover replying as O: %v", err)
+	}
+
+	// Replying settles the offer too
+	declined, _, _ := newTestGame(false)
+	declined.PieRule = true
+	playMoves(t, ge, declined, 4, 0)
+	if declined.SwapPending {
+		t.Error("swap still offered after O replied")
+	}
+}
+
+func TestMisereLineLoses(t *testing.T) {
+	ge := NewGameEngine()
+
+	g, x, o := newTestGame(false)
+	g.Variant = models.VARIANT_MISERE
+	playMoves(t, ge, g, 0, 3, 1, 4, 2)
+	if g.Status != models.STATUS_FINISHED || g.Winner != "O" {
+		t.Fatalf("X completed a line: status=%s winner=%q", g.Status, g.Winner)
+	}
+
+	ge.RecordResult(g)
+	if o.CasualWins != 1 || x.CasualLosses != 1 || o.Wins != 0 {
+		t.Errorf("misère result not tallied as casual: X %+v, O %+v", x, o)
+	}
+	if state := ge.GetGameStateForPlayer(g, o.ID); state.Variant != models.VARIANT_MISERE {
+		t.Errorf("state variant = %v", state.Variant)
+	}
+}
+
+func TestRatingPoolsAreSeparate(t *testing.T) {
+	ge := NewGameEngine()
+	misere := models.RatingPool(models.VARIANT_MISERE, models.MODE_RATED)
+	blitz := models.RatingPool(models.VARIANT_STANDARD, models.MODE_BLITZ)
+
+	g, x, o := newTestGame(true)
+	x.SetRating(models.RATING_POOL_STANDARD, 1300)
+	g.Variant = models.VARIANT_MISERE
+	playMoves(t, ge, g, 0, 3, 1, 4, 2)
+	ge.RecordResult(g)
+	if o.Wins != 1 || o.Rating(misere) <= models.DEFAULT_RATING || x.Rating(misere) >= models.DEFAULT_RATING {
+		t.Errorf("rated misère result: X %v, O %v", x.Ratings, o.Ratings)
+	}
+	if x.Rating(models.RATING_POOL_STANDARD) != 1300 || o.Rating(models.RATING_POOL_STANDARD) != models.DEFAULT_RATING {
+		t.Errorf("misère game moved standard ratings: X %v, O %v", x.Ratings, o.Ratings)
+	}
+
+	timed, _, _ := newTestGame(true)
+	ge.StartClock(timed, time.Minute)
+	if pool := timed.RatingPool(); pool != blitz {
+		t.Errorf("blitz game pool = %q, want %q", pool, blitz)
+	}
+}
+
+func TestGlickoRatings(t *testing.T) {
+	ge := NewGameEngine()
+	ge.SetGlicko(true)
+	pool := models.RATING_POOL_STANDARD
+
+	g, x, o := newTestGame(true)
+	x.SetRating(pool, 1300)
+	x.Wins = 20
+	playMoves(t, ge, g, 0, 3, 1, 4, 2)
+	ge.RecordResult(g)
+
+	glickoX, glickoO := x.Glicko[pool], o.Glicko[pool]
+	if glickoX.Rating <= 1300 || glickoX.Deviation >= rating.FromElo(1300, 20).Deviation {
+		t.Errorf("winner migrated from Elo 1300 = %+v", glickoX)
+	}
+	if glickoO.Rating >= models.DEFAULT_RATING || glickoO.Deviation >= rating.DEFAULT_DEVIATION {
+		t.Errorf("new loser = %+v", glickoO)
+	}
+	// A new player's rating moves further than an established one's
+	if models.DEFAULT_RATING-glickoO.Rating <= glickoX.Rating-1300 {
+		t.Errorf("loser moved %.1f, winner %.1f", models.DEFAULT_RATING-glickoO.Rating, glickoX.Rating-1300)
+	}
+	if x.Rating(pool) != int(math.Round(glickoX.Rating)) {
+		t.Errorf("X's rating %d doesn't match Glicko-2 rating %.1f", x.Rating(pool), glickoX.Rating)
+	}
+}
+
+// newTrioGame returns a trio game in progress with X, O and Δ seated in that order
+func newTrioGame(winLength int) (*models.Game, []*models.Player) {
+	g := models.NewTrioGame(winLength)
+	for _, name := range []string{"x", "o", "d"} {
+		g.Players = append(g.Players, models.NewPlayer(name))
+	}
+	for i, player := range g.Players {
+		player.Symbol = models.Symbols[i]
+	}
+	g.FirstMoverID = g.Players[0].ID
+	g.Status = models.STATUS_PLAYING
+	return g, g.Players
+}
+
+func TestFindLine(t *testing.T) {
+	ge := NewGameEngine()
+
+	tests := []struct {
+		name      string
+		cells     []int
+		winLength int
+		want      models.Symbol
+	}{
+		{"row of four", []int{5, 6, 7, 8}, 4, "Δ"},
+		{"column of four", []int{4, 9, 14, 19}, 4, "Δ"},
+		{"diagonal of four", []int{1, 7, 13, 19}, 4, "Δ"},
+		{"anti-diagonal of four", []int{3, 7, 11, 15}, 4, "Δ"},
+		{"three when four are needed", []int{0, 1, 2}, 4, ""},
+		{"three when three are needed", []int{12, 16, 20}, 3, "Δ"},
+		{"no wrapping across rows", []int{3, 4, 5, 6}, 4, ""},
+	}
+	for _, tt := range tests {
+		board := make([]models.Symbol, 25)
+		for _, cell := range tt.cells {
+			board[cell] = "Δ"
+		}
+		if got := ge.FindLine(board, 5, tt.winLength); got != tt.want {
+			t.Errorf("%s: FindLine = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGameStateShape(t *testing.T) {
+	ge := NewGameEngine()
+	g, x, _ := newTestGame(false)
+	playMoves(t, ge, g, 4, 0)
+
+	fields := func(state *models.GameStateView) map[string]interface{} {
+		data, err := json.Marshal(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		return decoded
+	}
+
+	state := fields(ge.GetGameStateForPlayer(g, x.ID))
+	for _, key := range []string{"gameId", "board", "currentTurn", "status", "winner", "mySymbol", "opponentName",
+		"isMyTurn", "rated", "moveCount", "takebackRequestedBy", "variant", "size", "winLength", "seq", "spectatorCount"} {
+		if _, present := state[key]; !present {
+			t.Errorf("state is missing %q: %v", key, state)
+		}
+	}
+	for _, key := range []string{"winningLine", "seats", "timeLeftMs", "moveDeadline", "pieRule", "spectating", "ratingProtected"} {
+		if _, present := state[key]; present {
+			t.Errorf("state of a casual game in progress has %q: %v", key, state)
+		}
+	}
+	if state["mySymbol"] != "X" || state["isMyTurn"] != true || state["moveCount"] != 2.0 {
+		t.Errorf("state = %v", state)
+	}
+
+	playMoves(t, ge, g, 1, 3, 7)
+	state = fields(ge.GetGameStateForPlayer(g, x.ID))
+	if line, _ := state["winningLine"].([]interface{}); len(line) != 3 || line[0] != 1.0 || line[1] != 4.0 || line[2] != 7.0 {
+		t.Errorf("winningLine = %v, want the middle column", state["winningLine"])
+	}
+	if state["winner"] != "X" || state["status"] != models.STATUS_FINISHED {
+		t.Errorf("finished state = %v", state)
+	}
+}
+
+func TestTrioTurnsRotate(t *testing.T) {
+	ge := NewGameEngine()
+	g, players := newTrioGame(4)
+
+	playMoves(t, ge, g, 0, 5, 10)
+	if g.CurrentTurn != "X" || g.Board[10] != "Δ" {
+		t.Fatalf("after a round: turn=%s board=%v", g.CurrentTurn, g.Board)
+	}
+	if err := ge.MakeMove(g, players[1].ID, 1); err == nil {
+		t.Error("O moved on X's turn")
+	}
+	if err := ge.MakeMove(g, players[0].ID, 25); err == nil {
+		t.Error("move off the 5x5 board accepted")
+	}
+
+	playMoves(t, ge, g, 1, 6, 11, 2, 7, 12, 3)
+	if g.Status != models.STATUS_FINISHED || g.Winner != "X" {
+		t.Errorf("four in a row: status=%s winner=%q", g.Status, g.Winner)
+	}
+}
+
+func TestTrioForfeitEliminates(t *testing.T) {
+	ge := NewGameEngine()
+	g, players := newTrioGame(4)
+	x, o, d := players[0], players[1], players[2]
+	playMoves(t, ge, g, 0, 5)
+
+	// Δ drops out on their own turn; X and O play on
+	if err := ge.Forfeit(g, d.ID); err != nil {
+		t.Fatal(err)
+	}
+	if g.Status != models.STATUS_PLAYING || g.CurrentTurn != "X" || !g.IsEliminated("Δ") {
+		t.Fatalf("after elimination: status=%s turn=%s eliminated=%v", g.Status, g.CurrentTurn, g.Eliminated)
+	}
+	playMoves(t, ge, g, 1)
+	if g.CurrentTurn != "O" {
+		t.Errorf("turn after X = %s, want O", g.CurrentTurn)
+	}
+	if err := ge.Forfeit(g, d.ID); err == nil {
+		t.Error("eliminated player forfeited twice")
+	}
+
+	// The last player standing wins
+	if err := ge.Forfeit(g, o.ID); err != nil {
+		t.Fatal(err)
+	}
+	if g.Status != models.STATUS_FINISHED || g.Winner != "X" {
+		t.Fatalf("after second forfeit: status=%s winner=%q", g.Status, g.Winner)
+	}
+
+	ge.RecordResult(g)
+	if x.CasualWins != 1 || o.CasualLosses != 1 || d.CasualLosses != 1 || x.Rating(models.RATING_POOL_STANDARD) != models.DEFAULT_RATING {
+		t.Errorf("trio result: X %+v, O %+v, Δ %+v", x, o, d)
+	}
+}
+
+func TestThinkTimeSkipsPauses(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	ge := NewGameEngineWithClock(clk)
+	g, _, _ := newTestGame(false)
+	ge.ResumeClock(g)
+
+	clk.Advance(2 * time.Second)
+	playMoves(t, ge, g, 4)
+	clk.Advance(time.Second)
+	ge.StopClock(g)
+	g.Status = models.STATUS_PAUSED
+	clk.Advance(time.Hour)
+	g.Status = models.STATUS_PLAYING
+	ge.ResumeClock(g)
+	clk.Advance(4 * time.Second)
+	playMoves(t, ge, g, 0)
+
+	if g.Moves[0].ThinkMs != 2000 || g.Moves[1].ThinkMs != 5000 {
+		t.Fatalf("think times = %dms and %dms, want 2000 and 5000 without the pause", g.Moves[0].ThinkMs, g.Moves[1].ThinkMs)
+	}
+}
+
+func TestBlitzClock(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	ge := NewGameEngineWithClock(clk)
+	g, _, o := newTestGame(false)
+	ge.StartClock(g, time.Minute)
+
+	// Only the player to move is charged
+	clk.Advance(10 * time.Second)
+	playMoves(t, ge, g, 4)
+	clk.Advance(20 * time.Second)
+	if left := g.TimeLeftMs(clk.Now()); left["X"] != 50000 || left["O"] != 40000 {
+		t.Fatalf("time left = %v", left)
+	}
+
+	// A paused game stops the clock
+	ge.StopClock(g)
+	g.Status = models.STATUS_PAUSED
+	clk.Advance(time.Hour)
+	g.Status = models.STATUS_PLAYING
+	ge.ResumeClock(g)
+	if left := g.RemainingTime("O", clk.Now()); left != 40*time.Second {
+		t.Fatalf("O's time after a pause = %v", left)
+	}
+
+	if err := ge.TimeOut(g); err == nil {
+		t.Error("timed out with time left")
+	}
+	clk.Advance(40 * time.Second)
+	if err := ge.MakeMove(g, o.ID, 0); err == nil {
+		t.Error("move accepted after the flag fell")
+	}
+	if err := ge.TimeOut(g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Status != models.STATUS_FINISHED || g.Winner != "X" || g.TimeLeft["O"] != 0 {
+		t.Errorf("after time out: status=%s winner=%q time=%v", g.Status, g.Winner, g.TimeLeft)
+	}
+}
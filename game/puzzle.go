@@ -0,0 +1,99 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	"tictactoe-server/models"
+)
+
+// Moves played into a generated puzzle's position before the player takes over
+const (
+	minPuzzlePlies = 2
+	maxPuzzlePlies = 5
+)
+
+// maxPuzzleAnswers is the most first moves a puzzle may accept; positions with more are too easy
+const maxPuzzleAnswers = 2
+
+// GeneratePuzzle plays random moves from an empty classic board until it reaches a position worth solving
+// The same rng state always yields the same puzzle
+func (ge *GameEngine) GeneratePuzzle(rng *rand.Rand) (board [9]models.Symbol, toMove models.Symbol, goal string) {
+	for {
+		board, toMove = [9]models.Symbol{}, models.SYMBOL_X
+		plies := minPuzzlePlies + rng.Intn(maxPuzzlePlies-minPuzzlePlies+1)
+		for i := 0; i < plies && ge.CheckWinner(board) == models.SYMBOL_NONE; i++ {
+			empty := make([]int, 0, 9)
+			for position, cell := range board {
+				if cell == models.SYMBOL_NONE {
+					empty = append(empty, position)
+				}
+			}
+			board[empty[rng.Intn(len(empty))]] = toMove
+			toMove = otherSymbol(toMove)
+		}
+		if ge.CheckWinner(board) != models.SYMBOL_NONE {
+			continue
+		}
+		if goal = ge.puzzleGoal(board, toMove); goal != "" {
+			return board, toMove, goal
+		}
+	}
+}
+
+// puzzleGoal says what the player to move must achieve for the position to be a puzzle, or "" if it isn't one
+// A puzzle has at most maxPuzzleAnswers good first moves, no line to complete on the spot, and moves that go wrong
+func (ge *GameEngine) puzzleGoal(board [9]models.Symbol, toMove models.Symbol) string {
+	scores, bestMoves := ge.scoreMoves(board, toMove, models.VARIANT_STANDARD)
+	if len(bestMoves) == 0 || len(bestMoves) > maxPuzzleAnswers || len(bestMoves) == len(scores) {
+		return ""
+	}
+	for position := range scores {
+		board[position] = toMove
+		completes := ge.CheckWinner(board) != models.SYMBOL_NONE
+		board[position] = models.SYMBOL_NONE
+		if completes {
+			return ""
+		}
+	}
+
+	switch scores[bestMoves[0]] {
+	case 1:
+		return models.PUZZLE_WIN
+	case 0:
+		return models.PUZZLE_DRAW
+	}
+	return ""
+}
+
+// CheckPuzzleLine checks a solution line of a puzzle: the player's moves must keep the goal in reach, the replies
+// must be best defense, and the line must run until the game ends as the goal asks
+// Returns "" when the line solves the puzzle, otherwise why it doesn't
+func (ge *GameEngine) CheckPuzzleLine(board [9]models.Symbol, toMove models.Symbol, goal string, line []int) string {
+	player := toMove
+	for i, position := range line {
+		if board[position] != models.SYMBOL_NONE {
+			return fmt.Sprintf("move %d is on a taken cell", i+1)
+		}
+		if quality, _ := ge.GradeMove(board, toMove, position, models.VARIANT_STANDARD); quality != models.MOVE_OPTIMAL {
+			if toMove == player {
+				return fmt.Sprintf("move %d gives away the %s", i+1, goal)
+			}
+			return fmt.Sprintf("move %d is not the opponent's best reply", i+1)
+		}
+
+		board[position] = toMove
+		winner := ge.CheckWinner(board)
+		if winner != models.SYMBOL_NONE || ge.IsBoardFull(board) {
+			if i != len(line)-1 {
+				return "the line goes on after the game is over"
+			}
+			if goal == models.PUZZLE_WIN && winner != player {
+				return "the line doesn't end in a win"
+			}
+			return ""
+		}
+		toMove = otherSymbol(toMove)
+	}
+	return "the line stops before the game is over"
+}
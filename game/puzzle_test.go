@@ -0,0 +1,65 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+// solutionLine plays best moves for both sides from a position until the game ends
+func solutionLine(ge *GameEngine, board [9]models.Symbol, toMove models.Symbol) []int {
+	var line []int
+	for ge.CheckWinner(board) == "" && !ge.IsBoardFull(board) {
+		position := ge.BestMove(board, toMove, models.VARIANT_STANDARD)
+		board[position] = toMove
+		line = append(line, position)
+		toMove = otherSymbol(toMove)
+	}
+	return line
+}
+
+func TestGeneratedPuzzlesAreSolvable(t *testing.T) {
+	ge := NewGameEngine()
+	goals := make(map[string]int)
+	for seed := int64(0); seed < 50; seed++ {
+		board, toMove, goal := ge.GeneratePuzzle(rand.New(rand.NewSource(seed)))
+		goals[goal]++
+
+		_, bestMoves := ge.scoreMoves(board, toMove, models.VARIANT_STANDARD)
+		if len(bestMoves) > maxPuzzleAnswers {
+			t.Errorf("seed %d: %d answers to %v", seed, len(bestMoves), board)
+		}
+		if reason := ge.CheckPuzzleLine(board, toMove, goal, solutionLine(ge, board, toMove)); reason != "" {
+			t.Errorf("seed %d: best play doesn't solve %v (%s): %s", seed, board, goal, reason)
+		}
+	}
+	if goals[models.PUZZLE_WIN] == 0 || goals[models.PUZZLE_DRAW] == 0 {
+		t.Errorf("goals = %v, want both kinds of puzzle", goals)
+	}
+
+	again, _, _ := ge.GeneratePuzzle(rand.New(rand.NewSource(7)))
+	if first, _, _ := ge.GeneratePuzzle(rand.New(rand.NewSource(7))); first != again {
+		t.Error("the same seed gave different puzzles")
+	}
+}
+
+func TestCheckPuzzleLine(t *testing.T) {
+	ge := NewGameEngine()
+	// O to move holds the draw by taking an edge; a corner lets X fork
+	board := [9]models.Symbol{"X", "", "", "", "O", "", "", "", "X"}
+
+	line := solutionLine(ge, board, "O")
+	if reason := ge.CheckPuzzleLine(board, "O", models.PUZZLE_DRAW, line); reason != "" {
+		t.Fatalf("best line %v refused: %s", line, reason)
+	}
+	if reason := ge.CheckPuzzleLine(board, "O", models.PUZZLE_DRAW, line[:len(line)-1]); reason == "" {
+		t.Error("a line stopping early was accepted")
+	}
+	if reason := ge.CheckPuzzleLine(board, "O", models.PUZZLE_DRAW, []int{2, 6, 5, 3}); reason != "move 1 gives away the draw" {
+		t.Errorf("corner reply: %q", reason)
+	}
+	if reason := ge.CheckPuzzleLine(board, "O", models.PUZZLE_DRAW, []int{0}); reason != "move 1 is on a taken cell" {
+		t.Errorf("taken cell: %q", reason)
+	}
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"slices"
+
+	"tictactoe-server/models"
+)
+
+// ErrUnknownVariant is returned for games whose variant has no registered rule set
+var ErrUnknownVariant = models.NewError(models.ERR_NOT_ALLOWED, "unknown game variant")
+
+// RuleSet is the rules of a game variant: how its board starts, which moves are legal, what a move does and how the game ends
+// The engine handles everything shared by all variants around these calls: seating, turn order,
+// the move log, clocks and status transitions. Rule sets are selected by Game.Variant.
+type RuleSet interface {
+	// InitialState sets up a new game's board, using its Size and WinLength where the variant has them
+	InitialState(game *models.Game)
+	// ValidateMove checks that the symbol to move may play at position, without changing the game
+	ValidateMove(game *models.Game, position int) error
+	// ApplyMove plays a validated move at position for the symbol to move
+	ApplyMove(game *models.Game, position int)
+	// CheckTerminal reports whether the game is over after the latest move, and its result
+	CheckTerminal(game *models.Game) (result models.Result, over bool)
+}
+
+// RegisterRuleSet makes the engine play games of a variant by the given rules, replacing any registered before
+func (ge *GameEngine) RegisterRuleSet(variant string, rules RuleSet) {
+	ge.ruleSets[variant] = rules
+}
+
+// rulesFor returns the rule set of a game's variant; games without one are standard
+func (ge *GameEngine) rulesFor(game *models.Game) (RuleSet, error) {
+	variant := game.Variant
+	if variant == "" {
+		variant = models.VARIANT_STANDARD
+	}
+	rules, exists := ge.ruleSets[variant]
+	if !exists {
+		return nil, ErrUnknownVariant
+	}
+	return rules, nil
+}
+
+// NewGame creates a game of a variant with its board set up by the variant's rules
+func (ge *GameEngine) NewGame(variant string) (*models.Game, error) {
+	return ge.NewSizedGame(variant, 3, 3)
+}
+
+// NewSizedGame creates a game of a variant on a size x size board where winLength marks in a row complete a line
+func (ge *GameEngine) NewSizedGame(variant string, size, winLength int) (*models.Game, error) {
+	game := models.NewGame()
+	game.Variant = variant
+	game.Size, game.WinLength = size, winLength
+	rules, err := ge.rulesFor(game)
+	if err != nil {
+		return nil, err
+	}
+	game.StartTime = ge.clock.Now()
+	rules.InitialState(game)
+	return game, nil
+}
+
+// classicRules is tic-tac-toe on a Size x Size board: WinLength marks in a row win, a full board is a draw
+type classicRules struct{}
+
+func (classicRules) InitialState(game *models.Game) {
+	if game.Size == 0 {
+		game.Size, game.WinLength = 3, 3
+	}
+	game.Board = make([]models.Symbol, game.Size*game.Size)
+	game.CurrentTurn = models.Symbols[0]
+}
+
+func (classicRules) ValidateMove(game *models.Game, position int) error {
+	if position < 0 || position >= len(game.Board) {
+		return ErrInvalidPosition
+	}
+	if game.Board[position] != models.SYMBOL_NONE {
+		return ErrPositionOccupied
+	}
+	return nil
+}
+
+func (classicRules) ApplyMove(game *models.Game, position int) {
+	game.Board[position] = game.CurrentTurn
+}
+
+func (classicRules) CheckTerminal(game *models.Game) (models.Result, bool) {
+	if line := findLine(game.Board, game.Size, game.WinLength); line != nil {
+		return models.WinFor(game.Board[line[0]]), true
+	}
+	if !slices.Contains(game.Board, models.SYMBOL_NONE) {
+		return models.RESULT_DRAW, true
+	}
+	return models.RESULT_NONE, false
+}
+
+// misereRules is classic tic-tac-toe where completing a line loses
+type misereRules struct {
+	classicRules
+}
+
+func (r misereRules) CheckTerminal(game *models.Game) (models.Result, bool) {
+	result, over := r.classicRules.CheckTerminal(game)
+	if over && result != models.RESULT_DRAW {
+		result = models.WinFor(otherSymbol(result.WinningSymbol()))
+	}
+	return result, over
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+// centerRules is a toy variant played on the classic board where taking the center wins outright
+type centerRules struct {
+	classicRules
+}
+
+func (r centerRules) CheckTerminal(game *models.Game) (models.Result, bool) {
+	if center := game.Board[4]; center != "" {
+		return models.WinFor(center), true
+	}
+	return r.classicRules.CheckTerminal(game)
+}
+
+func TestRegisteredRuleSetDecidesGame(t *testing.T) {
+	ge := NewGameEngine()
+	ge.RegisterRuleSet("center", centerRules{})
+
+	g, _, _ := newTestGame(false)
+	g.Variant = "center"
+	playMoves(t, ge, g, 0, 4)
+
+	if g.Status != models.STATUS_FINISHED || g.Winner != "O" {
+		t.Errorf("status/winner = %s/%q, want finished/O", g.Status, g.Winner)
+	}
+}
+
+func TestNewGameUsesVariantRules(t *testing.T) {
+	ge := NewGameEngine()
+
+	g, err := ge.NewGame(models.VARIANT_MISERE)
+	if err != nil {
+		t.Fatalf("NewGame: %v", err)
+	}
+	if len(g.Board) != 9 || g.CurrentTurn != "X" || g.Variant != models.VARIANT_MISERE {
+		t.Fatalf("new game = board %d, turn %s, variant %s", len(g.Board), g.CurrentTurn, g.Variant)
+	}
+
+	x, o := models.NewPlayer("x"), models.NewPlayer("o")
+	ge.SeatPlayers(g, x, o)
+	g.Status = models.STATUS_PLAYING
+	// X completes the top row, which loses under misère rules
+	playMoves(t, ge, g, 0, 3, 1, 4, 2)
+	if g.Winner != "O" {
+		t.Errorf("winner = %q, want O", g.Winner)
+	}
+
+	if _, err := ge.NewGame("ultimate"); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("NewGame(unknown) = %v, want ErrUnknownVariant", err)
+	}
+}
+
+func TestUnknownVariantRefusesMoves(t *testing.T) {
+	ge := NewGameEngine()
+	g, x, _ := newTestGame(false)
+	g.Variant = "gomoku"
+
+	if err := ge.MakeMove(g, x.ID, 0); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("MakeMove = %v, want ErrUnknownVariant", err)
+	}
+	if g.Board[0] != "" || len(g.Moves) != 0 {
+		t.Errorf("refused move changed the game: %v", g.Board)
+	}
+}
@@ -0,0 +1,25 @@
+package game
+
+import "tictactoe-server/models"
+
+// TransitionHook is called after a game changes status
+type TransitionHook func(game *models.Game, from, to string)
+
+// OnTransition registers a hook run after every status change made through the engine
+// Hooks run in registration order on the caller's goroutine
+func (ge *GameEngine) OnTransition(hook TransitionHook) {
+	ge.transitionHooks = append(ge.transitionHooks, hook)
+}
+
+// Transition moves a game to a new status and runs the transition hooks
+// Illegal transitions return an error wrapping models.ErrIllegalTransition and leave the game untouched
+func (ge *GameEngine) Transition(game *models.Game, to string) error {
+	from := game.Status
+	if err := game.Transition(to); err != nil {
+		return err
+	}
+	for _, hook := range ge.transitionHooks {
+		hook(game, from, to)
+	}
+	return nil
+}
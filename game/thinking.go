@@ -0,0 +1,12 @@
+package game
+
+import "tictactoe-server/models"
+
+// RecordThinkTimes adds a finished game's think times to its players' lifetime totals
+func (ge *GameEngine) RecordThinkTimes(game *models.Game) {
+	stats := game.ThinkStats()
+	for i, player := range game.AllPlayers() {
+		player.TimedMoves += stats[i].Moves
+		player.ThinkMs += stats[i].TotalMs
+	}
+}
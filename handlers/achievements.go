@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// Achievement thresholds
+const (
+	winStreakForBadge     = 10  // Consecutive rated wins for ACHIEVEMENT_WIN_STREAK
+	gamesForCenturion     = 100 // Games played, rated and casual, for ACHIEVEMENT_CENTURION
+	perfectSeasonMinGames = 5   // Rated games a perfect season needs
+)
+
+// achievementInfo describes a badge
+type achievementInfo struct {
+	Name        string
+	Description string
+}
+
+// achievements lists every badge that can be earned
+var achievements = map[string]achievementInfo{
+	models.ACHIEVEMENT_FIRST_WIN:      {"First Win", "Win a game"},
+	models.ACHIEVEMENT_WIN_STREAK:     {"Unstoppable", "Win 10 rated games in a row"},
+	models.ACHIEVEMENT_CORNER_KEEPER:  {"Corner Keeper", "Win a game without your opponent taking a corner"},
+	models.ACHIEVEMENT_PERFECT_SEASON: {"Perfect Season", "Win every rated game of a season, at least 5 of them"},
+	models.ACHIEVEMENT_CENTURION:      {"Centurion", "Play 100 games"},
+}
+
+// registerAchievements hooks badge checks into game and season results
+func (gs *GameServer) registerAchievements() {
+	gs.OnGameFinished(gs.checkGameAchievements)
+	gs.OnSeasonEnded(gs.checkSeasonAchievements)
+}
+
+// newBadge creates an earned badge for an achievement
+func (gs *GameServer) newBadge(id string) models.Badge {
+	info := achievements[id]
+	return models.Badge{ID: id, Name: info.Name, Description: info.Description, EarnedAt: gs.clock.Now()}
+}
+
+// checkGameAchievements awards badges earned by a finished game; flagged games earn nothing
+func (gs *GameServer) checkGameAchievements(gameInstance *models.Game) {
+	if len(gameInstance.Players) < 2 || len(gameInstance.Flags) > 0 {
+		return
+	}
+
+	type unlock struct {
+		playerID string
+		id       string
+	}
+	var unlocks []unlock
+
+	for i, player := range gameInstance.Players {
+		symbol := models.Symbols[i]
+		if player.IsBot {
+			continue
+		}
+
+		if gameInstance.Winner == models.WinFor(symbol) {
+			unlocks = append(unlocks, unlock{player.ID, models.ACHIEVEMENT_FIRST_WIN})
+			if gameInstance.Rated && player.CurrentStreak >= winStreakForBadge {
+				unlocks = append(unlocks, unlock{player.ID, models.ACHIEVEMENT_WIN_STREAK})
+			}
+			if !opponentHoldsCorner(gameInstance, symbol) {
+				unlocks = append(unlocks, unlock{player.ID, models.ACHIEVEMENT_CORNER_KEEPER})
+			}
+		}
+
+		played := player.Wins + player.Losses + player.Draws + player.CasualWins + player.CasualLosses + player.CasualDraws
+		if played >= gamesForCenturion {
+			unlocks = append(unlocks, unlock{player.ID, models.ACHIEVEMENT_CENTURION})
+		}
+	}
+
+	for _, u := range unlocks {
+		badge := gs.newBadge(u.id)
+		badge.GameID = gameInstance.ID
+		gs.awardBadge(u.playerID, badge)
+	}
+}
+
+// opponentHoldsCorner reports whether any player opposing symbol ended the game on a corner
+func opponentHoldsCorner(gameInstance *models.Game, symbol models.Symbol) bool {
+	last := gameInstance.Size - 1
+	for _, cell := range []int{0, last, last * gameInstance.Size, last*gameInstance.Size + last} {
+		if occupant := gameInstance.Board[cell]; occupant != models.SYMBOL_NONE && occupant != symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSeasonAchievements awards perfect seasons to ranked players who won every rated game
+func (gs *GameServer) checkSeasonAchievements(archive *models.SeasonArchive) {
+	for _, standing := range archive.Standings {
+		games, won := 0, 0
+		for _, record := range gs.store.GamesForPlayer(standing.PlayerID) {
+			if !record.Rated || record.EndTime.Before(archive.StartedAt) || !record.EndTime.Before(archive.EndedAt) {
+				continue
+			}
+			games++
+			if record.Winner == models.WinFor(record.SymbolFor(standing.PlayerID)) {
+				won++
+			}
+		}
+
+		if games >= perfectSeasonMinGames && won == games {
+			badge := gs.newBadge(models.ACHIEVEMENT_PERFECT_SEASON)
+			badge.Season = archive.Number
+			gs.awardBadge(standing.PlayerID, badge)
+		}
+	}
+}
+
+// awardBadge stores a badge and tells the player's connections, unless it was already earned
+func (gs *GameServer) awardBadge(playerID string, badge models.Badge) {
+	if !gs.store.AwardBadge(playerID, badge) {
+		return
+	}
+	log.Printf("Player %s unlocked %s", playerID, badge.ID)
+
+	for conn := range gs.playerConns[playerID] {
+		gs.sendToClient(conn, models.NewGameMessage(models.MSG_ACHIEVEMENT_UNLOCKED, badge))
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// badgeIDs lists a player's earned badge IDs
+func badgeIDs(gs *GameServer, playerID string) map[string]bool {
+	ids := map[string]bool{}
+	for _, badge := range gs.store.Badges(playerID) {
+		ids[badge.ID] = true
+	}
+	return ids
+}
+
+func TestWinUnlocksAchievements(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startRatedGame(t, wsURL)
+
+	// O only ever holds edges and the center
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+
+	unlocked := map[string]bool{}
+	for len(unlocked) < 2 {
+		var badge models.Badge
+		x.expect(models.MSG_ACHIEVEMENT_UNLOCKED, &badge)
+		if badge.GameID != gameID {
+			t.Errorf("badge %s earned by game %q, want %q", badge.ID, badge.GameID, gameID)
+		}
+		unlocked[badge.ID] = true
+	}
+	if !unlocked[models.ACHIEVEMENT_FIRST_WIN] || !unlocked[models.ACHIEVEMENT_CORNER_KEEPER] {
+		t.Errorf("unlocked %v", unlocked)
+	}
+
+	var profile *models.PlayerProfile
+	gs.do(func() { profile, _ = gs.buildProfile(x.playerID) })
+	if len(profile.Badges) != 2 {
+		t.Errorf("profile badges = %+v", profile.Badges)
+	}
+	if len(gs.store.Badges(o.playerID)) != 0 {
+		t.Error("loser earned a badge")
+	}
+}
+
+func TestGameAchievementRules(t *testing.T) {
+	gs := NewGameServer(testConfig())
+
+	finished := func(winner models.Result, board [9]models.Symbol, prepare func(x, o *models.Player)) (*models.Game, *models.Player, *models.Player) {
+		gameInstance := models.NewGame()
+		x, o := models.NewPlayer("x"), models.NewPlayer("o")
+		gameInstance.Players = []*models.Player{x, o}
+		gameInstance.Rated = true
+		gameInstance.Status = models.STATUS_FINISHED
+		gameInstance.Winner = winner
+		gameInstance.Board = board[:]
+		if prepare != nil {
+			prepare(x, o)
+		}
+		return gameInstance, x, o
+	}
+
+	// The loser held a corner, so only first win applies; a second win changes nothing
+	g, x, _ := finished("X", [9]models.Symbol{"X", "X", "X", "O", "O", "", "", "", "O"}, nil)
+	gs.checkGameAchievements(g)
+	gs.checkGameAchievements(g)
+	if got := gs.store.Badges(x.ID); len(got) != 1 || got[0].ID != models.ACHIEVEMENT_FIRST_WIN {
+		t.Errorf("badges = %+v, want only %s", got, models.ACHIEVEMENT_FIRST_WIN)
+	}
+
+	g, x, o := finished("draw", [9]models.Symbol{}, func(x, o *models.Player) {
+		x.CurrentStreak = winStreakForBadge
+		o.CasualDraws = gamesForCenturion
+	})
+	gs.checkGameAchievements(g)
+	if ids := badgeIDs(gs, o.ID); !ids[models.ACHIEVEMENT_CENTURION] || len(ids) != 1 {
+		t.Errorf("centurion badges = %v", ids)
+	}
+	if ids := badgeIDs(gs, x.ID); len(ids) != 0 {
+		t.Errorf("draw unlocked %v", ids)
+	}
+
+	g, x, _ = finished("X", [9]models.Symbol{"X", "X", "X", "O", "O"}, func(x, o *models.Player) {
+		x.CurrentStreak = winStreakForBadge
+	})
+	gs.checkGameAchievements(g)
+	if ids := badgeIDs(gs, x.ID); !ids[models.ACHIEVEMENT_WIN_STREAK] || !ids[models.ACHIEVEMENT_CORNER_KEEPER] {
+		t.Errorf("streak badges = %v", ids)
+	}
+
+	g, x, _ = finished("X", [9]models.Symbol{"X", "X", "X", "O", "O"}, nil)
+	g.Flags = []string{models.FLAG_FAST_MOVES}
+	gs.checkGameAchievements(g)
+	if ids := badgeIDs(gs, x.ID); len(ids) != 0 {
+		t.Errorf("flagged game unlocked %v", ids)
+	}
+}
+
+func TestPerfectSeasonAchievement(t *testing.T) {
+	gs := NewGameServer(testConfig())
+	start := time.Now().Add(-time.Hour)
+	archive := &models.SeasonArchive{
+		Season:  models.Season{Number: 3, StartedAt: start},
+		EndedAt: start.Add(time.Hour),
+		Standings: []models.SeasonStanding{
+			{PlayerID: "perfect"}, {PlayerID: "drew"}, {PlayerID: "few"},
+		},
+	}
+
+	record := func(playerID string, winner models.Result, offset time.Duration) {
+		gs.store.SaveGame(&models.GameRecord{
+			GameID: playerID, PlayerXID: playerID, PlayerOID: "rival", Winner: winner, Rated: true,
+			EndTime: start.Add(offset),
+		})
+	}
+	for i := 0; i < perfectSeasonMinGames; i++ {
+		record("perfect", "X", time.Minute)
+		record("drew", "X", time.Minute)
+	}
+	record("drew", "draw", time.Minute)
+	record("perfect", "O", -time.Minute) // Lost last season
+	record("few", "X", time.Minute)
+
+	gs.checkSeasonAchievements(archive)
+
+	if badges := gs.store.Badges("perfect"); len(badges) != 1 || badges[0].Season != 3 {
+		t.Errorf("perfect season badges = %+v", badges)
+	}
+	if len(gs.store.Badges("drew")) != 0 || len(gs.store.Badges("few")) != 0 {
+		t.Error("imperfect season earned a badge")
+	}
+}
@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// adminGameView is the admin listing of an active game
+type adminGameView struct {
+	ID          string          `json:"id"`
+	Status      string          `json:"status"`
+	PlayerX     string          `json:"playerX"`
+	PlayerO     string          `json:"playerO"`
+	PlayerDelta string          `json:"playerDelta,omitempty"` // Trio games only
+	CurrentTurn models.Symbol   `json:"currentTurn"`
+	Board       []models.Symbol `json:"board"`
+	StartTime   time.Time       `json:"startTime"`
+	Rated       bool            `json:"rated"`
+	Flags       []string        `json:"flags,omitempty"`
+}
+
+// adminConnectionView is the admin listing of a connected client
+type adminConnectionView struct {
+	PlayerID string `json:"playerId"`
+	Name     string `json:"name"`
+	IP       string `json:"ip"`
+	Rating   int    `json:"rating"`
+}
+
+// AdminHandler returns the handler for the /admin API and console
+// Each route names the role its reads need and the role its changes need
+func (gs *GameServer) AdminHandler() http.Handler {
+	const viewer, moderator, admin = config.ROLE_VIEWER, config.ROLE_MODERATOR, config.ROLE_ADMIN
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/", gs.handleAdminConsole)
+	mux.HandleFunc("/admin/games", gs.requireRole(viewer, viewer, gs.handleAdminListGames))
+	mux.HandleFunc("/admin/games/", gs.requireRole(viewer, moderator, gs.handleAdminGameAction))
+	mux.HandleFunc("/admin/connections", gs.requireRole(viewer, viewer, gs.handleAdminListConnections))
+	mux.HandleFunc("/admin/players/", gs.requireRole(moderator, moderator, gs.handleAdminPlayerAction))
+	mux.HandleFunc("/admin/ratings/reset", gs.requireRole(admin, admin, gs.handleAdminResetRatings))
+	mux.HandleFunc("/admin/announce", gs.requireRole(moderator, moderator, gs.handleAdminAnnounce))
+	mux.HandleFunc("/admin/maintenance", gs.requireRole(viewer, admin, gs.handleAdminMaintenance))
+	mux.HandleFunc("/admin/maintenance/schedule", gs.requireRole(admin, admin, gs.handleAdminMaintenanceSchedule))
+	mux.HandleFunc("/admin/drain", gs.requireRole(viewer, admin, gs.handleAdminDrain))
+	mux.HandleFunc("/admin/metrics", gs.requireRole(viewer, viewer, gs.handleAdminMetrics))
+	mux.HandleFunc("/admin/flags", gs.requireRole(viewer, viewer, gs.handleAdminFlags))
+	mux.HandleFunc("/admin/throttled", gs.requireRole(viewer, moderator, gs.handleAdminThrottled))
+	mux.HandleFunc("/admin/throttled/", gs.requireRole(viewer, moderator, gs.handleAdminThrottled))
+	mux.HandleFunc("/admin/reports", gs.requireRole(viewer, moderator, gs.handleAdminReports))
+	mux.HandleFunc("/admin/reports/", gs.requireRole(viewer, moderator, gs.handleAdminReports))
+	mux.HandleFunc("/admin/config/reload", gs.requireRole(admin, admin, gs.handleAdminConfigReload))
+	mux.HandleFunc("/admin/config/reloads", gs.requireRole(admin, admin, gs.handleAdminConfigReloads))
+	mux.HandleFunc("/admin/audit", gs.requireRole(admin, admin, gs.handleAdminAudit))
+	mux.HandleFunc("/admin/keys", gs.requireRole(admin, admin, gs.handleAdminKeys))
+	mux.HandleFunc("/admin/keys/", gs.requireRole(admin, admin, gs.handleAdminKeys))
+	mux.HandleFunc("/admin/export/games", gs.requireRole(admin, admin, gs.handleAdminExportGames))
+	mux.HandleFunc("/admin/export/players", gs.requireRole(admin, admin, gs.handleAdminExportPlayers))
+	return mux
+}
+
+// handleAdminListGames lists games that are playing or paused
+func (gs *GameServer) handleAdminListGames(w http.ResponseWriter, r *http.Request) {
+	games := make([]adminGameView, 0)
+	gs.do(func() {
+		for _, gameInstance := range gs.games.Games() {
+			if !inProgress(gameInstance) {
+				continue
+			}
+			view := adminGameView{
+				ID:          gameInstance.ID,
+				Status:      gameInstance.Status,
+				CurrentTurn: gameInstance.CurrentTurn,
+				Board:       append([]models.Symbol(nil), gameInstance.Board...),
+				StartTime:   gameInstance.StartTime,
+				Rated:       gameInstance.Rated,
+				Flags:       append([]string(nil), gameInstance.Flags...),
+			}
+			if gameInstance.PlayerX() != nil {
+				view.PlayerX = gameInstance.PlayerX().Name
+			}
+			if gameInstance.PlayerO() != nil {
+				view.PlayerO = gameInstance.PlayerO().Name
+			}
+			if len(gameInstance.Players) == models.TRIO_PLAYERS {
+				view.PlayerDelta = gameInstance.Players[2].Name
+			}
+			games = append(games, view)
+		}
+	})
+
+	writeJSON(w, http.StatusOK, games)
+}
+
+// handleAdminListConnections lists connected clients
+func (gs *GameServer) handleAdminListConnections(w http.ResponseWriter, r *http.Request) {
+	var connections []adminConnectionView
+	gs.do(func() {
+		connections = make([]adminConnectionView, 0, len(gs.clients))
+		for conn, player := range gs.clients {
+			connections = append(connections, adminConnectionView{
+				PlayerID: player.ID,
+				Name:     player.Name,
+				IP:       gs.clientIPs[conn],
+				Rating:   player.Rating(models.RATING_POOL_STANDARD),
+			})
+		}
+	})
+
+	writeJSON(w, http.StatusOK, connections)
+}
+
+// handleAdminGameAction serves POST /admin/games/{id}/end, /admin/games/{id}/cancel and GET /admin/games/{id}/events
+func (gs *GameServer) handleAdminGameAction(w http.ResponseWriter, r *http.Request) {
+	gameID, action := splitAdminPath(r.URL.Path, "/admin/games/")
+
+	if r.Method == http.MethodGet && action == "events" {
+		gs.handleAdminGameEvents(w, gameID)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Winner models.Result `json:"winner"`
+	}
+	if action == "end" {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	code, message := http.StatusOK, ""
+	var status string
+	var winner models.Result
+	gs.do(func() {
+		gameInstance, exists := gs.games.Game(gameID)
+		if !exists {
+			code, message = http.StatusNotFound, "game not found"
+			return
+		}
+
+		var err error
+		switch action {
+		case "end":
+			err = gs.gameEngine.EndGame(gameInstance, body.Winner)
+		case "cancel":
+			err = gs.cancelGame(gameInstance)
+		default:
+			code, message = http.StatusNotFound, "unknown action"
+			return
+		}
+		if err != nil {
+			code, message = http.StatusConflict, err.Error()
+			return
+		}
+
+		status, winner = gameInstance.Status, gameInstance.Winner
+		log.Printf("Admin %s game %s", action, gameID)
+		gs.logEvent(gameID, models.EVENT_ADMIN_ACTION, "", map[string]interface{}{"action": action})
+		if status == models.STATUS_ABORTED {
+			gs.logEvent(gameID, models.EVENT_ABORTED, "", nil)
+		}
+
+		if status == models.STATUS_FINISHED {
+			gs.finishGame(gameInstance)
+		} else {
+			gs.sendGameUpdate(gameInstance)
+		}
+	})
+	if code != http.StatusOK {
+		http.Error(w, message, code)
+		return
+	}
+
+	if action == "end" {
+		gs.auditAdmin(r, models.ADMIN_END_GAME, gameID, map[string]interface{}{"winner": body.Winner})
+	} else {
+		gs.auditAdmin(r, models.ADMIN_CANCEL_GAME, gameID, nil)
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": status, "winner": string(winner)})
+}
+
+// cancelGame aborts an in-progress game without affecting stats
+func (gs *GameServer) cancelGame(gameInstance *models.Game) error {
+	if !inProgress(gameInstance) {
+		return errGameNotInProgress
+	}
+
+	return gs.gameEngine.Transition(gameInstance, models.STATUS_ABORTED)
+}
+
+// handleAdminPlayerAction serves POST /admin/players/{id}/kick, /ban, /unban, /mute and /unmute
+func (gs *GameServer) handleAdminPlayerAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	playerID, action := splitAdminPath(r.URL.Path, "/admin/players/")
+
+	code, message := http.StatusOK, ""
+	var conns []clientConn
+	var ips []string
+	gs.do(func() {
+		if _, exists := gs.players.Player(playerID); !exists {
+			code, message = http.StatusNotFound, "player not found"
+			return
+		}
+
+		closeCode := models.CLOSE_KICKED
+		switch action {
+		case "kick":
+			conns = gs.connectionsForPlayer(playerID)
+		case "ban":
+			conns = gs.connectionsForPlayer(playerID)
+			closeCode = models.CLOSE_BANNED
+			gs.bannedPlayers[playerID] = true
+			ips = gs.banPlayerIPs(playerID, conns)
+		case "unban":
+			delete(gs.bannedPlayers, playerID)
+			ips = gs.unbanPlayerIPs(playerID)
+		case "mute":
+			gs.mutedPlayers[playerID] = true
+		case "unmute":
+			delete(gs.mutedPlayers, playerID)
+		default:
+			code, message = http.StatusNotFound, "unknown action"
+			return
+		}
+
+		// Closing the socket ends the read loop, which runs the normal disconnect cleanup
+		for _, conn := range conns {
+			gs.closeClient(conn, closeCode)
+		}
+		if action == "mute" || action == "unmute" {
+			gs.notifyMuted(playerID, action == "mute")
+		}
+	})
+	if code != http.StatusOK {
+		http.Error(w, message, code)
+		return
+	}
+
+	log.Printf("Admin %s player %s", action, playerID)
+	params := map[string]interface{}{"connectionsClosed": len(conns)}
+	response := map[string]interface{}{"playerId": playerID, "action": action, "connectionsClosed": len(conns)}
+	if action == "ban" || action == "unban" {
+		params["ips"] = ips
+		response["ips"] = ips
+	}
+	// The ADMIN_ names of player operations are the actions in the path
+	gs.auditAdmin(r, action, playerID, params)
+	writeJSON(w, http.StatusOK, response)
+}
+
+// banPlayerIPs bans the addresses of a player's open connections along with the player, returning them
+// They are remembered so unbanning the player lifts them too
+func (gs *GameServer) banPlayerIPs(playerID string, conns []clientConn) []string {
+	ips := gs.playerBanIPs[playerID]
+	for _, conn := range conns {
+		ip := gs.clientIPs[conn]
+		if ip == "" || slices.Contains(ips, ip) {
+			continue
+		}
+		gs.bannedIPs[ip] = true
+		ips = append(ips, ip)
+	}
+	if ips == nil {
+		ips = []string{}
+	}
+	gs.playerBanIPs[playerID] = ips
+	return ips
+}
+
+// unbanPlayerIPs lifts the address bans a player's ban added, except those another banned player's ban also holds,
+// returning the addresses lifted
+func (gs *GameServer) unbanPlayerIPs(playerID string) []string {
+	lifted := []string{}
+	ips := gs.playerBanIPs[playerID]
+	delete(gs.playerBanIPs, playerID)
+	for _, ip := range ips {
+		held := false
+		for _, others := range gs.playerBanIPs {
+			if slices.Contains(others, ip) {
+				held = true
+				break
+			}
+		}
+		if !held {
+			delete(gs.bannedIPs, ip)
+			lifted = append(lifted, ip)
+		}
+	}
+	return lifted
+}
+
+// connectionsForPlayer returns all open connections for a player
+func (gs *GameServer) connectionsForPlayer(playerID string) []clientConn {
+	conns := make([]clientConn, 0, len(gs.playerConns[playerID]))
+	for conn := range gs.playerConns[playerID] {
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+// handleAdminResetRatings resets one player's ratings in every pool (?playerId=) or everyone's
+func (gs *GameServer) handleAdminResetRatings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	playerID := r.URL.Query().Get("playerId")
+
+	reset := 0
+	gs.do(func() {
+		for _, player := range gs.players.Players() {
+			if playerID != "" && player.ID != playerID {
+				continue
+			}
+			player.Ratings = map[string]int{models.RATING_POOL_STANDARD: models.DEFAULT_RATING}
+			player.Glicko = nil
+			reset++
+		}
+		gs.broadcastLeaderboard()
+	})
+
+	log.Printf("Admin reset ratings for %d players", reset)
+	gs.auditAdmin(r, models.ADMIN_RESET_RATINGS, playerID, map[string]interface{}{"reset": reset})
+
+	writeJSON(w, http.StatusOK, map[string]int{"reset": reset})
+}
+
+// handleAdminAnnounce broadcasts a server announcement to all connected clients
+func (gs *GameServer) handleAdminAnnounce(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Message == "" {
+		http.Error(w, "message required", http.StatusBadRequest)
+		return
+	}
+
+	gs.broadcast <- models.NewGameMessage(models.MSG_ANNOUNCEMENT, map[string]string{"message": body.Message})
+
+	log.Printf("Admin announcement: %s", body.Message)
+	gs.auditAdmin(r, models.ADMIN_ANNOUNCE, "", map[string]interface{}{"message": body.Message})
+	writeJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+}
+
+// isBanned reports whether a connecting IP or player ID has been banned
+func (gs *GameServer) isBanned(ip, playerID string) bool {
+	return gs.bannedIPs[ip] || (playerID != "" && gs.bannedPlayers[playerID])
+}
+
+// splitAdminPath splits "/prefix/{id}/{action}" into id and action
+func splitAdminPath(path, prefix string) (string, string) {
+	parts := strings.SplitN(strings.Trim(strings.TrimPrefix(path, prefix), "/"), "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// remoteIP returns the host part of the request's remote address
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// handleAdminConsole serves a minimal browser console for the admin API
+func (gs *GameServer) handleAdminConsole(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/admin/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(adminConsoleHTML))
+}
+
+const adminConsoleHTML = `<!DOCTYPE html>
+<html>
+<head><title>Tic-Tac-Toe Admin</title></head>
+<body style="font-family: sans-serif">
+<h1>Tic-Tac-Toe Admin</h1>
+<p><input id="token" type="password" placeholder="Admin token" size="40"> <input id="actor" placeholder="Your name" size="20"></p>
+<p>
+  <button onclick="call('GET', '/admin/games')">Active games</button>
+  <button onclick="call('GET', '/admin/connections')">Connections</button>
+  <button onclick="call('GET', '/admin/maintenance')">Maintenance status</button>
+  <button onclick="call('GET', '/admin/metrics')">Metrics</button>
+  <button onclick="call('GET', '/admin/flags')">Flags</button>
+  <button onclick="call('GET', '/admin/audit')">Audit log</button>
+</p>
+<p>
+  <input id="target" placeholder="Game or player ID" size="40">
+  <button onclick="call('POST', '/admin/games/' + val('target') + '/end', {winner: 'draw'})">End as draw</button>
+  <button onclick="call('POST', '/admin/games/' + val('target') + '/cancel')">Cancel game</button>
+  <button onclick="call('GET', '/admin/games/' + val('target') + '/events')">Game events</button>
+  <button onclick="call('POST', '/admin/players/' + val('target') + '/kick')">Kick</button>
+  <button onclick="call('POST', '/admin/players/' + val('target') + '/ban')">Ban</button>
+  <button onclick="call('POST', '/admin/players/' + val('target') + '/mute')">Mute</button>
+  <button onclick="call('POST', '/admin/players/' + val('target') + '/unmute')">Unmute</button>
+  <button onclick="call('POST', '/admin/ratings/reset?playerId=' + val('target'))">Reset rating</button>
+</p>
+<p>
+  <input id="message" placeholder="Announcement" size="40">
+  <button onclick="call('POST', '/admin/announce', {message: val('message')})">Announce</button>
+  <button onclick="call('POST', '/admin/maintenance', {enabled: true})">Maintenance on</button>
+  <button onclick="call('POST', '/admin/maintenance', {enabled: false})">Maintenance off</button>
+  <button onclick="call('POST', '/admin/drain', {enabled: true})">Drain on</button>
+  <button onclick="call('POST', '/admin/drain', {enabled: false})">Drain off</button>
+</p>
+<pre id="out"></pre>
+<script>
+function val(id) { return document.getElementById(id).value; }
+function call(method, path, body) {
+  fetch(path, {
+    method: method,
+    headers: {'Authorization': 'Bearer ' + val('token'), 'X-Admin-Actor': val('actor'), 'Content-Type': 'application/json'},
+    body: body ? JSON.stringify(body) : undefined
+  }).then(r => r.text()).then(t => { document.getElementById('out').textContent = t; });
+}
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestUnbanLiftsTheIPBansOfTheBan(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	playerAction := func(action string) map[string]interface{} {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		gs.handleAdminPlayerAction(recorder, httptest.NewRequest(http.MethodPost, "/admin/players/"+alice.playerID+"/"+action, nil))
+		var response map[string]interface{}
+		if recorder.Code != http.StatusOK || json.Unmarshal(recorder.Body.Bytes(), &response) != nil {
+			t.Fatalf("%s = %d %s", action, recorder.Code, recorder.Body)
+		}
+		return response
+	}
+	dialStatus := func(query string) int {
+		t.Helper()
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&"+query, nil)
+		if err != nil {
+			return resp.StatusCode
+		}
+		conn.Close()
+		return resp.StatusCode
+	}
+
+	if ban := playerAction("ban"); len(ban["ips"].([]interface{})) != 1 {
+		t.Fatalf("ban = %v, want the connection's address banned", ban)
+	}
+	if status := dialStatus("name=bob"); status != http.StatusForbidden {
+		t.Fatalf("another player from the banned address got %d, want 403", status)
+	}
+
+	if unban := playerAction("unban"); len(unban["ips"].([]interface{})) != 1 {
+		t.Fatalf("unban = %v, want the address ban lifted", unban)
+	}
+	if status := dialStatus("playerId=" + alice.playerID + "&token=" + alice.token); status != http.StatusSwitchingProtocols {
+		t.Fatalf("unbanned player reconnecting from the same address got %d", status)
+	}
+	if status := dialStatus("name=bob"); status != http.StatusSwitchingProtocols {
+		t.Fatalf("another player from the unbanned address got %d", status)
+	}
+}
+
+func TestUnbanKeepsIPBansAnotherBanHolds(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	for _, target := range []string{alice.playerID + "/ban", bob.playerID + "/ban", alice.playerID + "/unban"} {
+		recorder := httptest.NewRecorder()
+		gs.handleAdminPlayerAction(recorder, httptest.NewRequest(http.MethodPost, "/admin/players/"+target, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s = %d %s", target, recorder.Code, recorder.Body)
+		}
+	}
+	if _, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=carol", nil); err == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("address still banned with bob was admitted")
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tictactoe-server/models"
+	"tictactoe-server/storage"
+)
+
+// Page sizes for GET /admin/audit
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// adminActorHeader names the operator making an admin request, for keys shared by several operators
+const adminActorHeader = "X-Admin-Actor"
+
+// auditAdmin records an admin operation that took effect, and appends it to AdminAuditFile when one is set
+func (gs *GameServer) auditAdmin(r *http.Request, action, target string, params map[string]interface{}) {
+	entry := &models.AdminAuditEntry{
+		Time:   gs.clock.Now(),
+		Actor:  r.Header.Get(adminActorHeader),
+		Key:    adminKeyName(r),
+		IP:     gs.requestIP(r),
+		Action: action,
+		Target: target,
+		Params: params,
+	}
+	if entry.Actor == "" {
+		entry.Actor = entry.Key
+	}
+
+	// Held across both writes so the file lists entries in the order the API does
+	gs.auditMutex.Lock()
+	defer gs.auditMutex.Unlock()
+	gs.store.AddAdminAudit(entry)
+	if path := gs.config.AdminAuditFile; path != "" {
+		if err := storage.AppendAdminAudit(path, entry); err != nil {
+			log.Printf("Failed to append to the admin audit log: %v", err)
+		}
+	}
+}
+
+// loadAdminAudit reads the admin operations recorded by earlier runs from AdminAuditFile
+func (gs *GameServer) loadAdminAudit() {
+	entries, err := storage.ReadAdminAudit(gs.config.AdminAuditFile)
+	if err != nil {
+		log.Printf("Failed to read the admin audit log: %v", err)
+	}
+	gs.store.AddAdminAudit(entries...)
+}
+
+// handleAdminAudit serves GET /admin/audit, newest first
+// ?actor=, ?action= and ?target= filter the entries, ?since= (RFC 3339) drops older ones and ?limit= caps how many
+func (gs *GameServer) handleAdminAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultAuditLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(parsed, maxAuditLimit)
+	}
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	entries := gs.store.AdminAudit()
+	matches := make([]*models.AdminAuditEntry, 0)
+	for i := len(entries) - 1; i >= 0 && len(matches) < limit; i-- {
+		entry := entries[i]
+		if entry.Time.Before(since) {
+			break
+		}
+		if matchesFilter(query.Get("actor"), entry.Actor) && matchesFilter(query.Get("action"), entry.Action) &&
+			matchesFilter(query.Get("target"), entry.Target) {
+			matches = append(matches, entry)
+		}
+	}
+	writeJSON(w, http.StatusOK, matches)
+}
+
+// matchesFilter reports whether a value passes a query filter, which an empty filter always does
+func matchesFilter(filter, value string) bool {
+	return filter == "" || filter == value
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestAdminActionsAreAudited(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdminAuditFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	gs, _, wsURL := newTestServer(t, cfg)
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	admin := func(handler http.HandlerFunc, actor, method, target, body string) {
+		t.Helper()
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set(adminActorHeader, actor)
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s %s = %d %s", method, target, recorder.Code, recorder.Body)
+		}
+	}
+	admin(gs.handleAdminAnnounce, "dana", http.MethodPost, "/admin/announce", `{"message": "Welcome"}`)
+	admin(gs.handleAdminResetRatings, "erik", http.MethodPost, "/admin/ratings/reset?playerId="+alice.playerID, "")
+	admin(gs.handleAdminPlayerAction, "dana", http.MethodPost, "/admin/players/"+alice.playerID+"/kick", "")
+	// Refused operations change nothing and aren't recorded
+	recorder := httptest.NewRecorder()
+	gs.handleAdminPlayerAction(recorder, httptest.NewRequest(http.MethodPost, "/admin/players/nobody/ban", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("banning an unknown player = %d", recorder.Code)
+	}
+
+	audit := func(gs *GameServer, query string) []models.AdminAuditEntry {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		gs.handleAdminAudit(recorder, httptest.NewRequest(http.MethodGet, "/admin/audit"+query, nil))
+		var entries []models.AdminAuditEntry
+		if err := json.Unmarshal(recorder.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("audit%s = %d %s", query, recorder.Code, recorder.Body)
+		}
+		return entries
+	}
+	entries := audit(gs, "")
+	if len(entries) != 3 || entries[0].Action != models.ADMIN_KICK || entries[2].Action != models.ADMIN_ANNOUNCE {
+		t.Fatalf("audit log = %+v, want kick, reset and announcement, newest first", entries)
+	}
+	if kick := entries[0]; kick.Actor != "dana" || kick.Target != alice.playerID || kick.IP == "" || kick.Params["connectionsClosed"] != 1.0 {
+		t.Errorf("kick entry = %+v", kick)
+	}
+	if announcement := entries[2]; announcement.Params["message"] != "Welcome" {
+		t.Errorf("announcement entry = %+v", announcement)
+	}
+	if byDana := audit(gs, "?actor=dana"); len(byDana) != 2 {
+		t.Errorf("entries by dana = %+v, want 2", byDana)
+	}
+	if resets := audit(gs, "?action=reset_ratings&target="+alice.playerID); len(resets) != 1 || resets[0].Actor != "erik" {
+		t.Errorf("rating resets of alice = %+v", resets)
+	}
+	if latest := audit(gs, "?limit=1"); len(latest) != 1 || latest[0].Action != models.ADMIN_KICK {
+		t.Errorf("latest entry = %+v", latest)
+	}
+
+	// The file outlives the server
+	restarted, _, _ := newTestServer(t, cfg)
+	restarted.loadAdminAudit()
+	if entries := audit(restarted, ""); len(entries) != 3 || entries[0].Action != models.ADMIN_KICK {
+		t.Errorf("audit log after a restart = %+v", entries)
+	}
+}
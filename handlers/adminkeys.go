@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// adminKeyBytes is the length of generated admin API keys before encoding
+const adminKeyBytes = 32
+
+// adminTokenKeyName is the name the shared ADMIN_TOKEN goes by in the audit log
+const adminTokenKeyName = "admin-token"
+
+// maxAdminKeyName bounds the names of keys created through the API
+const maxAdminKeyName = 64
+
+// Where an admin API key came from
+const (
+	KEY_FROM_CONFIG = "config" // ADMIN_TOKEN or ADMIN_KEYS
+	KEY_FROM_API    = "api"    // POST /admin/keys; lasts until the server restarts
+)
+
+// adminKey is an admin API key; only a hash of the secret is kept
+type adminKey struct {
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"createdAt"`
+	hash      [sha256.Size]byte
+}
+
+// adminKeyring holds the admin API keys, which HTTP handlers check outside the hub
+type adminKeyring struct {
+	mutex sync.RWMutex
+	keys  map[string]*adminKey // Name -> key
+}
+
+// adminKeyContext is the request context key under which requireRole leaves the name of the key used
+type adminKeyContext struct{}
+
+// loadAdminKeys fills the keyring from ADMIN_TOKEN and ADMIN_KEYS
+func (gs *GameServer) loadAdminKeys() {
+	gs.adminKeys.keys = make(map[string]*adminKey)
+	now := gs.clock.Now()
+	if gs.config.AdminToken != "" {
+		gs.adminKeys.add(adminTokenKeyName, config.ROLE_ADMIN, KEY_FROM_CONFIG, gs.config.AdminToken, now)
+	}
+	for _, key := range gs.config.AdminKeys {
+		if !gs.adminKeys.add(key.Name, key.Role, KEY_FROM_CONFIG, key.Key, now) {
+			log.Printf("Ignoring admin key %q, whose name is taken", key.Name)
+		}
+	}
+}
+
+// add stores a key under a name, reporting false if the name is taken
+func (k *adminKeyring) add(name, role, source, secret string, now time.Time) bool {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if _, exists := k.keys[name]; exists {
+		return false
+	}
+	k.keys[name] = &adminKey{Name: name, Role: role, Source: source, CreatedAt: now, hash: sha256.Sum256([]byte(secret))}
+	return true
+}
+
+// remove revokes a key, refusing to revoke the last admin key
+func (k *adminKeyring) remove(name string) (found, removed bool) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	key, exists := k.keys[name]
+	if !exists {
+		return false, false
+	}
+	if key.Role == config.ROLE_ADMIN {
+		admins := 0
+		for _, other := range k.keys {
+			if other.Role == config.ROLE_ADMIN {
+				admins++
+			}
+		}
+		if admins == 1 {
+			return true, false
+		}
+	}
+	delete(k.keys, name)
+	return true, true
+}
+
+// find returns the key with a secret, or nil; every key is compared so timing reveals nothing
+func (k *adminKeyring) find(secret string) *adminKey {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	hash := sha256.Sum256([]byte(secret))
+	var found *adminKey
+	for _, key := range k.keys {
+		if subtle.ConstantTimeCompare(hash[:], key.hash[:]) == 1 {
+			found = key
+		}
+	}
+	return found
+}
+
+// list returns the keys sorted by name
+func (k *adminKeyring) list() []adminKey {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	keys := make([]adminKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// empty reports whether there are no keys, which disables the admin API
+func (k *adminKeyring) empty() bool {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	return len(k.keys) == 0
+}
+
+// requireRole rejects requests without an admin API key, or whose key's role is below the one needed:
+// read for GET and HEAD requests and write for the rest
+func (gs *GameServer) requireRole(read, write string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if gs.adminKeys.empty() {
+			http.Error(w, "admin API disabled", http.StatusNotFound)
+			return
+		}
+
+		key := gs.adminKeys.find(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if key == nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		required := write
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			required = read
+		}
+		if !config.RoleAllows(key.Role, required) {
+			http.Error(w, "forbidden: needs the "+required+" role", http.StatusForbidden)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), adminKeyContext{}, key.Name)))
+	}
+}
+
+// adminKeyName returns the name of the admin API key a request was made with
+func adminKeyName(r *http.Request) string {
+	name, _ := r.Context().Value(adminKeyContext{}).(string)
+	return name
+}
+
+// newAdminKey returns a random URL-safe admin API key
+func newAdminKey() (string, error) {
+	raw := make([]byte, adminKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// handleAdminKeys serves GET /admin/keys, POST /admin/keys, which creates a key and answers with its secret once,
+// and DELETE /admin/keys/{name}, which revokes one
+func (gs *GameServer) handleAdminKeys(w http.ResponseWriter, r *http.Request) {
+	name, _ := splitAdminPath(r.URL.Path, "/admin/keys")
+
+	switch {
+	case r.Method == http.MethodGet && name == "":
+		writeJSON(w, http.StatusOK, gs.adminKeys.list())
+
+	case r.Method == http.MethodPost && name == "":
+		var body struct {
+			Name string `json:"name"`
+			Role string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		if body.Name == "" || len(body.Name) > maxAdminKeyName {
+			http.Error(w, "name required", http.StatusBadRequest)
+			return
+		}
+		if !slices.Contains(config.ROLES, body.Role) {
+			http.Error(w, "role must be one of "+strings.Join(config.ROLES, ", "), http.StatusBadRequest)
+			return
+		}
+
+		secret, err := newAdminKey()
+		if err != nil {
+			http.Error(w, "could not create key", http.StatusInternalServerError)
+			return
+		}
+		now := gs.clock.Now()
+		if !gs.adminKeys.add(body.Name, body.Role, KEY_FROM_API, secret, now) {
+			http.Error(w, "a key with that name exists", http.StatusConflict)
+			return
+		}
+
+		log.Printf("Admin created %s key %q", body.Role, body.Name)
+		gs.auditAdmin(r, models.ADMIN_CREATE_KEY, body.Name, map[string]interface{}{"role": body.Role})
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"name":      body.Name,
+			"role":      body.Role,
+			"key":       secret,
+			"createdAt": now,
+		})
+
+	case r.Method == http.MethodDelete && name != "":
+		found, removed := gs.adminKeys.remove(name)
+		if !found {
+			http.Error(w, "key not found", http.StatusNotFound)
+			return
+		}
+		if !removed {
+			http.Error(w, "the last admin key can't be revoked", http.StatusConflict)
+			return
+		}
+
+		log.Printf("Admin revoked key %q", name)
+		gs.auditAdmin(r, models.ADMIN_REVOKE_KEY, name, nil)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "revoked", "name": name})
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
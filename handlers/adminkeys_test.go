@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+func TestAdminRoles(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdminToken = "root-token"
+	cfg.AdminKeys = []config.AdminKey{
+		{Name: "vera", Role: config.ROLE_VIEWER, Key: "viewer-key"},
+		{Name: "mo", Role: config.ROLE_MODERATOR, Key: "moderator-key"},
+	}
+	gs, _, wsURL := newTestServer(t, cfg)
+	alice := dialTestClient(t, wsURL, "name=alice")
+	handler := gs.AdminHandler()
+
+	call := func(key, method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Authorization", "Bearer "+key)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	for _, tt := range []struct {
+		key, method, path string
+		want              int
+	}{
+		{"wrong-key", http.MethodGet, "/admin/games", http.StatusUnauthorized},
+		{"viewer-key", http.MethodGet, "/admin/games", http.StatusOK},
+		{"viewer-key", http.MethodGet, "/admin/maintenance", http.StatusOK},
+		{"viewer-key", http.MethodPost, "/admin/players/" + alice.playerID + "/mute", http.StatusForbidden},
+		{"viewer-key", http.MethodGet, "/admin/audit", http.StatusForbidden},
+		{"moderator-key", http.MethodPost, "/admin/players/" + alice.playerID + "/mute", http.StatusOK},
+		{"moderator-key", http.MethodPost, "/admin/ratings/reset", http.StatusForbidden},
+		{"moderator-key", http.MethodGet, "/admin/keys", http.StatusForbidden},
+		{"root-token", http.MethodPost, "/admin/ratings/reset", http.StatusOK},
+	} {
+		if recorder := call(tt.key, tt.method, tt.path, ""); recorder.Code != tt.want {
+			t.Errorf("%s %s with %s = %d, want %d", tt.method, tt.path, tt.key, recorder.Code, tt.want)
+		}
+	}
+
+	recorder := call("root-token", http.MethodPost, "/admin/keys", `{"name": "ada", "role": "admin"}`)
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &created); recorder.Code != http.StatusCreated || err != nil || created.Key == "" {
+		t.Fatalf("creating a key = %d %s", recorder.Code, recorder.Body)
+	}
+	if recorder := call(created.Key, http.MethodGet, "/admin/audit?action=mute", ""); recorder.Code != http.StatusOK ||
+		!strings.Contains(recorder.Body.String(), `"actor":"mo","key":"mo"`) {
+		t.Errorf("audit of the mute = %d %s, want it attributed to mo", recorder.Code, recorder.Body)
+	}
+	if listed := call(created.Key, http.MethodGet, "/admin/keys", "").Body.String(); strings.Contains(listed, created.Key) ||
+		strings.Count(listed, `"name"`) != 4 {
+		t.Errorf("key list = %s, want 4 keys and no secrets", listed)
+	}
+	if recorder := call("root-token", http.MethodPost, "/admin/keys", `{"name": "ada", "role": "viewer"}`); recorder.Code != http.StatusConflict {
+		t.Errorf("reusing a key name = %d, want 409", recorder.Code)
+	}
+
+	if recorder := call(created.Key, http.MethodDelete, "/admin/keys/vera", ""); recorder.Code != http.StatusOK {
+		t.Fatalf("revoking a key = %d %s", recorder.Code, recorder.Body)
+	}
+	if recorder := call("viewer-key", http.MethodGet, "/admin/games", ""); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("revoked key = %d, want 401", recorder.Code)
+	}
+	call(created.Key, http.MethodDelete, "/admin/keys/"+adminTokenKeyName, "")
+	if recorder := call(created.Key, http.MethodDelete, "/admin/keys/ada", ""); recorder.Code != http.StatusConflict {
+		t.Errorf("revoking the last admin key = %d, want 409", recorder.Code)
+	}
+
+	entries := gs.store.AdminAudit()
+	if last := entries[len(entries)-1]; last.Action != models.ADMIN_REVOKE_KEY || last.Target != adminTokenKeyName || last.Key != "ada" {
+		t.Errorf("last audit entry = %+v", last)
+	}
+}
+
+func TestAdminAPIDisabledWithoutKeys(t *testing.T) {
+	gs, _, _ := newTestServer(t, testConfig())
+	recorder := httptest.NewRecorder()
+	gs.AdminHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/games", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("admin API without keys = %d, want 404", recorder.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// queuePongTimeout is how long a connection may go without answering a ping before its queued player counts as gone
+const queuePongTimeout = 3 * latencyProbeInterval
+
+// dropAFKQueuedPlayers takes queued players who have gone quiet out of the queues, so nobody is matched against them
+func (gs *GameServer) dropAFKQueuedPlayers() {
+	var removed []string
+	reasons := map[string]string{}
+	modes := map[string]string{}
+	for _, mode := range gs.matchmaking.Modes() {
+		queue := gs.matchmaking.Queue(mode)
+		for _, playerID := range queue {
+			if reason := gs.afkReason(playerID); reason != "" {
+				removed = append(removed, playerID)
+				reasons[playerID], modes[playerID] = reason, mode
+			}
+		}
+	}
+
+	for _, playerID := range removed {
+		gs.removeFromQueue(playerID)
+		log.Printf("Removed %s player %s from the %s queue", reasons[playerID], playerID, modes[playerID])
+		if len(gs.playerConns[playerID]) > 0 {
+			gs.sendToPlayer(playerID, models.NewGameMessage(models.MSG_QUEUE_REMOVED, map[string]string{
+				"mode":   modes[playerID],
+				"reason": reasons[playerID],
+			}))
+		}
+	}
+}
+
+// afkReason reports why a queued player should leave the queue, or "" if they still look present
+func (gs *GameServer) afkReason(playerID string) string {
+	if timeout := gs.config.QueueIdleTimeout; timeout > 0 && gs.clock.Since(gs.lastActive[playerID]) >= timeout {
+		return models.QUEUE_REMOVED_IDLE
+	}
+
+	// A player is unresponsive once every connection that can be pinged has stopped answering
+	probed, responsive := false, false
+	for conn := range gs.playerConns[playerID] {
+		if lastPong, ok := gs.lastPong[conn]; ok {
+			probed = true
+			responsive = responsive || gs.clock.Since(lastPong) < queuePongTimeout
+		}
+	}
+	if probed && !responsive {
+		return models.QUEUE_REMOVED_UNRESPONSIVE
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// queueRemoval is the data of a queue_removed message
+type queueRemoval struct {
+	Mode   string `json:"mode"`
+	Reason string `json:"reason"`
+}
+
+// answerPing pings the client's connection and waits for the latency update its pong produces
+func answerPing(gs *GameServer, c *testClient) {
+	c.t.Helper()
+	gs.do(gs.probeLatency)
+	c.expect(models.MSG_PLAYER_UPDATE, nil)
+}
+
+func TestIdleQueuedPlayerIsRemoved(t *testing.T) {
+	cfg := testConfig()
+	cfg.QueueIdleTimeout = time.Minute
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+
+	// Anything the player sends resets the idle clock
+	clk.Advance(cfg.QueueIdleTimeout / 2)
+	alice.send(models.MSG_LEADERBOARD, nil)
+	alice.expect(models.MSG_LEADERBOARD, nil)
+	clk.Advance(cfg.QueueIdleTimeout / 2)
+	answerPing(gs, alice)
+	gs.do(gs.dropAFKQueuedPlayers)
+
+	var queued int
+	gs.do(func() { queued = len(gs.matchmaking.Queue(models.MODE_CASUAL)) })
+	if queued != 1 {
+		t.Fatal("active player removed from the queue")
+	}
+
+	clk.Advance(cfg.QueueIdleTimeout / 2)
+	answerPing(gs, alice)
+	gs.do(gs.dropAFKQueuedPlayers)
+	var removal queueRemoval
+	alice.expect(models.MSG_QUEUE_REMOVED, &removal)
+	if removal.Mode != models.MODE_CASUAL || removal.Reason != models.QUEUE_REMOVED_IDLE {
+		t.Errorf("queue_removed = %+v", removal)
+	}
+
+	// A player who comes back can queue again and be matched
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+}
+
+func TestUnresponsiveQueuedPlayerIsRemoved(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, testConfig())
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+
+	clk.Advance(queuePongTimeout)
+	gs.do(gs.dropAFKQueuedPlayers)
+
+	var removal queueRemoval
+	alice.expect(models.MSG_QUEUE_REMOVED, &removal)
+	if removal.Reason != models.QUEUE_REMOVED_UNRESPONSIVE {
+		t.Errorf("queue_removed = %+v", removal)
+	}
+	var queued int
+	gs.do(func() { queued = len(gs.matchmaking.Queue(models.MODE_RATED)) })
+	if queued != 0 {
+		t.Errorf("%d players still queued", queued)
+	}
+}
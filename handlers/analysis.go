@@ -0,0 +1,20 @@
+package handlers
+
+import "tictactoe-server/models"
+
+// sendGameEnd tells both players how the game ended, with every move graded against perfect play
+func (gs *GameServer) sendGameEnd(gameInstance *models.Game, analysis *models.GameAnalysis) {
+	msg := models.NewGameMessageForGame(models.MSG_GAME_END, gameInstance.ID, models.GameEndPayload{
+		GameID:   gameInstance.ID,
+		Winner:   gameInstance.Winner,
+		Analysis: analysis,
+
+		ThinkTimes: gameInstance.ThinkStats(),
+	})
+	for _, player := range gameInstance.AllPlayers() {
+		// A player who forfeited by disconnecting has nowhere to receive it
+		if len(gs.playerConns[player.ID]) > 0 {
+			gs.sendToPlayer(player.ID, msg)
+		}
+	}
+}
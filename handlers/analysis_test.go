@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestGameEndCarriesAnalysis(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startRatedGame(t, wsURL)
+	playTopRowWin(t, x, o, gameID)
+
+	var end models.GameEndPayload
+	x.expect(models.MSG_GAME_END, &end)
+	o.expect(models.MSG_GAME_END, nil)
+	if end.GameID != gameID || end.Winner != "X" || end.Analysis == nil {
+		t.Fatalf("game_end = %+v", end)
+	}
+
+	analysis := end.Analysis
+	if len(analysis.Moves) != len(topRowWin) || len(analysis.Players) != 2 {
+		t.Fatalf("analysis = %+v", analysis)
+	}
+	// Answering a corner opening on the edge loses against perfect play
+	if analysis.Moves[1].Quality != models.MOVE_BLUNDER || analysis.Players[1].Blunders == 0 {
+		t.Errorf("O's edge reply graded %s", analysis.Moves[1].Quality)
+	}
+
+	var profile *models.PlayerProfile
+	gs.do(func() { profile, _ = gs.buildProfile(x.playerID) })
+	if profile.Player.AnalyzedMoves != 3 || profile.Accuracy != analysis.Players[0].Accuracy {
+		t.Errorf("profile accuracy %v over %d moves, want %v over 3", profile.Accuracy, profile.Player.AnalyzedMoves, analysis.Players[0].Accuracy)
+	}
+}
+
+func TestGameEndCarriesThinkTimes(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	// X takes three seconds over each move and O one
+	for i, position := range topRowWin {
+		mover, think := x, 3*time.Second
+		if i%2 == 1 {
+			mover, think = o, time.Second
+		}
+		clk.Advance(think)
+		playMove(t, mover, x, o, gameID, position)
+	}
+
+	var end models.GameEndPayload
+	x.expect(models.MSG_GAME_END, &end)
+	if len(end.ThinkTimes) != 2 {
+		t.Fatalf("think times = %+v, want one per player", end.ThinkTimes)
+	}
+	if xs, os := end.ThinkTimes[0], end.ThinkTimes[1]; xs.PlayerID != x.playerID || xs.Moves != 3 || xs.TotalMs != 9000 || xs.AverageMs != 3000 ||
+		os.PlayerID != o.playerID || os.TotalMs != 2000 || os.AverageMs != 1000 {
+		t.Fatalf("think times = %+v", end.ThinkTimes)
+	}
+
+	var profile *models.PlayerProfile
+	gs.do(func() { profile, _ = gs.buildProfile(o.playerID) })
+	if profile.TotalThinkMs != 2000 || profile.AverageThinkMs != 1000 {
+		t.Errorf("profile think time %dms total, %dms average; want 2000 and 1000", profile.TotalThinkMs, profile.AverageThinkMs)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// maxCheatFlags bounds the flag history kept for the admin API
+const maxCheatFlags = 1000
+
+// fingerprint identifies where a player's latest connection came from
+type fingerprint struct {
+	IPHash    string
+	UserAgent string
+}
+
+// cheatFlag records why a game was flagged
+type cheatFlag struct {
+	GameID    string    `json:"gameId"`
+	Reason    string    `json:"reason"`
+	PlayerIDs []string  `json:"playerIds"`
+	Detail    string    `json:"detail"`
+	FlaggedAt time.Time `json:"flaggedAt"`
+}
+
+// hashIP keeps IPs out of flag records while still letting them be compared
+func hashIP(ip string) string {
+	sum := sha256.Sum256([]byte(ip))
+	return hex.EncodeToString(sum[:8])
+}
+
+// recordFingerprint remembers the origin of a player's newest connection
+func (gs *GameServer) recordFingerprint(playerID, clientIP, userAgent string) {
+	gs.fingerprints[playerID] = fingerprint{IPHash: hashIP(clientIP), UserAgent: userAgent}
+}
+
+// checkSameOrigin flags a new rated game whose players share an IP, a sign of win trading between accounts
+func (gs *GameServer) checkSameOrigin(gameInstance *models.Game) {
+	if !gameInstance.Rated || gs.config.SameIPPolicy == config.SAME_IP_OFF {
+		return
+	}
+
+	x, xKnown := gs.fingerprints[gameInstance.PlayerX().ID]
+	o, oKnown := gs.fingerprints[gameInstance.PlayerO().ID]
+	if !xKnown || !oKnown || x.IPHash != o.IPHash {
+		return
+	}
+
+	detail := "same IP, different user agents"
+	if x.UserAgent == o.UserAgent {
+		detail = "same IP and user agent"
+	}
+	gs.flagGame(gameInstance, models.FLAG_SAME_IP, detail, gameInstance.PlayerX().ID, gameInstance.PlayerO().ID)
+}
+
+// checkMoveTiming tracks how quickly a player answers in a rated game and flags streaks of inhumanly fast replies
+// Must be called before the move is applied so a flag can still keep the result unrated
+func (gs *GameServer) checkMoveTiming(gameInstance *models.Game, playerID string, position int) {
+	if gs.config.FastMoveStreak == 0 {
+		return
+	}
+
+	if !gameInstance.Rated || gs.gameEngine.IsValidMove(gameInstance, playerID, position) != nil {
+		return
+	}
+
+	// Reply time is the think time the move will be recorded with, from the opponent's last move or the start
+	key := gameInstance.ID + "/" + playerID
+	if gameInstance.ThinkTime(gs.clock.Now()) >= gs.config.FastMoveThreshold {
+		delete(gs.fastMoveStreaks, key)
+		return
+	}
+
+	gs.fastMoveStreaks[key]++
+	if gs.fastMoveStreaks[key] >= gs.config.FastMoveStreak {
+		gs.flagGame(gameInstance, models.FLAG_FAST_MOVES,
+			"replies under "+gs.config.FastMoveThreshold.String(), playerID)
+	}
+}
+
+// flagGame marks a game as suspicious and unrated, once per reason
+func (gs *GameServer) flagGame(gameInstance *models.Game, reason, detail string, playerIDs ...string) {
+	for _, existing := range gameInstance.Flags {
+		if existing == reason {
+			return
+		}
+	}
+
+	gameInstance.Flags = append(gameInstance.Flags, reason)
+	gameInstance.Rated = false
+
+	gs.cheatFlags = append(gs.cheatFlags, cheatFlag{
+		GameID:    gameInstance.ID,
+		Reason:    reason,
+		PlayerIDs: playerIDs,
+		Detail:    detail,
+		FlaggedAt: gs.clock.Now(),
+	})
+	if len(gs.cheatFlags) > maxCheatFlags {
+		gs.cheatFlags = gs.cheatFlags[len(gs.cheatFlags)-maxCheatFlags:]
+	}
+
+	log.Printf("Game %s flagged for %s (%s), now unrated", gameInstance.ID, reason, detail)
+	gs.logEvent(gameInstance.ID, models.EVENT_FLAGGED, "", map[string]interface{}{
+		"reason":    reason,
+		"detail":    detail,
+		"playerIds": playerIDs,
+	})
+}
+
+// forgetMoveTiming drops fast-move streaks for a game leaving memory
+func (gs *GameServer) forgetMoveTiming(gameInstance *models.Game) {
+	for _, player := range gameInstance.Players {
+		delete(gs.fastMoveStreaks, gameInstance.ID+"/"+player.ID)
+	}
+}
+
+// handleAdminFlags serves GET /admin/flags, newest first
+func (gs *GameServer) handleAdminFlags(w http.ResponseWriter, r *http.Request) {
+	var flags []cheatFlag
+	gs.do(func() {
+		flags = make([]cheatFlag, 0, len(gs.cheatFlags))
+		for i := len(gs.cheatFlags) - 1; i >= 0; i-- {
+			flags = append(flags, gs.cheatFlags[i])
+		}
+	})
+
+	writeJSON(w, http.StatusOK, flags)
+}
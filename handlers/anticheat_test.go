@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// startRatedGame queues two fresh clients for a rated game and returns them as X and O
+func startRatedGame(t *testing.T, wsURL string) (x, o *testClient, gameID string) {
+	t.Helper()
+	return startGame(t, wsURL, models.MODE_RATED)
+}
+
+// startGame queues two fresh clients in the given mode and returns them as X and O
+func startGame(t *testing.T, wsURL, mode string) (x, o *testClient, gameID string) {
+	t.Helper()
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: mode})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: mode})
+
+	var state testGameState
+	alice.expect(models.MSG_GAME_FOUND, &state)
+	bob.expect(models.MSG_GAME_FOUND, nil)
+	if state.MySymbol == "X" {
+		return alice, bob, state.GameID
+	}
+	return bob, alice, state.GameID
+}
+
+// playMove sends a move and waits until both players have seen it
+func playMove(t *testing.T, mover, x, o *testClient, gameID string, position int) {
+	t.Helper()
+	mover.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &position})
+	x.expect(models.MSG_GAME_UPDATE, nil)
+	o.expect(models.MSG_GAME_UPDATE, nil)
+}
+
+func TestSameIPRatedGameIsFlagged(t *testing.T) {
+	cfg := testConfig()
+	cfg.SameIPPolicy = config.SAME_IP_FLAG
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	_, _, gameID := startRatedGame(t, wsURL)
+
+	var rated bool
+	var flags []string
+	gs.do(func() { rated, flags = testGame(gs, gameID).Rated, testGame(gs, gameID).Flags })
+	if rated || len(flags) != 1 || flags[0] != models.FLAG_SAME_IP {
+		t.Fatalf("rated=%v flags=%v, want unrated with %s", rated, flags, models.FLAG_SAME_IP)
+	}
+
+	recorder := httptest.NewRecorder()
+	gs.handleAdminFlags(recorder, httptest.NewRequest(http.MethodGet, "/admin/flags", nil))
+	var listed []cheatFlag
+	if err := json.Unmarshal(recorder.Body.Bytes(), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].GameID != gameID || len(listed[0].PlayerIDs) != 2 {
+		t.Errorf("admin flags = %+v", listed)
+	}
+}
+
+func TestFastMoveStreakUnratesGame(t *testing.T) {
+	cfg := testConfig()
+	cfg.FastMoveThreshold = 100 * time.Millisecond
+	cfg.FastMoveStreak = 2
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	x, o, gameID := startRatedGame(t, wsURL)
+
+	// X answers instantly every time while O takes a human second
+	playMove(t, x, x, o, gameID, 0)
+	clk.Advance(time.Second)
+	playMove(t, o, x, o, gameID, 3)
+
+	var stillRated bool
+	gs.do(func() { stillRated = testGame(gs, gameID).Rated })
+	if !stillRated {
+		t.Fatal("flagged after a single fast reply")
+	}
+
+	playMove(t, x, x, o, gameID, 1)
+	clk.Advance(time.Second)
+	playMove(t, o, x, o, gameID, 4)
+	playMove(t, x, x, o, gameID, 2)
+
+	var rated bool
+	var flags []string
+	gs.do(func() { rated, flags = testGame(gs, gameID).Rated, testGame(gs, gameID).Flags })
+	if rated || len(flags) != 1 || flags[0] != models.FLAG_FAST_MOVES {
+		t.Fatalf("rated=%v flags=%v, want unrated with %s", rated, flags, models.FLAG_FAST_MOVES)
+	}
+
+	var winner models.Player
+	var cheatFlags []cheatFlag
+	gs.do(func() { winner, cheatFlags = *testPlayer(gs, x.playerID), gs.cheatFlags })
+	if winner.Rating(models.RATING_POOL_STANDARD) != models.DEFAULT_RATING || winner.Wins != 0 || winner.CasualWins != 1 {
+		t.Errorf("flagged win counted as rated: %+v", winner)
+	}
+	if len(cheatFlags) != 1 || cheatFlags[0].PlayerIDs[0] != x.playerID {
+		t.Errorf("cheat flags = %+v", cheatFlags)
+	}
+}
+
+func TestSlowRatedGameIsNotFlagged(t *testing.T) {
+	cfg := testConfig()
+	cfg.FastMoveStreak = 1
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	x, o, gameID := startRatedGame(t, wsURL)
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		clk.Advance(2 * time.Second)
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+
+	var wins int
+	var cheatFlags []cheatFlag
+	gs.do(func() { wins, cheatFlags = testPlayer(gs, x.playerID).Wins, gs.cheatFlags })
+	if wins != 1 || len(cheatFlags) != 0 {
+		t.Errorf("human-paced game flagged: wins=%d flags=%+v", wins, cheatFlags)
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"tictactoe-server/models"
+)
+
+// Recommendations in capacity reports
+const (
+	SCALE_UP     = "scale_up"   // The busiest limit is at or above ScaleUpPercent; add an instance
+	SCALE_STEADY = "steady"     // Load is between the thresholds, or no limits are configured to judge it by
+	SCALE_DOWN   = "scale_down" // Every limit is below ScaleDownPercent; an instance can be drained
+)
+
+// capacityReport is the body of /api/capacity
+type capacityReport struct {
+	LoadPercent    int    `json:"loadPercent"` // Use of the busiest configured limit, from connections, active games and the longest queue
+	Recommendation string `json:"recommendation"`
+	Draining       bool   `json:"draining"`
+	Drained        bool   `json:"drained"` // Draining with no games left, so the instance can be stopped
+	utilization
+}
+
+// capacityReport measures load against the limits and recommends how to scale
+func (gs *GameServer) capacityReport() capacityReport {
+	usage := gs.utilization()
+	report := capacityReport{
+		Recommendation: SCALE_STEADY,
+		Draining:       gs.draining,
+		Drained:        gs.draining && usage.ActiveGames == 0,
+		utilization:    usage,
+	}
+
+	longestQueue := 0
+	for _, queued := range usage.Queued {
+		longestQueue = max(longestQueue, queued)
+	}
+	limited := false
+	for _, load := range [][2]int{
+		{usage.Connections, usage.MaxConnections},
+		{usage.ActiveGames, usage.MaxActiveGames},
+		{longestQueue, usage.MaxQueueLength},
+	} {
+		if load[1] > 0 {
+			limited = true
+			report.LoadPercent = max(report.LoadPercent, load[0]*100/load[1])
+		}
+	}
+
+	switch {
+	case !limited:
+	case report.LoadPercent >= gs.config.ScaleUpPercent:
+		report.Recommendation = SCALE_UP
+	case report.LoadPercent < gs.config.ScaleDownPercent:
+		report.Recommendation = SCALE_DOWN
+	}
+	return report
+}
+
+// HandleCapacity serves GET /api/capacity for autoscalers
+func (gs *GameServer) HandleCapacity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var report capacityReport
+	gs.do(func() { report = gs.capacityReport() })
+	writeJSON(w, http.StatusOK, report)
+}
+
+// newGamesPaused reports whether new games are refused, ahead of or during maintenance or while draining
+func (gs *GameServer) newGamesPaused() bool {
+	status := gs.maintenanceStatus()
+	return status == models.MAINTENANCE_PREPARING || status == models.MAINTENANCE_ACTIVE || gs.draining
+}
+
+// setDraining starts or stops drain mode
+// A draining instance lets games in progress finish but takes no new connections or games, so queued players are
+// sent away to find a game on another instance
+func (gs *GameServer) setDraining(enabled bool) {
+	if gs.draining == enabled {
+		return
+	}
+	gs.draining = enabled
+	if enabled {
+		gs.clearQueues(models.QUEUE_REMOVED_DRAINING)
+	}
+}
+
+// clearQueues takes everyone out of the matchmaking queues, telling them why with a queue_removed reason
+func (gs *GameServer) clearQueues(reason string) {
+	for _, mode := range gs.matchmaking.Modes() {
+		for _, playerID := range gs.matchmaking.Queue(mode) {
+			gs.removeFromQueue(playerID)
+			gs.withdrawFromMatch(playerID)
+			gs.sendToPlayer(playerID, models.NewGameMessage(models.MSG_QUEUE_REMOVED, map[string]string{
+				"mode":   mode,
+				"reason": reason,
+			}))
+		}
+	}
+}
+
+// handleAdminDrain reports or toggles drain mode
+func (gs *GameServer) handleAdminDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		var body struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+
+		gs.do(func() { gs.setDraining(body.Enabled) })
+
+		log.Printf("Admin set drain mode: %v", body.Enabled)
+		gs.auditAdmin(r, models.ADMIN_DRAIN, "", map[string]interface{}{"enabled": body.Enabled})
+	}
+
+	var report capacityReport
+	gs.do(func() { report = gs.capacityReport() })
+
+	writeJSON(w, http.StatusOK, map[string]bool{"enabled": report.Draining, "drained": report.Drained})
+}
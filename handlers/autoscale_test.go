@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// capacity fetches /api/capacity
+func capacity(t *testing.T, gs *GameServer) capacityReport {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	gs.HandleCapacity(recorder, httptest.NewRequest(http.MethodGet, "/api/capacity", nil))
+	var report capacityReport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	return report
+}
+
+func TestCapacityRecommendsScaling(t *testing.T) {
+	cfg := testConfig()
+	cfg.ScaleUpPercent = 80
+	cfg.ScaleDownPercent = 30
+	gs, _, wsURL := newTestServer(t, cfg)
+	dialTestClient(t, wsURL, "name=alice")
+
+	if report := capacity(t, gs); report.Recommendation != SCALE_STEADY {
+		t.Fatalf("recommendation without limits = %s, want steady", report.Recommendation)
+	}
+
+	gs.do(func() { gs.config.MaxConnections = 4 })
+	if report := capacity(t, gs); report.LoadPercent != 25 || report.Recommendation != SCALE_DOWN {
+		t.Fatalf("report at 1/4 connections = %+v, want scale_down", report)
+	}
+
+	dialTestClient(t, wsURL, "name=bob")
+	dialTestClient(t, wsURL, "name=carol")
+	dialTestClient(t, wsURL, "name=dave")
+	if report := capacity(t, gs); report.LoadPercent != 100 || report.Recommendation != SCALE_UP {
+		t.Fatalf("report at 4/4 connections = %+v, want scale_up", report)
+	}
+
+	recorder := httptest.NewRecorder()
+	gs.HandleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(recorder.Body.String(), `ttt_scale_recommendation{recommendation="scale_up"} 1`) {
+		t.Errorf("metrics lack the recommendation:\n%s", recorder.Body)
+	}
+}
+
+func TestDrainFinishesGamesButTakesNoNewOnes(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+	carol := dialTestClient(t, wsURL, "name=carol")
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	carol.expect(models.MSG_QUEUE_JOINED, nil)
+
+	gs.do(func() { gs.setDraining(true) })
+	var removed map[string]string
+	carol.expect(models.MSG_QUEUE_REMOVED, &removed)
+	if removed["reason"] != models.QUEUE_REMOVED_DRAINING {
+		t.Fatalf("queue_removed = %+v, want draining", removed)
+	}
+	if code, report := probe(t, gs.HandleReadiness); code != http.StatusServiceUnavailable || !report.Draining {
+		t.Fatalf("readiness while draining = %d %+v", code, report)
+	}
+	if _, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=dave", nil); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("new connection while draining was not refused with 503")
+	}
+	carol.send(models.MSG_PLAY_BOT, models.PlayBotPayload{})
+	var refused models.ErrorPayload
+	carol.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_MAINTENANCE {
+		t.Fatalf("play_bot while draining = %+v", refused)
+	}
+
+	if report := capacity(t, gs); report.Drained {
+		t.Fatal("drained with a game in progress")
+	}
+	playTopRowWin(t, x, o, gameID)
+	if report := capacity(t, gs); !report.Draining || !report.Drained {
+		t.Fatalf("report after the last game = %+v, want drained", report)
+	}
+}
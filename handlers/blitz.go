@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// updateBlitzClock restarts a blitz game's flag timer for the player to move and tells everyone the times
+// Does nothing for games without a clock; once the game stops playing the timer is cancelled
+func (gs *GameServer) updateBlitzClock(gameInstance *models.Game) {
+	if gameInstance.TimeLeft == nil {
+		return
+	}
+
+	gs.stopFlagTimer(gameInstance.ID)
+	now := gs.clock.Now()
+	if gameInstance.Status == models.STATUS_PLAYING {
+		gs.flagTimers[gameInstance.ID] = gs.clock.AfterFunc(gameInstance.RemainingTime(gameInstance.CurrentTurn, now),
+			gs.doLater(func() { gs.flagFall(gameInstance) }))
+	}
+
+	msg := models.NewGameMessageForGame(models.MSG_CLOCK_UPDATE, gameInstance.ID, models.NewClockUpdate(gameInstance, now))
+	for _, player := range gameInstance.AllPlayers() {
+		if !player.IsBot && gs.isConnected(player.ID) {
+			gs.sendToPlayer(player.ID, msg)
+		}
+	}
+	gs.sendToSpectatorsOf(gameInstance, msg, nil)
+}
+
+// flagFall ends a blitz game when the player to move runs out of time
+func (gs *GameServer) flagFall(gameInstance *models.Game) {
+	delete(gs.flagTimers, gameInstance.ID)
+	loser := gameInstance.SidePlayers(gameInstance.CurrentTurn)
+	if err := gs.gameEngine.TimeOut(gameInstance); err != nil {
+		// Moved, paused or ended just before the flag fell
+		return
+	}
+
+	log.Printf("Game %s lost on time by %s", gameInstance.ID, loser[0].Name)
+	gs.logEvent(gameInstance.ID, models.EVENT_FORFEIT, loser[0].ID, map[string]interface{}{"reason": "timeout"})
+	gs.finishGame(gameInstance)
+}
+
+// stopFlagTimer cancels a blitz game's pending flag fall, if any
+func (gs *GameServer) stopFlagTimer(gameID string) {
+	if timer, exists := gs.flagTimers[gameID]; exists {
+		timer.Stop()
+		delete(gs.flagTimers, gameID)
+	}
+}
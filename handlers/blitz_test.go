@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestBlitzClockUpdatesAndFlagFall(t *testing.T) {
+	cfg := testConfig()
+	cfg.BlitzTime = 30 * time.Second
+	_, clk, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_BLITZ)
+
+	var update models.ClockUpdate
+	x.expect(models.MSG_CLOCK_UPDATE, &update)
+	if !update.Running || update.CurrentTurn != "X" || update.TimeLeftMs["X"] != 30000 || update.TimeLeftMs["O"] != 30000 {
+		t.Fatalf("clock at start = %+v", update)
+	}
+
+	// X thinks for 12 seconds, then O's clock runs
+	clk.Advance(12 * time.Second)
+	playMove(t, x, x, o, gameID, 4)
+	o.expect(models.MSG_CLOCK_UPDATE, &update)
+	if update.CurrentTurn != "O" || update.TimeLeftMs["X"] != 18000 || update.TimeLeftMs["O"] != 30000 {
+		t.Fatalf("clock after X's move = %+v", update)
+	}
+
+	// O never moves and loses on time
+	clk.Advance(cfg.BlitzTime)
+	var state testGameState
+	for state.Status != models.STATUS_FINISHED {
+		x.expect(models.MSG_GAME_UPDATE, &state)
+	}
+	if state.Winner != "X" {
+		t.Errorf("winner after O's flag fell = %q", state.Winner)
+	}
+	x.expect(models.MSG_CLOCK_UPDATE, &update)
+	if update.Running || update.TimeLeftMs["O"] != 0 {
+		t.Errorf("clock after the flag fell = %+v", update)
+	}
+}
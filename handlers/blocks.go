@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// maxBlockedPlayers caps how many players one player may block
+const maxBlockedPlayers = 500
+
+// handleBlockPlayer adds a player to the sender's block list and answers with the list
+// Blocked players can't challenge, join the lobbies of or follow the invites of the player who blocked them,
+// aren't paired with them in one-on-one queues and have their chat and emotes hidden from them
+func (gs *GameServer) handleBlockPlayer(conn clientConn, player *models.Player, request *models.BlockPayload) {
+	if request.PlayerID == player.ID {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot block yourself")
+		return
+	}
+	blocked, exists := gs.players.Player(request.PlayerID)
+	if !exists || blocked.Deleted {
+		gs.sendClientError(conn, models.ERR_PLAYER_NOT_FOUND, "Player not found")
+		return
+	}
+	if !gs.store.HasBlocked(player.ID, blocked.ID) && len(gs.store.BlockedPlayers(player.ID)) >= maxBlockedPlayers {
+		gs.sendClientError(conn, models.ERR_LIMIT_REACHED, "Your block list is full")
+		return
+	}
+
+	entry := models.BlockedPlayer{PlayerID: blocked.ID, Name: blocked.Name, BlockedAt: gs.clock.Now()}
+	if gs.store.BlockPlayer(player.ID, entry) {
+		log.Printf("Player %s blocked %s", player.ID, blocked.ID)
+		for _, c := range gs.challenges {
+			if (c.ChallengerID == player.ID && c.OpponentID == blocked.ID) || (c.ChallengerID == blocked.ID && c.OpponentID == player.ID) {
+				gs.closeChallenge(c, models.CHALLENGE_DECLINED, "")
+			}
+		}
+	}
+	gs.sendBlockList(conn, player.ID)
+}
+
+// handleUnblockPlayer takes a player off the sender's block list and answers with the list
+func (gs *GameServer) handleUnblockPlayer(conn clientConn, player *models.Player, request *models.BlockPayload) {
+	if gs.store.UnblockPlayer(player.ID, request.PlayerID) {
+		log.Printf("Player %s unblocked %s", player.ID, request.PlayerID)
+	}
+	gs.sendBlockList(conn, player.ID)
+}
+
+// sendBlockList sends a player their block list, oldest first
+func (gs *GameServer) sendBlockList(conn clientConn, playerID string) {
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_BLOCK_LIST, map[string]interface{}{
+		"blocked": gs.store.BlockedPlayers(playerID),
+	}))
+}
+
+// eitherBlocked reports whether either of two players has blocked the other
+func (gs *GameServer) eitherBlocked(playerID, otherID string) bool {
+	return gs.store.HasBlocked(playerID, otherID) || gs.store.HasBlocked(otherID, playerID)
+}
+
+// sendChatToSpectators sends a chat message or emote to a game's spectators, leaving out those who blocked its sender
+func (gs *GameServer) sendChatToSpectators(gameID, senderID string, msg *models.GameMessage) {
+	room, exists := gs.spectators[gameID]
+	if !exists {
+		return
+	}
+	for conn, watcher := range room.conns {
+		if !gs.store.HasBlocked(watcher.ID, senderID) {
+			gs.sendToClient(conn, msg)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+// blockList is the data of a block_list message
+type blockList struct {
+	Blocked []models.BlockedPlayer `json:"blocked"`
+}
+
+func TestBlockedPlayers(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+	carol := dialTestClient(t, wsURL, "name=carol")
+
+	alice.send(models.MSG_BLOCK_PLAYER, models.BlockPayload{PlayerID: bob.playerID})
+	var list blockList
+	alice.expect(models.MSG_BLOCK_LIST, &list)
+	if len(list.Blocked) != 1 || list.Blocked[0].PlayerID != bob.playerID || list.Blocked[0].Name != "bob" {
+		t.Fatalf("block list = %+v, want bob", list.Blocked)
+	}
+
+	bob.send(models.MSG_CHALLENGE, models.ChallengePayload{OpponentID: alice.playerID})
+	var refused models.ErrorPayload
+	bob.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_NOT_ALLOWED {
+		t.Fatalf("challenging a player who blocked you = %+v", refused)
+	}
+
+	// Carol arrives third but is the only opponent either of them may get
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	bob.expect(models.MSG_QUEUE_JOINED, nil)
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	gameID := carol.expect(models.MSG_GAME_FOUND, nil).GameID
+	if found := alice.expect(models.MSG_GAME_FOUND, nil); found.GameID != gameID {
+		t.Fatalf("alice was matched into %s, want carol's game %s", found.GameID, gameID)
+	}
+	var stillQueued bool
+	gs.do(func() { _, stillQueued = gs.queuedMode(bob.playerID) })
+	if !stillQueued {
+		t.Fatal("bob left the queue")
+	}
+
+	// Chat from a blocked spectator is hidden from the player who blocked them
+	bob.send(models.MSG_LEAVE_QUEUE, models.EmptyPayload{})
+	bob.send(models.MSG_SPECTATE_GAME, models.GamePayload{GameID: gameID})
+	bob.expect(models.MSG_GAME_UPDATE, nil)
+	dave := dialTestClient(t, wsURL, "name=dave")
+	dave.send(models.MSG_SPECTATE_GAME, models.GamePayload{GameID: gameID})
+	dave.expect(models.MSG_GAME_UPDATE, nil)
+
+	bob.send(models.MSG_SPECTATOR_CHAT, models.ChatPayload{GameID: gameID, Text: "from bob"})
+	var line chatLine
+	carol.expect(models.MSG_SPECTATOR_CHAT, &line)
+	dave.send(models.MSG_SPECTATOR_CHAT, models.ChatPayload{GameID: gameID, Text: "from dave"})
+	alice.expect(models.MSG_SPECTATOR_CHAT, &line)
+	if line.Text != "from dave" {
+		t.Errorf("alice got %q, want bob's chat hidden", line.Text)
+	}
+
+	alice.send(models.MSG_UNBLOCK_PLAYER, models.BlockPayload{PlayerID: bob.playerID})
+	alice.expect(models.MSG_BLOCK_LIST, &list)
+	alice.send(models.MSG_GET_BLOCK_LIST, models.EmptyPayload{})
+	alice.expect(models.MSG_BLOCK_LIST, &list)
+	if len(list.Blocked) != 0 {
+		t.Errorf("block list after unblocking = %+v", list.Blocked)
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"tictactoe-server/models"
+
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// botMoveDelay makes bot replies feel less instantaneous
+const botMoveDelay = 600 * time.Millisecond
+
+// runBotBackfill periodically matches players who have waited too long against a bot
+// It runs even while backfill is off, since a config reload may turn it on
+func (gs *GameServer) runBotBackfill() {
+	ticker := gs.clock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		gs.do(func() {
+			if gs.config.BotBackfillAfter > 0 {
+				gs.backfillBots()
+			}
+		})
+	}
+}
+
+// backfillBots moves players who have waited too long out of the queues and into bot games
+func (gs *GameServer) backfillBots() {
+	var waiting []*models.Player
+	queuedFor := make(map[string]string) // Player ID -> the mode they waited in
+	// Bot games count against the active game limit too
+	slots := -1
+	if gs.config.MaxActiveGames > 0 {
+		slots = gs.config.MaxActiveGames - gs.activeGameCount()
+	}
+	if !gs.newGamesPaused() {
+		for _, mode := range gs.matchmaking.Modes() {
+			queue := gs.matchmaking.Queue(mode)
+			// Bots don't play in teams, trios or correspondence games
+			if mode == models.MODE_TEAM || mode == models.MODE_TRIO || mode == models.MODE_CORRESPONDENCE {
+				continue
+			}
+			for _, playerID := range queue {
+				if slots >= 0 && len(waiting) >= slots {
+					break
+				}
+				if queuedAt, _ := gs.matchmaking.QueuedAt(playerID); gs.clock.Since(queuedAt) < gs.config.BotBackfillAfter {
+					continue
+				}
+				if player, exists := gs.players.Player(playerID); exists {
+					gs.removeFromQueue(playerID)
+					waiting = append(waiting, player)
+					queuedFor[playerID] = mode
+				}
+			}
+		}
+	}
+
+	for _, player := range waiting {
+		log.Printf("Matching %s with a bot after queue timeout", player.Name)
+		gs.createBotMatch(player, models.NewBotPlayer(), queuedFor[player.ID])
+	}
+}
+
+// handlePlayBot starts a practice game against a bot of the chosen personality, leaving any queue the player was in
+func (gs *GameServer) handlePlayBot(conn clientConn, player *models.Player, request *models.PlayBotPayload) {
+	if gs.newGamesPaused() {
+		gs.sendClientError(conn, models.ERR_MAINTENANCE, "New games are paused on this server")
+		return
+	}
+	if gs.pendingMatch(player.ID) != nil {
+		gs.sendClientError(conn, models.ERR_ALREADY_PENDING, "Accept or decline your proposed match first")
+		return
+	}
+	if gs.activeGameForPlayer(player.ID) != nil {
+		gs.sendClientError(conn, models.ERR_ALREADY_IN_GAME, "You are already in a game")
+		return
+	}
+	if _, err := gs.gameEngine.BotStrategyFor(request.Personality); err != nil {
+		gs.sendClientError(conn, models.ErrorCode(err), err.Error())
+		return
+	}
+	if gs.atGameLimit() {
+		gs.sendServerFull(player.ID, models.FULL_GAMES, "The server is at its game limit, try again shortly")
+		return
+	}
+
+	gs.removeFromQueue(player.ID)
+	log.Printf("Player %s (%s) started %s practice against a %s bot", player.Name, player.ID, request.Mode, request.Personality)
+	gs.createBotMatch(player, models.NewPracticeBot(request.Personality), request.Mode)
+}
+
+// createBotMatch starts a casual game between a player and a bot, under misère or blitz rules if the mode asks for them
+func (gs *GameServer) createBotMatch(player, bot *models.Player, mode string) {
+	newGame := gs.newGameForMode(mode)
+	gs.gameEngine.SeatPlayers(newGame, player, bot)
+	if mode == models.MODE_BLITZ {
+		gs.gameEngine.StartClock(newGame, gs.config.BlitzTime)
+	}
+	gs.addGame(newGame)
+
+	log.Printf("Created bot game %s for %s", newGame.ID, player.Name)
+	gs.logGameCreated(newGame)
+
+	gs.sendToPlayer(player.ID, models.NewGameMessageForGame(models.MSG_GAME_FOUND, newGame.ID,
+		gs.gameEngine.GetGameStateForPlayer(newGame, player.ID)))
+	gs.updateBlitzClock(newGame)
+
+	// The bot may have been seated as X
+	gs.scheduleBotMove(newGame)
+}
+
+// scheduleBotMove plays the bot's turn after a short delay if it is the bot's move
+func (gs *GameServer) scheduleBotMove(gameInstance *models.Game) {
+	bot := gs.botToMove(gameInstance)
+	if bot == nil {
+		return
+	}
+
+	gs.clock.AfterFunc(botMoveDelay, gs.doLater(func() {
+		gs.makeBotMove(gameInstance.ID, bot)
+	}))
+}
+
+// botToMove returns the bot whose turn it is, or nil if a human is to move
+func (gs *GameServer) botToMove(gameInstance *models.Game) *models.Player {
+	if gameInstance.Status != models.STATUS_PLAYING {
+		return nil
+	}
+
+	side := gameInstance.SidePlayers(gameInstance.CurrentTurn)
+	if len(side) == 0 || !side[0].IsBot {
+		return nil
+	}
+	return side[0]
+}
+
+// makeBotMove computes and plays the bot's move
+func (gs *GameServer) makeBotMove(gameID string, bot *models.Player) {
+	gameInstance, exists := gs.games.Game(gameID)
+	if !exists || gs.botToMove(gameInstance) != bot {
+		return
+	}
+
+	// Bot replies run off a timer, so each starts its own trace
+	ctx, span := tracer.Start(context.Background(), "botMove", trace.WithAttributes(ATTR_GAME_ID.String(gameID),
+		ATTR_PLAYER_ID.String(bot.ID)))
+	defer span.End()
+
+	strategy, err := gs.gameEngine.BotStrategyFor(bot.Personality)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+		log.Printf("Bot in game %s can't move: %v", gameID, err)
+		return
+	}
+	position := strategy.ChooseMove(gameInstance.ClassicBoard(), bot.Symbol, gameInstance.Variant)
+	span.SetAttributes(ATTR_MOVE_POSITION.Int(position))
+	if err := gs.gameEngine.MakeMove(gameInstance, bot.ID, position); err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+		log.Printf("Bot move failed in game %s: %v", gameID, err)
+		return
+	}
+
+	gs.afterMove(ctx, gameInstance)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestPlayBotPersonality(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	alice.send(models.MSG_PLAY_BOT, models.PlayBotPayload{Personality: models.BOT_CENTER_FIRST})
+	var found map[string]interface{}
+	alice.expect(models.MSG_GAME_FOUND, &found)
+	if found["opponentName"] != "Bot (center first)" || found["opponentIsBot"] != true || found["rated"] != false {
+		t.Fatalf("game_found = %+v, want an unrated game against the centre-first bot", found)
+	}
+
+	botSymbol := "X"
+	if found["mySymbol"] == "X" {
+		botSymbol = "O"
+		corner := 0
+		alice.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: found["gameId"].(string), Position: &corner})
+		alice.expect(models.MSG_GAME_UPDATE, nil)
+	}
+
+	gs.do(func() {}) // The bot's move is scheduled once the hub is done with the last one
+	clk.Advance(botMoveDelay)
+	var update map[string]interface{}
+	alice.expect(models.MSG_GAME_UPDATE, &update)
+	if board := update["board"].([]interface{}); board[4] != botSymbol {
+		t.Errorf("board after the bot's first move = %v, want %s in the centre", board, botSymbol)
+	}
+
+	alice.send(models.MSG_PLAY_BOT, models.PlayBotPayload{})
+	var refused models.ErrorPayload
+	alice.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_ALREADY_IN_GAME {
+		t.Errorf("play_bot during a game = %+v", refused)
+	}
+}
+
+func TestPlayBotRejectsUnknownPersonality(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	alice.send(models.MSG_PLAY_BOT, models.PlayBotPayload{Personality: "grandmaster"})
+	var refused models.ErrorPayload
+	alice.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_INVALID_PAYLOAD {
+		t.Errorf("unknown personality = %+v", refused)
+	}
+}
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// Rows a bulk export page holds when no limit is asked for, and at most
+const (
+	defaultBulkExportLimit = 1000
+	maxBulkExportLimit     = 50000
+)
+
+// maxPlayerExportLimit caps player export pages lower, since each page's players are copied on the hub
+const maxPlayerExportLimit = 5000
+
+// bulkExportFlushRows is how many rows are written between flushes, so large pages reach the client as they go
+const bulkExportFlushRows = 100
+
+// nextCursorHeader carries the cursor of a bulk export's next page; it is left out on the last page
+const nextCursorHeader = "X-Next-Cursor"
+
+// Formats bulk exports stream rows in
+const (
+	BULK_NDJSON = "ndjson" // One JSON object per line
+	BULK_CSV    = "csv"    // A header row, then one row per record
+)
+
+// gameCSVHeader names the columns of a CSV game export
+var gameCSVHeader = []string{"gameId", "variant", "size", "rated", "winner", "playerXId", "playerXName", "playerOId",
+	"playerOName", "playerDeltaId", "playerDeltaName", "firstMoverId", "startTime", "endTime", "moves"}
+
+// playerCSVHeader names the columns of a CSV player export
+var playerCSVHeader = []string{"id", "name", "ratings", "wins", "losses", "draws", "casualWins", "casualLosses",
+	"casualDraws", "longestStreak", "region", "isBot", "deleted", "lastSeen"}
+
+// bulkExportQuery is a bulk export request's format, date range and page
+type bulkExportQuery struct {
+	format string
+	since  time.Time // Inclusive; zero for no lower bound
+	until  time.Time // Exclusive; zero for no upper bound
+	cursor string    // Where the page starts; empty for the first page
+	limit  int
+}
+
+// playerKey is what the player export sorts and filters players by, copied off the hub
+type playerKey struct {
+	id       string
+	lastSeen time.Time
+}
+
+// exportedPlayer is a player as a bulk export lists them
+type exportedPlayer struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Ratings       map[string]int `json:"ratings"` // By rating pool
+	Wins          int            `json:"wins"`
+	Losses        int            `json:"losses"`
+	Draws         int            `json:"draws"`
+	CasualWins    int            `json:"casualWins"`
+	CasualLosses  int            `json:"casualLosses"`
+	CasualDraws   int            `json:"casualDraws"`
+	LongestStreak int            `json:"longestStreak"`
+	Region        string         `json:"region,omitempty"`
+	IsBot         bool           `json:"isBot,omitempty"`
+	Deleted       bool           `json:"deleted,omitempty"`
+	LastSeen      time.Time      `json:"lastSeen"`
+}
+
+// parseBulkExportQuery reads ?format=, ?since= and ?until= (RFC 3339), ?cursor= and ?limit=, which is capped at maxLimit
+func parseBulkExportQuery(r *http.Request, maxLimit int) (*bulkExportQuery, error) {
+	values := r.URL.Query()
+	query := &bulkExportQuery{format: values.Get("format"), cursor: values.Get("cursor"), limit: defaultBulkExportLimit}
+	if query.format == "" {
+		query.format = BULK_NDJSON
+	}
+	if query.format != BULK_NDJSON && query.format != BULK_CSV {
+		return nil, fmt.Errorf("format must be %s or %s", BULK_NDJSON, BULK_CSV)
+	}
+	if value := values.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return nil, fmt.Errorf("invalid limit")
+		}
+		query.limit = min(parsed, maxLimit)
+	}
+	for name, bound := range map[string]*time.Time{"since": &query.since, "until": &query.until} {
+		if value := values.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", name)
+			}
+			*bound = parsed
+		}
+	}
+	return query, nil
+}
+
+// inRange reports whether a time falls within the query's date range
+func (q *bulkExportQuery) inRange(t time.Time) bool {
+	return !t.Before(q.since) && (q.until.IsZero() || t.Before(q.until))
+}
+
+// handleAdminExportGames serves GET /admin/export/games: finished games in the order they were saved,
+// filtered by the time they ended and by ?variant=
+// The cursor is the place in that order to carry on from, which later games don't shift
+func (gs *GameServer) handleAdminExportGames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query, err := parseBulkExportQuery(r, maxBulkExportLimit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	start := 0
+	if query.cursor != "" {
+		if start, err = strconv.Atoi(query.cursor); err != nil || start < 0 {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+	variant := r.URL.Query().Get("variant")
+
+	records := gs.store.AllGames()
+	page := make([]*models.GameRecord, 0)
+	next := start
+	for ; next < len(records) && len(page) < query.limit; next++ {
+		record := records[next]
+		recordVariant := record.Variant
+		if recordVariant == "" {
+			recordVariant = models.VARIANT_STANDARD
+		}
+		if query.inRange(record.EndTime) && (variant == "" || recordVariant == variant) {
+			page = append(page, record)
+		}
+	}
+	if next < len(records) {
+		w.Header().Set(nextCursorHeader, strconv.Itoa(next))
+	}
+
+	streamBulkExport(w, query.format, gameCSVHeader, page, gameCSVRow)
+}
+
+// gameCSVRow lays out a game under gameCSVHeader, its moves as space-separated positions
+func gameCSVRow(record *models.GameRecord) []string {
+	moves := make([]string, len(record.Moves))
+	for i, position := range record.Moves {
+		moves[i] = strconv.Itoa(position)
+	}
+	return []string{record.GameID, record.Variant, strconv.Itoa(record.Size), strconv.FormatBool(record.Rated),
+		string(record.Winner), record.PlayerXID, record.PlayerXName, record.PlayerOID, record.PlayerOName,
+		record.PlayerDeltaID, record.PlayerDeltaName, record.FirstMover, record.StartTime.Format(time.RFC3339),
+		record.EndTime.Format(time.RFC3339), strings.Join(moves, " ")}
+}
+
+// handleAdminExportPlayers serves GET /admin/export/players: every player by ID, filtered by when they were last seen
+// The cursor is the last ID of the previous page
+// Only the players' keys and then the page's players are copied on the hub; sorting, filtering and encoding happen here
+func (gs *GameServer) handleAdminExportPlayers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query, err := parseBulkExportQuery(r, maxPlayerExportLimit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var keys []playerKey
+	gs.do(func() {
+		players := gs.players.Players()
+		keys = make([]playerKey, len(players))
+		for i, player := range players {
+			keys[i] = playerKey{id: player.ID, lastSeen: player.LastSeen}
+		}
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i].id < keys[j].id })
+
+	ids := make([]string, 0, query.limit)
+	more := false
+	for _, key := range keys {
+		if key.id <= query.cursor || !query.inRange(key.lastSeen) {
+			continue
+		}
+		if len(ids) == query.limit {
+			more = true
+			break
+		}
+		ids = append(ids, key.id)
+	}
+	if more {
+		w.Header().Set(nextCursorHeader, ids[len(ids)-1])
+	}
+
+	page := make([]*exportedPlayer, 0, len(ids))
+	gs.do(func() {
+		for _, id := range ids {
+			if player, exists := gs.players.Player(id); exists {
+				page = append(page, exportPlayer(player))
+			}
+		}
+	})
+
+	streamBulkExport(w, query.format, playerCSVHeader, page, playerCSVRow)
+}
+
+// exportPlayer copies a player for the bulk export; call it from the hub
+func exportPlayer(player *models.Player) *exportedPlayer {
+	return &exportedPlayer{
+		ID:            player.ID,
+		Name:          player.Name,
+		Ratings:       maps.Clone(player.Ratings),
+		Wins:          player.Wins,
+		Losses:        player.Losses,
+		Draws:         player.Draws,
+		CasualWins:    player.CasualWins,
+		CasualLosses:  player.CasualLosses,
+		CasualDraws:   player.CasualDraws,
+		LongestStreak: player.LongestStreak,
+		Region:        player.Region,
+		IsBot:         player.IsBot,
+		Deleted:       player.Deleted,
+		LastSeen:      player.LastSeen,
+	}
+}
+
+// playerCSVRow lays out a player under playerCSVHeader, their ratings as pool=rating pairs separated by semicolons
+func playerCSVRow(player *exportedPlayer) []string {
+	ratings := make([]string, 0, len(player.Ratings))
+	for pool, rating := range player.Ratings {
+		ratings = append(ratings, pool+"="+strconv.Itoa(rating))
+	}
+	sort.Strings(ratings)
+	return []string{player.ID, player.Name, strings.Join(ratings, ";"), strconv.Itoa(player.Wins),
+		strconv.Itoa(player.Losses), strconv.Itoa(player.Draws), strconv.Itoa(player.CasualWins),
+		strconv.Itoa(player.CasualLosses), strconv.Itoa(player.CasualDraws), strconv.Itoa(player.LongestStreak),
+		player.Region, strconv.FormatBool(player.IsBot), strconv.FormatBool(player.Deleted),
+		player.LastSeen.Format(time.RFC3339)}
+}
+
+// streamBulkExport writes a page of rows as NDJSON or CSV, flushing every bulkExportFlushRows rows
+func streamBulkExport[T any](w http.ResponseWriter, format string, header []string, rows []T, csvRow func(T) []string) {
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	if format == BULK_CSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(w)
+		writer.Write(header)
+		for i, row := range rows {
+			writer.Write(csvRow(row))
+			if (i+1)%bulkExportFlushRows == 0 {
+				writer.Flush()
+				flush()
+			}
+		}
+		writer.Flush()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	for i, row := range rows {
+		encoder.Encode(row)
+		if (i+1)%bulkExportFlushRows == 0 {
+			flush()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func bulkExport(t *testing.T, gs *GameServer, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	request.Header.Set("Authorization", "Bearer admin-secret")
+	recorder := httptest.NewRecorder()
+	gs.AdminHandler().ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("%s = %d %s", path, recorder.Code, recorder.Body)
+	}
+	return recorder
+}
+
+func ndjsonGameIDs(t *testing.T, body string) []string {
+	t.Helper()
+	var ids []string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var record models.GameRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, record.GameID)
+	}
+	return ids
+}
+
+func TestBulkExportGames(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdminToken = "admin-secret"
+	gs, _, _ := newTestServer(t, cfg)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, variant := range []string{models.VARIANT_STANDARD, models.VARIANT_MISERE, models.VARIANT_STANDARD, models.VARIANT_STANDARD} {
+		gs.store.SaveGame(&models.GameRecord{GameID: "g" + string(rune('1'+i)), Variant: variant, Size: 3,
+			Winner: models.RESULT_DRAW, Moves: []int{4, 0}, EndTime: day.AddDate(0, 0, i)})
+	}
+
+	first := bulkExport(t, gs, "/admin/export/games?variant=standard&limit=2")
+	if ids := ndjsonGameIDs(t, first.Body.String()); strings.Join(ids, ",") != "g1,g3" {
+		t.Errorf("first page = %v, want g1,g3", ids)
+	}
+	cursor := first.Header().Get(nextCursorHeader)
+	if cursor == "" {
+		t.Fatal("first page has no cursor")
+	}
+	second := bulkExport(t, gs, "/admin/export/games?variant=standard&limit=2&cursor="+cursor)
+	if ids := ndjsonGameIDs(t, second.Body.String()); strings.Join(ids, ",") != "g4" || second.Header().Get(nextCursorHeader) != "" {
+		t.Errorf("second page = %v with cursor %q, want g4 and no cursor", ids, second.Header().Get(nextCursorHeader))
+	}
+
+	ranged := bulkExport(t, gs, "/admin/export/games?format=csv&since=2024-03-02T00:00:00Z&until=2024-03-04T00:00:00Z")
+	rows, err := csv.NewReader(ranged.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "gameId" || rows[1][0] != "g2" || rows[2][0] != "g3" || rows[2][14] != "4 0" {
+		t.Errorf("csv rows = %v, want the header, g2 and g3", rows)
+	}
+}
+
+func TestBulkExportPlayers(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdminToken = "admin-secret"
+	gs, _, _ := newTestServer(t, cfg)
+	for _, name := range []string{"alice", "bob", "carol"} {
+		addSeasonPlayer(gs, name, 1200, 0)
+	}
+
+	var seen []string
+	path := "/admin/export/players?format=csv&limit=2"
+	for page := 0; path != ""; page++ {
+		recorder := bulkExport(t, gs, path)
+		rows, err := csv.NewReader(recorder.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range rows[1:] {
+			seen = append(seen, row[1])
+			if row[2] != models.RATING_POOL_STANDARD+"=1200" {
+				t.Errorf("ratings = %q", row[2])
+			}
+		}
+		path = ""
+		if cursor := recorder.Header().Get(nextCursorHeader); cursor != "" {
+			path = "/admin/export/players?format=csv&limit=2&cursor=" + cursor
+		}
+		if page > 2 {
+			t.Fatal("export never ended")
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("exported %v, want every player once", seen)
+	}
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// retryAfterSeconds is suggested to clients refused because the server is full
+const retryAfterSeconds = 5
+
+// capacityStats counts work refused or deferred because a limit was reached
+type capacityStats struct {
+	RejectedConnections int `json:"rejectedConnections"` // Upgrades or streams refused at the connection cap
+	RejectedQueueJoins  int `json:"rejectedQueueJoins"`  // join_queue requests refused at the queue cap
+	DeferredMatches     int `json:"deferredMatches"`     // Pairings postponed at the active game cap
+
+	ThrottledConnections int `json:"throttledConnections"` // Upgrades or streams refused by the per-IP limits
+}
+
+// deliveryStats counts messages that never reached clients too slow to drain their send queues
+type deliveryStats struct {
+	DroppedMessages map[string]int `json:"droppedMessages"` // Droppable messages discarded from full send queues, by type
+	StalledClients  int            `json:"stalledClients"`  // Connections closed because a critical message didn't fit
+}
+
+// utilization is a snapshot of load against the configured limits; a zero limit means unlimited
+type utilization struct {
+	Connections    int            `json:"connections"`
+	MaxConnections int            `json:"maxConnections"`
+	ActiveGames    int            `json:"activeGames"`
+	MaxActiveGames int            `json:"maxActiveGames"`
+	Queued         map[string]int `json:"queued"`
+	MaxQueueLength int            `json:"maxQueueLength"`
+	capacityStats
+	deliveryStats
+
+	Compression compressionReport `json:"compression"` // WebSocket permessage-deflate savings
+}
+
+// Outcomes of screening a new connection
+const (
+	ADMIT_OK            = iota
+	ADMIT_SHUTTING_DOWN // The server is refusing new connections
+	ADMIT_BANNED        // The address or player is banned
+	ADMIT_FULL          // The connection cap is reached
+	ADMIT_THROTTLED     // The address is over its per-IP limits or temporarily banned
+	ADMIT_MAINTENANCE   // A maintenance window is in progress
+)
+
+// screenConnection decides whether a new connection may open, checking shutdown, maintenance, bans, per-IP limits,
+// then capacity
+// Throttled connections are also told how long to wait before retrying
+func (gs *GameServer) screenConnection(clientIP, playerID, token string) (int, time.Duration) {
+	if gs.shuttingDown || (gs.draining && !gs.resumingGame(playerID, token)) {
+		return ADMIT_SHUTTING_DOWN, 0
+	}
+	if gs.maintenanceStatus() == models.MAINTENANCE_ACTIVE && !gs.resumingGame(playerID, token) {
+		return ADMIT_MAINTENANCE, 0
+	}
+	if gs.isBanned(clientIP, playerID) {
+		return ADMIT_BANNED, 0
+	}
+	if wait := gs.throttleConnection(clientIP, playerID, token); wait > 0 {
+		return ADMIT_THROTTLED, wait
+	}
+	if !gs.admitConnection(playerID, token) {
+		return ADMIT_FULL, retryAfterSeconds * time.Second
+	}
+	return ADMIT_OK, 0
+}
+
+// admitConnection reports whether a new connection fits under the connection cap
+// Players resuming a game in progress are always let back in so they don't forfeit
+func (gs *GameServer) admitConnection(playerID, token string) bool {
+	limit := gs.config.MaxConnections
+	if limit == 0 || len(gs.clients) < limit {
+		return true
+	}
+	if gs.resumingGame(playerID, token) {
+		return true
+	}
+
+	gs.capacity.RejectedConnections++
+	log.Printf("Refusing connection: %d/%d connections open", len(gs.clients), limit)
+	return false
+}
+
+// resumingGame reports whether a connection reclaims a player with a game in progress
+func (gs *GameServer) resumingGame(playerID, token string) bool {
+	player := gs.sessionPlayer(playerID, token)
+	return player != nil && gs.activeGameForPlayer(player.ID) != nil
+}
+
+// queueFull reports whether the queue for a mode is at its cap
+func (gs *GameServer) queueFull(mode string) bool {
+	return gs.config.MaxQueueLength > 0 && len(gs.matchmaking.Queue(mode)) >= gs.config.MaxQueueLength
+}
+
+// atGameLimit reports whether another game may not be started
+func (gs *GameServer) atGameLimit() bool {
+	// A proposed match holds a slot until its players answer
+	return gs.config.MaxActiveGames > 0 && gs.activeGameCount()+len(gs.matchProposals) >= gs.config.MaxActiveGames
+}
+
+// activeGameCount counts games being played or paused
+func (gs *GameServer) activeGameCount() int {
+	active := 0
+	for _, gameInstance := range gs.games.Games() {
+		if inProgress(gameInstance) {
+			active++
+		}
+	}
+	return active
+}
+
+// sendServerFull tells a player a limit was reached; legacy clients get a plain error instead
+func (gs *GameServer) sendServerFull(playerID, limit, message string) {
+	for conn := range gs.playerConns[playerID] {
+		if models.SupportsMessage(gs.clientVersion(conn), models.MSG_SERVER_FULL) {
+			gs.sendToClient(conn, models.NewGameMessage(models.MSG_SERVER_FULL, map[string]interface{}{
+				"limit":      limit,
+				"message":    message,
+				"retryAfter": retryAfterSeconds,
+			}))
+		} else {
+			gs.sendClientError(conn, models.ERR_SERVER_FULL, message)
+		}
+	}
+}
+
+// refuseFull rejects an HTTP upgrade because the server is at capacity
+func refuseFull(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+	http.Error(w, "server full", http.StatusServiceUnavailable)
+}
+
+// utilization snapshots current load and limits
+func (gs *GameServer) utilization() utilization {
+	modes := gs.matchmaking.Modes()
+	queued := make(map[string]int, len(modes))
+	for _, mode := range modes {
+		queued[mode] = len(gs.matchmaking.Queue(mode))
+	}
+	return utilization{
+		Connections:    len(gs.clients),
+		MaxConnections: gs.config.MaxConnections,
+		ActiveGames:    gs.activeGameCount(),
+		MaxActiveGames: gs.config.MaxActiveGames,
+		Queued:         queued,
+		MaxQueueLength: gs.config.MaxQueueLength,
+		capacityStats:  gs.capacity,
+		deliveryStats: deliveryStats{
+			DroppedMessages: maps.Clone(gs.delivery.DroppedMessages),
+			StalledClients:  gs.delivery.StalledClients,
+		},
+		Compression: gs.compressionReport(),
+	}
+}
+
+// HandleMetrics serves GET /metrics in the Prometheus text format
+func (gs *GameServer) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	var report capacityReport
+	gs.do(func() { report = gs.capacityReport() })
+	u := report.utilization
+
+	var b strings.Builder
+	metric := func(name, kind, help string, samples ...string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, sample := range samples {
+			fmt.Fprintf(&b, "%s%s\n", name, sample)
+		}
+	}
+	value := func(v int) string { return " " + strconv.Itoa(v) }
+
+	metric("ttt_connections", "gauge", "Open client connections.", value(u.Connections))
+	metric("ttt_connections_limit", "gauge", "Maximum open connections, 0 if unlimited.", value(u.MaxConnections))
+	metric("ttt_active_games", "gauge", "Games being played or paused.", value(u.ActiveGames))
+	metric("ttt_active_games_limit", "gauge", "Maximum active games, 0 if unlimited.", value(u.MaxActiveGames))
+
+	modes := make([]string, 0, len(u.Queued))
+	for mode := range u.Queued {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	queued := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		queued = append(queued, fmt.Sprintf("{mode=%q}%s", mode, value(u.Queued[mode])))
+	}
+	metric("ttt_queue_length", "gauge", "Players waiting in each matchmaking queue.", queued...)
+	metric("ttt_queue_length_limit", "gauge", "Maximum players per queue, 0 if unlimited.", value(u.MaxQueueLength))
+
+	metric("ttt_rejected_total", "counter", "Requests refused because a limit was reached.",
+		`{limit="connections"}`+value(u.RejectedConnections),
+		`{limit="queue"}`+value(u.RejectedQueueJoins),
+		`{limit="ip"}`+value(u.ThrottledConnections))
+	metric("ttt_deferred_matches_total", "counter", "Pairings postponed because the active game limit was reached.",
+		value(u.DeferredMatches))
+
+	types := make([]string, 0, len(u.DroppedMessages))
+	for msgType := range u.DroppedMessages {
+		types = append(types, msgType)
+	}
+	sort.Strings(types)
+	dropped := make([]string, 0, len(types))
+	for _, msgType := range types {
+		dropped = append(dropped, fmt.Sprintf("{type=%q}%s", msgType, value(u.DroppedMessages[msgType])))
+	}
+	metric("ttt_dropped_messages_total", "counter", "Droppable messages discarded because a client's send queue was full.",
+		dropped...)
+	metric("ttt_stalled_clients_total", "counter", "Connections closed because a game-critical message didn't fit their send queue.",
+		value(u.StalledClients))
+
+	compression := u.Compression
+	count := func(v int64) string { return " " + strconv.FormatInt(v, 10) }
+	metric("ttt_ws_compressed_connections_total", "counter", "WebSocket connections that negotiated permessage-deflate.",
+		count(compression.Connections))
+	metric("ttt_ws_frames_total", "counter", "Data frames sent on those connections, by whether they were compressed.",
+		`{compressed="true"}`+count(compression.CompressedFrames),
+		`{compressed="false"}`+count(compression.UncompressedFrames))
+	metric("ttt_ws_payload_bytes_total", "counter", "Bytes of the data frames sent on those connections, before compression.",
+		count(compression.PayloadBytes))
+	metric("ttt_ws_wire_bytes_total", "counter", "Bytes those connections wrote to the network, frame headers and control frames included.",
+		count(compression.WireBytes))
+	metric("ttt_ws_compression_saved_percent", "gauge", "Payload bytes permessage-deflate saved, in percent.",
+		value(compression.SavedPercent))
+
+	metric("ttt_load_percent", "gauge", "Use of the busiest configured limit, in percent.", value(report.LoadPercent))
+	recommendations := make([]string, 0, 3)
+	for _, recommendation := range []string{SCALE_UP, SCALE_STEADY, SCALE_DOWN} {
+		recommended := 0
+		if recommendation == report.Recommendation {
+			recommended = 1
+		}
+		recommendations = append(recommendations, fmt.Sprintf("{recommendation=%q}%s", recommendation, value(recommended)))
+	}
+	metric("ttt_scale_recommendation", "gauge", "1 for the current scaling recommendation, 0 for the others.", recommendations...)
+	draining, drained := 0, 0
+	if report.Draining {
+		draining = 1
+	}
+	if report.Drained {
+		drained = 1
+	}
+	metric("ttt_draining", "gauge", "1 while the instance is draining.", value(draining))
+	metric("ttt_drained", "gauge", "1 once a draining instance has no games left.", value(drained))
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write([]byte(b.String()))
+}
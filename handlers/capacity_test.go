@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// testServerFull is the body of a server_full message
+type testServerFull struct {
+	Limit      string `json:"limit"`
+	RetryAfter int    `json:"retryAfter"`
+}
+
+func TestConnectionLimitRefusesUpgrade(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxConnections = 1
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	dialTestClient(t, wsURL, "name=alice")
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=bob", nil)
+	if err == nil {
+		t.Fatal("connection over the limit was accepted")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("response = %+v, want 503 with Retry-After", resp)
+	}
+	var u utilization
+	gs.do(func() { u = gs.utilization() })
+	if u.Connections != 1 || u.RejectedConnections != 1 {
+		t.Errorf("utilization = %+v", u)
+	}
+}
+
+func TestQueueLimitSendsServerFull(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxQueueLength = 1
+	_, _, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+
+	// The rated queue is separate and still has room
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+	bob.expect(models.MSG_QUEUE_JOINED, nil)
+
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	var full testServerFull
+	bob.expect(models.MSG_SERVER_FULL, &full)
+	if full.Limit != models.FULL_QUEUE || full.RetryAfter <= 0 {
+		t.Errorf("server_full = %+v", full)
+	}
+}
+
+func TestGameLimitDefersMatchUntilGameEnds(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxActiveGames = 1
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	players := make([]*testClient, 4)
+	for i, name := range []string{"a", "b", "c", "d"} {
+		players[i] = dialTestClient(t, wsURL, "name="+name)
+	}
+
+	players[0].send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	players[0].expect(models.MSG_QUEUE_JOINED, nil)
+	players[1].send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	var first testGameState
+	players[0].expect(models.MSG_GAME_FOUND, &first)
+	players[1].expect(models.MSG_GAME_FOUND, nil)
+
+	players[2].send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	players[2].expect(models.MSG_QUEUE_JOINED, nil)
+	players[3].send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	var full testServerFull
+	players[3].expect(models.MSG_SERVER_FULL, &full)
+	if full.Limit != models.FULL_GAMES {
+		t.Errorf("server_full limit = %q, want %q", full.Limit, models.FULL_GAMES)
+	}
+	var u utilization
+	gs.do(func() { u = gs.utilization() })
+	if u.ActiveGames != 1 || u.Queued[models.MODE_CASUAL] != 2 || u.DeferredMatches == 0 {
+		t.Fatalf("utilization while full = %+v", u)
+	}
+
+	// Finishing the first game frees the slot for the waiting pair
+	x, o := players[0], players[1]
+	if first.MySymbol == "O" {
+		x, o = o, x
+	}
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		pos := position
+		mover.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: first.GameID, Position: &pos})
+		x.expect(models.MSG_GAME_UPDATE, nil)
+		o.expect(models.MSG_GAME_UPDATE, nil)
+	}
+
+	var second testGameState
+	players[2].expect(models.MSG_GAME_FOUND, &second)
+	if second.GameID == first.GameID {
+		t.Error("waiting players were not given a new game")
+	}
+}
+
+func TestMetricsReportUtilization(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxConnections = 10
+	gs, _, wsURL := newTestServer(t, cfg)
+	dialTestClient(t, wsURL, "name=alice")
+
+	recorder := httptest.NewRecorder()
+	gs.HandleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	for _, want := range []string{
+		"ttt_connections 1\n",
+		"ttt_connections_limit 10\n",
+		`ttt_queue_length{mode="casual"} 0` + "\n",
+		"# TYPE ttt_rejected_total counter\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	gs.HandleHealth(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"maxConnections":10`) {
+		t.Errorf("health = %d %s", recorder.Code, recorder.Body.String())
+	}
+}
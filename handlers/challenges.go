@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+
+	"github.com/google/uuid"
+)
+
+// challenge is a challenge whose settings are being negotiated
+type challenge struct {
+	models.Challenge
+	expiry clock.Timer
+}
+
+// handleChallenge offers a game to another player, or to the host of a lobby, with proposed settings
+// A player has at most one challenge out; a new one replaces it
+func (gs *GameServer) handleChallenge(conn clientConn, player *models.Player, request *models.ChallengePayload) {
+	opponentID := request.OpponentID
+	var settings models.GameSettings
+	if request.LobbyID != "" {
+		l, exists := gs.lobbies[request.LobbyID]
+		if !exists {
+			gs.sendClientError(conn, models.ERR_LOBBY_NOT_FOUND, "Lobby not found or no longer open")
+			return
+		}
+		opponentID = l.HostID
+		settings.LobbyRules = l.Rules
+	} else {
+		settings.Validate() // Fills in the defaults
+	}
+	if request.Settings != nil {
+		settings = *request.Settings
+	}
+
+	if opponentID == player.ID {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot challenge yourself")
+		return
+	}
+	opponent, exists := gs.players.Player(opponentID)
+	if !exists || !gs.isConnected(opponentID) {
+		gs.sendClientError(conn, models.ERR_PLAYER_OFFLINE, "That player is not online")
+		return
+	}
+	if gs.eitherBlocked(player.ID, opponentID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot challenge this player")
+		return
+	}
+	if !gs.understandsChallenges(opponentID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "That player's client does not support challenges")
+		return
+	}
+	if !gs.validFirstPlayer(conn, &settings, player.ID, opponentID) || !gs.mayStartGame(conn, player, &settings.LobbyRules) {
+		return
+	}
+
+	for _, existing := range gs.challenges {
+		if existing.ChallengerID == player.ID {
+			gs.closeChallenge(existing, models.CHALLENGE_REPLACED, "")
+		}
+	}
+	c := &challenge{Challenge: models.Challenge{
+		ID:             uuid.New().String(),
+		ChallengerID:   player.ID,
+		ChallengerName: player.Name,
+		OpponentID:     opponent.ID,
+		OpponentName:   opponent.Name,
+		LobbyID:        request.LobbyID,
+	}}
+	gs.challenges[c.ID] = c
+
+	log.Printf("Player %s (%s) challenged %s (%s)", player.Name, player.ID, opponent.Name, opponent.ID)
+	gs.proposeSettings(c, player.ID, settings)
+}
+
+// handleProposeSettings answers a challenge's latest proposal with different settings
+func (gs *GameServer) handleProposeSettings(conn clientConn, player *models.Player, request *models.ProposeSettingsPayload) {
+	c := gs.challengeFor(conn, player, request.ChallengeID)
+	if c == nil {
+		return
+	}
+	if !gs.validFirstPlayer(conn, &request.Settings, c.ChallengerID, c.OpponentID) {
+		return
+	}
+	gs.proposeSettings(c, player.ID, request.Settings)
+}
+
+// handleAcceptSettings agrees to a challenge's latest proposal and starts the game
+func (gs *GameServer) handleAcceptSettings(conn clientConn, player *models.Player, request *models.ChallengeIDPayload) {
+	c := gs.challengeFor(conn, player, request.ChallengeID)
+	if c == nil {
+		return
+	}
+	if c.ProposedBy == player.ID {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Your proposal is waiting for the other player to accept it")
+		return
+	}
+	if !gs.mayStartGame(conn, player, &c.Settings.LobbyRules) {
+		return
+	}
+	other, exists := gs.players.Player(c.ProposedBy)
+	if !exists || gs.activeGameForPlayer(other.ID) != nil {
+		gs.sendClientError(conn, models.ERR_ALREADY_IN_GAME, "The other player is already in a game")
+		return
+	}
+	if c.Settings.TimeControl == models.TIME_CORRESPONDENCE && gs.correspondenceLimitReached(other.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "The other player has too many correspondence games going")
+		return
+	}
+	if gs.atGameLimit() {
+		gs.sendServerFull(player.ID, models.FULL_GAMES, "The server is at its game limit, accept again shortly")
+		return
+	}
+
+	for _, seated := range []*models.Player{other, player} {
+		gs.removeFromQueue(seated.ID)
+		gs.withdrawFromMatch(seated.ID)
+	}
+	gs.recordPairing(other.ID, player.ID)
+	newGame := gs.startCustomGame(c.Settings, other, player)
+	gs.closeChallenge(c, models.CHALLENGE_ACCEPTED, newGame.ID)
+	if l, exists := gs.lobbies[c.LobbyID]; exists {
+		gs.closeLobby(l, models.LOBBY_STARTED, newGame.ID)
+	}
+}
+
+// handleDeclineChallenge ends a challenge at either player's request
+func (gs *GameServer) handleDeclineChallenge(conn clientConn, player *models.Player, request *models.ChallengeIDPayload) {
+	if c := gs.challengeFor(conn, player, request.ChallengeID); c != nil {
+		gs.closeChallenge(c, models.CHALLENGE_DECLINED, "")
+	}
+}
+
+// understandsChallenges reports whether any of a player's connections can be sent challenge messages
+func (gs *GameServer) understandsChallenges(playerID string) bool {
+	for conn := range gs.playerConns[playerID] {
+		if models.SupportsMessage(gs.clientVersion(conn), models.MSG_CHALLENGE_PROPOSED) {
+			return true
+		}
+	}
+	return false
+}
+
+// challengeFor looks up a challenge the player is part of, telling them when there is none
+func (gs *GameServer) challengeFor(conn clientConn, player *models.Player, challengeID string) *challenge {
+	c, exists := gs.challenges[challengeID]
+	if !exists || (c.ChallengerID != player.ID && c.OpponentID != player.ID) {
+		gs.sendClientError(conn, models.ERR_CHALLENGE_NOT_FOUND, "You have no open challenge with that ID")
+		return nil
+	}
+	return c
+}
+
+// validFirstPlayer checks that proposed settings leave the first move to one of the two players, or to chance
+func (gs *GameServer) validFirstPlayer(conn clientConn, settings *models.GameSettings, playerIDs ...string) bool {
+	if settings.FirstPlayerID == "" {
+		return true
+	}
+	for _, id := range playerIDs {
+		if settings.FirstPlayerID == id {
+			return true
+		}
+	}
+	gs.sendClientError(conn, models.ERR_INVALID_PAYLOAD, "firstPlayerId must be one of the two players")
+	return false
+}
+
+// proposeSettings makes settings a challenge's latest proposal, giving the other player ChallengeTTL to answer
+func (gs *GameServer) proposeSettings(c *challenge, proposerID string, settings models.GameSettings) {
+	if c.expiry != nil {
+		c.expiry.Stop()
+	}
+	c.Settings = settings
+	c.ProposedBy = proposerID
+	expiresAt := gs.clock.Now().Add(gs.config.ChallengeTTL)
+	c.ExpiresAt = expiresAt
+	c.expiry = gs.clock.AfterFunc(gs.config.ChallengeTTL, gs.doLater(func() {
+		if gs.challenges[c.ID] == c && c.ExpiresAt.Equal(expiresAt) {
+			gs.closeChallenge(c, models.CHALLENGE_EXPIRED, "")
+		}
+	}))
+
+	proposed := models.NewGameMessage(models.MSG_CHALLENGE_PROPOSED, c.Challenge)
+	for _, id := range []string{c.ChallengerID, c.OpponentID} {
+		gs.sendToPlayer(id, proposed)
+	}
+}
+
+// closeHeldChallenges closes every challenge a player is part of
+func (gs *GameServer) closeHeldChallenges(playerID, reason string) {
+	for _, c := range gs.challenges {
+		if c.ChallengerID == playerID || c.OpponentID == playerID {
+			gs.closeChallenge(c, reason, "")
+		}
+	}
+}
+
+// closeChallenge ends a challenge and tells both players why
+func (gs *GameServer) closeChallenge(c *challenge, reason, gameID string) {
+	c.expiry.Stop()
+	delete(gs.challenges, c.ID)
+	log.Printf("Challenge %s closed: %s", c.ID, reason)
+
+	closed := models.NewGameMessage(models.MSG_CHALLENGE_CLOSED, models.ChallengeClosed{
+		ChallengeID: c.ID,
+		Reason:      reason,
+		GameID:      gameID,
+	})
+	for _, id := range []string{c.ChallengerID, c.OpponentID} {
+		if gs.isConnected(id) {
+			gs.sendToPlayer(id, closed)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestChallengeSettingsAreNegotiated(t *testing.T) {
+	cfg := testConfig()
+	cfg.ChallengeTTL = time.Minute
+	_, _, wsURL := newTestServer(t, cfg)
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	alice.send(models.MSG_CHALLENGE, models.ChallengePayload{OpponentID: bob.playerID})
+	var proposed models.Challenge
+	bob.expect(models.MSG_CHALLENGE_PROPOSED, &proposed)
+	alice.expect(models.MSG_CHALLENGE_PROPOSED, nil)
+	if proposed.ChallengerName != "alice" || proposed.ProposedBy != alice.playerID || proposed.Settings.Size != 3 {
+		t.Fatalf("challenge = %+v, want alice's default proposal", proposed)
+	}
+
+	// Bob counters with a blitz game that he moves first in, and can't accept his own proposal
+	counter := models.GameSettings{
+		LobbyRules:    models.LobbyRules{TimeControl: models.TIME_BLITZ, Seconds: 60},
+		FirstPlayerID: bob.playerID,
+	}
+	bob.send(models.MSG_PROPOSE_SETTINGS, models.ProposeSettingsPayload{ChallengeID: proposed.ID, Settings: counter})
+	alice.expect(models.MSG_CHALLENGE_PROPOSED, &proposed)
+	bob.expect(models.MSG_CHALLENGE_PROPOSED, nil)
+	if proposed.ProposedBy != bob.playerID || proposed.Settings.TimeControl != models.TIME_BLITZ {
+		t.Fatalf("counter-proposal = %+v", proposed)
+	}
+	bob.send(models.MSG_ACCEPT_SETTINGS, models.ChallengeIDPayload{ChallengeID: proposed.ID})
+	var refused models.ErrorPayload
+	bob.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_NOT_ALLOWED {
+		t.Fatalf("accepting his own proposal = %+v", refused)
+	}
+
+	alice.send(models.MSG_ACCEPT_SETTINGS, models.ChallengeIDPayload{ChallengeID: proposed.ID})
+	var found map[string]interface{}
+	bob.expect(models.MSG_GAME_FOUND, &found)
+	settings, _ := found["settings"].(map[string]interface{})
+	if found["mySymbol"] != "X" || found["timeLeftMs"] == nil || settings["timeControl"] != models.TIME_BLITZ || settings["firstPlayerId"] != bob.playerID {
+		t.Fatalf("game_found = %+v, want bob moving first in the agreed blitz game", found)
+	}
+	alice.expect(models.MSG_GAME_FOUND, nil)
+	var closed models.ChallengeClosed
+	alice.expect(models.MSG_CHALLENGE_CLOSED, &closed)
+	if closed.Reason != models.CHALLENGE_ACCEPTED || closed.GameID != found["gameId"] {
+		t.Fatalf("challenge_closed = %+v, want it accepted as %v", closed, found["gameId"])
+	}
+}
+
+func TestUnansweredChallengeExpires(t *testing.T) {
+	cfg := testConfig()
+	cfg.ChallengeTTL = time.Minute
+	gs, clk, wsURL := newTestServer(t, cfg)
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	alice.send(models.MSG_CHALLENGE, models.ChallengePayload{OpponentID: bob.playerID})
+	var proposed models.Challenge
+	bob.expect(models.MSG_CHALLENGE_PROPOSED, &proposed)
+	alice.expect(models.MSG_CHALLENGE_PROPOSED, nil)
+
+	gs.do(func() {})
+	clk.Advance(cfg.ChallengeTTL)
+	var closed models.ChallengeClosed
+	bob.expect(models.MSG_CHALLENGE_CLOSED, &closed)
+	if closed.Reason != models.CHALLENGE_EXPIRED {
+		t.Fatalf("challenge_closed = %+v, want it expired", closed)
+	}
+
+	bob.send(models.MSG_ACCEPT_SETTINGS, models.ChallengeIDPayload{ChallengeID: proposed.ID})
+	var refused models.ErrorPayload
+	bob.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_CHALLENGE_NOT_FOUND {
+		t.Fatalf("accepting an expired challenge = %+v", refused)
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxMessageSize       = 8 * 1024 // Largest inbound message accepted, in bytes
+	maxMalformedMessages = 5        // Consecutive undecodable messages before the connection is closed
+	closeWriteTimeout    = time.Second
+)
+
+// closeClient tells the client why it is being disconnected, then closes the connection with that code
+func (gs *GameServer) closeClient(conn clientConn, code int) {
+	reason := models.CloseReasonFor(code)
+
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_DISCONNECT, reason))
+	conn.CloseWithReason(code, reason.Reason)
+}
+
+// awaitClose reads until the client answers a queued close frame, so the frames
+// before it are written rather than dropped when the handler closes the socket
+func awaitClose(conn *websocket.Conn) {
+	conn.SetReadDeadline(time.Now().Add(closeWriteTimeout))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// extendReadDeadline pushes back the idle timeout after activity on the connection
+func (gs *GameServer) extendReadDeadline(conn *websocket.Conn) {
+	if gs.config.IdleTimeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(gs.config.IdleTimeout))
+	}
+}
+
+// handleReadError logs why the read loop ended and closes idle connections with a reason
+func (gs *GameServer) handleReadError(conn clientConn, err error) {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		log.Printf("Closing idle connection: %v", err)
+		gs.closeClient(conn, models.CLOSE_IDLE_TIMEOUT)
+	case errors.Is(err, errInflatedMessage):
+		log.Printf("Closing connection after an oversized message: %v", err)
+		conn.CloseWithReason(websocket.CloseMessageTooBig, "message too big")
+	case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+		log.Printf("WebSocket closed by client: %v", err)
+	default:
+		log.Printf("WebSocket read error: %v", err)
+	}
+}
+
+// Shutdown refuses new connections, drops the games still being played unless they are saved to resume,
+// saves the queues and those games to StateFile if set, closes every open connection
+// with a "going away" reason and flushes queued webhook, push and Discord posts
+func (gs *GameServer) Shutdown() {
+	gs.do(func() {
+		gs.shuttingDown = true
+		gs.suspendGamesForShutdown()
+		gs.dropGamesForShutdown()
+		// Saved before closing connections, which takes everyone out of the queues
+		gs.saveState()
+		if gs.seasonTimer != nil {
+			gs.seasonTimer.Stop()
+		}
+		if gs.statsTimer != nil {
+			gs.statsTimer.Stop()
+		}
+		if gs.heatmapTimer != nil {
+			gs.heatmapTimer.Stop()
+		}
+		if gs.maintenanceTimer != nil {
+			gs.maintenanceTimer.Stop()
+		}
+
+		log.Printf("Closing %d connections for shutdown", len(gs.clients))
+		for conn := range gs.clients {
+			gs.closeClient(conn, websocket.CloseGoingAway)
+		}
+	})
+	gs.closeWebhooks()
+	gs.closePush()
+	gs.closeDiscord()
+}
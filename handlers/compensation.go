@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// dropGame aborts a game the server can no longer run and compensates its players
+func (gs *GameServer) dropGame(gameInstance *models.Game, reason string) {
+	if err := gs.cancelGame(gameInstance); err != nil {
+		return
+	}
+
+	log.Printf("Dropped game %s: %s", gameInstance.ID, reason)
+	gs.logEvent(gameInstance.ID, models.EVENT_ABORTED, "", map[string]interface{}{"reason": reason})
+	gs.sendGameUpdate(gameInstance)
+
+	for _, player := range gameInstance.AllPlayers() {
+		if player.IsBot {
+			continue
+		}
+		gs.store.AddCompensation(&models.Compensation{
+			PlayerID:  player.ID,
+			GameID:    gameInstance.ID,
+			Reason:    reason,
+			CreatedAt: gs.clock.Now(),
+		})
+	}
+}
+
+// dropGamesForShutdown drops every game still being played, except those saved to resume after the restart
+func (gs *GameServer) dropGamesForShutdown() {
+	for _, gameInstance := range gs.games.Games() {
+		if gs.resumesAfterRestart(gameInstance) {
+			continue
+		}
+		gs.dropGame(gameInstance, models.DROPPED_SHUTDOWN)
+	}
+}
+
+// dropGameAfterPanic drops the game of the player whose message crashed its handler, as it may be half updated
+func (gs *GameServer) dropGameAfterPanic(conn clientConn) {
+	player, exists := gs.clients[conn]
+	if !exists {
+		return
+	}
+	if gameInstance := gs.activeGameForPlayer(player.ID); gameInstance != nil {
+		gs.dropGame(gameInstance, models.DROPPED_SERVER_ERROR)
+	}
+}
+
+// compensated reports whether a player is owed compensation for a dropped game
+func (gs *GameServer) compensated(playerID string) bool {
+	_, owed := gs.store.Compensation(playerID)
+	return owed
+}
+
+// queueSlot is where a player joins a mode's queue: at the back, or ahead of everyone who isn't
+// compensated if the server dropped their last game
+func (gs *GameServer) queueSlot(mode, playerID string) int {
+	queue := gs.matchmaking.Queue(mode)
+	if !gs.compensated(playerID) {
+		return len(queue)
+	}
+	slot := 0
+	for slot < len(queue) && gs.compensated(queue[slot]) {
+		slot++
+	}
+	return slot
+}
+
+// claimCompensation spends the compensation owed to a new game's players, protecting their ratings in it
+func (gs *GameServer) claimCompensation(gameInstance *models.Game) {
+	for _, player := range gameInstance.AllPlayers() {
+		if compensation, owed := gs.store.ClaimCompensation(player.ID); owed {
+			gameInstance.RatingProtected = append(gameInstance.RatingProtected, player.ID)
+			log.Printf("Player %s plays game %s rating-protected for dropped game %s", player.ID, gameInstance.ID, compensation.GameID)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/game"
+	"tictactoe-server/models"
+)
+
+// brokenRules crashes on every move, standing in for a handler bug
+type brokenRules struct {
+	game.RuleSet
+}
+
+func (brokenRules) ValidateMove(*models.Game, int) error { panic("corrupt game") }
+
+func TestDroppedGameEarnsPriorityAndRatingProtection(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startRatedGame(t, wsURL)
+
+	gs.do(func() {
+		gs.gameEngine.RegisterRuleSet("broken", brokenRules{})
+		testGame(gs, gameID).Variant = "broken"
+	})
+	corner := 0
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &corner})
+	var dropped testGameState
+	o.expect(models.MSG_GAME_UPDATE, &dropped)
+	if dropped.Status != models.STATUS_ABORTED {
+		t.Fatalf("status after the crash = %q, want aborted", dropped.Status)
+	}
+	for _, player := range []*testClient{x, o} {
+		if compensation, owed := gs.store.Compensation(player.playerID); !owed || compensation.Reason != models.DROPPED_SERVER_ERROR {
+			t.Fatalf("compensation for %s = %+v, %v", player.playerID, compensation, owed)
+		}
+	}
+
+	// X jumps the queue, and losing the next game costs them no rating
+	carol := dialTestClient(t, wsURL, "name=carol")
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+	carol.expect(models.MSG_QUEUE_JOINED, nil)
+	x.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+	var joined struct{ Position int }
+	x.expect(models.MSG_QUEUE_JOINED, &joined)
+	if joined.Position != 1 {
+		t.Errorf("compensated player joined at position %d, want 1", joined.Position)
+	}
+
+	var state testGameState
+	carol.expect(models.MSG_GAME_FOUND, &state)
+	var protected struct{ RatingProtected bool }
+	x.expect(models.MSG_GAME_FOUND, &protected)
+	if !protected.RatingProtected {
+		t.Error("game_found should tell the compensated player their rating is protected")
+	}
+	if _, owed := gs.store.Compensation(x.playerID); owed {
+		t.Error("compensation should be spent by the next game")
+	}
+
+	first, second := carol, x
+	if state.MySymbol == "O" {
+		first, second = x, carol
+	}
+	// First wins along the top row
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := first
+		if i%2 == 1 {
+			mover = second
+		}
+		playMove(t, mover, first, second, state.GameID, position)
+	}
+
+	var ratingX, ratingCarol int
+	gs.do(func() {
+		ratingX = testPlayer(gs, x.playerID).Rating(models.RATING_POOL_STANDARD)
+		ratingCarol = testPlayer(gs, carol.playerID).Rating(models.RATING_POOL_STANDARD)
+	})
+	if first == x && ratingX <= 1000 || second == x && ratingX != 1000 {
+		t.Errorf("protected player's rating = %d after the game", ratingX)
+	}
+	if first == carol && ratingCarol <= 1000 || second == carol && ratingCarol >= 1000 {
+		t.Errorf("opponent's rating = %d, want it to move as usual", ratingCarol)
+	}
+}
+
+func TestShutdownDropsGamesInProgress(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, _, _ := startGame(t, wsURL, models.MODE_CASUAL)
+
+	gs.Shutdown()
+	var dropped testGameState
+	x.expect(models.MSG_GAME_UPDATE, &dropped)
+	if dropped.Status != models.STATUS_ABORTED {
+		t.Errorf("status after shutdown = %q, want aborted", dropped.Status)
+	}
+	if compensation, owed := gs.store.Compensation(x.playerID); !owed || compensation.Reason != models.DROPPED_SHUTDOWN {
+		t.Errorf("compensation = %+v, %v", compensation, owed)
+	}
+}
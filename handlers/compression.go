@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+// errInflatedMessage reports an inbound message that decompressed past maxMessageSize
+var errInflatedMessage = errors.New("message inflates past the size limit")
+
+// compressionStats counts what WebSocket connections that negotiated permessage-deflate sent, before and after compression
+// The write pumps update it outside the hub
+type compressionStats struct {
+	connections        atomic.Int64 // Connections that negotiated compression
+	compressedFrames   atomic.Int64 // Data frames sent compressed
+	uncompressedFrames atomic.Int64 // Data frames under WSCompressionMinBytes, sent as they were
+	payloadBytes       atomic.Int64 // Data frame bytes before compression
+	wireBytes          atomic.Int64 // Bytes written to the network, frame headers and control frames included
+}
+
+// compressionReport is a snapshot of compressionStats with the savings worked out
+type compressionReport struct {
+	Enabled            bool  `json:"enabled"`
+	Connections        int64 `json:"connections"`
+	CompressedFrames   int64 `json:"compressedFrames"`
+	UncompressedFrames int64 `json:"uncompressedFrames"`
+	PayloadBytes       int64 `json:"payloadBytes"`
+	WireBytes          int64 `json:"wireBytes"`
+	SavedBytes         int64 `json:"savedBytes"`   // Negative while frame overhead outweighs what deflate saved
+	SavedPercent       int   `json:"savedPercent"` // SavedBytes as a percentage of PayloadBytes
+}
+
+// compressionReport snapshots the compression counters
+func (gs *GameServer) compressionReport() compressionReport {
+	stats := &gs.compression
+	report := compressionReport{
+		Enabled:            gs.config.WSCompression,
+		Connections:        stats.connections.Load(),
+		CompressedFrames:   stats.compressedFrames.Load(),
+		UncompressedFrames: stats.uncompressedFrames.Load(),
+		PayloadBytes:       stats.payloadBytes.Load(),
+		WireBytes:          stats.wireBytes.Load(),
+	}
+	report.SavedBytes = report.PayloadBytes - report.WireBytes
+	if report.PayloadBytes > 0 {
+		report.SavedPercent = int(report.SavedBytes * 100 / report.PayloadBytes)
+	}
+	return report
+}
+
+// wsCompression is the permessage-deflate setup of a connection that negotiated it
+type wsCompression struct {
+	minBytes int
+	wire     *countingConn
+	stats    *compressionStats
+}
+
+// prepare turns compression on for frames of at least minBytes, where deflate pays for itself
+func (c *wsCompression) prepare(conn *websocket.Conn, size int) {
+	conn.EnableWriteCompression(size >= c.minBytes)
+}
+
+// record counts a data frame of size bytes and what the socket was sent since the last one
+func (c *wsCompression) record(size int) {
+	if size >= c.minBytes {
+		c.stats.compressedFrames.Add(1)
+	} else {
+		c.stats.uncompressedFrames.Add(1)
+	}
+	c.stats.payloadBytes.Add(int64(size))
+	c.stats.wireBytes.Add(c.wire.written.Swap(0))
+}
+
+// countingConn counts the bytes written to a hijacked connection
+type countingConn struct {
+	net.Conn
+	written atomic.Int64
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// countingResponseWriter hands the upgrader a countingConn when it hijacks the connection
+type countingResponseWriter struct {
+	http.ResponseWriter
+	conn *countingConn
+}
+
+func (w *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not implement http.Hijacker")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	w.conn = &countingConn{Conn: conn}
+	return w.conn, rw, nil
+}
+
+// upgradeWebSocket upgrades the request, returning the connection's compression setup when it negotiated permessage-deflate
+func (gs *GameServer) upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*websocket.Conn, *wsCompression, error) {
+	if !gs.config.WSCompression || !offersDeflate(r.Header) {
+		wsConn, err := gs.upgrader.Upgrade(w, r, nil)
+		return wsConn, nil, err
+	}
+
+	counting := &countingResponseWriter{ResponseWriter: w}
+	wsConn, err := gs.upgrader.Upgrade(counting, r, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	wsConn.SetCompressionLevel(gs.config.WSCompressionLevel)
+	counting.conn.written.Store(0) // The handshake isn't a frame
+	gs.compression.connections.Add(1)
+	return wsConn, &wsCompression{minBytes: gs.config.WSCompressionMinBytes, wire: counting.conn, stats: &gs.compression}, nil
+}
+
+// offersDeflate reports whether a handshake offers permessage-deflate, which the upgrader then accepts
+func offersDeflate(header http.Header) bool {
+	for _, value := range header.Values("Sec-WebSocket-Extensions") {
+		for _, extension := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(extension, ";")
+			if strings.TrimSpace(name) == "permessage-deflate" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage reads a whole message, refusing one that inflates past maxMessageSize
+// The connection's read limit only bounds the compressed bytes on the wire
+func readMessage(conn *websocket.Conn) ([]byte, error) {
+	_, reader, err := conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMessageSize {
+		return nil, errInflatedMessage
+	}
+	return data, nil
+}
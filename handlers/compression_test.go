@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialCompressed connects offering permessage-deflate and returns the client and whether the server accepted it
+func dialCompressed(t *testing.T, wsURL string) (*testClient, bool) {
+	t.Helper()
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, response, err := dialer.Dial(wsURL+"?v=2&name=alice", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	negotiated := strings.Contains(response.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+	return &testClient{t: t, conn: conn}, negotiated
+}
+
+func TestCompressionSavesBandwidth(t *testing.T) {
+	cfg := testConfig()
+	cfg.WSCompression = true
+	cfg.WSCompressionLevel = 1
+	cfg.WSCompressionMinBytes = 512
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	client, negotiated := dialCompressed(t, wsURL)
+	if !negotiated {
+		t.Fatal("server did not accept permessage-deflate")
+	}
+	client.expect(models.MSG_SESSION, nil)
+	gs.do(func() {
+		for conn := range gs.clients {
+			gs.sendClientError(conn, models.ERR_MALFORMED_MESSAGE, strings.Repeat("board ", 1000))
+		}
+	})
+	var payload models.ErrorPayload
+	client.expect(models.MSG_ERROR, &payload)
+	if len(payload.Error) != 6000 {
+		t.Fatalf("error of %d bytes arrived, want 6000", len(payload.Error))
+	}
+
+	// The write pump counts a frame once it has been written, which may be after the client read it
+	var report compressionReport
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if report = gs.compressionReport(); report.CompressedFrames > 0 {
+			break
+		}
+	}
+	if report.Connections != 1 || report.CompressedFrames != 1 || report.UncompressedFrames == 0 {
+		t.Errorf("report = %+v, want one connection with the large frame compressed and the small ones not", report)
+	}
+	if report.SavedBytes < 5000 || report.SavedPercent <= 0 {
+		t.Errorf("saved %d bytes (%d%%), want most of the repeated error", report.SavedBytes, report.SavedPercent)
+	}
+}
+
+func TestCompressionOff(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+
+	client, negotiated := dialCompressed(t, wsURL)
+	if negotiated {
+		t.Error("server accepted permessage-deflate with WS_COMPRESSION off")
+	}
+	client.expect(models.MSG_SESSION, nil)
+	if report := gs.compressionReport(); report.Connections != 0 || report.PayloadBytes != 0 {
+		t.Errorf("report = %+v, want nothing counted", report)
+	}
+}
+
+func TestInflatedMessageClosesConnection(t *testing.T) {
+	cfg := testConfig()
+	cfg.WSCompression = true
+	cfg.WSCompressionLevel = 1
+	_, _, wsURL := newTestServer(t, cfg)
+
+	client, _ := dialCompressed(t, wsURL)
+	client.expect(models.MSG_SESSION, nil)
+	// Compresses to far less than the read limit, but inflates past it
+	client.conn.EnableWriteCompression(true)
+	if err := client.conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat(" ", 4*maxMessageSize))); err != nil {
+		t.Fatal(err)
+	}
+
+	client.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err := client.conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseMessageTooBig {
+			t.Errorf("read = %v, want close %d", err, websocket.CloseMessageTooBig)
+		}
+		return
+	}
+}
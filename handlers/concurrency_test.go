@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"strconv"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+// topRowWin is a game X wins on the top row while O plays the middle row
+var topRowWin = []int{0, 3, 1, 4, 2}
+
+// playTopRowWin plays topRowWin to the end
+func playTopRowWin(t *testing.T, x, o *testClient, gameID string) {
+	t.Helper()
+	for i, position := range topRowWin {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+}
+
+// Run with -race: games played in parallel must neither race nor leak state into each other
+func TestConcurrentGamesDoNotInterfere(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+
+	type match struct {
+		x, o   *testClient
+		gameID string
+	}
+	matches := make([]match, 8)
+	for i := range matches {
+		x, o, gameID := startRatedGame(t, wsURL)
+		matches[i] = match{x, o, gameID}
+	}
+
+	t.Run("play", func(t *testing.T) {
+		for i, m := range matches {
+			m := m
+			t.Run(strconv.Itoa(i), func(t *testing.T) {
+				t.Parallel()
+				m.x.t, m.o.t = t, t
+				playTopRowWin(t, m.x, m.o, m.gameID)
+			})
+		}
+	})
+
+	type result struct {
+		board          [9]models.Symbol
+		winner         models.Result
+		moves          int
+		xWins, xLosses int
+		oWins, oLosses int
+	}
+	results := make([]result, len(matches))
+	gs.do(func() {
+		for i, m := range matches {
+			gameInstance := testGame(gs, m.gameID)
+			x, o := testPlayer(gs, m.x.playerID), testPlayer(gs, m.o.playerID)
+			results[i] = result{gameInstance.ClassicBoard(), gameInstance.Winner, len(gameInstance.Moves), x.Wins, x.Losses, o.Wins, o.Losses}
+		}
+	})
+
+	want := [9]models.Symbol{"X", "X", "X", "O", "O"}
+	for i, m := range matches {
+		r := results[i]
+		if r.board != want || r.winner != "X" || r.moves != len(topRowWin) {
+			t.Errorf("game %s ended %v won by %q after %d moves", m.gameID, r.board, r.winner, r.moves)
+		}
+		if r.xWins != 1 || r.xLosses != 0 || r.oLosses != 1 || r.oWins != 0 {
+			t.Errorf("game %s stats: X %d-%d, O %d-%d", m.gameID, r.xWins, r.xLosses, r.oWins, r.oLosses)
+		}
+	}
+}
+
+func TestHubSurvivesPanickingEvent(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startRatedGame(t, wsURL)
+
+	// A handler bug is logged and the hub carries on with every other game
+	gs.do(func() { panic("handler bug") })
+
+	playTopRowWin(t, x, o, gameID)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+	"tictactoe-server/webhooks"
+)
+
+// moveDeadline is the deadline of one turn of a correspondence game
+type moveDeadline struct {
+	turn  models.Symbol // Symbol to move
+	moves int           // Moves played when the turn began
+	timer clock.Timer
+}
+
+// scheduleMoveDeadline gives the player to move in a correspondence game the game's move time
+// Calling it again during the same turn keeps the deadline; once the game stops playing it is cancelled
+func (gs *GameServer) scheduleMoveDeadline(gameInstance *models.Game) {
+	if !gameInstance.IsCorrespondence() {
+		return
+	}
+	if gameInstance.Status != models.STATUS_PLAYING {
+		gameInstance.MoveDeadline = nil
+		gs.stopMoveDeadline(gameInstance.ID)
+		return
+	}
+
+	moves := len(gameInstance.Moves)
+	if pending, exists := gs.moveDeadlines[gameInstance.ID]; exists {
+		if pending.turn == gameInstance.CurrentTurn && pending.moves == moves {
+			return
+		}
+		pending.timer.Stop()
+	}
+
+	deadline := gs.clock.Now().Add(gameInstance.MoveTime)
+	gameInstance.MoveDeadline = &deadline
+	turn := &moveDeadline{turn: gameInstance.CurrentTurn, moves: moves}
+	turn.timer = gs.clock.AfterFunc(gameInstance.MoveTime, gs.doLater(func() {
+		gs.moveTimeout(gameInstance, turn)
+	}))
+	gs.moveDeadlines[gameInstance.ID] = turn
+	gs.announceCorrespondenceTurn(gameInstance, deadline)
+}
+
+// announceCorrespondenceTurn tells webhook subscribers whose move a correspondence game waits on
+// Players who aren't connected also get a push notification, see pushYourTurn
+func (gs *GameServer) announceCorrespondenceTurn(gameInstance *models.Game, deadline time.Time) {
+	if gs.webhooks == nil {
+		return
+	}
+	playerIDs := make([]string, 0)
+	for _, player := range gameInstance.SidePlayers(gameInstance.CurrentTurn) {
+		playerIDs = append(playerIDs, player.ID)
+	}
+	gs.webhooks.Send(webhooks.EVENT_CORRESPONDENCE_TURN, map[string]interface{}{
+		"gameId":    gameInstance.ID,
+		"symbol":    gameInstance.CurrentTurn,
+		"playerIds": playerIDs,
+		"deadline":  deadline,
+	})
+}
+
+// moveTimeout forfeits a correspondence game for the side that let its move deadline pass
+func (gs *GameServer) moveTimeout(gameInstance *models.Game, turn *moveDeadline) {
+	if gs.moveDeadlines[gameInstance.ID] != turn {
+		return
+	}
+	delete(gs.moveDeadlines, gameInstance.ID)
+	if gameInstance.Status != models.STATUS_PLAYING || gameInstance.CurrentTurn != turn.turn || len(gameInstance.Moves) != turn.moves {
+		return
+	}
+
+	loser := gameInstance.SidePlayers(turn.turn)[0]
+	if err := gs.gameEngine.Forfeit(gameInstance, loser.ID); err != nil {
+		log.Printf("Failed to forfeit game %s on time: %v", gameInstance.ID, err)
+		return
+	}
+
+	log.Printf("Correspondence game %s lost on time by %s", gameInstance.ID, loser.Name)
+	gs.logEvent(gameInstance.ID, models.EVENT_FORFEIT, loser.ID, map[string]interface{}{"reason": "timeout"})
+	gs.finishGame(gameInstance)
+}
+
+// stopMoveDeadline cancels a correspondence game's pending deadline, if any
+func (gs *GameServer) stopMoveDeadline(gameID string) {
+	if turn, exists := gs.moveDeadlines[gameID]; exists {
+		turn.timer.Stop()
+		delete(gs.moveDeadlines, gameID)
+	}
+}
+
+// correspondenceGames returns the correspondence games a player has in progress
+func (gs *GameServer) correspondenceGames(playerID string) []*models.Game {
+	games := make([]*models.Game, 0)
+	for _, gameInstance := range gs.games.Games() {
+		if gameInstance.IsCorrespondence() && inProgress(gameInstance) && isPlayerInGame(gameInstance, playerID) {
+			games = append(games, gameInstance)
+		}
+	}
+	return games
+}
+
+// correspondenceLimitReached reports whether a player has as many correspondence games going as they may
+func (gs *GameServer) correspondenceLimitReached(playerID string) bool {
+	limit := gs.config.MaxCorrespondenceGames
+	return limit > 0 && len(gs.correspondenceGames(playerID)) >= limit
+}
+
+// sendCorrespondenceGames sends a returning player's connection the state of each correspondence game they have going,
+// so moves made while they were away show up and they can reply
+func (gs *GameServer) sendCorrespondenceGames(conn clientConn, player *models.Player) {
+	for _, gameInstance := range gs.correspondenceGames(player.ID) {
+		seq := 0
+		if last, sent := gs.sentStates[gameInstance.ID]; sent {
+			seq = last.seq
+		}
+		state := gs.gameEngine.GetGameStateForPlayer(gameInstance, player.ID)
+		state.SpectatorCount = gs.spectatorCount(gameInstance.ID)
+		state.Seq = seq
+		gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_GAME_UPDATE, gameInstance.ID, state))
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// waitForDisconnect waits until the hub has handled a player's connection closing
+func waitForDisconnect(t *testing.T, gs *GameServer, playerID string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var connected bool
+		gs.do(func() { connected = gs.isConnected(playerID) })
+		if !connected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("player %s still connected", playerID)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCorrespondenceGameWaitsForAbsentPlayer(t *testing.T) {
+	cfg := testConfig()
+	cfg.DisconnectGracePeriod = 10 * time.Second
+	cfg.CorrespondenceMoveTime = 24 * time.Hour
+	gs, clk, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_CORRESPONDENCE)
+
+	x.conn.Close()
+	waitForDisconnect(t, gs, x.playerID)
+	clk.Advance(time.Hour)
+	gs.do(gs.sweepGames)
+	gs.do(gs.sweepGames)
+	gs.do(func() {
+		if status := testGame(gs, gameID).Status; status != models.STATUS_PLAYING {
+			t.Fatalf("game %s an hour after X left, want it still playing", status)
+		}
+	})
+
+	// X comes back a day later, within the move time, and finds the game waiting
+	x = dialTestClient(t, wsURL, "playerId="+x.playerID+"&token="+x.token)
+	var state testGameState
+	x.expect(models.MSG_GAME_UPDATE, &state)
+	if state.GameID != gameID || !state.IsMyTurn {
+		t.Fatalf("state on return = %+v, want X to move in %s", state, gameID)
+	}
+	playMove(t, x, x, o, gameID, 4)
+
+	// X may play live games while the correspondence game goes on
+	x.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	x.expect(models.MSG_QUEUE_JOINED, nil)
+}
+
+func TestCorrespondenceMoveDeadlineForfeits(t *testing.T) {
+	cfg := testConfig()
+	cfg.CorrespondenceMoveTime = 24 * time.Hour
+	gs, clk, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_CORRESPONDENCE)
+
+	playMove(t, x, x, o, gameID, 4)
+	var deadline time.Time
+	gs.do(func() { deadline = *testGame(gs, gameID).MoveDeadline })
+	if want := clk.Now().Add(cfg.CorrespondenceMoveTime); !deadline.Equal(want) {
+		t.Fatalf("deadline after X moved = %v, want %v", deadline, want)
+	}
+
+	clk.Advance(cfg.CorrespondenceMoveTime)
+	var state testGameState
+	for state.Status != models.STATUS_FINISHED {
+		x.expect(models.MSG_GAME_UPDATE, &state)
+	}
+	if state.Winner != "X" {
+		t.Fatalf("winner = %q, want X after O let the deadline pass", state.Winner)
+	}
+}
+
+func TestCorrespondenceGamesPerPlayerAreCapped(t *testing.T) {
+	cfg := testConfig()
+	cfg.CorrespondenceMoveTime = 24 * time.Hour
+	cfg.MaxCorrespondenceGames = 1
+	_, _, wsURL := newTestServer(t, cfg)
+	x, _, _ := startGame(t, wsURL, models.MODE_CORRESPONDENCE)
+
+	x.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CORRESPONDENCE})
+	var errorData models.ErrorPayload
+	x.expect(models.MSG_ERROR, &errorData)
+	if errorData.Code != models.ERR_NOT_ALLOWED {
+		t.Fatalf("error = %+v, want %s", errorData, models.ERR_NOT_ALLOWED)
+	}
+}
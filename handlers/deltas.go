@@ -0,0 +1,117 @@
+package handlers
+
+import "tictactoe-server/models"
+
+// fullSnapshotInterval is how often, in updates, delta clients get a full game_update anyway
+// so a client that silently lost a delta recovers without asking for a resync
+const fullSnapshotInterval = 10
+
+// sentState is what viewers of a game were last sent, the base for the next delta
+type sentState struct {
+	seq   int
+	board []models.Symbol
+}
+
+// nextGameDelta numbers the next update of a game and describes what changed since the last one
+// Returns a nil delta when a full snapshot is due: periodically, when forced, and once the game is over
+func (gs *GameServer) nextGameDelta(gameInstance *models.Game, spectatorCount int, forceFull bool) (int, *models.GameDelta) {
+	// Every game starts from an empty board, which game_found announced as update 0
+	last, exists := gs.sentStates[gameInstance.ID]
+	if !exists {
+		// Already swept from memory; whoever still hears about it gets the whole state
+		return 0, nil
+	}
+
+	previous := last.board
+	last.seq++
+	last.board = append([]models.Symbol(nil), gameInstance.Board...) // The game's board keeps changing
+
+	if forceFull || !inProgress(gameInstance) || last.seq%fullSnapshotInterval == 0 {
+		return last.seq, nil
+	}
+
+	return last.seq, &models.GameDelta{
+		GameID:              gameInstance.ID,
+		Seq:                 last.seq,
+		Cells:               models.BoardDelta(previous, gameInstance.Board),
+		CurrentTurn:         gameInstance.CurrentTurn,
+		Status:              gameInstance.Status,
+		Winner:              gameInstance.Winner,
+		MoveCount:           len(gameInstance.Moves),
+		TakebackRequestedBy: gameInstance.TakebackRequestedBy,
+		SwapPending:         gameInstance.SwapPending,
+		SpectatorCount:      spectatorCount,
+	}
+}
+
+// pickUpdate returns the delta for connections that understand it, otherwise the full update
+func (gs *GameServer) pickUpdate(conn clientConn, full, delta *models.GameMessage) *models.GameMessage {
+	if delta != nil && models.SupportsMessage(gs.clientVersion(conn), models.MSG_GAME_DELTA) {
+		return delta
+	}
+	return full
+}
+
+// sendStateToPlayer numbers a game update and sends it to each of a player's connections in the shape it understands
+// The full update is what gets replayed on reconnect
+func (gs *GameServer) sendStateToPlayer(playerID string, full, delta *models.GameMessage) {
+	full = gs.numberMessage(playerID, full)
+	if delta != nil && full.Seq != 0 {
+		numbered := *delta
+		numbered.Seq = full.Seq
+		delta = &numbered
+	}
+
+	for conn := range gs.playerConns[playerID] {
+		gs.sendToClient(conn, gs.pickUpdate(conn, full, delta))
+	}
+	if len(gs.playerConns[playerID]) > 0 {
+		gs.markDelivered(playerID, full.Seq)
+	}
+}
+
+// sendStateToSpectators sends a game update to each spectator in the shape it understands
+func (gs *GameServer) sendStateToSpectators(gameID string, full, delta *models.GameMessage) {
+	room, exists := gs.spectators[gameID]
+	if !exists {
+		return
+	}
+	for conn := range room.conns {
+		gs.sendToClient(conn, gs.pickUpdate(conn, full, delta))
+	}
+}
+
+// handleResync sends the full current state of a game to a player or spectator that lost track of it
+// The update keeps the latest sequence number so later deltas apply on top of it; spectators of a delayed game get the delayed state
+func (gs *GameServer) handleResync(conn clientConn, player *models.Player, request *models.GamePayload) {
+	gameInstance, exists := gs.games.Game(request.GameID)
+	isSpectator := false
+	if room, watched := gs.spectators[request.GameID]; watched {
+		isSpectator = room.conns[conn] != nil
+	}
+	if !exists || (!isSpectator && !isPlayerInGame(gameInstance, player.ID)) {
+		gs.sendClientError(conn, models.ERR_NOT_IN_GAME, "You are not playing or watching this game")
+		return
+	}
+
+	if released := gs.releasedSpectatorState(request.GameID); isSpectator && released != nil {
+		gs.sendToClient(conn, released)
+		return
+	}
+
+	seq := 0
+	if last, sent := gs.sentStates[request.GameID]; sent {
+		seq = last.seq
+	}
+	spectatorCount := gs.spectatorCount(request.GameID)
+	var state *models.GameStateView
+	if isSpectator {
+		state = gs.spectatorState(gameInstance, spectatorCount)
+	} else {
+		state = gs.gameEngine.GetGameStateForPlayer(gameInstance, player.ID)
+		state.SpectatorCount = spectatorCount
+	}
+	state.Seq = seq
+
+	gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_GAME_UPDATE, request.GameID, state))
+}
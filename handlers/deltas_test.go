@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+)
+
+// upgradeToDeltas negotiates the protocol version that receives game_delta messages
+func upgradeToDeltas(c *testClient) {
+	c.t.Helper()
+	c.send(models.MSG_HELLO, models.HelloPayload{Version: models.PROTOCOL_VERSION_DELTAS})
+	c.expect(models.MSG_HELLO, nil)
+}
+
+func TestDeltaClientsGetChangedCells(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startRatedGame(t, wsURL)
+	upgradeToDeltas(x)
+
+	position := 4
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &position})
+
+	var delta models.GameDelta
+	x.expect(models.MSG_GAME_DELTA, &delta)
+	if delta.Seq != 1 || len(delta.Cells) != 1 || delta.Cells[0] != (models.CellChange{Position: 4, Symbol: "X"}) {
+		t.Errorf("delta = %+v, want seq 1 with only cell 4 set to X", delta)
+	}
+	if delta.CurrentTurn != "O" || delta.MoveCount != 1 {
+		t.Errorf("delta turn %q after %d moves, want O after 1", delta.CurrentTurn, delta.MoveCount)
+	}
+
+	// Older clients keep getting the whole state
+	var state struct {
+		testGameState
+		Seq int `json:"seq"`
+	}
+	o.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Seq != 1 || state.Board[4] != "X" {
+		t.Errorf("v2 update = seq %d board %v, want seq 1 with X in the centre", state.Seq, state.Board)
+	}
+}
+
+func TestResyncSendsFullState(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startRatedGame(t, wsURL)
+	upgradeToDeltas(x)
+
+	position := 0
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &position})
+	x.expect(models.MSG_GAME_DELTA, nil)
+	o.expect(models.MSG_GAME_UPDATE, nil)
+
+	x.send(models.MSG_RESYNC, models.GamePayload{GameID: gameID})
+	var state struct {
+		testGameState
+		Seq int `json:"seq"`
+	}
+	x.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Seq != 1 || state.Board[0] != "X" || state.MySymbol != "X" {
+		t.Errorf("resync = seq %d board %v as %q, want seq 1 with X in the corner", state.Seq, state.Board, state.MySymbol)
+	}
+
+	outsider := dialTestClient(t, wsURL, "name=carol")
+	outsider.send(models.MSG_RESYNC, models.GamePayload{GameID: gameID})
+	outsider.expect(models.MSG_ERROR, nil)
+}
+
+func TestFullSnapshotsAreSentPeriodically(t *testing.T) {
+	gs := NewGameServerWithClock(testConfig(), clock.NewFake(testEpoch))
+	gameInstance := &models.Game{ID: "g1", Status: models.STATUS_PLAYING}
+	gs.addGame(gameInstance)
+
+	for want := 1; want <= fullSnapshotInterval; want++ {
+		seq, delta := gs.nextGameDelta(gameInstance, 0, false)
+		if seq != want {
+			t.Fatalf("seq = %d, want %d", seq, want)
+		}
+		if full := delta == nil; full != (want == fullSnapshotInterval) {
+			t.Errorf("update %d full = %v", seq, full)
+		}
+	}
+
+	if _, delta := gs.nextGameDelta(gameInstance, 0, true); delta != nil {
+		t.Error("forced update was sent as a delta")
+	}
+	gameInstance.Status = models.STATUS_FINISHED
+	if _, delta := gs.nextGameDelta(gameInstance, 0, false); delta != nil {
+		t.Error("final update was sent as a delta")
+	}
+}
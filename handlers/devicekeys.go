@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// devicePlayer returns the player a device key was first seen with, so clients without an account
+// keep their player across sessions; nil if the key is new or its player was deleted
+func (gs *GameServer) devicePlayer(deviceKey string) *models.Player {
+	if deviceKey == "" {
+		return nil
+	}
+	playerID, exists := gs.store.DevicePlayer(deviceKey)
+	if !exists {
+		return nil
+	}
+	player, exists := gs.players.Player(playerID)
+	if !exists || player.Deleted {
+		return nil
+	}
+	return player
+}
+
+// rememberDevice ties a device key to the player it connected as, unless it already belongs to one
+func (gs *GameServer) rememberDevice(deviceKey string, player *models.Player) {
+	if deviceKey == "" || player.IsBot {
+		return
+	}
+	if gs.store.LinkDeviceKey(deviceKey, player.ID) {
+		log.Printf("Linked a device key to player %s", player.ID)
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const testDeviceKey = "ZGV2aWNlLWtleS1mb3ItdGVzdHM"
+
+func TestDeviceKeyKeepsPlayerAcrossSessions(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+
+	first := dialTestClient(t, wsURL, "name=alice&deviceKey="+testDeviceKey)
+	first.conn.Close()
+	gs.do(func() { testPlayer(gs, first.playerID).Wins = 3 })
+
+	again := dialTestClient(t, wsURL, "name=alice&deviceKey="+testDeviceKey)
+	if again.playerID != first.playerID || again.token != first.token {
+		t.Fatalf("reconnected as %s, want %s", again.playerID, first.playerID)
+	}
+	var player models.Player
+	again.expect(models.MSG_PLAYER_UPDATE, &player)
+	if player.Wins != 3 {
+		t.Errorf("wins = %d, want the stats of the earlier session", player.Wins)
+	}
+
+	other := dialTestClient(t, wsURL, "name=bob&deviceKey=b3RoZXItZGV2aWNlLWtleS1oZXJl")
+	if other.playerID == first.playerID {
+		t.Error("another device key reclaimed alice")
+	}
+}
+
+func TestInvalidDeviceKeyIsIgnored(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=alice&deviceKey=short", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := &testClient{t: t, conn: conn}
+	var payload models.ErrorPayload
+	client.expect(models.MSG_ERROR, &payload)
+	if payload.Code != models.ERR_INVALID_DEVICE_KEY {
+		t.Errorf("error code = %q, want %q", payload.Code, models.ERR_INVALID_DEVICE_KEY)
+	}
+	client.expect(models.MSG_SESSION, nil)
+	if _, exists := gs.store.DevicePlayer("short"); exists {
+		t.Error("invalid device key was linked")
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"tictactoe-server/models"
+)
+
+var errGameNotInProgress = errors.New("game is not in progress")
+
+// sessionPlayer returns the existing player matching the session credentials, or nil
+func (gs *GameServer) sessionPlayer(playerID, token string) *models.Player {
+	if playerID == "" || token == "" {
+		return nil
+	}
+
+	player, exists := gs.players.Player(playerID)
+	if !exists || player.SessionToken != token {
+		return nil
+	}
+	return player
+}
+
+// detachConnections unregisters a player's open connections without running disconnect cleanup
+// Returns the detached connections so the caller can close them
+func (gs *GameServer) detachConnections(playerID string) []clientConn {
+	conns := gs.connectionsForPlayer(playerID)
+	for _, conn := range conns {
+		gs.removeClient(conn)
+		gs.removeSpectator(conn)
+	}
+	return conns
+}
+
+// addClient registers an open connection for a player
+func (gs *GameServer) addClient(conn clientConn, player *models.Player, clientIP string) {
+	gs.clients[conn] = player
+	gs.clientIPs[conn] = clientIP
+	gs.throttle.connections[clientIP]++
+	if gs.playerConns[player.ID] == nil {
+		gs.playerConns[player.ID] = make(map[clientConn]bool)
+	}
+	gs.playerConns[player.ID][conn] = true
+
+	// New connections count as active, and as responsive until they miss pings
+	now := gs.clock.Now()
+	gs.lastActive[player.ID] = now
+	if _, ok := conn.(latencyProber); ok {
+		gs.lastPong[conn] = now
+	}
+}
+
+// removeClient unregisters a connection
+func (gs *GameServer) removeClient(conn clientConn) {
+	if player, exists := gs.clients[conn]; exists {
+		delete(gs.playerConns[player.ID], conn)
+		if len(gs.playerConns[player.ID]) == 0 {
+			delete(gs.playerConns, player.ID)
+		}
+	}
+	if clientIP, exists := gs.clientIPs[conn]; exists {
+		if gs.throttle.connections[clientIP]--; gs.throttle.connections[clientIP] <= 0 {
+			delete(gs.throttle.connections, clientIP)
+		}
+	}
+	delete(gs.clients, conn)
+	delete(gs.clientIPs, conn)
+	delete(gs.lastPong, conn)
+}
+
+// isConnected reports whether a player currently has an open connection
+func (gs *GameServer) isConnected(playerID string) bool {
+	return len(gs.playerConns[playerID]) > 0
+}
+
+// activeGameForPlayer returns the playing or paused game the player is in, if any
+// Players eliminated from a trio game are done with it even while the others play on
+// Correspondence games don't count: they go on without the player, who may play live games meanwhile
+func (gs *GameServer) activeGameForPlayer(playerID string) *models.Game {
+	for _, gameInstance := range gs.games.Games() {
+		if gameInstance.IsCorrespondence() {
+			continue
+		}
+		if isPlayerInGame(gameInstance, playerID) && inProgress(gameInstance) && !gameInstance.IsEliminated(gameInstance.SideOf(playerID)) {
+			return gameInstance
+		}
+	}
+	return nil
+}
+
+// opponentOf returns the other player in a game
+func opponentOf(gameInstance *models.Game, playerID string) *models.Player {
+	if gameInstance.PlayerX() != nil && gameInstance.PlayerX().ID == playerID {
+		return gameInstance.PlayerO()
+	}
+	return gameInstance.PlayerX()
+}
+
+// pauseGameForDisconnect pauses the player's running game and schedules a forfeit
+// Returns the paused game, or nil if nothing was paused
+func (gs *GameServer) pauseGameForDisconnect(player *models.Player) *models.Game {
+	gameInstance := gs.activeGameForPlayer(player.ID)
+	if gameInstance == nil || gameInstance.Status != models.STATUS_PLAYING {
+		// Already paused means the opponent left first; their countdown keeps running
+		return nil
+	}
+	if gs.sideConnected(gameInstance, gameInstance.SideOf(player.ID)) {
+		// A teammate is still there to play for the side
+		return nil
+	}
+	if err := gs.gameEngine.Transition(gameInstance, models.STATUS_PAUSED); err != nil {
+		log.Printf("Failed to pause game %s: %v", gameInstance.ID, err)
+		return nil
+	}
+	gameInstance.DisconnectedPlayerID = player.ID
+
+	gs.startForfeitTimer(gameInstance.ID, player.ID)
+	gs.updateBlitzClock(gameInstance)
+
+	log.Printf("Game %s paused: %s disconnected", gameInstance.ID, player.Name)
+	gs.logEvent(gameInstance.ID, models.EVENT_PLAYER_DISCONNECTED, player.ID, nil)
+	return gameInstance
+}
+
+// startForfeitTimer schedules a forfeit for the disconnected player after the grace period
+func (gs *GameServer) startForfeitTimer(gameID, playerID string) {
+	gs.stopForfeitTimer(gameID)
+	gs.disconnectTimers[gameID] = gs.clock.AfterFunc(gs.config.DisconnectGracePeriod, gs.doLater(func() {
+		gs.forfeitDisconnected(gameID, playerID)
+	}))
+}
+
+// stopForfeitTimer cancels a pending forfeit for the game, if any
+func (gs *GameServer) stopForfeitTimer(gameID string) {
+	if timer, exists := gs.disconnectTimers[gameID]; exists {
+		timer.Stop()
+		delete(gs.disconnectTimers, gameID)
+	}
+}
+
+// notifyOpponentDisconnected tells the remaining side their opponent left and how long they have to return
+func (gs *GameServer) notifyOpponentDisconnected(gameInstance *models.Game, player *models.Player) {
+	gs.sendToOpponents(gameInstance, player.ID, models.NewGameMessageForGame(models.MSG_OPPONENT_DISCONNECTED, gameInstance.ID,
+		map[string]interface{}{
+			"gameId":             gameInstance.ID,
+			"gracePeriodSeconds": int(gs.config.DisconnectGracePeriod.Seconds()),
+		}))
+}
+
+// sendToOpponents sends a message to the connected human players on every other side from playerID
+func (gs *GameServer) sendToOpponents(gameInstance *models.Game, playerID string, msg *models.GameMessage) {
+	side := gameInstance.SideOf(playerID)
+	for _, opponent := range gameInstance.AllPlayers() {
+		if gameInstance.SideOf(opponent.ID) != side && !opponent.IsBot && gs.isConnected(opponent.ID) {
+			gs.sendToPlayer(opponent.ID, msg)
+		}
+	}
+}
+
+// sideConnected reports whether anyone playing a symbol in the game is connected
+func (gs *GameServer) sideConnected(gameInstance *models.Game, symbol models.Symbol) bool {
+	for _, player := range gameInstance.SidePlayers(symbol) {
+		if gs.isConnected(player.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// absentOpponent returns the seated human of another side still in the game with nobody connected, or nil
+func (gs *GameServer) absentOpponent(gameInstance *models.Game, side models.Symbol) *models.Player {
+	for _, symbol := range gameInstance.ActiveSymbols() {
+		if symbol == side || gs.sideConnected(gameInstance, symbol) {
+			continue
+		}
+		if seated := gameInstance.SidePlayers(symbol)[0]; !seated.IsBot {
+			return seated
+		}
+	}
+	return nil
+}
+
+// forfeitDisconnected ends a paused game in the opponent's favor once the grace period expires
+func (gs *GameServer) forfeitDisconnected(gameID, playerID string) {
+	gameInstance, exists := gs.games.Game(gameID)
+	if !exists || gameInstance.Status != models.STATUS_PAUSED || gameInstance.DisconnectedPlayerID != playerID {
+		return
+	}
+
+	if gs.bothPlayersGone(gameInstance) {
+		// Nobody is left to award the win to; treat it as abandoned instead
+		gs.abandonGame(gameID)
+		return
+	}
+
+	delete(gs.disconnectTimers, gameID)
+	if err := gs.gameEngine.Forfeit(gameInstance, playerID); err != nil {
+		log.Printf("Failed to forfeit game %s: %v", gameID, err)
+		return
+	}
+
+	log.Printf("Game %s forfeited by disconnected player %s", gameID, playerID)
+	gs.logEvent(gameID, models.EVENT_FORFEIT, playerID, map[string]interface{}{"reason": "disconnect"})
+	if leaver, exists := gs.players.Player(playerID); exists {
+		gs.recordLeave(leaver, gameID)
+	}
+
+	if gameInstance.Status != models.STATUS_FINISHED {
+		// Eliminated from a trio game; the others play on, unless another of them is away too
+		if absent := gs.absentOpponent(gameInstance, ""); absent != nil && gs.gameEngine.Transition(gameInstance, models.STATUS_PAUSED) == nil {
+			gameInstance.DisconnectedPlayerID = absent.ID
+			gs.startForfeitTimer(gameID, absent.ID)
+		}
+		gs.sendFullGameUpdate(gameInstance)
+		return
+	}
+	gs.finishGame(gameInstance)
+}
+
+// handleReconnect resumes a paused game when a disconnected player returns
+func (gs *GameServer) handleReconnect(player *models.Player) {
+	gameInstance := gs.activeGameForPlayer(player.ID)
+	if gameInstance == nil {
+		return
+	}
+
+	resumed := false
+	// In team games any member returning resumes play for their side
+	side := gameInstance.SideOf(player.ID)
+	if gameInstance.Status == models.STATUS_PAUSED && gameInstance.SideOf(gameInstance.DisconnectedPlayerID) == side {
+		gs.stopForfeitTimer(gameInstance.ID)
+
+		if opponent := gs.absentOpponent(gameInstance, side); opponent != nil {
+			// Opponent left while we were away; the countdown now applies to them
+			gameInstance.DisconnectedPlayerID = opponent.ID
+			gs.startForfeitTimer(gameInstance.ID, opponent.ID)
+		} else if err := gs.gameEngine.Transition(gameInstance, models.STATUS_PLAYING); err == nil {
+			gameInstance.DisconnectedPlayerID = ""
+			resumed = true
+		}
+	}
+
+	gs.logEvent(gameInstance.ID, models.EVENT_PLAYER_RECONNECTED, player.ID, map[string]interface{}{"resumed": resumed})
+
+	if resumed {
+		log.Printf("Game %s resumed: %s reconnected", gameInstance.ID, player.Name)
+		gs.sendToOpponents(gameInstance, player.ID, models.NewGameMessageForGame(models.MSG_OPPONENT_RECONNECTED, gameInstance.ID,
+			map[string]string{"gameId": gameInstance.ID}))
+	}
+
+	// The new connection has never seen this game, so it needs the whole state
+	gs.sendFullGameUpdate(gameInstance)
+	gs.updateBlitzClock(gameInstance)
+}
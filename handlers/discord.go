@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"tictactoe-server/config"
+	"tictactoe-server/discord"
+	"tictactoe-server/models"
+)
+
+// registerDiscord posts finished games to the configured Discord channel
+func (gs *GameServer) registerDiscord() {
+	if gs.config.DiscordWebhookURL == "" {
+		return
+	}
+
+	gs.discord = discord.NewClient(gs.config.DiscordWebhookURL)
+	if gs.config.DiscordResults != config.DISCORD_RESULTS_OFF {
+		gs.OnGameFinished(gs.postDiscordResult)
+	}
+}
+
+// postDiscordResult posts a game's result unless only rated games are wanted and it was not rated
+func (gs *GameServer) postDiscordResult(gameInstance *models.Game) {
+	if gs.config.DiscordResults == config.DISCORD_RESULTS_RATED && !gameInstance.Rated {
+		return
+	}
+	gs.discord.Post(discord.FormatResult(gameInstance))
+}
+
+// runDiscordLeaderboard posts a leaderboard snapshot every interval
+func (gs *GameServer) runDiscordLeaderboard() {
+	ticker := gs.clock.NewTicker(gs.config.DiscordLeaderboardInterval)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		gs.do(gs.postDiscordLeaderboard)
+	}
+}
+
+// postDiscordLeaderboard posts the current season's top players, if anyone is ranked yet
+func (gs *GameServer) postDiscordLeaderboard() {
+	leaderboard := gs.getLeaderboard(models.RATING_POOL_STANDARD)
+	if len(leaderboard) == 0 {
+		return
+	}
+	gs.discord.Post(discord.FormatLeaderboard(gs.season.Number, leaderboard))
+}
+
+// closeDiscord posts queued Discord messages before shutdown, giving up after webhookDrainTimeout
+func (gs *GameServer) closeDiscord() {
+	if gs.discord == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookDrainTimeout)
+	defer cancel()
+	if err := gs.discord.Close(ctx); err != nil {
+		log.Printf("Discord posts abandoned at shutdown: %v", err)
+	}
+}
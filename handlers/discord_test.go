@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+func TestDiscordPostsResultsAndLeaderboard(t *testing.T) {
+	var mu sync.Mutex
+	var posted []string
+	channel := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct{ Content string }
+		json.NewDecoder(r.Body).Decode(&message)
+		mu.Lock()
+		posted = append(posted, message.Content)
+		mu.Unlock()
+	}))
+	defer channel.Close()
+
+	cfg := testConfig()
+	cfg.DiscordWebhookURL = channel.URL
+	cfg.DiscordResults = config.DISCORD_RESULTS_RATED
+	cfg.PlacementGames = 0
+	cfg.SameIPPolicy = config.SAME_IP_OFF
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	// A casual game is left out of the channel
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+
+	x, o, gameID = startRatedGame(t, wsURL)
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+	x.expect(models.MSG_GAME_END, nil)
+
+	gs.do(gs.postDiscordLeaderboard)
+	gs.Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posted) != 2 {
+		t.Fatalf("posted %q, want a result and a leaderboard", posted)
+	}
+	if !strings.Contains(posted[0], "**alice** (X) beat **bob** (O) in a rated game") &&
+		!strings.Contains(posted[0], "**bob** (X) beat **alice** (O) in a rated game") {
+		t.Errorf("result = %q", posted[0])
+	}
+	if !strings.HasPrefix(posted[1], "📊 **Season 1 leaderboard**\n1. ") {
+		t.Errorf("leaderboard = %q", posted[1])
+	}
+}
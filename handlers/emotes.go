@@ -0,0 +1,55 @@
+package handlers
+
+import "tictactoe-server/models"
+
+// handleEmote relays a player's emote to their opponent, themselves and the game's spectators
+// Emotes work in finished games still in memory so players can say gg
+func (gs *GameServer) handleEmote(conn clientConn, player *models.Player, request *models.EmotePayload) {
+	if !gs.config.EmotesEnabled {
+		gs.sendClientError(conn, models.ERR_DISABLED, "Emotes are disabled")
+		return
+	}
+
+	gameInstance, exists := gs.games.Game(request.GameID)
+	if !exists || !isPlayerInGame(gameInstance, player.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_IN_GAME, "You are not playing in this game")
+		return
+	}
+
+	key := request.GameID + "/" + player.ID
+	now := gs.clock.Now()
+	if last, sent := gs.lastEmotes[key]; sent && now.Sub(last) < gs.config.EmoteCooldown {
+		gs.sendClientError(conn, models.ERR_RATE_LIMITED, "You are sending emotes too quickly")
+		return
+	}
+	gs.lastEmotes[key] = now
+
+	var recipients []string
+	for _, p := range gameInstance.AllPlayers() {
+		if !p.IsBot && !gs.store.HasBlocked(p.ID, player.ID) {
+			recipients = append(recipients, p.ID)
+		}
+	}
+
+	gs.logEvent(request.GameID, models.EVENT_EMOTE, player.ID, map[string]interface{}{"emote": request.Emote})
+
+	emoteMsg := models.NewGameMessageForGame(models.MSG_EMOTE, request.GameID, map[string]interface{}{
+		"gameId":    request.GameID,
+		"playerId":  player.ID,
+		"name":      player.Name,
+		"emote":     request.Emote,
+		"timestamp": now,
+	})
+
+	for _, playerID := range recipients {
+		gs.sendToPlayer(playerID, emoteMsg)
+	}
+	gs.sendChatToSpectators(request.GameID, player.ID, emoteMsg)
+}
+
+// forgetEmotes drops emote cooldowns for a game leaving memory
+func (gs *GameServer) forgetEmotes(gameInstance *models.Game) {
+	for _, player := range gameInstance.AllPlayers() {
+		delete(gs.lastEmotes, gameInstance.ID+"/"+player.ID)
+	}
+}
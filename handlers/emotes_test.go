@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// emoteMessage is the payload of an emote message
+type emoteMessage struct {
+	PlayerID string `json:"playerId"`
+	Emote    string `json:"emote"`
+}
+
+func TestEmoteRelayedToOpponentAndSpectators(t *testing.T) {
+	_, clk, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startRatedGame(t, wsURL)
+
+	watcher := dialTestClient(t, wsURL, "name=carol")
+	watcher.send(models.MSG_SPECTATE_GAME, models.GamePayload{GameID: gameID})
+	watcher.expect(models.MSG_GAME_UPDATE, nil)
+
+	x.send(models.MSG_EMOTE, models.EmotePayload{GameID: gameID, Emote: models.EMOTE_GOOD_MOVE})
+	for _, client := range []*testClient{x, o, watcher} {
+		var emote emoteMessage
+		client.expect(models.MSG_EMOTE, &emote)
+		if emote.PlayerID != x.playerID || emote.Emote != models.EMOTE_GOOD_MOVE {
+			t.Errorf("%s got emote %+v", client.playerID, emote)
+		}
+	}
+
+	// A second emote inside the cooldown is refused; after it, emotes flow again
+	x.send(models.MSG_EMOTE, models.EmotePayload{GameID: gameID, Emote: models.EMOTE_OOPS})
+	x.expect(models.MSG_ERROR, nil)
+
+	clk.Advance(time.Second)
+	x.send(models.MSG_EMOTE, models.EmotePayload{GameID: gameID, Emote: models.EMOTE_GG})
+	var emote emoteMessage
+	o.expect(models.MSG_EMOTE, &emote)
+	if emote.Emote != models.EMOTE_GG {
+		t.Errorf("after cooldown got %+v, want %s", emote, models.EMOTE_GG)
+	}
+
+	// Spectators and free text are rejected
+	watcher.send(models.MSG_EMOTE, models.EmotePayload{GameID: gameID, Emote: models.EMOTE_GG})
+	watcher.expect(models.MSG_ERROR, nil)
+	o.send(models.MSG_EMOTE, map[string]string{"gameId": gameID, "emote": "you stink"})
+	o.expect(models.MSG_ERROR, nil)
+}
+
+func TestEmotesDisabled(t *testing.T) {
+	cfg := testConfig()
+	cfg.EmotesEnabled = false
+	_, _, wsURL := newTestServer(t, cfg)
+	x, _, gameID := startRatedGame(t, wsURL)
+
+	x.send(models.MSG_EMOTE, models.EmotePayload{GameID: gameID, Emote: models.EMOTE_SMILE})
+	var failure struct {
+		Error string `json:"error"`
+	}
+	x.expect(models.MSG_ERROR, &failure)
+	if failure.Error != "Emotes are disabled" {
+		t.Errorf("error = %q", failure.Error)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// eventPruneInterval is how often expired event logs are removed
+const eventPruneInterval = time.Hour
+
+// logEvent appends an event to a game's audit trail
+func (gs *GameServer) logEvent(gameID, eventType, playerID string, data map[string]interface{}) {
+	gs.store.AppendEvent(models.GameEvent{
+		GameID:    gameID,
+		Type:      eventType,
+		PlayerID:  playerID,
+		Data:      data,
+		Timestamp: gs.clock.Now(),
+	})
+}
+
+// logGameCreated records a new game and every player joining it
+func (gs *GameServer) logGameCreated(gameInstance *models.Game) {
+	gs.logEvent(gameInstance.ID, models.EVENT_CREATED, "", map[string]interface{}{
+		"rated":      gameInstance.Rated,
+		"firstMover": gameInstance.FirstMoverID,
+		"size":       gameInstance.Size,
+		"variant":    gameInstance.Variant,
+	})
+	for _, player := range gameInstance.AllPlayers() {
+		gs.logEvent(gameInstance.ID, models.EVENT_PLAYER_JOINED, player.ID, map[string]interface{}{
+			"name":   player.Name,
+			"symbol": player.Symbol,
+			"isBot":  player.IsBot,
+		})
+	}
+}
+
+// runEventRetention periodically drops event logs older than the configured retention
+func (gs *GameServer) runEventRetention() {
+	ticker := gs.clock.NewTicker(eventPruneInterval)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		if pruned := gs.store.PruneEvents(gs.clock.Now().Add(-gs.config.EventRetention)); pruned > 0 {
+			log.Printf("Pruned event logs for %d games", pruned)
+		}
+	}
+}
+
+// handleAdminGameEvents serves GET /admin/games/{id}/events
+func (gs *GameServer) handleAdminGameEvents(w http.ResponseWriter, gameID string) {
+	events := gs.store.GameEvents(gameID)
+	if len(events) == 0 {
+		http.Error(w, "no events for game", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// staleNotice is the game_expiring notice sent for one turn of a game nobody is moving in
+type staleNotice struct {
+	turn      models.Symbol // Symbol to move
+	moves     int           // Moves played when the notice was sent; any move since lifts it
+	expiresAt time.Time
+}
+
+// checkStaleGame warns the players of an untimed game nobody has moved in for a while, and reports whether its time is up
+// Blitz games are settled by their clock and correspondence games by their move deadline, so only untimed games expire;
+// paused games wait on the forfeit timer instead
+func (gs *GameServer) checkStaleGame(gameInstance *models.Game, now time.Time) bool {
+	if gs.config.StaleGameAfter <= 0 || gameInstance.TimeLeft != nil || gameInstance.IsCorrespondence() || gameInstance.Status != models.STATUS_PLAYING {
+		delete(gs.staleNotices, gameInstance.ID)
+		return false
+	}
+
+	moves := len(gameInstance.Moves)
+	if notice, sent := gs.staleNotices[gameInstance.ID]; sent {
+		if notice.turn == gameInstance.CurrentTurn && notice.moves == moves {
+			return !now.Before(notice.expiresAt)
+		}
+		delete(gs.staleNotices, gameInstance.ID)
+	}
+
+	if now.Sub(lastMoveTime(gameInstance)) < gs.config.StaleGameAfter-gs.config.StaleGameWarning {
+		return false
+	}
+	notice := &staleNotice{turn: gameInstance.CurrentTurn, moves: moves, expiresAt: now.Add(gs.config.StaleGameWarning)}
+	gs.staleNotices[gameInstance.ID] = notice
+	gs.sendGameExpiring(gameInstance, notice)
+	return false
+}
+
+// lastMoveTime returns when a game's last move was played, or when it started if nobody has moved
+func lastMoveTime(gameInstance *models.Game) time.Time {
+	if len(gameInstance.Moves) == 0 {
+		return gameInstance.StartTime
+	}
+	return gameInstance.Moves[len(gameInstance.Moves)-1].Timestamp
+}
+
+// sendGameExpiring tells everyone playing a stale game how and when it will be adjudicated
+func (gs *GameServer) sendGameExpiring(gameInstance *models.Game, notice *staleNotice) {
+	msg := models.NewGameMessageForGame(models.MSG_GAME_EXPIRING, gameInstance.ID, models.GameExpiring{
+		GameID:       gameInstance.ID,
+		ExpiresAt:    notice.expiresAt,
+		Adjudication: gs.config.StaleGamePolicy,
+		Symbol:       notice.turn,
+	})
+	for _, player := range gameInstance.AllPlayers() {
+		for conn := range gs.playerConns[player.ID] {
+			if models.SupportsMessage(gs.clientVersion(conn), models.MSG_GAME_EXPIRING) {
+				gs.sendToClient(conn, msg)
+			}
+		}
+	}
+	log.Printf("Game %s has gone without a move, expiring at %s", gameInstance.ID, notice.expiresAt.Format(time.RFC3339))
+}
+
+// expireStaleGame adjudicates a game nobody moved in after its notice ran out, according to the configured policy
+func (gs *GameServer) expireStaleGame(gameID string) {
+	gameInstance, exists := gs.games.Game(gameID)
+	if !exists {
+		return
+	}
+	delete(gs.staleNotices, gameID)
+
+	policy := gs.config.StaleGamePolicy
+	loserID := ""
+	var err error
+	if policy == config.STALE_GAMES_FORFEIT {
+		loserID = gameInstance.SidePlayers(gameInstance.CurrentTurn)[0].ID
+		err = gs.gameEngine.Forfeit(gameInstance, loserID)
+	} else {
+		err = gs.gameEngine.EndGame(gameInstance, models.RESULT_DRAW)
+	}
+	if err != nil {
+		log.Printf("Failed to expire game %s: %v", gameID, err)
+		return
+	}
+
+	gs.lifecycle.Expired++
+	log.Printf("Game %s expired without a move, resolved as %s", gameID, policy)
+	gs.logEvent(gameID, models.EVENT_EXPIRED, loserID, map[string]interface{}{"policy": policy})
+
+	if gameInstance.Status != models.STATUS_FINISHED {
+		// Eliminated from a trio game; the others play on
+		gs.sendFullGameUpdate(gameInstance)
+		return
+	}
+	gs.finishGame(gameInstance)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// staleConfig expires games after ten minutes without a move, with a two-minute notice
+func staleConfig(policy string) *config.Config {
+	cfg := testConfig()
+	cfg.StaleGameAfter = 10 * time.Minute
+	cfg.StaleGameWarning = 2 * time.Minute
+	cfg.StaleGamePolicy = policy
+	return cfg
+}
+
+func TestStaleGameIsNoticedThenDrawn(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, staleConfig(config.STALE_GAMES_DRAW))
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	clk.Advance(8 * time.Minute)
+	gs.do(gs.sweepGames)
+	var notice models.GameExpiring
+	o.expect(models.MSG_GAME_EXPIRING, &notice)
+	x.expect(models.MSG_GAME_EXPIRING, nil)
+	if notice.GameID != gameID || notice.Adjudication != config.STALE_GAMES_DRAW || !notice.ExpiresAt.Equal(clk.Now().Add(2*time.Minute)) {
+		t.Fatalf("game_expiring = %+v", notice)
+	}
+
+	// A move lifts the notice and restarts the wait
+	playMove(t, x, x, o, gameID, 4)
+	clk.Advance(2 * time.Minute)
+	gs.do(gs.sweepGames)
+	if status := testGame(gs, gameID).Status; status != models.STATUS_PLAYING {
+		t.Fatalf("status after a move = %s, want playing", status)
+	}
+
+	clk.Advance(6 * time.Minute)
+	gs.do(gs.sweepGames)
+	x.expect(models.MSG_GAME_EXPIRING, nil)
+	clk.Advance(2 * time.Minute)
+	gs.do(gs.sweepGames)
+	var end models.GameEndPayload
+	x.expect(models.MSG_GAME_END, &end)
+	if end.Winner != "draw" {
+		t.Fatalf("game_end = %+v, want a draw", end)
+	}
+	var stats lifecycleStats
+	gs.do(func() { stats = gs.lifecycle })
+	if stats.Expired != 1 {
+		t.Errorf("lifecycle = %+v, want one expired game", stats)
+	}
+}
+
+func TestStaleGameForfeitsSideToMove(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, staleConfig(config.STALE_GAMES_FORFEIT))
+	x, o, _ := startGame(t, wsURL, models.MODE_CASUAL)
+
+	clk.Advance(8 * time.Minute)
+	gs.do(gs.sweepGames)
+	x.expect(models.MSG_GAME_EXPIRING, nil)
+	clk.Advance(2 * time.Minute)
+	gs.do(gs.sweepGames)
+	var end models.GameEndPayload
+	o.expect(models.MSG_GAME_END, &end)
+	if end.Winner != "O" {
+		t.Fatalf("game_end = %+v, want X to lose on the turn they never played", end)
+	}
+}
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"tictactoe-server/models"
+	"tictactoe-server/proto/tictactoepb"
+
+	"github.com/gorilla/websocket"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcProtocolVersion is the protocol gRPC clients speak; they get typed full game states rather than deltas
+const grpcProtocolVersion = models.PROTOCOL_VERSION_DELTAS - 1
+
+// grpcService implements the TicTacToe gRPC service on the same game core as the WebSocket handler
+type grpcService struct {
+	tictactoepb.UnimplementedTicTacToeServer
+	gs *GameServer
+}
+
+// GRPCService returns the gRPC mirror of the WebSocket game API
+func (gs *GameServer) GRPCService() tictactoepb.TicTacToeServer {
+	return &grpcService{gs: gs}
+}
+
+// PlayGame runs one player's session for the lifetime of the stream
+func (s *grpcService) PlayGame(stream tictactoepb.TicTacToe_PlayGameServer) error {
+	gs := s.gs
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	header := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	clientIP := gs.clientIP(peerIP(stream), md.Get("x-forwarded-for"), header("x-real-ip"))
+	var admit int
+	gs.do(func() { admit, _ = gs.screenConnection(clientIP, header("player-id"), header("token")) })
+	switch admit {
+	case ADMIT_SHUTTING_DOWN:
+		return status.Error(codes.Unavailable, "server shutting down")
+	case ADMIT_BANNED:
+		return status.Error(codes.PermissionDenied, "banned")
+	case ADMIT_THROTTLED:
+		return status.Error(codes.ResourceExhausted, "too many connections")
+	case ADMIT_FULL:
+		return status.Error(codes.ResourceExhausted, "server full")
+	}
+
+	conn := newGRPCClient(stream)
+	player := gs.register(conn, clientIP, header("user-agent"), header("name"), header("player-id"), header("token"), header("device-key"), header("region"), grpcProtocolVersion)
+	if player == nil {
+		conn.flush()
+		return conn.closeStatus()
+	}
+	defer gs.unregister(conn)
+	if token := header("invite"); token != "" {
+		gs.do(func() { gs.acceptInvite(conn, player, token) })
+	}
+
+	// Recv blocks until the stream ends, so it runs apart from the loop that watches for server-side closes
+	requests := make(chan *tictactoepb.ClientMessage)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			request, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- request:
+			case <-conn.done:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case request := <-requests:
+			ctx, span := tracer.Start(stream.Context(), "grpc.recv", trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(ATTR_TRANSPORT.String("grpc")))
+			msg, err := messageFromProto(request)
+			if err != nil {
+				span.SetStatus(otelcodes.Error, err.Error())
+				span.End()
+				gs.do(func() { gs.sendClientError(conn, models.ERR_MALFORMED_MESSAGE, err.Error()) })
+				continue
+			}
+			span.SetAttributes(ATTR_MESSAGE_TYPE.String(msg.Type))
+			gs.receive(ctx, conn, msg)
+			span.End()
+		case err := <-recvErr:
+			if err != io.EOF && status.Code(err) != codes.Canceled {
+				log.Printf("gRPC stream error: %v", err)
+			}
+			return nil
+		case <-conn.send.ready:
+			for event, ok := conn.send.pop(); ok; event, ok = conn.send.pop() {
+				if err := stream.Send(event); err != nil {
+					log.Printf("gRPC send error: %v", err)
+					return nil
+				}
+			}
+		case <-conn.done:
+			conn.flush()
+			return conn.closeStatus()
+		}
+	}
+}
+
+// peerIP returns the IP address of the stream's remote end
+func peerIP(stream tictactoepb.TicTacToe_PlayGameServer) string {
+	p, ok := peer.FromContext(stream.Context())
+	if !ok {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// grpcClient adapts a PlayGame stream to a clientConn
+// Messages are queued and sent by the PlayGame loop, since a stream may only be used by its handler.
+type grpcClient struct {
+	stream tictactoepb.TicTacToe_PlayGameServer
+	send   *sendQueue[*tictactoepb.ServerMessage]
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error // Status returned to the client when the server closes the stream
+}
+
+func newGRPCClient(stream tictactoepb.TicTacToe_PlayGameServer) *grpcClient {
+	return &grpcClient{
+		stream: stream,
+		send:   newSendQueue[*tictactoepb.ServerMessage](),
+		done:   make(chan struct{}),
+	}
+}
+
+// WriteMessage converts the message to its typed form and queues it for the stream
+func (c *grpcClient) WriteMessage(msg *models.GameMessage) error {
+	select {
+	case <-c.done:
+		return errClientClosed
+	default:
+	}
+
+	event, err := messageToProto(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.send.push(event, droppableType(msg))
+}
+
+// flush sends whatever is still queued, such as the disconnect notice before a close
+func (c *grpcClient) flush() {
+	for {
+		event, ok := c.send.pop()
+		if !ok || c.stream.Send(event) != nil {
+			return
+		}
+	}
+}
+
+// CloseWithReason ends the stream with a status carrying the close code and reason
+func (c *grpcClient) CloseWithReason(code int, reason string) {
+	c.close(status.Error(grpcCodeFor(code), fmt.Sprintf("%s (close code %d)", reason, code)))
+}
+
+// Close ends the stream normally
+func (c *grpcClient) Close() error {
+	c.close(nil)
+	return nil
+}
+
+func (c *grpcClient) close(err error) {
+	c.closeOnce.Do(func() {
+		c.closeErr = err
+		close(c.done)
+	})
+}
+
+// closeStatus returns the status the stream was closed with
+func (c *grpcClient) closeStatus() error {
+	<-c.done
+	return c.closeErr
+}
+
+// grpcCodeFor maps a WebSocket close code to the closest gRPC status code
+func grpcCodeFor(code int) codes.Code {
+	switch code {
+	case websocket.CloseGoingAway:
+		return codes.Unavailable
+	case websocket.ClosePolicyViolation, websocket.CloseProtocolError:
+		return codes.InvalidArgument
+	case models.CLOSE_IDLE_TIMEOUT:
+		return codes.DeadlineExceeded
+	case models.CLOSE_KICKED, models.CLOSE_BANNED:
+		return codes.PermissionDenied
+	case models.CLOSE_DUPLICATE_LOGIN:
+		return codes.AlreadyExists
+	case models.CLOSE_INVALID_NAME:
+		return codes.InvalidArgument
+	case models.CLOSE_ACCOUNT_DELETED:
+		return codes.Unauthenticated
+	}
+	return codes.Aborted
+}
+
+// messageFromProto converts a client request into the message the WebSocket handler would receive
+func messageFromProto(request *tictactoepb.ClientMessage) (*models.GameMessage, error) {
+	switch message := request.Message.(type) {
+	case *tictactoepb.ClientMessage_JoinQueue:
+		return models.NewGameMessage(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: message.JoinQueue.Mode}), nil
+	case *tictactoepb.ClientMessage_LeaveQueue:
+		return models.NewGameMessage(models.MSG_LEAVE_QUEUE, nil), nil
+	case *tictactoepb.ClientMessage_MakeMove:
+		position := int(message.MakeMove.Position)
+		return models.NewGameMessageForGame(models.MSG_MAKE_MOVE, message.MakeMove.GameId,
+			models.MakeMovePayload{GameID: message.MakeMove.GameId, Position: &position, MoveID: message.MakeMove.MoveId}), nil
+	case *tictactoepb.ClientMessage_Envelope:
+		return models.MessageFromEnvelope(message.Envelope)
+	}
+	return nil, errors.New("empty client message")
+}
+
+// messageToProto converts a server message to its typed form, falling back to an envelope
+func messageToProto(msg *models.GameMessage) (*tictactoepb.ServerMessage, error) {
+	event := &tictactoepb.ServerMessage{Type: msg.Type}
+
+	switch msg.Type {
+	case models.MSG_SESSION:
+		var session struct {
+			PlayerID string `json:"playerId"`
+			Token    string `json:"token"`
+		}
+		if err := json.Unmarshal(msg.Data, &session); err != nil {
+			return nil, err
+		}
+		event.Message = &tictactoepb.ServerMessage_Session{Session: &tictactoepb.Session{
+			PlayerId: session.PlayerID,
+			Token:    session.Token,
+		}}
+		return event, nil
+
+	case models.MSG_PLAYER_UPDATE:
+		var player models.Player
+		if err := json.Unmarshal(msg.Data, &player); err != nil {
+			return nil, err
+		}
+		event.Message = &tictactoepb.ServerMessage_Player{Player: playerToProto(&player)}
+		return event, nil
+
+	case models.MSG_LEADERBOARD:
+		var players []*models.Player
+		if err := json.Unmarshal(msg.Data, &players); err != nil {
+			return nil, err
+		}
+		leaderboard := &tictactoepb.Leaderboard{}
+		for _, player := range players {
+			leaderboard.Players = append(leaderboard.Players, playerToProto(player))
+		}
+		event.Message = &tictactoepb.ServerMessage_Leaderboard{Leaderboard: leaderboard}
+		return event, nil
+
+	case models.MSG_ERROR:
+		var body models.ErrorPayload
+		if err := json.Unmarshal(msg.Data, &body); err != nil {
+			return nil, err
+		}
+		event.Message = &tictactoepb.ServerMessage_Error{Error: &tictactoepb.Error{Message: body.Error, Code: body.Code}}
+		return event, nil
+
+	case models.MSG_GAME_FOUND, models.MSG_GAME_UPDATE:
+		var state models.GameStateView
+		if err := json.Unmarshal(msg.Data, &state); err != nil {
+			return nil, err
+		}
+		// Spectator views name both players and have no "my" side, team views list teammates,
+		// pie rule views carry the swap flags, misère views the variant, trio views their seats and blitz views
+		// the clock, so they stay envelopes
+		if !state.Spectating && state.Teams == nil && !state.PieRule && state.Variant != models.VARIANT_MISERE &&
+			state.Seats == nil && state.TimeLeftMs == nil {
+			event.Message = &tictactoepb.ServerMessage_Game{Game: gameToProto(&state)}
+			return event, nil
+		}
+	}
+
+	envelope, err := models.EnvelopeFromMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	event.Message = &tictactoepb.ServerMessage_Envelope{Envelope: envelope}
+	return event, nil
+}
+
+func playerToProto(player *models.Player) *tictactoepb.Player {
+	return &tictactoepb.Player{
+		Id:            player.ID,
+		Name:          player.Name,
+		Symbol:        string(player.Symbol),
+		Wins:          int32(player.Wins),
+		Losses:        int32(player.Losses),
+		Draws:         int32(player.Draws),
+		Rating:        int32(player.Rating(models.RATING_POOL_STANDARD)),
+		CurrentStreak: int32(player.CurrentStreak),
+		LongestStreak: int32(player.LongestStreak),
+		CasualWins:    int32(player.CasualWins),
+		CasualLosses:  int32(player.CasualLosses),
+		CasualDraws:   int32(player.CasualDraws),
+		LastSeen:      timestamppb.New(player.LastSeen),
+		IsBot:         player.IsBot,
+	}
+}
+
+func gameToProto(state *models.GameStateView) *tictactoepb.Game {
+	board := make([]string, len(state.Board))
+	for i, cell := range state.Board {
+		board[i] = string(cell)
+	}
+	return &tictactoepb.Game{
+		GameId:              state.GameID,
+		Board:               board,
+		CurrentTurn:         string(state.CurrentTurn),
+		Status:              state.Status,
+		Winner:              string(state.Winner),
+		MySymbol:            string(state.MySymbol),
+		OpponentName:        state.OpponentName,
+		OpponentIsBot:       state.OpponentIsBot,
+		IsMyTurn:            state.IsMyTurn,
+		Rated:               state.Rated,
+		MoveCount:           int32(state.MoveCount),
+		SpectatorCount:      int32(state.SpectatorCount),
+		TakebackRequestedBy: state.TakebackRequestedBy,
+	}
+}
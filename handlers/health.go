@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// storagePingTimeout bounds the storage check in readiness probes
+const storagePingTimeout = 2 * time.Second
+
+// healthReport is the body of the health endpoints
+type healthReport struct {
+	Status           string         `json:"status"`
+	UptimeSeconds    int64          `json:"uptimeSeconds"`
+	Connections      int            `json:"connections"`
+	ActiveGames      int            `json:"activeGames"`
+	Queued           map[string]int `json:"queued"`
+	QueueLength      int            `json:"queueLength"` // Total across all queues
+	BroadcastBacklog int            `json:"broadcastBacklog"`
+	BroadcastCap     int            `json:"broadcastCapacity"`
+	HubBacklog       int            `json:"hubBacklog"` // Events waiting for the hub
+	Storage          string         `json:"storage"`
+	ShuttingDown     bool           `json:"shuttingDown"`
+	Draining         bool           `json:"draining"`
+}
+
+// healthReport gathers the current state for the health endpoints, checking storage when asked
+func (gs *GameServer) healthReport(ctx context.Context, checkStorage bool) healthReport {
+	var usage utilization
+	var shuttingDown, draining bool
+	gs.do(func() {
+		usage = gs.utilization()
+		shuttingDown = gs.shuttingDown
+		draining = gs.draining
+	})
+	queueLength := 0
+	for _, queued := range usage.Queued {
+		queueLength += queued
+	}
+
+	report := healthReport{
+		Status:           "ok",
+		UptimeSeconds:    int64(gs.clock.Since(gs.startedAt).Seconds()),
+		Connections:      usage.Connections,
+		ActiveGames:      usage.ActiveGames,
+		Queued:           usage.Queued,
+		QueueLength:      queueLength,
+		BroadcastBacklog: len(gs.broadcast),
+		BroadcastCap:     cap(gs.broadcast),
+		HubBacklog:       gs.hub.backlog(),
+		Storage:          "ok",
+		ShuttingDown:     shuttingDown,
+		Draining:         draining,
+	}
+
+	if checkStorage {
+		ctx, cancel := context.WithTimeout(ctx, storagePingTimeout)
+		defer cancel()
+		if err := gs.store.Ping(ctx); err != nil {
+			report.Storage = err.Error()
+		}
+	}
+	return report
+}
+
+// HandleLiveness serves GET /healthz: the process is up and serving HTTP
+func (gs *GameServer) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, gs.healthReport(r.Context(), false))
+}
+
+// HandleReadiness serves GET /readyz: 503 while shutting down or draining, or when storage is unreachable
+func (gs *GameServer) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := gs.healthReport(r.Context(), true)
+	status := http.StatusOK
+	if report.ShuttingDown || report.Draining || report.Storage != "ok" {
+		report.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// HandleHealth serves GET /health with current utilization
+func (gs *GameServer) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	var usage utilization
+	gs.do(func() { usage = gs.utilization() })
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":      "ok",
+		"utilization": usage,
+	})
+}
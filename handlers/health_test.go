@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// probe calls a health handler and decodes its report
+func probe(t *testing.T, handler http.HandlerFunc) (int, healthReport) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var report healthReport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	return recorder.Code, report
+}
+
+func TestReadinessFollowsShutdown(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, testConfig())
+	dialTestClient(t, wsURL, "name=alice")
+	clk.Advance(90 * time.Second)
+
+	code, report := probe(t, gs.HandleReadiness)
+	if code != http.StatusOK || report.Status != "ok" || report.Storage != "ok" {
+		t.Fatalf("readiness = %d %+v", code, report)
+	}
+	if report.UptimeSeconds != 90 || report.Connections != 1 || report.BroadcastCap == 0 {
+		t.Errorf("report = %+v", report)
+	}
+
+	gs.Shutdown()
+
+	code, report = probe(t, gs.HandleReadiness)
+	if code != http.StatusServiceUnavailable || !report.ShuttingDown {
+		t.Errorf("readiness during shutdown = %d %+v, want 503", code, report)
+	}
+	if code, _ := probe(t, gs.HandleLiveness); code != http.StatusOK {
+		t.Errorf("liveness during shutdown = %d, want 200", code)
+	}
+}
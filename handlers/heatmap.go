@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"tictactoe-server/models"
+)
+
+// heatmapBoard identifies the games one heatmap aggregates
+type heatmapBoard struct {
+	size    int
+	variant string
+}
+
+// aggregateHeatmaps rebuilds the move heatmaps from every finished game, then waits HeatmapInterval to do it again
+func (gs *GameServer) aggregateHeatmaps() {
+	report := buildHeatmaps(gs.store.AllGames())
+	report.GeneratedAt = gs.clock.Now()
+	gs.store.SaveHeatmaps(report)
+	log.Printf("Aggregated move heatmaps for %d boards", len(report.Boards))
+
+	gs.heatmapTimer = gs.clock.AfterFunc(gs.config.HeatmapInterval, gs.doLater(gs.aggregateHeatmaps))
+}
+
+// buildHeatmaps counts where the moves of decided games were played, and how games went by the cell they opened on
+// Games recorded without their moves, and games that ended without a result, are left out
+func buildHeatmaps(records []*models.GameRecord) *models.HeatmapReport {
+	heatmaps := make(map[heatmapBoard]*models.MoveHeatmap)
+	for _, record := range records {
+		if len(record.Moves) == 0 || record.Size == 0 || record.Winner == models.RESULT_NONE {
+			continue
+		}
+		board := heatmapBoard{size: record.Size, variant: record.Variant}
+		if board.variant == "" {
+			board.variant = models.VARIANT_STANDARD
+		}
+		heatmap, exists := heatmaps[board]
+		if !exists {
+			heatmap = &models.MoveHeatmap{Size: board.size, Variant: board.variant, Positions: make([]models.PositionStats, board.size*board.size)}
+			for i := range heatmap.Positions {
+				heatmap.Positions[i].Position = i
+			}
+			heatmaps[board] = heatmap
+		}
+
+		heatmap.Games++
+		draw := record.Winner == models.RESULT_DRAW
+		if draw {
+			heatmap.Draws++
+		}
+		for _, position := range record.Moves {
+			if position >= 0 && position < len(heatmap.Positions) {
+				heatmap.Positions[position].Played++
+			}
+		}
+		if opening := record.Moves[0]; opening >= 0 && opening < len(heatmap.Positions) {
+			stats := &heatmap.Positions[opening]
+			stats.FirstMoves++
+			switch {
+			case draw:
+				stats.FirstMoveDraws++
+			case record.Winner == models.WinFor(models.SYMBOL_X):
+				stats.FirstMoveWins++
+			}
+		}
+	}
+
+	report := &models.HeatmapReport{Boards: make([]*models.MoveHeatmap, 0, len(heatmaps))}
+	for _, heatmap := range heatmaps {
+		heatmap.DrawRate = float64(heatmap.Draws) / float64(heatmap.Games)
+		for i := range heatmap.Positions {
+			stats := &heatmap.Positions[i]
+			stats.PlayRate = float64(stats.Played) / float64(heatmap.Games)
+			if stats.FirstMoves > 0 {
+				stats.FirstMoveWinRate = float64(stats.FirstMoveWins) / float64(stats.FirstMoves)
+			}
+		}
+		report.Boards = append(report.Boards, heatmap)
+	}
+	sort.Slice(report.Boards, func(i, j int) bool {
+		if report.Boards[i].Size != report.Boards[j].Size {
+			return report.Boards[i].Size < report.Boards[j].Size
+		}
+		return report.Boards[i].Variant < report.Boards[j].Variant
+	})
+	return report
+}
+
+// handleHeatmapAPI serves GET /api/stats/heatmap, the latest move heatmaps
+// ?size= and ?variant= narrow the answer to matching boards
+func (gs *GameServer) handleHeatmapAPI(w http.ResponseWriter, r *http.Request) {
+	if gs.config.HeatmapInterval <= 0 {
+		http.Error(w, "heatmap disabled", http.StatusNotFound)
+		return
+	}
+
+	size := 0
+	if value := r.URL.Query().Get("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			http.Error(w, "invalid size", http.StatusBadRequest)
+			return
+		}
+		size = parsed
+	}
+	variant := r.URL.Query().Get("variant")
+
+	// Answers before the first aggregation are empty rather than an error
+	latest := gs.store.Heatmaps()
+	if latest == nil {
+		latest = &models.HeatmapReport{}
+	}
+	report := &models.HeatmapReport{GeneratedAt: latest.GeneratedAt, Boards: make([]*models.MoveHeatmap, 0)}
+	for _, heatmap := range latest.Boards {
+		if (size == 0 || heatmap.Size == size) && (variant == "" || heatmap.Variant == variant) {
+			report.Boards = append(report.Boards, heatmap)
+		}
+	}
+	writeJSON(w, http.StatusOK, report)
+}
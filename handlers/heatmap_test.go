@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func getHeatmaps(t *testing.T, gs *GameServer, query string) (int, models.HeatmapReport) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	gs.HandleStatsAPI(recorder, httptest.NewRequest(http.MethodGet, "/api/stats/heatmap"+query, nil))
+	var report models.HeatmapReport
+	if recorder.Code == http.StatusOK {
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return recorder.Code, report
+}
+
+func TestHeatmapAggregatesFinishedGames(t *testing.T) {
+	cfg := testConfig()
+	cfg.HeatmapInterval = time.Hour
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	if code, report := getHeatmaps(t, gs, ""); code != http.StatusOK || len(report.Boards) != 0 {
+		t.Fatalf("before aggregating: %d %+v, want an empty report", code, report)
+	}
+
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+	playTopRowWin(t, x, o, gameID)
+	gs.store.SaveGame(&models.GameRecord{Winner: models.RESULT_DRAW, Size: 3, Variant: models.VARIANT_STANDARD,
+		Moves: []int{4, 0, 8, 2, 1, 7, 6, 3, 5}, EndTime: clk.Now()})
+	gs.store.SaveGame(&models.GameRecord{Winner: models.RESULT_NONE, Size: 3, Variant: models.VARIANT_STANDARD,
+		Moves: []int{4}, EndTime: clk.Now()})
+	gs.do(gs.aggregateHeatmaps)
+
+	_, report := getHeatmaps(t, gs, "")
+	if len(report.Boards) != 1 {
+		t.Fatalf("boards = %+v, want one", report.Boards)
+	}
+	board := report.Boards[0]
+	if board.Size != 3 || board.Variant != models.VARIANT_STANDARD || board.Games != 2 || board.Draws != 1 || board.DrawRate != 0.5 {
+		t.Errorf("board = %+v", board)
+	}
+	corner, center := board.Positions[0], board.Positions[4]
+	if corner.Played != 2 || corner.PlayRate != 1 || corner.FirstMoves != 1 || corner.FirstMoveWins != 1 || corner.FirstMoveWinRate != 1 {
+		t.Errorf("top left = %+v, want played in both games and a winning opening", corner)
+	}
+	if center.FirstMoves != 1 || center.FirstMoveDraws != 1 || center.FirstMoveWinRate != 0 {
+		t.Errorf("center = %+v, want one drawn opening", center)
+	}
+
+	if _, filtered := getHeatmaps(t, gs, "?size=5"); len(filtered.Boards) != 0 {
+		t.Errorf("size=5 boards = %+v, want none", filtered.Boards)
+	}
+
+	// The next aggregation picks up games finished since
+	gs.store.SaveGame(&models.GameRecord{Winner: models.WinFor(models.SYMBOL_O), Size: 3, Variant: models.VARIANT_STANDARD,
+		Moves: []int{8, 4}, EndTime: clk.Now()})
+	clk.Advance(time.Hour)
+	var games int
+	gs.do(func() { games = gs.store.Heatmaps().Boards[0].Games })
+	if games != 3 {
+		t.Errorf("games after the next aggregation = %d, want 3", games)
+	}
+}
+
+func TestHeatmapDisabled(t *testing.T) {
+	gs, _, _ := newTestServer(t, testConfig())
+
+	if code, _ := getHeatmaps(t, gs, ""); code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 with no heatmap interval", code)
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import "tictactoe-server/models"
+
+// handleRequestHint tells a player the best move in a casual or bot game without playing it
+func (gs *GameServer) handleRequestHint(conn clientConn, player *models.Player, request *models.GamePayload) {
+	if gs.config.HintsPerGame <= 0 {
+		gs.sendClientError(conn, models.ERR_DISABLED, "Hints are disabled")
+		return
+	}
+
+	gameInstance, exists := gs.games.Game(request.GameID)
+	if !exists || !isPlayerInGame(gameInstance, player.ID) {
+		gs.sendClientError(conn, models.ERR_GAME_NOT_FOUND, "Game not found")
+		return
+	}
+
+	if gameInstance.Rated {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Hints are not allowed in rated games")
+		return
+	}
+
+	if !gameInstance.IsClassic() {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Hints are only given in two-player games on a 3x3 board")
+		return
+	}
+
+	if gameInstance.Status != models.STATUS_PLAYING {
+		gs.sendClientError(conn, models.ERR_NOT_PLAYING, "Game is not in playing state")
+		return
+	}
+
+	symbol := gameInstance.SideOf(player.ID)
+	if gameInstance.CurrentTurn != symbol {
+		gs.sendClientError(conn, models.ERR_NOT_YOUR_TURN, "Hints are only given on your turn")
+		return
+	}
+
+	key := gameInstance.ID + "/" + player.ID
+	if gs.hintsUsed[key] >= gs.config.HintsPerGame {
+		gs.sendClientError(conn, models.ERR_LIMIT_REACHED, "No hints left in this game")
+		return
+	}
+	gs.hintsUsed[key]++
+
+	position := gs.gameEngine.BestMove(gameInstance.ClassicBoard(), symbol, gameInstance.Variant)
+	gs.logEvent(gameInstance.ID, models.EVENT_HINT, player.ID, map[string]interface{}{"position": position})
+	gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_HINT, gameInstance.ID, models.Hint{
+		GameID:    gameInstance.ID,
+		Position:  position,
+		HintsLeft: gs.config.HintsPerGame - gs.hintsUsed[key],
+	}))
+}
+
+// forgetHints drops hint counts for a game leaving memory
+func (gs *GameServer) forgetHints(gameInstance *models.Game) {
+	for _, player := range gameInstance.AllPlayers() {
+		delete(gs.hintsUsed, gameInstance.ID+"/"+player.ID)
+	}
+}
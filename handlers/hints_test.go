@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestHintsInCasualGames(t *testing.T) {
+	cfg := testConfig()
+	cfg.HintsPerGame = 2
+	_, _, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	// O has to block the top row
+	playMove(t, x, x, o, gameID, 0)
+	playMove(t, o, x, o, gameID, 4)
+	playMove(t, x, x, o, gameID, 1)
+
+	if text := refusedHint(x, gameID); text != "Hints are only given on your turn" {
+		t.Errorf("hint off turn: %q", text)
+	}
+
+	for want := 1; want >= 0; want-- {
+		o.send(models.MSG_REQUEST_HINT, models.GamePayload{GameID: gameID})
+		var hint models.Hint
+		o.expect(models.MSG_HINT, &hint)
+		if hint.GameID != gameID || hint.Position != 2 || hint.HintsLeft != want {
+			t.Errorf("hint = %+v, want position 2 with %d left", hint, want)
+		}
+	}
+
+	if text := refusedHint(o, gameID); text != "No hints left in this game" {
+		t.Errorf("hint over the limit: %q", text)
+	}
+}
+
+func TestHintsRefusedInRatedGames(t *testing.T) {
+	cfg := testConfig()
+	cfg.HintsPerGame = 2
+	_, _, wsURL := newTestServer(t, cfg)
+	x, _, gameID := startRatedGame(t, wsURL)
+
+	if text := refusedHint(x, gameID); text != "Hints are not allowed in rated games" {
+		t.Errorf("rated hint: %q", text)
+	}
+}
+
+// refusedHint requests a hint and returns the error it was refused with
+func refusedHint(c *testClient, gameID string) string {
+	c.t.Helper()
+	c.send(models.MSG_REQUEST_HINT, models.GamePayload{GameID: gameID})
+	var body struct {
+		Error string `json:"error"`
+	}
+	c.expect(models.MSG_ERROR, &body)
+	return body.Error
+}
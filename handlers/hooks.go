@@ -0,0 +1,64 @@
+package handlers
+
+import "tictactoe-server/models"
+
+// serverHooks lets subsystems react to server events without the game loop knowing about them
+// Hooks run on the hub in registration order; one that needs to block should hand the work to its own goroutine
+type serverHooks struct {
+	gameStarted      []func(*models.Game)
+	gameFinished     []func(*models.Game)
+	seasonEnded      []func(*models.SeasonArchive)
+	playerRegistered []func(*models.Player)
+}
+
+// OnGameStarted registers a hook called when a game has been seated and its first turn is about to be played
+// Register hooks before Run
+func (gs *GameServer) OnGameStarted(hook func(*models.Game)) {
+	gs.hooks.gameStarted = append(gs.hooks.gameStarted, hook)
+}
+
+// OnGameFinished registers a hook called after a game ends with a result and has been recorded
+// Register hooks before Run
+func (gs *GameServer) OnGameFinished(hook func(*models.Game)) {
+	gs.hooks.gameFinished = append(gs.hooks.gameFinished, hook)
+}
+
+// OnSeasonEnded registers a hook called after a season is archived and ratings are reset
+// Register hooks before Run
+func (gs *GameServer) OnSeasonEnded(hook func(*models.SeasonArchive)) {
+	gs.hooks.seasonEnded = append(gs.hooks.seasonEnded, hook)
+}
+
+// OnPlayerRegistered registers a hook called when a new player connects for the first time
+// Register hooks before Run
+func (gs *GameServer) OnPlayerRegistered(hook func(*models.Player)) {
+	gs.hooks.playerRegistered = append(gs.hooks.playerRegistered, hook)
+}
+
+// fireGameStarted runs the game started hooks
+func (gs *GameServer) fireGameStarted(gameInstance *models.Game) {
+	for _, hook := range gs.hooks.gameStarted {
+		hook(gameInstance)
+	}
+}
+
+// fireGameFinished runs the game finished hooks
+func (gs *GameServer) fireGameFinished(gameInstance *models.Game) {
+	for _, hook := range gs.hooks.gameFinished {
+		hook(gameInstance)
+	}
+}
+
+// fireSeasonEnded runs the season ended hooks
+func (gs *GameServer) fireSeasonEnded(archive *models.SeasonArchive) {
+	for _, hook := range gs.hooks.seasonEnded {
+		hook(archive)
+	}
+}
+
+// firePlayerRegistered runs the player registered hooks
+func (gs *GameServer) firePlayerRegistered(player *models.Player) {
+	for _, hook := range gs.hooks.playerRegistered {
+		hook(player)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"tictactoe-server/models"
+)
+
+// The server's state (clients, queues, games, players and everything tracked alongside them) is owned by
+// a single hub goroutine. Connection readers, timers, tickers and HTTP handlers never touch it themselves:
+// they hand the hub an event and wait until it has been handled. Code running on the hub calls other
+// handlers directly and must never wait on the hub, or it would deadlock.
+
+// hubQueueSize is how many events of each kind may wait for the hub before senders block
+const hubQueueSize = 256
+
+// hubEvents are the channels feeding the hub, one per kind of event
+type hubEvents struct {
+	register   chan *registration
+	unregister chan *departure
+	inbound    chan *inboundMessage
+	tick       chan *task // Timers, periodic jobs and HTTP handlers that need the server state
+}
+
+func newHubEvents() hubEvents {
+	return hubEvents{
+		register:   make(chan *registration, hubQueueSize),
+		unregister: make(chan *departure, hubQueueSize),
+		inbound:    make(chan *inboundMessage, hubQueueSize),
+		tick:       make(chan *task, hubQueueSize),
+	}
+}
+
+// backlog counts events waiting for the hub
+func (h hubEvents) backlog() int {
+	return len(h.register) + len(h.unregister) + len(h.inbound) + len(h.tick)
+}
+
+// registration asks the hub to attach a new connection to a player
+type registration struct {
+	conn      clientConn
+	clientIP  string
+	userAgent string
+	name      string // Already validated and moderated
+	nameErr   error  // Why the requested name was refused, if it was
+	playerID  string
+	token     string
+	deviceKey string // Already validated; empty if none was sent or it was refused
+	keyErr    error  // Why the device key was refused, if it was
+	region    string // Self-declared region, already normalized; empty keeps the player's current one
+	version   int
+
+	player *models.Player // Set by the hub; nil if the connection was refused
+	done   chan struct{}
+}
+
+// departure asks the hub to clean up after a closed connection
+type departure struct {
+	conn clientConn
+	done chan struct{}
+}
+
+// inboundMessage is a message a client sent
+type inboundMessage struct {
+	ctx  context.Context
+	conn clientConn
+	msg  *models.GameMessage
+	done chan struct{}
+}
+
+// task is any other work on the server state
+type task struct {
+	fn   func()
+	done chan struct{}
+}
+
+// runHub handles events one at a time until the process exits
+func (gs *GameServer) runHub() {
+	for {
+		select {
+		case r := <-gs.hub.register:
+			gs.handleEvent(func() { r.player = gs.registerClient(r) }, r.done, nil)
+		case d := <-gs.hub.unregister:
+			gs.handleEvent(func() { gs.handleDisconnect(d.conn) }, d.done, nil)
+		case in := <-gs.hub.inbound:
+			gs.handleEvent(func() { gs.handleMessage(in.ctx, in.conn, in.msg) }, in.done, func() { gs.dropGameAfterPanic(in.conn) })
+		case t := <-gs.hub.tick:
+			gs.handleEvent(t.fn, t.done, nil)
+		}
+	}
+}
+
+// handleEvent runs one event and releases its sender; a panicking handler is logged rather than
+// taking every game on the server down with it, and onPanic, if set, cleans up after it
+func (gs *GameServer) handleEvent(fn func(), done chan struct{}, onPanic func()) {
+	defer close(done)
+	if !runSafely(fn) && onPanic != nil {
+		runSafely(onPanic)
+	}
+}
+
+// runSafely runs fn, logging a panic instead of propagating it; reports whether fn returned normally
+func runSafely(fn func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Hub event panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	fn()
+	return true
+}
+
+// register attaches a connection to a player on the hub
+// Returns nil if the connection was refused. Called from the connection's goroutine
+func (gs *GameServer) register(conn clientConn, clientIP, userAgent, playerName, playerID, token, deviceKey, region string, version int) *models.Player {
+	// Moderation may call out to another service, so it runs here rather than stalling the hub
+	name, nameErr := gs.playerNameFor(playerName)
+	var keyErr error
+	if deviceKey != "" {
+		if keyErr = models.ValidateDeviceKey(deviceKey); keyErr != nil {
+			deviceKey = ""
+		}
+	}
+
+	r := &registration{
+		conn:      conn,
+		clientIP:  clientIP,
+		userAgent: userAgent,
+		name:      name,
+		nameErr:   nameErr,
+		playerID:  playerID,
+		token:     token,
+		deviceKey: deviceKey,
+		keyErr:    keyErr,
+		region:    normalizeRegion(region),
+		version:   version,
+		done:      make(chan struct{}),
+	}
+	gs.hub.register <- r
+	<-r.done
+	return r.player
+}
+
+// unregister runs disconnect cleanup for a connection on the hub
+func (gs *GameServer) unregister(conn clientConn) {
+	d := &departure{conn: conn, done: make(chan struct{})}
+	gs.hub.unregister <- d
+	<-d.done
+}
+
+// receive hands a client's message to the hub and waits until it has been handled,
+// so each connection's messages are handled in the order they were sent
+func (gs *GameServer) receive(ctx context.Context, conn clientConn, msg *models.GameMessage) {
+	in := &inboundMessage{ctx: ctx, conn: conn, msg: msg, done: make(chan struct{})}
+	gs.hub.inbound <- in
+	<-in.done
+}
+
+// do runs fn on the hub and waits for it to finish
+// Never call it from the hub itself
+func (gs *GameServer) do(fn func()) {
+	t := &task{fn: fn, done: make(chan struct{})}
+	gs.hub.tick <- t
+	<-t.done
+}
+
+// doLater returns a function that runs fn on the hub, for timers and other callbacks fired off the hub
+func (gs *GameServer) doLater(fn func()) func() {
+	return func() { gs.do(fn) }
+}
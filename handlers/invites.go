@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+)
+
+// inviteTokenBytes is how much randomness goes into an invite token
+const inviteTokenBytes = 18
+
+// invite is an invite link waiting to be followed
+type invite struct {
+	models.Invite
+	expiry clock.Timer
+}
+
+// HandleInvitesAPI serves POST /api/invites, creating an invite link for the player whose session is given
+// Body: {"playerId": ..., "token": ..., "mode": "casual"|"rated"}; mode defaults to casual
+func (gs *GameServer) HandleInvitesAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		PlayerID string `json:"playerId"`
+		Token    string `json:"token"`
+		Mode     string `json:"mode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if body.Mode == "" {
+		body.Mode = models.MODE_CASUAL
+	}
+	if body.Mode != models.MODE_CASUAL && body.Mode != models.MODE_RATED {
+		http.Error(w, "mode must be casual or rated", http.StatusBadRequest)
+		return
+	}
+
+	token, err := newInviteToken()
+	if err != nil {
+		log.Printf("Failed to generate invite token: %v", err)
+		http.Error(w, "could not create invite", http.StatusInternalServerError)
+		return
+	}
+
+	scheme := "ws"
+	if r.TLS != nil {
+		scheme = "wss"
+	}
+
+	var created *models.Invite
+	gs.do(func() {
+		inviter := gs.sessionPlayer(body.PlayerID, body.Token)
+		if inviter == nil {
+			return
+		}
+		created = gs.createInvite(inviter, token, body.Mode)
+		created.URL = scheme + "://" + r.Host + "/ws?invite=" + token
+	})
+	if created == nil {
+		http.Error(w, "invalid session", http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// newInviteToken returns a random URL-safe token
+func newInviteToken() (string, error) {
+	raw := make([]byte, inviteTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// createInvite registers an invite from a player, replacing any they already had out
+func (gs *GameServer) createInvite(inviter *models.Player, token, mode string) *models.Invite {
+	for _, existing := range gs.invites {
+		if existing.InviterID == inviter.ID {
+			gs.closeInvite(existing, models.INVITE_REPLACED, "", "")
+		}
+	}
+
+	inv := &invite{Invite: models.Invite{
+		Token:     token,
+		InviterID: inviter.ID,
+		Mode:      mode,
+		ExpiresAt: gs.clock.Now().Add(gs.config.InviteTTL),
+	}}
+	inv.expiry = gs.clock.AfterFunc(gs.config.InviteTTL, gs.doLater(func() {
+		if gs.invites[token] == inv {
+			gs.closeInvite(inv, models.INVITE_EXPIRED, "", "")
+		}
+	}))
+	gs.invites[token] = inv
+
+	log.Printf("Player %s (%s) created a %s invite", inviter.Name, inviter.ID, mode)
+	gs.notifyInviter(inv, models.INVITE_PENDING, "", "")
+
+	created := inv.Invite
+	return &created
+}
+
+// acceptInvite starts a game between a newly connected player and the creator of the invite they followed
+// The invite stays valid if the game cannot start yet, e.g. because the creator is offline
+func (gs *GameServer) acceptInvite(conn clientConn, player *models.Player, token string) {
+	inv, exists := gs.invites[token]
+	if !exists {
+		gs.sendClientError(conn, models.ERR_INVITE_NOT_FOUND, "Invite not found or expired")
+		return
+	}
+	if inv.InviterID == player.ID {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot accept your own invite")
+		return
+	}
+	if gs.eitherBlocked(inv.InviterID, player.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot accept this invite")
+		return
+	}
+	if gs.newGamesPaused() {
+		gs.sendClientError(conn, models.ERR_MAINTENANCE, "New games are paused on this server, invites can't be accepted")
+		return
+	}
+
+	inviter, exists := gs.players.Player(inv.InviterID)
+	if !exists || len(gs.playerConns[inviter.ID]) == 0 {
+		gs.sendClientError(conn, models.ERR_PLAYER_OFFLINE, "The player who invited you is not online")
+		return
+	}
+	if gs.activeGameForPlayer(inviter.ID) != nil {
+		gs.sendClientError(conn, models.ERR_ALREADY_IN_GAME, "The player who invited you is already in a game")
+		return
+	}
+	if gs.activeGameForPlayer(player.ID) != nil {
+		gs.sendClientError(conn, models.ERR_ALREADY_IN_GAME, "You are already in a game")
+		return
+	}
+	if gs.atGameLimit() {
+		gs.sendServerFull(player.ID, models.FULL_GAMES, "The server is at its game limit, follow the invite again shortly")
+		return
+	}
+
+	// The invite is spent once the game starts
+	gs.removeFromQueue(inviter.ID)
+	gs.removeFromQueue(player.ID)
+	gs.withdrawFromMatch(inviter.ID)
+	gs.withdrawFromMatch(player.ID)
+	gs.recordPairing(inviter.ID, player.ID)
+	newGame := gs.startMatch(inv.Mode, inviter, player)
+	gs.closeInvite(inv, models.INVITE_ACCEPTED, newGame.ID, player.Name)
+}
+
+// closeInvite retires an invite and tells its creator why
+func (gs *GameServer) closeInvite(inv *invite, status, gameID, inviteeName string) {
+	inv.expiry.Stop()
+	delete(gs.invites, inv.Token)
+	gs.notifyInviter(inv, status, gameID, inviteeName)
+}
+
+// notifyInviter sends an invite_status to the invite's creator if they are connected
+func (gs *GameServer) notifyInviter(inv *invite, status, gameID, inviteeName string) {
+	if len(gs.playerConns[inv.InviterID]) == 0 {
+		return
+	}
+	gs.sendToPlayer(inv.InviterID, models.NewGameMessage(models.MSG_INVITE_STATUS, models.InviteStatus{
+		Token:       inv.Token,
+		Status:      status,
+		GameID:      gameID,
+		InviteeName: inviteeName,
+	}))
+}
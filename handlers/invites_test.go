@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// createInvite posts to /api/invites for a client's session and returns the response
+func createInvite(gs *GameServer, c *testClient, mode string) (int, models.Invite) {
+	body, _ := json.Marshal(map[string]string{"playerId": c.playerID, "token": c.token, "mode": mode})
+	recorder := httptest.NewRecorder()
+	gs.HandleInvitesAPI(recorder, httptest.NewRequest(http.MethodPost, "/api/invites", strings.NewReader(string(body))))
+
+	var created models.Invite
+	json.Unmarshal(recorder.Body.Bytes(), &created)
+	return recorder.Code, created
+}
+
+func TestInviteStartsGameWithCreator(t *testing.T) {
+	cfg := testConfig()
+	cfg.InviteTTL = time.Minute
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	code, created := createInvite(gs, alice, "")
+	if code != http.StatusCreated || created.Token == "" || created.Mode != models.MODE_CASUAL ||
+		!strings.HasSuffix(created.URL, "/ws?invite="+created.Token) {
+		t.Fatalf("create invite = %d %+v", code, created)
+	}
+	var status models.InviteStatus
+	alice.expect(models.MSG_INVITE_STATUS, &status)
+	if status.Status != models.INVITE_PENDING {
+		t.Errorf("status after creating = %+v", status)
+	}
+
+	bob := dialTestClient(t, wsURL, "name=bob&invite="+created.Token)
+	var aliceState, bobState testGameState
+	alice.expect(models.MSG_GAME_FOUND, &aliceState)
+	bob.expect(models.MSG_GAME_FOUND, &bobState)
+	if aliceState.GameID != bobState.GameID {
+		t.Fatalf("alice and bob in different games: %s vs %s", aliceState.GameID, bobState.GameID)
+	}
+
+	alice.expect(models.MSG_INVITE_STATUS, &status)
+	if status.Status != models.INVITE_ACCEPTED || status.GameID != aliceState.GameID || status.InviteeName != "bob" {
+		t.Errorf("status after accepting = %+v", status)
+	}
+
+	// The link only works once
+	carol := dialTestClient(t, wsURL, "name=carol&invite="+created.Token)
+	var body struct {
+		Error string `json:"error"`
+	}
+	carol.expect(models.MSG_ERROR, &body)
+	if body.Error != "Invite not found or expired" {
+		t.Errorf("reused invite: %q", body.Error)
+	}
+}
+
+func TestInviteExpires(t *testing.T) {
+	cfg := testConfig()
+	cfg.InviteTTL = time.Minute
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	if code, _ := createInvite(gs, &testClient{playerID: alice.playerID, token: "wrong"}, ""); code != http.StatusUnauthorized {
+		t.Errorf("invite with a bad session = %d", code)
+	}
+
+	_, created := createInvite(gs, alice, models.MODE_RATED)
+	alice.expect(models.MSG_INVITE_STATUS, nil)
+
+	clk.Advance(cfg.InviteTTL)
+	var status models.InviteStatus
+	alice.expect(models.MSG_INVITE_STATUS, &status)
+	if status.Token != created.Token || status.Status != models.INVITE_EXPIRED {
+		t.Errorf("status after expiry = %+v", status)
+	}
+
+	bob := dialTestClient(t, wsURL, "name=bob&invite="+created.Token)
+	bob.expect(models.MSG_ERROR, nil)
+}
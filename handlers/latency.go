@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	latencyProbeInterval = 15 * time.Second // How often connections are pinged to measure their round trip
+	maxRegionLength      = 32
+)
+
+// latencyProber is a connection that can measure its round trip, which only WebSocket connections do
+type latencyProber interface {
+	Ping() error
+}
+
+// runLatencyProbe periodically pings every connection that supports it and drops AFK players from the queues
+func (gs *GameServer) runLatencyProbe() {
+	ticker := gs.clock.NewTicker(latencyProbeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		gs.do(func() {
+			gs.dropAFKQueuedPlayers()
+			gs.probeLatency()
+		})
+	}
+}
+
+// probeLatency pings every connection that can report its round trip
+func (gs *GameServer) probeLatency() {
+	for conn := range gs.clients {
+		if prober, ok := conn.(latencyProber); ok {
+			prober.Ping()
+		}
+	}
+}
+
+// measurePongs records the round trip each time the client answers a ping
+// Pongs are handled by the connection's reader while it waits for the next message
+func (gs *GameServer) measurePongs(wsConn *websocket.Conn, conn clientConn) {
+	wsConn.SetPongHandler(func(data string) error {
+		sent, err := strconv.ParseInt(data, 10, 64)
+		if err != nil {
+			return nil
+		}
+		// Network round trips are real time even when the server runs on a fake clock
+		rtt := time.Since(time.Unix(0, sent))
+		gs.do(func() { gs.recordLatency(conn, rtt) })
+		return nil
+	})
+}
+
+// recordLatency stores a connection's round trip on its player and tells the player their ping
+func (gs *GameServer) recordLatency(conn clientConn, rtt time.Duration) {
+	player, exists := gs.clients[conn]
+	if !exists {
+		return
+	}
+	gs.lastPong[conn] = gs.clock.Now()
+	player.LatencyMs = int((rtt + time.Millisecond - 1) / time.Millisecond)
+	if player.LatencyMs < 1 {
+		player.LatencyMs = 1
+	}
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_PLAYER_UPDATE, player))
+}
+
+// normalizeRegion lower-cases a self-declared region, returning "" for anything but letters, digits and dashes
+func normalizeRegion(region string) string {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if len(region) > maxRegionLength {
+		return ""
+	}
+	for _, r := range region {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return ""
+		}
+	}
+	return region
+}
+
+// matchAffinity scores how well two players suit each other for responsive turns:
+// 2 for the same declared region, plus 1 when both round trips are measured and within the tolerance
+func (gs *GameServer) matchAffinity(a, b *models.Player) int {
+	score := 0
+	if a.Region != "" && a.Region == b.Region {
+		score += 2
+	}
+	if tolerance := gs.config.MatchLatencyTolerance; tolerance > 0 && a.LatencyMs > 0 && b.LatencyMs > 0 {
+		diff := a.LatencyMs - b.LatencyMs
+		if diff < 0 {
+			diff = -diff
+		}
+		if time.Duration(diff)*time.Millisecond <= tolerance {
+			score++
+		}
+	}
+	return score
+}
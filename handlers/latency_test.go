@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestLatencyReportedInPlayerUpdate(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice&region=EU-West")
+
+	var update models.Player
+	alice.expect(models.MSG_PLAYER_UPDATE, &update)
+	if update.Region != "eu-west" || update.LatencyMs != 0 {
+		t.Fatalf("player_update on connect = region %q, latency %d", update.Region, update.LatencyMs)
+	}
+
+	// The client answers the ping while it waits for the next message
+	gs.do(gs.probeLatency)
+	alice.expect(models.MSG_PLAYER_UPDATE, &update)
+	if update.LatencyMs <= 0 {
+		t.Errorf("latency after a ping = %d", update.LatencyMs)
+	}
+}
+
+func TestNormalizeRegion(t *testing.T) {
+	for region, want := range map[string]string{
+		" US-East ": "us-east",
+		"ap1":       "ap1",
+		"eu west":   "",
+		"<script>":  "",
+	} {
+		if got := normalizeRegion(region); got != want {
+			t.Errorf("normalizeRegion(%q) = %q, want %q", region, got, want)
+		}
+	}
+}
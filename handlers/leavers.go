@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// leaverCooldowns are the queue cooldowns for leaving the threshold number of games, one more, and any more after that
+var leaverCooldowns = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute}
+
+// leaverRecord is a player's recent departures from games and the cooldown they earned
+type leaverRecord struct {
+	leftAt        []time.Time // Games left within the window, oldest first
+	cooldownUntil time.Time
+}
+
+// recordLeave counts a game a player left and starts a queue cooldown once they have left too many
+func (gs *GameServer) recordLeave(player *models.Player, gameID string) {
+	threshold := gs.config.LeaverPenaltyThreshold
+	if threshold <= 0 || player.IsBot {
+		return
+	}
+
+	record, exists := gs.leavers[player.ID]
+	if !exists {
+		record = &leaverRecord{}
+		gs.leavers[player.ID] = record
+	}
+	now := gs.clock.Now()
+	record.leftAt = append(gs.recentLeaves(record), now)
+	if len(record.leftAt) < threshold {
+		return
+	}
+
+	cooldown := leaverCooldowns[min(len(record.leftAt)-threshold, len(leaverCooldowns)-1)]
+	record.cooldownUntil = now.Add(cooldown)
+	log.Printf("Player %s left game %s, %d games left within %v; queue cooldown %v", player.ID, gameID, len(record.leftAt), gs.config.LeaverWindow, cooldown)
+}
+
+// recentLeaves returns the games a player left that still count toward cooldowns
+func (gs *GameServer) recentLeaves(record *leaverRecord) []time.Time {
+	cutoff := gs.clock.Now().Add(-gs.config.LeaverWindow)
+	for len(record.leftAt) > 0 && !record.leftAt[0].After(cutoff) {
+		record.leftAt = record.leftAt[1:]
+	}
+	return record.leftAt
+}
+
+// queuePenalty returns the cooldown a player is serving, if any
+func (gs *GameServer) queuePenalty(playerID string) (models.QueuePenalty, bool) {
+	record, exists := gs.leavers[playerID]
+	if !exists || !gs.clock.Now().Before(record.cooldownUntil) {
+		return models.QueuePenalty{}, false
+	}
+	return models.QueuePenalty{
+		RemainingMs:  record.cooldownUntil.Sub(gs.clock.Now()).Milliseconds(),
+		Until:        record.cooldownUntil,
+		RecentLeaves: len(gs.recentLeaves(record)),
+	}, true
+}
+
+// sendQueuePenalty tells a player why they can't queue yet; clients predating queue_penalty get an error
+func (gs *GameServer) sendQueuePenalty(playerID string, penalty models.QueuePenalty) {
+	for conn := range gs.playerConns[playerID] {
+		if models.SupportsMessage(gs.clientVersion(conn), models.MSG_QUEUE_PENALTY) {
+			gs.sendToClient(conn, models.NewGameMessage(models.MSG_QUEUE_PENALTY, penalty))
+		} else {
+			gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You left too many games recently, try again later")
+		}
+	}
+}
+
+// pruneLeavers forgets players with no recent leaves and no cooldown left
+func (gs *GameServer) pruneLeavers() {
+	for playerID, record := range gs.leavers {
+		if len(gs.recentLeaves(record)) == 0 && !gs.clock.Now().Before(record.cooldownUntil) {
+			delete(gs.leavers, playerID)
+		}
+	}
+}
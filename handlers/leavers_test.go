@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestLeaversServeEscalatingCooldowns(t *testing.T) {
+	cfg := testConfig()
+	cfg.DisconnectGracePeriod = 10 * time.Second
+	cfg.LeaverPenaltyThreshold = 2
+	cfg.LeaverWindow = time.Hour
+	_, clk, wsURL := newTestServer(t, cfg)
+	leaver := dialTestClient(t, wsURL, "name=leaver")
+
+	// leaveGame matches the leaver with a new opponent and forfeits by staying away
+	leaveGame := func() {
+		t.Helper()
+		opponent := dialTestClient(t, wsURL, "name=opponent")
+		opponent.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+		opponent.expect(models.MSG_QUEUE_JOINED, nil)
+		leaver.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+		var state testGameState
+		opponent.expect(models.MSG_GAME_FOUND, &state)
+
+		timers := clk.PendingTimers()
+		leaver.conn.Close()
+		waitForTimers(t, clk, timers+1)
+		clk.Advance(cfg.DisconnectGracePeriod)
+		for state.Status != models.STATUS_FINISHED {
+			opponent.expect(models.MSG_GAME_UPDATE, &state)
+		}
+		leaver = dialTestClient(t, wsURL, "playerId="+leaver.playerID+"&token="+leaver.token)
+	}
+
+	leaveGame()
+	leaveGame()
+	var penalty models.QueuePenalty
+	leaver.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	leaver.expect(models.MSG_QUEUE_PENALTY, &penalty)
+	if penalty.RemainingMs != time.Minute.Milliseconds() || penalty.RecentLeaves != 2 {
+		t.Fatalf("penalty after two leaves = %+v, want a minute", penalty)
+	}
+
+	clk.Advance(time.Minute)
+	leaveGame()
+	leaver.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	leaver.expect(models.MSG_QUEUE_PENALTY, &penalty)
+	if penalty.RemainingMs != (5*time.Minute).Milliseconds() || penalty.RecentLeaves != 3 {
+		t.Fatalf("penalty after three leaves = %+v, want five minutes", penalty)
+	}
+
+	// Leaves age out of the window
+	clk.Advance(time.Hour)
+	leaver.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	leaver.expect(models.MSG_QUEUE_JOINED, nil)
+}
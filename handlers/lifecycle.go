@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// gameSweepInterval is how often games are checked for abandonment and expiry
+const gameSweepInterval = 15 * time.Second
+
+// lifecycleStats counts games resolved or freed by the sweeper since startup
+type lifecycleStats struct {
+	Abandoned int `json:"abandoned"` // Games where both players were gone too long
+	Voided    int `json:"voided"`    // Abandoned games aborted without a result
+	Drawn     int `json:"drawn"`     // Abandoned games finalized as draws
+	Expired   int `json:"expired"`   // Games nobody moved in for too long, adjudicated by StaleGamePolicy
+	Swept     int `json:"swept"`     // Ended games removed from memory
+}
+
+// runGameSweeper periodically resolves abandoned games and frees ended ones
+func (gs *GameServer) runGameSweeper() {
+	ticker := gs.clock.NewTicker(gameSweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		gs.do(gs.sweepGames)
+		gs.do(gs.pruneThrottle)
+		gs.do(gs.pruneLeavers)
+		gs.do(gs.pruneOutboxes)
+	}
+}
+
+// sweepGames resolves games nobody is connected to and removes expired ended games
+func (gs *GameServer) sweepGames() {
+	now := gs.clock.Now()
+	var abandoned, expired []string
+	swept := 0
+
+	for _, gameInstance := range gs.games.Games() {
+		gameID := gameInstance.ID
+		switch gameInstance.Status {
+		case models.STATUS_FINISHED, models.STATUS_ABORTED, models.STATUS_ABANDONED:
+			if gameInstance.EndTime != nil && now.Sub(*gameInstance.EndTime) >= gs.config.FinishedGameRetention {
+				gs.removeGame(gameID)
+				swept++
+			}
+		case models.STATUS_PLAYING, models.STATUS_PAUSED:
+			if gs.checkStaleGame(gameInstance, now) {
+				expired = append(expired, gameID)
+				continue
+			}
+			if !gs.bothPlayersGone(gameInstance) {
+				delete(gs.abandonedSince, gameID)
+				continue
+			}
+			since, seen := gs.abandonedSince[gameID]
+			if !seen {
+				gs.abandonedSince[gameID] = now
+				continue
+			}
+			if now.Sub(since) >= gs.config.AbandonAfter {
+				abandoned = append(abandoned, gameID)
+			}
+		}
+	}
+	gs.lifecycle.Swept += swept
+
+	for _, gameID := range abandoned {
+		gs.abandonGame(gameID)
+	}
+	for _, gameID := range expired {
+		gs.expireStaleGame(gameID)
+	}
+
+	if swept > 0 || len(abandoned) > 0 || len(expired) > 0 {
+		log.Printf("Game sweep: %d abandoned, %d expired, %d ended games freed", len(abandoned), len(expired), swept)
+	}
+}
+
+// bothPlayersGone reports whether nobody playing in the game is connected
+// Bots never leave, so bot games are settled by the forfeit timer instead
+// Correspondence games expect their players to be away and are settled by the move deadline instead
+func (gs *GameServer) bothPlayersGone(gameInstance *models.Game) bool {
+	if gameInstance.IsCorrespondence() {
+		return false
+	}
+	for _, player := range gameInstance.AllPlayers() {
+		if player.IsBot || gs.isConnected(player.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// abandonGame resolves a game nobody returned to according to the configured policy
+func (gs *GameServer) abandonGame(gameID string) {
+	gameInstance, exists := gs.games.Game(gameID)
+	if !exists || !gs.bothPlayersGone(gameInstance) {
+		return
+	}
+
+	var err error
+	if gs.config.AbandonedGamePolicy == config.ABANDONED_GAMES_DRAW {
+		err = gs.gameEngine.EndGame(gameInstance, models.RESULT_DRAW)
+	} else {
+		err = gs.gameEngine.Transition(gameInstance, models.STATUS_ABANDONED)
+	}
+	if err != nil {
+		// Already ended by a move, forfeit or admin
+		return
+	}
+
+	finished := gameInstance.Status == models.STATUS_FINISHED
+	gs.lifecycle.Abandoned++
+	if finished {
+		gs.lifecycle.Drawn++
+	} else {
+		gs.lifecycle.Voided++
+	}
+
+	log.Printf("Game %s abandoned by both players, resolved as %s", gameID, gs.config.AbandonedGamePolicy)
+	gs.logEvent(gameID, models.EVENT_ABANDONED, "", map[string]interface{}{"policy": gs.config.AbandonedGamePolicy})
+	for _, player := range gameInstance.AllPlayers() {
+		gs.recordLeave(player, gameID)
+	}
+
+	if finished {
+		gs.finishGame(gameInstance)
+	} else {
+		gs.sendGameUpdate(gameInstance)
+		gs.logEvent(gameID, models.EVENT_ABORTED, "", nil)
+	}
+}
+
+// onGameTransition keeps timers and clocks in step with a game's status
+// Runs on the hub for every transition the engine makes; notifications stay with the callers, which know why the game changed
+func (gs *GameServer) onGameTransition(gameInstance *models.Game, from, to string) {
+	switch {
+	case models.IsEnded(to):
+		now := gs.clock.Now()
+		gameInstance.EndTime = &now
+		gameInstance.DisconnectedPlayerID = ""
+		gs.stopForfeitTimer(gameInstance.ID)
+		gs.stopFlagTimer(gameInstance.ID)
+		delete(gs.abandonedSince, gameInstance.ID)
+		delete(gs.staleNotices, gameInstance.ID)
+	case to == models.STATUS_PAUSED:
+		// Nobody's clock runs while the game waits
+		gs.gameEngine.StopClock(gameInstance)
+	case to == models.STATUS_PLAYING:
+		// The first turn starts, or the turn the pause interrupted resumes
+		gs.gameEngine.ResumeClock(gameInstance)
+	}
+}
+
+// inProgress reports whether a game is being played or paused
+func inProgress(gameInstance *models.Game) bool {
+	return gameInstance.Status == models.STATUS_PLAYING || gameInstance.Status == models.STATUS_PAUSED
+}
+
+// addGame registers a new game so players, spectators and the sweeper can find it, and starts play
+func (gs *GameServer) addGame(gameInstance *models.Game) {
+	if gameInstance.Status == models.STATUS_WAITING {
+		// Always allowed; new games are built waiting
+		gs.gameEngine.Transition(gameInstance, models.STATUS_PLAYING)
+	}
+	gs.claimCompensation(gameInstance)
+	gs.games.SaveGame(gameInstance)
+	gs.sentStates[gameInstance.ID] = &sentState{}
+	gs.startTurnReminders(gameInstance)
+	gs.scheduleMoveDeadline(gameInstance)
+	gs.startSpectatorFeed(gameInstance)
+	gs.fireGameStarted(gameInstance)
+}
+
+// removeGame frees a game and everything tracked alongside it
+func (gs *GameServer) removeGame(gameID string) {
+	if gameInstance, exists := gs.games.Game(gameID); exists {
+		gs.forgetMoveTiming(gameInstance)
+		gs.forgetEmotes(gameInstance)
+		gs.forgetHints(gameInstance)
+		gs.forgetTeamTurn(gameInstance)
+	}
+	gs.stopForfeitTimer(gameID)
+	gs.stopFlagTimer(gameID)
+	gs.stopTurnReminder(gameID)
+	gs.stopMoveDeadline(gameID)
+	gs.stopSpectatorFeed(gameID)
+	gs.games.RemoveGame(gameID)
+	delete(gs.abandonedSince, gameID)
+	delete(gs.staleNotices, gameID)
+	delete(gs.spectators, gameID)
+	delete(gs.sentStates, gameID)
+	delete(gs.moveAcks, gameID)
+	delete(gs.pushedTurns, gameID)
+}
+
+// handleAdminMetrics serves GET /admin/metrics
+func (gs *GameServer) handleAdminMetrics(w http.ResponseWriter, r *http.Request) {
+	byStatus := make(map[string]int)
+	var stats lifecycleStats
+	var usage utilization
+	gs.do(func() {
+		for _, gameInstance := range gs.games.Games() {
+			byStatus[gameInstance.Status]++
+		}
+		stats = gs.lifecycle
+		usage = gs.utilization()
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"games":       byStatus,
+		"lifecycle":   stats,
+		"connections": usage.Connections,
+		"capacity":    usage,
+	})
+}
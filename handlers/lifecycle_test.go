@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestAbandonedGameEndsAsAbandoned(t *testing.T) {
+	cfg := testConfig()
+	cfg.AbandonAfter = time.Minute
+	gs, clk, _ := newTestServer(t, cfg)
+
+	gameInstance := models.NewGame()
+	gs.do(func() {
+		gs.gameEngine.SeatPlayers(gameInstance, models.NewPlayer("x"), models.NewPlayer("o"))
+		gs.addGame(gameInstance)
+		gs.sweepGames()
+	})
+	if gameInstance.Status != models.STATUS_PLAYING {
+		t.Fatalf("new game status = %s, want playing", gameInstance.Status)
+	}
+
+	clk.Advance(time.Minute)
+	var stats lifecycleStats
+	var cancelErr error
+	gs.do(func() {
+		gs.sweepGames()
+		stats = gs.lifecycle
+		cancelErr = gs.cancelGame(gameInstance)
+	})
+	if gameInstance.Status != models.STATUS_ABANDONED {
+		t.Fatalf("status = %s, want abandoned", gameInstance.Status)
+	}
+	if gameInstance.EndTime == nil || !gameInstance.EndTime.Equal(clk.Now()) {
+		t.Errorf("end time = %v, want %v", gameInstance.EndTime, clk.Now())
+	}
+	if stats.Abandoned != 1 || stats.Voided != 1 {
+		t.Errorf("lifecycle = %+v", stats)
+	}
+	if !errors.Is(cancelErr, errGameNotInProgress) {
+		t.Errorf("cancelling an abandoned game: err = %v", cancelErr)
+	}
+}
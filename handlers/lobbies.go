@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+
+	"github.com/google/uuid"
+)
+
+// lobby is an open lobby waiting for someone to join
+type lobby struct {
+	models.Lobby
+	expiry clock.Timer
+}
+
+// handleCreateLobby opens a lobby with custom rules that anyone may join, replacing any the host already had open
+func (gs *GameServer) handleCreateLobby(conn clientConn, player *models.Player, rules *models.LobbyRules) {
+	if !gs.mayStartGame(conn, player, rules) {
+		return
+	}
+	gs.closeHostedLobby(player.ID, models.LOBBY_REPLACED)
+
+	now := gs.clock.Now()
+	l := &lobby{Lobby: models.Lobby{
+		ID:         uuid.New().String(),
+		HostID:     player.ID,
+		HostName:   player.Name,
+		HostRating: player.Rating(rules.RatingPool()),
+		Rules:      *rules,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(gs.config.LobbyTTL),
+	}}
+	l.expiry = gs.clock.AfterFunc(gs.config.LobbyTTL, gs.doLater(func() {
+		if gs.lobbies[l.ID] == l {
+			gs.closeLobby(l, models.LOBBY_EXPIRED, "")
+		}
+	}))
+	gs.lobbies[l.ID] = l
+
+	log.Printf("Player %s (%s) opened lobby %s: %+v", player.Name, player.ID, l.ID, l.Rules)
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_LOBBY_CREATED, l.Lobby))
+}
+
+// handleListLobbies sends the open lobbies, newest first
+func (gs *GameServer) handleListLobbies(conn clientConn, player *models.Player) {
+	lobbies := make([]models.Lobby, 0, len(gs.lobbies))
+	for _, l := range gs.lobbies {
+		if !gs.eitherBlocked(l.HostID, player.ID) {
+			lobbies = append(lobbies, l.Lobby)
+		}
+	}
+	sort.Slice(lobbies, func(i, j int) bool { return lobbies[i].CreatedAt.After(lobbies[j].CreatedAt) })
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_LOBBIES, map[string]interface{}{"lobbies": lobbies}))
+}
+
+// handleJoinLobby starts a lobby's game between its host and the player joining
+func (gs *GameServer) handleJoinLobby(conn clientConn, player *models.Player, request *models.LobbyPayload) {
+	l, exists := gs.lobbies[request.LobbyID]
+	if !exists {
+		gs.sendClientError(conn, models.ERR_LOBBY_NOT_FOUND, "Lobby not found or no longer open")
+		return
+	}
+	if l.HostID == player.ID {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot join your own lobby")
+		return
+	}
+	if gs.eitherBlocked(l.HostID, player.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot join this lobby")
+		return
+	}
+	if !gs.mayStartGame(conn, player, &l.Rules) {
+		return
+	}
+	host, exists := gs.players.Player(l.HostID)
+	if !exists || gs.activeGameForPlayer(host.ID) != nil {
+		gs.sendClientError(conn, models.ERR_ALREADY_IN_GAME, "The host of this lobby is already in a game")
+		return
+	}
+	if l.Rules.TimeControl == models.TIME_CORRESPONDENCE && gs.correspondenceLimitReached(host.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "The host of this lobby has too many correspondence games going")
+		return
+	}
+	if gs.atGameLimit() {
+		gs.sendServerFull(player.ID, models.FULL_GAMES, "The server is at its game limit, join the lobby again shortly")
+		return
+	}
+
+	for _, seated := range []*models.Player{host, player} {
+		gs.removeFromQueue(seated.ID)
+		gs.withdrawFromMatch(seated.ID)
+	}
+	gs.recordPairing(host.ID, player.ID)
+	newGame := gs.startCustomGame(models.GameSettings{LobbyRules: l.Rules}, host, player)
+	gs.closeLobby(l, models.LOBBY_STARTED, newGame.ID)
+}
+
+// handleCloseLobby closes a lobby at its host's request
+func (gs *GameServer) handleCloseLobby(conn clientConn, player *models.Player, request *models.LobbyPayload) {
+	l, exists := gs.lobbies[request.LobbyID]
+	if !exists || l.HostID != player.ID {
+		gs.sendClientError(conn, models.ERR_LOBBY_NOT_FOUND, "You have no open lobby with that ID")
+		return
+	}
+	gs.closeLobby(l, models.LOBBY_CLOSED, "")
+}
+
+// mayStartGame checks that a player may start a game by a lobby's rules, telling them why not
+// Lobbies are held to the same bans, cooldowns and limits as matchmaking
+func (gs *GameServer) mayStartGame(conn clientConn, player *models.Player, rules *models.LobbyRules) bool {
+	if gs.newGamesPaused() {
+		gs.sendClientError(conn, models.ERR_MAINTENANCE, "New games are paused on this server")
+		return false
+	}
+	if until, banned := gs.matchmakingBanned(player.ID); banned {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You are banned from matchmaking until "+until.UTC().Format(time.RFC3339))
+		return false
+	}
+	if penalty, cooling := gs.queuePenalty(player.ID); cooling {
+		gs.sendQueuePenalty(player.ID, penalty)
+		return false
+	}
+	if gs.activeGameForPlayer(player.ID) != nil {
+		gs.sendClientError(conn, models.ERR_ALREADY_IN_GAME, "You are already in a game")
+		return false
+	}
+	if rules.TimeControl == models.TIME_CORRESPONDENCE && gs.correspondenceLimitReached(player.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Finish one of your correspondence games before starting another")
+		return false
+	}
+	return true
+}
+
+// startCustomGame creates a game by settings from a lobby or challenge between two players
+func (gs *GameServer) startCustomGame(settings models.GameSettings, player1, player2 *models.Player) *models.Game {
+	rules := settings.LobbyRules
+	newGame, err := gs.gameEngine.NewSizedGame(rules.Variant, rules.Size, rules.WinLength)
+	if err != nil {
+		panic(err) // Lobby rules only allow variants built into the engine
+	}
+	switch settings.FirstPlayerID {
+	case player1.ID:
+		gs.gameEngine.SeatPlayersInOrder(newGame, player1, player2)
+	case player2.ID:
+		gs.gameEngine.SeatPlayersInOrder(newGame, player2, player1)
+	default:
+		gs.gameEngine.SeatPlayers(newGame, player1, player2)
+	}
+	newGame.Rated = rules.Rated
+	newGame.PieRule = gs.config.PieRule
+	newGame.Settings = &settings
+
+	seconds := time.Duration(rules.Seconds) * time.Second
+	switch rules.TimeControl {
+	case models.TIME_BLITZ:
+		if seconds == 0 {
+			seconds = gs.config.BlitzTime
+		}
+		gs.gameEngine.StartClock(newGame, seconds)
+	case models.TIME_CORRESPONDENCE:
+		if seconds == 0 {
+			seconds = gs.config.CorrespondenceMoveTime
+		}
+		newGame.MoveTime = seconds
+	}
+
+	gs.openGame(newGame, "custom", player1, player2)
+	return newGame
+}
+
+// closeHostedLobby closes the lobby a player has open, if any
+func (gs *GameServer) closeHostedLobby(playerID, reason string) {
+	for _, l := range gs.lobbies {
+		if l.HostID == playerID {
+			gs.closeLobby(l, reason, "")
+		}
+	}
+}
+
+// closeLobby takes a lobby off the list and tells its host why
+func (gs *GameServer) closeLobby(l *lobby, reason, gameID string) {
+	l.expiry.Stop()
+	delete(gs.lobbies, l.ID)
+	log.Printf("Lobby %s closed: %s", l.ID, reason)
+	if gs.isConnected(l.HostID) {
+		gs.sendToPlayer(l.HostID, models.NewGameMessage(models.MSG_LOBBY_CLOSED, models.LobbyClosed{
+			LobbyID: l.ID,
+			Reason:  reason,
+			GameID:  gameID,
+		}))
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestLobbyIsListedAndJoined(t *testing.T) {
+	cfg := testConfig()
+	cfg.LobbyTTL = 10 * time.Minute
+	_, _, wsURL := newTestServer(t, cfg)
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	alice.send(models.MSG_CREATE_LOBBY, models.LobbyRules{Size: 4, TimeControl: models.TIME_BLITZ, Seconds: 60})
+	var created models.Lobby
+	alice.expect(models.MSG_LOBBY_CREATED, &created)
+	if created.Rules.WinLength != 4 || created.Rules.Variant != models.VARIANT_STANDARD {
+		t.Fatalf("lobby rules = %+v, want the defaults filled in", created.Rules)
+	}
+
+	bob.send(models.MSG_LIST_LOBBIES, nil)
+	var listed struct {
+		Lobbies []models.Lobby `json:"lobbies"`
+	}
+	bob.expect(models.MSG_LOBBIES, &listed)
+	if len(listed.Lobbies) != 1 || listed.Lobbies[0].ID != created.ID || listed.Lobbies[0].HostName != "alice" {
+		t.Fatalf("lobbies = %+v, want alice's", listed.Lobbies)
+	}
+
+	bob.send(models.MSG_JOIN_LOBBY, models.LobbyPayload{LobbyID: created.ID})
+	var found map[string]interface{}
+	bob.expect(models.MSG_GAME_FOUND, &found)
+	if found["size"] != 4.0 || found["winLength"] != 4.0 || found["timeLeftMs"] == nil {
+		t.Fatalf("game_found = %+v, want a 4x4 blitz game", found)
+	}
+	alice.expect(models.MSG_GAME_FOUND, nil)
+	var closed models.LobbyClosed
+	alice.expect(models.MSG_LOBBY_CLOSED, &closed)
+	if closed.Reason != models.LOBBY_STARTED || closed.GameID != found["gameId"] {
+		t.Fatalf("lobby_closed = %+v, want it started as %v", closed, found["gameId"])
+	}
+
+	bob.send(models.MSG_LIST_LOBBIES, nil)
+	bob.expect(models.MSG_LOBBIES, &listed)
+	if len(listed.Lobbies) != 0 {
+		t.Fatalf("lobbies after joining = %+v, want none", listed.Lobbies)
+	}
+}
+
+func TestLobbyExpires(t *testing.T) {
+	cfg := testConfig()
+	cfg.LobbyTTL = 10 * time.Minute
+	gs, clk, wsURL := newTestServer(t, cfg)
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	alice.send(models.MSG_CREATE_LOBBY, models.LobbyRules{})
+	var created models.Lobby
+	alice.expect(models.MSG_LOBBY_CREATED, &created)
+
+	gs.do(func() {})
+	clk.Advance(cfg.LobbyTTL)
+	var closed models.LobbyClosed
+	alice.expect(models.MSG_LOBBY_CLOSED, &closed)
+	if closed.Reason != models.LOBBY_EXPIRED {
+		t.Fatalf("lobby_closed = %+v, want it expired", closed)
+	}
+
+	bob.send(models.MSG_JOIN_LOBBY, models.LobbyPayload{LobbyID: created.ID})
+	var refused models.ErrorPayload
+	bob.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_LOBBY_NOT_FOUND {
+		t.Fatalf("joining an expired lobby = %+v", refused)
+	}
+}
+
+func TestRatedLobbyMustBeStandard(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	alice.send(models.MSG_CREATE_LOBBY, models.LobbyRules{Size: 5, Rated: true})
+	var refused models.ErrorPayload
+	alice.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_INVALID_PAYLOAD {
+		t.Fatalf("rated 5x5 lobby = %+v", refused)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import "tictactoe-server/models"
+
+// lobbyState tallies today's games and remembers what was last pushed, so unchanged stats are not resent
+type lobbyState struct {
+	day        string // UTC date the tally is for
+	gamesToday int
+	lastSent   models.LobbyStats
+}
+
+// runLobbyStats pushes the lobby statistics every interval in which they changed
+func (gs *GameServer) runLobbyStats() {
+	ticker := gs.clock.NewTicker(gs.config.LobbyStatsInterval)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		gs.do(gs.broadcastLobbyStats)
+	}
+}
+
+// broadcastLobbyStats sends the lobby statistics to every client if they changed since the last push
+func (gs *GameServer) broadcastLobbyStats() {
+	stats := gs.lobbyStats()
+	if stats == gs.lobby.lastSent {
+		return
+	}
+	gs.lobby.lastSent = stats
+	gs.sendToAll(models.NewGameMessage(models.MSG_LOBBY_STATS, stats))
+}
+
+// lobbyStats computes the current lobby statistics
+func (gs *GameServer) lobbyStats() models.LobbyStats {
+	stats := models.LobbyStats{
+		OnlinePlayers: len(gs.playerConns),
+		ActiveGames:   gs.activeGameCount(),
+	}
+	for _, mode := range gs.matchmaking.Modes() {
+		stats.QueuedPlayers += len(gs.matchmaking.Queue(mode))
+	}
+	if gs.lobby.day == gs.today() {
+		stats.GamesToday = gs.lobby.gamesToday
+	}
+	return stats
+}
+
+// countFinishedGame adds a finished game to today's tally, starting a new tally after midnight UTC
+func (gs *GameServer) countFinishedGame(*models.Game) {
+	if today := gs.today(); gs.lobby.day != today {
+		gs.lobby.day, gs.lobby.gamesToday = today, 0
+	}
+	gs.lobby.gamesToday++
+}
+
+// today returns the current UTC date
+func (gs *GameServer) today() string {
+	return gs.clock.Now().UTC().Format("2006-01-02")
+}
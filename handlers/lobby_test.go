@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// lobbyPushes decodes the lobby_stats messages a recorder has received, read on the hub
+func lobbyPushes(gs *GameServer, c *recordingConn) []models.LobbyStats {
+	var pushes []models.LobbyStats
+	gs.do(func() {
+		for _, msg := range c.messages {
+			if msg.Type == models.MSG_LOBBY_STATS {
+				var stats models.LobbyStats
+				json.Unmarshal(msg.Data, &stats)
+				pushes = append(pushes, stats)
+			}
+		}
+	})
+	return pushes
+}
+
+func TestLobbyStatsPushedOnChange(t *testing.T) {
+	cfg := testConfig()
+	cfg.LobbyStatsInterval = time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+	watcher := attachRecorder(gs)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	var onConnect models.LobbyStats
+	alice.expect(models.MSG_LOBBY_STATS, &onConnect)
+	if onConnect.OnlinePlayers != 1 {
+		t.Errorf("lobby_stats on connect = %+v", onConnect)
+	}
+
+	gs.do(gs.broadcastLobbyStats)
+	gs.do(gs.broadcastLobbyStats)
+	if pushes := lobbyPushes(gs, watcher); len(pushes) != 1 {
+		t.Fatalf("unchanged stats pushed %d times", len(pushes))
+	}
+
+	x, o, gameID := startRatedGame(t, wsURL)
+	gs.do(gs.broadcastLobbyStats)
+	playTopRowWin(t, x, o, gameID)
+	gs.do(gs.broadcastLobbyStats)
+
+	pushes := lobbyPushes(gs, watcher)
+	want := []models.LobbyStats{
+		{OnlinePlayers: 1},
+		{OnlinePlayers: 3, ActiveGames: 1},
+		{OnlinePlayers: 3, GamesToday: 1},
+	}
+	if len(pushes) != len(want) {
+		t.Fatalf("pushes = %+v, want %+v", pushes, want)
+	}
+	for i := range want {
+		if pushes[i] != want[i] {
+			t.Errorf("push %d = %+v, want %+v", i, pushes[i], want[i])
+		}
+	}
+
+	// The tally starts over at midnight UTC
+	clk.Advance(24 * time.Hour)
+	gs.do(gs.broadcastLobbyStats)
+	if pushes := lobbyPushes(gs, watcher); pushes[len(pushes)-1].GamesToday != 0 {
+		t.Errorf("games today after midnight = %d", pushes[len(pushes)-1].GamesToday)
+	}
+}
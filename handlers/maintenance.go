@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// maintenancePhase works out which phase of a maintenance window the clock has reached
+func maintenancePhase(window *models.MaintenanceWindow, now time.Time) string {
+	switch {
+	case window.EndsAt != nil && !now.Before(*window.EndsAt):
+		return models.MAINTENANCE_ENDED
+	case !now.Before(window.StartsAt):
+		return models.MAINTENANCE_ACTIVE
+	case !now.Before(window.MatchmakingStopsAt):
+		return models.MAINTENANCE_PREPARING
+	}
+	return models.MAINTENANCE_SCHEDULED
+}
+
+// maintenanceStatus returns the phase of the maintenance window, or "" when none is scheduled
+func (gs *GameServer) maintenanceStatus() string {
+	if gs.maintenance == nil {
+		return ""
+	}
+	return gs.maintenance.Status
+}
+
+// currentMaintenance returns a copy of the maintenance window for use outside the hub, or nil
+func (gs *GameServer) currentMaintenance() *models.MaintenanceWindow {
+	if gs.maintenance == nil {
+		return nil
+	}
+	window := *gs.maintenance
+	return &window
+}
+
+// scheduleMaintenance replaces any maintenance window with a new one and announces it
+func (gs *GameServer) scheduleMaintenance(window models.MaintenanceWindow) {
+	if gs.maintenanceTimer != nil {
+		gs.maintenanceTimer.Stop()
+	}
+	window.Status = ""
+	gs.maintenance = &window
+	gs.advanceMaintenance()
+}
+
+// advanceMaintenance moves the maintenance window into the phase the clock has reached, announcing each new phase
+// to every client, and arms the timer for the next one
+// Matchmaking stops when the window is being prepared for: queued players are removed and games in progress finish
+func (gs *GameServer) advanceMaintenance() {
+	window := gs.maintenance
+	if window == nil {
+		return
+	}
+
+	now := gs.clock.Now()
+	phase := maintenancePhase(window, now)
+	if phase != window.Status {
+		window.Status = phase
+		log.Printf("Maintenance %s", phase)
+		if phase == models.MAINTENANCE_PREPARING || phase == models.MAINTENANCE_ACTIVE {
+			gs.clearQueues(models.QUEUE_REMOVED_MAINTENANCE)
+		}
+		gs.sendToAll(models.NewGameMessage(models.MSG_MAINTENANCE, window))
+	}
+	if phase == models.MAINTENANCE_ENDED {
+		gs.maintenance = nil
+		return
+	}
+
+	var next time.Time
+	switch phase {
+	case models.MAINTENANCE_SCHEDULED:
+		next = window.MatchmakingStopsAt
+	case models.MAINTENANCE_PREPARING:
+		next = window.StartsAt
+	case models.MAINTENANCE_ACTIVE:
+		if window.EndsAt == nil {
+			return
+		}
+		next = *window.EndsAt
+	}
+	gs.maintenanceTimer = gs.clock.AfterFunc(next.Sub(now), gs.doLater(func() {
+		// A window replaced in the meantime has its own timer
+		if gs.maintenance == window {
+			gs.advanceMaintenance()
+		}
+	}))
+}
+
+// endMaintenance calls off a maintenance window that hasn't started, or ends one in progress
+func (gs *GameServer) endMaintenance() {
+	window := gs.maintenance
+	if window == nil {
+		return
+	}
+	if gs.maintenanceTimer != nil {
+		gs.maintenanceTimer.Stop()
+	}
+
+	if window.Status == models.MAINTENANCE_ACTIVE {
+		now := gs.clock.Now()
+		window.Status = models.MAINTENANCE_ENDED
+		window.EndsAt = &now
+	} else {
+		window.Status = models.MAINTENANCE_CANCELLED
+	}
+	log.Printf("Maintenance %s", window.Status)
+	gs.maintenance = nil
+	gs.sendToAll(models.NewGameMessage(models.MSG_MAINTENANCE, window))
+}
+
+// refuseMaintenance answers a connection attempt during maintenance with a JSON 503 carrying the window,
+// so clients can count down to its expected end
+func (gs *GameServer) refuseMaintenance(w http.ResponseWriter, window *models.MaintenanceWindow) {
+	if window.EndsAt != nil {
+		wait := max(window.EndsAt.Sub(gs.clock.Now()), time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	}
+	writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+		"error":       "server under maintenance",
+		"code":        models.ERR_MAINTENANCE,
+		"maintenance": window,
+	})
+}
+
+// maintenanceReport is the body of /admin/maintenance
+func (gs *GameServer) maintenanceReport() map[string]interface{} {
+	status := gs.maintenanceStatus()
+	return map[string]interface{}{
+		"enabled":     status == models.MAINTENANCE_PREPARING || status == models.MAINTENANCE_ACTIVE,
+		"maintenance": gs.currentMaintenance(),
+	}
+}
+
+// handleAdminMaintenance reports or toggles maintenance mode
+// Turning it on starts a maintenance window straight away, ending at the optional endsAt
+func (gs *GameServer) handleAdminMaintenance(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		var body struct {
+			Enabled bool       `json:"enabled"`
+			EndsAt  *time.Time `json:"endsAt"`
+			Message string     `json:"message"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		now := gs.clock.Now()
+		if body.Enabled && body.EndsAt != nil && !body.EndsAt.After(now) {
+			http.Error(w, "endsAt must be in the future", http.StatusBadRequest)
+			return
+		}
+
+		gs.do(func() {
+			if body.Enabled {
+				gs.scheduleMaintenance(models.MaintenanceWindow{
+					MatchmakingStopsAt: now,
+					StartsAt:           now,
+					EndsAt:             body.EndsAt,
+					Message:            body.Message,
+				})
+			} else {
+				gs.endMaintenance()
+			}
+		})
+
+		log.Printf("Admin set maintenance mode: %v", body.Enabled)
+		gs.auditAdmin(r, models.ADMIN_MAINTENANCE, "", map[string]interface{}{
+			"enabled": body.Enabled,
+			"endsAt":  body.EndsAt,
+			"message": body.Message,
+		})
+	}
+
+	var report map[string]interface{}
+	gs.do(func() { report = gs.maintenanceReport() })
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleAdminMaintenanceSchedule schedules a maintenance window on POST, replacing any other
+// Matchmaking stops leadSeconds before it starts, MaintenanceLead by default
+func (gs *GameServer) handleAdminMaintenanceSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		StartsAt    time.Time `json:"startsAt"`
+		EndsAt      time.Time `json:"endsAt"`
+		LeadSeconds *int      `json:"leadSeconds"`
+		Message     string    `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if !body.StartsAt.After(gs.clock.Now()) || !body.EndsAt.After(body.StartsAt) {
+		http.Error(w, "startsAt must be in the future and endsAt after it", http.StatusBadRequest)
+		return
+	}
+	if body.LeadSeconds != nil && *body.LeadSeconds < 0 {
+		http.Error(w, "leadSeconds must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	var report map[string]interface{}
+	gs.do(func() {
+		lead := gs.config.MaintenanceLead
+		if body.LeadSeconds != nil {
+			lead = time.Duration(*body.LeadSeconds) * time.Second
+		}
+		gs.scheduleMaintenance(models.MaintenanceWindow{
+			MatchmakingStopsAt: body.StartsAt.Add(-lead),
+			StartsAt:           body.StartsAt,
+			EndsAt:             &body.EndsAt,
+			Message:            body.Message,
+		})
+		report = gs.maintenanceReport()
+	})
+
+	log.Printf("Admin scheduled maintenance from %s to %s", body.StartsAt.Format(time.RFC3339), body.EndsAt.Format(time.RFC3339))
+	gs.auditAdmin(r, models.ADMIN_SCHEDULE_MAINTENANCE, "", map[string]interface{}{
+		"startsAt":    body.StartsAt,
+		"endsAt":      body.EndsAt,
+		"leadSeconds": body.LeadSeconds,
+		"message":     body.Message,
+	})
+	writeJSON(w, http.StatusOK, report)
+}
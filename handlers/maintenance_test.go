@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestScheduledMaintenance(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaintenanceLead = 5 * time.Minute
+	gs, clk, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+	carol := dialTestClient(t, wsURL, "name=carol")
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	carol.expect(models.MSG_QUEUE_JOINED, nil)
+
+	startsAt := clk.Now().Add(10 * time.Minute)
+	endsAt := startsAt.Add(30 * time.Minute)
+	recorder := httptest.NewRecorder()
+	body := `{"startsAt": "` + startsAt.Format(time.RFC3339Nano) + `", "endsAt": "` + endsAt.Format(time.RFC3339Nano) + `", "message": "Upgrading"}`
+	gs.handleAdminMaintenanceSchedule(recorder, httptest.NewRequest(http.MethodPost, "/admin/maintenance/schedule", strings.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("scheduling = %d %s", recorder.Code, recorder.Body)
+	}
+
+	var window models.MaintenanceWindow
+	carol.expect(models.MSG_MAINTENANCE, &window)
+	if window.Status != models.MAINTENANCE_SCHEDULED || !window.MatchmakingStopsAt.Equal(startsAt.Add(-5*time.Minute)) || window.Message != "Upgrading" {
+		t.Fatalf("announcement = %+v", window)
+	}
+
+	clk.Advance(5 * time.Minute)
+	var removed map[string]string
+	carol.expect(models.MSG_QUEUE_REMOVED, &removed)
+	if removed["reason"] != models.QUEUE_REMOVED_MAINTENANCE {
+		t.Fatalf("queue_removed = %+v, want maintenance", removed)
+	}
+	carol.expect(models.MSG_MAINTENANCE, &window)
+	if window.Status != models.MAINTENANCE_PREPARING {
+		t.Fatalf("status once matchmaking stops = %s", window.Status)
+	}
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	var refused models.ErrorPayload
+	carol.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_MAINTENANCE {
+		t.Fatalf("joining the queue before maintenance = %+v", refused)
+	}
+	// The game in progress is allowed to finish
+	playTopRowWin(t, x, o, gameID)
+
+	clk.Advance(5 * time.Minute)
+	carol.expect(models.MSG_MAINTENANCE, &window)
+	if window.Status != models.MAINTENANCE_ACTIVE {
+		t.Fatalf("status once maintenance starts = %s", window.Status)
+	}
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=dave", nil)
+	if err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatal("new connection during maintenance was not refused with 503")
+	}
+	var refusal struct {
+		Code        string                   `json:"code"`
+		Maintenance models.MaintenanceWindow `json:"maintenance"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&refusal); err != nil {
+		t.Fatal(err)
+	}
+	if refusal.Code != models.ERR_MAINTENANCE || refusal.Maintenance.EndsAt == nil || !refusal.Maintenance.EndsAt.Equal(endsAt) {
+		t.Errorf("refusal = %+v, want the expected end", refusal)
+	}
+	if retry := resp.Header.Get("Retry-After"); retry != "1800" {
+		t.Errorf("Retry-After = %q, want 1800", retry)
+	}
+
+	clk.Advance(30 * time.Minute)
+	carol.expect(models.MSG_MAINTENANCE, &window)
+	if window.Status != models.MAINTENANCE_ENDED {
+		t.Fatalf("status after the window = %s", window.Status)
+	}
+	dialTestClient(t, wsURL, "name=dave")
+}
+
+func TestMaintenanceToggle(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	toggle := func(body string) map[string]interface{} {
+		recorder := httptest.NewRecorder()
+		gs.handleAdminMaintenance(recorder, httptest.NewRequest(http.MethodPost, "/admin/maintenance", strings.NewReader(body)))
+		var report map[string]interface{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Fatalf("toggle %s = %d %s", body, recorder.Code, recorder.Body)
+		}
+		return report
+	}
+
+	if report := toggle(`{"enabled": true}`); report["enabled"] != true {
+		t.Fatalf("report after turning maintenance on = %+v", report)
+	}
+	var window models.MaintenanceWindow
+	alice.expect(models.MSG_MAINTENANCE, &window)
+	if window.Status != models.MAINTENANCE_ACTIVE || window.EndsAt != nil {
+		t.Fatalf("announcement = %+v, want active with no expected end", window)
+	}
+	if _, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=bob", nil); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatal("new connection during maintenance was not refused with 503")
+	}
+
+	if report := toggle(`{"enabled": false}`); report["enabled"] != false || report["maintenance"] != nil {
+		t.Fatalf("report after turning maintenance off = %+v", report)
+	}
+	alice.expect(models.MSG_MAINTENANCE, &window)
+	if window.Status != models.MAINTENANCE_ENDED {
+		t.Fatalf("status after turning maintenance off = %s", window.Status)
+	}
+	dialTestClient(t, wsURL, "name=bob")
+}
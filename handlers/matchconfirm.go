@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+
+	"github.com/google/uuid"
+)
+
+// matchProposal is a match waiting for every player to accept it before the game is created
+type matchProposal struct {
+	id       string
+	mode     string
+	players  []*models.Player // In queue order, which is the order they are requeued in
+	accepted map[string]bool
+	timer    clock.Timer
+	start    func() // Creates the game once everyone has accepted
+}
+
+// proposeMatch asks matched players to accept before start creates their game
+// Without an accept timeout the game starts at once
+func (gs *GameServer) proposeMatch(mode string, players []*models.Player, start func()) {
+	timeout := gs.config.MatchAcceptTimeout
+	if timeout == 0 {
+		start()
+		return
+	}
+
+	proposal := &matchProposal{
+		id:       uuid.New().String(),
+		mode:     mode,
+		players:  players,
+		accepted: make(map[string]bool),
+		start:    start,
+	}
+	id := proposal.id
+	proposal.timer = gs.clock.AfterFunc(timeout, gs.doLater(func() { gs.expireMatch(id) }))
+	gs.matchProposals[id] = proposal
+
+	msg := models.MatchProposal{
+		MatchID:   id,
+		Mode:      mode,
+		ExpiresAt: gs.clock.Now().Add(timeout),
+		TimeoutMs: timeout.Milliseconds(),
+	}
+	for _, player := range players {
+		msg.Players = append(msg.Players, models.MatchedPlayer{ID: player.ID, Name: player.Name, Rating: player.Rating(models.RATING_POOL_STANDARD)})
+	}
+
+	log.Printf("Proposed %s match %s to %d players", mode, id, len(players))
+	for _, player := range players {
+		gs.sendToPlayer(player.ID, models.NewGameMessage(models.MSG_MATCH_PROPOSED, msg))
+	}
+}
+
+// handleAcceptMatch records a player's acceptance and starts the game once everyone has accepted
+func (gs *GameServer) handleAcceptMatch(player *models.Player, request *models.MatchPayload) {
+	proposal, ok := gs.proposalFor(player.ID, request.MatchID)
+	if !ok {
+		gs.sendError(player.ID, models.ERR_MATCH_NOT_FOUND, "Match not found")
+		return
+	}
+	if proposal.accepted[player.ID] {
+		return
+	}
+
+	proposal.accepted[player.ID] = true
+	for _, matched := range proposal.players {
+		gs.sendToPlayer(matched.ID, models.NewGameMessage(models.MSG_MATCH_ACCEPTED, map[string]string{
+			"matchId":  proposal.id,
+			"playerId": player.ID,
+		}))
+	}
+	if len(proposal.accepted) < len(proposal.players) {
+		return
+	}
+
+	gs.closeProposal(proposal)
+	log.Printf("All players accepted %s match %s", proposal.mode, proposal.id)
+	proposal.start()
+}
+
+// handleDeclineMatch cancels a match the player doesn't want; the others go back to the front of the queue
+func (gs *GameServer) handleDeclineMatch(player *models.Player, request *models.MatchPayload) {
+	proposal, ok := gs.proposalFor(player.ID, request.MatchID)
+	if !ok {
+		gs.sendError(player.ID, models.ERR_MATCH_NOT_FOUND, "Match not found")
+		return
+	}
+	gs.cancelMatch(proposal, models.MATCH_CANCELLED_DECLINED, func(playerID string) bool { return playerID != player.ID })
+}
+
+// withdrawFromMatch declines the match a player is being asked about, if any, e.g. when they disconnect
+func (gs *GameServer) withdrawFromMatch(playerID string) {
+	if proposal := gs.pendingMatch(playerID); proposal != nil {
+		gs.cancelMatch(proposal, models.MATCH_CANCELLED_DECLINED, func(id string) bool { return id != playerID })
+	}
+}
+
+// expireMatch cancels a match that wasn't accepted in time; only the players who accepted are requeued
+func (gs *GameServer) expireMatch(matchID string) {
+	proposal, exists := gs.matchProposals[matchID]
+	if !exists {
+		return
+	}
+	gs.cancelMatch(proposal, models.MATCH_CANCELLED_TIMEOUT, func(playerID string) bool { return proposal.accepted[playerID] })
+}
+
+// cancelMatch drops a proposal, returning the players requeue picks to the front of the queue in their original order
+func (gs *GameServer) cancelMatch(proposal *matchProposal, reason string, requeue func(playerID string) bool) {
+	gs.closeProposal(proposal)
+	log.Printf("Cancelled %s match %s: %s", proposal.mode, proposal.id, reason)
+
+	requeued := 0
+	for _, player := range proposal.players {
+		back := requeue(player.ID) && len(gs.playerConns[player.ID]) > 0
+		if back {
+			gs.matchmaking.Enqueue(proposal.mode, requeued, player.ID, gs.clock.Now())
+			requeued++
+		}
+		if len(gs.playerConns[player.ID]) > 0 {
+			gs.sendToPlayer(player.ID, models.NewGameMessage(models.MSG_MATCH_CANCELLED, models.MatchCancelled{
+				MatchID:  proposal.id,
+				Reason:   reason,
+				Requeued: back,
+			}))
+		}
+	}
+
+	// The proposal's game slot is free again for players held back by the cap
+	if gs.config.MaxActiveGames > 0 {
+		gs.retryMatches()
+	} else if len(gs.matchmaking.Queue(proposal.mode)) >= matchSize(proposal.mode) {
+		gs.createMatch(proposal.mode)
+	}
+}
+
+// closeProposal stops tracking a proposal and its timer
+func (gs *GameServer) closeProposal(proposal *matchProposal) {
+	proposal.timer.Stop()
+	delete(gs.matchProposals, proposal.id)
+}
+
+// proposalFor finds a pending match that includes the player
+func (gs *GameServer) proposalFor(playerID, matchID string) (*matchProposal, bool) {
+	proposal, exists := gs.matchProposals[matchID]
+	if !exists {
+		return nil, false
+	}
+	for _, player := range proposal.players {
+		if player.ID == playerID {
+			return proposal, true
+		}
+	}
+	return nil, false
+}
+
+// pendingMatch returns the match proposal a player has yet to answer, or nil
+func (gs *GameServer) pendingMatch(playerID string) *matchProposal {
+	for _, proposal := range gs.matchProposals {
+		for _, player := range proposal.players {
+			if player.ID == playerID {
+				return proposal
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// proposeTestMatch queues two clients and waits for both to be offered the match
+func proposeTestMatch(t *testing.T, a, b *testClient, mode string) string {
+	t.Helper()
+	a.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: mode})
+	a.expect(models.MSG_QUEUE_JOINED, nil)
+	b.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: mode})
+
+	var proposal models.MatchProposal
+	a.expect(models.MSG_MATCH_PROPOSED, &proposal)
+	b.expect(models.MSG_MATCH_PROPOSED, nil)
+	if proposal.Mode != mode || len(proposal.Players) != 2 || proposal.TimeoutMs != 10000 {
+		t.Fatalf("proposal = %+v", proposal)
+	}
+	return proposal.MatchID
+}
+
+func TestMatchStartsOnceEveryoneAccepts(t *testing.T) {
+	cfg := testConfig()
+	cfg.MatchAcceptTimeout = 10 * time.Second
+	_, _, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+	matchID := proposeTestMatch(t, alice, bob, models.MODE_CASUAL)
+
+	alice.send(models.MSG_ACCEPT_MATCH, models.MatchPayload{MatchID: matchID})
+	var accepted map[string]string
+	bob.expect(models.MSG_MATCH_ACCEPTED, &accepted)
+	if accepted["playerId"] != alice.playerID {
+		t.Errorf("match_accepted = %v", accepted)
+	}
+
+	bob.send(models.MSG_ACCEPT_MATCH, models.MatchPayload{MatchID: matchID})
+	var state testGameState
+	alice.expect(models.MSG_GAME_FOUND, &state)
+	bob.expect(models.MSG_GAME_FOUND, nil)
+	if state.GameID == "" {
+		t.Error("game_found without a game")
+	}
+}
+
+func TestUnansweredMatchRequeuesPlayersWhoAccepted(t *testing.T) {
+	cfg := testConfig()
+	cfg.MatchAcceptTimeout = 10 * time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+	matchID := proposeTestMatch(t, alice, bob, models.MODE_CASUAL)
+
+	// Bob declines: Alice goes back to the front of the queue, Bob leaves it
+	bob.send(models.MSG_DECLINE_MATCH, models.MatchPayload{MatchID: matchID})
+	var cancelled models.MatchCancelled
+	alice.expect(models.MSG_MATCH_CANCELLED, &cancelled)
+	if cancelled.Reason != models.MATCH_CANCELLED_DECLINED || !cancelled.Requeued {
+		t.Errorf("alice got %+v", cancelled)
+	}
+	bob.expect(models.MSG_MATCH_CANCELLED, &cancelled)
+	if cancelled.Requeued {
+		t.Error("bob was requeued after declining")
+	}
+
+	// Carol is offered a match with Alice; only Alice answers before time runs out
+	carol := dialTestClient(t, wsURL, "name=carol")
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	var proposal models.MatchProposal
+	alice.expect(models.MSG_MATCH_PROPOSED, &proposal)
+	carol.expect(models.MSG_MATCH_PROPOSED, nil)
+	alice.send(models.MSG_ACCEPT_MATCH, models.MatchPayload{MatchID: proposal.MatchID})
+	carol.expect(models.MSG_MATCH_ACCEPTED, nil)
+
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	carol.expect(models.MSG_ERROR, nil)
+
+	clk.Advance(cfg.MatchAcceptTimeout)
+	alice.expect(models.MSG_MATCH_CANCELLED, &cancelled)
+	if cancelled.Reason != models.MATCH_CANCELLED_TIMEOUT || !cancelled.Requeued {
+		t.Errorf("alice got %+v", cancelled)
+	}
+	carol.expect(models.MSG_MATCH_CANCELLED, &cancelled)
+	if cancelled.Requeued {
+		t.Error("carol was requeued without accepting")
+	}
+
+	var queue []string
+	var pending int
+	gs.do(func() { queue, pending = gs.matchmaking.Queue(models.MODE_CASUAL), len(gs.matchProposals) })
+	if len(queue) != 1 || queue[0] != alice.playerID || pending != 0 {
+		t.Errorf("casual queue = %v with %d proposals, want only alice", queue, pending)
+	}
+}
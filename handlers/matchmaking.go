@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"time"
+
+	"tictactoe-server/config"
+)
+
+// matchRetryInterval controls how often queues are re-checked for pairs that were held back
+const matchRetryInterval = 5 * time.Second
+
+// pickPair chooses the two queue positions to match
+// Players who blocked each other are never paired; players who haven't met recently come first, then players in
+// the same region or with similar latency
+func (gs *GameServer) pickPair(queue []string) (int, int, bool) {
+	if len(queue) < 2 {
+		return 0, 0, false
+	}
+
+	policy := gs.config.RecentOpponentPolicy
+	avoidRecent := policy != config.RECENT_OPPONENTS_OFF && len(queue) >= gs.config.RecentOpponentMinQueue
+
+	// Longest-waiting players get first pick of a fresh opponent
+	for i := 0; i < len(queue); i++ {
+		if j, ok := gs.bestOpponent(queue, i, avoidRecent); ok {
+			return i, j, true
+		}
+	}
+
+	if policy == config.RECENT_OPPONENTS_STRICT {
+		return 0, 0, false
+	}
+	for i := 0; i < len(queue); i++ {
+		if j, ok := gs.bestOpponent(queue, i, false); ok {
+			return i, j, true
+		}
+	}
+	return 0, 0, false
+}
+
+// bestOpponent picks the later queue position that suits queue[i] best, the longest-waiting on ties
+func (gs *GameServer) bestOpponent(queue []string, i int, avoidRecent bool) (int, bool) {
+	player, _ := gs.players.Player(queue[i])
+	best, bestScore := 0, -1
+	for j := i + 1; j < len(queue); j++ {
+		if (avoidRecent && gs.playedRecently(queue[i], queue[j])) || gs.eitherBlocked(queue[i], queue[j]) {
+			continue
+		}
+		score := 0
+		if opponent, _ := gs.players.Player(queue[j]); player != nil && opponent != nil {
+			score = gs.matchAffinity(player, opponent)
+		}
+		if score > bestScore {
+			best, bestScore = j, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// playedRecently reports whether two players were paired within the configured window
+func (gs *GameServer) playedRecently(playerID, opponentID string) bool {
+	pairedAt, exists := gs.recentOpponents[playerID][opponentID]
+	return exists && gs.clock.Since(pairedAt) < gs.config.RecentOpponentWindow
+}
+
+// recordPairing remembers that two players were just matched against each other
+func (gs *GameServer) recordPairing(playerID, opponentID string) {
+	now := gs.clock.Now()
+	for _, pair := range [][2]string{{playerID, opponentID}, {opponentID, playerID}} {
+		if gs.recentOpponents[pair[0]] == nil {
+			gs.recentOpponents[pair[0]] = make(map[string]time.Time)
+		}
+		gs.recentOpponents[pair[0]][pair[1]] = now
+	}
+}
+
+// pruneRecentOpponents forgets pairings older than the window
+func (gs *GameServer) pruneRecentOpponents() {
+	for playerID, opponents := range gs.recentOpponents {
+		for opponentID, pairedAt := range opponents {
+			if gs.clock.Since(pairedAt) >= gs.config.RecentOpponentWindow {
+				delete(opponents, opponentID)
+			}
+		}
+		if len(opponents) == 0 {
+			delete(gs.recentOpponents, playerID)
+		}
+	}
+}
+
+// runMatchRetry periodically retries queues where recent-opponent avoidance or the game cap held players back
+// It runs whatever the settings, since a config reload may turn either on
+func (gs *GameServer) runMatchRetry() {
+	ticker := gs.clock.NewTicker(matchRetryInterval)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		gs.do(func() {
+			if gs.config.RecentOpponentPolicy != config.RECENT_OPPONENTS_OFF || gs.config.MaxActiveGames > 0 {
+				gs.retryMatches()
+			}
+		})
+	}
+}
+
+// retryMatches attempts a match in every queue with enough waiting players for a game
+func (gs *GameServer) retryMatches() {
+	gs.pruneRecentOpponents()
+	var ready []string
+	for _, mode := range gs.matchmaking.Modes() {
+		if len(gs.matchmaking.Queue(mode)) >= matchSize(mode) {
+			ready = append(ready, mode)
+		}
+	}
+
+	for _, mode := range ready {
+		gs.createMatch(mode)
+	}
+}
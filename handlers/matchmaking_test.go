@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+func TestPickPair(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     string
+		queue      []string
+		recent     [][2]string
+		wantFirst  int
+		wantSecond int
+		wantOK     bool
+	}{
+		{"too few players", config.RECENT_OPPONENTS_PREFER, []string{"a"}, nil, 0, 0, false},
+		{"fresh pair in order", config.RECENT_OPPONENTS_PREFER, []string{"a", "b", "c"}, nil, 0, 1, true},
+		{"skips recent opponent", config.RECENT_OPPONENTS_PREFER, []string{"a", "b", "c"}, [][2]string{{"a", "b"}}, 0, 2, true},
+		{"longest waiter first", config.RECENT_OPPONENTS_PREFER, []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"a", "c"}}, 1, 2, true},
+		{"prefer falls back to order", config.RECENT_OPPONENTS_PREFER, []string{"a", "b", "c"},
+			[][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}}, 0, 1, true},
+		{"strict waits", config.RECENT_OPPONENTS_STRICT, []string{"a", "b", "c"},
+			[][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}}, 0, 0, false},
+		{"strict allows rematch in small queue", config.RECENT_OPPONENTS_STRICT, []string{"a", "b"}, [][2]string{{"a", "b"}}, 0, 1, true},
+		{"off ignores history", config.RECENT_OPPONENTS_OFF, []string{"a", "b", "c"}, [][2]string{{"a", "b"}}, 0, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.RecentOpponentPolicy = tt.policy
+			gs := NewGameServer(cfg)
+			for _, pair := range tt.recent {
+				gs.recordPairing(pair[0], pair[1])
+			}
+
+			first, second, ok := gs.pickPair(tt.queue)
+			if ok != tt.wantOK || (ok && (first != tt.wantFirst || second != tt.wantSecond)) {
+				t.Errorf("pickPair = %d, %d, %v; want %d, %d, %v", first, second, ok, tt.wantFirst, tt.wantSecond, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRecentOpponentsExpire(t *testing.T) {
+	clk := clock.NewFake(testEpoch)
+	gs := NewGameServerWithClock(testConfig(), clk)
+	gs.recordPairing("a", "b")
+	if !gs.playedRecently("b", "a") {
+		t.Fatal("pairing not recorded in both directions")
+	}
+
+	clk.Advance(gs.config.RecentOpponentWindow - time.Second)
+	if !gs.playedRecently("a", "b") {
+		t.Fatal("pairing expired before the window")
+	}
+
+	clk.Advance(time.Second)
+	gs.pruneRecentOpponents()
+	if gs.playedRecently("a", "b") {
+		t.Error("expired pairing still counts as recent")
+	}
+	if _, exists := gs.recentOpponents["a"]; exists {
+		t.Error("empty opponent map not pruned")
+	}
+}
+
+func TestCreateMatchSeparatesModes(t *testing.T) {
+	gs := NewGameServer(testConfig())
+
+	players := map[string]*models.Player{}
+	for _, name := range []string{"r1", "c1", "r2"} {
+		player := models.NewPlayer(name)
+		players[name] = player
+		gs.players.SavePlayer(player)
+	}
+	for _, name := range []string{"r1", "r2"} {
+		gs.matchmaking.Enqueue(models.MODE_RATED, 2, players[name].ID, gs.clock.Now())
+	}
+	gs.matchmaking.Enqueue(models.MODE_CASUAL, 0, players["c1"].ID, gs.clock.Now())
+
+	gs.createMatch(models.MODE_CASUAL)
+	if len(gs.games.Games()) != 0 {
+		t.Fatal("matched a lone casual player")
+	}
+
+	gs.createMatch(models.MODE_RATED)
+	if len(gs.games.Games()) != 1 {
+		t.Fatalf("%d games created, want 1", len(gs.games.Games()))
+	}
+	for _, gameInstance := range gs.games.Games() {
+		if !gameInstance.Rated || gameInstance.Status != models.STATUS_PLAYING {
+			t.Errorf("rated match created as rated=%v status=%s", gameInstance.Rated, gameInstance.Status)
+		}
+	}
+	if len(gs.matchmaking.Queue(models.MODE_RATED)) != 0 || len(gs.matchmaking.Queue(models.MODE_CASUAL)) != 1 {
+		t.Errorf("queues after match: rated=%v casual=%v", gs.matchmaking.Queue(models.MODE_RATED), gs.matchmaking.Queue(models.MODE_CASUAL))
+	}
+}
+
+func TestPickPairPrefersNearbyPlayers(t *testing.T) {
+	type profile struct {
+		region  string
+		latency int
+	}
+	tests := []struct {
+		name       string
+		players    []profile
+		recent     [][2]int
+		wantSecond int
+	}{
+		{"same region", []profile{{"eu", 0}, {"us", 0}, {"eu", 0}}, nil, 2},
+		{"similar latency", []profile{{"", 20}, {"", 200}, {"", 40}}, nil, 2},
+		{"region outweighs latency", []profile{{"eu", 20}, {"", 25}, {"eu", 300}}, nil, 2},
+		{"unknown latency is no match", []profile{{"", 20}, {"", 0}, {"", 0}}, nil, 1},
+		{"fresh opponent outweighs region", []profile{{"eu", 0}, {"us", 0}, {"eu", 0}}, [][2]int{{0, 2}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MatchLatencyTolerance = 50 * time.Millisecond
+			gs := NewGameServer(cfg)
+			queue := make([]string, len(tt.players))
+			for i, p := range tt.players {
+				player := models.NewPlayer("p")
+				player.Region, player.LatencyMs = p.region, p.latency
+				gs.players.SavePlayer(player)
+				queue[i] = player.ID
+			}
+			for _, pair := range tt.recent {
+				gs.recordPairing(queue[pair[0]], queue[pair[1]])
+			}
+
+			first, second, ok := gs.pickPair(queue)
+			if !ok || first != 0 || second != tt.wantSecond {
+				t.Errorf("pickPair = %d, %d, %v; want 0, %d", first, second, ok, tt.wantSecond)
+			}
+		})
+	}
+}
+
+func TestMisereQueueStartsMisereGame(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startGame(t, wsURL, models.MODE_MISERE)
+
+	playMove(t, x, x, o, gameID, 0)
+	playMove(t, o, x, o, gameID, 3)
+	playMove(t, x, x, o, gameID, 1)
+	playMove(t, o, x, o, gameID, 4)
+
+	// Completing the top row loses
+	position := 2
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &position})
+	var state struct {
+		testGameState
+		Variant string `json:"variant"`
+	}
+	o.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Variant != models.VARIANT_MISERE || state.Status != models.STATUS_FINISHED || state.Winner != "O" {
+		t.Errorf("misère game after X's line = %+v", state)
+	}
+}
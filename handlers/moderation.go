@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+	"tictactoe-server/moderation"
+)
+
+// moderationTimeout bounds how long a message or connection waits on the moderator
+const moderationTimeout = 2 * time.Second
+
+// newModerator builds the moderation chain from the configured word list and webhook
+func newModerator(cfg *config.Config) moderation.Moderator {
+	var moderators []moderation.Moderator
+	if len(cfg.BlockedWords) > 0 {
+		moderators = append(moderators, moderation.NewWordFilter(cfg.BlockedWords))
+	}
+	if cfg.ModerationWebhookURL != "" {
+		moderators = append(moderators, moderation.NewWebhook(cfg.ModerationWebhookURL))
+	}
+	return moderation.Chain(moderators...)
+}
+
+// SetModerator replaces the moderation chain, e.g. to wire in an operator's own service
+// Call before Run
+func (gs *GameServer) SetModerator(moderator moderation.Moderator) {
+	gs.moderator = moderator
+}
+
+// moderate asks the moderator about text; if the moderator fails the text is let
+// through so an outage of an external service doesn't silence every player
+func (gs *GameServer) moderate(kind, text string) moderation.Verdict {
+	ctx, cancel := context.WithTimeout(context.Background(), moderationTimeout)
+	defer cancel()
+
+	verdict, err := gs.moderator.Moderate(ctx, kind, text)
+	if err != nil {
+		log.Printf("Moderation of %s failed, allowing it: %v", kind, err)
+		return moderation.Verdict{Allowed: true, Text: text}
+	}
+	return verdict
+}
+
+// isMuted reports whether an admin has muted a player's chat
+func (gs *GameServer) isMuted(playerID string) bool {
+	return gs.mutedPlayers[playerID]
+}
+
+// notifyMuted tells a player's connections that their chat was muted or unmuted
+func (gs *GameServer) notifyMuted(playerID string, muted bool) {
+	message := "An admin unmuted your chat"
+	if muted {
+		message = "An admin muted your chat"
+	}
+
+	for conn := range gs.playerConns[playerID] {
+		gs.sendToClient(conn, models.NewGameMessage(models.MSG_ANNOUNCEMENT, map[string]string{"message": message}))
+	}
+}
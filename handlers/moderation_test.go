@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tictactoe-server/models"
+	"tictactoe-server/moderation"
+)
+
+// chatLine is the payload of a spectator_chat message
+type chatLine struct {
+	Text string `json:"text"`
+}
+
+// failingModerator simulates an unreachable moderation service
+type failingModerator struct{}
+
+func (failingModerator) Moderate(ctx context.Context, kind, text string) (moderation.Verdict, error) {
+	return moderation.Verdict{}, errors.New("service down")
+}
+
+// watchGame starts a rated game and adds a spectator to it
+func watchGame(t *testing.T, wsURL string) (x, o, watcher *testClient, gameID string) {
+	t.Helper()
+	x, o, gameID = startRatedGame(t, wsURL)
+	watcher = dialTestClient(t, wsURL, "name=carol")
+	watcher.send(models.MSG_SPECTATE_GAME, models.GamePayload{GameID: gameID})
+	watcher.expect(models.MSG_GAME_UPDATE, nil)
+	return x, o, watcher, gameID
+}
+
+func TestBlockedNameBecomesAnonymous(t *testing.T) {
+	cfg := testConfig()
+	cfg.BlockedWords = []string{"darn"}
+	_, _, wsURL := newTestServer(t, cfg)
+
+	client := dialTestClient(t, wsURL, "name=xXDarnXx")
+	var player models.Player
+	client.expect(models.MSG_PLAYER_UPDATE, &player)
+	if player.Name != anonymousName {
+		t.Errorf("name = %q, want %q", player.Name, anonymousName)
+	}
+}
+
+func TestSpectatorChatIsModerated(t *testing.T) {
+	cfg := testConfig()
+	cfg.BlockedWords = []string{"darn"}
+	_, _, wsURL := newTestServer(t, cfg)
+	x, _, watcher, gameID := watchGame(t, wsURL)
+
+	watcher.send(models.MSG_SPECTATOR_CHAT, models.ChatPayload{GameID: gameID, Text: "darn nice"})
+	var line chatLine
+	x.expect(models.MSG_SPECTATOR_CHAT, &line)
+	if line.Text != "**** nice" {
+		t.Errorf("relayed %q, want masked text", line.Text)
+	}
+}
+
+func TestModerationOutageAllowsChat(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	gs.SetModerator(failingModerator{})
+	x, _, watcher, gameID := watchGame(t, wsURL)
+
+	watcher.send(models.MSG_SPECTATOR_CHAT, models.ChatPayload{GameID: gameID, Text: "still here"})
+	var line chatLine
+	x.expect(models.MSG_SPECTATOR_CHAT, &line)
+	if line.Text != "still here" {
+		t.Errorf("relayed %q during outage", line.Text)
+	}
+}
+
+func TestAdminMute(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, _, watcher, gameID := watchGame(t, wsURL)
+
+	admin := func(action string) {
+		recorder := httptest.NewRecorder()
+		gs.handleAdminPlayerAction(recorder, httptest.NewRequest(http.MethodPost, "/admin/players/"+watcher.playerID+"/"+action, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s = %d", action, recorder.Code)
+		}
+		watcher.expect(models.MSG_ANNOUNCEMENT, nil)
+	}
+
+	admin("mute")
+	watcher.send(models.MSG_SPECTATOR_CHAT, models.ChatPayload{GameID: gameID, Text: "hello"})
+	var failure struct {
+		Error string `json:"error"`
+	}
+	watcher.expect(models.MSG_ERROR, &failure)
+	if failure.Error != "You are muted" {
+		t.Errorf("error = %q", failure.Error)
+	}
+
+	admin("unmute")
+	watcher.send(models.MSG_SPECTATOR_CHAT, models.ChatPayload{GameID: gameID, Text: "hello again"})
+	var line chatLine
+	x.expect(models.MSG_SPECTATOR_CHAT, &line)
+	if line.Text != "hello again" {
+		t.Errorf("relayed %q after unmute", line.Text)
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// ackedMoves maps client move IDs to the acknowledgment sent for them
+type ackedMoves map[string]models.MoveAck
+
+// ackMove confirms the move just played to the mover, before the update that shows it goes out
+// Moves sent with a client move ID are remembered so retries get the same answer
+func (gs *GameServer) ackMove(gameInstance *models.Game, moveID string) {
+	record := gameInstance.Moves[len(gameInstance.Moves)-1]
+	ack := models.MoveAck{
+		OK:         true,
+		MoveID:     moveID,
+		GameID:     gameInstance.ID,
+		PlayerID:   record.PlayerID,
+		Symbol:     record.Symbol,
+		Position:   record.Position,
+		MoveNumber: len(gameInstance.Moves),
+	}
+	if last, exists := gs.sentStates[gameInstance.ID]; exists {
+		ack.Seq = last.seq + 1
+	}
+	if moveID != "" {
+		if gs.moveAcks[gameInstance.ID] == nil {
+			gs.moveAcks[gameInstance.ID] = make(ackedMoves)
+		}
+		gs.moveAcks[gameInstance.ID][moveID] = ack
+	}
+	gs.sendMoveAck(record.PlayerID, ack)
+}
+
+// resendMoveAck answers a retried move whose ID was already played in the game, reporting whether it was one
+// The original acknowledgment is sent again, even after the game ended; reusing an ID for a different move is refused
+func (gs *GameServer) resendMoveAck(player *models.Player, gameInstance *models.Game, move *models.MakeMovePayload) bool {
+	ack, seen := gs.moveAcks[gameInstance.ID][move.MoveID]
+	if !seen {
+		return false
+	}
+	if ack.PlayerID != player.ID || ack.Position != *move.Position {
+		gs.rejectMove(player, move, models.ERR_MOVE_ID_REUSED, "moveId was already used for a different move")
+		return true
+	}
+
+	log.Printf("Move %s in game %s retried by %s, acknowledging again", move.MoveID, gameInstance.ID, player.Name)
+	gs.sendMoveAck(player.ID, ack)
+	return true
+}
+
+// rejectMove tells the mover their move was refused: a move_ack with a code, or an error for earlier clients
+func (gs *GameServer) rejectMove(player *models.Player, move *models.MakeMovePayload, code, message string) {
+	ack := models.MoveAck{
+		MoveID:   move.MoveID,
+		GameID:   move.GameID,
+		PlayerID: player.ID,
+		Position: *move.Position,
+		Code:     code,
+		Error:    message,
+	}
+	for conn := range gs.playerConns[player.ID] {
+		if models.SupportsMessage(gs.clientVersion(conn), models.MSG_MOVE_ACK) {
+			gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_MOVE_ACK, move.GameID, ack))
+		} else {
+			gs.sendClientError(conn, code, message)
+		}
+	}
+}
+
+// sendMoveAck sends a successful acknowledgment to each of the player's connections that expects one
+// Clients before move_ack only hear about moves they gave an ID, as move_accepted
+func (gs *GameServer) sendMoveAck(playerID string, ack models.MoveAck) {
+	for conn := range gs.playerConns[playerID] {
+		switch {
+		case models.SupportsMessage(gs.clientVersion(conn), models.MSG_MOVE_ACK):
+			gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_MOVE_ACK, ack.GameID, ack))
+		case ack.MoveID != "":
+			gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_MOVE_ACCEPTED, ack.GameID, ack))
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestRetriedMoveIsAcknowledgedOnce(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	position := 4
+	move := models.MakeMovePayload{GameID: gameID, Position: &position, MoveID: "move-1"}
+	x.send(models.MSG_MAKE_MOVE, move)
+	var ack models.MoveAck
+	x.expect(models.MSG_MOVE_ACCEPTED, &ack)
+	o.expect(models.MSG_GAME_UPDATE, nil)
+	if ack.MoveID != "move-1" || ack.Position != 4 || ack.Symbol != "X" || ack.MoveNumber != 1 {
+		t.Fatalf("ack = %+v", ack)
+	}
+
+	// The retry gets the same acknowledgment instead of "position already occupied"
+	x.send(models.MSG_MAKE_MOVE, move)
+	var retried models.MoveAck
+	x.expect(models.MSG_MOVE_ACCEPTED, &retried)
+	if retried != ack {
+		t.Errorf("retried ack = %+v, want %+v", retried, ack)
+	}
+	var moves int
+	gs.do(func() { moves = len(testGame(gs, gameID).Moves) })
+	if moves != 1 {
+		t.Errorf("%d moves played, want 1", moves)
+	}
+
+	other := 8
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &other, MoveID: "move-1"})
+	var failure struct {
+		Error string `json:"error"`
+	}
+	x.expect(models.MSG_ERROR, &failure)
+	if !strings.Contains(failure.Error, "different move") {
+		t.Errorf("error = %q", failure.Error)
+	}
+}
+
+func TestMoveIDLengthIsLimited(t *testing.T) {
+	position := 0
+	payload := models.MakeMovePayload{GameID: "g", Position: &position, MoveID: strings.Repeat("m", models.MaxMoveIDLength+1)}
+	if err := payload.Validate(); err == nil {
+		t.Error("accepted an overlong moveId")
+	}
+}
+
+func TestMoveAckPrecedesUpdate(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+	x.send(models.MSG_HELLO, models.HelloPayload{Version: models.PROTOCOL_VERSION_MOVE_ACKS})
+	x.expect(models.MSG_HELLO, nil)
+
+	position := 4
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &position})
+	var ack models.MoveAck
+	for ack.GameID == "" {
+		var msg models.GameMessage
+		if err := x.conn.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		switch msg.Type {
+		case models.MSG_GAME_UPDATE, models.MSG_GAME_DELTA:
+			t.Fatalf("%s arrived before move_ack", msg.Type)
+		case models.MSG_MOVE_ACK:
+			json.Unmarshal(msg.Data, &ack)
+		}
+	}
+	if !ack.OK || ack.Seq != 1 || ack.MoveNumber != 1 || ack.Code != "" {
+		t.Errorf("ack = %+v, want success shown in update 1", ack)
+	}
+	var delta models.GameDelta
+	x.expect(models.MSG_GAME_DELTA, &delta)
+	if delta.Seq != ack.Seq {
+		t.Errorf("update seq = %d, ack said %d", delta.Seq, ack.Seq)
+	}
+	o.expect(models.MSG_GAME_UPDATE, nil)
+
+	// Refusals carry a code instead of arriving as an error
+	corner := 0
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &corner, MoveID: "early"})
+	x.expect(models.MSG_MOVE_ACK, &ack)
+	if ack.OK || ack.Code != models.ERR_NOT_YOUR_TURN || ack.MoveID != "early" || ack.Position != 0 {
+		t.Errorf("refusal = %+v", ack)
+	}
+
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: "missing", Position: &position})
+	x.expect(models.MSG_MOVE_ACK, &ack)
+	if ack.OK || ack.Code != models.ERR_GAME_NOT_FOUND {
+		t.Errorf("refusal = %+v", ack)
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"tictactoe-server/models"
+	"tictactoe-server/moderation"
+)
+
+// anonymousName is given to players who connect without a name or with one moderation rejects
+const anonymousName = "Anonymous"
+
+// playerNameFor validates the name a client asked for and returns the name to use
+// A missing name becomes anonymousName; a malformed one is an error that closes the connection
+func (gs *GameServer) playerNameFor(requested string) (string, error) {
+	name := strings.TrimSpace(requested)
+	if name == "" {
+		return anonymousName, nil
+	}
+	if err := models.ValidateName(name); err != nil {
+		return "", err
+	}
+
+	verdict := gs.moderate(moderation.KIND_NAME, name)
+	if !verdict.Allowed {
+		log.Printf("Rejected player name %q: %s", name, verdict.Reason)
+		return anonymousName, nil
+	}
+	return verdict.Text, nil
+}
+
+// uniqueName appends the smallest numeric suffix that sets name apart from every online player's
+func (gs *GameServer) uniqueName(name string) string {
+	taken := make(map[string]bool, len(gs.clients))
+	for _, player := range gs.clients {
+		taken[strings.ToLower(player.Name)] = true
+	}
+	if !taken[strings.ToLower(name)] {
+		return name
+	}
+
+	base := []rune(name)
+	for n := 2; ; n++ {
+		suffix := strconv.Itoa(n)
+		if keep := models.MaxNameLength - len(suffix); len(base) > keep {
+			base = base[:keep]
+		}
+		candidate := string(base) + suffix
+		if !taken[strings.ToLower(candidate)] {
+			return candidate
+		}
+	}
+}
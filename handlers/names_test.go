@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestInvalidNameClosesConnection(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+
+	for _, name := range []string{strings.Repeat("x", 10*1024), "bell\a", "system"} {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name="+url.QueryEscape(name), nil)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		client := &testClient{t: t, conn: conn}
+		client.expect(models.MSG_ERROR, nil)
+		client.expect(models.MSG_DISCONNECT, nil)
+
+		_, _, err = conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != models.CLOSE_INVALID_NAME {
+			t.Errorf("name of %d bytes: read after disconnect = %v, want close %d", len(name), err, models.CLOSE_INVALID_NAME)
+		}
+		conn.Close()
+	}
+}
+
+func TestNamesTrimmedAndDefaulted(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+
+	for query, want := range map[string]string{"name=+alice+": "alice", "name=": anonymousName, "": anonymousName} {
+		var player models.Player
+		dialTestClient(t, wsURL, query).expect(models.MSG_PLAYER_UPDATE, &player)
+		if player.Name != want {
+			t.Errorf("%q: name = %q, want %q", query, player.Name, want)
+		}
+	}
+}
+
+func TestUniqueNames(t *testing.T) {
+	cfg := testConfig()
+	cfg.UniqueNames = true
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	long := strings.Repeat("b", models.MaxNameLength)
+	for _, tt := range []struct{ query, want string }{
+		{"name=alice", "alice"},
+		{"name=Alice", "Alice2"},
+		{"name=alice", "alice3"},
+		{"name=" + long, long},
+		{"name=" + long, long[:models.MaxNameLength-1] + "2"},
+	} {
+		var player models.Player
+		dialTestClient(t, wsURL, tt.query).expect(models.MSG_PLAYER_UPDATE, &player)
+		if player.Name != tt.want {
+			t.Errorf("%s: name = %q, want %q", tt.query, player.Name, tt.want)
+		}
+	}
+
+	// Without the option duplicates are allowed
+	gs.config.UniqueNames = false
+	var player models.Player
+	dialTestClient(t, wsURL, "name=alice").expect(models.MSG_PLAYER_UPDATE, &player)
+	if player.Name != "alice" {
+		t.Errorf("name = %q with uniqueness off", player.Name)
+	}
+}
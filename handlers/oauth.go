@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tictactoe-server/models"
+	"tictactoe-server/oauth"
+)
+
+// oauthLoginTTL is how long a player has to log in at their provider before the login has to start over
+const oauthLoginTTL = 10 * time.Minute
+
+// oauthCookiePrefix names the cookie, one per provider, that ties a login to the browser that started it
+const oauthCookiePrefix = "ttt_oauth_"
+
+// pendingLogin is a player sent to log in at a provider, waiting for the provider's callback
+type pendingLogin struct {
+	provider string
+	linkTo   string // Player the account is being linked to; empty to log in as whoever it is linked to
+	nonce    string // Also set as a cookie in the browser that started the login, so nobody else can finish it
+	expires  time.Time
+}
+
+// oauthLogins holds the logins waiting for callbacks, which HTTP handlers check outside the hub
+type oauthLogins struct {
+	mutex   sync.Mutex
+	pending map[string]*pendingLogin // State -> login
+}
+
+// add remembers a login under its state, forgetting the expired ones
+func (l *oauthLogins) add(state string, login *pendingLogin, now time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for other, pending := range l.pending {
+		if !now.Before(pending.expires) {
+			delete(l.pending, other)
+		}
+	}
+	l.pending[state] = login
+}
+
+// take returns the login with a state and forgets it, so each callback is honoured once; nil if it is unknown or expired
+func (l *oauthLogins) take(state string, now time.Time) *pendingLogin {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	login, exists := l.pending[state]
+	delete(l.pending, state)
+	if !exists || !now.Before(login.expires) {
+		return nil
+	}
+	return login
+}
+
+// loadOAuthProviders sets up the providers with an OAuth app in the config
+func (gs *GameServer) loadOAuthProviders() {
+	gs.oauthProviders = make(map[string]*oauth.Provider)
+	gs.oauthLogins.pending = make(map[string]*pendingLogin)
+	for name, client := range gs.config.OAuthClients {
+		provider, err := oauth.New(name, client)
+		if err != nil {
+			log.Printf("Ignoring OAuth client: %v", err)
+			continue
+		}
+		gs.oauthProviders[name] = provider
+	}
+}
+
+// HandleAuth serves GET /auth/{provider}/login, which sends the player to log in at the provider,
+// GET /auth/{provider}/callback, where the provider sends them back,
+// and POST /auth/{provider}/link, which starts a login that links the provider's account to the session's player
+func (gs *GameServer) HandleAuth(w http.ResponseWriter, r *http.Request) {
+	name, step, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/auth/"), "/"), "/")
+	provider, exists := gs.oauthProviders[name]
+	if !exists || (step != "login" && step != "callback" && step != "link") {
+		http.NotFound(w, r)
+		return
+	}
+	method := http.MethodGet
+	if step == "link" {
+		method = http.MethodPost
+	}
+	if r.Method != method {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch step {
+	case "login":
+		gs.startOAuthLogin(w, r, provider)
+	case "link":
+		gs.startOAuthLink(w, r, provider)
+	default:
+		gs.finishOAuthLogin(w, r, provider)
+	}
+}
+
+// oauthCallbackURL is where a provider sends players back to, under OAuthBaseURL or else this request's host
+func (gs *GameServer) oauthCallbackURL(r *http.Request, provider string) string {
+	base := gs.config.OAuthBaseURL
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+	return base + "/auth/" + provider + "/callback"
+}
+
+// startOAuthLogin redirects the player to the provider's login page
+func (gs *GameServer) startOAuthLogin(w http.ResponseWriter, r *http.Request, provider *oauth.Provider) {
+	if r.URL.Query().Has("token") {
+		// Tokens in URLs end up in access logs and browser history
+		http.Error(w, "link accounts with POST /auth/"+provider.Name+"/link", http.StatusBadRequest)
+		return
+	}
+	loginURL, err := gs.beginOAuthLogin(w, r, provider, "")
+	if err != nil {
+		http.Error(w, "could not start login", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, loginURL, http.StatusFound)
+}
+
+// startOAuthLink serves POST /auth/{provider}/link?playerId= with the session token as Authorization: Bearer <token>,
+// answering with the provider's login page for the client to open in the same browser
+func (gs *GameServer) startOAuthLink(w http.ResponseWriter, r *http.Request, provider *oauth.Provider) {
+	player := gs.authorizedPlayer(r, r.URL.Query().Get("playerId"))
+	if player == nil {
+		http.Error(w, "invalid session", http.StatusUnauthorized)
+		return
+	}
+	loginURL, err := gs.beginOAuthLogin(w, r, provider, player.ID)
+	if err != nil {
+		http.Error(w, "could not start login", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]string{"url": loginURL})
+}
+
+// beginOAuthLogin remembers a login, linking to linkTo when set, sets its cookie and returns the provider's login page
+func (gs *GameServer) beginOAuthLogin(w http.ResponseWriter, r *http.Request, provider *oauth.Provider, linkTo string) (string, error) {
+	login := &pendingLogin{provider: provider.Name, linkTo: linkTo, expires: gs.clock.Now().Add(oauthLoginTTL)}
+	state, err := oauth.NewState()
+	if err != nil {
+		return "", err
+	}
+	if login.nonce, err = oauth.NewState(); err != nil {
+		return "", err
+	}
+	gs.oauthLogins.add(state, login, gs.clock.Now())
+	gs.setOAuthCookie(w, r, provider.Name, login.nonce, oauthLoginTTL)
+	return provider.LoginURL(state, gs.oauthCallbackURL(r, provider.Name)), nil
+}
+
+// setOAuthCookie sets or, with a zero maxAge, clears the cookie a provider's login is tied to the browser with
+// SameSite=Lax still sends it on the provider's redirect back, a top-level GET
+func (gs *GameServer) setOAuthCookie(w http.ResponseWriter, r *http.Request, provider, nonce string, maxAge time.Duration) {
+	cookie := &http.Cookie{
+		Name:     oauthCookiePrefix + provider,
+		Value:    nonce,
+		Path:     "/auth/" + provider + "/",
+		MaxAge:   int(maxAge / time.Second),
+		Secure:   r.TLS != nil || strings.HasPrefix(gs.config.OAuthBaseURL, "https://"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if maxAge == 0 {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
+// startedHere reports whether a callback comes from the browser that started the login
+func startedHere(r *http.Request, login *pendingLogin) bool {
+	cookie, err := r.Cookie(oauthCookiePrefix + login.provider)
+	return err == nil && subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(login.nonce)) == 1
+}
+
+// finishOAuthLogin looks up the account the provider logged the player in with and hands them the session of its player,
+// creating the player the first time the account logs in
+func (gs *GameServer) finishOAuthLogin(w http.ResponseWriter, r *http.Request, provider *oauth.Provider) {
+	query := r.URL.Query()
+	login := gs.oauthLogins.take(query.Get("state"), gs.clock.Now())
+	gs.setOAuthCookie(w, r, provider.Name, "", 0)
+	if login == nil || login.provider != provider.Name {
+		http.Error(w, "login expired, please try again", http.StatusBadRequest)
+		return
+	}
+	if !startedHere(r, login) {
+		http.Error(w, "login was started in another browser, please try again", http.StatusBadRequest)
+		return
+	}
+	if query.Get("error") != "" || query.Get("code") == "" {
+		http.Error(w, "login cancelled at "+provider.Name, http.StatusBadRequest)
+		return
+	}
+
+	token, err := provider.Exchange(r.Context(), query.Get("code"), gs.oauthCallbackURL(r, provider.Name))
+	if err != nil {
+		log.Printf("OAuth login with %s failed: %v", provider.Name, err)
+		http.Error(w, "could not log in with "+provider.Name, http.StatusBadGateway)
+		return
+	}
+	account, err := provider.User(r.Context(), token)
+	if err != nil {
+		log.Printf("OAuth login with %s failed: %v", provider.Name, err)
+		http.Error(w, "could not log in with "+provider.Name, http.StatusBadGateway)
+		return
+	}
+
+	// As with connecting players, moderation runs here rather than stalling the hub
+	name, err := gs.playerNameFor(account.Name)
+	if err != nil {
+		name = anonymousName
+	}
+
+	var session url.Values
+	status, message := http.StatusOK, ""
+	gs.do(func() {
+		var player *models.Player
+		var created bool
+		player, created, status, message = gs.oauthPlayer(account, login.linkTo, name)
+		if player != nil {
+			session = url.Values{
+				"playerId": {player.ID},
+				"token":    {player.SessionToken},
+				"provider": {provider.Name},
+				"created":  {strconv.FormatBool(created)},
+			}
+		}
+	})
+	if session == nil {
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if gs.config.OAuthSuccessURL != "" {
+		// The fragment stays in the browser, out of server logs and referrers
+		http.Redirect(w, r, gs.config.OAuthSuccessURL+"#"+session.Encode(), http.StatusFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"playerId": session.Get("playerId"),
+		"token":    session.Get("token"),
+		"provider": provider.Name,
+		"created":  session.Get("created") == "true",
+	})
+}
+
+// oauthPlayer returns the player a provider's account logs in as, linking it to linkTo when set,
+// or to a new player named name when the account is new
+// Refusals come back as a nil player with the status and message to answer with
+func (gs *GameServer) oauthPlayer(account *models.Identity, linkTo, name string) (*models.Player, bool, int, string) {
+	if linked, exists := gs.store.Identity(account.Provider, account.Subject); exists {
+		if linkTo != "" && linked.PlayerID != linkTo {
+			return nil, false, http.StatusConflict, "that " + account.Provider + " account is linked to another player"
+		}
+		player, exists := gs.players.Player(linked.PlayerID)
+		if !exists || player.Deleted {
+			return nil, false, http.StatusNotFound, "player not found"
+		}
+		return player, false, 0, ""
+	}
+
+	account.LinkedAt = gs.clock.Now()
+	if linkTo != "" {
+		player, exists := gs.players.Player(linkTo)
+		if !exists || player.Deleted {
+			return nil, false, http.StatusNotFound, "player not found"
+		}
+		account.PlayerID = player.ID
+		if !gs.store.LinkIdentity(account) {
+			return nil, false, http.StatusConflict, "another " + account.Provider + " account is linked to this player"
+		}
+		log.Printf("Linked %s account %s to player %s", account.Provider, account.Subject, player.ID)
+		return player, false, 0, ""
+	}
+
+	if gs.config.UniqueNames {
+		name = gs.uniqueName(name)
+	}
+	player := models.NewPlayer(name)
+	player.LastSeen = gs.clock.Now()
+	gs.players.SavePlayer(player)
+	account.PlayerID = player.ID
+	gs.store.LinkIdentity(account)
+	log.Printf("New player %s (ID: %s) from %s account %s", player.Name, player.ID, account.Provider, account.Subject)
+	gs.firePlayerRegistered(player)
+	return player, true, 0, ""
+}
+
+// handleIdentities serves GET /api/players/{id}/identities, the player's linked provider accounts,
+// and DELETE /api/players/{id}/identities/{provider}, which unlinks one; both need the player's session
+func (gs *GameServer) handleIdentities(w http.ResponseWriter, r *http.Request, playerID, provider string) {
+	if gs.authorizedPlayer(r, playerID) == nil {
+		http.Error(w, "invalid session", http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && provider == "":
+		writeJSON(w, http.StatusOK, gs.store.PlayerIdentities(playerID))
+	case r.Method == http.MethodDelete && provider != "":
+		if !gs.store.UnlinkIdentity(playerID, provider) {
+			http.Error(w, "no "+provider+" account linked", http.StatusNotFound)
+			return
+		}
+		log.Printf("Unlinked %s account from player %s", provider, playerID)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "unlinked", "provider": provider})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+type oauthSession struct {
+	PlayerID string `json:"playerId"`
+	Token    string `json:"token"`
+	Provider string `json:"provider"`
+	Created  bool   `json:"created"`
+}
+
+// newOAuthTestServer sets up GitHub and Discord logins against a fake provider,
+// whose access token for code "c" is "token-c" and whose users are the codes' accounts
+func newOAuthTestServer(t *testing.T, users map[string]string) *GameServer {
+	t.Helper()
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/token"):
+			r.ParseForm()
+			if r.Form.Get("client_secret") != "secret" || r.Form.Get("redirect_uri") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"access_token": "token-" + r.Form.Get("code")})
+		case strings.HasSuffix(r.URL.Path, "/user"):
+			user, exists := users[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer token-")]
+			if !exists {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(user))
+		}
+	}))
+	t.Cleanup(fake.Close)
+
+	cfg := testConfig()
+	cfg.OAuthBaseURL = "https://ttt.example.com"
+	cfg.OAuthClients = map[string]config.OAuthClient{
+		config.OAUTH_GITHUB:  {ID: "id", Secret: "secret"},
+		config.OAUTH_DISCORD: {ID: "id", Secret: "secret"},
+	}
+	gs, _, _ := newTestServer(t, cfg)
+	for name, provider := range gs.oauthProviders {
+		provider.TokenURL = fake.URL + "/" + name + "/token"
+		provider.UserURL = fake.URL + "/" + name + "/user"
+	}
+	return gs
+}
+
+// oauthLogin follows a login through the provider, which answers with code, and returns the callback's response
+// With a session to link to, the login starts at /auth/{provider}/link
+func oauthLogin(t *testing.T, gs *GameServer, provider string, link *oauthSession, code string) *httptest.ResponseRecorder {
+	t.Helper()
+	callback, cookies := startOAuthTestLogin(t, gs, provider, link, code)
+	if callback == nil {
+		return cookies
+	}
+	recorder := httptest.NewRecorder()
+	for _, cookie := range cookies.Result().Cookies() {
+		callback.AddCookie(cookie)
+	}
+	gs.HandleAuth(recorder, callback)
+	return recorder
+}
+
+// startOAuthTestLogin starts a login and returns the provider's callback request, without the login's cookie,
+// and the login's response; the callback is nil if the login was refused
+func startOAuthTestLogin(t *testing.T, gs *GameServer, provider string, link *oauthSession, code string) (*http.Request, *httptest.ResponseRecorder) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	loginURL := ""
+	if link == nil {
+		gs.HandleAuth(recorder, httptest.NewRequest(http.MethodGet, "/auth/"+provider+"/login", nil))
+		if recorder.Code != http.StatusFound {
+			return nil, recorder
+		}
+		loginURL = recorder.Header().Get("Location")
+	} else {
+		request := httptest.NewRequest(http.MethodPost, "/auth/"+provider+"/link?playerId="+link.PlayerID, nil)
+		request.Header.Set("Authorization", "Bearer "+link.Token)
+		gs.HandleAuth(recorder, request)
+		if recorder.Code != http.StatusOK {
+			return nil, recorder
+		}
+		var started struct {
+			URL string `json:"url"`
+		}
+		json.Unmarshal(recorder.Body.Bytes(), &started)
+		loginURL = started.URL
+	}
+	location, err := url.Parse(loginURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redirect := location.Query().Get("redirect_uri"); redirect != "https://ttt.example.com/auth/"+provider+"/callback" {
+		t.Errorf("redirect_uri = %q", redirect)
+	}
+
+	callback := "/auth/" + provider + "/callback?" + url.Values{"code": {code}, "state": {location.Query().Get("state")}}.Encode()
+	return httptest.NewRequest(http.MethodGet, callback, nil), recorder
+}
+
+func decodeOAuthSession(t *testing.T, recorder *httptest.ResponseRecorder) oauthSession {
+	t.Helper()
+	var session oauthSession
+	if err := json.Unmarshal(recorder.Body.Bytes(), &session); recorder.Code != http.StatusOK || err != nil {
+		t.Fatalf("login = %d %s", recorder.Code, recorder.Body)
+	}
+	return session
+}
+
+func TestOAuthLoginCreatesAndLinksPlayers(t *testing.T) {
+	gs := newOAuthTestServer(t, map[string]string{
+		"octo":   `{"id": 42, "login": "octocat", "name": "Octo Cat"}`,
+		"wumpus": `{"id": "8080", "username": "wumpus"}`,
+	})
+
+	first := decodeOAuthSession(t, oauthLogin(t, gs, config.OAUTH_GITHUB, nil, "octo"))
+	if !first.Created || first.Token == "" {
+		t.Fatalf("first login = %+v, want a new player", first)
+	}
+	var name string
+	gs.do(func() {
+		player, _ := gs.players.Player(first.PlayerID)
+		name = player.Name
+	})
+	if name != "Octo Cat" {
+		t.Errorf("name = %q, want the GitHub name", name)
+	}
+
+	again := decodeOAuthSession(t, oauthLogin(t, gs, config.OAUTH_GITHUB, nil, "octo"))
+	if again.Created || again.PlayerID != first.PlayerID {
+		t.Errorf("second login = %+v, want the same player", again)
+	}
+
+	linked := decodeOAuthSession(t, oauthLogin(t, gs, config.OAUTH_DISCORD, &first, "wumpus"))
+	if linked.PlayerID != first.PlayerID {
+		t.Errorf("linking = %+v, want the GitHub player", linked)
+	}
+	if viaDiscord := decodeOAuthSession(t, oauthLogin(t, gs, config.OAUTH_DISCORD, nil, "wumpus")); viaDiscord.PlayerID != first.PlayerID {
+		t.Errorf("Discord login = %+v, want the linked player", viaDiscord)
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/players/"+first.PlayerID+"/identities", nil)
+	request.Header.Set("Authorization", "Bearer "+first.Token)
+	gs.HandlePlayerAPI(recorder, request)
+	var identities []models.Identity
+	json.Unmarshal(recorder.Body.Bytes(), &identities)
+	if len(identities) != 2 || identities[0].Provider != config.OAUTH_DISCORD || identities[0].Subject != "8080" || identities[1].Subject != "42" {
+		t.Errorf("identities = %+v, want the Discord and GitHub accounts", identities)
+	}
+}
+
+func TestOAuthRefusals(t *testing.T) {
+	gs := newOAuthTestServer(t, map[string]string{
+		"octo":  `{"id": 42, "login": "octocat"}`,
+		"other": `{"id": 7, "login": "other"}`,
+	})
+	octo := decodeOAuthSession(t, oauthLogin(t, gs, config.OAUTH_GITHUB, nil, "octo"))
+	other := decodeOAuthSession(t, oauthLogin(t, gs, config.OAUTH_GITHUB, nil, "other"))
+
+	if code := oauthLogin(t, gs, config.OAUTH_GITHUB, &other, "octo").Code; code != http.StatusConflict {
+		t.Errorf("linking an account linked to %s: status %d, want 409", octo.PlayerID, code)
+	}
+	if code := oauthLogin(t, gs, config.OAUTH_GITHUB, &oauthSession{PlayerID: other.PlayerID, Token: "wrong"}, "octo").Code; code != http.StatusUnauthorized {
+		t.Errorf("linking without a valid session: status %d, want 401", code)
+	}
+	recorder := httptest.NewRecorder()
+	gs.HandleAuth(recorder, httptest.NewRequest(http.MethodGet, "/auth/github/login?playerId="+other.PlayerID+"&token="+other.Token, nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("linking with the token in the login URL: status %d, want 400", recorder.Code)
+	}
+
+	// A login URL handed to someone else can't be finished without the cookie of the browser that started it
+	callback, _ := startOAuthTestLogin(t, gs, config.OAUTH_GITHUB, nil, "octo")
+	recorder = httptest.NewRecorder()
+	gs.HandleAuth(recorder, callback)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("callback without the login's cookie: status %d, want 400", recorder.Code)
+	}
+	callback, _ = startOAuthTestLogin(t, gs, config.OAUTH_GITHUB, nil, "octo")
+	callback.AddCookie(&http.Cookie{Name: oauthCookiePrefix + config.OAUTH_GITHUB, Value: "forged"})
+	recorder = httptest.NewRecorder()
+	gs.HandleAuth(recorder, callback)
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("callback with another login's cookie: status %d, want 400", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	gs.HandleAuth(recorder, httptest.NewRequest(http.MethodGet, "/auth/github/callback?code=octo&state=forged", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("callback with an unknown state: status %d, want 400", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	gs.HandleAuth(recorder, httptest.NewRequest(http.MethodGet, "/auth/google/login", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unconfigured provider: status %d, want 404", recorder.Code)
+	}
+}
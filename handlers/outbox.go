@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// maxOutboxMessages bounds how many recent messages are kept per player, however short the window
+const maxOutboxMessages = 200
+
+// outbox numbers the messages pushed to a player and keeps recent ones to replay when they reconnect
+// Only pushed messages are numbered; direct replies such as errors, move acks and resyncs go to the asking connection alone
+type outbox struct {
+	seq       int             // Number of the latest message
+	delivered int             // Latest message written to an open connection
+	recent    []outboxMessage // Messages from the last ResumeWindow, oldest first
+}
+
+// outboxMessage is a numbered message kept for replay
+type outboxMessage struct {
+	msg    *models.GameMessage
+	sentAt time.Time
+}
+
+// numberMessage gives a message pushed to a player the next number in their stream and keeps it for replay
+// Returns a numbered copy, since one message is often pushed to several players; msg itself when replay is off
+func (gs *GameServer) numberMessage(playerID string, msg *models.GameMessage) *models.GameMessage {
+	if gs.config.ResumeWindow <= 0 {
+		return msg
+	}
+
+	box, exists := gs.outboxes[playerID]
+	if !exists {
+		box = &outbox{}
+		gs.outboxes[playerID] = box
+	}
+	box.seq++
+	numbered := *msg
+	numbered.Seq = box.seq
+
+	now := gs.clock.Now()
+	box.recent = append(box.recent, outboxMessage{msg: &numbered, sentAt: now})
+	gs.trimOutbox(box, now)
+	return &numbered
+}
+
+// markDelivered records that a player's message reached at least one open connection
+func (gs *GameServer) markDelivered(playerID string, seq int) {
+	if box, exists := gs.outboxes[playerID]; exists && seq > box.delivered {
+		box.delivered = seq
+	}
+}
+
+// trimOutbox drops messages older than the resume window, and the oldest beyond maxOutboxMessages
+func (gs *GameServer) trimOutbox(box *outbox, now time.Time) {
+	cutoff := now.Add(-gs.config.ResumeWindow)
+	drop := max(len(box.recent)-maxOutboxMessages, 0)
+	for drop < len(box.recent) && box.recent[drop].sentAt.Before(cutoff) {
+		drop++
+	}
+	box.recent = box.recent[drop:]
+}
+
+// replayOutbox sends a reconnecting player's new connection the messages pushed while they had none
+// Messages that outlived the window are gone; the client sees the gap in seq and asks for a resync
+func (gs *GameServer) replayOutbox(conn clientConn, playerID string) {
+	box, exists := gs.outboxes[playerID]
+	if !exists || box.delivered == box.seq {
+		return
+	}
+	gs.trimOutbox(box, gs.clock.Now())
+
+	replayed := 0
+	for _, buffered := range box.recent {
+		if buffered.msg.Seq > box.delivered {
+			gs.sendToClient(conn, buffered.msg)
+			replayed++
+		}
+	}
+	log.Printf("Replayed %d of %d missed messages to player %s", replayed, box.seq-box.delivered, playerID)
+	box.delivered = box.seq
+}
+
+// pruneOutboxes drops expired messages of players who stopped receiving new ones
+// The outboxes themselves stay so a returning player's numbering carries on
+func (gs *GameServer) pruneOutboxes() {
+	now := gs.clock.Now()
+	for _, box := range gs.outboxes {
+		gs.trimOutbox(box, now)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"tictactoe-server/models"
+)
+
+type announcement struct {
+	Message string `json:"message"`
+}
+
+func TestMissedMessagesReplayedOnReconnect(t *testing.T) {
+	cfg := testConfig()
+	cfg.ResumeWindow = 30 * time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	push := func(text string) {
+		gs.do(func() {
+			gs.sendToPlayer(alice.playerID, models.NewGameMessage(models.MSG_ANNOUNCEMENT, announcement{Message: text}))
+		})
+	}
+	push("delivered")
+	alice.expect(models.MSG_ANNOUNCEMENT, nil)
+
+	alice.conn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for connected := true; connected; {
+		if time.Now().After(deadline) {
+			t.Fatal("server never noticed the disconnect")
+		}
+		gs.do(func() { connected = gs.isConnected(alice.playerID) })
+	}
+
+	push("expired")
+	clk.Advance(cfg.ResumeWindow + time.Second)
+	push("kept 1")
+	push("kept 2")
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?v=5&playerId="+alice.playerID+"&token="+alice.token, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	resumed := &testClient{t: t, conn: conn}
+
+	// The expired message leaves a gap in seq for the client to notice
+	for _, want := range []struct {
+		seq  int
+		text string
+	}{{3, "kept 1"}, {4, "kept 2"}} {
+		var got announcement
+		msg := resumed.expect(models.MSG_ANNOUNCEMENT, &got)
+		if msg.Seq != want.seq || got.Message != want.text {
+			t.Errorf("replayed #%d %q, want #%d %q", msg.Seq, got.Message, want.seq, want.text)
+		}
+	}
+
+	push("live")
+	if msg := resumed.expect(models.MSG_ANNOUNCEMENT, nil); msg.Seq != 5 {
+		t.Errorf("live message seq = %d, want 5", msg.Seq)
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strings"
+
+	"tictactoe-server/models"
+)
+
+// maxHeadToHeadOpponents limits head-to-head records to the most recent opponents
+const maxHeadToHeadOpponents = 5
+
+// recordFinishedGame stores a finished game and both players' new ratings
+func (gs *GameServer) recordFinishedGame(gameInstance *models.Game) {
+	record := models.NewGameRecord(gameInstance)
+	gs.store.SaveGame(record)
+
+	// Only rated games move ratings, so only they extend the rating history, which charts the standard pool
+	if !gameInstance.Rated || gameInstance.RatingPool() != models.RATING_POOL_STANDARD {
+		return
+	}
+
+	for _, player := range []*models.Player{gameInstance.PlayerX(), gameInstance.PlayerO()} {
+		if player == nil {
+			continue
+		}
+		gs.store.AddRatingSnapshot(player.ID, models.RatingSnapshot{
+			Rating:    player.Rating(models.RATING_POOL_STANDARD),
+			GameID:    gameInstance.ID,
+			Timestamp: record.EndTime,
+		})
+	}
+}
+
+// handleGetProfile sends a player's profile; defaults to the requester's own profile
+func (gs *GameServer) handleGetProfile(conn clientConn, player *models.Player, request *models.GetProfilePayload) {
+	playerID := request.PlayerID
+	if playerID == "" {
+		playerID = player.ID
+	}
+
+	profile, exists := gs.buildProfile(playerID)
+	if !exists {
+		gs.sendClientError(conn, models.ERR_PLAYER_NOT_FOUND, "Player not found")
+		return
+	}
+
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_PROFILE, profile))
+}
+
+// HandlePlayerAPI serves GET /api/players/{id} and GET /api/players/{id}/rating-history,
+// and with the player's session token GET /api/players/{id}/export, DELETE /api/players/{id} and
+// POST and DELETE /api/players/{id}/push-subscriptions
+func (gs *GameServer) HandlePlayerAPI(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/players/"), "/")
+	playerID, resource, _ := strings.Cut(path, "/")
+	if playerID == "" {
+		http.Error(w, "player id required", http.StatusBadRequest)
+		return
+	}
+
+	if resource == "push-subscriptions" {
+		gs.handlePushSubscriptions(w, r, playerID)
+		return
+	}
+	if kind, provider, _ := strings.Cut(resource, "/"); kind == "identities" {
+		gs.handleIdentities(w, r, playerID, provider)
+		return
+	}
+	if r.Method == http.MethodDelete && resource == "" {
+		gs.handleDeleteAccount(w, r, playerID)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch resource {
+	case "":
+	case "rating-history":
+		gs.serveRatingHistory(w, r, playerID)
+		return
+	case "export":
+		gs.serveDataExport(w, r, playerID)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	var profile *models.PlayerProfile
+	var exists bool
+	gs.do(func() { profile, exists = gs.buildProfile(playerID) })
+	if !exists {
+		http.Error(w, "player not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, profile)
+}
+
+// buildProfile computes a player's profile from their stats and game history
+func (gs *GameServer) buildProfile(playerID string) (*models.PlayerProfile, bool) {
+	player, exists := gs.players.Player(playerID)
+	if !exists || player.Deleted {
+		return nil, false
+	}
+	// The profile is sent after later moves may have changed the player's stats
+	snapshot := *player
+	snapshot.Ratings = maps.Clone(player.Ratings)
+	snapshot.Glicko = maps.Clone(player.Glicko)
+	season := gs.season.Number
+
+	games := gs.store.GamesForPlayer(playerID)
+	profile := &models.PlayerProfile{
+		Player:        &snapshot,
+		GamesPlayed:   snapshot.RatedGames(),
+		CurrentStreak: snapshot.CurrentStreak,
+		LongestStreak: snapshot.LongestStreak,
+		RatingHistory: gs.store.RatingHistory(playerID),
+		HeadToHead:    make([]models.HeadToHead, 0),
+		Season:        season,
+		Badges:        gs.store.Badges(playerID),
+	}
+	if left := gs.config.PlacementGames - snapshot.SeasonGames; left > 0 {
+		profile.PlacementGamesLeft = left
+	}
+	if left := gs.config.ProvisionalGames - snapshot.RatedGames(); left > 0 {
+		profile.IsProvisional = true
+		profile.ProvisionalGamesLeft = left
+	}
+
+	if profile.GamesPlayed > 0 {
+		profile.WinRate = float64(snapshot.Wins) / float64(profile.GamesPlayed)
+	}
+	if snapshot.AnalyzedMoves > 0 {
+		profile.Accuracy = float64(snapshot.OptimalMoves) / float64(snapshot.AnalyzedMoves)
+	}
+	profile.TotalThinkMs = snapshot.ThinkMs
+	if snapshot.TimedMoves > 0 {
+		profile.AverageThinkMs = snapshot.ThinkMs / int64(snapshot.TimedMoves)
+	}
+
+	// Average duration and favorite symbol
+	var totalSeconds float64
+	symbolCounts := map[models.Symbol]int{}
+	for _, record := range games {
+		totalSeconds += record.Duration().Seconds()
+		symbolCounts[record.SymbolFor(playerID)]++
+	}
+	if len(games) > 0 {
+		profile.AverageGameSeconds = totalSeconds / float64(len(games))
+		if symbolCounts[models.SYMBOL_X] >= symbolCounts[models.SYMBOL_O] {
+			profile.FavoriteSymbol = models.SYMBOL_X
+		} else {
+			profile.FavoriteSymbol = models.SYMBOL_O
+		}
+	}
+
+	profile.HeadToHead = headToHeadRecords(playerID, games)
+
+	return profile, true
+}
+
+// headToHeadRecords returns records against the most recent distinct opponents, newest first
+func headToHeadRecords(playerID string, games []*models.GameRecord) []models.HeadToHead {
+	records := make([]models.HeadToHead, 0)
+	index := map[string]int{}
+
+	// Walk newest to oldest so the opponent list reflects recency
+	for i := len(games) - 1; i >= 0; i-- {
+		record := games[i]
+		if record.PlayerDeltaID != "" {
+			continue // Trio games have no single opponent
+		}
+		mySymbol := record.SymbolFor(playerID)
+
+		opponentID, opponentName := record.PlayerOID, record.PlayerOName
+		if mySymbol == models.SYMBOL_O {
+			opponentID, opponentName = record.PlayerXID, record.PlayerXName
+		}
+
+		pos, seen := index[opponentID]
+		if !seen {
+			if len(records) >= maxHeadToHeadOpponents {
+				continue
+			}
+			records = append(records, models.HeadToHead{OpponentID: opponentID, OpponentName: opponentName})
+			pos = len(records) - 1
+			index[opponentID] = pos
+		}
+
+		switch record.Winner {
+		case models.RESULT_DRAW:
+			records[pos].Draws++
+		case models.WinFor(mySymbol):
+			records[pos].Wins++
+		default:
+			records[pos].Losses++
+		}
+	}
+
+	return records
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
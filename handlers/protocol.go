@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// subprotocolPrefix namespaces the encoding subprotocols, e.g. "tictactoe.msgpack"
+const subprotocolPrefix = "tictactoe."
+
+// codecSubprotocols lists the WebSocket subprotocols offered during the upgrade
+var codecSubprotocols = []string{
+	subprotocolPrefix + models.ENCODING_JSON,
+	subprotocolPrefix + models.ENCODING_MSGPACK,
+	subprotocolPrefix + models.ENCODING_PROTOBUF,
+}
+
+// selectCodec picks a codec from the negotiated subprotocol, then the query parameter, defaulting to JSON
+func selectCodec(subprotocol, encoding string) models.Codec {
+	if codec, ok := models.CodecFor(strings.TrimPrefix(subprotocol, subprotocolPrefix)); ok && subprotocol != "" {
+		return codec
+	}
+	if codec, ok := models.CodecFor(encoding); ok {
+		return codec
+	}
+	return models.JSONCodec{}
+}
+
+// clientVersion returns the protocol version negotiated with a connection
+func (gs *GameServer) clientVersion(conn clientConn) int {
+	if version, ok := gs.clientVersions[conn]; ok {
+		return version
+	}
+	return models.PROTOCOL_VERSION_LEGACY
+}
+
+// handleHello negotiates the protocol version with a client
+func (gs *GameServer) handleHello(conn clientConn, player *models.Player, hello *models.HelloPayload) {
+	version, err := models.NegotiateVersion(*hello)
+	if err != nil {
+		reason := fmt.Sprintf("Unsupported protocol version, server supports %d-%d",
+			models.PROTOCOL_VERSION_MIN, models.PROTOCOL_VERSION_CURRENT)
+		log.Printf("Rejecting player %s: %s", player.ID, reason)
+
+		gs.sendClientError(conn, models.ERR_UNSUPPORTED_VERSION, reason)
+		gs.closeClient(conn, websocket.CloseProtocolError)
+		return
+	}
+
+	gs.clientVersions[conn] = version
+	log.Printf("Player %s negotiated protocol version %d", player.ID, version)
+
+	helloMsg := models.NewGameMessage(models.MSG_HELLO, map[string]interface{}{
+		"version":    version,
+		"minVersion": models.PROTOCOL_VERSION_MIN,
+		"maxVersion": models.PROTOCOL_VERSION_CURRENT,
+		"playerId":   player.ID,
+		"token":      player.SessionToken,
+	})
+	helloMsg.PlayerID = player.ID
+	gs.sendToClient(conn, helloMsg)
+}
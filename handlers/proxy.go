@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// requestIP returns the address of the client behind an HTTP request, see clientIP
+func (gs *GameServer) requestIP(r *http.Request) string {
+	return gs.clientIP(remoteIP(r), r.Header.Values("X-Forwarded-For"), r.Header.Get("X-Real-IP"))
+}
+
+// clientIP returns the address of the client behind any trusted proxies
+// Forwarding headers only count when the direct peer is a trusted proxy. X-Forwarded-For is read from
+// the right, skipping hops that are trusted proxies themselves, so a client can't pose as another address
+// by sending the header itself; X-Real-IP is used when there is no X-Forwarded-For
+func (gs *GameServer) clientIP(peer string, forwardedFor []string, realIP string) string {
+	proxies := gs.config.TrustedProxies
+	addr, ok := parseHop(peer)
+	if !ok || !proxies.Trusts(addr) {
+		return peer
+	}
+
+	var hops []string
+	for _, header := range forwardedFor {
+		for _, hop := range strings.Split(header, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	if len(hops) == 0 {
+		if ip, ok := parseHop(realIP); ok {
+			return ip.String()
+		}
+		return addr.String()
+	}
+
+	client := addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseHop(hops[i])
+		if !ok {
+			break // Whatever lies beyond a malformed entry can't be trusted
+		}
+		client = hop
+		if !proxies.Trusts(hop) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// parseHop reads an address from a forwarding header, which some proxies write with a port
+func parseHop(hop string) (netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(hop); err == nil {
+		return addr.Unmap(), true
+	}
+	if addrPort, err := netip.ParseAddrPort(hop); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	return netip.Addr{}, false
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"tictactoe-server/config"
+)
+
+func TestClientIPBehindTrustedProxies(t *testing.T) {
+	cfg := testConfig()
+	cfg.TrustedProxies = config.Proxies{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+	gs := &GameServer{config: cfg}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIP       string
+		want         string
+	}{
+		{"direct client ignores headers", "203.0.113.9:5000", []string{"198.51.100.1"}, "198.51.100.2", "203.0.113.9"},
+		{"proxy forwards client", "10.0.0.5:443", []string{"198.51.100.1"}, "", "198.51.100.1"},
+		{"spoofed entry left of the real client", "10.0.0.5:443", []string{"1.2.3.4, 198.51.100.1"}, "", "198.51.100.1"},
+		{"chain of proxies", "10.0.0.5:443", []string{"198.51.100.1, 10.1.1.1", "10.2.2.2"}, "", "198.51.100.1"},
+		{"hop with a port", "[::1]:443", []string{"198.51.100.1:61000"}, "", "198.51.100.1"},
+		{"garbage stops the walk", "10.0.0.5:443", []string{"198.51.100.1, bogus, 10.1.1.1"}, "", "10.1.1.1"},
+		{"real IP without forwarded for", "10.0.0.5:443", nil, "198.51.100.7", "198.51.100.7"},
+		{"nothing forwarded", "10.0.0.5:443", nil, "", "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		r.RemoteAddr = tt.remoteAddr
+		for _, value := range tt.forwardedFor {
+			r.Header.Add("X-Forwarded-For", value)
+		}
+		if tt.realIP != "" {
+			r.Header.Set("X-Real-IP", tt.realIP)
+		}
+		if got := gs.requestIP(r); got != tt.want {
+			t.Errorf("%s: client IP %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
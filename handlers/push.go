@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"tictactoe-server/models"
+	"tictactoe-server/push"
+)
+
+// registerPush sends push notifications to players without an open connection once VAPID keys are configured
+func (gs *GameServer) registerPush() {
+	if gs.config.VAPIDPublicKey == "" && gs.config.VAPIDPrivateKey == "" {
+		return
+	}
+
+	keys, err := push.ParseKeys(gs.config.VAPIDPublicKey, gs.config.VAPIDPrivateKey)
+	if err != nil {
+		log.Printf("Push notifications disabled: %v", err)
+		return
+	}
+	if gs.config.VAPIDSubject == "" {
+		log.Printf("Push notifications disabled: VAPID_SUBJECT is required")
+		return
+	}
+
+	gs.push = push.NewSender(keys, gs.config.VAPIDSubject, gs.config.PushTTL)
+	gs.push.OnGone = func(subscription *models.PushSubscription) {
+		gs.store.RemovePushSubscription(subscription.PlayerID, subscription.Endpoint)
+		log.Printf("Dropped expired push subscription of player %s", subscription.PlayerID)
+	}
+	gs.OnGameStarted(gs.pushMatchFound)
+}
+
+// pushToOffline sends a notification to every browser a player subscribed, unless they are connected
+func (gs *GameServer) pushToOffline(player *models.Player, notification push.Notification) {
+	if gs.push == nil || player.IsBot || gs.isConnected(player.ID) {
+		return
+	}
+	for _, subscription := range gs.store.PushSubscriptions(player.ID) {
+		gs.push.Send(subscription, notification)
+	}
+}
+
+// pushMatchFound tells players who aren't connected that a game of theirs has started
+func (gs *GameServer) pushMatchFound(gameInstance *models.Game) {
+	for _, player := range gameInstance.AllPlayers() {
+		gs.pushToOffline(player, push.Notification{
+			Kind:   push.KIND_MATCH_FOUND,
+			Title:  "Match found",
+			Body:   "Your game against " + opponentNames(gameInstance, player.ID) + " has started",
+			GameID: gameInstance.ID,
+		})
+	}
+}
+
+// pushYourTurn tells the side to move, if none of them is connected, that the game is waiting on them
+// Each turn is pushed at most once, however often the game state is sent during it
+func (gs *GameServer) pushYourTurn(gameInstance *models.Game) {
+	if gs.push == nil || !inProgress(gameInstance) {
+		return
+	}
+	turn := fmt.Sprintf("%s/%d", gameInstance.CurrentTurn, len(gameInstance.Moves))
+	if gs.pushedTurns[gameInstance.ID] == turn || gs.sideConnected(gameInstance, gameInstance.CurrentTurn) {
+		return
+	}
+
+	gs.pushedTurns[gameInstance.ID] = turn
+	for _, player := range gameInstance.SidePlayers(gameInstance.CurrentTurn) {
+		gs.pushToOffline(player, push.Notification{
+			Kind:   push.KIND_YOUR_TURN,
+			Title:  "Your turn",
+			Body:   opponentNames(gameInstance, player.ID) + " is waiting for your move",
+			GameID: gameInstance.ID,
+		})
+	}
+}
+
+// opponentNames lists the names of everyone playing against a player
+func opponentNames(gameInstance *models.Game, playerID string) string {
+	side := gameInstance.SideOf(playerID)
+	names := make([]string, 0)
+	for _, player := range gameInstance.AllPlayers() {
+		if gameInstance.SideOf(player.ID) != side {
+			names = append(names, player.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// closePush sends queued notifications before shutdown, giving up after webhookDrainTimeout
+func (gs *GameServer) closePush() {
+	if gs.push == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookDrainTimeout)
+	defer cancel()
+	if err := gs.push.Close(ctx); err != nil {
+		log.Printf("Push notifications abandoned at shutdown: %v", err)
+	}
+}
+
+// HandlePushKey serves GET /api/push/key, the public key browsers subscribe with
+func (gs *GameServer) HandlePushKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if gs.push == nil {
+		http.Error(w, "push notifications are not enabled", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"publicKey": gs.push.Keys.Public})
+}
+
+// handlePushSubscriptions serves POST and DELETE /api/players/{id}/push-subscriptions for the player whose
+// session token the request carries
+// POST takes the browser's PushSubscription JSON; DELETE takes {"endpoint": ...}
+func (gs *GameServer) handlePushSubscriptions(w http.ResponseWriter, r *http.Request, playerID string) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if gs.push == nil {
+		http.Error(w, "push notifications are not enabled", http.StatusNotFound)
+		return
+	}
+	if gs.authorizedPlayer(r, playerID) == nil {
+		http.Error(w, "invalid session", http.StatusUnauthorized)
+		return
+	}
+
+	var subscription models.PushSubscription
+	if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if !gs.store.RemovePushSubscription(playerID, subscription.Endpoint) {
+			http.Error(w, "subscription not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := subscription.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	subscription.PlayerID = playerID
+	gs.do(func() { subscription.CreatedAt = gs.clock.Now() })
+	gs.store.SavePushSubscription(&subscription)
+	log.Printf("Player %s subscribed to push notifications", playerID)
+	writeJSON(w, http.StatusCreated, subscription)
+}
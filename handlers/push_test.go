@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+	"tictactoe-server/push"
+)
+
+// subscribeRequest registers or removes a push subscription through the player API
+func subscribeRequest(gs *GameServer, method, playerID, token string, body interface{}) *httptest.ResponseRecorder {
+	encoded, _ := json.Marshal(body)
+	request := httptest.NewRequest(method, "/api/players/"+playerID+"/push-subscriptions", strings.NewReader(string(encoded)))
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	gs.HandlePlayerAPI(recorder, request)
+	return recorder
+}
+
+// browserSubscription returns a subscription with fresh browser keys
+func browserSubscription(t *testing.T, endpoint string) models.PushSubscription {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	return models.PushSubscription{
+		Endpoint: endpoint,
+		Keys: models.PushKeys{
+			P256dh: base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(auth),
+		},
+	}
+}
+
+func TestYourTurnIsPushedToADisconnectedPlayer(t *testing.T) {
+	pushed := make(chan string, 4)
+	service := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "aes128gcm" || !strings.HasPrefix(r.Header.Get("Authorization"), "vapid ") {
+			t.Errorf("push headers = %v", r.Header)
+		}
+		pushed <- r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer service.Close()
+
+	cfg := testConfig()
+	cfg.DisconnectGracePeriod = time.Minute
+	cfg.VAPIDPublicKey, cfg.VAPIDPrivateKey, _ = push.GenerateKeys()
+	cfg.VAPIDSubject = "mailto:ops@example.com"
+	gs, _, wsURL := newTestServer(t, cfg)
+	gs.push.Client = service.Client()
+
+	keyRequest := httptest.NewRecorder()
+	gs.HandlePushKey(keyRequest, httptest.NewRequest(http.MethodGet, "/api/push/key", nil))
+	if !strings.Contains(keyRequest.Body.String(), cfg.VAPIDPublicKey) {
+		t.Fatalf("push key = %q, want the VAPID public key", keyRequest.Body.String())
+	}
+
+	x, o, _ := startGame(t, wsURL, models.MODE_CASUAL)
+	if recorder := subscribeRequest(gs, http.MethodPost, x.playerID, o.token, browserSubscription(t, service.URL+"/x")); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("subscribing with another player's token = %d, want 401", recorder.Code)
+	}
+	if recorder := subscribeRequest(gs, http.MethodPost, x.playerID, x.token, browserSubscription(t, "http://insecure.example.com")); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("subscribing a plain http endpoint = %d, want 400", recorder.Code)
+	}
+	for _, client := range []*testClient{x, o} {
+		if recorder := subscribeRequest(gs, http.MethodPost, client.playerID, client.token, browserSubscription(t, service.URL+"/"+client.playerID)); recorder.Code != http.StatusCreated {
+			t.Fatalf("subscribing = %d: %s", recorder.Code, recorder.Body)
+		}
+	}
+
+	// X leaves on their turn; O is still connected and gets nothing
+	x.conn.Close()
+	o.expect(models.MSG_OPPONENT_DISCONNECTED, nil)
+	select {
+	case path := <-pushed:
+		if path != "/"+x.playerID {
+			t.Fatalf("pushed to %s, want only X", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push for X's turn")
+	}
+
+	if recorder := subscribeRequest(gs, http.MethodDelete, x.playerID, x.token, map[string]string{"endpoint": service.URL + "/" + x.playerID}); recorder.Code != http.StatusNoContent {
+		t.Fatalf("unsubscribing = %d, want 204", recorder.Code)
+	}
+	if subscriptions := gs.store.PushSubscriptions(x.playerID); len(subscriptions) != 0 {
+		t.Fatalf("%d subscriptions left after unsubscribing", len(subscriptions))
+	}
+}
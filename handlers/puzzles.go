@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// todaysPuzzle returns the puzzle of the current UTC day, generating it on first request
+// The generator is seeded with the date, so a restarted server poses the same puzzle
+func (gs *GameServer) todaysPuzzle() *models.Puzzle {
+	id := gs.clock.Now().UTC().Format(time.DateOnly)
+	if puzzle, exists := gs.store.Puzzle(id); exists {
+		return puzzle
+	}
+
+	seed := fnv.New64a()
+	seed.Write([]byte(id))
+	board, toMove, goal := gs.gameEngine.GeneratePuzzle(rand.New(rand.NewSource(int64(seed.Sum64()))))
+	puzzle := &models.Puzzle{ID: id, Board: board[:], ToMove: toMove, Goal: goal}
+	gs.store.SavePuzzle(puzzle)
+	return puzzle
+}
+
+// currentStreak is a player's streak as of today: it lapses once a day passes without a solve
+func (gs *GameServer) currentStreak(stats *models.PuzzleStats) int {
+	today := gs.clock.Now().UTC()
+	if stats.LastSolved == today.Format(time.DateOnly) || stats.LastSolved == today.AddDate(0, 0, -1).Format(time.DateOnly) {
+		return stats.Streak
+	}
+	return 0
+}
+
+// handleGetPuzzle sends today's puzzle with the player's record
+func (gs *GameServer) handleGetPuzzle(conn clientConn, player *models.Player) {
+	puzzle := gs.todaysPuzzle()
+	stats := gs.store.PuzzleStats(player.ID)
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_PUZZLE, models.PuzzleView{
+		Puzzle:      *puzzle,
+		SolvedToday: stats.LastSolved == puzzle.ID,
+		Streak:      gs.currentStreak(stats),
+	}))
+}
+
+// handleSolvePuzzle checks a solution line for today's puzzle and counts the first solve of the day
+// Wrong lines may be retried; only missing a day breaks the streak
+func (gs *GameServer) handleSolvePuzzle(conn clientConn, player *models.Player, request *models.SolvePuzzlePayload) {
+	puzzle := gs.todaysPuzzle()
+	if request.PuzzleID != puzzle.ID {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Only today's puzzle can be solved")
+		return
+	}
+
+	var board [9]models.Symbol
+	copy(board[:], puzzle.Board)
+	reason := gs.gameEngine.CheckPuzzleLine(board, puzzle.ToMove, puzzle.Goal, request.Moves)
+
+	stats := gs.store.PuzzleStats(player.ID)
+	if reason == "" && stats.LastSolved != puzzle.ID {
+		// Records are replaced, never changed, as the store hands out pointers
+		updated := *stats
+		updated.Streak = gs.currentStreak(stats) + 1
+		updated.Solved++
+		updated.BestStreak = max(updated.BestStreak, updated.Streak)
+		updated.LastSolved = puzzle.ID
+		gs.store.SavePuzzleStats(&updated)
+		stats = &updated
+		log.Printf("Player %s solved puzzle %s, streak %d", player.ID, puzzle.ID, updated.Streak)
+	}
+
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_PUZZLE_RESULT, models.PuzzleResult{
+		PuzzleID:   puzzle.ID,
+		Solved:     reason == "",
+		Reason:     reason,
+		Streak:     gs.currentStreak(stats),
+		BestStreak: stats.BestStreak,
+	}))
+}
+
+// puzzleLeaderboard ranks players by puzzles solved, then by best streak
+func (gs *GameServer) puzzleLeaderboard() []models.PuzzleStanding {
+	standings := make([]models.PuzzleStanding, 0)
+	for _, stats := range gs.store.AllPuzzleStats() {
+		player, exists := gs.players.Player(stats.PlayerID)
+		if !exists || player.Deleted {
+			continue
+		}
+		standings = append(standings, models.PuzzleStanding{
+			PlayerID:   stats.PlayerID,
+			Name:       player.Name,
+			Solved:     stats.Solved,
+			Streak:     gs.currentStreak(stats),
+			BestStreak: stats.BestStreak,
+		})
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Solved != b.Solved {
+			return a.Solved > b.Solved
+		}
+		if a.BestStreak != b.BestStreak {
+			return a.BestStreak > b.BestStreak
+		}
+		return a.Name < b.Name
+	})
+	if len(standings) > models.PUZZLE_LEADERBOARD_SIZE {
+		standings = standings[:models.PUZZLE_LEADERBOARD_SIZE]
+	}
+	return standings
+}
+
+// HandlePuzzleLeaderboard serves GET /api/puzzles/leaderboard
+func (gs *GameServer) HandlePuzzleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var standings []models.PuzzleStanding
+	gs.do(func() { standings = gs.puzzleLeaderboard() })
+	writeJSON(w, http.StatusOK, standings)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// puzzleSolution plays the solver's best moves for both sides of a puzzle until the game ends
+func puzzleSolution(gs *GameServer, puzzle models.PuzzleView) []int {
+	var board [9]models.Symbol
+	copy(board[:], puzzle.Board)
+	toMove := puzzle.ToMove
+	var line []int
+	for gs.gameEngine.CheckWinner(board) == "" && !gs.gameEngine.IsBoardFull(board) {
+		position := gs.gameEngine.BestMove(board, toMove, models.VARIANT_STANDARD)
+		board[position] = toMove
+		line = append(line, position)
+		toMove = map[models.Symbol]models.Symbol{models.SYMBOL_X: models.SYMBOL_O, models.SYMBOL_O: models.SYMBOL_X}[toMove]
+	}
+	return line
+}
+
+func TestDailyPuzzleStreak(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	var result models.PuzzleResult
+	for day := 1; day <= 2; day++ {
+		var puzzle models.PuzzleView
+		alice.send(models.MSG_GET_PUZZLE, nil)
+		alice.expect(models.MSG_PUZZLE, &puzzle)
+		if puzzle.ID != clk.Now().UTC().Format(time.DateOnly) || len(puzzle.Board) != 9 || puzzle.SolvedToday {
+			t.Fatalf("day %d puzzle = %+v", day, puzzle)
+		}
+
+		// A wrong line may be retried without breaking the streak
+		alice.send(models.MSG_SOLVE_PUZZLE, models.SolvePuzzlePayload{PuzzleID: puzzle.ID, Moves: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}})
+		alice.expect(models.MSG_PUZZLE_RESULT, &result)
+		if result.Solved || result.Reason == "" {
+			t.Fatalf("day %d: nonsense line = %+v", day, result)
+		}
+
+		alice.send(models.MSG_SOLVE_PUZZLE, models.SolvePuzzlePayload{PuzzleID: puzzle.ID, Moves: puzzleSolution(gs, puzzle)})
+		alice.expect(models.MSG_PUZZLE_RESULT, &result)
+		if !result.Solved || result.Streak != day {
+			t.Fatalf("day %d: solution = %+v, want streak %d", day, result, day)
+		}
+		clk.Advance(24 * time.Hour)
+	}
+
+	// A day without a solve ends the streak
+	clk.Advance(24 * time.Hour)
+	recorder := httptest.NewRecorder()
+	gs.HandlePuzzleLeaderboard(recorder, httptest.NewRequest(http.MethodGet, "/api/puzzles/leaderboard", nil))
+	var standings []models.PuzzleStanding
+	if err := json.Unmarshal(recorder.Body.Bytes(), &standings); err != nil {
+		t.Fatal(err)
+	}
+	if len(standings) != 1 || standings[0].Name != "alice" || standings[0].Solved != 2 ||
+		standings[0].Streak != 0 || standings[0].BestStreak != 2 {
+		t.Errorf("puzzle leaderboard = %+v", standings)
+	}
+}
+
+func TestOnlyTodaysPuzzleCounts(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	alice.send(models.MSG_SOLVE_PUZZLE, models.SolvePuzzlePayload{PuzzleID: "2000-01-01", Moves: []int{4}})
+	var refused models.ErrorPayload
+	alice.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_NOT_ALLOWED {
+		t.Errorf("old puzzle = %+v", refused)
+	}
+}
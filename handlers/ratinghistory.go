@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"tictactoe-server/models"
+)
+
+// handleGetRatingHistory sends a player's rating history for charting; defaults to the requester's own
+func (gs *GameServer) handleGetRatingHistory(conn clientConn, player *models.Player, request *models.RatingHistoryPayload) {
+	playerID := request.PlayerID
+	if playerID == "" {
+		playerID = player.ID
+	}
+
+	history, exists := gs.buildRatingHistory(playerID, request.Points)
+	if !exists {
+		gs.sendClientError(conn, models.ERR_PLAYER_NOT_FOUND, "Player not found")
+		return
+	}
+
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_RATING_HISTORY, history))
+}
+
+// serveRatingHistory serves GET /api/players/{id}/rating-history, thinned to ?points= snapshots
+func (gs *GameServer) serveRatingHistory(w http.ResponseWriter, r *http.Request, playerID string) {
+	request := &models.RatingHistoryPayload{PlayerID: playerID}
+	if points := r.URL.Query().Get("points"); points != "" {
+		n, err := strconv.Atoi(points)
+		if err != nil {
+			http.Error(w, "points must be a number", http.StatusBadRequest)
+			return
+		}
+		request.Points = n
+	}
+	if err := request.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var history *models.RatingHistory
+	var exists bool
+	gs.do(func() { history, exists = gs.buildRatingHistory(playerID, request.Points) })
+	if !exists {
+		http.Error(w, "player not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// buildRatingHistory returns a player's rating snapshots, downsampled to at most maxPoints
+func (gs *GameServer) buildRatingHistory(playerID string, maxPoints int) (*models.RatingHistory, bool) {
+	if player, exists := gs.players.Player(playerID); !exists || player.Deleted {
+		return nil, false
+	}
+
+	snapshots := gs.store.RatingHistory(playerID)
+	points := models.DownsampleRatings(snapshots, maxPoints)
+	if points == nil {
+		points = make([]models.RatingSnapshot, 0)
+	}
+	return &models.RatingHistory{
+		PlayerID:    playerID,
+		Points:      points,
+		Total:       len(snapshots),
+		Downsampled: len(points) < len(snapshots),
+	}, true
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestRatingHistoryDownsamples(t *testing.T) {
+	gs, clk, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	gs.do(func() {
+		for i := 0; i < 150; i++ {
+			gs.store.AddRatingSnapshot(alice.playerID, models.RatingSnapshot{
+				Rating:    1200 + i,
+				Timestamp: clk.Now().Add(time.Duration(i) * time.Minute),
+			})
+		}
+	})
+
+	alice.send(models.MSG_GET_RATING_HISTORY, models.RatingHistoryPayload{Points: 10})
+	var history models.RatingHistory
+	alice.expect(models.MSG_RATING_HISTORY, &history)
+	if history.PlayerID != alice.playerID || history.Total != 150 || !history.Downsampled || len(history.Points) != 10 {
+		t.Fatalf("history = %+v", history)
+	}
+	if first, last := history.Points[0].Rating, history.Points[9].Rating; first != 1200 || last != 1349 {
+		t.Errorf("history spans %d to %d, want 1200 to 1349", first, last)
+	}
+
+	recorder := httptest.NewRecorder()
+	gs.HandlePlayerAPI(recorder, httptest.NewRequest(http.MethodGet, "/api/players/"+alice.playerID+"/rating-history", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", recorder.Code, recorder.Body)
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Points) != models.DEFAULT_RATING_HISTORY_POINTS {
+		t.Errorf("API returned %d points, want %d", len(history.Points), models.DEFAULT_RATING_HISTORY_POINTS)
+	}
+
+	for path, want := range map[string]int{
+		"/api/players/" + alice.playerID + "/rating-history?points=1": http.StatusBadRequest,
+		"/api/players/nobody/rating-history":                          http.StatusNotFound,
+		"/api/players/" + alice.playerID + "/unknown":                 http.StatusNotFound,
+	} {
+		recorder := httptest.NewRecorder()
+		gs.HandlePlayerAPI(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != want {
+			t.Errorf("%s: status %d, want %d", path, recorder.Code, want)
+		}
+	}
+}
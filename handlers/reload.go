@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// ReloadConfig reads the configuration again and applies its RELOADABLE settings to the running server,
+// keeping every connection, game and queue; changes are logged and kept in the audit log at /admin/config/reloads
+// An invalid configuration is rejected whole and changes nothing
+func (gs *GameServer) ReloadConfig(source string) ([]models.SettingChange, error) {
+	next, err := config.Reload()
+	if err != nil {
+		log.Printf("Config reload from %s rejected: %v", source, err)
+		return nil, err
+	}
+
+	var changes []models.SettingChange
+	gs.do(func() {
+		changes = gs.config.Apply(next)
+		gs.storeOrigins()
+	})
+	if len(changes) == 0 {
+		log.Printf("Config reload from %s changed nothing", source)
+		return []models.SettingChange{}, nil
+	}
+
+	for _, change := range changes {
+		log.Printf("Config reload from %s: %s %s -> %s", source, change.Setting, change.From, change.To)
+	}
+	gs.store.AddConfigReload(&models.ConfigReload{Time: gs.clock.Now(), Source: source, Changes: changes})
+	return changes, nil
+}
+
+// storeOrigins publishes the configured origin lists to the HTTP handlers
+func (gs *GameServer) storeOrigins() {
+	allowed, webSocket := gs.config.AllowedOrigins, gs.config.WebSocketOrigins
+	gs.allowedOrigins.Store(&allowed)
+	gs.webSocketOrigins.Store(&webSocket)
+}
+
+// AllowsOrigin reports whether the REST API answers cross-origin requests from an origin, following reloads
+func (gs *GameServer) AllowsOrigin(origin string) bool {
+	return gs.allowedOrigins.Load().Allows(origin)
+}
+
+// checkWebSocketOrigin decides whether a browser may open a WebSocket connection
+func (gs *GameServer) checkWebSocketOrigin(r *http.Request) bool {
+	// Non-browser clients send no Origin; only browsers are subject to the origin list
+	origin := r.Header.Get("Origin")
+	return origin == "" || gs.webSocketOrigins.Load().Allows(origin)
+}
+
+// handleAdminConfigReload reloads the configuration on POST, answering with what changed
+func (gs *GameServer) handleAdminConfigReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	changes, err := gs.ReloadConfig(models.RELOAD_ADMIN)
+	if err != nil {
+		http.Error(w, "invalid configuration: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	gs.auditAdmin(r, models.ADMIN_RELOAD_CONFIG, "", map[string]interface{}{"changes": changes})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"changes": changes})
+}
+
+// handleAdminConfigReloads lists the reloads that changed something, oldest first
+func (gs *GameServer) handleAdminConfigReloads(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, gs.store.ConfigReloads())
+}
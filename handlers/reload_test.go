@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestConfigReloadKeepsConnections(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+
+	path := filepath.Join(t.TempDir(), "server.env")
+	t.Setenv("CONFIG_FILE", path)
+	reload := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		gs.handleAdminConfigReload(recorder, httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil))
+		return recorder
+	}
+
+	if err := os.WriteFile(path, []byte("MAX_CONNECTIONS=lots\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if recorder := reload(); recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reloading an invalid file = %d, want 422", recorder.Code)
+	}
+	if reloads := gs.store.ConfigReloads(); len(reloads) != 0 {
+		t.Fatalf("rejected reload was recorded: %+v", reloads)
+	}
+
+	if err := os.WriteFile(path, []byte("MAX_CONNECTIONS=1\nPORT=9999\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	recorder := reload()
+	var body struct {
+		Changes []models.SettingChange `json:"changes"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); recorder.Code != http.StatusOK || err != nil {
+		t.Fatalf("reload = %d %s", recorder.Code, recorder.Body)
+	}
+	var changedLimit bool
+	for _, change := range body.Changes {
+		changedLimit = changedLimit || change.Setting == "MaxConnections" && change.To == "1"
+		if change.Setting == "Port" {
+			t.Errorf("Port was reloaded: %+v", change)
+		}
+	}
+	if !changedLimit {
+		t.Errorf("changes = %+v, want MaxConnections -> 1", body.Changes)
+	}
+
+	var limit int
+	gs.do(func() { limit = gs.config.MaxConnections })
+	if limit != 1 {
+		t.Errorf("MaxConnections = %d after reload, want 1", limit)
+	}
+	if reloads := gs.store.ConfigReloads(); len(reloads) != 1 || reloads[0].Source != models.RELOAD_ADMIN {
+		t.Errorf("audit log = %+v, want one admin reload", reloads)
+	}
+
+	// The player already connected keeps playing under the new limit
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+	"tictactoe-server/webhooks"
+)
+
+// turnReminder is the reminder pending for one turn of a game
+type turnReminder struct {
+	turn  models.Symbol // Symbol to move
+	moves int           // Moves played when the turn began, telling apart two turns of the same symbol
+	timer clock.Timer
+}
+
+// startTurnReminders sets how long a new game's turns may run before a reminder and arms the first one
+// Blitz games get none, as their clock already tells the player to hurry, and neither do correspondence games
+func (gs *GameServer) startTurnReminders(gameInstance *models.Game) {
+	if gameInstance.TimeLeft == nil && !gameInstance.IsCorrespondence() {
+		gameInstance.ReminderAfter = gs.config.TurnReminderAfter
+	}
+	gs.scheduleTurnReminder(gameInstance)
+}
+
+// scheduleTurnReminder arms the reminder for the turn a game is waiting on
+// Calling it again during the same turn keeps the pending reminder, so each turn is reminded at most once
+func (gs *GameServer) scheduleTurnReminder(gameInstance *models.Game) {
+	if gameInstance.Status != models.STATUS_PLAYING || gameInstance.ReminderAfter <= 0 || gs.botToMove(gameInstance) != nil {
+		gs.stopTurnReminder(gameInstance.ID)
+		return
+	}
+
+	moves := len(gameInstance.Moves)
+	if pending, exists := gs.turnReminders[gameInstance.ID]; exists {
+		if pending.turn == gameInstance.CurrentTurn && pending.moves == moves {
+			return
+		}
+		pending.timer.Stop()
+	}
+
+	reminder := &turnReminder{turn: gameInstance.CurrentTurn, moves: moves}
+	reminder.timer = gs.clock.AfterFunc(gameInstance.ReminderAfter, gs.doLater(func() {
+		gs.sendTurnReminder(gameInstance, reminder)
+	}))
+	gs.turnReminders[gameInstance.ID] = reminder
+}
+
+// sendTurnReminder tells the side to move that the game is waiting on them
+func (gs *GameServer) sendTurnReminder(gameInstance *models.Game, reminder *turnReminder) {
+	if gs.turnReminders[gameInstance.ID] != reminder {
+		return
+	}
+	// The entry stays until the turn changes, marking this turn as reminded
+	if gameInstance.Status != models.STATUS_PLAYING || gameInstance.CurrentTurn != reminder.turn || len(gameInstance.Moves) != reminder.moves {
+		return
+	}
+
+	data := models.TurnReminder{
+		GameID:    gameInstance.ID,
+		Symbol:    reminder.turn,
+		WaitingMs: gameInstance.ReminderAfter.Milliseconds(),
+	}
+	playerIDs := make([]string, 0)
+	for _, player := range gameInstance.SidePlayers(reminder.turn) {
+		if player.IsBot {
+			continue
+		}
+		playerIDs = append(playerIDs, player.ID)
+		for conn := range gs.playerConns[player.ID] {
+			if models.SupportsMessage(gs.clientVersion(conn), models.MSG_TURN_REMINDER) {
+				gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_TURN_REMINDER, gameInstance.ID, data))
+			}
+		}
+	}
+	log.Printf("Reminded %s in game %s to move", reminder.turn, gameInstance.ID)
+
+	if gs.webhooks != nil {
+		gs.webhooks.Send(webhooks.EVENT_TURN_REMINDER, map[string]interface{}{
+			"gameId":    gameInstance.ID,
+			"symbol":    reminder.turn,
+			"playerIds": playerIDs,
+			"waitingMs": data.WaitingMs,
+		})
+	}
+}
+
+// stopTurnReminder cancels a game's pending reminder
+func (gs *GameServer) stopTurnReminder(gameID string) {
+	if reminder, exists := gs.turnReminders[gameID]; exists {
+		reminder.timer.Stop()
+		delete(gs.turnReminders, gameID)
+	}
+}
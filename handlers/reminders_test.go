@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestTurnReminderGoesToThePlayerToMove(t *testing.T) {
+	cfg := testConfig()
+	cfg.TurnReminderAfter = 30 * time.Second
+	_, clk, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	var reminder models.TurnReminder
+	clk.Advance(cfg.TurnReminderAfter)
+	x.expect(models.MSG_TURN_REMINDER, &reminder)
+	if reminder.GameID != gameID || reminder.Symbol != "X" || reminder.WaitingMs != cfg.TurnReminderAfter.Milliseconds() {
+		t.Fatalf("reminder = %+v, want X reminded after 30s", reminder)
+	}
+
+	playMove(t, x, x, o, gameID, 4)
+	clk.Advance(cfg.TurnReminderAfter)
+	o.expect(models.MSG_TURN_REMINDER, &reminder)
+	if reminder.Symbol != "O" {
+		t.Fatalf("reminder after X moved = %+v, want O reminded", reminder)
+	}
+}
+
+func TestBlitzGamesSendNoTurnReminders(t *testing.T) {
+	cfg := testConfig()
+	cfg.TurnReminderAfter = 30 * time.Second
+	cfg.BlitzTime = time.Minute
+	gs, _, wsURL := newTestServer(t, cfg)
+	_, _, gameID := startGame(t, wsURL, models.MODE_BLITZ)
+
+	gs.do(func() {
+		if after := testGame(gs, gameID).ReminderAfter; after != 0 {
+			t.Errorf("blitz game reminds after %v, want no reminders", after)
+		}
+		if _, pending := gs.turnReminders[gameID]; pending {
+			t.Error("blitz game has a pending turn reminder")
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+)
+
+// replay is a finished game being played back to one connection from its event log
+type replay struct {
+	gameID   string
+	size     int
+	variant  string
+	players  []models.ReplayPlayer
+	moves    []models.GameEvent // The move events of the final line of play, in order
+	winner   models.Result
+	started  time.Time
+	ended    time.Time
+	board    []models.Symbol
+	next     int           // Index of the next move to send
+	step     bool          // Moves are sent one per replay_next
+	interval time.Duration // Time between timed moves; 0 sends them all at once
+	timer    clock.Timer   // Fires when the next timed move is due
+}
+
+// eventText reads a string from event data, which stores keep as the typed value logged, such as a models.Symbol,
+// or decode from JSON as a plain string
+func eventText(data map[string]interface{}, key string) string {
+	switch value := data[key].(type) {
+	case models.Symbol:
+		return string(value)
+	case models.Result:
+		return string(value)
+	case string:
+		return value
+	}
+	return ""
+}
+
+// eventInt reads a whole number from event data, which stores keep as int or decode from JSON as float64
+func eventInt(data map[string]interface{}, key string) (int, bool) {
+	switch value := data[key].(type) {
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	}
+	return 0, false
+}
+
+// loadReplay rebuilds the moves of a finished game from its event log, leaving out moves taken back
+// Returns false when the log has expired or the game never finished
+func loadReplay(events []models.GameEvent) (*replay, bool) {
+	r := &replay{size: 3, variant: models.VARIANT_STANDARD}
+	finished := false
+	for _, event := range events {
+		r.gameID = event.GameID
+		switch event.Type {
+		case models.EVENT_CREATED:
+			if size, ok := eventInt(event.Data, "size"); ok && size > 0 {
+				r.size = size
+			}
+			if variant := eventText(event.Data, "variant"); variant != "" {
+				r.variant = variant
+			}
+			r.started = event.Timestamp
+		case models.EVENT_PLAYER_JOINED:
+			name, _ := event.Data["name"].(string)
+			symbol := models.Symbol(eventText(event.Data, "symbol"))
+			isBot, _ := event.Data["isBot"].(bool)
+			r.players = append(r.players, models.ReplayPlayer{PlayerID: event.PlayerID, Name: name, Symbol: symbol, IsBot: isBot})
+		case models.EVENT_MOVE:
+			r.moves = append(r.moves, event)
+		case models.EVENT_TAKEBACK_ACCEPTED:
+			// As in UndoLastMove: the requester's last move goes, with the reply after it if there was one.
+			// The event names whoever agreed, so the requester's move is the latest one not theirs.
+			for i := len(r.moves) - 1; i >= 0 && i >= len(r.moves)-2; i-- {
+				if r.moves[i].PlayerID != event.PlayerID {
+					r.moves = r.moves[:i]
+					break
+				}
+			}
+		case models.EVENT_FINISHED:
+			r.winner = models.Result(eventText(event.Data, "winner"))
+			r.ended = event.Timestamp
+			finished = true
+		}
+	}
+	if !finished {
+		return nil, false
+	}
+	r.board = make([]models.Symbol, r.size*r.size)
+	return r, true
+}
+
+// handleReplayGame starts playing a finished game back to the connection, replacing any replay it was watching
+func (gs *GameServer) handleReplayGame(conn clientConn, request *models.ReplayPayload) {
+	r, ok := loadReplay(gs.store.GameEvents(request.GameID))
+	if !ok {
+		gs.sendClientError(conn, models.ERR_GAME_NOT_FOUND, "No finished game to replay")
+		return
+	}
+	gs.stopReplay(conn)
+
+	r.step = request.Step
+	if !r.step {
+		r.interval = time.Duration(float64(gs.config.ReplayMoveInterval) / request.Speed)
+	}
+	gs.replays[conn] = r
+	gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_REPLAY_STARTED, r.gameID, models.ReplayStarted{
+		GameID:     r.gameID,
+		Size:       r.size,
+		Players:    r.players,
+		TotalMoves: len(r.moves),
+		Step:       r.step,
+		IntervalMs: r.interval.Milliseconds(),
+	}))
+
+	switch {
+	case len(r.moves) == 0:
+		gs.finishReplay(conn, r)
+	case r.step:
+	case r.interval > 0:
+		gs.scheduleReplayMove(conn, r)
+	default:
+		gs.advanceReplay(conn, r)
+	}
+}
+
+// handleReplayNext sends the next move of a step-by-step replay
+func (gs *GameServer) handleReplayNext(conn clientConn, request *models.GamePayload) {
+	r, exists := gs.replays[conn]
+	if !exists || r.gameID != request.GameID || !r.step {
+		gs.sendClientError(conn, models.ERR_NOTHING_PENDING, "No step-by-step replay of this game")
+		return
+	}
+	gs.advanceReplay(conn, r)
+}
+
+// scheduleReplayMove arms the timer for a timed replay's next move
+func (gs *GameServer) scheduleReplayMove(conn clientConn, r *replay) {
+	r.timer = gs.clock.AfterFunc(r.interval, gs.doLater(func() {
+		// A newer replay or a disconnect may have taken this one's place
+		if gs.replays[conn] == r {
+			gs.advanceReplay(conn, r)
+		}
+	}))
+}
+
+// advanceReplay sends the next move, then waits for the next step or timer; without an interval it sends the rest at once
+func (gs *GameServer) advanceReplay(conn clientConn, r *replay) {
+	r.timer = nil
+	for {
+		event := r.moves[r.next]
+		r.next++
+		symbol := models.Symbol(eventText(event.Data, "symbol"))
+		position, _ := eventInt(event.Data, "position")
+		if position >= 0 && position < len(r.board) {
+			r.board[position] = symbol
+		}
+		gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_REPLAY_MOVE, r.gameID, models.ReplayMove{
+			GameID:     r.gameID,
+			MoveNumber: r.next,
+			PlayerID:   event.PlayerID,
+			Symbol:     symbol,
+			Position:   position,
+			Board:      append([]models.Symbol(nil), r.board...),
+		}))
+
+		switch {
+		case r.next == len(r.moves):
+			gs.finishReplay(conn, r)
+			return
+		case r.step:
+			return
+		case r.interval > 0:
+			gs.scheduleReplayMove(conn, r)
+			return
+		}
+	}
+}
+
+// finishReplay tells the connection the replay is over and forgets it
+func (gs *GameServer) finishReplay(conn clientConn, r *replay) {
+	gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_REPLAY_FINISHED, r.gameID,
+		models.ReplayFinished{GameID: r.gameID, Winner: r.winner}))
+	delete(gs.replays, conn)
+}
+
+// stopReplay abandons the connection's replay, if any
+func (gs *GameServer) stopReplay(conn clientConn) {
+	if r, exists := gs.replays[conn]; exists {
+		if r.timer != nil {
+			r.timer.Stop()
+		}
+		delete(gs.replays, conn)
+	}
+}
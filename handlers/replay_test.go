@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// finishTestGame plays a casual game X wins along the top row, with one takeback on the way
+func finishTestGame(t *testing.T, wsURL string) string {
+	t.Helper()
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	playMove(t, x, x, o, gameID, 8)
+	playMove(t, o, x, o, gameID, 6)
+	x.send(models.MSG_REQUEST_TAKEBACK, models.GamePayload{GameID: gameID})
+	o.expect(models.MSG_TAKEBACK_REQUESTED, nil)
+	o.send(models.MSG_ACCEPT_TAKEBACK, models.GamePayload{GameID: gameID})
+	for i := 0; i < 2; i++ {
+		x.expect(models.MSG_GAME_UPDATE, nil)
+		o.expect(models.MSG_GAME_UPDATE, nil)
+	}
+
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+	return gameID
+}
+
+func TestStepThroughReplay(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	gameID := finishTestGame(t, wsURL)
+
+	viewer := dialTestClient(t, wsURL, "name=carol")
+	viewer.send(models.MSG_REPLAY_GAME, models.ReplayPayload{GameID: gameID, Step: true})
+	var started models.ReplayStarted
+	viewer.expect(models.MSG_REPLAY_STARTED, &started)
+	if started.TotalMoves != 5 || started.Size != 3 || len(started.Players) != 2 || !started.Step {
+		t.Fatalf("replay_started = %+v, want 5 moves by 2 players on a 3x3 board", started)
+	}
+
+	var move models.ReplayMove
+	for want, position := range []int{0, 3, 1, 4, 2} {
+		viewer.send(models.MSG_REPLAY_NEXT, models.GamePayload{GameID: gameID})
+		viewer.expect(models.MSG_REPLAY_MOVE, &move)
+		if move.MoveNumber != want+1 || move.Position != position {
+			t.Fatalf("replay_move = %+v, want move %d at %d", move, want+1, position)
+		}
+	}
+	if move.Board[0] != "X" || move.Board[4] != "O" || move.Board[8] != "" {
+		t.Errorf("final board = %v, want the taken-back move left out", move.Board)
+	}
+
+	var finished models.ReplayFinished
+	viewer.expect(models.MSG_REPLAY_FINISHED, &finished)
+	if finished.Winner != "X" {
+		t.Errorf("winner = %q, want X", finished.Winner)
+	}
+
+	viewer.send(models.MSG_REPLAY_NEXT, models.GamePayload{GameID: gameID})
+	var refused models.ErrorPayload
+	viewer.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_NOTHING_PENDING {
+		t.Errorf("replay_next after the end = %+v", refused)
+	}
+}
+
+func TestTimedReplayFollowsSpeed(t *testing.T) {
+	cfg := testConfig()
+	cfg.ReplayMoveInterval = time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+	gameID := finishTestGame(t, wsURL)
+
+	viewer := dialTestClient(t, wsURL, "name=carol")
+	viewer.send(models.MSG_REPLAY_GAME, models.ReplayPayload{GameID: gameID, Speed: 2})
+	var started models.ReplayStarted
+	viewer.expect(models.MSG_REPLAY_STARTED, &started)
+	if started.IntervalMs != 500 {
+		t.Fatalf("interval = %dms, want 500", started.IntervalMs)
+	}
+
+	for want := 1; want <= 5; want++ {
+		gs.do(func() {}) // The next move's timer is armed once the hub is done with the last
+		clk.Advance(500 * time.Millisecond)
+		var move models.ReplayMove
+		viewer.expect(models.MSG_REPLAY_MOVE, &move)
+		if move.MoveNumber != want {
+			t.Fatalf("replay_move %d arrived as move %d", want, move.MoveNumber)
+		}
+	}
+	viewer.expect(models.MSG_REPLAY_FINISHED, nil)
+}
+
+func TestReplayNeedsFinishedGame(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	_, _, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	viewer := dialTestClient(t, wsURL, "name=carol")
+	viewer.send(models.MSG_REPLAY_GAME, models.ReplayPayload{GameID: gameID})
+	var refused models.ErrorPayload
+	viewer.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_GAME_NOT_FOUND {
+		t.Errorf("replay of a game in progress = %+v", refused)
+	}
+}
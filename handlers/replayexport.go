@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tictactoe-server/models"
+)
+
+// exportLineWidth is where the moves of a text export wrap, as in PGN
+const exportLineWidth = 80
+
+// pgnEscaper escapes tag pair values in text exports
+var pgnEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// HandleGamesAPI serves GET /api/games/{id}/export, a finished game rendered in one of EXPORT_FORMATS chosen with
+// ?format= (json by default), for as long as the game's event log is kept
+func (gs *GameServer) HandleGamesAPI(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/games/"), "/")
+	gameID, resource, _ := strings.Cut(path, "/")
+	if gameID == "" || resource != "export" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = models.EXPORT_JSON
+	}
+	if !slices.Contains(models.EXPORT_FORMATS, format) {
+		http.Error(w, "format must be one of "+strings.Join(models.EXPORT_FORMATS, ", "), http.StatusBadRequest)
+		return
+	}
+
+	replay, ok := loadReplay(gs.store.GameEvents(gameID))
+	if !ok {
+		http.Error(w, "no finished game to export", http.StatusNotFound)
+		return
+	}
+	export := replay.export()
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="game-%s.%s"`, export.GameID, exportExtension(format)))
+	switch format {
+	case models.EXPORT_JSON:
+		writeJSON(w, http.StatusOK, export)
+	case models.EXPORT_TEXT:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(exportText(export)))
+	case models.EXPORT_SVG:
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write([]byte(exportSVG(export)))
+	case models.EXPORT_GIF:
+		w.Header().Set("Content-Type", "image/gif")
+		if err := exportGIF(w, export); err != nil {
+			http.Error(w, "could not render game", http.StatusInternalServerError)
+		}
+	}
+}
+
+// exportExtension is the file extension of an export format
+func exportExtension(format string) string {
+	if format == models.EXPORT_TEXT {
+		return "txt"
+	}
+	return format
+}
+
+// export lists a replay's moves with their squares and times
+func (r *replay) export() *models.ReplayExport {
+	export := &models.ReplayExport{
+		GameID:    r.gameID,
+		Size:      r.size,
+		Variant:   r.variant,
+		Players:   r.players,
+		Moves:     make([]models.ExportedMove, 0, len(r.moves)),
+		Winner:    r.winner,
+		StartTime: r.started,
+		EndTime:   r.ended,
+	}
+	for i, event := range r.moves {
+		position, _ := eventInt(event.Data, "position")
+		export.Moves = append(export.Moves, models.ExportedMove{
+			MoveNumber: i + 1,
+			PlayerID:   event.PlayerID,
+			Symbol:     models.Symbol(eventText(event.Data, "symbol")),
+			Position:   position,
+			Square:     squareName(position, r.size),
+			Timestamp:  event.Timestamp,
+		})
+	}
+	return export
+}
+
+// squareName names a cell by its column letter and row number, "a1" being the top left cell
+func squareName(position, size int) string {
+	return string(rune('a'+position%size)) + strconv.Itoa(position/size+1)
+}
+
+// exportResult is the result of an exported game in text notation: the winning symbol, "draw", or "*" for none
+func exportResult(winner models.Result) string {
+	if winner == models.RESULT_NONE {
+		return "*"
+	}
+	return string(winner)
+}
+
+// exportText renders a game in PGN-like notation: tag pairs, a blank line, then the moves numbered by round
+// Each side's players are named in one tag, separated by commas in team games
+func exportText(export *models.ReplayExport) string {
+	var text strings.Builder
+	tag := func(name, value string) {
+		fmt.Fprintf(&text, "[%s \"%s\"]\n", name, pgnEscaper.Replace(value))
+	}
+	tag("Game", export.GameID)
+	tag("Date", export.StartTime.UTC().Format("2006.01.02"))
+	tag("Variant", export.Variant)
+	tag("Size", strconv.Itoa(export.Size))
+
+	sides := 0
+	for _, symbol := range models.Symbols {
+		var names []string
+		for _, player := range export.Players {
+			if player.Symbol == symbol {
+				names = append(names, player.Name)
+			}
+		}
+		if len(names) > 0 {
+			tag(string(symbol), strings.Join(names, ", "))
+			sides++
+		}
+	}
+	tag("Result", exportResult(export.Winner))
+	text.WriteString("\n")
+
+	tokens := make([]string, 0, len(export.Moves)+1)
+	for i, move := range export.Moves {
+		if sides > 0 && i%sides == 0 {
+			tokens = append(tokens, strconv.Itoa(i/sides+1)+".")
+		}
+		tokens = append(tokens, move.Square)
+	}
+	tokens = append(tokens, exportResult(export.Winner))
+
+	width := 0
+	for i, token := range tokens {
+		if i > 0 && width+1+len(token) > exportLineWidth {
+			text.WriteString("\n")
+			width = 0
+		} else if i > 0 {
+			text.WriteString(" ")
+			width++
+		}
+		text.WriteString(token)
+		width += len(token)
+	}
+	text.WriteString("\n")
+	return text.String()
+}
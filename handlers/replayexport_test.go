@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func exportGame(gs *GameServer, gameID, format string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	gs.HandleGamesAPI(recorder, httptest.NewRequest(http.MethodGet, "/api/games/"+gameID+"/export?format="+format, nil))
+	return recorder
+}
+
+func TestExportFinishedGame(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	gameID := finishTestGame(t, wsURL)
+
+	recorder := exportGame(gs, gameID, "")
+	var export models.ReplayExport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &export); err != nil {
+		t.Fatalf("json export: %v: %s", err, recorder.Body)
+	}
+	if export.Size != 3 || export.Variant != models.VARIANT_STANDARD || len(export.Players) != 2 || export.Winner != models.WinFor(models.SYMBOL_X) {
+		t.Errorf("export = %+v", export)
+	}
+	if len(export.Moves) != 5 || export.Moves[1].Square != "a2" || export.Moves[4].Square != "c1" || export.Moves[4].Symbol != models.SYMBOL_X {
+		t.Errorf("moves = %+v, want the top row win without the moves taken back", export.Moves)
+	}
+
+	text := exportGame(gs, gameID, models.EXPORT_TEXT).Body.String()
+	wants := []string{`[Result "X"]`, "\n1. a1 a2 2. b1 b2 3. c1 X\n"}
+	for _, player := range export.Players {
+		wants = append(wants, "["+string(player.Symbol)+` "`+player.Name+`"]`)
+	}
+	for _, want := range wants {
+		if !strings.Contains(text, want) {
+			t.Errorf("text export missing %q:\n%s", want, text)
+		}
+	}
+
+	svg := exportGame(gs, gameID, models.EXPORT_SVG)
+	if svg.Header().Get("Content-Type") != "image/svg+xml" || strings.Count(svg.Body.String(), "<set ") != 5 {
+		t.Errorf("svg export = %s", svg.Body)
+	}
+
+	animation, err := gif.DecodeAll(bytes.NewReader(exportGame(gs, gameID, models.EXPORT_GIF).Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(animation.Image) != 6 || animation.Image[0].Rect.Dx() != 3*exportCellPixels {
+		t.Errorf("gif has %d frames, want the empty board and one per move", len(animation.Image))
+	}
+}
+
+func TestExportRefusesUnknownGamesAndFormats(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	gameID := finishTestGame(t, wsURL)
+
+	if code := exportGame(gs, "missing", "").Code; code != http.StatusNotFound {
+		t.Errorf("unknown game: status %d, want 404", code)
+	}
+	if code := exportGame(gs, gameID, "mp4").Code; code != http.StatusBadRequest {
+		t.Errorf("unknown format: status %d, want 400", code)
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// Layout and pacing of the animated exports
+const (
+	exportCellPixels = 64              // Width and height of a board cell
+	exportMoveDelay  = time.Second     // Time between moves
+	exportFinalDelay = 3 * time.Second // Time the final position is shown before a GIF loops
+)
+
+// exportColors are the colours of the animated exports: the background, the grid, then each of models.Symbols
+var exportColors = color.Palette{
+	color.RGBA{0xff, 0xff, 0xff, 0xff},
+	color.RGBA{0x33, 0x33, 0x33, 0xff},
+	color.RGBA{0xd9, 0x3a, 0x3a, 0xff},
+	color.RGBA{0x2b, 0x6c, 0xd9, 0xff},
+	color.RGBA{0x2e, 0x9e, 0x55, 0xff},
+}
+
+// Indexes into exportColors
+const (
+	exportBackground = 0
+	exportGrid       = 1
+)
+
+// symbolColor returns the index into exportColors a symbol is drawn in
+func symbolColor(symbol models.Symbol) uint8 {
+	for i, candidate := range models.Symbols {
+		if candidate == symbol {
+			return uint8(exportGrid + 1 + i)
+		}
+	}
+	return exportGrid
+}
+
+// exportTitle names the sides of a game, such as "alice (X) vs bob (O)"
+func exportTitle(export *models.ReplayExport) string {
+	sides := make([]string, 0, len(export.Players))
+	for _, player := range export.Players {
+		sides = append(sides, fmt.Sprintf("%s (%s)", player.Name, player.Symbol))
+	}
+	return strings.Join(sides, " vs ")
+}
+
+// exportSVG renders a game as an SVG whose marks appear one by one, exportMoveDelay apart, and stay
+func exportSVG(export *models.ReplayExport) string {
+	width := export.Size * exportCellPixels
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, width, width, width)
+	fmt.Fprintf(&svg, "<title>%s</title>\n", html.EscapeString(exportTitle(export)))
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, width, svgColor(exportBackground))
+	for i := 1; i < export.Size; i++ {
+		line := i * exportCellPixels
+		fmt.Fprintf(&svg, `<line x1="%d" y1="0" x2="%d" y2="%d" stroke="%s" stroke-width="2"/>`+"\n", line, line, width, svgColor(exportGrid))
+		fmt.Fprintf(&svg, `<line x1="0" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2"/>`+"\n", line, width, line, svgColor(exportGrid))
+	}
+
+	margin := exportCellPixels / 5
+	for i, move := range export.Moves {
+		left := move.Position % export.Size * exportCellPixels
+		top := move.Position / export.Size * exportCellPixels
+		colour := svgColor(symbolColor(move.Symbol))
+		fmt.Fprintf(&svg, `<g opacity="0" stroke="%s" stroke-width="6" fill="none" stroke-linecap="round">`, colour)
+		fmt.Fprintf(&svg, `<set attributeName="opacity" to="1" begin="%gs" fill="freeze"/>`, (time.Duration(i+1) * exportMoveDelay).Seconds())
+		switch move.Symbol {
+		case models.SYMBOL_X:
+			fmt.Fprintf(&svg, `<path d="M%d %dL%d %dM%d %dL%d %d"/>`,
+				left+margin, top+margin, left+exportCellPixels-margin, top+exportCellPixels-margin,
+				left+exportCellPixels-margin, top+margin, left+margin, top+exportCellPixels-margin)
+		case models.SYMBOL_O:
+			fmt.Fprintf(&svg, `<circle cx="%d" cy="%d" r="%d"/>`,
+				left+exportCellPixels/2, top+exportCellPixels/2, exportCellPixels/2-margin)
+		default:
+			fmt.Fprintf(&svg, `<path d="M%d %dL%d %dL%d %dZ"/>`,
+				left+exportCellPixels/2, top+margin, left+exportCellPixels-margin, top+exportCellPixels-margin,
+				left+margin, top+exportCellPixels-margin)
+		}
+		svg.WriteString("</g>\n")
+	}
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// svgColor writes a colour of exportColors as #rrggbb
+func svgColor(index uint8) string {
+	r, g, b, _ := exportColors[index].RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// exportGIF renders a game as a looping GIF: the empty board, then one frame per move
+func exportGIF(w io.Writer, export *models.ReplayExport) error {
+	width := export.Size * exportCellPixels
+	frame := image.NewPaletted(image.Rect(0, 0, width, width), exportColors)
+	for i := 1; i < export.Size; i++ {
+		line := i * exportCellPixels
+		fillRect(frame, image.Rect(line-1, 0, line+1, width), exportGrid)
+		fillRect(frame, image.Rect(0, line-1, width, line+1), exportGrid)
+	}
+
+	animation := &gif.GIF{}
+	addFrame := func(delay time.Duration) {
+		snapshot := image.NewPaletted(frame.Rect, exportColors)
+		copy(snapshot.Pix, frame.Pix)
+		animation.Image = append(animation.Image, snapshot)
+		animation.Delay = append(animation.Delay, int(delay/(10*time.Millisecond)))
+	}
+	addFrame(exportMoveDelay)
+	for i, move := range export.Moves {
+		drawMark(frame, move, export.Size)
+		delay := exportMoveDelay
+		if i == len(export.Moves)-1 {
+			delay = exportFinalDelay
+		}
+		addFrame(delay)
+	}
+	return gif.EncodeAll(w, animation)
+}
+
+// drawMark draws a move's symbol into its cell
+func drawMark(frame *image.Paletted, move models.ExportedMove, size int) {
+	colour := symbolColor(move.Symbol)
+	margin := float64(exportCellPixels / 5)
+	left := float64(move.Position % size * exportCellPixels)
+	top := float64(move.Position / size * exportCellPixels)
+	right, bottom := left+exportCellPixels-margin, top+exportCellPixels-margin
+	left, top = left+margin, top+margin
+
+	switch move.Symbol {
+	case models.SYMBOL_X:
+		drawSegment(frame, left, top, right, bottom, colour)
+		drawSegment(frame, right, top, left, bottom, colour)
+	case models.SYMBOL_O:
+		centerX, centerY, radius := (left+right)/2, (top+bottom)/2, (right-left)/2
+		for y := int(top) - 3; y <= int(bottom)+3; y++ {
+			for x := int(left) - 3; x <= int(right)+3; x++ {
+				if distance := math.Hypot(float64(x)-centerX, float64(y)-centerY); math.Abs(distance-radius) <= 3 {
+					frame.SetColorIndex(x, y, colour)
+				}
+			}
+		}
+	default:
+		middle := (left + right) / 2
+		drawSegment(frame, middle, top, right, bottom, colour)
+		drawSegment(frame, right, bottom, left, bottom, colour)
+		drawSegment(frame, left, bottom, middle, top, colour)
+	}
+}
+
+// drawSegment draws a line six pixels thick between two points
+func drawSegment(frame *image.Paletted, x1, y1, x2, y2 float64, colour uint8) {
+	steps := int(math.Max(math.Abs(x2-x1), math.Abs(y2-y1)))
+	for step := 0; step <= steps; step++ {
+		t := float64(step) / float64(max(steps, 1))
+		x, y := int(x1+(x2-x1)*t), int(y1+(y2-y1)*t)
+		fillRect(frame, image.Rect(x-3, y-3, x+3, y+3), colour)
+	}
+}
+
+// fillRect paints a rectangle, clipped to the frame
+func fillRect(frame *image.Paletted, rect image.Rectangle, colour uint8) {
+	rect = rect.Intersect(frame.Rect)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			frame.SetColorIndex(x, y, colour)
+		}
+	}
+}
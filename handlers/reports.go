@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"tictactoe-server/models"
+
+	"github.com/google/uuid"
+)
+
+// adminReportView is the admin listing of a report with the context a reviewer needs
+type adminReportView struct {
+	*models.Report
+	ReporterName string             `json:"reporterName"`
+	ReportedName string             `json:"reportedName"`
+	Events       []models.GameEvent `json:"events"`                // The game's moves, chat and other events while its log is kept
+	BannedUntil  *time.Time         `json:"bannedUntil,omitempty"` // The reported player's matchmaking ban, if one is running
+}
+
+// handleReportPlayer files a report against a player the sender shared a game with
+func (gs *GameServer) handleReportPlayer(conn clientConn, player *models.Player, request *models.ReportPlayerPayload) {
+	if request.PlayerID == player.ID {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "You cannot report yourself")
+		return
+	}
+	reported, exists := gs.players.Player(request.PlayerID)
+	if !exists || reported.Deleted {
+		gs.sendClientError(conn, models.ERR_PLAYER_NOT_FOUND, "Player not found")
+		return
+	}
+	if reported.IsBot {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Bots cannot be reported")
+		return
+	}
+	if !gs.playedTogether(request.GameID, player.ID, reported.ID) {
+		gs.sendClientError(conn, models.ERR_GAME_NOT_FOUND, "You did not play that game with this player")
+		return
+	}
+	for _, report := range gs.store.Reports() {
+		if report.ReporterID == player.ID && report.ReportedID == reported.ID && report.GameID == request.GameID {
+			gs.sendClientError(conn, models.ERR_ALREADY_PENDING, "You already reported this player for that game")
+			return
+		}
+	}
+
+	report := &models.Report{
+		ID:         uuid.New().String(),
+		ReporterID: player.ID,
+		ReportedID: reported.ID,
+		GameID:     request.GameID,
+		Reason:     request.Reason,
+		Comment:    request.Comment,
+		Status:     models.REPORT_OPEN,
+		CreatedAt:  gs.clock.Now(),
+	}
+	gs.store.SaveReport(report)
+	log.Printf("Player %s reported %s for %s in game %s", player.ID, reported.ID, report.Reason, report.GameID)
+
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_REPORT_FILED, map[string]string{
+		"reportId": report.ID,
+		"status":   report.Status,
+	}))
+}
+
+// playedTogether reports whether two players were both seated in a game, in memory or on record
+func (gs *GameServer) playedTogether(gameID, playerID, otherID string) bool {
+	if gameInstance, exists := gs.games.Game(gameID); exists {
+		return isPlayerInGame(gameInstance, playerID) && isPlayerInGame(gameInstance, otherID)
+	}
+	for _, record := range gs.store.GamesForPlayer(playerID) {
+		if record.GameID == gameID {
+			return otherID == record.PlayerXID || otherID == record.PlayerOID || otherID == record.PlayerDeltaID
+		}
+	}
+	return false
+}
+
+// matchmakingBanned reports whether a player is banned from matchmaking and until when, forgetting bans that ran out
+func (gs *GameServer) matchmakingBanned(playerID string) (time.Time, bool) {
+	until, banned := gs.matchmakingBans[playerID]
+	if banned && !gs.clock.Now().Before(until) {
+		delete(gs.matchmakingBans, playerID)
+		return time.Time{}, false
+	}
+	return until, banned
+}
+
+// checkReportBan bans a player from matchmaking once enough different players' reports against them were verified
+// within the configured window, taking them out of any queue or proposed match
+func (gs *GameServer) checkReportBan(playerID string) {
+	threshold := gs.config.ReportBanThreshold
+	if threshold <= 0 {
+		return
+	}
+	if _, banned := gs.matchmakingBanned(playerID); banned {
+		return
+	}
+
+	reporters := make(map[string]bool)
+	for _, report := range gs.store.Reports() {
+		if report.ReportedID == playerID && report.Status == models.REPORT_VERIFIED &&
+			gs.clock.Since(report.CreatedAt) < gs.config.ReportBanWindow {
+			reporters[report.ReporterID] = true
+		}
+	}
+	if len(reporters) < threshold {
+		return
+	}
+
+	gs.matchmakingBans[playerID] = gs.clock.Now().Add(gs.config.ReportBanDuration)
+	log.Printf("Player %s banned from matchmaking for %v after %d verified reports", playerID, gs.config.ReportBanDuration, len(reporters))
+
+	mode, queued := gs.queuedMode(playerID)
+	gs.removeFromQueue(playerID)
+	gs.withdrawFromMatch(playerID)
+	if queued && len(gs.playerConns[playerID]) > 0 {
+		gs.sendToPlayer(playerID, models.NewGameMessage(models.MSG_QUEUE_REMOVED, map[string]string{
+			"mode":   mode,
+			"reason": models.QUEUE_REMOVED_BANNED,
+		}))
+	}
+}
+
+// reportView adds the names, game log and ban state an admin reviews a report with
+func (gs *GameServer) reportView(report *models.Report) adminReportView {
+	view := adminReportView{Report: report, Events: gs.store.GameEvents(report.GameID)}
+	if reporter, exists := gs.players.Player(report.ReporterID); exists {
+		view.ReporterName = reporter.Name
+	}
+	if reported, exists := gs.players.Player(report.ReportedID); exists {
+		view.ReportedName = reported.Name
+	}
+	if until, banned := gs.matchmakingBanned(report.ReportedID); banned {
+		view.BannedUntil = &until
+	}
+	return view
+}
+
+// handleAdminReports serves GET /admin/reports, newest first and optionally filtered by ?status=,
+// and POST /admin/reports/{id}/verify and /dismiss, which close an open report
+func (gs *GameServer) handleAdminReports(w http.ResponseWriter, r *http.Request) {
+	reportID, action := splitAdminPath(r.URL.Path, "/admin/reports")
+
+	switch {
+	case r.Method == http.MethodGet && reportID == "":
+		status := r.URL.Query().Get("status")
+		var views []adminReportView
+		gs.do(func() {
+			reports := gs.store.Reports()
+			views = make([]adminReportView, 0, len(reports))
+			for i := len(reports) - 1; i >= 0; i-- {
+				if status == "" || reports[i].Status == status {
+					views = append(views, gs.reportView(reports[i]))
+				}
+			}
+		})
+		writeJSON(w, http.StatusOK, views)
+
+	case r.Method == http.MethodPost && reportID != "":
+		var newStatus, auditAction string
+		switch action {
+		case "verify":
+			newStatus, auditAction = models.REPORT_VERIFIED, models.ADMIN_VERIFY_REPORT
+		case "dismiss":
+			newStatus, auditAction = models.REPORT_DISMISSED, models.ADMIN_DISMISS_REPORT
+		default:
+			http.Error(w, "unknown action", http.StatusNotFound)
+			return
+		}
+
+		code, message := http.StatusOK, ""
+		var view adminReportView
+		gs.do(func() {
+			report, exists := gs.store.Report(reportID)
+			if !exists {
+				code, message = http.StatusNotFound, "report not found"
+				return
+			}
+			if report.Status != models.REPORT_OPEN {
+				code, message = http.StatusConflict, "report already "+report.Status
+				return
+			}
+
+			// Records are replaced, never changed, as the store hands out pointers
+			reviewed := *report
+			reviewedAt := gs.clock.Now()
+			reviewed.Status, reviewed.ReviewedAt = newStatus, &reviewedAt
+			gs.store.SaveReport(&reviewed)
+			log.Printf("Admin %s report %s against %s", newStatus, reportID, reviewed.ReportedID)
+			if newStatus == models.REPORT_VERIFIED {
+				gs.checkReportBan(reviewed.ReportedID)
+			}
+			view = gs.reportView(&reviewed)
+		})
+		if code != http.StatusOK {
+			http.Error(w, message, code)
+			return
+		}
+		gs.auditAdmin(r, auditAction, reportID, map[string]interface{}{"reportedId": view.ReportedID})
+		writeJSON(w, http.StatusOK, view)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// cancelledGame matches two clients in a casual game, then cancels it so both are free to play again
+func cancelledGame(t *testing.T, gs *GameServer, first, second *testClient) string {
+	t.Helper()
+	first.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	first.expect(models.MSG_QUEUE_JOINED, nil)
+	second.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	var state testGameState
+	first.expect(models.MSG_GAME_FOUND, &state)
+	second.expect(models.MSG_GAME_FOUND, nil)
+	gs.do(func() { gs.cancelGame(testGame(gs, state.GameID)) })
+	return state.GameID
+}
+
+func TestVerifiedReportsBanFromMatchmaking(t *testing.T) {
+	cfg := testConfig()
+	cfg.ReportBanThreshold = 2
+	cfg.ReportBanWindow = 24 * time.Hour
+	cfg.ReportBanDuration = time.Hour
+	gs, clk, wsURL := newTestServer(t, cfg)
+	villain := dialTestClient(t, wsURL, "name=villain")
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	firstGame := cancelledGame(t, gs, alice, villain)
+	alice.send(models.MSG_REPORT_PLAYER, models.ReportPlayerPayload{PlayerID: villain.playerID, GameID: firstGame, Reason: models.REPORT_ABUSE})
+	alice.expect(models.MSG_REPORT_FILED, nil)
+
+	var refused models.ErrorPayload
+	alice.send(models.MSG_REPORT_PLAYER, models.ReportPlayerPayload{PlayerID: villain.playerID, GameID: firstGame, Reason: models.REPORT_CHEATING})
+	alice.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_ALREADY_PENDING {
+		t.Errorf("second report for one game = %+v", refused)
+	}
+	alice.send(models.MSG_REPORT_PLAYER, models.ReportPlayerPayload{PlayerID: bob.playerID, GameID: firstGame, Reason: models.REPORT_ABUSE})
+	alice.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_GAME_NOT_FOUND {
+		t.Errorf("report of a player from another game = %+v", refused)
+	}
+
+	secondGame := cancelledGame(t, gs, bob, villain)
+	bob.send(models.MSG_REPORT_PLAYER, models.ReportPlayerPayload{PlayerID: villain.playerID, GameID: secondGame, Reason: models.REPORT_CHEATING, Comment: "instant replies"})
+	bob.expect(models.MSG_REPORT_FILED, nil)
+
+	recorder := httptest.NewRecorder()
+	gs.handleAdminReports(recorder, httptest.NewRequest(http.MethodGet, "/admin/reports?status=open", nil))
+	var open []adminReportView
+	if err := json.Unmarshal(recorder.Body.Bytes(), &open); err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 2 || open[0].GameID != secondGame || open[0].ReportedName != "villain" || len(open[0].Events) == 0 {
+		t.Fatalf("open reports = %+v, want both, newest first, with the game log", open)
+	}
+
+	var reviewed adminReportView
+	for i := len(open) - 1; i >= 0; i-- {
+		recorder = httptest.NewRecorder()
+		gs.handleAdminReports(recorder, httptest.NewRequest(http.MethodPost, "/admin/reports/"+open[i].ID+"/verify", nil))
+		if err := json.Unmarshal(recorder.Body.Bytes(), &reviewed); err != nil {
+			t.Fatal(err)
+		}
+		if banned := reviewed.BannedUntil != nil; banned != (i == 0) {
+			t.Fatalf("banned after %d verified reports = %v", len(open)-i, banned)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	gs.handleAdminReports(recorder, httptest.NewRequest(http.MethodPost, "/admin/reports/"+open[0].ID+"/dismiss", nil))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("dismissing a verified report = %d, want 409", recorder.Code)
+	}
+
+	villain.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	villain.expect(models.MSG_ERROR, &refused)
+	if refused.Code != models.ERR_NOT_ALLOWED {
+		t.Errorf("banned player joining the queue = %+v", refused)
+	}
+
+	clk.Advance(time.Hour)
+	villain.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	villain.expect(models.MSG_QUEUE_JOINED, nil)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+	"tictactoe-server/storage"
+)
+
+// recordingQueues is a queue repository that remembers every player enqueued
+type recordingQueues struct {
+	*storage.MemoryQueues
+	enqueued []string
+}
+
+func (q *recordingQueues) Enqueue(mode string, position int, playerID string, at time.Time) {
+	q.enqueued = append(q.enqueued, playerID)
+	q.MemoryQueues.Enqueue(mode, position, playerID, at)
+}
+
+func TestServerRunsOnInjectedRepos(t *testing.T) {
+	repos := storage.NewMemoryRepos(queueModes...)
+	queues := &recordingQueues{MemoryQueues: storage.NewMemoryQueues(queueModes...)}
+	repos.Queues = queues
+	seeded := models.NewPlayer("alice")
+	seeded.SetRating(models.RATING_POOL_STANDARD, 1700)
+	repos.Players.SavePlayer(seeded)
+	gs, _, wsURL := newTestServerWithRepos(t, testConfig(), repos)
+
+	alice := dialTestClient(t, wsURL, "playerId="+seeded.ID+"&token="+seeded.SessionToken)
+	if alice.playerID != seeded.ID {
+		t.Fatalf("connected as %s, want the seeded player %s", alice.playerID, seeded.ID)
+	}
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+
+	var enqueued []string
+	gs.do(func() { enqueued = queues.enqueued })
+	if len(enqueued) != 1 || enqueued[0] != seeded.ID {
+		t.Fatalf("enqueued %v, want only the seeded player", enqueued)
+	}
+}
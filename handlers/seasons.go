@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// maxSeasonStandings limits how many players a finished season's leaderboard keeps
+const maxSeasonStandings = 100
+
+// newSeason describes a season starting at start
+func (gs *GameServer) newSeason(number int, start time.Time) models.Season {
+	season := models.Season{Number: number, StartedAt: start}
+	if gs.config.SeasonLength > 0 {
+		endsAt := start.Add(gs.config.SeasonLength)
+		season.EndsAt = &endsAt
+	}
+	return season
+}
+
+// scheduleSeasonEnd arms the timer that rolls the current season over
+func (gs *GameServer) scheduleSeasonEnd() {
+	gs.seasonTimer = gs.clock.AfterFunc(gs.season.EndsAt.Sub(gs.clock.Now()), gs.doLater(gs.rolloverSeason))
+}
+
+// rankedPlayers returns players the leaderboard policy admits who are rated in a pool,
+// highest rating in it first
+func (gs *GameServer) rankedPlayers(pool string) []*models.Player {
+	players := make([]*models.Player, 0)
+	for _, player := range gs.players.Players() {
+		if _, rated := player.Ratings[pool]; rated && !player.Deleted && gs.leaderboardEligible(player) {
+			players = append(players, player)
+		}
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Rating(pool) > players[j].Rating(pool)
+	})
+	return players
+}
+
+// leaderboardEligible reports whether a player may be ranked under the configured LeaderboardPolicy
+func (gs *GameServer) leaderboardEligible(player *models.Player) bool {
+	if player.SeasonGames == 0 || gs.gameEngine.InPlacement(player) {
+		return false
+	}
+	return gs.config.LeaderboardPolicy == config.LEADERBOARD_PLAYED || !gs.gameEngine.IsProvisional(player)
+}
+
+// seasonStandings ranks the current season's players in the standard pool
+func (gs *GameServer) seasonStandings() []models.SeasonStanding {
+	players := gs.rankedPlayers(models.RATING_POOL_STANDARD)
+	if len(players) > maxSeasonStandings {
+		players = players[:maxSeasonStandings]
+	}
+
+	standings := make([]models.SeasonStanding, 0, len(players))
+	for i, player := range players {
+		standings = append(standings, models.SeasonStanding{
+			Rank:     i + 1,
+			PlayerID: player.ID,
+			Name:     player.Name,
+			Rating:   player.Rating(models.RATING_POOL_STANDARD),
+			Games:    player.SeasonGames,
+		})
+	}
+	return standings
+}
+
+// softReset compresses a rating toward DEFAULT_RATING, keeping SeasonResetKeep percent of the distance
+func (gs *GameServer) softReset(rating int) int {
+	return models.DEFAULT_RATING + (rating-models.DEFAULT_RATING)*gs.config.SeasonResetKeep/100
+}
+
+// rolloverSeason archives the finished season, soft-resets every rating in every pool and starts the next season
+func (gs *GameServer) rolloverSeason() {
+	ended := gs.season
+	now := gs.clock.Now()
+	archive := &models.SeasonArchive{Season: ended, EndedAt: now, Standings: gs.seasonStandings()}
+
+	snapshots := make(map[string]models.RatingSnapshot)
+	for _, player := range gs.players.Players() {
+		player.SeasonGames = 0
+		if player.Deleted {
+			continue // Their rating history is gone and stays gone
+		}
+		before := player.Rating(models.RATING_POOL_STANDARD)
+		for pool, rating := range player.Ratings {
+			if glicko, tracked := player.Glicko[pool]; tracked {
+				// Glicko-2 ratings keep their deviation; only the rating itself is compressed
+				glicko.Rating = float64(gs.softReset(rating))
+				player.SetGlicko(pool, glicko)
+				continue
+			}
+			player.Ratings[pool] = gs.softReset(rating)
+		}
+		// The rating history charts the standard pool
+		if rating := player.Rating(models.RATING_POOL_STANDARD); rating != before {
+			snapshots[player.ID] = models.RatingSnapshot{Rating: rating, Timestamp: now}
+		}
+	}
+
+	// The next season starts where this one was scheduled to end so seasons don't drift
+	gs.season = gs.newSeason(ended.Number+1, *ended.EndsAt)
+	gs.seasonTimer = gs.clock.AfterFunc(gs.season.EndsAt.Sub(now), gs.doLater(gs.rolloverSeason))
+	season := gs.season
+
+	gs.store.ArchiveSeason(archive)
+	for playerID, snapshot := range snapshots {
+		gs.store.AddRatingSnapshot(playerID, snapshot)
+	}
+
+	log.Printf("Season %d ended with %d ranked players; season %d started", ended.Number, len(archive.Standings), season.Number)
+	gs.sendToAll(models.NewGameMessage(models.MSG_SEASON_STARTED, map[string]interface{}{
+		"season":         season,
+		"previousSeason": ended.Number,
+	}))
+	gs.broadcastLeaderboard()
+	gs.fireSeasonEnded(archive)
+}
+
+// currentSeason returns the season being played
+func (gs *GameServer) currentSeason() models.Season {
+	return gs.season
+}
+
+// HandleSeasonsAPI serves GET /api/seasons (the current season and finished ones)
+// and GET /api/seasons/{number} (a season's leaderboard, live for the current season)
+func (gs *GameServer) HandleSeasonsAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/seasons"), "/")
+	if path == "" {
+		past := make([]models.Season, 0)
+		for _, archive := range gs.store.SeasonArchives() {
+			past = append(past, archive.Season)
+		}
+		var current models.Season
+		gs.do(func() { current = gs.currentSeason() })
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"current": current,
+			"past":    past,
+		})
+		return
+	}
+
+	number, err := strconv.Atoi(path)
+	if err != nil {
+		http.Error(w, "invalid season number", http.StatusBadRequest)
+		return
+	}
+
+	var current map[string]interface{}
+	gs.do(func() {
+		if number == gs.season.Number {
+			current = map[string]interface{}{
+				"season":    gs.season,
+				"standings": gs.seasonStandings(),
+			}
+		}
+	})
+	if current != nil {
+		writeJSON(w, http.StatusOK, current)
+		return
+	}
+
+	archive, exists := gs.store.SeasonArchive(number)
+	if !exists {
+		http.Error(w, "season not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"season":    archive.Season,
+		"endedAt":   archive.EndedAt,
+		"standings": archive.Standings,
+	})
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// addSeasonPlayer registers a player with a rating and games played this season
+func addSeasonPlayer(gs *GameServer, name string, rating, seasonGames int) *models.Player {
+	player := models.NewPlayer(name)
+	player.SetRating(models.RATING_POOL_STANDARD, rating)
+	player.SeasonGames = seasonGames
+	gs.players.SavePlayer(player)
+	return player
+}
+
+func TestSeasonRollover(t *testing.T) {
+	cfg := testConfig()
+	cfg.SeasonLength = 24 * time.Hour
+	cfg.SeasonResetKeep = 50
+	cfg.PlacementGames = 3
+	clk := clock.NewFake(testEpoch)
+	gs := NewGameServerWithClock(cfg, clk)
+
+	top := addSeasonPlayer(gs, "top", 1400, 10)
+	low := addSeasonPlayer(gs, "low", 800, 4)
+	placing := addSeasonPlayer(gs, "placing", 1100, 2)
+
+	leaderboard := gs.getLeaderboard(models.RATING_POOL_STANDARD)
+	if len(leaderboard) != 2 || leaderboard[0] != top || leaderboard[1] != low {
+		t.Fatalf("leaderboard includes players still in placement: %v", leaderboard)
+	}
+	watcher := attachRecorder(gs)
+
+	gs.scheduleSeasonEnd()
+	clk.Advance(24 * time.Hour)
+
+	if top.Rating(models.RATING_POOL_STANDARD) != 1200 || low.Rating(models.RATING_POOL_STANDARD) != 900 || placing.Rating(models.RATING_POOL_STANDARD) != 1050 {
+		t.Errorf("soft-reset ratings = %d/%d/%d, want 1200/900/1050", top.Rating(models.RATING_POOL_STANDARD), low.Rating(models.RATING_POOL_STANDARD), placing.Rating(models.RATING_POOL_STANDARD))
+	}
+	if top.SeasonGames != 0 || len(gs.getLeaderboard(models.RATING_POOL_STANDARD)) != 0 {
+		t.Error("new season did not start with placement games")
+	}
+
+	season := gs.currentSeason()
+	if season.Number != 2 || !season.StartedAt.Equal(testEpoch.Add(24*time.Hour)) {
+		t.Errorf("current season = %+v", season)
+	}
+
+	archive, exists := gs.store.SeasonArchive(1)
+	if !exists || len(archive.Standings) != 2 || archive.Standings[0].PlayerID != top.ID || archive.Standings[0].Rating != 1400 {
+		t.Fatalf("season 1 archive = %+v", archive)
+	}
+	if history := gs.store.RatingHistory(top.ID); len(history) != 1 || history[0].Rating != 1200 {
+		t.Errorf("reset not recorded in rating history: %+v", history)
+	}
+
+	if received := watcher.received(gs); len(received) != 1 || received[0] != models.MSG_SEASON_STARTED {
+		t.Errorf("clients received %v, want %s", received, models.MSG_SEASON_STARTED)
+	}
+
+	// Seasons keep their schedule rather than drifting from the rollover time
+	clk.Advance(24 * time.Hour)
+	if season := gs.currentSeason(); season.Number != 3 || len(gs.store.SeasonArchives()) != 2 {
+		t.Errorf("second rollover: season %d, %d archives", season.Number, len(gs.store.SeasonArchives()))
+	}
+}
+
+func TestSeasonsAPI(t *testing.T) {
+	cfg := testConfig()
+	cfg.SeasonLength = time.Hour
+	clk := clock.NewFake(testEpoch)
+	gs := NewGameServerWithClock(cfg, clk)
+	addSeasonPlayer(gs, "alice", 1100, 1)
+	gs.scheduleSeasonEnd()
+	clk.Advance(time.Hour)
+
+	get := func(path string) (int, map[string]json.RawMessage) {
+		recorder := httptest.NewRecorder()
+		gs.HandleSeasonsAPI(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]json.RawMessage
+		json.Unmarshal(recorder.Body.Bytes(), &body)
+		return recorder.Code, body
+	}
+
+	code, body := get("/api/seasons")
+	if code != http.StatusOK || body["current"] == nil || body["past"] == nil {
+		t.Fatalf("/api/seasons = %d %v", code, body)
+	}
+
+	code, body = get("/api/seasons/1")
+	var standings []models.SeasonStanding
+	json.Unmarshal(body["standings"], &standings)
+	if code != http.StatusOK || len(standings) != 1 || standings[0].Name != "alice" {
+		t.Errorf("/api/seasons/1 = %d %s", code, body["standings"])
+	}
+
+	if code, _ := get("/api/seasons/2"); code != http.StatusOK {
+		t.Errorf("current season = %d", code)
+	}
+	if code, _ := get("/api/seasons/7"); code != http.StatusNotFound {
+		t.Errorf("unknown season = %d", code)
+	}
+}
+
+func TestLeaderboardFilteredByPool(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	misere := models.RatingPool(models.VARIANT_MISERE, models.MODE_RATED)
+	var standard, both *models.Player
+	gs.do(func() {
+		standard = addSeasonPlayer(gs, "standard", 1300, 5)
+		both = addSeasonPlayer(gs, "both", 1100, 5)
+		both.SetRating(misere, 1250)
+	})
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	var leaderboard []*models.Player
+	alice.expect(models.MSG_LEADERBOARD, &leaderboard)
+	if len(leaderboard) != 2 || leaderboard[0].ID != standard.ID {
+		t.Fatalf("standard leaderboard = %v", leaderboard)
+	}
+
+	alice.send(models.MSG_LEADERBOARD, models.LeaderboardPayload{Variant: models.VARIANT_MISERE})
+	alice.expect(models.MSG_LEADERBOARD, &leaderboard)
+	if len(leaderboard) != 1 || leaderboard[0].ID != both.ID || leaderboard[0].Ratings[misere] != 1250 {
+		t.Errorf("misère leaderboard = %v", leaderboard)
+	}
+
+	alice.send(models.MSG_LEADERBOARD, models.LeaderboardPayload{Mode: models.MODE_CASUAL})
+	var body models.ErrorPayload
+	alice.expect(models.MSG_ERROR, &body)
+	if body.Code != models.ERR_INVALID_PAYLOAD {
+		t.Errorf("casual leaderboard error = %+v", body)
+	}
+}
+
+func TestProvisionalPlayersOffLeaderboard(t *testing.T) {
+	for _, policy := range []string{config.LEADERBOARD_ESTABLISHED, config.LEADERBOARD_PLAYED} {
+		cfg := testConfig()
+		cfg.ProvisionalGames = 10
+		cfg.LeaderboardPolicy = policy
+		gs, _, _ := newTestServer(t, cfg)
+
+		var leaderboard []*models.Player
+		var profile *models.PlayerProfile
+		gs.do(func() {
+			established := addSeasonPlayer(gs, "established", 1100, 3)
+			established.Wins = 10
+			newcomer := addSeasonPlayer(gs, "newcomer", 1200, 3)
+			newcomer.Wins = 3
+			leaderboard = gs.getLeaderboard(models.RATING_POOL_STANDARD)
+			profile, _ = gs.buildProfile(newcomer.ID)
+		})
+
+		want := 1
+		if policy == config.LEADERBOARD_PLAYED {
+			want = 2
+		}
+		if len(leaderboard) != want {
+			t.Errorf("%s: leaderboard has %d players, want %d", policy, len(leaderboard), want)
+		}
+		if !profile.IsProvisional || profile.ProvisionalGamesLeft != 7 {
+			t.Errorf("%s: newcomer profile provisional %v with %d games left, want 7", policy, profile.IsProvisional, profile.ProvisionalGamesLeft)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+	"tictactoe-server/storage"
+
+	"github.com/gorilla/websocket"
+)
+
+// testConfig returns settings suitable for tests, independent of the environment
+func testConfig() *config.Config {
+	return &config.Config{
+		Port:                   "0",
+		GRPCPort:               "0",
+		DisconnectGracePeriod:  time.Second,
+		EventRetention:         time.Hour,
+		IdleTimeout:            time.Minute,
+		DuplicateLoginPolicy:   config.DUPLICATE_LOGIN_TRANSFER,
+		RecentOpponentWindow:   time.Minute,
+		RecentOpponentPolicy:   config.RECENT_OPPONENTS_PREFER,
+		RecentOpponentMinQueue: 3,
+		AbandonAfter:           time.Minute,
+		AbandonedGamePolicy:    config.ABANDONED_GAMES_VOID,
+		FinishedGameRetention:  time.Minute,
+		EmotesEnabled:          true,
+		EmoteCooldown:          time.Second,
+		// Test clients all connect from localhost and move instantly on the fake clock
+		SameIPPolicy:   config.SAME_IP_OFF,
+		FastMoveStreak: 0,
+	}
+}
+
+// testEpoch is where fake clocks start
+var testEpoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestServer starts a GameServer on a fake clock behind an httptest server and returns its WebSocket URL
+func newTestServer(t *testing.T, cfg *config.Config) (*GameServer, *clock.Fake, string) {
+	t.Helper()
+	return newTestServerWithRepos(t, cfg, storage.NewMemoryRepos(queueModes...))
+}
+
+// newTestServerWithRepos starts a test server keeping its state in repos
+func newTestServerWithRepos(t *testing.T, cfg *config.Config, repos storage.Repos) (*GameServer, *clock.Fake, string) {
+	t.Helper()
+
+	clk := clock.NewFake(testEpoch)
+	gs := NewGameServerWithRepos(cfg, clk, repos)
+	go gs.handleBroadcast()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", gs.HandleWebSocket)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return gs, clk, "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+}
+
+// testGame returns a game the server holds, nil if there is none; call it from the hub
+func testGame(gs *GameServer, gameID string) *models.Game {
+	gameInstance, _ := gs.games.Game(gameID)
+	return gameInstance
+}
+
+// testPlayer returns a player the server knows, nil if there is none; call it from the hub
+func testPlayer(gs *GameServer, playerID string) *models.Player {
+	player, _ := gs.players.Player(playerID)
+	return player
+}
+
+// testClient is a WebSocket client speaking the current protocol version
+type testClient struct {
+	t        *testing.T
+	conn     *websocket.Conn
+	playerID string
+	token    string
+}
+
+// dialTestClient connects and waits for the session message
+func dialTestClient(t *testing.T, wsURL, query string) *testClient {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&"+query, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	c := &testClient{t: t, conn: conn}
+	var session struct {
+		PlayerID string `json:"playerId"`
+		Token    string `json:"token"`
+	}
+	c.expect(models.MSG_SESSION, &session)
+	c.playerID, c.token = session.PlayerID, session.Token
+	return c
+}
+
+// send writes a message with the given payload
+func (c *testClient) send(msgType string, payload interface{}) {
+	c.t.Helper()
+	if err := c.conn.WriteJSON(models.NewGameMessage(msgType, payload)); err != nil {
+		c.t.Fatalf("send %s: %v", msgType, err)
+	}
+}
+
+// expect reads until a message of the given type arrives and decodes its data into out
+func (c *testClient) expect(msgType string, out interface{}) *models.GameMessage {
+	c.t.Helper()
+
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var msg models.GameMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			c.t.Fatalf("waiting for %s: %v", msgType, err)
+		}
+		if msg.Type != msgType {
+			continue
+		}
+		if out != nil {
+			if err := json.Unmarshal(msg.Data, out); err != nil {
+				c.t.Fatalf("decode %s: %v", msgType, err)
+			}
+		}
+		return &msg
+	}
+}
+
+// recordingConn is a clientConn that keeps whatever the server writes to it
+type recordingConn struct {
+	messages []*models.GameMessage
+}
+
+func (c *recordingConn) WriteMessage(msg *models.GameMessage) error {
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+func (c *recordingConn) CloseWithReason(code int, reason string) {}
+
+func (c *recordingConn) Close() error { return nil }
+
+// attachRecorder registers a recordingConn as a current-protocol client of a new player
+func attachRecorder(gs *GameServer) *recordingConn {
+	conn := &recordingConn{}
+	gs.do(func() {
+		gs.clients[conn] = models.NewPlayer("watcher")
+		gs.clientVersions[conn] = models.PROTOCOL_VERSION_CURRENT
+	})
+	return conn
+}
+
+// received lists the message types the recorder has seen, read on the hub
+func (c *recordingConn) received(gs *GameServer) []string {
+	var types []string
+	gs.do(func() {
+		for _, msg := range c.messages {
+			types = append(types, msg.Type)
+		}
+	})
+	return types
+}
+
+// testGameState is the per-player view in game_found and game_update messages
+type testGameState struct {
+	GameID   string    `json:"gameId"`
+	Board    [9]string `json:"board"`
+	Status   string    `json:"status"`
+	Winner   string    `json:"winner"`
+	MySymbol string    `json:"mySymbol"`
+	IsMyTurn bool      `json:"isMyTurn"`
+}
+
+func TestFullGameOverWebSocket(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+
+	var aliceState, bobState testGameState
+	alice.expect(models.MSG_GAME_FOUND, &aliceState)
+	bob.expect(models.MSG_GAME_FOUND, &bobState)
+	if aliceState.GameID == "" || aliceState.GameID != bobState.GameID {
+		t.Fatalf("players matched into different games: %q vs %q", aliceState.GameID, bobState.GameID)
+	}
+	if aliceState.MySymbol == bobState.MySymbol || aliceState.IsMyTurn == bobState.IsMyTurn {
+		t.Fatalf("bad seating: alice %+v, bob %+v", aliceState, bobState)
+	}
+
+	x, o := alice, bob
+	if aliceState.MySymbol == "O" {
+		x, o = bob, alice
+	}
+
+	// X takes the top row while O plays the middle row
+	gameID := aliceState.GameID
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		pos := position
+		mover.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &pos})
+
+		var xState, oState testGameState
+		x.expect(models.MSG_GAME_UPDATE, &xState)
+		o.expect(models.MSG_GAME_UPDATE, &oState)
+		if xState.Board[position] == "" || xState.Board != oState.Board {
+			t.Fatalf("move %d not reflected: X sees %v, O sees %v", position, xState.Board, oState.Board)
+		}
+		if i == 4 && (xState.Status != models.STATUS_FINISHED || xState.Winner != "X") {
+			t.Fatalf("final state = %s/%q, want finished/X", xState.Status, xState.Winner)
+		}
+	}
+
+	var winner, loser models.Player
+	gs.do(func() { winner, loser = *testPlayer(gs, x.playerID), *testPlayer(gs, o.playerID) })
+	if winner.Wins != 1 || loser.Losses != 1 || winner.Rating(models.RATING_POOL_STANDARD) <= loser.Rating(models.RATING_POOL_STANDARD) {
+		t.Errorf("stats not updated: winner %+v, loser %+v", winner, loser)
+	}
+	if records := gs.store.GamesForPlayer(x.playerID); len(records) != 1 {
+		t.Errorf("stored %d game records, want 1", len(records))
+	}
+}
+
+func TestMoveOutOfTurnIsRejected(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+
+	var state testGameState
+	alice.expect(models.MSG_GAME_FOUND, &state)
+	waiting := alice
+	if state.IsMyTurn {
+		waiting = bob
+	}
+
+	position := 4
+	waiting.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: state.GameID, Position: &position})
+	var body models.ErrorPayload
+	waiting.expect(models.MSG_ERROR, &body)
+	if body.Error == "" {
+		t.Error("error message has no text")
+	}
+	if body.Code != models.ERR_NOT_YOUR_TURN {
+		t.Errorf("error code = %q, want %q", body.Code, models.ERR_NOT_YOUR_TURN)
+	}
+}
+
+func TestSessionResumeKeepsPlayer(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+
+	first := dialTestClient(t, wsURL, "name=carol")
+	first.conn.Close()
+
+	resumed := dialTestClient(t, wsURL, "name=carol&playerId="+first.playerID+"&token="+first.token)
+	if resumed.playerID != first.playerID {
+		t.Errorf("resumed as %s, want %s", resumed.playerID, first.playerID)
+	}
+
+	forged := dialTestClient(t, wsURL, "name=mallory&playerId="+first.playerID+"&token=wrong")
+	if forged.playerID == first.playerID {
+		t.Error("a wrong token reclaimed the session")
+	}
+
+	var players int
+	gs.do(func() { players = len(gs.players.Players()) })
+	if players != 2 {
+		t.Errorf("%d players registered, want 2", players)
+	}
+}
+
+// waitForTimers polls until the fake clock has n pending timers, since the server schedules them from the hub
+func waitForTimers(t *testing.T, clk *clock.Fake, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for clk.PendingTimers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d timers pending, want %d", clk.PendingTimers(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDisconnectForfeitsAfterGracePeriod(t *testing.T) {
+	cfg := testConfig()
+	cfg.DisconnectGracePeriod = 30 * time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_RATED})
+
+	var state testGameState
+	alice.expect(models.MSG_GAME_FOUND, &state)
+	bob.expect(models.MSG_GAME_FOUND, nil)
+
+	bob.conn.Close()
+	waitForTimers(t, clk, 1)
+
+	// Just short of the grace period the game is still waiting for bob
+	clk.Advance(cfg.DisconnectGracePeriod - time.Second)
+	var status string
+	gs.do(func() { status = testGame(gs, state.GameID).Status })
+	if status != models.STATUS_PAUSED {
+		t.Fatalf("status before grace period = %s, want paused", status)
+	}
+
+	clk.Advance(time.Second)
+	for state.Status != models.STATUS_FINISHED {
+		alice.expect(models.MSG_GAME_UPDATE, &state)
+	}
+	if state.Winner != state.MySymbol {
+		t.Errorf("winner = %q, want alice's %q", state.Winner, state.MySymbol)
+	}
+}
+
+func TestWebSocketOriginCheck(t *testing.T) {
+	cfg := testConfig()
+	cfg.WebSocketOrigins = config.Origins{"https://*.myapp.dev"}
+	_, _, wsURL := newTestServer(t, cfg)
+
+	for origin, want := range map[string]bool{
+		"https://preview.myapp.dev": true,
+		"https://evil.example":      false,
+		"":                          true, // Non-browser clients
+	} {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=alice", header)
+		if conn != nil {
+			conn.Close()
+		}
+		if got := err == nil; got != want {
+			t.Errorf("origin %q: connected=%v, want %v (%v)", origin, got, want, resp)
+		}
+	}
+}
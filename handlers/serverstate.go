@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"log"
+	"slices"
+
+	"tictactoe-server/models"
+	"tictactoe-server/storage"
+)
+
+// saveState writes who is waiting in each matchmaking queue and the games to resume to StateFile,
+// so a restart doesn't drop them
+func (gs *GameServer) saveState() {
+	if gs.config.StateFile == "" {
+		return
+	}
+
+	state := &models.ServerState{
+		SavedAt: gs.clock.Now(),
+		Players: make([]models.SavedPlayer, 0),
+		Queues:  make([]models.QueuedPlayer, 0),
+		Games:   make([]models.SavedGame, 0),
+	}
+	saved := make(map[string]bool)
+	savePlayer := func(player *models.Player) {
+		if !saved[player.ID] {
+			saved[player.ID] = true
+			state.Players = append(state.Players, models.SavedPlayer{Player: player, Token: player.SessionToken})
+		}
+	}
+
+	for _, mode := range gs.matchmaking.Modes() {
+		for _, playerID := range gs.matchmaking.Queue(mode) {
+			player, exists := gs.players.Player(playerID)
+			if !exists {
+				continue
+			}
+			queuedAt, _ := gs.matchmaking.QueuedAt(playerID)
+			savePlayer(player)
+			state.Queues = append(state.Queues, models.QueuedPlayer{Mode: mode, PlayerID: playerID, QueuedAt: queuedAt})
+		}
+	}
+	for _, gameInstance := range gs.games.Games() {
+		if !gs.resumesAfterRestart(gameInstance) {
+			continue
+		}
+		for _, player := range gameInstance.AllPlayers() {
+			savePlayer(player)
+		}
+		state.Games = append(state.Games, models.SavedGame{
+			Game:        gameInstance,
+			MoveTime:    gameInstance.MoveTime,
+			TurnThought: gameInstance.TurnThought,
+		})
+	}
+
+	if err := storage.WriteServerState(gs.config.StateFile, state); err != nil {
+		log.Printf("Saving server state failed: %v", err)
+		return
+	}
+	log.Printf("Saved %d queued players and %d games to %s", len(state.Queues), len(state.Games), gs.config.StateFile)
+}
+
+// restoreState reads the state saved at the last shutdown: queued players get their places held until they reconnect,
+// and saved games are picked up again
+// State saved longer than RestoreWindow ago is discarded
+func (gs *GameServer) restoreState() {
+	state, err := storage.TakeServerState(gs.config.StateFile)
+	if err != nil {
+		log.Printf("Restoring server state failed: %v", err)
+		return
+	}
+	if state == nil {
+		return
+	}
+	if gs.clock.Now().Sub(state.SavedAt) > gs.config.RestoreWindow {
+		log.Printf("Discarding server state saved at %s, too long ago to restore", state.SavedAt.Format("15:04:05"))
+		return
+	}
+
+	// Players live in memory, so the server usually has to learn about them again from the saved copies
+	for _, saved := range state.Players {
+		if saved.Player == nil {
+			continue
+		}
+		if _, exists := gs.players.Player(saved.Player.ID); !exists {
+			saved.Player.SessionToken = saved.Token
+			gs.players.SavePlayer(saved.Player)
+		}
+	}
+	gs.restoreQueues(state.Queues)
+	gs.restoreGames(state.Games)
+}
+
+// restoreQueues holds each saved queue place until its player reconnects, for up to RestoreWindow
+func (gs *GameServer) restoreQueues(entries []models.QueuedPlayer) {
+	for _, entry := range entries {
+		if _, exists := gs.players.Player(entry.PlayerID); !exists || !slices.Contains(gs.matchmaking.Modes(), entry.Mode) {
+			continue
+		}
+		gs.restoredQueue[entry.PlayerID] = entry
+	}
+	if len(gs.restoredQueue) == 0 {
+		return
+	}
+	log.Printf("Holding queue places of %d players from before the restart", len(gs.restoredQueue))
+	gs.clock.AfterFunc(gs.config.RestoreWindow, gs.doLater(gs.expireRestoredQueue))
+}
+
+// requeueRestored puts a reconnecting player back in the queue they waited in before the restart,
+// ahead of everyone who joined after them, and tells them where they stand
+func (gs *GameServer) requeueRestored(player *models.Player) {
+	entry, held := gs.restoredQueue[player.ID]
+	if !held {
+		return
+	}
+	delete(gs.restoredQueue, player.ID)
+	if _, queued := gs.queuedMode(player.ID); queued || gs.activeGameForPlayer(player.ID) != nil {
+		return
+	}
+
+	queue := gs.matchmaking.Queue(entry.Mode)
+	slot := 0
+	for slot < len(queue) {
+		if queuedAt, _ := gs.matchmaking.QueuedAt(queue[slot]); queuedAt.After(entry.QueuedAt) {
+			break
+		}
+		slot++
+	}
+	gs.matchmaking.Enqueue(entry.Mode, slot, player.ID, entry.QueuedAt)
+	log.Printf("Player %s (%s) restored to %s queue at position %d", player.Name, player.ID, entry.Mode, slot+1)
+
+	gs.sendToPlayer(player.ID, models.NewGameMessage(models.MSG_QUEUE_JOINED, map[string]interface{}{
+		"mode":     entry.Mode,
+		"position": slot + 1,
+		"restored": true,
+		"queuedAt": entry.QueuedAt,
+	}))
+
+	if len(queue)+1 >= matchSize(entry.Mode) {
+		gs.createMatch(entry.Mode)
+	}
+}
+
+// expireRestoredQueue gives up the places of players who didn't reconnect in time
+func (gs *GameServer) expireRestoredQueue() {
+	if len(gs.restoredQueue) > 0 {
+		log.Printf("Releasing queue places of %d players who didn't reconnect after the restart", len(gs.restoredQueue))
+	}
+	clear(gs.restoredQueue)
+}
+
+// resumesAfterRestart reports whether a game in progress is saved at shutdown rather than dropped
+// Bot games are dropped, since bots move on timers that don't survive a restart
+func (gs *GameServer) resumesAfterRestart(gameInstance *models.Game) bool {
+	if gs.config.StateFile == "" || !inProgress(gameInstance) {
+		return false
+	}
+	for _, player := range gameInstance.AllPlayers() {
+		if player.IsBot {
+			return false
+		}
+	}
+	return true
+}
+
+// suspendGamesForShutdown pauses the live games that resume after the restart, stopping their clocks
+// Correspondence games carry on as they are; their players aren't expected to be connected anyway
+func (gs *GameServer) suspendGamesForShutdown() {
+	for _, gameInstance := range gs.games.Games() {
+		if gameInstance.Status != models.STATUS_PLAYING || gameInstance.IsCorrespondence() || !gs.resumesAfterRestart(gameInstance) {
+			continue
+		}
+		if err := gs.gameEngine.Transition(gameInstance, models.STATUS_PAUSED); err != nil {
+			log.Printf("Failed to pause game %s for shutdown: %v", gameInstance.ID, err)
+			continue
+		}
+		gs.sendGameUpdate(gameInstance)
+	}
+}
+
+// restoreGames picks up the games saved at shutdown, seated with the restored players
+// Live games wait paused for their players to reconnect within the disconnect grace period, the side to move first:
+// a side that returns hands the countdown to the next one still away, a side that doesn't forfeits,
+// and a game nobody returns to is resolved by AbandonedGamePolicy
+// Correspondence games carry on, their move deadline starting over
+func (gs *GameServer) restoreGames(saved []models.SavedGame) {
+	restored := 0
+	for _, entry := range saved {
+		gameInstance := entry.Game
+		if gameInstance == nil || !inProgress(gameInstance) {
+			continue
+		}
+		if _, exists := gs.games.Game(gameInstance.ID); exists || !gs.reseatPlayers(gameInstance) {
+			continue
+		}
+		gameInstance.MoveTime = entry.MoveTime
+		gameInstance.TurnThought = entry.TurnThought
+
+		gs.games.SaveGame(gameInstance)
+		gs.sentStates[gameInstance.ID] = &sentState{}
+		gs.startTurnReminders(gameInstance)
+		gs.scheduleMoveDeadline(gameInstance)
+		gs.startSpectatorFeed(gameInstance)
+
+		if gameInstance.Status == models.STATUS_PAUSED {
+			if !isPlayerInGame(gameInstance, gameInstance.DisconnectedPlayerID) {
+				gameInstance.DisconnectedPlayerID = gameInstance.SidePlayers(gameInstance.CurrentTurn)[0].ID
+			}
+			gs.startForfeitTimer(gameInstance.ID, gameInstance.DisconnectedPlayerID)
+		}
+		restored++
+	}
+	if restored > 0 {
+		log.Printf("Restored %d games from before the restart", restored)
+	}
+}
+
+// reseatPlayers points a restored game's seats at the server's players, which the saved copies were decoded apart from
+// Returns false if a seated player is unknown
+func (gs *GameServer) reseatPlayers(gameInstance *models.Game) bool {
+	seat := func(players []*models.Player) bool {
+		for i, player := range players {
+			known, exists := gs.players.Player(player.ID)
+			if !exists {
+				return false
+			}
+			players[i] = known
+		}
+		return true
+	}
+	if !seat(gameInstance.Players) {
+		return false
+	}
+	for _, team := range gameInstance.Teams {
+		if !seat(team.Players) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+func TestQueuesSurviveRestart(t *testing.T) {
+	cfg := testConfig()
+	cfg.StateFile = filepath.Join(t.TempDir(), "state.json")
+	cfg.RestoreWindow = time.Minute
+
+	before, _, beforeURL := newTestServer(t, cfg)
+	alice := dialTestClient(t, beforeURL, "name=alice")
+	carol := dialTestClient(t, beforeURL, "name=carol")
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	alice.expect(models.MSG_QUEUE_JOINED, nil)
+	carol.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_MISERE})
+	carol.expect(models.MSG_QUEUE_JOINED, nil)
+	before.Shutdown()
+
+	after, clk, afterURL := newTestServer(t, cfg)
+	after.do(after.restoreState)
+	if _, err := os.Stat(cfg.StateFile); !os.IsNotExist(err) {
+		t.Errorf("saved state still on disk after restoring it: %v", err)
+	}
+
+	// Bob joined after alice did, so she goes back ahead of him
+	clk.Advance(time.Second)
+	bob := dialTestClient(t, afterURL, "name=bob")
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	bob.expect(models.MSG_QUEUE_JOINED, nil)
+
+	rejoined := dialTestClient(t, afterURL, "playerId="+alice.playerID+"&token="+alice.token)
+	if rejoined.playerID != alice.playerID {
+		t.Fatalf("reconnected as %s, want alice's session %s", rejoined.playerID, alice.playerID)
+	}
+	var joined struct {
+		Mode     string `json:"mode"`
+		Position int    `json:"position"`
+		Restored bool   `json:"restored"`
+	}
+	rejoined.expect(models.MSG_QUEUE_JOINED, &joined)
+	if joined.Mode != models.MODE_CASUAL || joined.Position != 1 || !joined.Restored {
+		t.Errorf("restored queue_joined = %+v, want casual position 1", joined)
+	}
+	rejoined.expect(models.MSG_GAME_FOUND, nil)
+
+	// Carol doesn't come back in time and loses her place
+	clk.Advance(time.Minute)
+	dialTestClient(t, afterURL, "playerId="+carol.playerID+"&token="+carol.token)
+	after.do(func() {
+		if mode, queued := after.queuedMode(carol.playerID); queued {
+			t.Errorf("carol back in the %s queue after the restore window", mode)
+		}
+	})
+}
+
+func TestGamesResumeAfterRestart(t *testing.T) {
+	cfg := testConfig()
+	cfg.StateFile = filepath.Join(t.TempDir(), "state.json")
+	cfg.RestoreWindow = time.Minute
+	cfg.DisconnectGracePeriod = 30 * time.Second
+
+	before, _, beforeURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, beforeURL, models.MODE_CASUAL)
+	playMove(t, x, x, o, gameID, 4)
+	before.Shutdown()
+	var paused testGameState
+	x.expect(models.MSG_GAME_UPDATE, &paused)
+	if paused.Status != models.STATUS_PAUSED {
+		t.Errorf("status at shutdown = %q, want paused", paused.Status)
+	}
+
+	after, clk, afterURL := newTestServer(t, cfg)
+	after.do(after.restoreState)
+
+	// O is to move, so O's countdown runs first; X coming back doesn't resume the game on its own
+	backX := dialTestClient(t, afterURL, "playerId="+x.playerID+"&token="+x.token)
+	var state testGameState
+	backX.expect(models.MSG_GAME_UPDATE, &state)
+	if state.GameID != gameID || state.Status != models.STATUS_PAUSED || state.Board[4] != "X" || state.MySymbol != "X" {
+		t.Fatalf("X's restored game = %+v", state)
+	}
+
+	backO := dialTestClient(t, afterURL, "playerId="+o.playerID+"&token="+o.token)
+	backO.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Status != models.STATUS_PLAYING || !state.IsMyTurn {
+		t.Fatalf("O's game after both returned = %+v", state)
+	}
+	backX.expect(models.MSG_OPPONENT_RECONNECTED, nil)
+	playMove(t, backO, backX, backO, gameID, 0)
+
+	// Past the grace period the game is still going
+	clk.Advance(time.Minute)
+	after.do(func() {
+		if gameInstance := testGame(after, gameID); gameInstance.Status != models.STATUS_PLAYING || len(gameInstance.Moves) != 2 {
+			t.Errorf("game after the grace period: status %q, %d moves", gameInstance.Status, len(gameInstance.Moves))
+		}
+	})
+}
+
+func TestRestoredGameNobodyReturnsToIsAbandoned(t *testing.T) {
+	cfg := testConfig()
+	cfg.StateFile = filepath.Join(t.TempDir(), "state.json")
+	cfg.RestoreWindow = time.Minute
+
+	before, _, beforeURL := newTestServer(t, cfg)
+	_, _, gameID := startGame(t, beforeURL, models.MODE_CASUAL)
+	before.Shutdown()
+
+	after, clk, _ := newTestServer(t, cfg)
+	after.do(after.restoreState)
+	clk.Advance(cfg.DisconnectGracePeriod)
+	after.do(func() {
+		if gameInstance := testGame(after, gameID); gameInstance == nil || gameInstance.Status != models.STATUS_ABANDONED {
+			t.Errorf("restored game nobody returned to = %+v, want abandoned", gameInstance)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+)
+
+// spectatorFeed holds back a rated game's spectator updates until the spectator delay has passed
+// so a player watching their own game from another tab learns nothing the board hasn't shown them
+type spectatorFeed struct {
+	queue    []delayedUpdate     // Updates waiting to be released, oldest first
+	timer    clock.Timer         // Fires when the oldest queued update is due; nil when the queue is empty
+	released *models.GameMessage // The latest full game_update spectators could have been sent
+}
+
+// delayedUpdate is a spectator message waiting out the delay
+type delayedUpdate struct {
+	due   time.Time
+	full  *models.GameMessage
+	delta *models.GameMessage // nil unless full is a game_update with a delta alternative
+}
+
+// spectatorsDelayed reports whether a game's spectators see it late
+func (gs *GameServer) spectatorsDelayed(gameInstance *models.Game) bool {
+	return gs.config.SpectatorDelay > 0 && gameInstance.Rated
+}
+
+// startSpectatorFeed begins delaying a new game's spectator updates from its empty board, update 0
+func (gs *GameServer) startSpectatorFeed(gameInstance *models.Game) {
+	if !gs.spectatorsDelayed(gameInstance) {
+		return
+	}
+	state := gs.spectatorState(gameInstance, 0) // Update 0
+	gs.spectatorFeeds[gameInstance.ID] = &spectatorFeed{
+		released: models.NewGameMessageForGame(models.MSG_GAME_UPDATE, gameInstance.ID, state),
+	}
+}
+
+// sendToSpectatorsOf sends a game's spectators an update, after the spectator delay for rated games
+// delta is the game_delta alternative to a full game_update, or nil
+func (gs *GameServer) sendToSpectatorsOf(gameInstance *models.Game, full, delta *models.GameMessage) {
+	feed, delayed := gs.spectatorFeeds[gameInstance.ID]
+	if !delayed {
+		gs.sendStateToSpectators(gameInstance.ID, full, delta)
+		return
+	}
+
+	feed.queue = append(feed.queue, delayedUpdate{due: gs.clock.Now().Add(gs.config.SpectatorDelay), full: full, delta: delta})
+	if feed.timer == nil {
+		feed.timer = gs.clock.AfterFunc(gs.config.SpectatorDelay, gs.doLater(func() { gs.releaseSpectatorUpdates(gameInstance.ID) }))
+	}
+}
+
+// releaseSpectatorUpdates sends spectators every queued update that is due, in order, and waits for the next one
+// One timer per game keeps updates in order however the wall clock schedules them
+func (gs *GameServer) releaseSpectatorUpdates(gameID string) {
+	feed, exists := gs.spectatorFeeds[gameID]
+	if !exists {
+		return
+	}
+	feed.timer = nil
+
+	now := gs.clock.Now()
+	for len(feed.queue) > 0 && !feed.queue[0].due.After(now) {
+		update := feed.queue[0]
+		feed.queue = feed.queue[1:]
+		if update.full.Type == models.MSG_GAME_UPDATE {
+			feed.released = update.full
+		}
+		gs.sendStateToSpectators(gameID, update.full, update.delta)
+	}
+	if len(feed.queue) > 0 {
+		feed.timer = gs.clock.AfterFunc(feed.queue[0].due.Sub(now), gs.doLater(func() { gs.releaseSpectatorUpdates(gameID) }))
+	}
+}
+
+// releasedSpectatorState returns the delayed state a new or resyncing spectator should see, or nil when they see it live
+func (gs *GameServer) releasedSpectatorState(gameID string) *models.GameMessage {
+	if feed, exists := gs.spectatorFeeds[gameID]; exists {
+		return feed.released
+	}
+	return nil
+}
+
+// stopSpectatorFeed drops a game's undelivered spectator updates
+func (gs *GameServer) stopSpectatorFeed(gameID string) {
+	if feed, exists := gs.spectatorFeeds[gameID]; exists && feed.timer != nil {
+		feed.timer.Stop()
+	}
+	delete(gs.spectatorFeeds, gameID)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+type spectatedState struct {
+	Board []string `json:"board"`
+	Seq   int      `json:"seq"`
+}
+
+func TestRatedGameSpectatorsLagBehind(t *testing.T) {
+	cfg := testConfig()
+	cfg.SpectatorDelay = 5 * time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startRatedGame(t, wsURL)
+
+	// A new spectator starts from the delayed board
+	watcher := dialTestClient(t, wsURL, "name=carol")
+	watcher.send(models.MSG_SPECTATE_GAME, models.GamePayload{GameID: gameID})
+	var state spectatedState
+	watcher.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Seq != 0 || state.Board[4] != "" {
+		t.Fatalf("first spectator state = %+v, want the empty board", state)
+	}
+	// The players see the new spectator count at once
+	x.expect(models.MSG_GAME_UPDATE, nil)
+	o.expect(models.MSG_GAME_UPDATE, nil)
+
+	playMove(t, x, x, o, gameID, 4)
+	var queued int
+	gs.do(func() { queued = len(gs.spectatorFeeds[gameID].queue) })
+	if queued != 2 {
+		t.Fatalf("%d spectator updates held back, want the join and the move", queued)
+	}
+
+	// Resyncing doesn't skip the delay
+	watcher.send(models.MSG_RESYNC, models.GamePayload{GameID: gameID})
+	watcher.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Board[4] != "" {
+		t.Fatalf("resync showed the live board %v", state.Board)
+	}
+
+	clk.Advance(cfg.SpectatorDelay)
+	watcher.expect(models.MSG_GAME_UPDATE, &state)
+	watcher.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Seq != 2 || state.Board[4] != "X" {
+		t.Errorf("delayed state = %+v, want X in the center at update 2", state)
+	}
+}
+
+func TestCasualGameSpectatorsSeeMovesLive(t *testing.T) {
+	cfg := testConfig()
+	cfg.SpectatorDelay = 5 * time.Second
+	gs, _, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	watcher := dialTestClient(t, wsURL, "name=carol")
+	watcher.send(models.MSG_SPECTATE_GAME, models.GamePayload{GameID: gameID})
+	watcher.expect(models.MSG_GAME_UPDATE, nil)
+	x.expect(models.MSG_GAME_UPDATE, nil)
+	o.expect(models.MSG_GAME_UPDATE, nil)
+
+	playMove(t, x, x, o, gameID, 4)
+	var state spectatedState
+	watcher.expect(models.MSG_GAME_UPDATE, &state)
+	if state.Board[4] != "X" {
+		t.Errorf("spectator board = %v, want X in the center", state.Board)
+	}
+	gs.do(func() {
+		if _, delayed := gs.spectatorFeeds[gameID]; delayed {
+			t.Error("casual game spectators are delayed")
+		}
+	})
+}
+
+func TestRemovedGameDropsHeldSpectatorUpdates(t *testing.T) {
+	cfg := testConfig()
+	cfg.SpectatorDelay = 5 * time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startRatedGame(t, wsURL)
+
+	pending := clk.PendingTimers()
+	playMove(t, x, x, o, gameID, 4)
+	waitForTimers(t, clk, pending+1)
+
+	gs.do(func() { gs.removeGame(gameID) })
+	waitForTimers(t, clk, pending)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+	"tictactoe-server/moderation"
+)
+
+// spectatorRoom tracks who is watching a game and which players muted its spectator chat
+type spectatorRoom struct {
+	conns   map[clientConn]*models.Player
+	mutedBy map[string]bool // Player IDs that don't want spectator chat
+}
+
+// newSpectatorRoom creates an empty spectator room
+func newSpectatorRoom() *spectatorRoom {
+	return &spectatorRoom{
+		conns:   make(map[clientConn]*models.Player),
+		mutedBy: make(map[string]bool),
+	}
+}
+
+// handleSpectateGame adds the connection as a spectator of a game
+func (gs *GameServer) handleSpectateGame(conn clientConn, player *models.Player, request *models.GamePayload) {
+	gameInstance, exists := gs.games.Game(request.GameID)
+	if !exists {
+		gs.sendClientError(conn, models.ERR_GAME_NOT_FOUND, "Game not found")
+		return
+	}
+
+	if isPlayerInGame(gameInstance, player.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Players cannot spectate their own game")
+		return
+	}
+
+	room, exists := gs.spectators[request.GameID]
+	if !exists {
+		room = newSpectatorRoom()
+		gs.spectators[request.GameID] = room
+	}
+	room.conns[conn] = player
+
+	log.Printf("Player %s is spectating game %s", player.Name, request.GameID)
+
+	// Spectators of a delayed game start from the board the others are seeing, not the live one
+	if released := gs.releasedSpectatorState(request.GameID); released != nil {
+		gs.sendToClient(conn, released)
+	}
+	// Everyone gets the new spectator count; a full state so the new spectator has the board
+	gs.sendFullGameUpdate(gameInstance)
+}
+
+// handleStopSpectating removes the connection from a game's spectators
+func (gs *GameServer) handleStopSpectating(conn clientConn, request *models.GamePayload) {
+	room, exists := gs.spectators[request.GameID]
+	if exists {
+		delete(room.conns, conn)
+		gs.cleanupSpectatorRoom(request.GameID)
+	}
+	if gameInstance, found := gs.games.Game(request.GameID); exists && found {
+		gs.sendGameUpdate(gameInstance)
+	}
+}
+
+// removeSpectator removes a disconnecting connection from every game it was watching
+// Returns the games whose spectator count changed
+func (gs *GameServer) removeSpectator(conn clientConn) []*models.Game {
+	changed := make([]*models.Game, 0)
+	for gameID, room := range gs.spectators {
+		if _, watching := room.conns[conn]; !watching {
+			continue
+		}
+		delete(room.conns, conn)
+		gs.cleanupSpectatorRoom(gameID)
+		if gameInstance, exists := gs.games.Game(gameID); exists {
+			changed = append(changed, gameInstance)
+		}
+	}
+	return changed
+}
+
+// cleanupSpectatorRoom drops a room once nobody is watching and no mute preferences remain
+func (gs *GameServer) cleanupSpectatorRoom(gameID string) {
+	if room, exists := gs.spectators[gameID]; exists && len(room.conns) == 0 && len(room.mutedBy) == 0 {
+		delete(gs.spectators, gameID)
+	}
+}
+
+// spectatorCount returns how many connections are watching a game
+func (gs *GameServer) spectatorCount(gameID string) int {
+	if room, exists := gs.spectators[gameID]; exists {
+		return len(room.conns)
+	}
+	return 0
+}
+
+// spectatorState returns the neutral game state shown to spectators
+func (gs *GameServer) spectatorState(gameInstance *models.Game, spectatorCount int) *models.GameStateView {
+	state := gs.gameEngine.GetGameStateForPlayer(gameInstance, "")
+	if gameInstance.PlayerX() != nil {
+		state.PlayerXName = gameInstance.PlayerX().Name
+	}
+	if gameInstance.PlayerO() != nil {
+		state.PlayerOName = gameInstance.PlayerO().Name
+	}
+	state.Spectating = true
+	state.SpectatorCount = spectatorCount
+	return state
+}
+
+// sendToSpectators sends a message to everyone watching a game
+func (gs *GameServer) sendToSpectators(gameID string, msg *models.GameMessage) {
+	room, exists := gs.spectators[gameID]
+	if !exists {
+		return
+	}
+	for conn := range room.conns {
+		gs.sendToClient(conn, msg)
+	}
+}
+
+// handleSpectatorChat checks a spectator may chat, then has the message moderated and relayed
+func (gs *GameServer) handleSpectatorChat(conn clientConn, player *models.Player, chat *models.ChatPayload) {
+	room, exists := gs.spectators[chat.GameID]
+	if !exists || room.conns[conn] == nil {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Only spectators can use spectator chat")
+		return
+	}
+	if gs.isMuted(player.ID) {
+		gs.sendClientError(conn, models.ERR_MUTED, "You are muted")
+		return
+	}
+
+	// The moderator may be a remote service, so it is asked off the hub and the verdict comes back as an event
+	go func() {
+		verdict := gs.moderate(moderation.KIND_CHAT, chat.Text)
+		gs.do(func() { gs.relaySpectatorChat(conn, player, chat, verdict) })
+	}()
+}
+
+// relaySpectatorChat sends a moderated spectator message to other spectators and unmuted players
+func (gs *GameServer) relaySpectatorChat(conn clientConn, player *models.Player, chat *models.ChatPayload, verdict moderation.Verdict) {
+	if !verdict.Allowed {
+		gs.sendClientError(conn, models.ERR_MESSAGE_BLOCKED, "Message blocked: "+verdict.Reason)
+		return
+	}
+
+	event := map[string]interface{}{
+		"channel": "spectator",
+		"text":    verdict.Text,
+	}
+	if verdict.Text != chat.Text {
+		event["moderated"] = verdict.Reason
+	}
+	gs.logEvent(chat.GameID, models.EVENT_CHAT, player.ID, event)
+
+	chatMsg := models.NewGameMessageForGame(models.MSG_SPECTATOR_CHAT, chat.GameID, map[string]interface{}{
+		"gameId":    chat.GameID,
+		"playerId":  player.ID,
+		"name":      player.Name,
+		"text":      verdict.Text,
+		"timestamp": gs.clock.Now(),
+	})
+
+	gs.sendChatToSpectators(chat.GameID, player.ID, chatMsg)
+	gameInstance, exists := gs.games.Game(chat.GameID)
+	if !exists {
+		return
+	}
+	var mutedBy map[string]bool
+	if room, watched := gs.spectators[chat.GameID]; watched {
+		mutedBy = room.mutedBy
+	}
+	for _, p := range gameInstance.AllPlayers() {
+		if !p.IsBot && !mutedBy[p.ID] && !gs.store.HasBlocked(p.ID, player.ID) {
+			gs.sendToPlayer(p.ID, chatMsg)
+		}
+	}
+}
+
+// handleMuteSpectatorChat lets a player mute or unmute spectator chat for their game
+func (gs *GameServer) handleMuteSpectatorChat(conn clientConn, player *models.Player, request *models.MuteChatPayload) {
+	gameInstance, exists := gs.games.Game(request.GameID)
+	if !exists || !isPlayerInGame(gameInstance, player.ID) {
+		gs.sendClientError(conn, models.ERR_NOT_IN_GAME, "You are not playing in this game")
+		return
+	}
+
+	room, exists := gs.spectators[request.GameID]
+	if !exists {
+		room = newSpectatorRoom()
+		gs.spectators[request.GameID] = room
+	}
+	if request.Muted {
+		room.mutedBy[player.ID] = true
+	} else {
+		delete(room.mutedBy, player.ID)
+	}
+
+	gs.sendToClient(conn, models.NewGameMessageForGame(models.MSG_MUTE_SPECTATOR_CHAT, request.GameID,
+		map[string]interface{}{"gameId": request.GameID, "muted": request.Muted}))
+}
+
+// isPlayerInGame reports whether the player is X or O in the game
+func isPlayerInGame(gameInstance *models.Game, playerID string) bool {
+	return gameInstance.SideOf(playerID) != ""
+}
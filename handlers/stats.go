@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tictactoe-server/models"
+	"tictactoe-server/webhooks"
+)
+
+// statsBucketWidth is the rating range each bar of a summary's histogram covers
+const statsBucketWidth = 100
+
+// Summaries returned by /api/stats when no limit is asked for, and at most
+const (
+	defaultStatsLimit = 30
+	maxStatsLimit     = 366
+)
+
+// nextMidnight returns the first UTC midnight after t
+func nextMidnight(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// scheduleStatsAggregation arms the timer that summarizes the day ending at end
+func (gs *GameServer) scheduleStatsAggregation(end time.Time) {
+	gs.statsTimer = gs.clock.AfterFunc(end.Sub(gs.clock.Now()), gs.doLater(func() { gs.aggregateStats(end) }))
+}
+
+// aggregateStats stores and reports the day that ended at end, and the week on Mondays, then waits for the next day
+func (gs *GameServer) aggregateStats(end time.Time) {
+	summaries := []*models.StatsSummary{gs.summarizeStats(models.STATS_DAILY, end.AddDate(0, 0, -1), end)}
+	if end.Weekday() == time.Monday {
+		summaries = append(summaries, gs.summarizeStats(models.STATS_WEEKLY, end.AddDate(0, 0, -7), end))
+	}
+
+	for _, summary := range summaries {
+		gs.store.SaveStatsSummary(summary)
+		log.Printf("Aggregated %s stats from %s: %d games, %d players", summary.Period, summary.Start.Format(time.DateOnly),
+			summary.Games, summary.UniquePlayers)
+		if gs.statsReports != nil {
+			gs.statsReports.Send(webhooks.EVENT_STATS_REPORT, summary)
+		}
+	}
+
+	// Scheduling from end rather than now keeps a timer that fires early from summarizing the same day twice
+	gs.scheduleStatsAggregation(end.Add(24 * time.Hour))
+}
+
+// summarizeStats aggregates the games that ended between start and end
+// Bots are left out of the player count and the rating histogram
+func (gs *GameServer) summarizeStats(period string, start, end time.Time) *models.StatsSummary {
+	summary := &models.StatsSummary{Period: period, Start: start, End: end}
+	seen := make(map[string]bool)
+	ratings := make([]int, 0)
+	var played time.Duration
+
+	for _, record := range gs.store.GamesEndedBetween(start, end) {
+		summary.Games++
+		if record.Rated {
+			summary.RatedGames++
+		}
+		played += record.EndTime.Sub(record.StartTime)
+
+		for _, playerID := range []string{record.PlayerXID, record.PlayerOID, record.PlayerDeltaID} {
+			player, _ := gs.players.Player(playerID)
+			if playerID == "" || seen[playerID] || (player != nil && player.IsBot) {
+				continue
+			}
+			seen[playerID] = true
+			if player != nil && !player.Deleted {
+				ratings = append(ratings, player.Rating(models.RATING_POOL_STANDARD))
+			}
+		}
+	}
+
+	summary.UniquePlayers = len(seen)
+	if summary.Games > 0 {
+		summary.AverageGameSeconds = played.Seconds() / float64(summary.Games)
+	}
+	summary.RatingHistogram = ratingHistogram(ratings)
+	return summary
+}
+
+// ratingHistogram counts ratings into statsBucketWidth buckets from the lowest rating's bucket to the highest's
+func ratingHistogram(ratings []int) []models.RatingBucket {
+	buckets := make([]models.RatingBucket, 0)
+	if len(ratings) == 0 {
+		return buckets
+	}
+
+	sort.Ints(ratings)
+	low := ratings[0] - ratings[0]%statsBucketWidth
+	for floor := low; floor <= ratings[len(ratings)-1]; floor += statsBucketWidth {
+		buckets = append(buckets, models.RatingBucket{Min: floor, Max: floor + statsBucketWidth})
+	}
+	for _, rating := range ratings {
+		buckets[(rating-low)/statsBucketWidth].Players++
+	}
+	return buckets
+}
+
+// HandleStatsAPI serves GET /api/stats/daily and GET /api/stats/weekly, newest first, and GET /api/stats/heatmap
+// ?limit= sets how many days or weeks are returned
+func (gs *GameServer) HandleStatsAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	period := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/stats"), "/")
+	if period == "heatmap" {
+		gs.handleHeatmapAPI(w, r)
+		return
+	}
+	if period != models.STATS_DAILY && period != models.STATS_WEEKLY {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	limit := defaultStatsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(parsed, maxStatsLimit)
+	}
+
+	writeJSON(w, http.StatusOK, gs.store.StatsSummaries(period, limit))
+}
+
+// registerStatsReports posts each day's and week's summary to the operator's report URLs
+func (gs *GameServer) registerStatsReports() {
+	if len(gs.config.StatsReportURLs) == 0 {
+		return
+	}
+	gs.statsReports = webhooks.NewDispatcher(gs.config.StatsReportURLs, gs.config.WebhookSecret,
+		gs.config.WebhookMaxAttempts, gs.config.WebhookBackoff)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+	"tictactoe-server/webhooks"
+)
+
+func TestDailyAndWeeklyStatsAggregation(t *testing.T) {
+	var mu sync.Mutex
+	var reports []webhooks.Event
+	operator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhooks.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		mu.Lock()
+		reports = append(reports, event)
+		mu.Unlock()
+	}))
+	defer operator.Close()
+
+	cfg := testConfig()
+	cfg.StatsReportURLs = []string{operator.URL}
+	cfg.WebhookMaxAttempts = 1
+	clk := clock.NewFake(testEpoch) // A Monday
+	gs := NewGameServerWithClock(cfg, clk)
+
+	alice := addSeasonPlayer(gs, "alice", 1050, 0)
+	bob := addSeasonPlayer(gs, "bob", 1249, 0)
+	bot := addSeasonPlayer(gs, "bot", 1000, 0)
+	bot.IsBot = true
+	play := func(x, o *models.Player, rated bool, length time.Duration) {
+		end := clk.Now()
+		gs.store.SaveGame(&models.GameRecord{PlayerXID: x.ID, PlayerOID: o.ID, Rated: rated, StartTime: end.Add(-length), EndTime: end})
+	}
+
+	play(alice, bob, true, time.Minute)
+	play(alice, bot, false, 3*time.Minute)
+	gs.do(func() { gs.scheduleStatsAggregation(nextMidnight(clk.Now())) })
+	clk.Advance(12 * time.Hour)
+
+	recorder := httptest.NewRecorder()
+	gs.HandleStatsAPI(recorder, httptest.NewRequest(http.MethodGet, "/api/stats/daily", nil))
+	var daily []models.StatsSummary
+	if err := json.Unmarshal(recorder.Body.Bytes(), &daily); err != nil {
+		t.Fatal(err)
+	}
+	if len(daily) != 1 {
+		t.Fatalf("daily summaries = %+v, want one", daily)
+	}
+	day := daily[0]
+	if !day.Start.Equal(testEpoch.Add(-12*time.Hour)) || day.Games != 2 || day.RatedGames != 1 || day.UniquePlayers != 2 || day.AverageGameSeconds != 120 {
+		t.Errorf("day = %+v", day)
+	}
+	want := []models.RatingBucket{{Min: 1000, Max: 1100, Players: 1}, {Min: 1100, Max: 1200}, {Min: 1200, Max: 1300, Players: 1}}
+	if len(day.RatingHistogram) != len(want) {
+		t.Fatalf("histogram = %+v, want %+v", day.RatingHistogram, want)
+	}
+	for i := range want {
+		if day.RatingHistogram[i] != want[i] {
+			t.Errorf("histogram = %+v, want %+v", day.RatingHistogram, want)
+		}
+	}
+
+	// The next Monday's midnight closes the week as well
+	clk.Advance(24 * time.Hour)
+	play(bob, alice, true, time.Minute)
+	clk.Advance(5 * 24 * time.Hour)
+
+	recorder = httptest.NewRecorder()
+	gs.HandleStatsAPI(recorder, httptest.NewRequest(http.MethodGet, "/api/stats/daily?limit=2", nil))
+	daily = nil
+	json.Unmarshal(recorder.Body.Bytes(), &daily)
+	if len(daily) != 2 || daily[0].Games != 0 || !daily[0].End.Equal(testEpoch.Add(6*24*time.Hour+12*time.Hour)) {
+		t.Errorf("latest daily summaries = %+v", daily)
+	}
+
+	weekly := gs.store.StatsSummaries(models.STATS_WEEKLY, defaultStatsLimit)
+	if len(weekly) != 1 || weekly[0].Games != 3 || weekly[0].UniquePlayers != 2 {
+		t.Errorf("weekly summaries = %+v", weekly)
+	}
+
+	gs.Shutdown()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 8 || reports[0].Type != webhooks.EVENT_STATS_REPORT {
+		t.Errorf("%d reports sent, want 7 days and a week", len(reports))
+	}
+}
+
+func TestStatsAPIRejectsUnknownPeriods(t *testing.T) {
+	gs := NewGameServer(testConfig())
+	for path, status := range map[string]int{
+		"/api/stats/monthly":       http.StatusNotFound,
+		"/api/stats/daily?limit=0": http.StatusBadRequest,
+		"/api/stats/weekly":        http.StatusOK,
+	} {
+		recorder := httptest.NewRecorder()
+		gs.HandleStatsAPI(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != status {
+			t.Errorf("GET %s = %d, want %d", path, recorder.Code, status)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// handleSwapDecision lets O take over X's first move under the pie rule, or decline and reply as usual
+func (gs *GameServer) handleSwapDecision(conn clientConn, player *models.Player, request *models.SwapDecisionPayload) {
+	gameInstance, exists := gs.games.Game(request.GameID)
+	if !exists || !isPlayerInGame(gameInstance, player.ID) {
+		gs.sendClientError(conn, models.ERR_GAME_NOT_FOUND, "Game not found")
+		return
+	}
+
+	var err error
+	if request.Swap {
+		err = gs.gameEngine.SwapSides(gameInstance, player.ID)
+	} else {
+		err = gs.gameEngine.DeclineSwap(gameInstance, player.ID)
+	}
+	if err != nil {
+		gs.sendClientError(conn, models.ErrorCode(err), err.Error())
+		return
+	}
+
+	gs.logEvent(gameInstance.ID, models.EVENT_SWAP_DECISION, player.ID, map[string]interface{}{"swap": request.Swap})
+	if !request.Swap {
+		gs.sendGameUpdate(gameInstance)
+		return
+	}
+
+	// Deltas don't say who plays which side, so everyone needs the whole state after a swap
+	log.Printf("Game %s: %s swapped sides and now plays X", gameInstance.ID, player.Name)
+	gs.sendFullGameUpdate(gameInstance)
+}
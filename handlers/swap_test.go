@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+// swapState is the part of a game state the pie rule adds
+type swapState struct {
+	testGameState
+	SwapPending bool `json:"swapPending"`
+	CanSwap     bool `json:"canSwap"`
+}
+
+func TestPieRuleSwap(t *testing.T) {
+	cfg := testConfig()
+	cfg.PieRule = true
+	_, _, wsURL := newTestServer(t, cfg)
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	position := 4
+	x.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: &position})
+	var state swapState
+	x.expect(models.MSG_GAME_UPDATE, &state)
+	if !state.SwapPending || state.CanSwap {
+		t.Errorf("X after the first move = %+v", state)
+	}
+	o.expect(models.MSG_GAME_UPDATE, &state)
+	if !state.CanSwap || !state.IsMyTurn {
+		t.Errorf("O after the first move = %+v", state)
+	}
+
+	// Only O decides
+	x.send(models.MSG_SWAP_DECISION, models.SwapDecisionPayload{GameID: gameID, Swap: true})
+	var body struct {
+		Error string `json:"error"`
+	}
+	x.expect(models.MSG_ERROR, &body)
+	if body.Error != "only O may swap sides" {
+		t.Errorf("swap by X: %q", body.Error)
+	}
+
+	o.send(models.MSG_SWAP_DECISION, models.SwapDecisionPayload{GameID: gameID, Swap: true})
+	o.expect(models.MSG_GAME_UPDATE, &state)
+	if state.MySymbol != "X" || state.Board[4] != "X" || state.IsMyTurn || state.SwapPending {
+		t.Errorf("swapper after swapping = %+v", state)
+	}
+	x.expect(models.MSG_GAME_UPDATE, &state)
+	if state.MySymbol != "O" || !state.IsMyTurn {
+		t.Errorf("first mover after the swap = %+v", state)
+	}
+
+	// The former X now replies as O; the offer is gone for good
+	playMove(t, x, o, x, gameID, 0)
+	o.send(models.MSG_SWAP_DECISION, models.SwapDecisionPayload{GameID: gameID, Swap: true})
+	o.expect(models.MSG_ERROR, &body)
+	if body.Error != "no swap is on offer" {
+		t.Errorf("second swap: %q", body.Error)
+	}
+}
+
+func TestPieRuleOffByDefault(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+
+	playMove(t, x, x, o, gameID, 4)
+	o.send(models.MSG_SWAP_DECISION, models.SwapDecisionPayload{GameID: gameID, Swap: true})
+	var body struct {
+		Error string `json:"error"`
+	}
+	o.expect(models.MSG_ERROR, &body)
+	if body.Error != "no swap is on offer" {
+		t.Errorf("swap without the pie rule: %q", body.Error)
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// handleRequestTakeback asks the opponent to let the player undo their last move
+func (gs *GameServer) handleRequestTakeback(player *models.Player, request *models.GamePayload) {
+	gameInstance, exists := gs.games.Game(request.GameID)
+	if !exists || !isPlayerInGame(gameInstance, player.ID) {
+		gs.sendError(player.ID, models.ERR_GAME_NOT_FOUND, "Game not found")
+		return
+	}
+
+	if gameInstance.Rated {
+		gs.sendError(player.ID, models.ERR_NOT_ALLOWED, "Takebacks are not allowed in rated games")
+		return
+	}
+
+	if gameInstance.Teams != nil {
+		gs.sendError(player.ID, models.ERR_NOT_ALLOWED, "Takebacks are not allowed in team games")
+		return
+	}
+
+	if len(gameInstance.Players) > 2 {
+		gs.sendError(player.ID, models.ERR_NOT_ALLOWED, "Takebacks are not allowed in trio games")
+		return
+	}
+
+	if gameInstance.TimeLeft != nil {
+		gs.sendError(player.ID, models.ERR_NOT_ALLOWED, "Takebacks are not allowed in blitz games")
+		return
+	}
+
+	if gameInstance.Status != models.STATUS_PLAYING {
+		gs.sendError(player.ID, models.ERR_NOT_PLAYING, "Game is not in playing state")
+		return
+	}
+
+	if gameInstance.TakebackRequestedBy != "" {
+		gs.sendError(player.ID, models.ERR_ALREADY_PENDING, "A takeback is already pending")
+		return
+	}
+
+	opponent := opponentOf(gameInstance, player.ID)
+	if opponent != nil && opponent.IsBot {
+		// Bots always agree
+		err := gs.gameEngine.UndoLastMove(gameInstance, player.ID)
+		if err != nil {
+			gs.sendError(player.ID, models.ErrorCode(err), err.Error())
+			return
+		}
+		gs.logEvent(gameInstance.ID, models.EVENT_TAKEBACK_ACCEPTED, opponent.ID, nil)
+		gs.sendGameUpdate(gameInstance)
+		return
+	}
+
+	gameInstance.TakebackRequestedBy = player.ID
+
+	gs.logEvent(gameInstance.ID, models.EVENT_TAKEBACK_REQUESTED, player.ID, nil)
+
+	if opponent != nil {
+		gs.sendToPlayer(opponent.ID, models.NewGameMessageForGame(models.MSG_TAKEBACK_REQUESTED, gameInstance.ID,
+			map[string]string{"gameId": gameInstance.ID, "playerId": player.ID}))
+	}
+	gs.sendGameUpdate(gameInstance)
+}
+
+// handleAnswerTakeback applies or rejects the opponent's pending takeback request
+func (gs *GameServer) handleAnswerTakeback(player *models.Player, request *models.GamePayload, accept bool) {
+	gameInstance, exists := gs.games.Game(request.GameID)
+	if !exists || !isPlayerInGame(gameInstance, player.ID) {
+		gs.sendError(player.ID, models.ERR_GAME_NOT_FOUND, "Game not found")
+		return
+	}
+
+	requesterID := gameInstance.TakebackRequestedBy
+	if requesterID == "" || requesterID == player.ID {
+		gs.sendError(player.ID, models.ERR_NOTHING_PENDING, "No takeback request to answer")
+		return
+	}
+
+	var err error
+	if accept {
+		// Board, move log and turn are reverted together
+		err = gs.gameEngine.UndoLastMove(gameInstance, requesterID)
+	} else {
+		gameInstance.TakebackRequestedBy = ""
+	}
+
+	if err != nil {
+		gs.sendError(player.ID, models.ErrorCode(err), err.Error())
+		return
+	}
+
+	if accept {
+		log.Printf("Takeback accepted in game %s", gameInstance.ID)
+		gs.logEvent(gameInstance.ID, models.EVENT_TAKEBACK_ACCEPTED, player.ID, nil)
+	} else {
+		gs.logEvent(gameInstance.ID, models.EVENT_TAKEBACK_DECLINED, player.ID, nil)
+		gs.sendToPlayer(requesterID, models.NewGameMessageForGame(models.MSG_TAKEBACK_DECLINED, gameInstance.ID,
+			map[string]string{"gameId": gameInstance.ID}))
+	}
+	gs.sendGameUpdate(gameInstance)
+}
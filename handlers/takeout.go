@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"tictactoe-server/models"
+)
+
+// authorizedPlayer returns the player whose session token the request carries as a bearer token, or nil
+func (gs *GameServer) authorizedPlayer(r *http.Request, playerID string) *models.Player {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	var player *models.Player
+	gs.do(func() { player = gs.sessionPlayer(playerID, token) })
+	return player
+}
+
+// serveDataExport sends a player everything stored about them as a downloadable JSON archive
+func (gs *GameServer) serveDataExport(w http.ResponseWriter, r *http.Request, playerID string) {
+	if gs.authorizedPlayer(r, playerID) == nil {
+		http.Error(w, "invalid session", http.StatusUnauthorized)
+		return
+	}
+
+	export := &models.PlayerExport{
+		Games:  gs.store.GamesForPlayer(playerID),
+		Events: gs.store.PlayerEvents(playerID),
+
+		Blocked: gs.store.BlockedPlayers(playerID),
+
+		Identities: gs.store.PlayerIdentities(playerID),
+	}
+	var exists bool
+	gs.do(func() {
+		export.ExportedAt = gs.clock.Now()
+		export.Profile, exists = gs.buildProfile(playerID)
+	})
+	if !exists {
+		http.Error(w, "player not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="player-`+playerID+`.json"`)
+	writeJSON(w, http.StatusOK, export)
+}
+
+// handleDeleteAccount deletes a player's account; see deletePlayer
+// Players in a game in progress must finish it first
+func (gs *GameServer) handleDeleteAccount(w http.ResponseWriter, r *http.Request, playerID string) {
+	if gs.authorizedPlayer(r, playerID) == nil {
+		http.Error(w, "invalid session", http.StatusUnauthorized)
+		return
+	}
+
+	var deleted, playing bool
+	gs.do(func() {
+		if gs.activeGameForPlayer(playerID) != nil || len(gs.correspondenceGames(playerID)) > 0 {
+			playing = true
+			return
+		}
+		deleted = gs.deletePlayer(playerID)
+	})
+	switch {
+	case playing:
+		http.Error(w, "finish your game before deleting your account", http.StatusConflict)
+	case !deleted:
+		http.Error(w, "player not found", http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deletePlayer soft-deletes a player: their connections close, personal data is purged and their name is replaced
+// in stored games and archived leaderboards, while their ID stays so opponents' histories don't break
+// Returns false if the player doesn't exist or was already deleted
+func (gs *GameServer) deletePlayer(playerID string) bool {
+	player, exists := gs.players.Player(playerID)
+	if !exists || player.Deleted {
+		return false
+	}
+
+	// Closing the socket ends the read loop, which runs the normal disconnect cleanup
+	for _, conn := range gs.connectionsForPlayer(playerID) {
+		gs.closeClient(conn, models.CLOSE_ACCOUNT_DELETED)
+	}
+	gs.removeFromQueue(playerID)
+
+	// Games still in memory share the player, so they show the new name too
+	player.SoftDelete()
+	delete(gs.lastActive, playerID)
+	delete(gs.fingerprints, playerID)
+	delete(gs.recentOpponents, playerID)
+	delete(gs.outboxes, playerID)
+	gs.store.DeleteBlocks(playerID)
+	gs.store.DeleteIdentities(playerID)
+	gs.store.DeleteDeviceKeys(playerID)
+
+	games := gs.store.AnonymizePlayer(playerID, models.DELETED_PLAYER_NAME)
+	log.Printf("Deleted player %s and anonymized %d games", playerID, games)
+	gs.broadcastLeaderboard()
+	return true
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+// playerRequest calls the player API with a session token
+func playerRequest(gs *GameServer, method, path, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	gs.HandlePlayerAPI(recorder, request)
+	return recorder
+}
+
+func TestDataExportAndAccountDeletion(t *testing.T) {
+	gs, _, wsURL := newTestServer(t, testConfig())
+	x, o, gameID := startGame(t, wsURL, models.MODE_RATED)
+
+	if recorder := playerRequest(gs, http.MethodDelete, "/api/players/"+x.playerID, x.token); recorder.Code != http.StatusConflict {
+		t.Errorf("delete during a game = %d, want 409", recorder.Code)
+	}
+
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+	x.expect(models.MSG_GAME_END, nil)
+
+	if recorder := playerRequest(gs, http.MethodGet, "/api/players/"+x.playerID+"/export", o.token); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("export with another player's token = %d, want 401", recorder.Code)
+	}
+	recorder := playerRequest(gs, http.MethodGet, "/api/players/"+x.playerID+"/export", x.token)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Disposition") == "" {
+		t.Fatalf("export = %d %v", recorder.Code, recorder.Header())
+	}
+	var export models.PlayerExport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &export); err != nil {
+		t.Fatal(err)
+	}
+	if export.Profile.Player.ID != x.playerID || len(export.Games) != 1 || len(export.Profile.RatingHistory) != 1 || len(export.Events) == 0 {
+		t.Errorf("export = %+v", export)
+	}
+
+	if recorder := playerRequest(gs, http.MethodDelete, "/api/players/"+x.playerID, x.token); recorder.Code != http.StatusNoContent {
+		t.Fatalf("delete = %d", recorder.Code)
+	}
+	var reason models.CloseReason
+	x.expect(models.MSG_DISCONNECT, &reason)
+	if reason.Code != models.CLOSE_ACCOUNT_DELETED || reason.Reconnect {
+		t.Errorf("close reason = %+v", reason)
+	}
+
+	if recorder := playerRequest(gs, http.MethodGet, "/api/players/"+x.playerID, ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("deleted profile = %d, want 404", recorder.Code)
+	}
+	if recorder := playerRequest(gs, http.MethodDelete, "/api/players/"+x.playerID, x.token); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("second delete with the old token = %d, want 401", recorder.Code)
+	}
+	for _, event := range gs.store.PlayerEvents(x.playerID) {
+		if name, named := event.Data["name"]; named && name != models.DELETED_PLAYER_NAME {
+			t.Errorf("event %s still names the player %v", event.Type, name)
+		}
+	}
+	if history := gs.store.RatingHistory(x.playerID); len(history) != 0 {
+		t.Errorf("rating history kept: %v", history)
+	}
+
+	// The opponent keeps the game, and their head-to-head record, against the same but now anonymous player
+	games := gs.store.GamesForPlayer(o.playerID)
+	if len(games) != 1 || games[0].PlayerXName != models.DELETED_PLAYER_NAME || games[0].PlayerXID != x.playerID || games[0].PlayerOID != o.playerID {
+		t.Fatalf("opponent's games = %+v", games)
+	}
+	var profile *models.PlayerProfile
+	gs.do(func() { profile, _ = gs.buildProfile(o.playerID) })
+	if len(profile.HeadToHead) != 1 || profile.HeadToHead[0].OpponentID != x.playerID || profile.HeadToHead[0].Losses != 1 {
+		t.Errorf("opponent's head-to-head = %+v", profile.HeadToHead)
+	}
+}
+
+func TestDeletedPlayerLeavesLeaderboards(t *testing.T) {
+	cfg := testConfig()
+	gs := NewGameServer(cfg)
+	top := addSeasonPlayer(gs, "top", 1400, 10)
+	next := addSeasonPlayer(gs, "next", 1200, 10)
+	top.SessionToken = "secret"
+	gs.store.ArchiveSeason(&models.SeasonArchive{
+		Season:    models.Season{Number: 1},
+		Standings: []models.SeasonStanding{{Rank: 1, PlayerID: top.ID, Name: top.Name}, {Rank: 2, PlayerID: next.ID, Name: next.Name}},
+	})
+
+	var deleted bool
+	gs.do(func() { deleted = gs.deletePlayer(top.ID) })
+	if !deleted || !top.Deleted || top.Name != models.DELETED_PLAYER_NAME || top.SessionToken != "" {
+		t.Fatalf("deleted player = %+v", top)
+	}
+
+	var leaderboard []*models.Player
+	gs.do(func() { leaderboard = gs.getLeaderboard(models.RATING_POOL_STANDARD) })
+	if len(leaderboard) != 1 || leaderboard[0] != next {
+		t.Errorf("leaderboard = %v, want only the remaining player", leaderboard)
+	}
+	archive, _ := gs.store.SeasonArchive(1)
+	if archive.Standings[0].PlayerID != top.ID || archive.Standings[0].Name != models.DELETED_PLAYER_NAME || archive.Standings[1].Name != "next" {
+		t.Errorf("archived standings = %+v", archive.Standings)
+	}
+	if err := models.ValidateName(models.DELETED_PLAYER_NAME); err == nil {
+		t.Error("players can take the deleted player name")
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"tictactoe-server/clock"
+	"tictactoe-server/models"
+)
+
+// teamTurn collects a team's proposals for the move it is deciding
+type teamTurn struct {
+	symbol    models.Symbol
+	proposals map[string]int // Player ID -> proposed position
+	first     string         // Player whose proposal is played if the team doesn't agree in time, the earliest to propose
+	deadline  time.Time
+	timer     clock.Timer
+}
+
+// matchSize returns how many queued players a game in the mode needs
+func matchSize(mode string) int {
+	switch mode {
+	case models.MODE_TEAM:
+		return 2 * models.TEAM_SIZE
+	case models.MODE_TRIO:
+		return models.TRIO_PLAYERS
+	}
+	return 2
+}
+
+// createTeamMatch starts a team game between the four longest-waiting players in the team queue
+// The strongest and weakest of them play together against the other two, so the sides are close in rating
+func (gs *GameServer) createTeamMatch() {
+	if gs.newGamesPaused() {
+		return
+	}
+
+	queue := gs.matchmaking.Queue(models.MODE_TEAM)
+	size := matchSize(models.MODE_TEAM)
+	if len(queue) < size {
+		log.Printf("Not enough players in team queue: %d", len(queue))
+		return
+	}
+
+	if gs.atGameLimit() {
+		gs.capacity.DeferredMatches++
+		log.Printf("Active game limit of %d reached, team queue of %d waits", gs.config.MaxActiveGames, len(queue))
+		return
+	}
+
+	var players []*models.Player
+	for _, playerID := range queue[:size] {
+		gs.matchmaking.Dequeue(playerID)
+		if player, exists := gs.players.Player(playerID); exists {
+			players = append(players, player)
+		}
+	}
+
+	if len(players) < size {
+		log.Printf("Team match needs %d players but only %d were found", size, len(players))
+		return
+	}
+
+	byRating := append([]*models.Player(nil), players...)
+	sort.SliceStable(byRating, func(i, j int) bool {
+		return byRating[i].Rating(models.RATING_POOL_STANDARD) > byRating[j].Rating(models.RATING_POOL_STANDARD)
+	})
+	team1 := []*models.Player{byRating[0], byRating[3]}
+	team2 := []*models.Player{byRating[1], byRating[2]}
+	gs.proposeMatch(models.MODE_TEAM, players, func() { gs.startTeamMatch(team1, team2) })
+}
+
+// startTeamMatch creates a team game and tells every player in it
+// The first player of each team is seated and makes the moves the team settles on
+func (gs *GameServer) startTeamMatch(team1, team2 []*models.Player) *models.Game {
+	newGame := gs.newGameForMode(models.MODE_TEAM)
+	gs.gameEngine.SeatPlayers(newGame, team1[0], team2[0])
+	if newGame.PlayerX() != team1[0] {
+		team1, team2 = team2, team1
+	}
+	newGame.Teams = []*models.Team{{Symbol: models.SYMBOL_X, Players: team1}, {Symbol: models.SYMBOL_O, Players: team2}}
+	for _, team := range newGame.Teams {
+		for _, player := range team.Players {
+			player.Symbol = team.Symbol
+		}
+	}
+	gs.addGame(newGame)
+
+	log.Printf("Created team game %s between %s (X) and %s (O)", newGame.ID, teamNames(team1), teamNames(team2))
+	gs.logGameCreated(newGame)
+
+	for _, player := range newGame.AllPlayers() {
+		gs.sendToPlayer(player.ID, models.NewGameMessageForGame(models.MSG_GAME_FOUND, newGame.ID,
+			gs.gameEngine.GetGameStateForPlayer(newGame, player.ID)))
+	}
+	return newGame
+}
+
+// teamNames joins a team's player names for logging
+func teamNames(team []*models.Player) string {
+	names := team[0].Name
+	for _, player := range team[1:] {
+		names += " & " + player.Name
+	}
+	return names
+}
+
+// handleProposeMove records a team member's proposed move
+// The move is played as soon as the whole team proposes the same position; if time runs out first,
+// the teammate who proposed first this turn decides
+func (gs *GameServer) handleProposeMove(ctx context.Context, conn clientConn, player *models.Player, move *models.MakeMovePayload) {
+	gameInstance, exists := gs.games.Game(move.GameID)
+	if !exists || !isPlayerInGame(gameInstance, player.ID) {
+		gs.sendClientError(conn, models.ERR_GAME_NOT_FOUND, "Game not found")
+		return
+	}
+
+	if gameInstance.Teams == nil {
+		gs.sendClientError(conn, models.ERR_NOT_ALLOWED, "Moves are only proposed in team games")
+		return
+	}
+
+	if gameInstance.Status != models.STATUS_PLAYING {
+		gs.sendClientError(conn, models.ERR_NOT_PLAYING, "Game is not in playing state")
+		return
+	}
+
+	symbol := gameInstance.SideOf(player.ID)
+	if gameInstance.CurrentTurn != symbol {
+		gs.sendClientError(conn, models.ERR_NOT_YOUR_TURN, "Moves are only proposed on your team's turn")
+		return
+	}
+
+	position := *move.Position
+	if position >= len(gameInstance.Board) {
+		gs.sendClientError(conn, models.ERR_INVALID_POSITION, "Invalid position")
+		return
+	}
+	if gameInstance.Board[position] != "" {
+		gs.sendClientError(conn, models.ERR_POSITION_OCCUPIED, "Position already occupied")
+		return
+	}
+
+	turn := gs.teamTurns[gameInstance.ID]
+	if turn == nil {
+		turn = gs.startTeamTurn(gameInstance, symbol, player.ID)
+	}
+	turn.proposals[player.ID] = position
+
+	if teamAgrees(gameInstance.SidePlayers(symbol), turn.proposals, position) {
+		gs.playTeamMove(ctx, gameInstance, turn, position)
+		return
+	}
+
+	msg := models.NewGameMessageForGame(models.MSG_MOVE_PROPOSED, gameInstance.ID, models.MoveProposal{
+		GameID:    gameInstance.ID,
+		PlayerID:  player.ID,
+		Position:  position,
+		Proposals: turn.proposals,
+		Deadline:  turn.deadline,
+	})
+	for _, teammate := range gameInstance.SidePlayers(symbol) {
+		if gs.isConnected(teammate.ID) {
+			gs.sendToPlayer(teammate.ID, msg)
+		}
+	}
+}
+
+// startTeamTurn opens proposals for a team's move and starts its deadline
+func (gs *GameServer) startTeamTurn(gameInstance *models.Game, symbol models.Symbol, firstID string) *teamTurn {
+	turn := &teamTurn{
+		symbol:    symbol,
+		proposals: make(map[string]int),
+		first:     firstID,
+		deadline:  gs.clock.Now().Add(gs.config.TeamMoveTimeout),
+	}
+	turn.timer = gs.clock.AfterFunc(gs.config.TeamMoveTimeout, gs.doLater(func() {
+		if gs.teamTurns[gameInstance.ID] != turn {
+			return
+		}
+		if gameInstance.Status != models.STATUS_PLAYING || gameInstance.CurrentTurn != turn.symbol {
+			// Paused or ended while the team was deciding; they decide afresh if play resumes
+			gs.forgetTeamTurn(gameInstance)
+			return
+		}
+		log.Printf("Team %s in game %s ran out of time, playing the first proposal", turn.symbol, gameInstance.ID)
+		gs.playTeamMove(context.Background(), gameInstance, turn, turn.proposals[turn.first])
+	}))
+	gs.teamTurns[gameInstance.ID] = turn
+	return turn
+}
+
+// teamAgrees reports whether every member of a team has proposed the same position
+func teamAgrees(team []*models.Player, proposals map[string]int, position int) bool {
+	for _, player := range team {
+		if proposed, exists := proposals[player.ID]; !exists || proposed != position {
+			return false
+		}
+	}
+	return true
+}
+
+// playTeamMove plays a team's chosen move through its seated player
+func (gs *GameServer) playTeamMove(ctx context.Context, gameInstance *models.Game, turn *teamTurn, position int) {
+	gs.forgetTeamTurn(gameInstance)
+
+	seated := gameInstance.SidePlayers(turn.symbol)[0]
+	if err := gs.gameEngine.MakeMove(gameInstance, seated.ID, position); err != nil {
+		log.Printf("Failed to play team move in game %s: %v", gameInstance.ID, err)
+		return
+	}
+	gs.afterMove(ctx, gameInstance)
+}
+
+// forgetTeamTurn drops the proposals of a team game, if any
+func (gs *GameServer) forgetTeamTurn(gameInstance *models.Game) {
+	if turn, exists := gs.teamTurns[gameInstance.ID]; exists {
+		turn.timer.Stop()
+		delete(gs.teamTurns, gameInstance.ID)
+	}
+}
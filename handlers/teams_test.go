@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// startTeamGame queues four players for a team game and returns them by side
+func startTeamGame(t *testing.T, wsURL string) (xTeam, oTeam []*testClient, gameID string) {
+	t.Helper()
+
+	var clients []*testClient
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		c := dialTestClient(t, wsURL, "name="+name)
+		c.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_TEAM})
+		c.expect(models.MSG_QUEUE_JOINED, nil)
+		clients = append(clients, c)
+	}
+
+	for _, c := range clients {
+		var state testGameState
+		c.expect(models.MSG_GAME_FOUND, &state)
+		if gameID == "" {
+			gameID = state.GameID
+		} else if state.GameID != gameID {
+			t.Fatalf("players in different games: %s vs %s", state.GameID, gameID)
+		}
+		if state.MySymbol == "X" {
+			xTeam = append(xTeam, c)
+		} else {
+			oTeam = append(oTeam, c)
+		}
+	}
+	if len(xTeam) != models.TEAM_SIZE || len(oTeam) != models.TEAM_SIZE {
+		t.Fatalf("teams of %d and %d", len(xTeam), len(oTeam))
+	}
+	return xTeam, oTeam, gameID
+}
+
+func TestTeamMovePlayedWhenTeammatesAgree(t *testing.T) {
+	_, _, wsURL := newTestServer(t, testConfig())
+	xTeam, oTeam, gameID := startTeamGame(t, wsURL)
+
+	position := func(p int) *int { return &p }
+	xTeam[0].send(models.MSG_PROPOSE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(4)})
+	var proposal models.MoveProposal
+	for _, c := range xTeam {
+		c.expect(models.MSG_MOVE_PROPOSED, &proposal)
+		if proposal.PlayerID != xTeam[0].playerID || proposal.Position != 4 || len(proposal.Proposals) != 1 {
+			t.Errorf("proposal = %+v", proposal)
+		}
+	}
+
+	// A disagreement waits for the team to settle
+	xTeam[1].send(models.MSG_PROPOSE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(0)})
+	xTeam[0].expect(models.MSG_MOVE_PROPOSED, &proposal)
+	if len(proposal.Proposals) != 2 {
+		t.Errorf("proposals after disagreeing = %+v", proposal.Proposals)
+	}
+
+	xTeam[1].send(models.MSG_PROPOSE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(4)})
+	for _, c := range append(xTeam, oTeam...) {
+		var state testGameState
+		c.expect(models.MSG_GAME_UPDATE, &state)
+		if state.Board[4] != "X" || state.IsMyTurn != (state.MySymbol == "O") {
+			t.Errorf("state after agreeing = %+v", state)
+		}
+	}
+
+	// Proposals belong to the side whose turn it is, and moves are never made directly
+	xTeam[0].send(models.MSG_PROPOSE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(0)})
+	var body struct {
+		Error string `json:"error"`
+	}
+	xTeam[0].expect(models.MSG_ERROR, &body)
+	if body.Error != "Moves are only proposed on your team's turn" {
+		t.Errorf("proposal off turn: %q", body.Error)
+	}
+	oTeam[0].send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(0)})
+	oTeam[0].expect(models.MSG_ERROR, &body)
+	if body.Error != "Team games take moves as propose_move" {
+		t.Errorf("direct move: %q", body.Error)
+	}
+}
+
+func TestTeamMoveTimesOutToFirstProposal(t *testing.T) {
+	cfg := testConfig()
+	cfg.TeamMoveTimeout = 10 * time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+	xTeam, oTeam, gameID := startTeamGame(t, wsURL)
+
+	position := func(p int) *int { return &p }
+	xTeam[1].send(models.MSG_PROPOSE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(8)})
+	xTeam[0].expect(models.MSG_MOVE_PROPOSED, nil)
+	xTeam[0].send(models.MSG_PROPOSE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(2)})
+	xTeam[1].expect(models.MSG_MOVE_PROPOSED, nil)
+
+	clk.Advance(cfg.TeamMoveTimeout)
+	var state testGameState
+	oTeam[0].expect(models.MSG_GAME_UPDATE, &state)
+	if state.Board[8] != "X" || state.Board[2] != "" {
+		t.Errorf("board after timeout = %v", state.Board)
+	}
+
+	var pending int
+	gs.do(func() { pending = len(gs.teamTurns) })
+	if pending != 0 {
+		t.Errorf("%d team turns left open", pending)
+	}
+}
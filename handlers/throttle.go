@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// throttleWindow is the period connection attempts are counted over
+const throttleWindow = time.Minute
+
+// ipThrottle tracks connections and connection attempts per client address
+type ipThrottle struct {
+	attempts    map[string][]time.Time // IP -> attempts within the last throttleWindow, oldest first
+	bannedUntil map[string]time.Time   // IP -> when its temporary ban lifts
+	connections map[string]int         // IP -> open connections
+}
+
+func newIPThrottle() ipThrottle {
+	return ipThrottle{
+		attempts:    make(map[string][]time.Time),
+		bannedUntil: make(map[string]time.Time),
+		connections: make(map[string]int),
+	}
+}
+
+// adminThrottledView is an address currently refused by the per-IP limits
+type adminThrottledView struct {
+	IP             string     `json:"ip"`
+	Reason         string     `json:"reason"` // "banned" or "connections"
+	BannedUntil    *time.Time `json:"bannedUntil,omitempty"`
+	Connections    int        `json:"connections"`
+	RecentAttempts int        `json:"recentAttempts"` // Attempts within the last minute
+}
+
+// throttleConnection records a connection attempt and returns how long the address must wait, or 0 to let it in
+// Flooding attempts earns a temporary ban; players resuming a game in progress may exceed the per-IP connection cap
+func (gs *GameServer) throttleConnection(clientIP, playerID, token string) time.Duration {
+	if clientIP == "" {
+		return 0
+	}
+	now := gs.clock.Now()
+	if until, banned := gs.throttle.bannedUntil[clientIP]; banned {
+		if now.Before(until) {
+			gs.capacity.ThrottledConnections++
+			return until.Sub(now)
+		}
+		delete(gs.throttle.bannedUntil, clientIP)
+	}
+
+	if limit := gs.config.ConnectAttemptsPerMinute; limit > 0 {
+		attempts := append(recentAttempts(gs.throttle.attempts[clientIP], now), now)
+		gs.throttle.attempts[clientIP] = attempts
+		if len(attempts) > limit {
+			gs.capacity.ThrottledConnections++
+			if gs.config.ThrottleBanDuration > 0 {
+				gs.throttle.bannedUntil[clientIP] = now.Add(gs.config.ThrottleBanDuration)
+				delete(gs.throttle.attempts, clientIP)
+				log.Printf("Banning %s for %v after %d connection attempts in a minute", clientIP, gs.config.ThrottleBanDuration, len(attempts))
+				return gs.config.ThrottleBanDuration
+			}
+			return attempts[0].Add(throttleWindow).Sub(now)
+		}
+	}
+
+	if limit := gs.config.MaxConnectionsPerIP; limit > 0 && gs.throttle.connections[clientIP] >= limit && !gs.resumingGame(playerID, token) {
+		gs.capacity.ThrottledConnections++
+		log.Printf("Refusing connection from %s: %d/%d connections open", clientIP, gs.throttle.connections[clientIP], limit)
+		return retryAfterSeconds * time.Second
+	}
+	return 0
+}
+
+// recentAttempts drops attempts that fell out of the window
+func recentAttempts(attempts []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-throttleWindow)
+	for len(attempts) > 0 && !attempts[0].After(cutoff) {
+		attempts = attempts[1:]
+	}
+	return attempts
+}
+
+// pruneThrottle forgets expired bans and attempts so idle addresses don't accumulate
+func (gs *GameServer) pruneThrottle() {
+	now := gs.clock.Now()
+	for ip, until := range gs.throttle.bannedUntil {
+		if !now.Before(until) {
+			delete(gs.throttle.bannedUntil, ip)
+		}
+	}
+	for ip, attempts := range gs.throttle.attempts {
+		if attempts = recentAttempts(attempts, now); len(attempts) == 0 {
+			delete(gs.throttle.attempts, ip)
+		} else {
+			gs.throttle.attempts[ip] = attempts
+		}
+	}
+}
+
+// refuseThrottled rejects an HTTP upgrade from an address over its limits
+func refuseThrottled(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	http.Error(w, "too many connections", http.StatusTooManyRequests)
+}
+
+// throttledIPs lists addresses that are temporarily banned or at the per-IP connection cap
+func (gs *GameServer) throttledIPs() []adminThrottledView {
+	now := gs.clock.Now()
+	views := make([]adminThrottledView, 0)
+	view := func(ip, reason string) adminThrottledView {
+		return adminThrottledView{
+			IP:             ip,
+			Reason:         reason,
+			Connections:    gs.throttle.connections[ip],
+			RecentAttempts: len(recentAttempts(gs.throttle.attempts[ip], now)),
+		}
+	}
+
+	for ip, until := range gs.throttle.bannedUntil {
+		if now.Before(until) {
+			v := view(ip, "banned")
+			v.BannedUntil = &until
+			views = append(views, v)
+		}
+	}
+	if limit := gs.config.MaxConnectionsPerIP; limit > 0 {
+		for ip, open := range gs.throttle.connections {
+			if _, banned := gs.throttle.bannedUntil[ip]; open >= limit && !banned {
+				views = append(views, view(ip, "connections"))
+			}
+		}
+	}
+
+	sort.Slice(views, func(i, j int) bool { return views[i].IP < views[j].IP })
+	return views
+}
+
+// handleAdminThrottled serves GET /admin/throttled and DELETE /admin/throttled/{ip}, which lifts a temporary ban
+func (gs *GameServer) handleAdminThrottled(w http.ResponseWriter, r *http.Request) {
+	ip, _ := splitAdminPath(r.URL.Path, "/admin/throttled")
+
+	switch {
+	case r.Method == http.MethodGet && ip == "":
+		var views []adminThrottledView
+		gs.do(func() { views = gs.throttledIPs() })
+		writeJSON(w, http.StatusOK, views)
+
+	case r.Method == http.MethodDelete && ip != "":
+		var lifted bool
+		gs.do(func() {
+			_, lifted = gs.throttle.bannedUntil[ip]
+			delete(gs.throttle.bannedUntil, ip)
+			delete(gs.throttle.attempts, ip)
+		})
+		if !lifted {
+			http.Error(w, "address not banned", http.StatusNotFound)
+			return
+		}
+		log.Printf("Admin lifted the temporary ban on %s", ip)
+		gs.auditAdmin(r, models.ADMIN_LIFT_THROTTLE, ip, nil)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "unbanned", "ip": ip})
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestPerIPConnectionLimitRefusesUpgrade(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxConnectionsPerIP = 1
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	dialTestClient(t, wsURL, "name=alice")
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL+"?v=2&name=bob", nil)
+	if err == nil {
+		t.Fatal("second connection from one address was accepted")
+	}
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("response = %+v, want 429 with Retry-After", resp)
+	}
+
+	var throttled []adminThrottledView
+	var u utilization
+	gs.do(func() {
+		throttled = gs.throttledIPs()
+		u = gs.utilization()
+	})
+	if len(throttled) != 1 || throttled[0].Reason != "connections" || throttled[0].Connections != 1 {
+		t.Errorf("throttled = %+v", throttled)
+	}
+	if u.ThrottledConnections != 1 {
+		t.Errorf("throttled connections = %d, want 1", u.ThrottledConnections)
+	}
+}
+
+func TestConnectionFloodBansAddress(t *testing.T) {
+	cfg := testConfig()
+	cfg.ConnectAttemptsPerMinute = 2
+	cfg.ThrottleBanDuration = 10 * time.Minute
+	gs, clk, _ := newTestServer(t, cfg)
+
+	attempt := func(ip string) (wait time.Duration) {
+		gs.do(func() { _, wait = gs.screenConnection(ip, "", "") })
+		return wait
+	}
+
+	for i := 0; i < 2; i++ {
+		if wait := attempt("203.0.113.7"); wait != 0 {
+			t.Fatalf("attempt %d throttled for %v", i+1, wait)
+		}
+	}
+	if wait := attempt("203.0.113.7"); wait != cfg.ThrottleBanDuration {
+		t.Fatalf("third attempt wait = %v, want the ban of %v", wait, cfg.ThrottleBanDuration)
+	}
+	if wait := attempt("198.51.100.1"); wait != 0 {
+		t.Errorf("another address throttled for %v", wait)
+	}
+
+	// The ban outlasts the attempt window
+	clk.Advance(2 * time.Minute)
+	if wait := attempt("203.0.113.7"); wait != 8*time.Minute {
+		t.Errorf("wait during ban = %v, want 8m", wait)
+	}
+
+	recorder := httptest.NewRecorder()
+	gs.handleAdminThrottled(recorder, httptest.NewRequest(http.MethodGet, "/admin/throttled", nil))
+	var listed []adminThrottledView
+	if err := json.Unmarshal(recorder.Body.Bytes(), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].IP != "203.0.113.7" || listed[0].Reason != "banned" || listed[0].BannedUntil == nil {
+		t.Fatalf("throttled = %+v", listed)
+	}
+
+	recorder = httptest.NewRecorder()
+	gs.handleAdminThrottled(recorder, httptest.NewRequest(http.MethodDelete, "/admin/throttled/203.0.113.7", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unban = %d", recorder.Code)
+	}
+	if wait := attempt("203.0.113.7"); wait != 0 {
+		t.Errorf("attempt after unban throttled for %v", wait)
+	}
+}
+
+func TestAttemptWindowSlides(t *testing.T) {
+	cfg := testConfig()
+	cfg.ConnectAttemptsPerMinute = 1
+	gs, clk, _ := newTestServer(t, cfg)
+
+	attempt := func() (wait time.Duration) {
+		gs.do(func() { wait = gs.throttleConnection("203.0.113.7", "", "") })
+		return wait
+	}
+
+	attempt()
+	clk.Advance(40 * time.Second)
+	// Without a ban the excess attempt waits until the oldest one leaves the window
+	if wait := attempt(); wait != 20*time.Second {
+		t.Errorf("wait = %v, want 20s", wait)
+	}
+	clk.Advance(time.Minute)
+	gs.do(gs.pruneThrottle)
+	if wait := attempt(); wait != 0 {
+		t.Errorf("attempt after the window throttled for %v", wait)
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"tictactoe-server/models"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracer records spans for the message pipeline; it is a no-op unless tracing.Setup installed a provider
+var tracer = otel.Tracer("tictactoe-server/handlers")
+
+// Span attribute keys
+const (
+	ATTR_MESSAGE_TYPE  = attribute.Key("ttt.message.type")
+	ATTR_PLAYER_ID     = attribute.Key("ttt.player.id")
+	ATTR_GAME_ID       = attribute.Key("ttt.game.id")
+	ATTR_GAME_STATUS   = attribute.Key("ttt.game.status")
+	ATTR_MOVE_POSITION = attribute.Key("ttt.move.position")
+	ATTR_TRANSPORT     = attribute.Key("ttt.transport")
+)
+
+// gameAttributes describes a game for span attributes
+func gameAttributes(gameInstance *models.Game) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		ATTR_GAME_ID.String(gameInstance.ID),
+		ATTR_GAME_STATUS.String(gameInstance.Status),
+	}
+}
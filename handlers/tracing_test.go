@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testSpans records spans for the package; the global provider can only be delegated to once per process
+var (
+	testSpans       = tracetest.NewSpanRecorder()
+	installProvider sync.Once
+)
+
+func TestMoveProducesSpanPipeline(t *testing.T) {
+	installProvider.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(testSpans)))
+	})
+
+	_, _, wsURL := newTestServer(t, testConfig())
+	alice := dialTestClient(t, wsURL, "name=alice")
+	bob := dialTestClient(t, wsURL, "name=bob")
+	alice.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+	bob.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_CASUAL})
+
+	var state testGameState
+	alice.expect(models.MSG_GAME_FOUND, &state)
+	mover := alice
+	if !state.IsMyTurn {
+		mover = bob
+	}
+	position := 4
+	mover.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: state.GameID, Position: &position})
+	mover.expect(models.MSG_GAME_UPDATE, nil)
+
+	// The read span ends after the update is queued, so wait for the whole chain
+	var read, handle, move, update sdktrace.ReadOnlySpan
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		spans := testSpans.Ended()
+		if move = spanNamed(spans, "engine.MakeMove", ATTR_GAME_ID.String(state.GameID)); move != nil {
+			handle = spanWithID(spans, move.Parent().SpanID())
+			update = spanNamed(spans, "sendGameUpdate", ATTR_GAME_ID.String(state.GameID))
+		}
+		if handle != nil {
+			read = spanWithID(spans, handle.Parent().SpanID())
+		}
+		if read != nil && update != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("move spans not recorded; have %d spans", len(spans))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if handle.Name() != "handleMessage" || !hasAttribute(handle, ATTR_MESSAGE_TYPE.String(models.MSG_MAKE_MOVE)) {
+		t.Errorf("engine.MakeMove parent = %s %v, want make_move handleMessage", handle.Name(), handle.Attributes())
+	}
+	if read.Name() != "websocket.read" || read.Parent().IsValid() {
+		t.Errorf("handleMessage parent = %s, want root websocket.read", read.Name())
+	}
+	if update.Parent().SpanID() != handle.SpanContext().SpanID() {
+		t.Error("sendGameUpdate is not a child of handleMessage")
+	}
+	if !hasAttribute(move, ATTR_MOVE_POSITION.Int(position)) || !hasAttribute(move, ATTR_PLAYER_ID.String(mover.playerID)) {
+		t.Errorf("engine.MakeMove attributes = %v", move.Attributes())
+	}
+}
+
+// spanWithID returns the span with the given ID
+func spanWithID(spans []sdktrace.ReadOnlySpan, id trace.SpanID) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.SpanContext().SpanID() == id {
+			return span
+		}
+	}
+	return nil
+}
+
+// spanNamed returns the first span with the name and all the given attributes
+func spanNamed(spans []sdktrace.ReadOnlySpan, name string, attrs ...attribute.KeyValue) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.Name() != name {
+			continue
+		}
+		matches := true
+		for _, attr := range attrs {
+			matches = matches && hasAttribute(span, attr)
+		}
+		if matches {
+			return span
+		}
+	}
+	return nil
+}
+
+// hasAttribute reports whether the span carries the attribute
+func hasAttribute(span sdktrace.ReadOnlySpan, want attribute.KeyValue) bool {
+	for _, attr := range span.Attributes() {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
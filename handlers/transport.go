@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"tictactoe-server/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// clientConn is a connected client on any transport (WebSocket or gRPC)
+type clientConn interface {
+	// WriteMessage delivers a message, already shaped for the client's protocol version
+	WriteMessage(msg *models.GameMessage) error
+	// CloseWithReason tells the transport why the connection is ending, then closes it
+	CloseWithReason(code int, reason string)
+	// Close closes the connection without a reason
+	Close() error
+}
+
+const (
+	sendQueueSize     = 64               // Outbound frames buffered per connection before droppable ones are discarded
+	criticalQueueSize = 256              // Outbound frames buffered per connection before it counts as stalled
+	writeTimeout      = 10 * time.Second // Longest a single frame write may take
+)
+
+var (
+	errClientClosed  = errors.New("client connection closed")
+	errSendQueueFull = errors.New("send queue full")
+)
+
+// droppedMessageError reports that a full send queue discarded a droppable message, the new one or an older one
+type droppedMessageError struct {
+	msgType string
+}
+
+func (e *droppedMessageError) Error() string {
+	return e.msgType + " message dropped from full send queue"
+}
+
+// sendQueue is a connection's outbound queue, drained by a single writer
+// Once it holds sendQueueSize items, a droppable item makes room by evicting the oldest droppable item
+// waiting, or is dropped itself when there is none. Critical items are never dropped: they queue past
+// sendQueueSize until criticalQueueSize, where the connection counts as stalled and has to be closed.
+type sendQueue[T any] struct {
+	mu    sync.Mutex
+	items []queuedItem[T]
+	ready chan struct{} // Holds a signal while items may be waiting
+}
+
+// queuedItem is an item waiting in a sendQueue
+type queuedItem[T any] struct {
+	item      T
+	droppable string // Message type of a droppable item, "" for critical ones
+}
+
+func newSendQueue[T any]() *sendQueue[T] {
+	return &sendQueue[T]{
+		items: make([]queuedItem[T], 0, sendQueueSize),
+		ready: make(chan struct{}, 1),
+	}
+}
+
+// push queues an item without blocking; droppable is its message type if it may be dropped, "" if not
+// Returns a *droppedMessageError naming what was dropped to make room, or errSendQueueFull if a critical item didn't fit
+func (q *sendQueue[T]) push(item T, droppable string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var err error
+	switch {
+	case len(q.items) < sendQueueSize:
+	case droppable != "":
+		evicted := q.evictOldestDroppable()
+		if evicted == "" {
+			return &droppedMessageError{msgType: droppable}
+		}
+		err = &droppedMessageError{msgType: evicted}
+	case len(q.items) >= criticalQueueSize:
+		return errSendQueueFull
+	}
+
+	q.items = append(q.items, queuedItem[T]{item: item, droppable: droppable})
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return err
+}
+
+// evictOldestDroppable removes the oldest droppable item and returns its message type, or "" if every item is critical
+func (q *sendQueue[T]) evictOldestDroppable() string {
+	for i, queued := range q.items {
+		if queued.droppable != "" {
+			q.items = append(q.items[:i], q.items[i+1:]...)
+			return queued.droppable
+		}
+	}
+	return ""
+}
+
+// pop takes the oldest item, reporting false when the queue is empty
+func (q *sendQueue[T]) pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var item T
+	if len(q.items) == 0 {
+		return item, false
+	}
+	item = q.items[0].item
+	q.items[0] = queuedItem[T]{}
+	q.items = q.items[1:]
+	return item, true
+}
+
+// outboundFrame is a frame waiting in a connection's send queue
+type outboundFrame struct {
+	frameType int
+	data      []byte
+}
+
+// wsClient is a WebSocket connection and the codec negotiated for it
+// All writes go through a buffered queue drained by a single write pump, since gorilla/websocket
+// allows only one concurrent writer and a slow client must never stall the caller.
+type wsClient struct {
+	conn        *websocket.Conn
+	codec       models.Codec
+	send        *sendQueue[outboundFrame]
+	compression *wsCompression // nil unless the connection negotiated permessage-deflate
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// newWSClient wraps a connection and starts its write pump
+func newWSClient(conn *websocket.Conn, codec models.Codec, compression *wsCompression) *wsClient {
+	c := &wsClient{
+		conn:        conn,
+		codec:       codec,
+		send:        newSendQueue[outboundFrame](),
+		compression: compression,
+		closed:      make(chan struct{}),
+	}
+	go c.writePump()
+	return c
+}
+
+// WriteMessage encodes the message with the client's codec and queues it as one frame
+func (c *wsClient) WriteMessage(msg *models.GameMessage) error {
+	data, err := c.codec.Encode(msg)
+	if err != nil {
+		return err
+	}
+
+	frameType := websocket.TextMessage
+	if c.codec.Binary() {
+		frameType = websocket.BinaryMessage
+	}
+	return c.enqueue(outboundFrame{frameType: frameType, data: data}, droppableType(msg))
+}
+
+// CloseWithReason queues a close frame with the code and reason behind any pending messages
+func (c *wsClient) CloseWithReason(code int, reason string) {
+	frame := outboundFrame{frameType: websocket.CloseMessage, data: websocket.FormatCloseMessage(code, reason)}
+	if err := c.enqueue(frame, ""); err != nil {
+		c.Close()
+	}
+}
+
+// Ping queues a ping; the write pump stamps it with the send time, which the pong echoes back
+func (c *wsClient) Ping() error {
+	return c.enqueue(outboundFrame{frameType: websocket.PingMessage}, "")
+}
+
+// Close closes the socket immediately, dropping anything still queued
+func (c *wsClient) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return c.conn.Close()
+}
+
+// enqueue hands a frame to the write pump without blocking; droppable is as for sendQueue.push
+func (c *wsClient) enqueue(frame outboundFrame, droppable string) error {
+	select {
+	case <-c.closed:
+		return errClientClosed
+	default:
+	}
+	return c.send.push(frame, droppable)
+}
+
+// writePump writes queued frames in order until the connection closes
+func (c *wsClient) writePump() {
+	for {
+		select {
+		case <-c.send.ready:
+		case <-c.closed:
+			return
+		}
+
+		for {
+			frame, ok := c.send.pop()
+			if !ok {
+				break
+			}
+			deadline := time.Now().Add(writeTimeout)
+			if frame.frameType == websocket.CloseMessage {
+				c.conn.WriteControl(websocket.CloseMessage, frame.data, time.Now().Add(closeWriteTimeout))
+				c.Close()
+				return
+			}
+			if frame.frameType == websocket.PingMessage {
+				stamp := strconv.FormatInt(time.Now().UnixNano(), 10)
+				if err := c.conn.WriteControl(websocket.PingMessage, []byte(stamp), deadline); err != nil {
+					log.Printf("WebSocket ping error: %v", err)
+					c.Close()
+					return
+				}
+				continue
+			}
+
+			c.conn.SetWriteDeadline(deadline)
+			if c.compression != nil {
+				c.compression.prepare(c.conn, len(frame.data))
+			}
+			if err := c.conn.WriteMessage(frame.frameType, frame.data); err != nil {
+				log.Printf("WebSocket write error: %v", err)
+				c.Close()
+				return
+			}
+			if c.compression != nil {
+				c.compression.record(len(frame.data))
+			}
+		}
+	}
+}
+
+// droppableType returns a message's type if a slow client may miss it, or "" if it must be delivered
+// Numbered messages are never dropped, since a gap in seq would send the client back for a resync
+func droppableType(msg *models.GameMessage) string {
+	if msg.Seq == 0 && models.IsDroppable(msg.Type) {
+		return msg.Type
+	}
+	return ""
+}
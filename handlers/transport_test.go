@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tictactoe-server/models"
+)
+
+func TestSendQueueEvictsOldestDroppable(t *testing.T) {
+	q := newSendQueue[string]()
+	q.push("leaderboard-1", models.MSG_LEADERBOARD)
+	q.push("lobby-1", models.MSG_LOBBY_STATS)
+	for len(q.items) < sendQueueSize {
+		q.push("update", "")
+	}
+
+	var dropped *droppedMessageError
+	if err := q.push("leaderboard-2", models.MSG_LEADERBOARD); !errors.As(err, &dropped) || dropped.msgType != models.MSG_LEADERBOARD {
+		t.Fatalf("push into a full queue = %v, want the oldest leaderboard dropped", err)
+	}
+	if first, _ := q.pop(); first != "lobby-1" {
+		t.Fatalf("oldest remaining item = %q, want lobby-1", first)
+	}
+	if last := q.items[len(q.items)-1].item; last != "leaderboard-2" {
+		t.Errorf("newest item = %q, want leaderboard-2", last)
+	}
+}
+
+func TestSendQueueKeepsCriticalItemsUntilStalled(t *testing.T) {
+	q := newSendQueue[int]()
+	for i := 0; i < sendQueueSize; i++ {
+		q.push(i, "")
+	}
+
+	// With nothing droppable queued, a droppable item is the one dropped
+	var dropped *droppedMessageError
+	if err := q.push(-1, models.MSG_LOBBY_STATS); !errors.As(err, &dropped) || dropped.msgType != models.MSG_LOBBY_STATS {
+		t.Fatalf("droppable push = %v, want it dropped", err)
+	}
+
+	for i := sendQueueSize; i < criticalQueueSize; i++ {
+		if err := q.push(i, ""); err != nil {
+			t.Fatalf("critical push %d = %v", i, err)
+		}
+	}
+	if err := q.push(criticalQueueSize, ""); err != errSendQueueFull {
+		t.Fatalf("critical push past the limit = %v, want errSendQueueFull", err)
+	}
+	for want := 0; want < criticalQueueSize; want++ {
+		if got, ok := q.pop(); !ok || got != want {
+			t.Fatalf("pop = %d, %v; want %d in order", got, ok, want)
+		}
+	}
+}
+
+// stalledConn is a clientConn whose queue is never drained, like a client that stopped reading
+type stalledConn struct {
+	queue  *sendQueue[*models.GameMessage]
+	closed bool
+}
+
+func (c *stalledConn) WriteMessage(msg *models.GameMessage) error {
+	return c.queue.push(msg, droppableType(msg))
+}
+
+func (c *stalledConn) CloseWithReason(code int, reason string) { c.closed = true }
+
+func (c *stalledConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSlowClientMissesLeaderboardBeforeBeingDisconnected(t *testing.T) {
+	gs, _, _ := newTestServer(t, testConfig())
+	conn := &stalledConn{queue: newSendQueue[*models.GameMessage]()}
+	update := models.NewGameMessage(models.MSG_GAME_UPDATE, nil)
+	leaderboard := models.NewGameMessage(models.MSG_LEADERBOARD, nil)
+
+	gs.do(func() {
+		gs.clients[conn] = models.NewPlayer("slow")
+		gs.clientVersions[conn] = models.PROTOCOL_VERSION_CURRENT
+		gs.sendToClient(conn, leaderboard)
+		for i := 1; i < sendQueueSize; i++ {
+			gs.sendToClient(conn, update)
+		}
+		gs.sendToAll(leaderboard)
+		gs.sendToAll(leaderboard)
+	})
+	if conn.closed {
+		t.Fatal("client disconnected over droppable messages")
+	}
+
+	gs.do(func() {
+		for i := sendQueueSize; i <= criticalQueueSize; i++ {
+			gs.sendToClient(conn, update)
+		}
+	})
+	if !conn.closed {
+		t.Fatal("client not disconnected once game updates backed up")
+	}
+
+	recorder := httptest.NewRecorder()
+	gs.HandleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	for _, want := range []string{
+		`ttt_dropped_messages_total{type="leaderboard"} 2` + "\n",
+		"ttt_stalled_clients_total 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"log"
+
+	"tictactoe-server/models"
+)
+
+// createTrioMatch starts a trio game between the three longest-waiting players in the trio queue
+func (gs *GameServer) createTrioMatch() {
+	if gs.newGamesPaused() {
+		return
+	}
+
+	queue := gs.matchmaking.Queue(models.MODE_TRIO)
+	size := matchSize(models.MODE_TRIO)
+	if len(queue) < size {
+		log.Printf("Not enough players in trio queue: %d", len(queue))
+		return
+	}
+
+	if gs.atGameLimit() {
+		gs.capacity.DeferredMatches++
+		log.Printf("Active game limit of %d reached, trio queue of %d waits", gs.config.MaxActiveGames, len(queue))
+		return
+	}
+
+	var players []*models.Player
+	for _, playerID := range queue[:size] {
+		gs.matchmaking.Dequeue(playerID)
+		if player, exists := gs.players.Player(playerID); exists {
+			players = append(players, player)
+		}
+	}
+
+	if len(players) < size {
+		log.Printf("Trio match needs %d players but only %d were found", size, len(players))
+		return
+	}
+
+	gs.proposeMatch(models.MODE_TRIO, players, func() { gs.startTrioMatch(players) })
+}
+
+// startTrioMatch creates a trio game on a 5x5 board and tells every player in it
+// Play rotates X, O, Δ; a player who forfeits is eliminated and the other two play on
+func (gs *GameServer) startTrioMatch(players []*models.Player) *models.Game {
+	newGame := models.NewTrioGame(gs.config.TrioWinLength)
+	newGame.StartTime = gs.clock.Now()
+	gs.gameEngine.SeatPlayers(newGame, players...)
+	gs.addGame(newGame)
+
+	log.Printf("Created trio game %s between %s (X), %s (O) and %s (Δ), %d in a row wins",
+		newGame.ID, newGame.Players[0].Name, newGame.Players[1].Name, newGame.Players[2].Name, newGame.WinLength)
+	gs.logGameCreated(newGame)
+
+	for _, player := range newGame.Players {
+		gs.sendToPlayer(player.ID, models.NewGameMessageForGame(models.MSG_GAME_FOUND, newGame.ID,
+			gs.gameEngine.GetGameStateForPlayer(newGame, player.ID)))
+	}
+	return newGame
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// trioState is the part of a trio game state the tests check
+type trioState struct {
+	GameID      string   `json:"gameId"`
+	Board       []string `json:"board"`
+	Size        int      `json:"size"`
+	WinLength   int      `json:"winLength"`
+	CurrentTurn string   `json:"currentTurn"`
+	Status      string   `json:"status"`
+	Winner      string   `json:"winner"`
+	MySymbol    string   `json:"mySymbol"`
+	Seats       []struct {
+		Symbol     string `json:"symbol"`
+		Eliminated bool   `json:"eliminated"`
+	} `json:"seats"`
+}
+
+// startTrioGame queues three players for a trio game and returns them in turn order
+func startTrioGame(t *testing.T, wsURL string) ([]*testClient, string) {
+	t.Helper()
+
+	var clients []*testClient
+	for _, name := range []string{"alice", "bob", "carol"} {
+		c := dialTestClient(t, wsURL, "name="+name)
+		c.send(models.MSG_JOIN_QUEUE, models.JoinQueuePayload{Mode: models.MODE_TRIO})
+		c.expect(models.MSG_QUEUE_JOINED, nil)
+		clients = append(clients, c)
+	}
+
+	seated := make([]*testClient, len(models.Symbols))
+	var gameID string
+	for _, c := range clients {
+		var state trioState
+		c.expect(models.MSG_GAME_FOUND, &state)
+		if state.Size != models.TRIO_BOARD_SIZE || len(state.Board) != 25 || len(state.Seats) != 3 {
+			t.Fatalf("trio game found: %+v", state)
+		}
+		gameID = state.GameID
+		for i, symbol := range models.Symbols {
+			if string(symbol) == state.MySymbol {
+				seated[i] = c
+			}
+		}
+	}
+	for i, c := range seated {
+		if c == nil {
+			t.Fatalf("nobody seated as %s", models.Symbols[i])
+		}
+	}
+	return seated, gameID
+}
+
+func TestTrioGameRotatesTurnsToFourInARow(t *testing.T) {
+	cfg := testConfig()
+	cfg.TrioWinLength = 4
+	_, _, wsURL := newTestServer(t, cfg)
+	players, gameID := startTrioGame(t, wsURL)
+
+	position := func(p int) *int { return &p }
+	moves := []int{0, 5, 10, 1, 6, 11, 2, 7, 12, 3}
+	var state trioState
+	for i, cell := range moves {
+		mover := players[i%3]
+		mover.send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(cell)})
+		for _, c := range players {
+			c.expect(models.MSG_GAME_UPDATE, &state)
+		}
+		if want := models.Symbols[i%3]; state.Board[cell] != string(want) {
+			t.Fatalf("cell %d = %q, want %q", cell, state.Board[cell], want)
+		}
+	}
+	if state.Status != models.STATUS_FINISHED || state.Winner != "X" {
+		t.Errorf("after X's fourth in a row: status=%s winner=%q", state.Status, state.Winner)
+	}
+}
+
+func TestTrioDisconnectEliminates(t *testing.T) {
+	cfg := testConfig()
+	cfg.TrioWinLength = 4
+	cfg.DisconnectGracePeriod = 30 * time.Second
+	gs, clk, wsURL := newTestServer(t, cfg)
+	players, gameID := startTrioGame(t, wsURL)
+
+	// Δ leaves and doesn't come back; X and O carry on without them
+	players[2].conn.Close()
+	waitForTimers(t, clk, 1)
+	clk.Advance(cfg.DisconnectGracePeriod)
+
+	var state trioState
+	for state.Status != models.STATUS_PLAYING || !state.Seats[2].Eliminated {
+		players[0].expect(models.MSG_GAME_UPDATE, &state)
+	}
+	if state.CurrentTurn != "X" {
+		t.Errorf("turn after elimination = %s, want X", state.CurrentTurn)
+	}
+
+	position := func(p int) *int { return &p }
+	players[0].send(models.MSG_MAKE_MOVE, models.MakeMovePayload{GameID: gameID, Position: position(12)})
+	state = trioState{}
+	for len(state.Board) == 0 || state.Board[12] != "X" {
+		players[1].expect(models.MSG_GAME_UPDATE, &state)
+	}
+	if state.CurrentTurn != "O" {
+		t.Errorf("turn after X = %s, want O with Δ skipped", state.CurrentTurn)
+	}
+
+	var winner models.Result
+	gs.do(func() { winner = testGame(gs, gameID).Winner })
+	if winner != "" {
+		t.Errorf("game decided after one elimination: %q", winner)
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"tictactoe-server/models"
+	"tictactoe-server/webhooks"
+)
+
+// webhookDrainTimeout bounds how long shutdown waits for queued webhook deliveries
+const webhookDrainTimeout = 5 * time.Second
+
+// webhookPlayer is a seated player as described to webhook subscribers
+type webhookPlayer struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Symbol models.Symbol `json:"symbol"`
+	Rating int           `json:"rating"`
+	IsBot  bool          `json:"isBot,omitempty"`
+}
+
+// webhookGame is the data of game_started and game_finished events
+type webhookGame struct {
+	GameID    string          `json:"gameId"`
+	Rated     bool            `json:"rated"`
+	Variant   string          `json:"variant"`
+	Size      int             `json:"size"`
+	Players   []webhookPlayer `json:"players"`
+	Winner    models.Result   `json:"winner,omitempty"` // Finished games only
+	Moves     int             `json:"moves"`
+	StartTime time.Time       `json:"startTime"`
+	EndTime   *time.Time      `json:"endTime,omitempty"`
+}
+
+// registerWebhooks posts game and player events to the configured subscribers
+func (gs *GameServer) registerWebhooks() {
+	if len(gs.config.WebhookURLs) == 0 {
+		return
+	}
+
+	gs.webhooks = webhooks.NewDispatcher(gs.config.WebhookURLs, gs.config.WebhookSecret,
+		gs.config.WebhookMaxAttempts, gs.config.WebhookBackoff)
+	gs.OnGameStarted(func(gameInstance *models.Game) {
+		gs.webhooks.Send(webhooks.EVENT_GAME_STARTED, newWebhookGame(gameInstance))
+	})
+	gs.OnGameFinished(func(gameInstance *models.Game) {
+		gs.webhooks.Send(webhooks.EVENT_GAME_FINISHED, newWebhookGame(gameInstance))
+	})
+	gs.OnPlayerRegistered(func(player *models.Player) {
+		gs.webhooks.Send(webhooks.EVENT_PLAYER_REGISTERED, map[string]string{"playerId": player.ID, "name": player.Name})
+	})
+}
+
+// newWebhookGame describes a game for webhook subscribers
+func newWebhookGame(gameInstance *models.Game) webhookGame {
+	event := webhookGame{
+		GameID:    gameInstance.ID,
+		Rated:     gameInstance.Rated,
+		Variant:   gameInstance.Variant,
+		Size:      gameInstance.Size,
+		Players:   make([]webhookPlayer, 0, len(gameInstance.Players)),
+		Winner:    gameInstance.Winner,
+		Moves:     len(gameInstance.Moves),
+		StartTime: gameInstance.StartTime,
+		EndTime:   gameInstance.EndTime,
+	}
+	pool := models.RATING_POOL_STANDARD
+	if gameInstance.Rated {
+		pool = gameInstance.RatingPool()
+	}
+	for i, player := range gameInstance.Players {
+		event.Players = append(event.Players, webhookPlayer{
+			ID:     player.ID,
+			Name:   player.Name,
+			Symbol: models.Symbols[i],
+			Rating: player.Rating(pool),
+			IsBot:  player.IsBot,
+		})
+	}
+	return event
+}
+
+// closeWebhooks delivers queued webhook events and stats reports before shutdown, giving up after webhookDrainTimeout
+func (gs *GameServer) closeWebhooks() {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookDrainTimeout)
+	defer cancel()
+	for _, dispatcher := range []*webhooks.Dispatcher{gs.webhooks, gs.statsReports} {
+		if dispatcher == nil {
+			continue
+		}
+		if err := dispatcher.Close(ctx); err != nil {
+			log.Printf("Webhook deliveries abandoned at shutdown: %v", err)
+		}
+	}
+}
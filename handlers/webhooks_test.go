@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"tictactoe-server/models"
+	"tictactoe-server/webhooks"
+)
+
+func TestWebhooksReportLifecycleEvents(t *testing.T) {
+	var mu sync.Mutex
+	var events []webhooks.Event
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhooks.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	}))
+	defer subscriber.Close()
+
+	cfg := testConfig()
+	cfg.WebhookURLs = []string{subscriber.URL}
+	cfg.WebhookMaxAttempts = 1
+	gs, _, wsURL := newTestServer(t, cfg)
+
+	x, o, gameID := startGame(t, wsURL, models.MODE_CASUAL)
+	for i, position := range []int{0, 3, 1, 4, 2} {
+		mover := x
+		if i%2 == 1 {
+			mover = o
+		}
+		playMove(t, mover, x, o, gameID, position)
+	}
+	x.expect(models.MSG_GAME_END, nil)
+	gs.Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	var types []string
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	want := []string{webhooks.EVENT_PLAYER_REGISTERED, webhooks.EVENT_PLAYER_REGISTERED, webhooks.EVENT_GAME_STARTED, webhooks.EVENT_GAME_FINISHED}
+	if len(types) != len(want) {
+		t.Fatalf("events %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("events %v, want %v", types, want)
+		}
+	}
+
+	var finished webhookGame
+	if err := json.Unmarshal(events[3].Data, &finished); err != nil {
+		t.Fatal(err)
+	}
+	if finished.GameID != gameID || finished.Winner != "X" || finished.Moves != 5 || len(finished.Players) != 2 || finished.Players[0].ID != x.playerID {
+		t.Errorf("game_finished data = %+v", finished)
+	}
+}
@@ -1,363 +1,948 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"log"
 	"net/http"
-	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"tictactoe-server/clock"
+	"tictactoe-server/config"
+	"tictactoe-server/discord"
 	"tictactoe-server/game"
 	"tictactoe-server/models"
+	"tictactoe-server/moderation"
+	"tictactoe-server/oauth"
+	"tictactoe-server/push"
+	"tictactoe-server/storage"
+	"tictactoe-server/webhooks"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// leaderboardBroadcastInterval batches leaderboard pushes so a burst of finished games sends one update
+const leaderboardBroadcastInterval = time.Second
+
 // GameServer manages all game sessions and players
+// Its state belongs to the hub goroutine; see hub.go
 type GameServer struct {
-	clients     map[*websocket.Conn]*models.Player
-	games       map[string]*models.Game
-	players     map[string]*models.Player
-	matchmaking []string // Queue of player IDs waiting for a match
+	clients     map[clientConn]*models.Player
+	games       storage.GameRepo
+	players     storage.PlayerRepo
+	matchmaking storage.QueueRepo         // Queues of player IDs waiting for a match, keyed by game mode
+	spectators  map[string]*spectatorRoom // Spectators keyed by game ID
 	gameEngine  *game.GameEngine
+	store       *storage.MemoryStore
 	upgrader    websocket.Upgrader
-	mutex       sync.RWMutex
+	hub         hubEvents
 	broadcast   chan *models.GameMessage
+	config      *config.Config
+	clock       clock.Clock
+	startedAt   time.Time
+
+	disconnectTimers   map[string]clock.Timer          // Grace-period timers keyed by game ID
+	flagTimers         map[string]clock.Timer          // Blitz game ID -> fires when the player to move runs out of time
+	recentOpponents    map[string]map[string]time.Time // Player ID -> opponent ID -> when they were last paired
+	abandonedSince     map[string]time.Time            // When the sweeper first saw each game with nobody connected
+	lifecycle          lifecycleStats
+	capacity           capacityStats
+	delivery           deliveryStats
+	compression        compressionStats          // WebSocket bytes before and after permessage-deflate, counted by the write pumps
+	throttle           ipThrottle                // Per-IP connection counts, recent attempts and temporary bans
+	fingerprints       map[string]fingerprint    // Player ID -> origin of their newest connection
+	fastMoveStreaks    map[string]int            // "gameID/playerID" -> consecutive fast replies
+	lastEmotes         map[string]time.Time      // "gameID/playerID" -> when the player last emoted
+	hintsUsed          map[string]int            // "gameID/playerID" -> hints given in that game
+	teamTurns          map[string]*teamTurn      // Team game ID -> proposals for the move being decided
+	invites            map[string]*invite        // Invite token -> invite waiting to be followed
+	matchProposals     map[string]*matchProposal // Match ID -> match waiting for its players to accept
+	moveAcks           map[string]ackedMoves     // Game ID -> moves played with a client move ID
+	lastActive         map[string]time.Time      // Player ID -> when they last connected or sent a message
+	lastPong           map[clientConn]time.Time  // WebSocket connection -> when it last answered a ping
+	lobby              lobbyState                // Today's game tally and the lobby statistics last pushed
+	sentStates         map[string]*sentState     // Game ID -> the last update sent to its viewers
+	spectatorFeeds     map[string]*spectatorFeed // Rated game ID -> spectator updates waiting out the delay
+	outboxes           map[string]*outbox        // Player ID -> numbered messages kept to replay on reconnect
+	replays            map[clientConn]*replay    // Connection -> finished game being played back to it
+	cheatFlags         []cheatFlag               // Recent anti-cheat flags, oldest first
+	season             models.Season             // The season being played
+	seasonTimer        clock.Timer               // Fires when the current season ends
+	statsTimer         clock.Timer               // Fires at the next UTC midnight to aggregate the day
+	heatmapTimer       clock.Timer               // Fires when the move heatmaps are next aggregated
+	hooks              serverHooks               // Callbacks registered by subsystems such as achievements
+	moderator          moderation.Moderator      // Screens player names and chat
+	webhooks           *webhooks.Dispatcher      // Posts events to subscribers; nil when none are configured
+	push               *push.Sender              // Sends Web Push notifications; nil without VAPID keys
+	statsReports       *webhooks.Dispatcher      // Posts stats summaries to the operator; nil when not configured
+	discord            *discord.Client           // Posts results and leaderboards to a Discord channel; nil when not configured
+	leaderboardChanged chan struct{}             // Signals a pending leaderboard broadcast
+
+	playerConns    map[string]map[clientConn]bool // Open connections keyed by player ID
+	clientIPs      map[clientConn]string
+	bannedPlayers  map[string]bool
+	bannedIPs      map[string]bool
+	mutedPlayers   map[string]bool    // Players whose chat an admin muted
+	shuttingDown   bool               // When true, new connections are refused
+	draining       bool               // When true, games in progress finish but new connections and games are refused
+	clientVersions map[clientConn]int // Negotiated protocol version of each connection
+
+	playerBanIPs map[string][]string // Player ID -> the addresses their ban put in bannedIPs, lifted with it
+
+	matchmakingBans map[string]time.Time     // Players verified reports banned from matchmaking, and until when
+	leavers         map[string]*leaverRecord // Players who recently left games, see leavers.go
+	turnReminders   map[string]*turnReminder // Game ID -> reminder pending for the turn being played
+	pushedTurns     map[string]string        // Game ID -> the turn the side to move was last pushed about
+	moveDeadlines   map[string]*moveDeadline // Correspondence game ID -> the deadline of the turn being played
+	lobbies         map[string]*lobby        // Lobby ID -> open lobby waiting for an opponent
+	challenges      map[string]*challenge    // Challenge ID -> challenge whose settings are being negotiated
+
+	staleNotices map[string]*staleNotice // Game ID -> the game_expiring notice sent for a game nobody is moving in
+
+	restoredQueue map[string]models.QueuedPlayer // Player ID -> queue place kept from before a restart until they reconnect
+
+	maintenance      *models.MaintenanceWindow // Scheduled or current maintenance window; nil when there is none
+	maintenanceTimer clock.Timer               // Fires when the maintenance window reaches its next phase
+
+	auditMutex sync.Mutex   // Orders writes to the admin audit log, which admin requests make outside the hub
+	adminKeys  adminKeyring // Admin API keys and their roles
+
+	oauthProviders map[string]*oauth.Provider // Providers players can log in with, by name
+	oauthLogins    oauthLogins                // Logins waiting for their provider's callback
+
+	// Copies of the origin lists for HTTP handlers, which can't read config while a reload changes it
+	allowedOrigins   atomic.Pointer[config.Origins]
+	webSocketOrigins atomic.Pointer[config.Origins]
+}
+
+// NewGameServer creates a new game server using the wall clock
+func NewGameServer(cfg *config.Config) *GameServer {
+	return NewGameServerWithClock(cfg, clock.New())
+}
+
+// queueModes are the game modes players queue for
+var queueModes = []string{models.MODE_RATED, models.MODE_CASUAL, models.MODE_TEAM, models.MODE_MISERE, models.MODE_TRIO, models.MODE_BLITZ, models.MODE_CORRESPONDENCE}
+
+// NewGameServerWithClock creates a game server whose timers, timeouts and timestamps follow clk
+func NewGameServerWithClock(cfg *config.Config, clk clock.Clock) *GameServer {
+	return NewGameServerWithRepos(cfg, clk, storage.NewMemoryRepos(queueModes...))
 }
 
-// NewGameServer creates a new game server
-func NewGameServer() *GameServer {
-	return &GameServer{
-		clients:     make(map[*websocket.Conn]*models.Player),
-		games:       make(map[string]*models.Game),
-		players:     make(map[string]*models.Player),
-		matchmaking: make([]string, 0),
-		gameEngine:  game.NewGameEngine(),
+// NewGameServerWithRepos creates a game server keeping its players, games and queues in repos
+// The repositories are only used from the hub goroutine
+func NewGameServerWithRepos(cfg *config.Config, clk clock.Clock, repos storage.Repos) *GameServer {
+	gs := &GameServer{
+		clients:     make(map[clientConn]*models.Player),
+		playerConns: make(map[string]map[clientConn]bool),
+		games:       repos.Games,
+		players:     repos.Players,
+		matchmaking: repos.Queues,
+		spectators:  make(map[string]*spectatorRoom),
+		hub:         newHubEvents(),
+
+		gameEngine: game.NewGameEngineWithClock(clk),
+		store:      storage.NewMemoryStore(),
 		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				// Allow all origins for development and production
-				// In production, you could restrict this to specific domains
-				return true
-			},
+			Subprotocols:      codecSubprotocols,
+			EnableCompression: cfg.WSCompression,
+			// Share write buffers between connections; most sit idle between moves
+			WriteBufferPool: &sync.Pool{},
 		},
-		broadcast: make(chan *models.GameMessage, 256),
+		broadcast:          make(chan *models.GameMessage, 256),
+		leaderboardChanged: make(chan struct{}, 1),
+		delivery:           deliveryStats{DroppedMessages: make(map[string]int)},
+		config:             cfg,
+		clock:              clk,
+		startedAt:          clk.Now(),
+		disconnectTimers:   make(map[string]clock.Timer),
+		flagTimers:         make(map[string]clock.Timer),
+		recentOpponents:    make(map[string]map[string]time.Time),
+		abandonedSince:     make(map[string]time.Time),
+		fingerprints:       make(map[string]fingerprint),
+		fastMoveStreaks:    make(map[string]int),
+		lastEmotes:         make(map[string]time.Time),
+		hintsUsed:          make(map[string]int),
+		teamTurns:          make(map[string]*teamTurn),
+		invites:            make(map[string]*invite),
+		matchProposals:     make(map[string]*matchProposal),
+		moveAcks:           make(map[string]ackedMoves),
+		lastActive:         make(map[string]time.Time),
+		lastPong:           make(map[clientConn]time.Time),
+		sentStates:         make(map[string]*sentState),
+		spectatorFeeds:     make(map[string]*spectatorFeed),
+		outboxes:           make(map[string]*outbox),
+		replays:            make(map[clientConn]*replay),
+		clientIPs:          make(map[clientConn]string),
+		throttle:           newIPThrottle(),
+		clientVersions:     make(map[clientConn]int),
+		bannedPlayers:      make(map[string]bool),
+		bannedIPs:          make(map[string]bool),
+		playerBanIPs:       make(map[string][]string),
+		mutedPlayers:       make(map[string]bool),
+		matchmakingBans:    make(map[string]time.Time),
+		leavers:            make(map[string]*leaverRecord),
+		turnReminders:      make(map[string]*turnReminder),
+		pushedTurns:        make(map[string]string),
+		moveDeadlines:      make(map[string]*moveDeadline),
+		lobbies:            make(map[string]*lobby),
+		challenges:         make(map[string]*challenge),
+		staleNotices:       make(map[string]*staleNotice),
+		restoredQueue:      make(map[string]models.QueuedPlayer),
+		moderator:          newModerator(cfg),
 	}
+
+	gs.upgrader.CheckOrigin = gs.checkWebSocketOrigin
+	gs.storeOrigins()
+	gs.loadAdminKeys()
+	gs.loadOAuthProviders()
+	gs.gameEngine.SetPlacement(cfg.PlacementGames, cfg.PlacementKFactor)
+	gs.gameEngine.SetProvisional(cfg.ProvisionalGames, cfg.ProvisionalKFactor)
+	gs.gameEngine.SetGlicko(cfg.RatingSystem == config.RATING_GLICKO2)
+	gs.season = gs.newSeason(1, clk.Now())
+	gs.registerAchievements()
+	gs.registerWebhooks()
+	gs.registerPush()
+	gs.registerStatsReports()
+	gs.registerDiscord()
+	gs.OnGameFinished(gs.countFinishedGame)
+	gs.gameEngine.OnTransition(gs.onGameTransition)
+	go gs.runHub()
+	return gs
 }
 
 // Run starts the game server
 func (gs *GameServer) Run() {
 	go gs.handleBroadcast()
+	go gs.runLeaderboardBroadcast()
+
+	go gs.runBotBackfill()
+
+	if gs.config.EventRetention > 0 {
+		go gs.runEventRetention()
+	}
+
+	go gs.runMatchRetry()
+
+	go gs.runGameSweeper()
+	go gs.runLatencyProbe()
+
+	if gs.config.LobbyStatsInterval > 0 {
+		go gs.runLobbyStats()
+	}
+
+	if gs.discord != nil && gs.config.DiscordLeaderboardInterval > 0 {
+		go gs.runDiscordLeaderboard()
+	}
+
+	if gs.config.SeasonLength > 0 {
+		gs.do(gs.scheduleSeasonEnd)
+	}
+
+	if gs.config.StateFile != "" {
+		gs.do(gs.restoreState)
+	}
+
+	if gs.config.AdminAuditFile != "" {
+		gs.loadAdminAudit()
+	}
+
+	gs.do(func() { gs.scheduleStatsAggregation(nextMidnight(gs.clock.Now())) })
+
+	if gs.config.HeatmapInterval > 0 {
+		gs.do(gs.aggregateHeatmaps)
+	}
 }
 
 // HandleWebSocket handles WebSocket connections
 func (gs *GameServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := gs.upgrader.Upgrade(w, r, nil)
+	query := r.URL.Query()
+	clientIP := gs.requestIP(r)
+	var admit int
+	var wait time.Duration
+	var maintenance *models.MaintenanceWindow
+	gs.do(func() {
+		admit, wait = gs.screenConnection(clientIP, query.Get("playerId"), query.Get("token"))
+		maintenance = gs.currentMaintenance()
+	})
+	switch admit {
+	case ADMIT_SHUTTING_DOWN:
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	case ADMIT_MAINTENANCE:
+		gs.refuseMaintenance(w, maintenance)
+		return
+	case ADMIT_BANNED:
+		http.Error(w, "banned", http.StatusForbidden)
+		return
+	case ADMIT_THROTTLED:
+		refuseThrottled(w, wait)
+		return
+	case ADMIT_FULL:
+		refuseFull(w)
+		return
+	}
+
+	wsConn, compression, err := gs.upgradeWebSocket(w, r)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
+
+	// Pick the wire encoding from the negotiated subprotocol or ?encoding=
+	codec := selectCodec(wsConn.Subprotocol(), query.Get("encoding"))
+	conn := newWSClient(wsConn, codec, compression)
 	defer conn.Close()
 
-	// Get player name from query parameter
-	playerName := r.URL.Query().Get("name")
-	if playerName == "" {
-		playerName = "Anonymous"
+	// Clients may negotiate a protocol version up front with ?v=, or later with a hello message
+	version := models.PROTOCOL_VERSION_LEGACY
+	if v, err := strconv.Atoi(query.Get("v")); err == nil {
+		if negotiated, err := models.NegotiateVersion(models.HelloPayload{Version: v}); err == nil {
+			version = negotiated
+		}
 	}
 
-	// Create or get existing player
-	player := models.NewPlayer(playerName)
-
-	gs.mutex.Lock()
-	gs.clients[conn] = player
-	gs.players[player.ID] = player
-	gs.mutex.Unlock()
-
-	log.Printf("New player connected: %s (ID: %s)", player.Name, player.ID)
-
-	// Send player info
-	gs.sendToClient(conn, &models.GameMessage{
-		Type: models.MSG_PLAYER_UPDATE,
-		Data: player,
-	})
-
-	// Send current leaderboard
-	gs.sendLeaderboard(conn)
+	player := gs.register(conn, clientIP, r.UserAgent(), query.Get("name"), query.Get("playerId"), query.Get("token"), query.Get("deviceKey"), query.Get("region"), version)
+	if player == nil {
+		awaitClose(wsConn)
+		return
+	}
+	gs.measurePongs(wsConn, conn)
+	if token := query.Get("invite"); token != "" {
+		gs.do(func() { gs.acceptInvite(conn, player, token) })
+	}
 
 	// Handle messages
+	wsConn.SetReadLimit(maxMessageSize)
+	malformed := 0
 	for {
+		gs.extendReadDeadline(wsConn)
+
 		var msg models.GameMessage
-		err := conn.ReadJSON(&msg)
+		data, err := readMessage(wsConn)
 		if err != nil {
-			log.Printf("WebSocket read error: %v", err)
+			gs.do(func() { gs.handleReadError(conn, err) })
+			if errors.Is(err, errInflatedMessage) {
+				awaitClose(wsConn)
+			}
 			break
 		}
 
-		gs.handleMessage(conn, &msg)
+		// Each message is its own trace; the span starts once a frame has arrived, not while waiting for one
+		ctx, span := tracer.Start(context.Background(), "websocket.read", trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(ATTR_TRANSPORT.String("websocket"), attribute.String("ttt.codec", codec.Name()),
+				attribute.Int("ttt.message.bytes", len(data))))
+		if err := codec.Decode(data, &msg); err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, "malformed message")
+			span.End()
+			log.Printf("Failed to decode %s message: %v", codec.Name(), err)
+			malformed++
+			if malformed >= maxMalformedMessages {
+				gs.do(func() { gs.closeClient(conn, websocket.ClosePolicyViolation) })
+				break
+			}
+			gs.do(func() { gs.sendClientError(conn, models.ERR_MALFORMED_MESSAGE, "Malformed message") })
+			continue
+		}
+		malformed = 0
+
+		span.SetAttributes(ATTR_MESSAGE_TYPE.String(msg.Type))
+		gs.receive(ctx, conn, &msg)
+		span.End()
 	}
 
 	// Clean up on disconnect
-	gs.handleDisconnect(conn)
+	gs.unregister(conn)
+}
+
+// registerClient attaches a new connection to a player, reclaiming the session when the token is valid
+// Returns nil if the connection was refused for its name or as a duplicate login
+func (gs *GameServer) registerClient(r *registration) *models.Player {
+	conn := r.conn
+	gs.clientVersions[conn] = r.version
+
+	if r.nameErr != nil {
+		log.Printf("Rejecting connection with invalid name: %v", r.nameErr)
+		gs.sendClientError(conn, models.ERR_INVALID_NAME, r.nameErr.Error())
+		gs.closeClient(conn, models.CLOSE_INVALID_NAME)
+		delete(gs.clientVersions, conn)
+		return nil
+	}
+
+	if r.keyErr != nil {
+		gs.sendClientError(conn, models.ERR_INVALID_DEVICE_KEY, r.keyErr.Error())
+	}
+
+	// Reclaim an existing player if the client presents a valid session or a known device key, otherwise create one
+	player := gs.sessionPlayer(r.playerID, r.token)
+	if player == nil {
+		player = gs.devicePlayer(r.deviceKey)
+		// Bans are screened by the player ID clients claim, which a device key reclaims without
+		if player != nil && gs.isBanned(r.clientIP, player.ID) {
+			log.Printf("Rejecting banned player %s reconnecting by device key", player.ID)
+			gs.closeClient(conn, models.CLOSE_BANNED)
+			delete(gs.clientVersions, conn)
+			return nil
+		}
+	}
+	var replaced []clientConn
+	if player != nil && gs.isConnected(player.ID) {
+		if gs.config.DuplicateLoginPolicy == config.DUPLICATE_LOGIN_REJECT {
+			log.Printf("Rejecting duplicate login for player %s", player.ID)
+			gs.closeClient(conn, models.CLOSE_DUPLICATE_LOGIN)
+			delete(gs.clientVersions, conn)
+			return nil
+		}
+		// Transfer the session: the old connections stop receiving anything for this player
+		replaced = gs.detachConnections(player.ID)
+	}
+
+	resumed := player != nil
+	if !resumed {
+		playerName := r.name
+		if gs.config.UniqueNames {
+			playerName = gs.uniqueName(playerName)
+		}
+		player = models.NewPlayer(playerName)
+		player.LastSeen = gs.clock.Now()
+	}
+	if r.region != "" {
+		player.Region = r.region
+	}
+	gs.addClient(conn, player, r.clientIP)
+	gs.recordFingerprint(player.ID, r.clientIP, r.userAgent)
+	gs.players.SavePlayer(player)
+	gs.rememberDevice(r.deviceKey, player)
+
+	for _, oldConn := range replaced {
+		log.Printf("Transferring session of player %s to a new connection", player.ID)
+		gs.closeClient(oldConn, models.CLOSE_DUPLICATE_LOGIN)
+	}
+
+	if resumed {
+		log.Printf("Player reconnected: %s (ID: %s)", player.Name, player.ID)
+	} else {
+		log.Printf("New player connected: %s (ID: %s)", player.Name, player.ID)
+		gs.firePlayerRegistered(player)
+	}
+
+	// Send session credentials so the client can reconnect as this player
+	sessionMsg := models.NewGameMessage(models.MSG_SESSION, map[string]string{"playerId": player.ID, "token": player.SessionToken})
+	sessionMsg.PlayerID = player.ID
+	gs.sendToClient(conn, sessionMsg)
+
+	// Send player info
+	gs.sendToClient(conn, models.NewGameMessage(models.MSG_PLAYER_UPDATE, player))
+
+	// Send current leaderboard
+	gs.sendLeaderboard(conn, models.RATING_POOL_STANDARD)
+	if gs.config.LobbyStatsInterval > 0 {
+		gs.sendToClient(conn, models.NewGameMessage(models.MSG_LOBBY_STATS, gs.lobbyStats()))
+	}
+	if gs.maintenance != nil {
+		gs.sendToClient(conn, models.NewGameMessage(models.MSG_MAINTENANCE, gs.maintenance))
+	}
+
+	if resumed {
+		gs.replayOutbox(conn, player.ID)
+		gs.handleReconnect(player)
+		gs.requeueRestored(player)
+		gs.sendCorrespondenceGames(conn, player)
+	}
+	return player
 }
 
 // handleMessage processes incoming WebSocket messages
-func (gs *GameServer) handleMessage(conn *websocket.Conn, msg *models.GameMessage) {
-	gs.mutex.Lock()
-	player, exists := gs.clients[conn]
-	gs.mutex.Unlock()
+func (gs *GameServer) handleMessage(ctx context.Context, conn clientConn, msg *models.GameMessage) {
+	ctx, span := tracer.Start(ctx, "handleMessage", trace.WithAttributes(ATTR_MESSAGE_TYPE.String(msg.Type)))
+	defer span.End()
 
+	player, exists := gs.clients[conn]
 	if !exists {
 		return
 	}
 
 	msg.PlayerID = player.ID
+	gs.lastActive[player.ID] = gs.clock.Now()
+	span.SetAttributes(ATTR_PLAYER_ID.String(player.ID))
+	if msg.GameID != "" {
+		span.SetAttributes(ATTR_GAME_ID.String(msg.GameID))
+	}
+
+	// Decode and validate the typed payload for this message type
+	payload, err := models.DecodePayload(msg)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+		gs.sendClientError(conn, models.ErrorCode(err), err.Error())
+		return
+	}
 
 	switch msg.Type {
 	case models.MSG_JOIN_QUEUE:
-		gs.handleJoinQueue(player)
+		gs.handleJoinQueue(player, payload.(*models.JoinQueuePayload).Mode)
 	case models.MSG_LEAVE_QUEUE:
 		gs.handleLeaveQueue(player)
 	case models.MSG_MAKE_MOVE:
-		gs.handleMakeMove(msg)
+		gs.handleMakeMove(ctx, player, payload.(*models.MakeMovePayload))
+	case models.MSG_SWAP_DECISION:
+		gs.handleSwapDecision(conn, player, payload.(*models.SwapDecisionPayload))
+	case models.MSG_PROPOSE_MOVE:
+		gs.handleProposeMove(ctx, conn, player, payload.(*models.MakeMovePayload))
 	case models.MSG_LEADERBOARD:
-		gs.sendLeaderboard(conn)
+		gs.sendLeaderboard(conn, payload.(*models.LeaderboardPayload).Pool())
+	case models.MSG_GET_PROFILE:
+		gs.handleGetProfile(conn, player, payload.(*models.GetProfilePayload))
+	case models.MSG_ACCEPT_MATCH:
+		gs.handleAcceptMatch(player, payload.(*models.MatchPayload))
+	case models.MSG_DECLINE_MATCH:
+		gs.handleDeclineMatch(player, payload.(*models.MatchPayload))
+	case models.MSG_GET_RATING_HISTORY:
+		gs.handleGetRatingHistory(conn, player, payload.(*models.RatingHistoryPayload))
+	case models.MSG_RESYNC:
+		gs.handleResync(conn, player, payload.(*models.GamePayload))
+	case models.MSG_HELLO:
+		gs.handleHello(conn, player, payload.(*models.HelloPayload))
+	case models.MSG_SPECTATE_GAME:
+		gs.handleSpectateGame(conn, player, payload.(*models.GamePayload))
+	case models.MSG_STOP_SPECTATING:
+		gs.handleStopSpectating(conn, payload.(*models.GamePayload))
+	case models.MSG_SPECTATOR_CHAT:
+		gs.handleSpectatorChat(conn, player, payload.(*models.ChatPayload))
+	case models.MSG_MUTE_SPECTATOR_CHAT:
+		gs.handleMuteSpectatorChat(conn, player, payload.(*models.MuteChatPayload))
+	case models.MSG_EMOTE:
+		gs.handleEmote(conn, player, payload.(*models.EmotePayload))
+	case models.MSG_BLOCK_PLAYER:
+		gs.handleBlockPlayer(conn, player, payload.(*models.BlockPayload))
+	case models.MSG_UNBLOCK_PLAYER:
+		gs.handleUnblockPlayer(conn, player, payload.(*models.BlockPayload))
+	case models.MSG_GET_BLOCK_LIST:
+		gs.sendBlockList(conn, player.ID)
+	case models.MSG_REQUEST_TAKEBACK:
+		gs.handleRequestTakeback(player, payload.(*models.GamePayload))
+	case models.MSG_ACCEPT_TAKEBACK:
+		gs.handleAnswerTakeback(player, payload.(*models.GamePayload), true)
+	case models.MSG_DECLINE_TAKEBACK:
+		gs.handleAnswerTakeback(player, payload.(*models.GamePayload), false)
+	case models.MSG_REQUEST_HINT:
+		gs.handleRequestHint(conn, player, payload.(*models.GamePayload))
+	case models.MSG_REPLAY_GAME:
+		gs.handleReplayGame(conn, payload.(*models.ReplayPayload))
+	case models.MSG_REPLAY_NEXT:
+		gs.handleReplayNext(conn, payload.(*models.GamePayload))
+	case models.MSG_GET_PUZZLE:
+		gs.handleGetPuzzle(conn, player)
+	case models.MSG_SOLVE_PUZZLE:
+		gs.handleSolvePuzzle(conn, player, payload.(*models.SolvePuzzlePayload))
+	case models.MSG_PLAY_BOT:
+		gs.handlePlayBot(conn, player, payload.(*models.PlayBotPayload))
+	case models.MSG_REPORT_PLAYER:
+		gs.handleReportPlayer(conn, player, payload.(*models.ReportPlayerPayload))
+	case models.MSG_CREATE_LOBBY:
+		gs.handleCreateLobby(conn, player, payload.(*models.LobbyRules))
+	case models.MSG_LIST_LOBBIES:
+		gs.handleListLobbies(conn, player)
+	case models.MSG_JOIN_LOBBY:
+		gs.handleJoinLobby(conn, player, payload.(*models.LobbyPayload))
+	case models.MSG_CLOSE_LOBBY:
+		gs.handleCloseLobby(conn, player, payload.(*models.LobbyPayload))
+	case models.MSG_CHALLENGE:
+		gs.handleChallenge(conn, player, payload.(*models.ChallengePayload))
+	case models.MSG_PROPOSE_SETTINGS:
+		gs.handleProposeSettings(conn, player, payload.(*models.ProposeSettingsPayload))
+	case models.MSG_ACCEPT_SETTINGS:
+		gs.handleAcceptSettings(conn, player, payload.(*models.ChallengeIDPayload))
+	case models.MSG_DECLINE_CHALLENGE:
+		gs.handleDeclineChallenge(conn, player, payload.(*models.ChallengeIDPayload))
 	}
 }
 
-// handleJoinQueue adds a player to the matchmaking queue
-func (gs *GameServer) handleJoinQueue(player *models.Player) {
-	gs.mutex.Lock()
+// handleJoinQueue adds a player to the matchmaking queue for the given mode
+func (gs *GameServer) handleJoinQueue(player *models.Player, mode string) {
+	if gs.newGamesPaused() {
+		gs.sendError(player.ID, models.ERR_MAINTENANCE, "New games are paused on this server, matchmaking is unavailable")
+		return
+	}
+
+	if gs.pendingMatch(player.ID) != nil {
+		gs.sendError(player.ID, models.ERR_ALREADY_PENDING, "Accept or decline your proposed match first")
+		return
+	}
+
+	if until, banned := gs.matchmakingBanned(player.ID); banned {
+		gs.sendError(player.ID, models.ERR_NOT_ALLOWED, "You are banned from matchmaking until "+until.UTC().Format(time.RFC3339))
+		return
+	}
+	if penalty, cooling := gs.queuePenalty(player.ID); cooling {
+		gs.sendQueuePenalty(player.ID, penalty)
+		return
+	}
+	if mode == models.MODE_CORRESPONDENCE && gs.correspondenceLimitReached(player.ID) {
+		gs.sendError(player.ID, models.ERR_NOT_ALLOWED, "Finish one of your correspondence games before starting another")
+		return
+	}
 
 	// Check if player is already in queue
-	for _, playerID := range gs.matchmaking {
-		if playerID == player.ID {
-			log.Printf("Player %s (%s) already in queue", player.Name, player.ID)
-			gs.mutex.Unlock()
-			return
-		}
+	if queuedMode, queued := gs.queuedMode(player.ID); queued && queuedMode == mode {
+		log.Printf("Player %s (%s) already in %s queue", player.Name, player.ID, mode)
+		return
+	}
+
+	if gs.queueFull(mode) {
+		gs.capacity.RejectedQueueJoins++
+		gs.sendServerFull(player.ID, models.FULL_QUEUE, "The "+mode+" queue is full, try again shortly")
+		return
 	}
 
-	// Add to queue
-	gs.matchmaking = append(gs.matchmaking, player.ID)
-	log.Printf("Player %s (%s) added to queue. Queue size: %d", player.Name, player.ID, len(gs.matchmaking))
+	// Switching modes moves the player to the back of the other queue
+	gs.removeFromQueue(player.ID)
+
+	// Add to queue; players whose last game the server dropped go ahead
+	slot := gs.queueSlot(mode, player.ID)
+	gs.matchmaking.Enqueue(mode, slot, player.ID, gs.clock.Now())
+	queueSize := len(gs.matchmaking.Queue(mode))
+	gamesFull := gs.atGameLimit()
+	log.Printf("Player %s (%s) added to %s queue. Queue size: %d", player.Name, player.ID, mode, queueSize)
+
+	gs.sendToPlayer(player.ID, models.NewGameMessage(models.MSG_QUEUE_JOINED, map[string]interface{}{
+		"mode":     mode,
+		"position": slot + 1,
+	}))
+	if gamesFull {
+		gs.sendServerFull(player.ID, models.FULL_GAMES, "All game slots are in use, you will be matched when one frees up")
+	}
 
 	// Try to match players
-	if len(gs.matchmaking) >= 2 {
-		log.Printf("Attempting to create match with %d players in queue", len(gs.matchmaking))
-		// Release the lock before calling createMatch to avoid deadlock
-		gs.mutex.Unlock()
-		gs.createMatch()
-	} else {
-		gs.mutex.Unlock()
+	if queueSize >= matchSize(mode) {
+		log.Printf("Attempting to create %s match with %d players in queue", mode, queueSize)
+		gs.createMatch(mode)
 	}
 }
 
-// handleLeaveQueue removes a player from the matchmaking queue
+// queuedMode returns which queue a player is waiting in, if any
+func (gs *GameServer) queuedMode(playerID string) (string, bool) {
+	return gs.matchmaking.QueuedMode(playerID)
+}
+
+// handleLeaveQueue removes a player from the matchmaking queue, declining any match they were offered
 func (gs *GameServer) handleLeaveQueue(player *models.Player) {
-	gs.mutex.Lock()
-	defer gs.mutex.Unlock()
+	gs.removeFromQueue(player.ID)
+	gs.withdrawFromMatch(player.ID)
+}
 
-	for i, playerID := range gs.matchmaking {
-		if playerID == player.ID {
-			gs.matchmaking = append(gs.matchmaking[:i], gs.matchmaking[i+1:]...)
-			break
-		}
-	}
+// removeFromQueue removes a player from the matchmaking queue if present
+func (gs *GameServer) removeFromQueue(playerID string) bool {
+	_, removed := gs.matchmaking.Dequeue(playerID)
+	return removed
 }
 
-// createMatch creates a new game between two players
-func (gs *GameServer) createMatch() {
-	gs.mutex.Lock()
+// createMatch creates a new game between the longest-waiting eligible pair in a mode's queue
+func (gs *GameServer) createMatch(mode string) {
+	if gs.newGamesPaused() {
+		return
+	}
 
-	if len(gs.matchmaking) < 2 {
-		log.Printf("Not enough players in queue: %d", len(gs.matchmaking))
-		gs.mutex.Unlock()
+	switch mode {
+	case models.MODE_TEAM:
+		gs.createTeamMatch()
+		return
+	case models.MODE_TRIO:
+		gs.createTrioMatch()
 		return
 	}
 
-	// Get first two players from queue
-	player1ID := gs.matchmaking[0]
-	player2ID := gs.matchmaking[1]
-	gs.matchmaking = gs.matchmaking[2:]
+	queue := gs.matchmaking.Queue(mode)
+	if len(queue) < 2 {
+		log.Printf("Not enough players in %s queue: %d", mode, len(queue))
+		return
+	}
 
-	player1, exists1 := gs.players[player1ID]
-	player2, exists2 := gs.players[player2ID]
+	first, second, ok := gs.pickPair(queue)
+	if !ok {
+		log.Printf("No eligible pair in %s queue of %d, waiting for new opponents", mode, len(queue))
+		return
+	}
+
+	if gs.atGameLimit() {
+		gs.capacity.DeferredMatches++
+		log.Printf("Active game limit of %d reached, %s queue of %d waits", gs.config.MaxActiveGames, mode, len(queue))
+		return
+	}
+
+	player1ID := queue[first]
+	player2ID := queue[second]
+	gs.matchmaking.Dequeue(player1ID)
+	gs.matchmaking.Dequeue(player2ID)
+
+	player1, exists1 := gs.players.Player(player1ID)
+	player2, exists2 := gs.players.Player(player2ID)
 
 	if !exists1 || !exists2 {
 		log.Printf("One or both players not found: player1=%v, player2=%v", exists1, exists2)
-		gs.mutex.Unlock()
 		return
 	}
 
-	// Create new game
-	newGame := models.NewGame()
-	newGame.PlayerX = player1
-	newGame.PlayerO = player2
-	newGame.Status = models.STATUS_PLAYING
-	player1.Symbol = "X"
-	player2.Symbol = "O"
+	gs.recordPairing(player1ID, player2ID)
+	gs.proposeMatch(mode, []*models.Player{player1, player2}, func() { gs.startMatch(mode, player1, player2) })
+}
 
-	gs.games[newGame.ID] = newGame
+// newGameForMode creates a game played by the rules of the variant a mode uses
+func (gs *GameServer) newGameForMode(mode string) *models.Game {
+	variant := models.VARIANT_STANDARD
+	if mode == models.MODE_MISERE {
+		variant = models.VARIANT_MISERE
+	}
+	newGame, err := gs.gameEngine.NewGame(variant)
+	if err != nil {
+		panic(err) // Every mode's variant is built into the engine, so this can't fail
+	}
+	return newGame
+}
 
-	// Release lock before sending messages to avoid deadlock
-	gs.mutex.Unlock()
+// startMatch creates a game between two human players and tells both of them
+func (gs *GameServer) startMatch(mode string, player1, player2 *models.Player) *models.Game {
+	newGame := gs.newGameForMode(mode)
+	gs.gameEngine.SeatPlayers(newGame, player1, player2)
+	newGame.Rated = mode == models.MODE_RATED
+	newGame.PieRule = gs.config.PieRule
+	if mode == models.MODE_BLITZ {
+		gs.gameEngine.StartClock(newGame, gs.config.BlitzTime)
+	}
+	if mode == models.MODE_CORRESPONDENCE {
+		newGame.MoveTime = gs.config.CorrespondenceMoveTime
+	}
+	gs.openGame(newGame, mode, player1, player2)
+	return newGame
+}
 
-	log.Printf("Created game %s between %s (X) and %s (O)", newGame.ID, player1.Name, player2.Name)
+// openGame starts a seated two-player game and sends both players game_found
+func (gs *GameServer) openGame(newGame *models.Game, kind string, player1, player2 *models.Player) {
+	gs.checkSameOrigin(newGame)
+	gs.addGame(newGame)
 
-	// Notify both players
-	gameFoundMsg := &models.GameMessage{
-		Type:   models.MSG_GAME_FOUND,
-		Data:   gs.gameEngine.GetGameStateForPlayer(newGame, player1.ID),
-		GameID: newGame.ID,
-	}
+	log.Printf("Created %s game %s between %s (X) and %s (O)", kind, newGame.ID, newGame.PlayerX().Name, newGame.PlayerO().Name)
+	gs.logGameCreated(newGame)
 
 	// Notify both players
-	gs.sendToPlayer(player1.ID, gameFoundMsg)
-
-	gameFoundMsg.Data = gs.gameEngine.GetGameStateForPlayer(newGame, player2.ID)
-	gs.sendToPlayer(player2.ID, gameFoundMsg)
+	gs.sendToPlayer(player1.ID, models.NewGameMessageForGame(models.MSG_GAME_FOUND, newGame.ID,
+		gs.gameEngine.GetGameStateForPlayer(newGame, player1.ID)))
+	gs.sendToPlayer(player2.ID, models.NewGameMessageForGame(models.MSG_GAME_FOUND, newGame.ID,
+		gs.gameEngine.GetGameStateForPlayer(newGame, player2.ID)))
+	gs.updateBlitzClock(newGame)
 }
 
 // handleMakeMove processes a player's move
-func (gs *GameServer) handleMakeMove(msg *models.GameMessage) {
-	var moveData map[string]interface{}
-	moveBytes, _ := json.Marshal(msg.Data)
-	json.Unmarshal(moveBytes, &moveData)
-
-	gameID, ok := moveData["gameId"].(string)
-	if !ok {
+func (gs *GameServer) handleMakeMove(ctx context.Context, player *models.Player, move *models.MakeMovePayload) {
+	gameInstance, exists := gs.games.Game(move.GameID)
+	if !exists {
+		gs.rejectMove(player, move, models.ERR_GAME_NOT_FOUND, "Game not found")
 		return
 	}
-
-	position, ok := moveData["position"].(float64)
-	if !ok {
+	if gameInstance.Teams != nil {
+		gs.rejectMove(player, move, models.ERR_TEAM_GAME, "Team games take moves as propose_move")
 		return
 	}
 
-	gs.mutex.Lock()
-	gameInstance, exists := gs.games[gameID]
-	gs.mutex.Unlock()
-
-	if !exists {
-		gs.sendError(msg.PlayerID, "Game not found")
+	if move.MoveID != "" && gs.resendMoveAck(player, gameInstance, move) {
 		return
 	}
 
+	gs.checkMoveTiming(gameInstance, player.ID, *move.Position)
+
 	// Make the move
-	err := gs.gameEngine.MakeMove(gameInstance, msg.PlayerID, int(position))
+	_, span := tracer.Start(ctx, "engine.MakeMove", trace.WithAttributes(ATTR_GAME_ID.String(gameInstance.ID),
+		ATTR_PLAYER_ID.String(player.ID), ATTR_MOVE_POSITION.Int(*move.Position)))
+	err := gs.gameEngine.MakeMove(gameInstance, player.ID, *move.Position)
 	if err != nil {
-		gs.sendError(msg.PlayerID, err.Error())
+		span.SetStatus(otelcodes.Error, err.Error())
+		span.End()
+		gs.rejectMove(player, move, models.ErrorCode(err), err.Error())
 		return
 	}
+	span.SetAttributes(ATTR_GAME_STATUS.String(gameInstance.Status))
+	span.End()
 
-	// Send game update to both players
-	gs.sendGameUpdate(gameInstance)
+	gs.ackMove(gameInstance, move.MoveID)
+	gs.afterMove(ctx, gameInstance)
+}
+
+// afterMove broadcasts the new state and either finishes the game or lets a bot reply
+func (gs *GameServer) afterMove(ctx context.Context, gameInstance *models.Game) {
+	move := gameInstance.Moves[len(gameInstance.Moves)-1]
+	gs.logEvent(gameInstance.ID, models.EVENT_MOVE, move.PlayerID, map[string]interface{}{
+		"symbol":   move.Symbol,
+		"position": move.Position,
+	})
 
-	// If game is finished, update leaderboard
+	// Send game update to both players; a finished game is announced once its result is recorded
+	_, span := tracer.Start(ctx, "sendGameUpdate", trace.WithAttributes(gameAttributes(gameInstance)...))
+	defer span.End()
 	if gameInstance.Status == models.STATUS_FINISHED {
-		now := time.Now()
-		gameInstance.EndTime = &now
-		gs.broadcastLeaderboard()
+		gs.finishGame(gameInstance)
+		return
+	}
+	gs.sendGameUpdate(gameInstance)
+	gs.updateBlitzClock(gameInstance)
+	gs.scheduleBotMove(gameInstance)
+}
+
+// finishGame records the result of a game that just ended, shows it to everyone, and refreshes the leaderboard
+func (gs *GameServer) finishGame(gameInstance *models.Game) {
+	gs.gameEngine.RecordResult(gameInstance)
+	analysis := gs.gameEngine.AnalyzeGame(gameInstance)
+	gs.gameEngine.RecordAnalysis(gameInstance, analysis)
+	gs.gameEngine.RecordThinkTimes(gameInstance)
+	gs.recordFinishedGame(gameInstance)
+
+	gs.sendGameUpdate(gameInstance)
+	gs.updateBlitzClock(gameInstance)
+	gs.sendGameEnd(gameInstance, analysis)
+	gs.logEvent(gameInstance.ID, models.EVENT_FINISHED, "", map[string]interface{}{"winner": gameInstance.Winner})
+	gs.broadcastLeaderboard()
+	gs.fireGameFinished(gameInstance)
+
+	// A game slot just opened for players held back by the cap
+	if gs.config.MaxActiveGames > 0 {
+		gs.retryMatches()
 	}
 }
 
-// sendGameUpdate sends game state to both players
+// sendGameUpdate sends game state to the players and any spectators
+// Clients that understand deltas get a game_delta unless a full snapshot is due
 func (gs *GameServer) sendGameUpdate(gameInstance *models.Game) {
-	if gameInstance.PlayerX != nil {
-		updateMsg := &models.GameMessage{
-			Type:   models.MSG_GAME_UPDATE,
-			Data:   gs.gameEngine.GetGameStateForPlayer(gameInstance, gameInstance.PlayerX.ID),
-			GameID: gameInstance.ID,
-		}
-		gs.sendToPlayer(gameInstance.PlayerX.ID, updateMsg)
+	gs.broadcastGameState(gameInstance, false)
+}
+
+// sendFullGameUpdate sends every viewer the full game state, for viewers that may have missed earlier updates
+func (gs *GameServer) sendFullGameUpdate(gameInstance *models.Game) {
+	gs.broadcastGameState(gameInstance, true)
+}
+
+// broadcastGameState sends the next numbered update of a game to its players and spectators
+func (gs *GameServer) broadcastGameState(gameInstance *models.Game, forceFull bool) {
+	gs.scheduleTurnReminder(gameInstance)
+	gs.scheduleMoveDeadline(gameInstance)
+	gs.pushYourTurn(gameInstance)
+	spectatorCount := gs.spectatorCount(gameInstance.ID)
+	seq, delta := gs.nextGameDelta(gameInstance, spectatorCount, forceFull)
+
+	var deltaMsg *models.GameMessage
+	if delta != nil {
+		deltaMsg = models.NewGameMessageForGame(models.MSG_GAME_DELTA, gameInstance.ID, delta)
 	}
 
-	if gameInstance.PlayerO != nil {
-		updateMsg := &models.GameMessage{
-			Type:   models.MSG_GAME_UPDATE,
-			Data:   gs.gameEngine.GetGameStateForPlayer(gameInstance, gameInstance.PlayerO.ID),
-			GameID: gameInstance.ID,
+	for _, player := range gameInstance.AllPlayers() {
+		if player.IsBot {
+			continue
 		}
-		gs.sendToPlayer(gameInstance.PlayerO.ID, updateMsg)
+		state := gs.gameEngine.GetGameStateForPlayer(gameInstance, player.ID)
+		state.SpectatorCount = spectatorCount
+		state.Seq = seq
+		gs.sendStateToPlayer(player.ID, models.NewGameMessageForGame(models.MSG_GAME_UPDATE, gameInstance.ID, state), deltaMsg)
 	}
+
+	state := gs.spectatorState(gameInstance, spectatorCount)
+	state.Seq = seq
+	gs.sendToSpectatorsOf(gameInstance, models.NewGameMessageForGame(models.MSG_GAME_UPDATE, gameInstance.ID, state), deltaMsg)
 }
 
-// sendToPlayer sends a message to a specific player
+// sendToPlayer numbers a message and sends it to each of a player's connections
+// A player without connections gets it on reconnecting, within the resume window
 func (gs *GameServer) sendToPlayer(playerID string, msg *models.GameMessage) {
-	gs.mutex.RLock()
-	defer gs.mutex.RUnlock()
-
-	found := false
-	for conn, player := range gs.clients {
-		if player.ID == playerID {
-			gs.sendToClient(conn, msg)
-			found = true
-			break
+	msg = gs.numberMessage(playerID, msg)
+	conns := gs.playerConns[playerID]
+	if len(conns) == 0 {
+		if msg.Seq == 0 {
+			log.Printf("ERROR: Player %s not found in clients map!", playerID)
 		}
+		return
 	}
 
-	if !found {
-		log.Printf("ERROR: Player %s not found in clients map!", playerID)
+	for conn := range conns {
+		gs.sendToClient(conn, msg)
 	}
+	gs.markDelivered(playerID, msg.Seq)
 }
 
 // sendToClient sends a message to a WebSocket connection
-func (gs *GameServer) sendToClient(conn *websocket.Conn, msg *models.GameMessage) {
-	err := conn.WriteJSON(msg)
-	if err != nil {
-		log.Printf("WebSocket write error: %v", err)
+func (gs *GameServer) sendToClient(conn clientConn, msg *models.GameMessage) {
+	// Shape the message for the client's protocol version, skipping types it doesn't know
+	version := gs.clientVersion(conn)
+	if !models.SupportsMessage(version, msg.Type) {
+		return
+	}
+
+	// Writes only queue the message. A slow client misses droppable messages first, and is
+	// disconnected only once game-critical ones back up; it catches up from its outbox on reconnect.
+	err := conn.WriteMessage(models.AdaptForVersion(msg, version))
+	var dropped *droppedMessageError
+	switch {
+	case err == nil || err == errClientClosed:
+	case errors.As(err, &dropped):
+		gs.delivery.DroppedMessages[dropped.msgType]++
+	case err == errSendQueueFull:
+		log.Printf("Disconnecting stalled client: no room for %s message", msg.Type)
+		gs.delivery.StalledClients++
+		conn.Close()
+	default:
+		log.Printf("Write error for %s message: %v", msg.Type, err)
 		conn.Close()
-	} else {
-		log.Printf("Message %s sent successfully", msg.Type)
 	}
 }
 
 // sendError sends an error message to a player
-func (gs *GameServer) sendError(playerID string, errorMsg string) {
-	msg := &models.GameMessage{
-		Type: models.MSG_ERROR,
-		Data: map[string]string{"error": errorMsg},
-	}
+func (gs *GameServer) sendError(playerID, code, errorMsg string) {
+	msg := models.NewGameMessage(models.MSG_ERROR, models.ErrorPayload{Error: errorMsg, Code: code})
 	gs.sendToPlayer(playerID, msg)
 }
 
-// sendLeaderboard sends the leaderboard to a specific connection
-func (gs *GameServer) sendLeaderboard(conn *websocket.Conn) {
-	leaderboard := gs.getLeaderboard()
-	msg := &models.GameMessage{
-		Type: models.MSG_LEADERBOARD,
-		Data: leaderboard,
-	}
+// sendClientError sends an error message to a specific connection
+func (gs *GameServer) sendClientError(conn clientConn, code, errorMsg string) {
+	msg := models.NewGameMessage(models.MSG_ERROR, models.ErrorPayload{Error: errorMsg, Code: code})
+	gs.sendToClient(conn, msg)
+}
+
+// sendLeaderboard sends a rating pool's leaderboard to a specific connection
+func (gs *GameServer) sendLeaderboard(conn clientConn, pool string) {
+	leaderboard := gs.getLeaderboard(pool)
+	msg := models.NewGameMessage(models.MSG_LEADERBOARD, leaderboard)
 	gs.sendToClient(conn, msg)
 }
 
-// broadcastLeaderboard sends the leaderboard to all connected players
+// broadcastLeaderboard sends the standard pool's leaderboard to all connected players
 func (gs *GameServer) broadcastLeaderboard() {
-	leaderboard := gs.getLeaderboard()
-	msg := &models.GameMessage{
-		Type: models.MSG_LEADERBOARD,
-		Data: leaderboard,
+	select {
+	case gs.leaderboardChanged <- struct{}{}:
+	default:
+		// A broadcast is already pending and will include this change
 	}
-	gs.broadcast <- msg
 }
 
-// getLeaderboard returns the top players sorted by rating
-func (gs *GameServer) getLeaderboard() []*models.Player {
-	gs.mutex.RLock()
-	defer gs.mutex.RUnlock()
-
-	players := make([]*models.Player, 0, len(gs.players))
-	for _, player := range gs.players {
-		// Only include players who have played at least one game
-		if player.Wins+player.Losses+player.Draws > 0 {
-			players = append(players, player)
+// runLeaderboardBroadcast pushes the leaderboard at most once per interval, however many games finished
+func (gs *GameServer) runLeaderboardBroadcast() {
+	ticker := gs.clock.NewTicker(leaderboardBroadcastInterval)
+	defer ticker.Stop()
+
+	for range ticker.C() {
+		select {
+		case <-gs.leaderboardChanged:
+			gs.do(func() {
+				gs.sendToAll(models.NewGameMessage(models.MSG_LEADERBOARD, gs.getLeaderboard(models.RATING_POOL_STANDARD)))
+			})
+		default:
 		}
 	}
+}
 
-	// Sort by rating (descending)
-	sort.Slice(players, func(i, j int) bool {
-		return players[i].Rating > players[j].Rating
-	})
+// getLeaderboard returns the top players of the current season sorted by their rating in a pool
+func (gs *GameServer) getLeaderboard(pool string) []*models.Player {
+	players := gs.rankedPlayers(pool)
 
 	// Return top 10
 	if len(players) > 10 {
@@ -367,37 +952,57 @@ func (gs *GameServer) getLeaderboard() []*models.Player {
 	return players
 }
 
-// handleBroadcast processes broadcast messages
+// handleBroadcast sends messages queued from outside the hub to every client
 func (gs *GameServer) handleBroadcast() {
 	for msg := range gs.broadcast {
-		gs.mutex.RLock()
-		for conn := range gs.clients {
-			gs.sendToClient(conn, msg)
-		}
-		gs.mutex.RUnlock()
+		gs.do(func() { gs.sendToAll(msg) })
+	}
+}
+
+// sendToAll sends a message to every connected client
+func (gs *GameServer) sendToAll(msg *models.GameMessage) {
+	for conn := range gs.clients {
+		gs.sendToClient(conn, msg)
 	}
 }
 
 // handleDisconnect cleans up when a player disconnects
-func (gs *GameServer) handleDisconnect(conn *websocket.Conn) {
-	gs.mutex.Lock()
-	defer gs.mutex.Unlock()
+func (gs *GameServer) handleDisconnect(conn clientConn) {
+	// Per-connection encoding and replay state is dropped even for connections already detached by a session transfer
+	defer delete(gs.clientVersions, conn)
+	gs.stopReplay(conn)
 
 	player, exists := gs.clients[conn]
 	if !exists {
 		return
 	}
 
-	// Remove from queue if present
-	for i, playerID := range gs.matchmaking {
-		if playerID == player.ID {
-			gs.matchmaking = append(gs.matchmaking[:i], gs.matchmaking[i+1:]...)
-			break
-		}
-	}
+	// Remove from queue if present, and decline any match waiting for them
+	gs.removeFromQueue(player.ID)
+	gs.withdrawFromMatch(player.ID)
 
 	// Update last seen time
-	player.LastSeen = time.Now()
+	player.LastSeen = gs.clock.Now()
+
+	gs.removeClient(conn)
+	if !gs.isConnected(player.ID) {
+		gs.closeHostedLobby(player.ID, models.LOBBY_HOST_LEFT)
+		gs.closeHeldChallenges(player.ID, models.CHALLENGE_LEFT)
+	}
 
-	delete(gs.clients, conn)
+	// Stop watching any games
+	watched := gs.removeSpectator(conn)
+
+	// Pause any game in progress and start the forfeit countdown
+	pausedGame := gs.pauseGameForDisconnect(player)
+
+	if pausedGame != nil {
+		gs.notifyOpponentDisconnected(pausedGame, player)
+		gs.sendGameUpdate(pausedGame)
+	}
+
+	// Refresh spectator counts for games this connection was watching
+	for _, gameInstance := range watched {
+		gs.sendGameUpdate(gameInstance)
+	}
 }
@@ -1,18 +1,50 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"tictactoe-server/certs"
+	"tictactoe-server/config"
+	"tictactoe-server/discord"
 	"tictactoe-server/handlers"
+	"tictactoe-server/models"
+	"tictactoe-server/proto/tictactoepb"
+	"tictactoe-server/tracing"
+	"tictactoe-server/web"
 
 	"github.com/rs/cors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
+	// Load configuration from environment
+	cfg := config.Load()
+
+	// Export traces when an OTLP collector is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("Tracing setup failed: %v", err)
+	}
+	if cfg.OTLPEndpoint != "" {
+		log.Printf("🔭 Exporting traces to %s", cfg.OTLPEndpoint)
+	}
+
+	// Serve HTTPS, WSS and gRPC over TLS directly when a certificate is configured
+	tlsConfig, redirect, err := certs.Setup(cfg)
+	if err != nil {
+		log.Fatalf("TLS setup failed: %v", err)
+	}
+
 	// Create game server
-	gameServer := handlers.NewGameServer()
+	gameServer := handlers.NewGameServer(cfg)
 	gameServer.Run()
 
 	// Set up HTTP routes
@@ -21,21 +53,75 @@ func main() {
 	// WebSocket endpoint
 	mux.HandleFunc("/ws", gameServer.HandleWebSocket)
 
-	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// Player profile endpoint
+	mux.HandleFunc("/api/players/", gameServer.HandlePlayerAPI)
 
-	// Enable CORS for cross-origin requests (frontend will be on different domain)
-	// Get allowed origins from environment variable for security
-	allowedOrigins := []string{"http://localhost:3000"} // Default for local development
-	if frontendURL := os.Getenv("FRONTEND_URL"); frontendURL != "" {
-		allowedOrigins = []string{frontendURL}
+	// Current and past season leaderboards
+	mux.HandleFunc("/api/seasons", gameServer.HandleSeasonsAPI)
+	mux.HandleFunc("/api/seasons/", gameServer.HandleSeasonsAPI)
+
+	// Daily and weekly stats summaries
+	mux.HandleFunc("/api/stats/", gameServer.HandleStatsAPI)
+
+	// Finished games exported as JSON, text notation, SVG or GIF
+	mux.HandleFunc("/api/games/", gameServer.HandleGamesAPI)
+
+	// Daily puzzle leaderboard
+	mux.HandleFunc("/api/puzzles/leaderboard", gameServer.HandlePuzzleLeaderboard)
+
+	// One-time invite links that start a game with their creator
+	mux.HandleFunc("/api/invites", gameServer.HandleInvitesAPI)
+
+	// OAuth login and account linking (requires OAUTH_<PROVIDER>_CLIENT_ID and _SECRET)
+	mux.HandleFunc("/auth/", gameServer.HandleAuth)
+
+	// Web Push public key for subscribing browsers (requires VAPID keys)
+	mux.HandleFunc("/api/push/key", gameServer.HandlePushKey)
+
+	// Discord slash commands (requires DISCORD_PUBLIC_KEY)
+	if cfg.DiscordPublicKey != "" {
+		interactions, err := discord.NewInteractionHandler(cfg.DiscordPublicKey, cfg.DiscordAPIURL)
+		if err != nil {
+			log.Fatalf("Discord setup failed: %v", err)
+		}
+		mux.Handle("/integrations/discord", interactions)
 	}
 
+	// Admin API and console (requires ADMIN_TOKEN)
+	mux.Handle("/admin/", gameServer.AdminHandler())
+
+	// Liveness and readiness probes
+	mux.HandleFunc("/healthz", gameServer.HandleLiveness)
+	mux.HandleFunc("/readyz", gameServer.HandleReadiness)
+
+	// Utilization against the configured limits
+	mux.HandleFunc("/health", gameServer.HandleHealth)
+
+	// Utilization, scaling recommendation and drain state for autoscalers
+	mux.HandleFunc("/api/capacity", gameServer.HandleCapacity)
+
+	// Utilization and limit metrics for Prometheus
+	mux.HandleFunc("/metrics", gameServer.HandleMetrics)
+
+	// Optional static frontend on every other path, with single-page app fallback
+	if cfg.StaticFrontend != "" {
+		frontend := web.Embedded()
+		if cfg.StaticFrontend != config.STATIC_EMBEDDED {
+			if frontend, err = web.Dir(cfg.StaticFrontend); err != nil {
+				log.Fatalf("Static frontend: %v", err)
+			}
+		}
+		mux.Handle("/", web.Handler(frontend))
+		// Unknown API paths stay 404s instead of falling back to the app
+		mux.Handle("/api/", http.NotFoundHandler())
+		log.Printf("🖥️  Serving the %s frontend from /", cfg.StaticFrontend)
+	}
+
+	// Enable CORS for cross-origin requests (frontend will be on different domain)
+	// Allowed origins come from the FRONTEND_URL environment variable for security
+	// and are matched the same way as WebSocket origins, wildcard subdomains included; reloads update them
 	c := cors.New(cors.Options{
-		AllowedOrigins:   allowedOrigins,
+		AllowOriginFunc:  gameServer.AllowsOrigin,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
 		AllowCredentials: true,
@@ -43,14 +129,93 @@ func main() {
 
 	handler := c.Handler(mux)
 
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	log.Printf("🎮 Multiplayer Tic-Tac-Toe Server starting on port %s", cfg.Port)
+	log.Printf("🌐 Allowed CORS origins: %v | WebSocket origins: %v", cfg.AllowedOrigins, cfg.WebSocketOrigins)
+	log.Printf("✅ Health: /healthz /readyz | Metrics: /metrics | WebSocket: /ws")
+
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: handler, TLSConfig: tlsConfig}
+
+	// Shut down gracefully on SIGINT/SIGTERM so clients get a proper close reason
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Reload timers, limits, matchmaking parameters and origins on SIGHUP, keeping every connection
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			gameServer.ReloadConfig(models.RELOAD_SIGNAL)
+		}
+	}()
+
+	go func() {
+		// Over TLS, HTTP/2 is negotiated automatically; WebSocket upgrades still use HTTP/1.1
+		var err error
+		if tlsConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	if tlsConfig != nil {
+		log.Printf("🔒 TLS enabled: https:// and wss:// on port %s", cfg.Port)
+	}
+
+	// Plain HTTP redirects to HTTPS and answers Let's Encrypt challenges
+	var redirectServer *http.Server
+	if redirect != nil {
+		redirectServer = &http.Server{Addr: ":" + cfg.HTTPRedirectPort, Handler: redirect}
+		log.Printf("↪️  Redirecting http:// on port %s to HTTPS", cfg.HTTPRedirectPort)
+
+		go func() {
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal(err)
+			}
+		}()
 	}
 
-	log.Printf("🎮 Multiplayer Tic-Tac-Toe Server starting on port %s", port)
-	log.Printf("🌐 Allowed CORS origins: %v", allowedOrigins)
-	log.Printf("✅ Health check: /health | WebSocket: /ws")
-	log.Fatal(http.ListenAndServe(":"+port, handler))
+	// gRPC mirror of the game API for native clients
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "0" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var options []grpc.ServerOption
+		if tlsConfig != nil {
+			options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		}
+		grpcServer = grpc.NewServer(options...)
+		tictactoepb.RegisterTicTacToeServer(grpcServer, gameServer.GRPCService())
+		log.Printf("📡 gRPC API on port %s", cfg.GRPCPort)
+
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	log.Printf("🛑 Shutting down...")
+
+	gameServer.Shutdown()
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP shutdown error: %v", err)
+	}
+	if redirectServer != nil {
+		redirectServer.Shutdown(shutdownCtx)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Printf("Tracing shutdown error: %v", err)
+	}
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Achievement IDs
+const (
+	ACHIEVEMENT_FIRST_WIN      = "first_win"      // Won a game
+	ACHIEVEMENT_WIN_STREAK     = "win_streak_10"  // Won 10 rated games in a row
+	ACHIEVEMENT_CORNER_KEEPER  = "corner_keeper"  // Won a game in which the opponent never took a corner
+	ACHIEVEMENT_PERFECT_SEASON = "perfect_season" // Finished a ranked season without dropping a game
+	ACHIEVEMENT_CENTURION      = "games_100"      // Played 100 games
+)
+
+// Badge is an achievement a player has earned
+type Badge struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	EarnedAt    time.Time `json:"earnedAt"`
+	GameID      string    `json:"gameId,omitempty"` // Game that earned it, if any
+	Season      int       `json:"season,omitempty"` // Season that earned it, if any
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Admin operations recorded in the audit log
+const (
+	ADMIN_END_GAME             = "end_game"
+	ADMIN_CANCEL_GAME          = "cancel_game"
+	ADMIN_KICK                 = "kick"
+	ADMIN_BAN                  = "ban"
+	ADMIN_UNBAN                = "unban"
+	ADMIN_MUTE                 = "mute"
+	ADMIN_UNMUTE               = "unmute"
+	ADMIN_RESET_RATINGS        = "reset_ratings"
+	ADMIN_ANNOUNCE             = "announce"
+	ADMIN_MAINTENANCE          = "maintenance"
+	ADMIN_SCHEDULE_MAINTENANCE = "schedule_maintenance"
+	ADMIN_DRAIN                = "drain"
+	ADMIN_VERIFY_REPORT        = "verify_report"
+	ADMIN_DISMISS_REPORT       = "dismiss_report"
+	ADMIN_LIFT_THROTTLE        = "lift_throttle"
+	ADMIN_RELOAD_CONFIG        = "reload_config"
+	ADMIN_CREATE_KEY           = "create_key"
+	ADMIN_REVOKE_KEY           = "revoke_key"
+)
+
+// AdminAuditEntry records one admin operation that took effect
+type AdminAuditEntry struct {
+	Time   time.Time              `json:"time"`
+	Actor  string                 `json:"actor,omitempty"` // Operator named in the X-Admin-Actor header, or else the key
+	Key    string                 `json:"key,omitempty"`   // Name of the admin API key used
+	IP     string                 `json:"ip"`
+	Action string                 `json:"action"`           // One of the ADMIN_ operations
+	Target string                 `json:"target,omitempty"` // The game, player, report or address acted on
+	Params map[string]interface{} `json:"params,omitempty"`
+}
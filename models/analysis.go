@@ -0,0 +1,49 @@
+package models
+
+// Move grades, judged against perfect play
+const (
+	MOVE_OPTIMAL = "optimal" // Kept the best result the position allowed
+	MOVE_MISTAKE = "mistake" // Let a won position slip to a draw
+	MOVE_BLUNDER = "blunder" // Turned a won or drawn position into a loss
+)
+
+// MoveAnalysis grades a single move
+type MoveAnalysis struct {
+	Symbol    Symbol `json:"symbol"`
+	Position  int    `json:"position"`
+	Quality   string `json:"quality"`
+	BestMoves []int  `json:"bestMoves"` // Positions that kept the best result
+}
+
+// PlayerAnalysis totals one player's move grades in a game
+type PlayerAnalysis struct {
+	PlayerID string  `json:"playerId"`
+	Symbol   Symbol  `json:"symbol"`
+	Moves    int     `json:"moves"`
+	Optimal  int     `json:"optimal"`
+	Mistakes int     `json:"mistakes"`
+	Blunders int     `json:"blunders"`
+	Accuracy float64 `json:"accuracy"` // Share of moves that were optimal, 0-1
+}
+
+// GameAnalysis compares a game's moves with perfect play
+type GameAnalysis struct {
+	Moves   []MoveAnalysis   `json:"moves"`
+	Players []PlayerAnalysis `json:"players"` // X first
+}
+
+// Hint suggests a move to the player whose turn it is
+type Hint struct {
+	GameID    string `json:"gameId"`
+	Position  int    `json:"position"`  // Best cell, 0-8
+	HintsLeft int    `json:"hintsLeft"` // Hints the player may still request in this game
+}
+
+// GameEndPayload is sent to both players once a game has finished
+type GameEndPayload struct {
+	GameID   string        `json:"gameId"`
+	Winner   Result        `json:"winner"`
+	Analysis *GameAnalysis `json:"analysis"` // Nil for games the solver does not cover, such as trio games
+
+	ThinkTimes []ThinkStats `json:"thinkTimes"` // Each player's time over their moves, in seating order
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ClockUpdate is the data of a clock_update message, sent whenever a blitz game's running clock changes hands
+type ClockUpdate struct {
+	GameID      string           `json:"gameId"`
+	CurrentTurn Symbol           `json:"currentTurn"`
+	Running     bool             `json:"running"`    // False while the game is paused or over
+	TimeLeftMs  map[Symbol]int64 `json:"timeLeftMs"` // Symbol -> milliseconds left when the message was sent
+}
+
+// RemainingTime returns how long a symbol has left on a blitz game's clock at now
+func (g *Game) RemainingTime(symbol Symbol, now time.Time) time.Duration {
+	left := g.TimeLeft[symbol]
+	if symbol == g.CurrentTurn && g.Status == STATUS_PLAYING {
+		left -= now.Sub(g.TurnStartedAt)
+	}
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// TimeLeftMs returns every symbol's remaining time at now in milliseconds
+func (g *Game) TimeLeftMs(now time.Time) map[Symbol]int64 {
+	timeLeft := make(map[Symbol]int64, len(g.TimeLeft))
+	for symbol := range g.TimeLeft {
+		timeLeft[symbol] = g.RemainingTime(symbol, now).Milliseconds()
+	}
+	return timeLeft
+}
+
+// NewClockUpdate describes a blitz game's clock at now
+func NewClockUpdate(game *Game, now time.Time) ClockUpdate {
+	return ClockUpdate{
+		GameID:      game.ID,
+		CurrentTurn: game.CurrentTurn,
+		Running:     game.Status == STATUS_PLAYING,
+		TimeLeftMs:  game.TimeLeftMs(now),
+	}
+}
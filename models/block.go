@@ -0,0 +1,25 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// BlockedPlayer is an entry in a player's block list
+type BlockedPlayer struct {
+	PlayerID  string    `json:"playerId"`
+	Name      string    `json:"name"`
+	BlockedAt time.Time `json:"blockedAt"`
+}
+
+// BlockPayload is the data of block_player and unblock_player messages
+type BlockPayload struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *BlockPayload) Validate() error {
+	if p.PlayerID == "" {
+		return errors.New("playerId is required")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import "fmt"
+
+// Bot personalities, each played by one of the engine's bot strategies
+const (
+	BOT_PERFECT       = "perfect"       // Plays perfectly; the bot that fills in for missing players
+	BOT_CORNER_OPENER = "corner_opener" // Opens in a corner to set up forks, then plays perfectly
+	BOT_CENTER_FIRST  = "center_first"  // Takes the centre whenever it can, then plays perfectly
+	BOT_BLUNDERER     = "blunderer"     // Moves at random, sometimes missing wins and blocks
+)
+
+// BotPersonalities lists the personalities a play_bot may ask for
+var BotPersonalities = []string{BOT_PERFECT, BOT_CORNER_OPENER, BOT_CENTER_FIRST, BOT_BLUNDERER}
+
+// PlayBotPayload is the data of a play_bot message: a practice game against a bot, casual unless Mode says otherwise
+type PlayBotPayload struct {
+	Personality string `json:"personality"` // Defaults to BOT_PERFECT
+	Mode        string `json:"mode"`        // MODE_CASUAL, MODE_MISERE or MODE_BLITZ; defaults to casual
+}
+
+func (p *PlayBotPayload) Validate() error {
+	switch p.Personality {
+	case "":
+		p.Personality = BOT_PERFECT
+	case BOT_PERFECT, BOT_CORNER_OPENER, BOT_CENTER_FIRST, BOT_BLUNDERER:
+	default:
+		return fmt.Errorf("personality must be one of %q", BotPersonalities)
+	}
+
+	switch p.Mode {
+	case "":
+		p.Mode = MODE_CASUAL
+	case MODE_CASUAL, MODE_MISERE, MODE_BLITZ:
+	default:
+		return fmt.Errorf("mode must be %q, %q or %q", MODE_CASUAL, MODE_MISERE, MODE_BLITZ)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Reasons sent in challenge_closed messages
+const (
+	CHALLENGE_ACCEPTED = "accepted" // Both players agreed on the settings and the game began
+	CHALLENGE_DECLINED = "declined" // One of the players declined or withdrew
+	CHALLENGE_EXPIRED  = "expired"  // The latest proposal went unanswered for too long
+	CHALLENGE_REPLACED = "replaced" // The challenger challenged someone else
+	CHALLENGE_LEFT     = "left"     // One of the players disconnected
+)
+
+// GameSettings are the rules two players agree on before a challenge's game starts
+// They are kept on the game and echoed in its state
+type GameSettings struct {
+	LobbyRules
+	FirstPlayerID string `json:"firstPlayerId,omitempty"` // Who plays X and moves first; empty decides at random
+}
+
+func (s *GameSettings) Validate() error {
+	return s.LobbyRules.Validate()
+}
+
+// Challenge is a game offered to one player whose settings are being negotiated
+type Challenge struct {
+	ID             string       `json:"id"`
+	ChallengerID   string       `json:"challengerId"`
+	ChallengerName string       `json:"challengerName"`
+	OpponentID     string       `json:"opponentId"`
+	OpponentName   string       `json:"opponentName"`
+	LobbyID        string       `json:"lobbyId,omitempty"` // Set when the opponent is the host of a lobby the challenger found
+	Settings       GameSettings `json:"settings"`          // The latest proposal
+	ProposedBy     string       `json:"proposedBy"`        // Who made the latest proposal; only the other player may accept it
+	ExpiresAt      time.Time    `json:"expiresAt"`         // When the latest proposal lapses
+}
+
+// ChallengePayload is the data of a challenge message: either a player to challenge or a lobby whose host to challenge
+// Settings default to the lobby's rules, or to a casual untimed 3x3 game
+type ChallengePayload struct {
+	OpponentID string        `json:"opponentId,omitempty"`
+	LobbyID    string        `json:"lobbyId,omitempty"`
+	Settings   *GameSettings `json:"settings,omitempty"`
+}
+
+func (p *ChallengePayload) Validate() error {
+	if (p.OpponentID == "") == (p.LobbyID == "") {
+		return errors.New("exactly one of opponentId and lobbyId is required")
+	}
+	if p.Settings != nil {
+		return p.Settings.Validate()
+	}
+	return nil
+}
+
+// ProposeSettingsPayload is the data of a propose_settings message, a counter-proposal in a challenge
+type ProposeSettingsPayload struct {
+	ChallengeID string       `json:"challengeId"`
+	Settings    GameSettings `json:"settings"`
+}
+
+func (p *ProposeSettingsPayload) Validate() error {
+	if p.ChallengeID == "" {
+		return errors.New("challengeId is required")
+	}
+	return p.Settings.Validate()
+}
+
+// ChallengeIDPayload is the data of accept_settings and decline_challenge messages
+type ChallengeIDPayload struct {
+	ChallengeID string `json:"challengeId"`
+}
+
+func (p *ChallengeIDPayload) Validate() error {
+	if p.ChallengeID == "" {
+		return errors.New("challengeId is required")
+	}
+	return nil
+}
+
+// ChallengeClosed is the data of a challenge_closed message sent to both players
+type ChallengeClosed struct {
+	ChallengeID string `json:"challengeId"`
+	Reason      string `json:"reason"`
+	GameID      string `json:"gameId,omitempty"` // Set when the game started
+}
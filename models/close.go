@@ -0,0 +1,41 @@
+package models
+
+import "github.com/gorilla/websocket"
+
+// Application-specific WebSocket close codes (4000-4999 is reserved for applications)
+const (
+	CLOSE_IDLE_TIMEOUT    = 4000
+	CLOSE_KICKED          = 4001
+	CLOSE_BANNED          = 4002
+	CLOSE_DUPLICATE_LOGIN = 4003
+	CLOSE_INVALID_NAME    = 4004
+	CLOSE_ACCOUNT_DELETED = 4005
+)
+
+// CloseReason describes why the server closed a connection
+type CloseReason struct {
+	Code      int    `json:"code"`
+	Reason    string `json:"reason"`
+	Reconnect bool   `json:"reconnect"` // Whether the client should try to reconnect automatically
+}
+
+// closeReasons lists the structured reasons the server sends before closing a connection
+var closeReasons = map[int]CloseReason{
+	websocket.CloseGoingAway:       {Code: websocket.CloseGoingAway, Reason: "server shutting down", Reconnect: true},
+	websocket.ClosePolicyViolation: {Code: websocket.ClosePolicyViolation, Reason: "policy violation", Reconnect: false},
+	websocket.CloseProtocolError:   {Code: websocket.CloseProtocolError, Reason: "unsupported protocol version", Reconnect: false},
+	CLOSE_IDLE_TIMEOUT:             {Code: CLOSE_IDLE_TIMEOUT, Reason: "idle timeout", Reconnect: true},
+	CLOSE_KICKED:                   {Code: CLOSE_KICKED, Reason: "kicked by an administrator", Reconnect: false},
+	CLOSE_BANNED:                   {Code: CLOSE_BANNED, Reason: "banned", Reconnect: false},
+	CLOSE_DUPLICATE_LOGIN:          {Code: CLOSE_DUPLICATE_LOGIN, Reason: "logged in from another connection", Reconnect: false},
+	CLOSE_INVALID_NAME:             {Code: CLOSE_INVALID_NAME, Reason: "invalid name", Reconnect: false},
+	CLOSE_ACCOUNT_DELETED:          {Code: CLOSE_ACCOUNT_DELETED, Reason: "account deleted", Reconnect: false},
+}
+
+// CloseReasonFor returns the structured reason for a close code
+func CloseReasonFor(code int) CloseReason {
+	if reason, exists := closeReasons[code]; exists {
+		return reason
+	}
+	return CloseReason{Code: code, Reason: "connection closed", Reconnect: true}
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"tictactoe-server/proto/tictactoepb"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Supported message encodings
+const (
+	ENCODING_JSON     = "json"
+	ENCODING_MSGPACK  = "msgpack"
+	ENCODING_PROTOBUF = "protobuf"
+)
+
+// Encoder serializes messages for the wire
+type Encoder interface {
+	Encode(msg *GameMessage) ([]byte, error)
+}
+
+// Decoder parses messages received from the wire
+type Decoder interface {
+	Decode(data []byte, msg *GameMessage) error
+}
+
+// Codec is a named wire format for WebSocket messages
+type Codec interface {
+	Encoder
+	Decoder
+	Name() string
+	Binary() bool // Whether frames should be sent as binary rather than text
+}
+
+// CodecFor returns the codec for an encoding name, or false if it is unknown
+func CodecFor(name string) (Codec, bool) {
+	switch name {
+	case ENCODING_JSON, "":
+		return JSONCodec{}, true
+	case ENCODING_MSGPACK:
+		return MsgpackCodec{}, true
+	case ENCODING_PROTOBUF:
+		return ProtobufCodec{}, true
+	}
+	return nil, false
+}
+
+// JSONCodec encodes messages as JSON text frames
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string { return ENCODING_JSON }
+func (JSONCodec) Binary() bool { return false }
+
+func (JSONCodec) Encode(msg *GameMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec) Decode(data []byte, msg *GameMessage) error {
+	return json.Unmarshal(data, msg)
+}
+
+// MsgpackCodec encodes messages as MessagePack using the same field names as JSON
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Name() string { return ENCODING_MSGPACK }
+func (MsgpackCodec) Binary() bool { return true }
+
+func (MsgpackCodec) Encode(msg *GameMessage) ([]byte, error) {
+	data, err := normalizeData(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return msgpack.Marshal(map[string]interface{}{
+		"v":        msg.Version,
+		"type":     msg.Type,
+		"data":     data,
+		"gameId":   msg.GameID,
+		"playerId": msg.PlayerID,
+		"seq":      msg.Seq,
+	})
+}
+
+func (MsgpackCodec) Decode(data []byte, msg *GameMessage) error {
+	var envelope struct {
+		Version  int         `msgpack:"v"`
+		Type     string      `msgpack:"type"`
+		Data     interface{} `msgpack:"data"`
+		GameID   string      `msgpack:"gameId"`
+		PlayerID string      `msgpack:"playerId"`
+		Seq      int         `msgpack:"seq"`
+	}
+	if err := msgpack.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(envelope.Data)
+	if err != nil {
+		return err
+	}
+
+	*msg = GameMessage{
+		Version:  envelope.Version,
+		Type:     envelope.Type,
+		Data:     payload,
+		GameID:   envelope.GameID,
+		PlayerID: envelope.PlayerID,
+		Seq:      envelope.Seq,
+	}
+	return nil
+}
+
+// ProtobufCodec encodes messages as the tictactoe.Envelope protobuf message, with typed payloads for the busiest types
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) Name() string { return ENCODING_PROTOBUF }
+func (ProtobufCodec) Binary() bool { return true }
+
+func (ProtobufCodec) Encode(msg *GameMessage) ([]byte, error) {
+	envelope, err := EnvelopeFromMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(envelope)
+}
+
+func (ProtobufCodec) Decode(data []byte, msg *GameMessage) error {
+	var envelope tictactoepb.Envelope
+	if err := proto.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+
+	decoded, err := MessageFromEnvelope(&envelope)
+	if err != nil {
+		return err
+	}
+	*msg = *decoded
+	return nil
+}
+
+// EnvelopeFromMessage converts a message to its protobuf envelope
+// Payloads with a typed form go as one; the rest as an untyped value
+func EnvelopeFromMessage(msg *GameMessage) (*tictactoepb.Envelope, error) {
+	envelope := &tictactoepb.Envelope{
+		V:        int32(msg.Version),
+		Type:     msg.Type,
+		GameId:   msg.GameID,
+		PlayerId: msg.PlayerID,
+		Seq:      int32(msg.Seq),
+	}
+	if setTypedPayload(envelope, msg) {
+		return envelope, nil
+	}
+
+	data, err := normalizeData(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Data, err = structpb.NewValue(data); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// MessageFromEnvelope converts a protobuf envelope back to a message
+func MessageFromEnvelope(envelope *tictactoepb.Envelope) (*GameMessage, error) {
+	var data interface{}
+	if envelope.Payload != nil {
+		data = typedPayloadData(envelope)
+	} else {
+		data = envelope.Data.AsInterface()
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GameMessage{
+		Version:  int(envelope.V),
+		Type:     envelope.Type,
+		Data:     payload,
+		GameID:   envelope.GameId,
+		PlayerID: envelope.PlayerId,
+		Seq:      int(envelope.Seq),
+	}, nil
+}
+
+// normalizeData converts an encoded payload into plain JSON values (maps, slices, strings, float64s)
+// so every codec exposes the same field names and shapes as the JSON protocol
+func normalizeData(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("normalize payload: %w", err)
+	}
+	return normalized, nil
+}
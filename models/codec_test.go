@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"tictactoe-server/proto/tictactoepb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// protobufRoundTrip encodes a message with the protobuf codec, checks which payload form it took and decodes it again
+func protobufRoundTrip(t *testing.T, msg *GameMessage, typed bool) (*GameMessage, []byte) {
+	t.Helper()
+	data, err := ProtobufCodec{}.Encode(msg)
+	if err != nil {
+		t.Fatalf("encode %s: %v", msg.Type, err)
+	}
+	var envelope tictactoepb.Envelope
+	if err := proto.Unmarshal(data, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if (envelope.Payload != nil) != typed || (envelope.Data != nil) == typed {
+		t.Errorf("%s: typed payload %v, data %v; want typed %v", msg.Type, envelope.Payload != nil, envelope.Data != nil, typed)
+	}
+
+	var decoded GameMessage
+	if err := (ProtobufCodec{}).Decode(data, &decoded); err != nil {
+		t.Fatalf("decode %s: %v", msg.Type, err)
+	}
+	return &decoded, data
+}
+
+func assertSamePayload(t *testing.T, want, got *GameMessage) {
+	t.Helper()
+	var wantData, gotData interface{}
+	json.Unmarshal(want.Data, &wantData)
+	json.Unmarshal(got.Data, &gotData)
+	wantJSON, _ := json.Marshal(wantData)
+	gotJSON, _ := json.Marshal(gotData)
+	if string(wantJSON) != string(gotJSON) || got.Type != want.Type || got.GameID != want.GameID || got.Seq != want.Seq {
+		t.Errorf("%s round trip:\n got %s (seq %d)\nwant %s (seq %d)", want.Type, gotJSON, got.Seq, wantJSON, want.Seq)
+	}
+}
+
+func TestProtobufTypedPayloadsRoundTrip(t *testing.T) {
+	position := 4
+	deadline := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, msg := range []*GameMessage{
+		NewGameMessageForGame(MSG_GAME_UPDATE, "g1", &GameStateView{GameID: "g1",
+			Board:       []Symbol{SYMBOL_X, "", "", "", SYMBOL_O, "", "", "", SYMBOL_X},
+			CurrentTurn: SYMBOL_O, Status: "playing", MySymbol: SYMBOL_X, OpponentName: "bob", Rated: true,
+			MoveCount: 3, Variant: VARIANT_STANDARD, Size: 3, WinLength: 3, Seq: 3,
+			TimeLeftMs: map[Symbol]int64{SYMBOL_X: 41000, SYMBOL_O: 39500}, MoveDeadline: &deadline}),
+		NewGameMessageForGame(MSG_GAME_DELTA, "g1", &GameDelta{GameID: "g1", Seq: 4,
+			Cells: []CellChange{{Position: 2, Symbol: SYMBOL_O}}, CurrentTurn: SYMBOL_X, Status: "playing", MoveCount: 4}),
+		NewGameMessage(MSG_MAKE_MOVE, MakeMovePayload{GameID: "g1", Position: &position, MoveID: "m1"}),
+		NewGameMessageForGame(MSG_MOVE_ACK, "g1", MoveAck{OK: true, MoveID: "m1", GameID: "g1", PlayerID: "p1",
+			Symbol: SYMBOL_X, Position: 4, MoveNumber: 5, Seq: 5}),
+		NewGameMessage(MSG_ERROR, ErrorPayload{Error: "Not your turn", Code: ERR_NOT_YOUR_TURN}),
+	} {
+		msg.Seq = 7
+		decoded, _ := protobufRoundTrip(t, msg, true)
+		assertSamePayload(t, msg, decoded)
+	}
+}
+
+func TestProtobufFallsBackToUntypedData(t *testing.T) {
+	for _, msg := range []*GameMessage{
+		NewGameMessage(MSG_LEADERBOARD, []*Player{NewPlayer("alice")}),
+		// Team views have no typed form
+		NewGameMessageForGame(MSG_GAME_UPDATE, "g1", &GameStateView{GameID: "g1", Board: make([]Symbol, 9),
+			Teams: []*Team{{Symbol: SYMBOL_X}}}),
+		// Fields the typed form doesn't know about
+		{Type: MSG_ERROR, Data: json.RawMessage(`{"error":"slow down","code":"rate_limited","retryAfterMs":500}`)},
+		// Moves without a position are left for validation to refuse
+		{Type: MSG_MAKE_MOVE, Data: json.RawMessage(`{"gameId":"g1"}`)},
+	} {
+		decoded, _ := protobufRoundTrip(t, msg, false)
+		assertSamePayload(t, msg, decoded)
+	}
+}
+
+func TestProtobufFramesSmallerThanJSON(t *testing.T) {
+	board := make([]Symbol, 9)
+	board[0], board[4] = SYMBOL_X, SYMBOL_O
+	for _, msg := range []*GameMessage{
+		NewGameMessageForGame(MSG_GAME_UPDATE, "3f6c1f0e-8f2a-4a57-9d0c-6d3b1c2a9e11", &GameStateView{
+			GameID: "3f6c1f0e-8f2a-4a57-9d0c-6d3b1c2a9e11", Board: board, CurrentTurn: SYMBOL_X, Status: "playing",
+			MySymbol: SYMBOL_X, OpponentName: "bob", IsMyTurn: true, Rated: true, MoveCount: 2,
+			Variant: VARIANT_STANDARD, Size: 3, WinLength: 3, Seq: 2}),
+		NewGameMessageForGame(MSG_GAME_DELTA, "3f6c1f0e-8f2a-4a57-9d0c-6d3b1c2a9e11", &GameDelta{
+			GameID: "3f6c1f0e-8f2a-4a57-9d0c-6d3b1c2a9e11", Seq: 3, Cells: []CellChange{{Position: 8, Symbol: SYMBOL_X}},
+			CurrentTurn: SYMBOL_O, Status: "playing", MoveCount: 3}),
+	} {
+		jsonFrame, err := JSONCodec{}.Encode(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, protobufFrame := protobufRoundTrip(t, msg, true)
+		if len(protobufFrame)*2 > len(jsonFrame) {
+			t.Errorf("%s: protobuf frame is %d bytes, want under half the %d of JSON", msg.Type, len(protobufFrame), len(jsonFrame))
+		}
+	}
+}
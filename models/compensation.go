@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Why the server dropped a game
+const (
+	DROPPED_SHUTDOWN     = "shutdown"     // The server shut down mid-game
+	DROPPED_SERVER_ERROR = "server_error" // Handling a message for the game failed
+)
+
+// Compensation is owed to a player whose game the server dropped: their next game comes first in the queue
+// and can't lower their rating
+type Compensation struct {
+	PlayerID  string    `json:"playerId"`
+	GameID    string    `json:"gameId"` // The dropped game
+	Reason    string    `json:"reason"` // DROPPED_SHUTDOWN or DROPPED_SERVER_ERROR
+	CreatedAt time.Time `json:"createdAt"`
+}
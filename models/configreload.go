@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Where a configuration reload was asked for
+const (
+	RELOAD_SIGNAL = "sighup" // The process received SIGHUP
+	RELOAD_ADMIN  = "admin"  // POST /admin/config/reload
+)
+
+// ConfigReload is an audit log entry of a configuration reload that changed something
+type ConfigReload struct {
+	Time    time.Time       `json:"time"`
+	Source  string          `json:"source"` // RELOAD_SIGNAL or RELOAD_ADMIN
+	Changes []SettingChange `json:"changes"`
+}
+
+// SettingChange is one setting a reload changed
+type SettingChange struct {
+	Setting string `json:"setting"` // The name of the configuration field
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// IsCorrespondence reports whether a game gives each move its own long deadline instead of expecting both players online
+func (g *Game) IsCorrespondence() bool {
+	return g.MoveTime > 0
+}
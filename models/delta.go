@@ -0,0 +1,47 @@
+package models
+
+// CellChange is one board cell that changed; an empty symbol means the cell was cleared by a takeback
+type CellChange struct {
+	Position int    `json:"position"`
+	Symbol   Symbol `json:"symbol"`
+}
+
+// GameDelta is the data of a game_delta message: what changed since update Seq-1
+// Deltas carry no per-player fields; clients derive whose turn it is from CurrentTurn
+type GameDelta struct {
+	GameID              string       `json:"gameId"`
+	Seq                 int          `json:"seq"`
+	Cells               []CellChange `json:"cells"`
+	CurrentTurn         Symbol       `json:"currentTurn"`
+	Status              string       `json:"status"`
+	Winner              Result       `json:"winner,omitempty"`
+	MoveCount           int          `json:"moveCount"`
+	TakebackRequestedBy string       `json:"takebackRequestedBy,omitempty"`
+	SwapPending         bool         `json:"swapPending,omitempty"`
+	SpectatorCount      int          `json:"spectatorCount"`
+}
+
+// BoardDelta lists the cells that differ between two boards; cells missing from before count as empty
+func BoardDelta(before, after []Symbol) []CellChange {
+	cells := make([]CellChange, 0, 1)
+	for position := range after {
+		var was Symbol
+		if position < len(before) {
+			was = before[position]
+		}
+		if was != after[position] {
+			cells = append(cells, CellChange{Position: position, Symbol: after[position]})
+		}
+	}
+	return cells
+}
+
+// ApplyBoard writes the delta's cell changes onto a board
+func (d *GameDelta) ApplyBoard(board []Symbol) {
+	for _, cell := range d.Cells {
+		if cell.Position < 0 || cell.Position >= len(board) {
+			continue
+		}
+		board[cell.Position] = cell.Symbol
+	}
+}
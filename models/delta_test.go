@@ -0,0 +1,19 @@
+package models
+
+import "testing"
+
+func TestBoardDeltaRoundTrip(t *testing.T) {
+	before := [9]Symbol{"X", "O", "", "", "X", "", "", "", ""}
+	after := [9]Symbol{"X", "", "", "", "X", "", "", "", "O"}
+
+	delta := GameDelta{Cells: BoardDelta(before[:], after[:])}
+	if len(delta.Cells) != 2 {
+		t.Fatalf("cells = %+v, want the takeback and the new move", delta.Cells)
+	}
+
+	board := before
+	delta.ApplyBoard(board[:])
+	if board != after {
+		t.Errorf("applied board = %v, want %v", board, after)
+	}
+}
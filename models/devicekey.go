@@ -0,0 +1,23 @@
+package models
+
+import "fmt"
+
+// Lengths of the device keys clients may connect with; the minimum is 128 bits in base64url, too many to guess
+const (
+	MIN_DEVICE_KEY_LENGTH = 22
+	MAX_DEVICE_KEY_LENGTH = 128
+)
+
+// ValidateDeviceKey checks a client-generated device key: MIN_DEVICE_KEY_LENGTH to MAX_DEVICE_KEY_LENGTH
+// letters, digits, '-' or '_', as a random value encoded in base64url or hex would be
+func ValidateDeviceKey(key string) error {
+	if len(key) < MIN_DEVICE_KEY_LENGTH || len(key) > MAX_DEVICE_KEY_LENGTH {
+		return fmt.Errorf("device key must be %d to %d characters", MIN_DEVICE_KEY_LENGTH, MAX_DEVICE_KEY_LENGTH)
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("device key may only contain letters, digits, '-' and '_'")
+		}
+	}
+	return nil
+}
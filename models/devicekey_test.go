@@ -0,0 +1,23 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDeviceKey(t *testing.T) {
+	for _, tt := range []struct {
+		key   string
+		valid bool
+	}{
+		{"Zm9vYmFyYmF6cXV4LXF1dXg", true},
+		{"0123456789abcdef0123456789abcdef", true},
+		{"short", false},
+		{strings.Repeat("a", MAX_DEVICE_KEY_LENGTH+1), false},
+		{"Zm9vYmFyYmF6cXV4+cXV1eA==", false},
+	} {
+		if err := ValidateDeviceKey(tt.key); (err == nil) != tt.valid {
+			t.Errorf("ValidateDeviceKey(%q) = %v, want valid %v", tt.key, err, tt.valid)
+		}
+	}
+}
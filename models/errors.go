@@ -0,0 +1,73 @@
+package models
+
+import "errors"
+
+// Error codes sent alongside the message in every error payload and refused move_ack
+// Clients should branch on these rather than on the wording of the message
+const (
+	ERR_MALFORMED_MESSAGE   = "malformed_message"   // Not a decodable message
+	ERR_UNKNOWN_MESSAGE     = "unknown_message"     // A message type the server doesn't handle
+	ERR_INVALID_PAYLOAD     = "invalid_payload"     // The payload is missing fields or has bad values
+	ERR_UNSUPPORTED_VERSION = "unsupported_version" // No protocol version in common
+	ERR_INVALID_NAME        = "invalid_name"
+	ERR_INVALID_DEVICE_KEY  = "invalid_device_key" // The deviceKey was ignored; the client connected without it
+
+	ERR_GAME_NOT_FOUND   = "game_not_found"
+	ERR_PLAYER_NOT_FOUND = "player_not_found"
+	ERR_MATCH_NOT_FOUND  = "match_not_found"
+	ERR_INVITE_NOT_FOUND = "invite_not_found" // Unknown, used or expired
+	ERR_LOBBY_NOT_FOUND  = "lobby_not_found"  // Unknown, started, closed or expired
+
+	ERR_CHALLENGE_NOT_FOUND = "challenge_not_found" // Unknown, or no longer open to the sender
+
+	ERR_NOT_PLAYING       = "not_playing" // The game isn't being played: not started, paused or over
+	ERR_NOT_IN_GAME       = "not_in_game" // The sender isn't playing, or watching, the game
+	ERR_NOT_YOUR_TURN     = "not_your_turn"
+	ERR_INVALID_POSITION  = "invalid_position"
+	ERR_POSITION_OCCUPIED = "occupied"
+	ERR_OUT_OF_TIME       = "out_of_time"
+	ERR_TEAM_GAME         = "team_game"      // Team games take moves as propose_move
+	ERR_MOVE_ID_REUSED    = "move_id_reused" // The moveId was already used for a different move
+	ERR_NOTHING_PENDING   = "nothing_pending"
+	ERR_ALREADY_PENDING   = "already_pending" // A takeback or proposed match is already waiting for an answer
+	ERR_ALREADY_IN_GAME   = "already_in_game"
+	ERR_PLAYER_OFFLINE    = "player_offline"
+
+	ERR_NOT_ALLOWED     = "not_allowed"   // The action isn't allowed in this game, mode or role
+	ERR_DISABLED        = "disabled"      // The feature is turned off on this server
+	ERR_LIMIT_REACHED   = "limit_reached" // A per-game allowance, such as hints, is used up
+	ERR_RATE_LIMITED    = "rate_limited"  // Too many requests; try again shortly
+	ERR_MUTED           = "muted"
+	ERR_MESSAGE_BLOCKED = "message_blocked" // Moderation refused the text
+	ERR_MAINTENANCE     = "maintenance"
+	ERR_SERVER_FULL     = "server_full"
+	ERR_REJECTED        = "rejected" // Any other reason
+)
+
+// Error is an error with a code clients can rely on
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// NewError creates an error with one of the ERR_ codes
+func NewError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// ErrorCode returns the code of the first coded error in err's chain, or ERR_REJECTED if there is none
+func ErrorCode(err error) string {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ERR_REJECTED
+}
+
+// ErrorPayload is the data of an error message
+type ErrorPayload struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
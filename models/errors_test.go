@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	notFound := NewError(ERR_GAME_NOT_FOUND, "game not found")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"coded", notFound, ERR_GAME_NOT_FOUND},
+		{"wrapped", fmt.Errorf("resync: %w", notFound), ERR_GAME_NOT_FOUND},
+		{"plain", errors.New("boom"), ERR_REJECTED},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(tt.err); got != tt.want {
+			t.Errorf("%s: ErrorCode = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodePayloadErrorCodes(t *testing.T) {
+	tests := []struct {
+		msg  GameMessage
+		want string
+	}{
+		{GameMessage{Type: "teleport"}, ERR_UNKNOWN_MESSAGE},
+		{GameMessage{Type: MSG_MAKE_MOVE, Data: []byte(`{"gameId": 7}`)}, ERR_INVALID_PAYLOAD},
+		{GameMessage{Type: MSG_MAKE_MOVE, Data: []byte(`{"gameId": "g"}`)}, ERR_INVALID_PAYLOAD},
+	}
+	for _, tt := range tests {
+		_, err := DecodePayload(&tt.msg)
+		if got := ErrorCode(err); got != tt.want {
+			t.Errorf("%s %s: code = %q (%v), want %q", tt.msg.Type, tt.msg.Data, got, err, tt.want)
+		}
+	}
+	if _, err := DecodePayload(&GameMessage{Type: "teleport"}); !errors.Is(err, ErrUnknownMessageType) {
+		t.Errorf("unknown type error = %v, want ErrUnknownMessageType", err)
+	}
+}
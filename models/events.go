@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Game event types recorded in a game's event log
+const (
+	EVENT_CREATED             = "created"
+	EVENT_PLAYER_JOINED       = "player_joined"
+	EVENT_MOVE                = "move"
+	EVENT_CHAT                = "chat"
+	EVENT_EMOTE               = "emote"
+	EVENT_TAKEBACK_REQUESTED  = "takeback_requested"
+	EVENT_TAKEBACK_ACCEPTED   = "takeback_accepted"
+	EVENT_TAKEBACK_DECLINED   = "takeback_declined"
+	EVENT_HINT                = "hint"
+	EVENT_SWAP_DECISION       = "swap_decision"
+	EVENT_PLAYER_DISCONNECTED = "player_disconnected"
+	EVENT_PLAYER_RECONNECTED  = "player_reconnected"
+	EVENT_FORFEIT             = "forfeit"
+	EVENT_ABANDONED           = "abandoned"
+	EVENT_EXPIRED             = "expired"
+	EVENT_ADMIN_ACTION        = "admin_action"
+	EVENT_FINISHED            = "finished"
+	EVENT_ABORTED             = "aborted"
+	EVENT_FLAGGED             = "flagged"
+)
+
+// GameEvent is an entry in a game's append-only event log
+type GameEvent struct {
+	Seq       int                    `json:"seq"` // Position in the game's log, starting at 1
+	GameID    string                 `json:"gameId"`
+	Type      string                 `json:"type"`
+	PlayerID  string                 `json:"playerId,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
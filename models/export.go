@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// PlayerExport is everything stored about a player, as handed to them on request
+type PlayerExport struct {
+	ExportedAt time.Time      `json:"exportedAt"`
+	Profile    *PlayerProfile `json:"profile"` // Includes the player's stats, rating history and badges
+	Games      []*GameRecord  `json:"games"`
+	Events     []GameEvent    `json:"events"` // Everything the player did in logged games, chat included
+
+	Blocked []BlockedPlayer `json:"blocked"`
+
+	Identities []*Identity `json:"identities"` // Accounts at OAuth providers the player logs in with
+}
+
+// DELETED_PLAYER_NAME replaces the name of a player whose account was deleted
+const DELETED_PLAYER_NAME = "Deleted Player"
+
+// SoftDelete marks the player deleted and purges what identifies them
+// The ID and results stay so the games they played keep adding up for their opponents
+func (p *Player) SoftDelete() {
+	p.Deleted = true
+	p.Name = DELETED_PLAYER_NAME
+	p.SessionToken = "" // No session can reclaim the player
+	p.Region = ""
+	p.LatencyMs = 0
+	p.LastSeen = time.Time{}
+}
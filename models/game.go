@@ -1,49 +1,150 @@
 package models
 
 import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
 	"time"
 
+	"tictactoe-server/rating"
+
 	"github.com/google/uuid"
 )
 
 // Player represents a player in the game
 type Player struct {
-	ID       string    `json:"id"`
-	Name     string    `json:"name"`
-	Symbol   string    `json:"symbol"` // "X" or "O"
-	Wins     int       `json:"wins"`
-	Losses   int       `json:"losses"`
-	Draws    int       `json:"draws"`
-	Rating   int       `json:"rating"`
-	LastSeen time.Time `json:"lastSeen"`
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Symbol        Symbol    `json:"symbol"` // SYMBOL_DELTA only in trio games
+	Wins          int       `json:"wins"`
+	Losses        int       `json:"losses"`
+	Draws         int       `json:"draws"`
+	CurrentStreak int       `json:"currentStreak"` // Consecutive wins, reset by a loss or draw
+	LongestStreak int       `json:"longestStreak"`
+	CasualWins    int       `json:"casualWins"`
+	CasualLosses  int       `json:"casualLosses"`
+	CasualDraws   int       `json:"casualDraws"`
+	SeasonGames   int       `json:"seasonGames"`   // Rated games played this season, placement games included
+	AnalyzedMoves int       `json:"analyzedMoves"` // Moves graded against perfect play, see GameAnalysis
+	OptimalMoves  int       `json:"optimalMoves"`
+	Mistakes      int       `json:"mistakes"`
+	Blunders      int       `json:"blunders"`
+	TimedMoves    int       `json:"timedMoves"` // Moves in finished games whose think time is counted in ThinkMs
+	ThinkMs       int64     `json:"thinkMs"`
+	LastSeen      time.Time `json:"lastSeen"`
+	SessionToken  string    `json:"-"` // Secret used to reclaim this player when reconnecting
+	IsBot         bool      `json:"isBot,omitempty"`
+	Personality   string    `json:"personality,omitempty"`
+	Region        string    `json:"region,omitempty"`    // Self-declared, e.g. "eu-west"; preferred when matchmaking
+	LatencyMs     int       `json:"latencyMs,omitempty"` // Measured round trip of the newest connection, rounded up; 0 until measured
+	Deleted       bool      `json:"deleted,omitempty"`   // The account was deleted; the player remains so their games stay consistent
+
+	Ratings map[string]int           `json:"ratings"`          // By rating pool, see RatingPool; pools the player hasn't been rated in are left out
+	Glicko  map[string]rating.Glicko `json:"glicko,omitempty"` // Glicko-2 parameters by rating pool, once rated with Glicko-2 there
 }
 
 // Game represents a Tic-Tac-Toe game
 type Game struct {
 	ID          string     `json:"id"`
-	Board       [9]string  `json:"board"` // 0-8 positions, empty string means empty cell
-	PlayerX     *Player    `json:"playerX"`
-	PlayerO     *Player    `json:"playerO"`
-	CurrentTurn string     `json:"currentTurn"` // "X" or "O"
-	Status      string     `json:"status"`      // "waiting", "playing", "finished"
-	Winner      string     `json:"winner"`      // "X", "O", "draw", or ""
+	Board       []Symbol   `json:"board"`       // Size*Size cells row by row, SYMBOL_NONE means empty cell
+	Size        int        `json:"size"`        // Cells per row and column
+	WinLength   int        `json:"winLength"`   // Marks in a row needed to complete a line
+	Players     []*Player  `json:"players"`     // Seated players in turn order; Players[i] plays Symbols[i]
+	CurrentTurn Symbol     `json:"currentTurn"` // The side to move
+	Status      string     `json:"status"`      // "waiting", "playing", "paused", "finished"
+	Winner      Result     `json:"winner"`      // A symbol's win, RESULT_DRAW, or RESULT_NONE
 	StartTime   time.Time  `json:"startTime"`
 	EndTime     *time.Time `json:"endTime,omitempty"`
+
+	Rated                bool         `json:"rated"`                          // Rated games affect ratings and allow no takebacks
+	FirstMoverID         string       `json:"firstMoverId"`                   // Player who was seated as X and moved first
+	Moves                []MoveRecord `json:"moves"`                          // Moves in the order they were played
+	DisconnectedPlayerID string       `json:"disconnectedPlayerId,omitempty"` // Set while the game is paused
+	TakebackRequestedBy  string       `json:"takebackRequestedBy,omitempty"`  // Player waiting for a takeback answer
+	Flags                []string     `json:"flags,omitempty"`                // Anti-cheat findings; flagged games are unrated
+	Teams                []*Team      `json:"teams,omitempty"`                // Team games only, X first; the seated players move for their team
+	PieRule              bool         `json:"pieRule,omitempty"`              // O may swap sides instead of replying to X's first move
+	Variant              string       `json:"variant"`                        // Picks the engine's rule set: VARIANT_STANDARD, VARIANT_MISERE or a registered one
+	SwapPending          bool         `json:"swapPending,omitempty"`          // Set while O decides whether to swap
+	Eliminated           []Symbol     `json:"eliminated,omitempty"`           // Trio games only: symbols knocked out, skipped in turn order
+
+	RatingProtected []string `json:"ratingProtected,omitempty"` // Players whose rating this game can't lower, see Compensation
+
+	ReminderAfter time.Duration `json:"-"` // How long a turn may run before the player to move is reminded; 0 sends no reminders
+
+	MoveTime     time.Duration `json:"-"`                      // Correspondence games only: the time allowed for each move
+	MoveDeadline *time.Time    `json:"moveDeadline,omitempty"` // Correspondence games only: when the player to move forfeits
+
+	Settings *GameSettings `json:"settings,omitempty"` // Games from lobbies and challenges only: the rules the players chose
+
+	TimeLeft      map[Symbol]time.Duration `json:"timeLeft,omitempty"` // Blitz games only: each symbol's time, not counting the running turn
+	TurnStartedAt time.Time                `json:"turnStartedAt"`      // When the player to move's turn began or last resumed; blitz clocks charge from it
+	TurnThought   time.Duration            `json:"-"`                  // Time the player to move spent on their turn before the game was last paused
+}
+
+// Anti-cheat flags
+const (
+	FLAG_SAME_IP    = "same_ip"    // Both players connected from the same IP address
+	FLAG_FAST_MOVES = "fast_moves" // A player replied faster than a human plausibly could, repeatedly
+)
+
+// MoveRecord is an entry in a game's move log
+type MoveRecord struct {
+	PlayerID  string    `json:"playerId"`
+	Symbol    Symbol    `json:"symbol"`
+	Position  int       `json:"position"`
+	Timestamp time.Time `json:"timestamp"`
+	ThinkMs   int64     `json:"thinkMs"` // How long the player took over the move, not counting pauses
+}
+
+// MoveAck answers a make_move: as move_ack on protocol version 4, or move_accepted for earlier clients that sent a moveId
+// Earlier clients get refusals as plain errors, so move_accepted always reports success
+type MoveAck struct {
+	OK         bool   `json:"ok"`
+	MoveID     string `json:"moveId"`
+	GameID     string `json:"gameId"`
+	PlayerID   string `json:"playerId"`
+	Symbol     Symbol `json:"symbol,omitempty"`
+	Position   int    `json:"position"`
+	MoveNumber int    `json:"moveNumber,omitempty"` // 1 for the game's first move
+	Seq        int    `json:"seq,omitempty"`        // The game_update or game_delta that first shows the move
+	Code       string `json:"code,omitempty"`       // Refusals only, one of the ERR_ codes
+	Error      string `json:"error,omitempty"`      // Refusals only, for people
 }
 
 // Move represents a player's move
 type Move struct {
 	GameID   string `json:"gameId"`
 	PlayerID string `json:"playerId"`
-	Position int    `json:"position"` // 0-8
+	Position int    `json:"position"` // 0-8, or 0-24 on a 5x5 board
 }
 
 // GameMessage represents WebSocket messages
 type GameMessage struct {
-	Type     string      `json:"type"`
-	Data     interface{} `json:"data"`
-	GameID   string      `json:"gameId,omitempty"`
-	PlayerID string      `json:"playerId,omitempty"`
+	Version  int             `json:"v,omitempty"` // Protocol version, omitted for legacy clients
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data"` // Encoded payload, see payloads.go for inbound types
+	GameID   string          `json:"gameId,omitempty"`
+	PlayerID string          `json:"playerId,omitempty"`
+	Seq      int             `json:"seq,omitempty"` // Position among the messages pushed to the receiving player; replies to requests have none
+}
+
+// NewGameMessage creates a message with the payload encoded as its data
+func NewGameMessage(msgType string, payload interface{}) *GameMessage {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode %s payload: %v", msgType, err)
+		data = []byte("null")
+	}
+	return &GameMessage{Type: msgType, Data: data}
+}
+
+// NewGameMessageForGame creates a message tied to a specific game
+func NewGameMessageForGame(msgType string, gameID string, payload interface{}) *GameMessage {
+	msg := NewGameMessage(msgType, payload)
+	msg.GameID = gameID
+	return msg
 }
 
 // MessageTypes
@@ -57,32 +158,218 @@ const (
 	MSG_ERROR         = "error"
 	MSG_LEADERBOARD   = "leaderboard"
 	MSG_PLAYER_UPDATE = "player_update"
+	MSG_GET_PROFILE   = "get_profile"
+	MSG_PROFILE       = "profile"
+	MSG_SESSION       = "session"
+	MSG_ANNOUNCEMENT  = "announcement"
+	MSG_HELLO         = "hello"
+
+	MSG_SPECTATE_GAME       = "spectate_game"
+	MSG_STOP_SPECTATING     = "stop_spectating"
+	MSG_SPECTATOR_CHAT      = "spectator_chat"
+	MSG_MUTE_SPECTATOR_CHAT = "mute_spectator_chat"
+
+	MSG_REQUEST_TAKEBACK   = "request_takeback"
+	MSG_ACCEPT_TAKEBACK    = "accept_takeback"
+	MSG_DECLINE_TAKEBACK   = "decline_takeback"
+	MSG_TAKEBACK_REQUESTED = "takeback_requested"
+	MSG_TAKEBACK_DECLINED  = "takeback_declined"
+	MSG_QUEUE_JOINED       = "queue_joined"
+	MSG_DISCONNECT         = "disconnect"
+
+	MSG_OPPONENT_DISCONNECTED = "opponent_disconnected"
+	MSG_OPPONENT_RECONNECTED  = "opponent_reconnected"
+	MSG_SERVER_FULL           = "server_full"
+	MSG_SEASON_STARTED        = "season_started"
+	MSG_ACHIEVEMENT_UNLOCKED  = "achievement_unlocked"
+	MSG_EMOTE                 = "emote"
+	MSG_GAME_DELTA            = "game_delta"
+	MSG_RESYNC                = "resync"
+	MSG_REQUEST_HINT          = "request_hint"
+	MSG_HINT                  = "hint"
+	MSG_INVITE_STATUS         = "invite_status"
+	MSG_QUEUE_REMOVED         = "queue_removed"
+	MSG_LOBBY_STATS           = "lobby_stats"
+	MSG_PROPOSE_MOVE          = "propose_move"
+	MSG_MOVE_PROPOSED         = "move_proposed"
+	MSG_SWAP_DECISION         = "swap_decision"
+	MSG_CLOCK_UPDATE          = "clock_update"
+	MSG_GET_RATING_HISTORY    = "get_rating_history"
+	MSG_RATING_HISTORY        = "rating_history"
+	MSG_MATCH_PROPOSED        = "match_proposed"
+	MSG_ACCEPT_MATCH          = "accept_match"
+	MSG_DECLINE_MATCH         = "decline_match"
+	MSG_MATCH_ACCEPTED        = "match_accepted"
+	MSG_MATCH_CANCELLED       = "match_cancelled"
+	MSG_MOVE_ACCEPTED         = "move_accepted"
+	MSG_MOVE_ACK              = "move_ack"
+	MSG_REPLAY_GAME           = "replay_game"
+	MSG_REPLAY_NEXT           = "replay_next"
+	MSG_REPLAY_STARTED        = "replay_started"
+	MSG_REPLAY_MOVE           = "replay_move"
+	MSG_REPLAY_FINISHED       = "replay_finished"
+	MSG_GET_PUZZLE            = "get_puzzle"
+	MSG_PUZZLE                = "puzzle"
+	MSG_SOLVE_PUZZLE          = "solve_puzzle"
+	MSG_PUZZLE_RESULT         = "puzzle_result"
+	MSG_PLAY_BOT              = "play_bot"
+	MSG_REPORT_PLAYER         = "report_player"
+	MSG_REPORT_FILED          = "report_filed"
+	MSG_QUEUE_PENALTY         = "queue_penalty"
+	MSG_TURN_REMINDER         = "turn_reminder"
+	MSG_CREATE_LOBBY          = "create_lobby"
+	MSG_LOBBY_CREATED         = "lobby_created"
+	MSG_LIST_LOBBIES          = "list_lobbies"
+	MSG_LOBBIES               = "lobbies"
+	MSG_JOIN_LOBBY            = "join_lobby"
+	MSG_CLOSE_LOBBY           = "close_lobby"
+	MSG_LOBBY_CLOSED          = "lobby_closed"
+	MSG_CHALLENGE             = "challenge"
+	MSG_PROPOSE_SETTINGS      = "propose_settings"
+	MSG_ACCEPT_SETTINGS       = "accept_settings"
+	MSG_DECLINE_CHALLENGE     = "decline_challenge"
+	MSG_CHALLENGE_PROPOSED    = "challenge_proposed"
+	MSG_CHALLENGE_CLOSED      = "challenge_closed"
+	MSG_GAME_EXPIRING         = "game_expiring"
+	MSG_MAINTENANCE           = "maintenance"
+	MSG_BLOCK_PLAYER          = "block_player"
+	MSG_UNBLOCK_PLAYER        = "unblock_player"
+	MSG_GET_BLOCK_LIST        = "get_block_list"
+	MSG_BLOCK_LIST            = "block_list"
+)
+
+// Limits reported in server_full messages
+const (
+	FULL_CONNECTIONS = "connections" // Connection cap reached; the client should retry later
+	FULL_GAMES       = "games"       // Active game cap reached; the player stays queued until a game ends
+	FULL_QUEUE       = "queue"       // Queue is full; the player was not queued
+)
+
+// Reasons given in queue_removed messages
+const (
+	QUEUE_REMOVED_IDLE         = "idle"         // The player sent nothing for the configured period
+	QUEUE_REMOVED_UNRESPONSIVE = "unresponsive" // The connection stopped answering pings
+	QUEUE_REMOVED_BANNED       = "banned"       // Verified reports banned the player from matchmaking
+	QUEUE_REMOVED_DRAINING     = "draining"     // The server is draining; the player should reconnect and queue elsewhere
+	QUEUE_REMOVED_MAINTENANCE  = "maintenance"  // Matchmaking stopped ahead of a maintenance window
 )
 
-// GameStatus constants
+// GameStatus constants; see state.go for the transitions allowed between them
 const (
-	STATUS_WAITING  = "waiting"
-	STATUS_PLAYING  = "playing"
-	STATUS_FINISHED = "finished"
+	STATUS_WAITING   = "waiting"
+	STATUS_PLAYING   = "playing"
+	STATUS_PAUSED    = "paused"
+	STATUS_FINISHED  = "finished"
+	STATUS_ABORTED   = "aborted"   // Cancelled without a result, e.g. by an admin
+	STATUS_ABANDONED = "abandoned" // Voided because every player left, see ABANDONED_GAMES_VOID
 )
 
+// Game modes
+const (
+	MODE_RATED  = "rated"  // Affects ratings and the leaderboard
+	MODE_CASUAL = "casual" // Tallied separately, never rated
+	MODE_TEAM   = "team"   // Two players per side agree on each move; tallied as casual
+	MODE_MISERE = "misere" // Misère rules; tallied as casual
+	MODE_TRIO   = "trio"   // Three players on a 5x5 board; tallied as casual
+	MODE_BLITZ  = "blitz"  // Each player has a fixed total time for the whole game; tallied as casual
+
+	MODE_CORRESPONDENCE = "correspondence" // Days per move; the game waits for players who aren't connected; tallied as casual
+)
+
+// Game variants
+const (
+	VARIANT_STANDARD = "standard" // Three in a row wins
+	VARIANT_MISERE   = "misere"   // Three in a row loses
+)
+
+// DEFAULT_RATING is the rating new players start with
+const DEFAULT_RATING = 1000
+
 // NewGame creates a new game instance
 func NewGame() *Game {
 	return &Game{
 		ID:          uuid.New().String(),
-		Board:       [9]string{},
-		CurrentTurn: "X",
+		Board:       make([]Symbol, 9),
+		Size:        3,
+		WinLength:   3,
+		Moves:       make([]MoveRecord, 0),
+		CurrentTurn: SYMBOL_X,
 		Status:      STATUS_WAITING,
+		Variant:     VARIANT_STANDARD,
 		StartTime:   time.Now(),
 	}
 }
 
+// PlayerX returns the player seated as X, or nil
+func (g *Game) PlayerX() *Player {
+	return g.seat(0)
+}
+
+// PlayerO returns the player seated as O, or nil
+func (g *Game) PlayerO() *Player {
+	return g.seat(1)
+}
+
+// seat returns the player in a seat, or nil if it is empty
+func (g *Game) seat(index int) *Player {
+	if index >= len(g.Players) {
+		return nil
+	}
+	return g.Players[index]
+}
+
+// SeatSymbols returns the symbols played in a game, in turn order
+func (g *Game) SeatSymbols() []Symbol {
+	return Symbols[:len(g.Players)]
+}
+
+// IsClassic reports whether a game is two players on a 3x3 board, which analysis, hints and bots need
+func (g *Game) IsClassic() bool {
+	return g.Size == 3 && len(g.Players) == 2
+}
+
+// IsRatingProtected reports whether a player's rating can't drop in this game
+func (g *Game) IsRatingProtected(playerID string) bool {
+	return slices.Contains(g.RatingProtected, playerID)
+}
+
+// ClassicBoard returns a 3x3 board as the fixed-size array the solver works on
+func (g *Game) ClassicBoard() [9]Symbol {
+	var board [9]Symbol
+	copy(board[:], g.Board)
+	return board
+}
+
+// NewBotPlayer creates a server-controlled bot opponent
+func NewBotPlayer() *Player {
+	bot := NewPlayer("Bot")
+	bot.IsBot = true
+	return bot
+}
+
+// NewPracticeBot creates a bot opponent with a personality, named after it
+func NewPracticeBot(personality string) *Player {
+	bot := NewBotPlayer()
+	bot.Name = "Bot (" + strings.ReplaceAll(personality, "_", " ") + ")"
+	bot.Personality = personality
+	return bot
+}
+
 // NewPlayer creates a new player
 func NewPlayer(name string) *Player {
 	return &Player{
-		ID:       uuid.New().String(),
-		Name:     name,
-		Rating:   1000, // Starting rating
-		LastSeen: time.Now(),
+		ID:           uuid.New().String(),
+		Name:         name,
+		Ratings:      map[string]int{RATING_POOL_STANDARD: DEFAULT_RATING},
+		LastSeen:     time.Now(),
+		SessionToken: uuid.New().String(),
 	}
 }
+
+// LobbyStats is the lobby dashboard pushed in lobby_stats messages
+type LobbyStats struct {
+	OnlinePlayers int `json:"onlinePlayers"`
+	QueuedPlayers int `json:"queuedPlayers"`
+	ActiveGames   int `json:"activeGames"`
+	GamesToday    int `json:"gamesToday"` // Games finished with a result since midnight UTC
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// HeatmapReport is the latest aggregation of where finished games were played, one heatmap per board
+type HeatmapReport struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Boards      []*MoveHeatmap `json:"boards"` // Smallest board first, then by variant
+}
+
+// MoveHeatmap aggregates the decided games played on one board size and variant
+type MoveHeatmap struct {
+	Size      int             `json:"size"`
+	Variant   string          `json:"variant"`
+	Games     int             `json:"games"`
+	Draws     int             `json:"draws"`
+	DrawRate  float64         `json:"drawRate"`  // Share of the games that were drawn, 0-1
+	Positions []PositionStats `json:"positions"` // One per cell, row by row
+}
+
+// PositionStats is how often a cell was played, and how games that opened there went
+type PositionStats struct {
+	Position         int     `json:"position"`
+	Played           int     `json:"played"`           // Games in which a mark was placed here
+	PlayRate         float64 `json:"playRate"`         // Share of the games in which a mark was placed here, 0-1
+	FirstMoves       int     `json:"firstMoves"`       // Games that opened here
+	FirstMoveWins    int     `json:"firstMoveWins"`    // Of those, games the opening player won
+	FirstMoveDraws   int     `json:"firstMoveDraws"`   // Of those, games that were drawn
+	FirstMoveWinRate float64 `json:"firstMoveWinRate"` // FirstMoveWins / FirstMoves, 0 when nobody opened here
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Identity links a player to their account at an OAuth login provider
+// Identities are kept apart from the player, so one player may log in through several providers
+type Identity struct {
+	Provider string    `json:"provider"`        // One of config.OAUTH_PROVIDERS
+	Subject  string    `json:"subject"`         // The account's ID at the provider
+	PlayerID string    `json:"playerId"`        // The player the account logs in as
+	Name     string    `json:"name"`            // The account's name at the provider
+	Email    string    `json:"email,omitempty"` // Only from providers that share it
+	LinkedAt time.Time `json:"linkedAt"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Invite statuses reported to the creator in invite_status messages
+const (
+	INVITE_PENDING  = "pending"  // Created and waiting for someone to follow the link
+	INVITE_ACCEPTED = "accepted" // Someone followed the link and the game has started
+	INVITE_EXPIRED  = "expired"  // Nobody followed the link in time
+	INVITE_REPLACED = "replaced" // The creator made a newer invite, which is the only one still valid
+)
+
+// Invite is a one-time link that starts a game against its creator
+type Invite struct {
+	Token     string    `json:"token"` // URL-safe; connect with ?invite=TOKEN
+	URL       string    `json:"url"`
+	InviterID string    `json:"inviterId"`
+	Mode      string    `json:"mode"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// InviteStatus tells an invite's creator what became of it
+type InviteStatus struct {
+	Token       string `json:"token"`
+	Status      string `json:"status"`
+	GameID      string `json:"gameId,omitempty"`      // Set once accepted
+	InviteeName string `json:"inviteeName,omitempty"` // Set once accepted
+}
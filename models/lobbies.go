@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lobby time controls
+const (
+	TIME_NONE           = "none"           // No clock, as in casual games
+	TIME_BLITZ          = "blitz"          // Each player has Seconds for the whole game
+	TIME_CORRESPONDENCE = "correspondence" // Each move may take Seconds
+)
+
+// MAX_LOBBY_BOARD_SIZE is the largest board a lobby may use, as large as moves are accepted for
+const MAX_LOBBY_BOARD_SIZE = TRIO_BOARD_SIZE
+
+// Limits on lobby timers, in seconds
+const (
+	MIN_LOBBY_BLITZ_SECONDS          = 10
+	MAX_LOBBY_BLITZ_SECONDS          = 600
+	MIN_LOBBY_CORRESPONDENCE_SECONDS = 3600
+	MAX_LOBBY_CORRESPONDENCE_SECONDS = 14 * 24 * 3600
+)
+
+// Reasons sent in lobby_closed messages
+const (
+	LOBBY_STARTED   = "started"   // Someone joined and the game began
+	LOBBY_CLOSED    = "closed"    // The host closed it
+	LOBBY_EXPIRED   = "expired"   // Nobody joined in time
+	LOBBY_REPLACED  = "replaced"  // The host opened a newer lobby
+	LOBBY_HOST_LEFT = "host_left" // The host disconnected
+)
+
+// LobbyRules are the rules a lobby's game is played by; it is also the data of a create_lobby message
+type LobbyRules struct {
+	Variant     string `json:"variant"`           // VARIANT_STANDARD (the default) or VARIANT_MISERE
+	Size        int    `json:"size"`              // Board size, 3 (the default) to 5
+	WinLength   int    `json:"winLength"`         // Marks in a row needed, 3 to Size; defaults to 3 on a 3x3 board and 4 otherwise
+	TimeControl string `json:"timeControl"`       // TIME_NONE (the default), TIME_BLITZ or TIME_CORRESPONDENCE
+	Seconds     int    `json:"seconds,omitempty"` // The blitz or per-move time; 0 uses the server's default
+	Rated       bool   `json:"rated"`             // Only 3x3 games can be rated; each variant and time control separately
+}
+
+func (r *LobbyRules) Validate() error {
+	switch r.Variant {
+	case "":
+		r.Variant = VARIANT_STANDARD
+	case VARIANT_STANDARD, VARIANT_MISERE:
+	default:
+		return fmt.Errorf("variant must be %q or %q", VARIANT_STANDARD, VARIANT_MISERE)
+	}
+
+	if r.Size == 0 {
+		r.Size = 3
+	}
+	if r.Size < 3 || r.Size > MAX_LOBBY_BOARD_SIZE {
+		return fmt.Errorf("size must be between 3 and %d", MAX_LOBBY_BOARD_SIZE)
+	}
+	if r.WinLength == 0 {
+		r.WinLength = min(r.Size, 4)
+	}
+	if r.WinLength < 3 || r.WinLength > r.Size {
+		return errors.New("winLength must be between 3 and size")
+	}
+
+	if r.TimeControl == "" {
+		r.TimeControl = TIME_NONE
+	}
+	switch r.TimeControl {
+	case TIME_NONE:
+		if r.Seconds != 0 {
+			return errors.New("seconds needs a blitz or correspondence timeControl")
+		}
+	case TIME_BLITZ:
+		if r.Seconds != 0 && (r.Seconds < MIN_LOBBY_BLITZ_SECONDS || r.Seconds > MAX_LOBBY_BLITZ_SECONDS) {
+			return fmt.Errorf("blitz seconds must be between %d and %d", MIN_LOBBY_BLITZ_SECONDS, MAX_LOBBY_BLITZ_SECONDS)
+		}
+	case TIME_CORRESPONDENCE:
+		if r.Seconds != 0 && (r.Seconds < MIN_LOBBY_CORRESPONDENCE_SECONDS || r.Seconds > MAX_LOBBY_CORRESPONDENCE_SECONDS) {
+			return fmt.Errorf("correspondence seconds must be between %d and %d", MIN_LOBBY_CORRESPONDENCE_SECONDS, MAX_LOBBY_CORRESPONDENCE_SECONDS)
+		}
+	default:
+		return fmt.Errorf("timeControl must be %q, %q or %q", TIME_NONE, TIME_BLITZ, TIME_CORRESPONDENCE)
+	}
+
+	if r.Rated && r.Size != 3 {
+		return errors.New("only 3x3 games can be rated")
+	}
+	return nil
+}
+
+// Lobby is an open game waiting for an opponent, as listed in lobbies messages
+type Lobby struct {
+	ID         string     `json:"id"`
+	HostID     string     `json:"hostId"`
+	HostName   string     `json:"hostName"`
+	HostRating int        `json:"hostRating"`
+	Rules      LobbyRules `json:"rules"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+}
+
+// LobbyPayload is the data of join_lobby and close_lobby messages
+type LobbyPayload struct {
+	LobbyID string `json:"lobbyId"`
+}
+
+func (p *LobbyPayload) Validate() error {
+	if p.LobbyID == "" {
+		return errors.New("lobbyId is required")
+	}
+	return nil
+}
+
+// LobbyClosed is the data of a lobby_closed message sent to the host
+type LobbyClosed struct {
+	LobbyID string `json:"lobbyId"`
+	Reason  string `json:"reason"`
+	GameID  string `json:"gameId,omitempty"` // Set when the game started
+}
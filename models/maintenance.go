@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Phases of a maintenance window, announced in maintenance messages as each begins
+const (
+	MAINTENANCE_SCHEDULED = "scheduled" // Announced; matchmaking is still open
+	MAINTENANCE_PREPARING = "preparing" // Matchmaking has stopped and games in progress are finishing
+	MAINTENANCE_ACTIVE    = "active"    // New connections are refused until the window ends
+	MAINTENANCE_ENDED     = "ended"     // The window ended or an admin turned maintenance off
+	MAINTENANCE_CANCELLED = "cancelled" // An admin called the window off before it started
+)
+
+// MaintenanceWindow is a period the server is down for maintenance, and the body of maintenance messages
+type MaintenanceWindow struct {
+	Status             string     `json:"status"`             // One of the MAINTENANCE_ phases
+	MatchmakingStopsAt time.Time  `json:"matchmakingStopsAt"` // When new games stop, ahead of StartsAt
+	StartsAt           time.Time  `json:"startsAt"`
+	EndsAt             *time.Time `json:"endsAt"` // Expected end, for countdowns; nil until an admin turns maintenance off
+	Message            string     `json:"message,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Reasons given in match_cancelled messages
+const (
+	MATCH_CANCELLED_DECLINED = "declined" // A player declined, left the queue or disconnected
+	MATCH_CANCELLED_TIMEOUT  = "timeout"  // Not every player accepted in time
+)
+
+// MatchProposal is the data of a match_proposed message: a match every player must accept before its game starts
+type MatchProposal struct {
+	MatchID   string          `json:"matchId"`
+	Mode      string          `json:"mode"`
+	Players   []MatchedPlayer `json:"players"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+	TimeoutMs int64           `json:"timeoutMs"` // Time left to answer when the proposal was sent
+}
+
+// MatchedPlayer is a player in a proposed match
+type MatchedPlayer struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Rating int    `json:"rating"`
+}
+
+// MatchCancelled is the data of a match_cancelled message
+type MatchCancelled struct {
+	MatchID  string `json:"matchId"`
+	Reason   string `json:"reason"`
+	Requeued bool   `json:"requeued"` // Whether this player went back to the front of the queue
+}
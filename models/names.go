@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxNameLength is the longest player name accepted, in characters
+const MaxNameLength = 20
+
+// reservedNames can't be taken by players because they would impersonate the server, staff or bots
+var reservedNames = map[string]bool{
+	"admin":          true,
+	"administrator":  true,
+	"moderator":      true,
+	"server":         true,
+	"system":         true,
+	"bot":            true,
+	"deleted player": true,
+}
+
+// ValidateName checks a trimmed player name: 1 to MaxNameLength letters, digits,
+// spaces, '_', '-' or '.', starting with a letter or digit, and not reserved
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return fmt.Errorf("name must be at most %d characters", MaxNameLength)
+	}
+
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+		case i > 0 && (r == ' ' || r == '_' || r == '-' || r == '.'):
+		default:
+			return fmt.Errorf("name may only contain letters, digits, spaces, '_', '-' and '.', starting with a letter or digit")
+		}
+	}
+
+	if reservedNames[strings.ToLower(name)] {
+		return fmt.Errorf("name %q is reserved", name)
+	}
+	return nil
+}
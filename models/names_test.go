@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"alice", true},
+		{"Zoë_42", true},
+		{"j.r. smith-jones", true},
+		{strings.Repeat("a", MaxNameLength), true},
+		{strings.Repeat("a", MaxNameLength+1), false},
+		{"", false},
+		{"_alice", false},
+		{"bad\x00name", false},
+		{"tab\there", false},
+		{"<script>", false},
+		{"Admin", false},
+		{"BOT", false},
+		{"admin2", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("ValidateName(%q) = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnknownMessageType is returned for inbound message types the server doesn't handle
+var ErrUnknownMessageType = NewError(ERR_UNKNOWN_MESSAGE, "unknown message type")
+
+// Payload is implemented by every inbound message payload
+type Payload interface {
+	Validate() error
+}
+
+// EmptyPayload is used by messages that carry no data
+type EmptyPayload struct{}
+
+func (p *EmptyPayload) Validate() error { return nil }
+
+// MaxMoveIDLength is the longest client move ID accepted
+const MaxMoveIDLength = 64
+
+// MakeMovePayload is the data of a make_move message
+// MoveID is optional and chosen by the client; a retry with the same ID is acknowledged again instead of replayed
+type MakeMovePayload struct {
+	GameID   string `json:"gameId"`
+	Position *int   `json:"position"`
+	MoveID   string `json:"moveId,omitempty"`
+}
+
+func (p *MakeMovePayload) Validate() error {
+	if p.GameID == "" {
+		return errors.New("gameId is required")
+	}
+	if p.Position == nil {
+		return errors.New("position is required")
+	}
+	// The game's own board size is checked when the move is played
+	if *p.Position < 0 || *p.Position >= TRIO_BOARD_SIZE*TRIO_BOARD_SIZE {
+		return errors.New("position must be between 0 and 24")
+	}
+	if len(p.MoveID) > MaxMoveIDLength {
+		return fmt.Errorf("moveId must be at most %d characters", MaxMoveIDLength)
+	}
+	return nil
+}
+
+// JoinQueuePayload is the data of a join_queue message; Mode defaults to rated
+type JoinQueuePayload struct {
+	Mode string `json:"mode"`
+}
+
+func (p *JoinQueuePayload) Validate() error {
+	switch p.Mode {
+	case "":
+		p.Mode = MODE_RATED
+	case MODE_RATED, MODE_CASUAL, MODE_TEAM, MODE_MISERE, MODE_TRIO, MODE_BLITZ, MODE_CORRESPONDENCE:
+	default:
+		return fmt.Errorf("mode must be %q, %q, %q, %q, %q, %q or %q", MODE_RATED, MODE_CASUAL, MODE_TEAM, MODE_MISERE, MODE_TRIO,
+			MODE_BLITZ, MODE_CORRESPONDENCE)
+	}
+	return nil
+}
+
+// LeaderboardPayload is the data of a leaderboard message, filtering it to one rating pool
+type LeaderboardPayload struct {
+	Variant string `json:"variant"` // VARIANT_STANDARD (the default) or VARIANT_MISERE
+	Mode    string `json:"mode"`    // MODE_RATED (the default), MODE_BLITZ or MODE_CORRESPONDENCE
+}
+
+func (p *LeaderboardPayload) Validate() error {
+	switch p.Variant {
+	case "":
+		p.Variant = VARIANT_STANDARD
+	case VARIANT_STANDARD, VARIANT_MISERE:
+	default:
+		return fmt.Errorf("variant must be %q or %q", VARIANT_STANDARD, VARIANT_MISERE)
+	}
+	switch p.Mode {
+	case "":
+		p.Mode = MODE_RATED
+	case MODE_RATED, MODE_BLITZ, MODE_CORRESPONDENCE:
+	default:
+		return fmt.Errorf("mode must be %q, %q or %q", MODE_RATED, MODE_BLITZ, MODE_CORRESPONDENCE)
+	}
+	return nil
+}
+
+// Pool returns the key of the rating pool the leaderboard is filtered to
+func (p *LeaderboardPayload) Pool() string {
+	return RatingPool(p.Variant, p.Mode)
+}
+
+// GetProfilePayload is the data of a get_profile message; an empty PlayerID means the sender
+type GetProfilePayload struct {
+	PlayerID string `json:"playerId"`
+}
+
+func (p *GetProfilePayload) Validate() error { return nil }
+
+// RatingHistoryPayload is the data of a get_rating_history message; an empty PlayerID means the sender
+type RatingHistoryPayload struct {
+	PlayerID string `json:"playerId"`
+	Points   int    `json:"points"` // Most points wanted; 0 means DEFAULT_RATING_HISTORY_POINTS
+}
+
+func (p *RatingHistoryPayload) Validate() error {
+	if p.Points == 0 {
+		p.Points = DEFAULT_RATING_HISTORY_POINTS
+	}
+	if p.Points < 2 || p.Points > MAX_RATING_HISTORY_POINTS {
+		return fmt.Errorf("points must be between 2 and %d", MAX_RATING_HISTORY_POINTS)
+	}
+	return nil
+}
+
+// MaxChatLength is the longest chat message accepted
+const MaxChatLength = 200
+
+// GamePayload is the data of messages that only reference a game
+type GamePayload struct {
+	GameID string `json:"gameId"`
+}
+
+func (p *GamePayload) Validate() error {
+	if p.GameID == "" {
+		return errors.New("gameId is required")
+	}
+	return nil
+}
+
+// ChatPayload is the data of a chat message sent to a game's chat channel
+type ChatPayload struct {
+	GameID string `json:"gameId"`
+	Text   string `json:"text"`
+}
+
+func (p *ChatPayload) Validate() error {
+	if p.GameID == "" {
+		return errors.New("gameId is required")
+	}
+	p.Text = strings.TrimSpace(p.Text)
+	if p.Text == "" {
+		return errors.New("text is required")
+	}
+	if utf8.RuneCountInString(p.Text) > MaxChatLength {
+		return fmt.Errorf("text must be at most %d characters", MaxChatLength)
+	}
+	return nil
+}
+
+// Emotes players can send during a game
+const (
+	EMOTE_GG        = "gg"
+	EMOTE_GOOD_MOVE = "good_move"
+	EMOTE_OOPS      = "oops"
+	EMOTE_SMILE     = "smile" // 😄
+)
+
+// Emotes lists the allowed emotes in display order
+var Emotes = []string{EMOTE_GG, EMOTE_GOOD_MOVE, EMOTE_OOPS, EMOTE_SMILE}
+
+// EmotePayload is the data of an emote message
+type EmotePayload struct {
+	GameID string `json:"gameId"`
+	Emote  string `json:"emote"`
+}
+
+func (p *EmotePayload) Validate() error {
+	if p.GameID == "" {
+		return errors.New("gameId is required")
+	}
+	for _, emote := range Emotes {
+		if p.Emote == emote {
+			return nil
+		}
+	}
+	return fmt.Errorf("emote must be one of %s", strings.Join(Emotes, ", "))
+}
+
+// MuteChatPayload is the data of a mute_spectator_chat message
+type MuteChatPayload struct {
+	GameID string `json:"gameId"`
+	Muted  bool   `json:"muted"`
+}
+
+func (p *MuteChatPayload) Validate() error {
+	if p.GameID == "" {
+		return errors.New("gameId is required")
+	}
+	return nil
+}
+
+// SwapDecisionPayload is the data of a swap_decision message; Swap false keeps the sides and O moves as usual
+type SwapDecisionPayload struct {
+	GameID string `json:"gameId"`
+	Swap   bool   `json:"swap"`
+}
+
+func (p *SwapDecisionPayload) Validate() error {
+	if p.GameID == "" {
+		return errors.New("gameId is required")
+	}
+	return nil
+}
+
+// MatchPayload is the data of accept_match and decline_match messages
+type MatchPayload struct {
+	MatchID string `json:"matchId"`
+}
+
+func (p *MatchPayload) Validate() error {
+	if p.MatchID == "" {
+		return errors.New("matchId is required")
+	}
+	return nil
+}
+
+// payloadRegistry maps each inbound message type to its payload type
+var payloadRegistry = map[string]func() Payload{
+	MSG_JOIN_QUEUE:  func() Payload { return &JoinQueuePayload{} },
+	MSG_LEAVE_QUEUE: func() Payload { return &EmptyPayload{} },
+	MSG_LEADERBOARD: func() Payload { return &LeaderboardPayload{} },
+	MSG_MAKE_MOVE:   func() Payload { return &MakeMovePayload{} },
+	MSG_GET_PROFILE: func() Payload { return &GetProfilePayload{} },
+	MSG_HELLO:       func() Payload { return &HelloPayload{} },
+	MSG_RESYNC:      func() Payload { return &GamePayload{} },
+
+	MSG_SPECTATE_GAME:       func() Payload { return &GamePayload{} },
+	MSG_STOP_SPECTATING:     func() Payload { return &GamePayload{} },
+	MSG_SPECTATOR_CHAT:      func() Payload { return &ChatPayload{} },
+	MSG_MUTE_SPECTATOR_CHAT: func() Payload { return &MuteChatPayload{} },
+	MSG_EMOTE:               func() Payload { return &EmotePayload{} },
+
+	MSG_REQUEST_TAKEBACK: func() Payload { return &GamePayload{} },
+	MSG_ACCEPT_TAKEBACK:  func() Payload { return &GamePayload{} },
+	MSG_DECLINE_TAKEBACK: func() Payload { return &GamePayload{} },
+	MSG_REQUEST_HINT:     func() Payload { return &GamePayload{} },
+	MSG_PROPOSE_MOVE:     func() Payload { return &MakeMovePayload{} },
+	MSG_SWAP_DECISION:    func() Payload { return &SwapDecisionPayload{} },
+
+	MSG_GET_RATING_HISTORY: func() Payload { return &RatingHistoryPayload{} },
+	MSG_ACCEPT_MATCH:       func() Payload { return &MatchPayload{} },
+	MSG_DECLINE_MATCH:      func() Payload { return &MatchPayload{} },
+
+	MSG_REPLAY_GAME: func() Payload { return &ReplayPayload{} },
+	MSG_REPLAY_NEXT: func() Payload { return &GamePayload{} },
+
+	MSG_GET_PUZZLE:   func() Payload { return &EmptyPayload{} },
+	MSG_SOLVE_PUZZLE: func() Payload { return &SolvePuzzlePayload{} },
+
+	MSG_PLAY_BOT: func() Payload { return &PlayBotPayload{} },
+
+	MSG_REPORT_PLAYER: func() Payload { return &ReportPlayerPayload{} },
+
+	MSG_CREATE_LOBBY: func() Payload { return &LobbyRules{} },
+	MSG_LIST_LOBBIES: func() Payload { return &EmptyPayload{} },
+	MSG_JOIN_LOBBY:   func() Payload { return &LobbyPayload{} },
+	MSG_CLOSE_LOBBY:  func() Payload { return &LobbyPayload{} },
+
+	MSG_CHALLENGE:         func() Payload { return &ChallengePayload{} },
+	MSG_PROPOSE_SETTINGS:  func() Payload { return &ProposeSettingsPayload{} },
+	MSG_ACCEPT_SETTINGS:   func() Payload { return &ChallengeIDPayload{} },
+	MSG_DECLINE_CHALLENGE: func() Payload { return &ChallengeIDPayload{} },
+
+	MSG_BLOCK_PLAYER:   func() Payload { return &BlockPayload{} },
+	MSG_UNBLOCK_PLAYER: func() Payload { return &BlockPayload{} },
+	MSG_GET_BLOCK_LIST: func() Payload { return &EmptyPayload{} },
+}
+
+// DecodePayload parses and validates the payload of an inbound message
+func DecodePayload(msg *GameMessage) (Payload, error) {
+	newPayload, exists := payloadRegistry[msg.Type]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMessageType, msg.Type)
+	}
+
+	payload := newPayload()
+	if len(msg.Data) > 0 && string(msg.Data) != "null" {
+		if err := json.Unmarshal(msg.Data, payload); err != nil {
+			return nil, NewError(ERR_INVALID_PAYLOAD, fmt.Sprintf("malformed %s payload: %v", msg.Type, err))
+		}
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, NewError(ERR_INVALID_PAYLOAD, fmt.Sprintf("invalid %s payload: %v", msg.Type, err))
+	}
+	return payload, nil
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// QueuePenalty is the data of a queue_penalty message: the player left too many games lately and must wait to queue again
+type QueuePenalty struct {
+	RemainingMs  int64     `json:"remainingMs"`
+	Until        time.Time `json:"until"`
+	RecentLeaves int       `json:"recentLeaves"` // Games left within the penalty window
+}
@@ -0,0 +1,116 @@
+package models
+
+import "time"
+
+// RatingSnapshot records a player's rating at a point in time
+type RatingSnapshot struct {
+	Rating    int       `json:"rating"`
+	GameID    string    `json:"gameId,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GameRecord is the stored summary of a finished game
+type GameRecord struct {
+	GameID          string    `json:"gameId"`
+	PlayerXID       string    `json:"playerXId"`
+	PlayerXName     string    `json:"playerXName"`
+	PlayerOID       string    `json:"playerOId"`
+	PlayerOName     string    `json:"playerOName"`
+	PlayerDeltaID   string    `json:"playerDeltaId,omitempty"` // Δ in trio games
+	PlayerDeltaName string    `json:"playerDeltaName,omitempty"`
+	Winner          Result    `json:"winner"`
+	Rated           bool      `json:"rated"`
+	FirstMover      string    `json:"firstMoverId"` // Player ID that moved first, for fairness analytics
+	StartTime       time.Time `json:"startTime"`
+	EndTime         time.Time `json:"endTime"`
+
+	Size    int    `json:"size,omitempty"`    // Cells per row and column
+	Variant string `json:"variant,omitempty"` // The rule set the game was played under
+	Moves   []int  `json:"moves,omitempty"`   // Positions in the order they were played, taken back moves left out
+}
+
+// HeadToHead summarizes a player's record against a single opponent
+type HeadToHead struct {
+	OpponentID   string `json:"opponentId"`
+	OpponentName string `json:"opponentName"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
+	Draws        int    `json:"draws"`
+}
+
+// PlayerProfile is a player's public profile with computed statistics
+type PlayerProfile struct {
+	Player             *Player          `json:"player"`
+	GamesPlayed        int              `json:"gamesPlayed"`
+	WinRate            float64          `json:"winRate"`
+	CurrentStreak      int              `json:"currentStreak"`
+	LongestStreak      int              `json:"longestStreak"`
+	AverageGameSeconds float64          `json:"averageGameSeconds"`
+	FavoriteSymbol     Symbol           `json:"favoriteSymbol"`
+	RatingHistory      []RatingSnapshot `json:"ratingHistory"`
+	HeadToHead         []HeadToHead     `json:"headToHead"`
+	Season             int              `json:"season"`
+	PlacementGamesLeft int              `json:"placementGamesLeft"` // Unranked until this reaches 0
+	Badges             []Badge          `json:"badges"`
+	Accuracy           float64          `json:"accuracy"` // Share of analyzed moves that were optimal, 0-1
+
+	IsProvisional        bool `json:"isProvisional"`        // Too few rated games for an established rating
+	ProvisionalGamesLeft int  `json:"provisionalGamesLeft"` // Rated games until the rating is established
+
+	TotalThinkMs   int64 `json:"totalThinkMs"`   // Time spent on moves across all finished games
+	AverageThinkMs int64 `json:"averageThinkMs"` // Per move
+}
+
+// NewGameRecord creates a history record from a finished game
+func NewGameRecord(game *Game) *GameRecord {
+	record := &GameRecord{
+		GameID:     game.ID,
+		Winner:     game.Winner,
+		Rated:      game.Rated,
+		FirstMover: game.FirstMoverID,
+		StartTime:  game.StartTime,
+		EndTime:    time.Now(),
+		Size:       game.Size,
+		Variant:    game.Variant,
+		Moves:      make([]int, len(game.Moves)),
+	}
+	for i, move := range game.Moves {
+		record.Moves[i] = move.Position
+	}
+	if game.EndTime != nil {
+		record.EndTime = *game.EndTime
+	}
+	if playerX := game.PlayerX(); playerX != nil {
+		record.PlayerXID = playerX.ID
+		record.PlayerXName = playerX.Name
+	}
+	if playerO := game.PlayerO(); playerO != nil {
+		record.PlayerOID = playerO.ID
+		record.PlayerOName = playerO.Name
+	}
+	if len(game.Players) == TRIO_PLAYERS {
+		record.PlayerDeltaID = game.Players[2].ID
+		record.PlayerDeltaName = game.Players[2].Name
+	}
+	return record
+}
+
+// SymbolFor returns the symbol the given player had in this game, or SYMBOL_NONE if they didn't play
+func (r *GameRecord) SymbolFor(playerID string) Symbol {
+	switch playerID {
+	case r.PlayerXID:
+		return SYMBOL_X
+	case r.PlayerOID:
+		return SYMBOL_O
+	case r.PlayerDeltaID:
+		if playerID != "" {
+			return SYMBOL_DELTA
+		}
+	}
+	return SYMBOL_NONE
+}
+
+// Duration returns how long the game lasted
+func (r *GameRecord) Duration() time.Duration {
+	return r.EndTime.Sub(r.StartTime)
+}
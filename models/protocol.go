@@ -0,0 +1,117 @@
+package models
+
+import "errors"
+
+// Protocol versions
+const (
+	PROTOCOL_VERSION_LEGACY    = 1 // Clients that never send hello
+	PROTOCOL_VERSION_MIN       = 1 // Oldest version the server still speaks
+	PROTOCOL_VERSION_DELTAS    = 3 // First version sent game_delta instead of a full game_update after each change
+	PROTOCOL_VERSION_MOVE_ACKS = 4 // First version answered every make_move with move_ack instead of error
+	PROTOCOL_VERSION_RESUME    = 5 // First version numbered the messages pushed to a player, see GameMessage.Seq
+	PROTOCOL_VERSION_CURRENT   = 5
+)
+
+// ErrUnsupportedVersion is returned when client and server share no protocol version
+var ErrUnsupportedVersion = errors.New("unsupported protocol version")
+
+// legacyMessageTypes are the server messages understood by version 1 clients
+// Any type not listed here was introduced in a later version
+var legacyMessageTypes = map[string]bool{
+	MSG_GAME_FOUND:    true,
+	MSG_GAME_UPDATE:   true,
+	MSG_GAME_END:      true,
+	MSG_ERROR:         true,
+	MSG_LEADERBOARD:   true,
+	MSG_PLAYER_UPDATE: true,
+}
+
+// messageVersions lists server messages introduced after version 2 with the version that added them
+var messageVersions = map[string]int{
+	MSG_GAME_DELTA: PROTOCOL_VERSION_DELTAS,
+	MSG_MOVE_ACK:   PROTOCOL_VERSION_MOVE_ACKS,
+}
+
+// droppableMessageTypes are server messages a slow client may miss without losing track of a game
+// Each is superseded by the next of its type, or is purely cosmetic
+var droppableMessageTypes = map[string]bool{
+	MSG_LEADERBOARD:  true,
+	MSG_LOBBY_STATS:  true,
+	MSG_CLOCK_UPDATE: true,
+	MSG_EMOTE:        true,
+}
+
+// versionAdapters rewrite a message from version v into the shape version v-1 expects
+// Register an adapter here whenever a payload field is renamed or changes meaning
+var versionAdapters = map[int]func(*GameMessage){
+	PROTOCOL_VERSION_RESUME: func(msg *GameMessage) { msg.Seq = 0 },
+}
+
+// HelloPayload is sent by clients to announce the protocol versions they support
+type HelloPayload struct {
+	Version  int   `json:"version,omitempty"`  // Highest version the client supports
+	Versions []int `json:"versions,omitempty"` // Explicit list of supported versions
+}
+
+func (p *HelloPayload) Validate() error {
+	if p.Version == 0 && len(p.Versions) == 0 {
+		return errors.New("version or versions is required")
+	}
+	return nil
+}
+
+// NegotiateVersion picks the highest version supported by both client and server
+func NegotiateVersion(hello HelloPayload) (int, error) {
+	versions := hello.Versions
+	if len(versions) == 0 && hello.Version > 0 {
+		// A single version means "up to and including"; downgrade to what we speak
+		if hello.Version >= PROTOCOL_VERSION_CURRENT {
+			return PROTOCOL_VERSION_CURRENT, nil
+		}
+		if hello.Version >= PROTOCOL_VERSION_MIN {
+			return hello.Version, nil
+		}
+		return 0, ErrUnsupportedVersion
+	}
+
+	best := 0
+	for _, v := range versions {
+		if v >= PROTOCOL_VERSION_MIN && v <= PROTOCOL_VERSION_CURRENT && v > best {
+			best = v
+		}
+	}
+	if best == 0 {
+		return 0, ErrUnsupportedVersion
+	}
+	return best, nil
+}
+
+// SupportsMessage reports whether a client on the given version understands a message type
+func SupportsMessage(version int, msgType string) bool {
+	if introduced, exists := messageVersions[msgType]; exists {
+		return version >= introduced
+	}
+	return version > PROTOCOL_VERSION_LEGACY || legacyMessageTypes[msgType]
+}
+
+// IsDroppable reports whether a message type may be discarded when a client can't keep up
+func IsDroppable(msgType string) bool {
+	return droppableMessageTypes[msgType]
+}
+
+// AdaptForVersion returns a copy of msg shaped for a client on the given version
+func AdaptForVersion(msg *GameMessage, version int) *GameMessage {
+	adapted := *msg
+	for v := PROTOCOL_VERSION_CURRENT; v > version; v-- {
+		if adapter, exists := versionAdapters[v]; exists {
+			adapter(&adapted)
+		}
+	}
+
+	if version > PROTOCOL_VERSION_LEGACY {
+		adapted.Version = version
+	} else {
+		adapted.Version = 0
+	}
+	return &adapted
+}
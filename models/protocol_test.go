@@ -0,0 +1,14 @@
+package models
+
+import "testing"
+
+func TestSeqOnlyForResumeClients(t *testing.T) {
+	msg := NewGameMessage(MSG_ANNOUNCEMENT, map[string]string{"message": "hi"})
+	msg.Seq = 7
+	if adapted := AdaptForVersion(msg, PROTOCOL_VERSION_MOVE_ACKS); adapted.Seq != 0 {
+		t.Errorf("version %d message seq = %d, want none", PROTOCOL_VERSION_MOVE_ACKS, adapted.Seq)
+	}
+	if adapted := AdaptForVersion(msg, PROTOCOL_VERSION_RESUME); adapted.Seq != 7 || msg.Seq != 7 {
+		t.Errorf("version %d message seq = %d, want 7", PROTOCOL_VERSION_RESUME, adapted.Seq)
+	}
+}
@@ -0,0 +1,263 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"tictactoe-server/proto/tictactoepb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// setTypedPayload fills in the envelope's typed payload for message types with one, reporting false when the type has
+// none or the payload carries fields the typed form can't, so it has to go as an untyped value
+func setTypedPayload(envelope *tictactoepb.Envelope, msg *GameMessage) bool {
+	switch msg.Type {
+	case MSG_GAME_FOUND, MSG_GAME_UPDATE:
+		var state GameStateView
+		if !decodeExactly(msg.Data, &state) || state.Teams != nil || state.Settings != nil {
+			return false
+		}
+		envelope.Payload = &tictactoepb.Envelope_GameState{GameState: gameStateToProto(&state)}
+		return true
+
+	case MSG_GAME_DELTA:
+		var delta GameDelta
+		if !decodeExactly(msg.Data, &delta) {
+			return false
+		}
+		cells := make([]*tictactoepb.CellChange, len(delta.Cells))
+		for i, cell := range delta.Cells {
+			cells[i] = &tictactoepb.CellChange{Position: int32(cell.Position), Symbol: string(cell.Symbol)}
+		}
+		envelope.Payload = &tictactoepb.Envelope_GameDelta{GameDelta: &tictactoepb.GameDelta{
+			GameId:              delta.GameID,
+			Seq:                 int32(delta.Seq),
+			Cells:               cells,
+			CurrentTurn:         string(delta.CurrentTurn),
+			Status:              delta.Status,
+			Winner:              string(delta.Winner),
+			MoveCount:           int32(delta.MoveCount),
+			TakebackRequestedBy: delta.TakebackRequestedBy,
+			SwapPending:         delta.SwapPending,
+			SpectatorCount:      int32(delta.SpectatorCount),
+		}}
+		return true
+
+	case MSG_MAKE_MOVE:
+		var move MakeMovePayload
+		// Moves without a position stay untyped, so validation still reports it missing
+		if !decodeExactly(msg.Data, &move) || move.Position == nil {
+			return false
+		}
+		envelope.Payload = &tictactoepb.Envelope_Move{Move: &tictactoepb.Move{
+			GameId:   move.GameID,
+			Position: int32(*move.Position),
+			MoveId:   move.MoveID,
+		}}
+		return true
+
+	case MSG_MOVE_ACK:
+		var ack MoveAck
+		if !decodeExactly(msg.Data, &ack) {
+			return false
+		}
+		envelope.Payload = &tictactoepb.Envelope_MoveAck{MoveAck: &tictactoepb.MoveAck{
+			Ok:         ack.OK,
+			MoveId:     ack.MoveID,
+			GameId:     ack.GameID,
+			PlayerId:   ack.PlayerID,
+			Symbol:     string(ack.Symbol),
+			Position:   int32(ack.Position),
+			MoveNumber: int32(ack.MoveNumber),
+			Seq:        int32(ack.Seq),
+			Code:       ack.Code,
+			Error:      ack.Error,
+		}}
+		return true
+
+	case MSG_ERROR:
+		var payload ErrorPayload
+		if !decodeExactly(msg.Data, &payload) {
+			return false
+		}
+		envelope.Payload = &tictactoepb.Envelope_Error{Error: &tictactoepb.Error{Message: payload.Error, Code: payload.Code}}
+		return true
+	}
+	return false
+}
+
+// decodeExactly decodes a JSON object into out, reporting false if it isn't one or has fields out lacks
+func decodeExactly(data json.RawMessage, out interface{}) bool {
+	if len(data) == 0 || data[0] != '{' {
+		return false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(out) == nil
+}
+
+// gameStateToProto converts a game view to its typed protobuf form
+func gameStateToProto(state *GameStateView) *tictactoepb.GameState {
+	board := make([]string, len(state.Board))
+	for i, cell := range state.Board {
+		board[i] = string(cell)
+	}
+	var winningLine []int32
+	for _, position := range state.WinningLine {
+		winningLine = append(winningLine, int32(position))
+	}
+	var seats []*tictactoepb.Seat
+	for _, seat := range state.Seats {
+		seats = append(seats, &tictactoepb.Seat{Symbol: string(seat.Symbol), Name: seat.Name, IsBot: seat.IsBot, Eliminated: seat.Eliminated})
+	}
+	var timeLeft map[string]int64
+	if state.TimeLeftMs != nil {
+		timeLeft = make(map[string]int64, len(state.TimeLeftMs))
+		for symbol, ms := range state.TimeLeftMs {
+			timeLeft[string(symbol)] = ms
+		}
+	}
+	var deadline *timestamppb.Timestamp
+	if state.MoveDeadline != nil {
+		deadline = timestamppb.New(*state.MoveDeadline)
+	}
+
+	return &tictactoepb.GameState{
+		GameId:              state.GameID,
+		Board:               board,
+		CurrentTurn:         string(state.CurrentTurn),
+		Status:              state.Status,
+		Winner:              string(state.Winner),
+		WinningLine:         winningLine,
+		MySymbol:            string(state.MySymbol),
+		OpponentName:        state.OpponentName,
+		OpponentIsBot:       state.OpponentIsBot,
+		IsMyTurn:            state.IsMyTurn,
+		Rated:               state.Rated,
+		MoveCount:           int32(state.MoveCount),
+		TakebackRequestedBy: state.TakebackRequestedBy,
+		Variant:             state.Variant,
+		Size:                int32(state.Size),
+		WinLength:           int32(state.WinLength),
+		Seq:                 int32(state.Seq),
+		SpectatorCount:      int32(state.SpectatorCount),
+		Spectating:          state.Spectating,
+		PlayerXName:         state.PlayerXName,
+		PlayerOName:         state.PlayerOName,
+		Seats:               seats,
+		TimeLeftMs:          timeLeft,
+		MoveDeadline:        deadline,
+		PieRule:             state.PieRule,
+		SwapPending:         state.SwapPending,
+		CanSwap:             state.CanSwap,
+		RatingProtected:     state.RatingProtected,
+	}
+}
+
+// gameStateFromProto converts a typed game view back to the JSON protocol's form
+func gameStateFromProto(state *tictactoepb.GameState) *GameStateView {
+	board := make([]Symbol, len(state.Board))
+	for i, cell := range state.Board {
+		board[i] = Symbol(cell)
+	}
+	var winningLine []int
+	for _, position := range state.WinningLine {
+		winningLine = append(winningLine, int(position))
+	}
+	var seats []SeatView
+	for _, seat := range state.Seats {
+		seats = append(seats, SeatView{Symbol: Symbol(seat.Symbol), Name: seat.Name, IsBot: seat.IsBot, Eliminated: seat.Eliminated})
+	}
+	var timeLeft map[Symbol]int64
+	if state.TimeLeftMs != nil {
+		timeLeft = make(map[Symbol]int64, len(state.TimeLeftMs))
+		for symbol, ms := range state.TimeLeftMs {
+			timeLeft[Symbol(symbol)] = ms
+		}
+	}
+	view := &GameStateView{
+		GameID:              state.GameId,
+		Board:               board,
+		CurrentTurn:         Symbol(state.CurrentTurn),
+		Status:              state.Status,
+		Winner:              Result(state.Winner),
+		WinningLine:         winningLine,
+		MySymbol:            Symbol(state.MySymbol),
+		OpponentName:        state.OpponentName,
+		OpponentIsBot:       state.OpponentIsBot,
+		IsMyTurn:            state.IsMyTurn,
+		Rated:               state.Rated,
+		MoveCount:           int(state.MoveCount),
+		TakebackRequestedBy: state.TakebackRequestedBy,
+		Variant:             state.Variant,
+		Size:                int(state.Size),
+		WinLength:           int(state.WinLength),
+		Seq:                 int(state.Seq),
+		SpectatorCount:      int(state.SpectatorCount),
+		Spectating:          state.Spectating,
+		PlayerXName:         state.PlayerXName,
+		PlayerOName:         state.PlayerOName,
+		Seats:               seats,
+		TimeLeftMs:          timeLeft,
+		PieRule:             state.PieRule,
+		SwapPending:         state.SwapPending,
+		CanSwap:             state.CanSwap,
+		RatingProtected:     state.RatingProtected,
+	}
+	if state.MoveDeadline != nil {
+		deadline := state.MoveDeadline.AsTime()
+		view.MoveDeadline = &deadline
+	}
+	return view
+}
+
+// typedPayloadData converts the envelope's typed payload back to the JSON protocol's form, nil if it has none
+func typedPayloadData(envelope *tictactoepb.Envelope) interface{} {
+	switch payload := envelope.Payload.(type) {
+	case *tictactoepb.Envelope_GameState:
+		return gameStateFromProto(payload.GameState)
+
+	case *tictactoepb.Envelope_GameDelta:
+		delta := payload.GameDelta
+		cells := make([]CellChange, len(delta.Cells))
+		for i, cell := range delta.Cells {
+			cells[i] = CellChange{Position: int(cell.Position), Symbol: Symbol(cell.Symbol)}
+		}
+		return &GameDelta{
+			GameID:              delta.GameId,
+			Seq:                 int(delta.Seq),
+			Cells:               cells,
+			CurrentTurn:         Symbol(delta.CurrentTurn),
+			Status:              delta.Status,
+			Winner:              Result(delta.Winner),
+			MoveCount:           int(delta.MoveCount),
+			TakebackRequestedBy: delta.TakebackRequestedBy,
+			SwapPending:         delta.SwapPending,
+			SpectatorCount:      int(delta.SpectatorCount),
+		}
+
+	case *tictactoepb.Envelope_Move:
+		position := int(payload.Move.Position)
+		return &MakeMovePayload{GameID: payload.Move.GameId, Position: &position, MoveID: payload.Move.MoveId}
+
+	case *tictactoepb.Envelope_MoveAck:
+		ack := payload.MoveAck
+		return &MoveAck{
+			OK:         ack.Ok,
+			MoveID:     ack.MoveId,
+			GameID:     ack.GameId,
+			PlayerID:   ack.PlayerId,
+			Symbol:     Symbol(ack.Symbol),
+			Position:   int(ack.Position),
+			MoveNumber: int(ack.MoveNumber),
+			Seq:        int(ack.Seq),
+			Code:       ack.Code,
+			Error:      ack.Error,
+		}
+
+	case *tictactoepb.Envelope_Error:
+		return &ErrorPayload{Error: payload.Error.Message, Code: payload.Error.Code}
+	}
+	return nil
+}
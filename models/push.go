@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MAX_PUSH_SUBSCRIPTIONS is how many browsers a player may register for push; registering another drops the oldest
+const MAX_PUSH_SUBSCRIPTIONS = 5
+
+// PushSubscription is a browser's Web Push subscription, as PushSubscription.toJSON() gives it
+type PushSubscription struct {
+	PlayerID  string    `json:"playerId"`
+	Endpoint  string    `json:"endpoint"` // The push service URL messages are posted to
+	Keys      PushKeys  `json:"keys"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PushKeys are a subscription's encryption keys, base64url encoded
+type PushKeys struct {
+	P256dh string `json:"p256dh"` // The browser's P-256 public key
+	Auth   string `json:"auth"`   // 16-byte authentication secret
+}
+
+func (s *PushSubscription) Validate() error {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return errors.New("endpoint must be an https URL")
+	}
+	if key, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s.Keys.P256dh, "=")); err != nil || len(key) != 65 {
+		return errors.New("keys.p256dh must be an uncompressed P-256 public key")
+	}
+	if auth, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s.Keys.Auth, "=")); err != nil || len(auth) != 16 {
+		return errors.New("keys.auth must be 16 bytes")
+	}
+	return nil
+}
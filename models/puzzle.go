@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Puzzle goals
+const (
+	PUZZLE_WIN  = "win"  // The player to move can force a win
+	PUZZLE_DRAW = "draw" // The player to move is in trouble but can hold a draw
+)
+
+// PUZZLE_LEADERBOARD_SIZE is how many players the puzzle leaderboard lists
+const PUZZLE_LEADERBOARD_SIZE = 10
+
+// Puzzle is a day's classic tic-tac-toe position to solve, the same for every player
+type Puzzle struct {
+	ID     string   `json:"id"`     // The UTC date it is the puzzle of, e.g. "2024-01-01"
+	Board  []Symbol `json:"board"`  // 9 cells row by row
+	ToMove Symbol   `json:"toMove"` // The symbol the player moves for
+	Goal   string   `json:"goal"`   // PUZZLE_WIN or PUZZLE_DRAW
+}
+
+// PuzzleStats is a player's puzzle record
+type PuzzleStats struct {
+	PlayerID   string `json:"playerId"`
+	Solved     int    `json:"solved"`
+	Streak     int    `json:"streak"` // Consecutive days solved, up to the last one solved
+	BestStreak int    `json:"bestStreak"`
+	LastSolved string `json:"lastSolved,omitempty"` // ID of the latest puzzle solved
+}
+
+// PuzzleView is the data of a puzzle message: today's puzzle and the asking player's record
+type PuzzleView struct {
+	Puzzle
+	SolvedToday bool `json:"solvedToday"`
+	Streak      int  `json:"streak"`
+}
+
+// SolvePuzzlePayload is the data of a solve_puzzle message
+// Moves is the whole line: the player's moves alternating with the replies they expect, ending when the game does
+type SolvePuzzlePayload struct {
+	PuzzleID string `json:"puzzleId"`
+	Moves    []int  `json:"moves"`
+}
+
+func (p *SolvePuzzlePayload) Validate() error {
+	if p.PuzzleID == "" {
+		return errors.New("puzzleId is required")
+	}
+	if len(p.Moves) == 0 || len(p.Moves) > 9 {
+		return errors.New("moves must list between 1 and 9 positions")
+	}
+	for _, position := range p.Moves {
+		if position < 0 || position > 8 {
+			return fmt.Errorf("position %d must be between 0 and 8", position)
+		}
+	}
+	return nil
+}
+
+// PuzzleResult is the data of a puzzle_result message
+type PuzzleResult struct {
+	PuzzleID   string `json:"puzzleId"`
+	Solved     bool   `json:"solved"`
+	Reason     string `json:"reason,omitempty"` // Why the line fails, when it does
+	Streak     int    `json:"streak"`
+	BestStreak int    `json:"bestStreak"`
+}
+
+// PuzzleStanding is an entry on the puzzle leaderboard
+type PuzzleStanding struct {
+	PlayerID   string `json:"playerId"`
+	Name       string `json:"name"`
+	Solved     int    `json:"solved"`
+	Streak     int    `json:"streak"`
+	BestStreak int    `json:"bestStreak"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// Limits on how many points a rating history returns
+const (
+	DEFAULT_RATING_HISTORY_POINTS = 100
+	MAX_RATING_HISTORY_POINTS     = 1000
+)
+
+// RatingHistory is the data of a rating_history message: a player's rating over time, ready to chart
+type RatingHistory struct {
+	PlayerID    string           `json:"playerId"`
+	Points      []RatingSnapshot `json:"points"`      // Oldest first
+	Total       int              `json:"total"`       // Snapshots recorded, before downsampling
+	Downsampled bool             `json:"downsampled"` // Whether Points is a sample of a longer history
+}
+
+// DownsampleRatings thins a rating history to at most maxPoints snapshots for charting
+// The first and last snapshots are always kept; the rest are split into equal runs and
+// each run is represented by its last snapshot, the rating the player ended that stretch on
+func DownsampleRatings(history []RatingSnapshot, maxPoints int) []RatingSnapshot {
+	if len(history) <= maxPoints || maxPoints < 2 {
+		return history
+	}
+
+	sampled := make([]RatingSnapshot, 0, maxPoints)
+	sampled = append(sampled, history[0])
+	rest := history[1:]
+	buckets := maxPoints - 1
+	for bucket := 1; bucket <= buckets; bucket++ {
+		end := bucket * len(rest) / buckets
+		sampled = append(sampled, rest[end-1])
+	}
+	return sampled
+}
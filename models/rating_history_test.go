@@ -0,0 +1,27 @@
+package models
+
+import "testing"
+
+func TestDownsampleRatings(t *testing.T) {
+	history := make([]RatingSnapshot, 250)
+	for i := range history {
+		history[i].Rating = i
+	}
+
+	sampled := DownsampleRatings(history, 11)
+	if len(sampled) != 11 {
+		t.Fatalf("%d points, want 11", len(sampled))
+	}
+	if sampled[0].Rating != 0 || sampled[10].Rating != 249 {
+		t.Errorf("endpoints = %d, %d; want the first and last snapshots", sampled[0].Rating, sampled[10].Rating)
+	}
+	for i := 1; i < len(sampled); i++ {
+		if sampled[i].Rating <= sampled[i-1].Rating {
+			t.Errorf("points out of order at %d: %d after %d", i, sampled[i].Rating, sampled[i-1].Rating)
+		}
+	}
+
+	if short := DownsampleRatings(history[:5], 11); len(short) != 5 {
+		t.Errorf("short history resampled to %d points", len(short))
+	}
+}
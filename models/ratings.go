@@ -0,0 +1,76 @@
+package models
+
+import (
+	"math"
+
+	"tictactoe-server/rating"
+)
+
+// A rating pool is a variant and mode combination rated separately from the others, keyed "variant/mode"
+// The modes rated games are played in are MODE_RATED for untimed games, MODE_BLITZ and MODE_CORRESPONDENCE
+
+// RATING_POOL_STANDARD is the pool of the rated queue, and the rating shown wherever a player has just one
+const RATING_POOL_STANDARD = VARIANT_STANDARD + "/" + MODE_RATED
+
+// RatingPool returns the key of the pool for a variant and mode
+func RatingPool(variant, mode string) string {
+	return variant + "/" + mode
+}
+
+// ratedMode returns the mode a time control's rated games are pooled under
+func ratedMode(timeControl string) string {
+	switch timeControl {
+	case TIME_BLITZ:
+		return MODE_BLITZ
+	case TIME_CORRESPONDENCE:
+		return MODE_CORRESPONDENCE
+	}
+	return MODE_RATED
+}
+
+// RatingPool returns the pool a game's result is rated in
+func (g *Game) RatingPool() string {
+	timeControl := TIME_NONE
+	switch {
+	case g.TimeLeft != nil:
+		timeControl = TIME_BLITZ
+	case g.IsCorrespondence():
+		timeControl = TIME_CORRESPONDENCE
+	}
+	return RatingPool(g.Variant, ratedMode(timeControl))
+}
+
+// RatingPool returns the pool a lobby's game would be rated in
+func (r LobbyRules) RatingPool() string {
+	return RatingPool(r.Variant, ratedMode(r.TimeControl))
+}
+
+// Rating returns a player's rating in a pool, DEFAULT_RATING until they play a rated game in it
+func (p *Player) Rating(pool string) int {
+	if rating, rated := p.Ratings[pool]; rated {
+		return rating
+	}
+	return DEFAULT_RATING
+}
+
+// RatedGames returns how many rated games a player has finished, over all pools
+func (p *Player) RatedGames() int {
+	return p.Wins + p.Losses + p.Draws
+}
+
+// SetRating sets a player's rating in a pool
+func (p *Player) SetRating(pool string, rating int) {
+	if p.Ratings == nil {
+		p.Ratings = make(map[string]int)
+	}
+	p.Ratings[pool] = rating
+}
+
+// SetGlicko sets a player's Glicko-2 parameters in a pool, and their rating there to match
+func (p *Player) SetGlicko(pool string, glicko rating.Glicko) {
+	if p.Glicko == nil {
+		p.Glicko = make(map[string]rating.Glicko)
+	}
+	p.Glicko[pool] = glicko
+	p.SetRating(pool, int(math.Round(glicko.Rating)))
+}
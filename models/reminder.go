@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TurnReminder is the data of a turn_reminder message: the game has been waiting on the recipient's side to move
+type TurnReminder struct {
+	GameID    string `json:"gameId"`
+	Symbol    Symbol `json:"symbol"`    // The side to move
+	WaitingMs int64  `json:"waitingMs"` // How long the turn has run
+}
+
+// GameExpiring is the data of a game_expiring message: nobody has moved in a while and the game will be adjudicated
+// unless someone does
+type GameExpiring struct {
+	GameID       string    `json:"gameId"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Adjudication string    `json:"adjudication"` // "draw", or "forfeit" for the side to move
+	Symbol       Symbol    `json:"symbol"`       // The side to move
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Playback speeds a replay_game may ask for, as multiples of the normal speed
+const (
+	MIN_REPLAY_SPEED = 0.25
+	MAX_REPLAY_SPEED = 16.0
+)
+
+// ReplayPayload is the data of a replay_game message
+type ReplayPayload struct {
+	GameID string  `json:"gameId"`
+	Speed  float64 `json:"speed"` // Multiple of the normal playback speed; 0 means 1
+	Step   bool    `json:"step"`  // Send one move per replay_next instead of playing on a timer
+}
+
+func (p *ReplayPayload) Validate() error {
+	if p.GameID == "" {
+		return errors.New("gameId is required")
+	}
+	if p.Speed == 0 {
+		p.Speed = 1
+	}
+	if p.Speed < MIN_REPLAY_SPEED || p.Speed > MAX_REPLAY_SPEED {
+		return fmt.Errorf("speed must be between %g and %g", MIN_REPLAY_SPEED, MAX_REPLAY_SPEED)
+	}
+	return nil
+}
+
+// ReplayPlayer is a player seated in a replayed game
+type ReplayPlayer struct {
+	PlayerID string `json:"playerId"`
+	Name     string `json:"name"`
+	Symbol   Symbol `json:"symbol"`
+	IsBot    bool   `json:"isBot,omitempty"`
+}
+
+// ReplayStarted is the data of a replay_started message, sent before the first move
+type ReplayStarted struct {
+	GameID     string         `json:"gameId"`
+	Size       int            `json:"size"` // Cells per row and column
+	Players    []ReplayPlayer `json:"players"`
+	TotalMoves int            `json:"totalMoves"` // Moves taken back during the game are left out
+	Step       bool           `json:"step"`
+	IntervalMs int64          `json:"intervalMs"` // Time between moves; 0 when stepping
+}
+
+// ReplayMove is the data of a replay_move message
+type ReplayMove struct {
+	GameID     string   `json:"gameId"`
+	MoveNumber int      `json:"moveNumber"` // 1 for the game's first move
+	PlayerID   string   `json:"playerId"`
+	Symbol     Symbol   `json:"symbol"`
+	Position   int      `json:"position"`
+	Board      []Symbol `json:"board"` // The board after the move
+}
+
+// ReplayFinished is the data of a replay_finished message, sent after the last move
+type ReplayFinished struct {
+	GameID string `json:"gameId"`
+	Winner Result `json:"winner"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Formats GET /api/games/{id}/export renders a finished game in
+const (
+	EXPORT_JSON = "json" // A ReplayExport
+	EXPORT_TEXT = "text" // PGN-like notation: tag pairs, then the numbered moves and the result
+	EXPORT_SVG  = "svg"  // An animated SVG of the board filling up
+	EXPORT_GIF  = "gif"  // An animated GIF of the same
+)
+
+// EXPORT_FORMATS lists the export formats, the default first
+var EXPORT_FORMATS = []string{EXPORT_JSON, EXPORT_TEXT, EXPORT_SVG, EXPORT_GIF}
+
+// ReplayExport is a finished game as exported in EXPORT_JSON
+type ReplayExport struct {
+	GameID    string         `json:"gameId"`
+	Size      int            `json:"size"` // Cells per row and column
+	Variant   string         `json:"variant"`
+	Players   []ReplayPlayer `json:"players"`
+	Moves     []ExportedMove `json:"moves"` // Moves taken back during the game are left out
+	Winner    Result         `json:"winner"`
+	StartTime time.Time      `json:"startTime"`
+	EndTime   time.Time      `json:"endTime"`
+}
+
+// ExportedMove is one move of an exported game
+type ExportedMove struct {
+	MoveNumber int       `json:"moveNumber"` // 1 for the game's first move
+	PlayerID   string    `json:"playerId"`
+	Symbol     Symbol    `json:"symbol"`
+	Position   int       `json:"position"`
+	Square     string    `json:"square"` // The cell in text notation: its column letter and row number, "a1" being top left
+	Timestamp  time.Time `json:"timestamp"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Reasons a player may be reported for
+const (
+	REPORT_ABUSE        = "abuse"        // Offensive chat or names
+	REPORT_CHEATING     = "cheating"     // Engine assistance, win trading and the like
+	REPORT_AFK_GRIEFING = "afk_griefing" // Stalling or leaving games on purpose
+)
+
+// Report review states
+const (
+	REPORT_OPEN      = "open"
+	REPORT_VERIFIED  = "verified"
+	REPORT_DISMISSED = "dismissed"
+)
+
+// MAX_REPORT_COMMENT_LENGTH bounds the free text a report may carry
+const MAX_REPORT_COMMENT_LENGTH = 500
+
+// ReportPlayerPayload is the data of a report_player message
+type ReportPlayerPayload struct {
+	PlayerID string `json:"playerId"` // The player reported
+	GameID   string `json:"gameId"`   // A game both players were in
+	Reason   string `json:"reason"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+func (p *ReportPlayerPayload) Validate() error {
+	if p.PlayerID == "" || p.GameID == "" {
+		return errors.New("playerId and gameId are required")
+	}
+	switch p.Reason {
+	case REPORT_ABUSE, REPORT_CHEATING, REPORT_AFK_GRIEFING:
+	default:
+		return fmt.Errorf("reason must be %q, %q or %q", REPORT_ABUSE, REPORT_CHEATING, REPORT_AFK_GRIEFING)
+	}
+	if len(p.Comment) > MAX_REPORT_COMMENT_LENGTH {
+		return fmt.Errorf("comment must be at most %d bytes", MAX_REPORT_COMMENT_LENGTH)
+	}
+	return nil
+}
+
+// Report is a player's complaint about another player in a game, waiting for or given an admin's review
+type Report struct {
+	ID         string     `json:"id"`
+	ReporterID string     `json:"reporterId"`
+	ReportedID string     `json:"reportedId"`
+	GameID     string     `json:"gameId"`
+	Reason     string     `json:"reason"`
+	Comment    string     `json:"comment,omitempty"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+}
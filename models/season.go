@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Season is a ranked period; ratings are compressed toward DEFAULT_RATING when it ends
+type Season struct {
+	Number    int        `json:"number"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndsAt    *time.Time `json:"endsAt,omitempty"` // Nil when seasons never roll over
+}
+
+// SeasonStanding is a player's place on a season's leaderboard
+type SeasonStanding struct {
+	Rank     int    `json:"rank"`
+	PlayerID string `json:"playerId"`
+	Name     string `json:"name"`
+	Rating   int    `json:"rating"`
+	Games    int    `json:"games"`
+}
+
+// SeasonArchive is the final leaderboard of a finished season
+type SeasonArchive struct {
+	Season
+	EndedAt   time.Time        `json:"endedAt"`
+	Standings []SeasonStanding `json:"standings"`
+}
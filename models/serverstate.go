@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ServerState is what the server saves at shutdown to pick up again when it comes back up:
+// who was waiting in the matchmaking queues and the games still being played
+type ServerState struct {
+	SavedAt time.Time      `json:"savedAt"`
+	Players []SavedPlayer  `json:"players"` // Everyone queued or seated in a saved game
+	Queues  []QueuedPlayer `json:"queues"`  // Each queue longest-waiting first
+	Games   []SavedGame    `json:"games"`
+}
+
+// SavedPlayer is a player and the session token Player leaves out, so they can reclaim the session after a restart
+type SavedPlayer struct {
+	Player *Player `json:"player"`
+	Token  string  `json:"token"`
+}
+
+// QueuedPlayer is one player waiting in a saved queue
+type QueuedPlayer struct {
+	Mode     string    `json:"mode"`
+	PlayerID string    `json:"playerId"`
+	QueuedAt time.Time `json:"queuedAt"`
+}
+
+// SavedGame is a game in progress with the server-side timings Game leaves out
+type SavedGame struct {
+	Game        *Game         `json:"game"`
+	MoveTime    time.Duration `json:"moveTime,omitempty"`    // See Game.MoveTime
+	TurnThought time.Duration `json:"turnThought,omitempty"` // See Game.TurnThought
+}
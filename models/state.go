@@ -0,0 +1,39 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrIllegalTransition is returned when a game is moved to a status its current one can't reach
+var ErrIllegalTransition = errors.New("illegal game status transition")
+
+// statusTransitions lists the statuses each status may move to; ended games move nowhere
+//
+//	waiting → playing ⇄ paused
+//	playing, paused → finished | aborted | abandoned
+var statusTransitions = map[string][]string{
+	STATUS_WAITING: {STATUS_PLAYING, STATUS_ABORTED},
+	STATUS_PLAYING: {STATUS_PAUSED, STATUS_FINISHED, STATUS_ABORTED, STATUS_ABANDONED},
+	STATUS_PAUSED:  {STATUS_PLAYING, STATUS_FINISHED, STATUS_ABORTED, STATUS_ABANDONED},
+}
+
+// CanTransition reports whether a game may move from one status to another
+func CanTransition(from, to string) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// IsEnded reports whether a status is final: finished with a result, aborted or abandoned
+func IsEnded(status string) bool {
+	return status == STATUS_FINISHED || status == STATUS_ABORTED || status == STATUS_ABANDONED
+}
+
+// Transition moves the game to a new status, refusing transitions the state machine doesn't allow
+func (g *Game) Transition(to string) error {
+	if !CanTransition(g.Status, to) {
+		return fmt.Errorf("%w: %s to %s", ErrIllegalTransition, g.Status, to)
+	}
+	g.Status = to
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{STATUS_WAITING, STATUS_PLAYING, true},
+		{STATUS_WAITING, STATUS_ABORTED, true},
+		{STATUS_WAITING, STATUS_FINISHED, false},
+		{STATUS_PLAYING, STATUS_PAUSED, true},
+		{STATUS_PAUSED, STATUS_PLAYING, true},
+		{STATUS_PAUSED, STATUS_ABANDONED, true},
+		{STATUS_PLAYING, STATUS_FINISHED, true},
+		{STATUS_PLAYING, STATUS_PLAYING, false},
+		{STATUS_PLAYING, STATUS_WAITING, false},
+		{STATUS_FINISHED, STATUS_PLAYING, false},
+		{STATUS_ABORTED, STATUS_FINISHED, false},
+		{STATUS_ABANDONED, STATUS_PAUSED, false},
+	}
+
+	for _, tt := range tests {
+		g := &Game{Status: tt.from}
+		err := g.Transition(tt.to)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("%s → %s allowed = %v, want %v", tt.from, tt.to, allowed, tt.allowed)
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, ErrIllegalTransition) {
+				t.Errorf("%s → %s error = %v, want ErrIllegalTransition", tt.from, tt.to, err)
+			}
+			if g.Status != tt.from {
+				t.Errorf("refused transition changed status to %s", g.Status)
+			}
+		} else if g.Status != tt.to {
+			t.Errorf("status = %s after %s → %s", g.Status, tt.from, tt.to)
+		}
+	}
+}
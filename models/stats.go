@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Periods stats are aggregated over
+const (
+	STATS_DAILY  = "daily"
+	STATS_WEEKLY = "weekly" // Monday to Monday, UTC
+)
+
+// StatsSummary is the aggregate of the games finished in one day or week
+type StatsSummary struct {
+	Period             string         `json:"period"` // STATS_DAILY or STATS_WEEKLY
+	Start              time.Time      `json:"start"`
+	End                time.Time      `json:"end"`
+	Games              int            `json:"games"`
+	RatedGames         int            `json:"ratedGames"`
+	UniquePlayers      int            `json:"uniquePlayers"`
+	AverageGameSeconds float64        `json:"averageGameSeconds"`
+	RatingHistogram    []RatingBucket `json:"ratingHistogram"` // Ratings of the period's players when it ended, lowest bucket first
+}
+
+// RatingBucket counts players whose rating is at least Min and below Max
+type RatingBucket struct {
+	Min     int `json:"min"`
+	Max     int `json:"max"`
+	Players int `json:"players"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Symbol is a mark on the board, and the side of a game that plays it
+type Symbol string
+
+// Symbols players are seated with
+const (
+	SYMBOL_NONE  Symbol = ""  // An empty cell, or nobody
+	SYMBOL_X     Symbol = "X" // Moves first
+	SYMBOL_O     Symbol = "O"
+	SYMBOL_DELTA Symbol = "Δ" // Trio games only
+)
+
+// Symbols are the marks in seating order; two-player games use the first two
+var Symbols = []Symbol{SYMBOL_X, SYMBOL_O, SYMBOL_DELTA}
+
+// UnmarshalText accepts one of Symbols, or none, so a typo such as "x" is refused instead of matching no side
+func (s *Symbol) UnmarshalText(text []byte) error {
+	symbol := Symbol(text)
+	if symbol != SYMBOL_NONE && !slices.Contains(Symbols, symbol) {
+		return fmt.Errorf("unknown symbol %q", text)
+	}
+	*s = symbol
+	return nil
+}
+
+// Result is how a game ended: a win for one symbol, or a draw
+type Result string
+
+// Results that aren't a win
+const (
+	RESULT_NONE Result = ""     // The game is undecided, or ended without a result
+	RESULT_DRAW Result = "draw" // Nobody won
+)
+
+// WinFor returns the result of a game won by symbol
+func WinFor(symbol Symbol) Result {
+	return Result(symbol)
+}
+
+// WinningSymbol returns the symbol a result is a win for, or SYMBOL_NONE for a draw or no result
+func (r Result) WinningSymbol() Symbol {
+	if r == RESULT_DRAW {
+		return SYMBOL_NONE
+	}
+	return Symbol(r)
+}
+
+// UnmarshalText accepts a win for one of Symbols, a draw or no result
+func (r *Result) UnmarshalText(text []byte) error {
+	result := Result(text)
+	if result != RESULT_DRAW {
+		var symbol Symbol
+		if err := symbol.UnmarshalText(text); err != nil {
+			return fmt.Errorf("unknown result %q", text)
+		}
+	}
+	*r = result
+	return nil
+}
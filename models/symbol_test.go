@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSymbolAndResultDecoding(t *testing.T) {
+	tests := []struct {
+		json  string
+		valid bool
+	}{
+		{`{"symbol":"X","winner":"O"}`, true},
+		{`{"symbol":"Δ","winner":"draw"}`, true},
+		{`{"symbol":"","winner":""}`, true},
+		{`{"symbol":"x"}`, false},
+		{`{"symbol":"Y"}`, false},
+		{`{"winner":"o"}`, false},
+		{`{"winner":"tie"}`, false},
+	}
+	for _, tt := range tests {
+		var decoded struct {
+			Symbol Symbol `json:"symbol"`
+			Winner Result `json:"winner"`
+		}
+		if err := json.Unmarshal([]byte(tt.json), &decoded); (err == nil) != tt.valid {
+			t.Errorf("Unmarshal(%s) = %v, want valid=%v", tt.json, err, tt.valid)
+		}
+	}
+
+	if got := WinFor(SYMBOL_O).WinningSymbol(); got != SYMBOL_O {
+		t.Errorf("WinFor(O).WinningSymbol() = %q", got)
+	}
+	if got := RESULT_DRAW.WinningSymbol(); got != SYMBOL_NONE {
+		t.Errorf("draw's WinningSymbol() = %q, want none", got)
+	}
+}
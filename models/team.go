@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// TEAM_SIZE is how many players share a side in team games
+const TEAM_SIZE = 2
+
+// Team is one side of a team game
+type Team struct {
+	Symbol  Symbol    `json:"symbol"`
+	Players []*Player `json:"players"` // The seated player first
+}
+
+// MoveProposal tells a team what its members have proposed for the current move
+type MoveProposal struct {
+	GameID    string         `json:"gameId"`
+	PlayerID  string         `json:"playerId"`  // Who proposed most recently
+	Position  int            `json:"position"`  // What they proposed
+	Proposals map[string]int `json:"proposals"` // Player ID -> proposed position, for every member who has proposed
+	Deadline  time.Time      `json:"deadline"`  // When the first proposal is played unless the team agrees sooner
+}
+
+// SidePlayers returns everyone playing a symbol: the seated player, plus their teammates in team games
+func (g *Game) SidePlayers(symbol Symbol) []*Player {
+	for _, team := range g.Teams {
+		if team.Symbol == symbol {
+			return team.Players
+		}
+	}
+
+	for i, seated := range g.Players {
+		if Symbols[i] == symbol {
+			return []*Player{seated}
+		}
+	}
+	return nil
+}
+
+// AllPlayers returns everyone playing in a game, side by side in turn order
+func (g *Game) AllPlayers() []*Player {
+	var players []*Player
+	for _, symbol := range g.SeatSymbols() {
+		players = append(players, g.SidePlayers(symbol)...)
+	}
+	return players
+}
+
+// SideOf returns the symbol a player plays in a game, or "" if they are not playing in it
+func (g *Game) SideOf(playerID string) Symbol {
+	for _, symbol := range g.SeatSymbols() {
+		for _, player := range g.SidePlayers(symbol) {
+			if player.ID == playerID {
+				return symbol
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ThinkStats totals how long one player took over their moves in a game
+type ThinkStats struct {
+	PlayerID  string `json:"playerId"`
+	Moves     int    `json:"moves"`
+	TotalMs   int64  `json:"totalMs"`
+	AverageMs int64  `json:"averageMs"` // 0 if the player made no moves
+}
+
+// ThinkTime returns how long the player to move has spent on their turn at now, not counting time the game was paused
+func (g *Game) ThinkTime(now time.Time) time.Duration {
+	if g.Status != STATUS_PLAYING || g.TurnStartedAt.IsZero() {
+		return g.TurnThought
+	}
+	return g.TurnThought + now.Sub(g.TurnStartedAt)
+}
+
+// ThinkStats totals each player's think time over the game's moves, in seating order
+func (g *Game) ThinkStats() []ThinkStats {
+	players := g.AllPlayers()
+	stats := make([]ThinkStats, len(players))
+	for i, player := range players {
+		stats[i].PlayerID = player.ID
+	}
+	for _, move := range g.Moves {
+		for i := range stats {
+			if stats[i].PlayerID == move.PlayerID {
+				stats[i].Moves++
+				stats[i].TotalMs += move.ThinkMs
+			}
+		}
+	}
+	for i := range stats {
+		if stats[i].Moves > 0 {
+			stats[i].AverageMs = stats[i].TotalMs / int64(stats[i].Moves)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,37 @@
+package models
+
+// Trio games seat three players on a bigger board
+const (
+	TRIO_PLAYERS    = 3
+	TRIO_BOARD_SIZE = 5
+)
+
+// NewTrioGame creates a three-player game on a 5x5 board where winLength marks in a row win
+func NewTrioGame(winLength int) *Game {
+	game := NewGame()
+	game.Size = TRIO_BOARD_SIZE
+	game.Board = make([]Symbol, TRIO_BOARD_SIZE*TRIO_BOARD_SIZE)
+	game.WinLength = winLength
+	return game
+}
+
+// IsEliminated reports whether a symbol has been knocked out of a trio game
+func (g *Game) IsEliminated(symbol Symbol) bool {
+	for _, eliminated := range g.Eliminated {
+		if eliminated == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveSymbols returns the symbols still in the game, in turn order
+func (g *Game) ActiveSymbols() []Symbol {
+	active := make([]Symbol, 0, len(g.Players))
+	for _, symbol := range g.SeatSymbols() {
+		if !g.IsEliminated(symbol) {
+			active = append(active, symbol)
+		}
+	}
+	return active
+}
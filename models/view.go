@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// GameStateView is a game as one player, or a spectator, sees it in game_found and game_update messages
+type GameStateView struct {
+	GameID              string   `json:"gameId"`
+	Board               []Symbol `json:"board"`
+	CurrentTurn         Symbol   `json:"currentTurn"`
+	Status              string   `json:"status"`
+	Winner              Result   `json:"winner"`
+	WinningLine         []int    `json:"winningLine,omitempty"` // Finished games only: the cells of the line that ended the game
+	MySymbol            Symbol   `json:"mySymbol"`              // SYMBOL_NONE for spectators
+	OpponentName        string   `json:"opponentName"`          // Empty for spectators and games with more than two seats
+	OpponentIsBot       bool     `json:"opponentIsBot"`
+	IsMyTurn            bool     `json:"isMyTurn"`
+	Rated               bool     `json:"rated"`
+	MoveCount           int      `json:"moveCount"`
+	TakebackRequestedBy string   `json:"takebackRequestedBy"`
+	Variant             string   `json:"variant"`
+	Size                int      `json:"size"`
+	WinLength           int      `json:"winLength"`
+
+	// Filled in by the server as it sends the state
+	Seq            int `json:"seq"` // The game update this state is current as of, which deltas follow on from
+	SpectatorCount int `json:"spectatorCount"`
+
+	// Spectator views only
+	Spectating  bool   `json:"spectating,omitempty"`
+	PlayerXName string `json:"playerXName,omitempty"`
+	PlayerOName string `json:"playerOName,omitempty"`
+
+	Teams        []*Team          `json:"teams,omitempty"`        // Team games only
+	Seats        []SeatView       `json:"seats,omitempty"`        // Games with more than two seats only
+	TimeLeftMs   map[Symbol]int64 `json:"timeLeftMs,omitempty"`   // Blitz games only
+	MoveDeadline *time.Time       `json:"moveDeadline,omitempty"` // Correspondence games only
+	Settings     *GameSettings    `json:"settings,omitempty"`     // Games from lobbies and challenges only
+
+	// Pie rule: O may swap sides instead of replying to X's first move
+	PieRule     bool `json:"pieRule,omitempty"`
+	SwapPending bool `json:"swapPending"`
+	CanSwap     bool `json:"canSwap"` // The viewer is O and may swap now
+
+	RatingProtected bool `json:"ratingProtected,omitempty"` // The viewer's rating won't drop if they lose this game
+}
+
+// SeatView is who plays one symbol in a game with more than two seats
+type SeatView struct {
+	Symbol     Symbol `json:"symbol"`
+	Name       string `json:"name"`
+	IsBot      bool   `json:"isBot"`
+	Eliminated bool   `json:"eliminated"`
+}
@@ -0,0 +1,58 @@
+package moderation
+
+import "context"
+
+// Kinds of text a moderator is asked about
+const (
+	KIND_NAME = "name" // Player display names; rejected outright when objectionable
+	KIND_CHAT = "chat" // Chat messages; may be censored instead of rejected
+)
+
+// Verdict is a moderator's decision on a piece of text
+type Verdict struct {
+	Allowed bool   `json:"allowed"`
+	Text    string `json:"text"`             // The text to use, possibly censored
+	Reason  string `json:"reason,omitempty"` // Why the text was rejected or changed
+}
+
+// Moderator decides whether user-supplied text may be shown to other players
+// Implementations wrap word lists or external moderation services
+type Moderator interface {
+	Moderate(ctx context.Context, kind, text string) (Verdict, error)
+}
+
+// allowAll is the moderator used when nothing is configured
+type allowAll struct{}
+
+func (allowAll) Moderate(ctx context.Context, kind, text string) (Verdict, error) {
+	return Verdict{Allowed: true, Text: text}, nil
+}
+
+// Chain runs moderators in order, passing each one the text the previous one returned
+// The first rejection or error stops the chain
+func Chain(moderators ...Moderator) Moderator {
+	if len(moderators) == 0 {
+		return allowAll{}
+	}
+	if len(moderators) == 1 {
+		return moderators[0]
+	}
+	return chain(moderators)
+}
+
+type chain []Moderator
+
+func (c chain) Moderate(ctx context.Context, kind, text string) (Verdict, error) {
+	verdict := Verdict{Allowed: true, Text: text}
+	for _, moderator := range c {
+		next, err := moderator.Moderate(ctx, kind, verdict.Text)
+		if err != nil || !next.Allowed {
+			return next, err
+		}
+		if next.Reason == "" {
+			next.Reason = verdict.Reason
+		}
+		verdict = next
+	}
+	return verdict, nil
+}
@@ -0,0 +1,97 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWordFilterNames(t *testing.T) {
+	filter := NewWordFilter([]string{"Darn", " ", "heck"})
+
+	tests := []struct {
+		name    string
+		allowed bool
+	}{
+		{"alice", true},
+		{"DarnIt", false},
+		{"d4rn", true},
+		{"x_h.e.c.k_x", false},
+		{"Heckler99", false},
+	}
+	for _, tt := range tests {
+		verdict, err := filter.Moderate(context.Background(), KIND_NAME, tt.name)
+		if err != nil || verdict.Allowed != tt.allowed {
+			t.Errorf("%q: allowed = %v, %v; want %v", tt.name, verdict.Allowed, err, tt.allowed)
+		}
+	}
+}
+
+func TestWordFilterChat(t *testing.T) {
+	filter := NewWordFilter([]string{"darn"})
+
+	verdict, _ := filter.Moderate(context.Background(), KIND_CHAT, "Darn, that was a darned good move. DARN!")
+	if !verdict.Allowed || verdict.Text != "****, that was a darned good move. ****!" || verdict.Reason == "" {
+		t.Errorf("verdict = %+v", verdict)
+	}
+
+	verdict, _ = filter.Moderate(context.Background(), KIND_CHAT, "nice one")
+	if verdict.Text != "nice one" || verdict.Reason != "" {
+		t.Errorf("clean message changed: %+v", verdict)
+	}
+}
+
+// moderatorFunc adapts a function to Moderator
+type moderatorFunc func(kind, text string) Verdict
+
+func (f moderatorFunc) Moderate(ctx context.Context, kind, text string) (Verdict, error) {
+	return f(kind, text), nil
+}
+
+func TestChain(t *testing.T) {
+	var seen string
+	reject := moderatorFunc(func(kind, text string) Verdict {
+		seen = text
+		return Verdict{Allowed: false, Text: text, Reason: "nope"}
+	})
+
+	verdict, err := Chain(NewWordFilter([]string{"darn"}), reject).Moderate(context.Background(), KIND_CHAT, "darn it")
+	if err != nil || verdict.Allowed || verdict.Reason != "nope" || seen != "**** it" {
+		t.Errorf("verdict = %+v, %v; second moderator saw %q", verdict, err, seen)
+	}
+
+	verdict, _ = Chain().Moderate(context.Background(), KIND_NAME, "anything")
+	if !verdict.Allowed || verdict.Text != "anything" {
+		t.Errorf("empty chain = %+v", verdict)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct{ Kind, Text string }
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Text == "spam" {
+			json.NewEncoder(w).Encode(Verdict{Allowed: false, Reason: "spam"})
+			return
+		}
+		json.NewEncoder(w).Encode(Verdict{Allowed: true})
+	}))
+	defer service.Close()
+
+	webhook := NewWebhook(service.URL)
+	verdict, err := webhook.Moderate(context.Background(), KIND_CHAT, "hello")
+	if err != nil || !verdict.Allowed || verdict.Text != "hello" {
+		t.Errorf("hello: %+v, %v", verdict, err)
+	}
+	verdict, err = webhook.Moderate(context.Background(), KIND_CHAT, "spam")
+	if err != nil || verdict.Allowed || verdict.Reason != "spam" {
+		t.Errorf("spam: %+v, %v", verdict, err)
+	}
+
+	service.Close()
+	if _, err := webhook.Moderate(context.Background(), KIND_CHAT, "hello"); err == nil {
+		t.Error("unreachable service did not fail")
+	}
+}
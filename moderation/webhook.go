@@ -0,0 +1,55 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Webhook asks an external HTTP service to moderate text
+// The service receives {"kind": ..., "text": ...} as a JSON POST and answers with a Verdict
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhook creates a moderator that calls the service at url
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, Client: http.DefaultClient}
+}
+
+// Moderate posts the text to the service; callers bound the wait with ctx
+func (w *Webhook) Moderate(ctx context.Context, kind, text string) (Verdict, error) {
+	body, err := json.Marshal(map[string]string{"kind": kind, "text": text})
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return Verdict{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Verdict{}, fmt.Errorf("moderation service returned %s", resp.Status)
+	}
+
+	var verdict Verdict
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return Verdict{}, fmt.Errorf("malformed moderation verdict: %v", err)
+	}
+	// Services that only answer allowed/rejected leave the text unchanged
+	if verdict.Text == "" {
+		verdict.Text = text
+	}
+	return verdict, nil
+}
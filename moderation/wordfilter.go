@@ -0,0 +1,84 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// WordFilter blocks names containing listed words and masks them in chat
+type WordFilter struct {
+	words []string // Lowercase blocked words
+}
+
+// NewWordFilter creates a filter for the given words, ignoring case and blanks
+func NewWordFilter(words []string) *WordFilter {
+	filter := &WordFilter{}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			filter.words = append(filter.words, word)
+		}
+	}
+	return filter
+}
+
+// Moderate rejects names that contain a blocked word anywhere, even split by
+// punctuation or digits, and replaces blocked words in chat with asterisks
+func (f *WordFilter) Moderate(ctx context.Context, kind, text string) (Verdict, error) {
+	if kind == KIND_NAME {
+		letters := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, text)
+		for _, word := range f.words {
+			if strings.Contains(letters, word) {
+				return Verdict{Allowed: false, Text: text, Reason: "name contains a blocked word"}, nil
+			}
+		}
+		return Verdict{Allowed: true, Text: text}, nil
+	}
+
+	masked, changed := f.mask(text)
+	verdict := Verdict{Allowed: true, Text: masked}
+	if changed {
+		verdict.Reason = "blocked words masked"
+	}
+	return verdict, nil
+}
+
+// mask replaces every whole word on the list with asterisks
+func (f *WordFilter) mask(text string) (string, bool) {
+	runes := []rune(text)
+	changed := false
+
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		inWord := i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			if f.blocked(string(runes[start:i])) {
+				for j := start; j < i; j++ {
+					runes[j] = '*'
+				}
+				changed = true
+			}
+			start = -1
+		}
+	}
+	return string(runes), changed
+}
+
+// blocked reports whether a word is on the list
+func (f *WordFilter) blocked(word string) bool {
+	word = strings.ToLower(word)
+	for _, blockedWord := range f.words {
+		if word == blockedWord {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+// maxResponseSize bounds the token and user info responses read from providers
+const maxResponseSize = 64 * 1024
+
+// stateBytes is the length of login states before encoding
+const stateBytes = 24
+
+// Provider is an OAuth2 provider players log in with through the authorization code flow
+type Provider struct {
+	Name         string // One of config.OAUTH_PROVIDERS
+	AuthURL      string // Where players are sent to log in
+	TokenURL     string // Where codes are exchanged for access tokens
+	UserURL      string // Where the access token buys the account's details
+	Scopes       []string
+	ClientID     string
+	ClientSecret string
+	Client       *http.Client
+
+	// parseUser reads the account from the user info response
+	parseUser func(body []byte) (*models.Identity, error)
+}
+
+// New creates one of config.OAUTH_PROVIDERS for the app registered with it
+func New(name string, client config.OAuthClient) (*Provider, error) {
+	provider := &Provider{
+		Name:         name,
+		ClientID:     client.ID,
+		ClientSecret: client.Secret,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+	}
+	switch name {
+	case config.OAUTH_GOOGLE:
+		provider.AuthURL = "https://accounts.google.com/o/oauth2/v2/auth"
+		provider.TokenURL = "https://oauth2.googleapis.com/token"
+		provider.UserURL = "https://openidconnect.googleapis.com/v1/userinfo"
+		provider.Scopes = []string{"openid", "profile", "email"}
+		provider.parseUser = parseGoogleUser
+	case config.OAUTH_GITHUB:
+		provider.AuthURL = "https://github.com/login/oauth/authorize"
+		provider.TokenURL = "https://github.com/login/oauth/access_token"
+		provider.UserURL = "https://api.github.com/user"
+		provider.Scopes = []string{"read:user"}
+		provider.parseUser = parseGitHubUser
+	case config.OAUTH_DISCORD:
+		provider.AuthURL = "https://discord.com/oauth2/authorize"
+		provider.TokenURL = "https://discord.com/api/oauth2/token"
+		provider.UserURL = "https://discord.com/api/users/@me"
+		provider.Scopes = []string{"identify"}
+		provider.parseUser = parseDiscordUser
+	default:
+		return nil, fmt.Errorf("unknown OAuth provider %q", name)
+	}
+	return provider, nil
+}
+
+// NewState returns a random URL-safe value that ties a provider's callback to the login that asked for it
+func NewState() (string, error) {
+	raw := make([]byte, stateBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// LoginURL is the provider page that asks the player to log in, then sends them to redirectURL with a code and state
+func (p *Provider) LoginURL(state, redirectURL string) string {
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.ClientID},
+		"redirect_uri":  {redirectURL},
+		"scope":         {strings.Join(p.Scopes, " ")},
+		"state":         {state},
+	}
+	return p.AuthURL + "?" + query.Encode()
+}
+
+// Exchange trades the code from a callback for an access token
+func (p *Provider) Exchange(ctx context.Context, code, redirectURL string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {p.ClientID},
+		"client_secret": {p.ClientSecret},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json") // GitHub answers with a form otherwise
+
+	body, err := p.do(request)
+	if err != nil {
+		return "", fmt.Errorf("exchanging code: %w", err)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("exchanging code: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("exchanging code: no access token (%s)", token.Error)
+	}
+	return token.AccessToken, nil
+}
+
+// User fetches the account an access token belongs to, with its provider, subject and name filled in
+func (p *Provider) User(ctx context.Context, accessToken string) (*models.Identity, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.UserURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	request.Header.Set("Accept", "application/json")
+
+	body, err := p.do(request)
+	if err != nil {
+		return nil, fmt.Errorf("fetching user: %w", err)
+	}
+	identity, err := p.parseUser(body)
+	if err != nil {
+		return nil, fmt.Errorf("fetching user: %w", err)
+	}
+	if identity.Subject == "" {
+		return nil, errors.New("fetching user: no account ID")
+	}
+	identity.Provider = p.Name
+	return identity, nil
+}
+
+// do sends a request and reads the body of a successful response
+func (p *Provider) do(request *http.Request) ([]byte, error) {
+	response, err := p.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %d", p.Name, response.StatusCode)
+	}
+	return body, nil
+}
+
+// parseGoogleUser reads an OpenID Connect user info response
+func parseGoogleUser(body []byte) (*models.Identity, error) {
+	var user struct {
+		Sub   string `json:"sub"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, err
+	}
+	return &models.Identity{Subject: user.Sub, Name: user.Name, Email: user.Email}, nil
+}
+
+// parseGitHubUser reads a GitHub user, whose ID is a number and whose display name is optional
+func parseGitHubUser(body []byte) (*models.Identity, error) {
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, err
+	}
+	identity := &models.Identity{Name: user.Login}
+	if user.ID != 0 {
+		identity.Subject = strconv.FormatInt(user.ID, 10)
+	}
+	if user.Name != "" {
+		identity.Name = user.Name
+	}
+	return identity, nil
+}
+
+// parseDiscordUser reads a Discord user, preferring their display name to their username
+func parseDiscordUser(body []byte) (*models.Identity, error) {
+	var user struct {
+		ID         string `json:"id"`
+		Username   string `json:"username"`
+		GlobalName string `json:"global_name"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, err
+	}
+	identity := &models.Identity{Subject: user.ID, Name: user.Username}
+	if user.GlobalName != "" {
+		identity.Name = user.GlobalName
+	}
+	return identity, nil
+}
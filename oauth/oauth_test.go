@@ -0,0 +1,46 @@
+package oauth
+
+import (
+	"net/url"
+	"testing"
+
+	"tictactoe-server/config"
+	"tictactoe-server/models"
+)
+
+func TestLoginURL(t *testing.T) {
+	provider, err := New(config.OAUTH_GOOGLE, config.OAuthClient{ID: "client", Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	login, err := url.Parse(provider.LoginURL("state-1", "https://ttt.example.com/auth/google/callback"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := login.Query()
+	if login.Host != "accounts.google.com" || query.Get("client_id") != "client" || query.Get("state") != "state-1" ||
+		query.Get("scope") != "openid profile email" || query.Get("response_type") != "code" || query.Has("client_secret") {
+		t.Errorf("login URL = %s", login)
+	}
+
+	if _, err := New("myspace", config.OAuthClient{}); err == nil {
+		t.Error("unknown provider accepted")
+	}
+}
+
+func TestParseUsers(t *testing.T) {
+	for _, tt := range []struct {
+		parse         func([]byte) (*models.Identity, error)
+		body          string
+		subject, name string
+	}{
+		{parseGoogleUser, `{"sub": "1099", "name": "Ada", "email": "ada@example.com"}`, "1099", "Ada"},
+		{parseGitHubUser, `{"id": 583231, "login": "octocat", "name": null}`, "583231", "octocat"},
+		{parseDiscordUser, `{"id": "80351110224678912", "username": "nelly", "global_name": "Nelly"}`, "80351110224678912", "Nelly"},
+	} {
+		identity, err := tt.parse([]byte(tt.body))
+		if err != nil || identity.Subject != tt.subject || identity.Name != tt.name {
+			t.Errorf("%s parsed as %+v, %v", tt.body, identity, err)
+		}
+	}
+}
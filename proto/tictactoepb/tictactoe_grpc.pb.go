@@ -0,0 +1,150 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: tictactoe.proto
+
+package tictactoepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	TicTacToe_PlayGame_FullMethodName = "/tictactoe.TicTacToe/PlayGame"
+)
+
+// TicTacToeClient is the client API for TicTacToe service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TicTacToe mirrors the WebSocket game API for native clients
+type TicTacToeClient interface {
+	// PlayGame is a player's session: client requests in, server events out.
+	// Identify with the "name", "player-id" and "token" metadata keys, like the WebSocket query parameters.
+	PlayGame(ctx context.Context, opts ...grpc.CallOption) (TicTacToe_PlayGameClient, error)
+}
+
+type ticTacToeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTicTacToeClient(cc grpc.ClientConnInterface) TicTacToeClient {
+	return &ticTacToeClient{cc}
+}
+
+func (c *ticTacToeClient) PlayGame(ctx context.Context, opts ...grpc.CallOption) (TicTacToe_PlayGameClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TicTacToe_ServiceDesc.Streams[0], TicTacToe_PlayGame_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ticTacToePlayGameClient{ClientStream: stream}
+	return x, nil
+}
+
+type TicTacToe_PlayGameClient interface {
+	Send(*ClientMessage) error
+	Recv() (*ServerMessage, error)
+	grpc.ClientStream
+}
+
+type ticTacToePlayGameClient struct {
+	grpc.ClientStream
+}
+
+func (x *ticTacToePlayGameClient) Send(m *ClientMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ticTacToePlayGameClient) Recv() (*ServerMessage, error) {
+	m := new(ServerMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TicTacToeServer is the server API for TicTacToe service.
+// All implementations must embed UnimplementedTicTacToeServer
+// for forward compatibility
+//
+// TicTacToe mirrors the WebSocket game API for native clients
+type TicTacToeServer interface {
+	// PlayGame is a player's session: client requests in, server events out.
+	// Identify with the "name", "player-id" and "token" metadata keys, like the WebSocket query parameters.
+	PlayGame(TicTacToe_PlayGameServer) error
+	mustEmbedUnimplementedTicTacToeServer()
+}
+
+// UnimplementedTicTacToeServer must be embedded to have forward compatible implementations.
+type UnimplementedTicTacToeServer struct {
+}
+
+func (UnimplementedTicTacToeServer) PlayGame(TicTacToe_PlayGameServer) error {
+	return status.Errorf(codes.Unimplemented, "method PlayGame not implemented")
+}
+func (UnimplementedTicTacToeServer) mustEmbedUnimplementedTicTacToeServer() {}
+
+// UnsafeTicTacToeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TicTacToeServer will
+// result in compilation errors.
+type UnsafeTicTacToeServer interface {
+	mustEmbedUnimplementedTicTacToeServer()
+}
+
+func RegisterTicTacToeServer(s grpc.ServiceRegistrar, srv TicTacToeServer) {
+	s.RegisterService(&TicTacToe_ServiceDesc, srv)
+}
+
+func _TicTacToe_PlayGame_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TicTacToeServer).PlayGame(&ticTacToePlayGameServer{ServerStream: stream})
+}
+
+type TicTacToe_PlayGameServer interface {
+	Send(*ServerMessage) error
+	Recv() (*ClientMessage, error)
+	grpc.ServerStream
+}
+
+type ticTacToePlayGameServer struct {
+	grpc.ServerStream
+}
+
+func (x *ticTacToePlayGameServer) Send(m *ServerMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ticTacToePlayGameServer) Recv() (*ClientMessage, error) {
+	m := new(ClientMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TicTacToe_ServiceDesc is the grpc.ServiceDesc for TicTacToe service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TicTacToe_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tictactoe.TicTacToe",
+	HandlerType: (*TicTacToeServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PlayGame",
+			Handler:       _TicTacToe_PlayGame_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "tictactoe.proto",
+}
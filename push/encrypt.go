@@ -0,0 +1,86 @@
+package push
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// recordSize is the aes128gcm record size advertised in the header; a notification always fits one record
+const recordSize = 4096
+
+// encrypt seals a payload for a subscription with the aes128gcm content coding (RFC 8291)
+// p256dh is the browser's public key and auth its authentication secret, both from the subscription
+func encrypt(payload, p256dh, auth []byte) ([]byte, error) {
+	browserKey, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, err
+	}
+	if len(auth) != 16 {
+		return nil, errors.New("auth secret must be 16 bytes")
+	}
+
+	// A new key and salt for every message, so no two messages share a content key
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := serverKey.ECDH(browserKey)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	serverPublic := serverKey.PublicKey().Bytes()
+	keyInfo := append(append([]byte("WebPush: info\x00"), p256dh...), serverPublic...)
+	ikm, err := derive(shared, auth, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	contentKey, err := derive(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := derive(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: salt, record size, key ID length and the server's public key as key ID
+	body := make([]byte, 0, 16+4+1+len(serverPublic)+len(payload)+1+gcm.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, recordSize)
+	body = append(body, byte(len(serverPublic)))
+	body = append(body, serverPublic...)
+	// 0x02 marks the last (and only) record
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+// derive runs HKDF-SHA256 and reads length bytes
+func derive(secret, salt, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,171 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// Notification kinds
+const (
+	KIND_MATCH_FOUND = "match_found"
+	KIND_YOUR_TURN   = "your_turn"
+)
+
+// queueSize bounds notifications waiting to be sent; later ones are dropped while it is full
+const queueSize = 256
+
+// Notification is the JSON a service worker receives in its push event
+type Notification struct {
+	Kind   string `json:"kind"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	GameID string `json:"gameId,omitempty"`
+}
+
+// delivery is a notification waiting to be sent to one subscription
+type delivery struct {
+	subscription *models.PushSubscription
+	notification Notification
+}
+
+// Sender encrypts notifications and posts them to push services in the background
+// Each notification is tried once: a missed "your turn" is better dropped than delivered late
+type Sender struct {
+	Keys    *Keys
+	Subject string        // Contact URL or mailto: address push services reach the operator at
+	TTL     time.Duration // How long a push service keeps a notification for an offline browser
+	Client  *http.Client
+
+	// OnGone is called from the sender's goroutine for subscriptions the push service reports expired or removed
+	OnGone func(*models.PushSubscription)
+
+	queue  chan delivery
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewSender creates a sender and starts delivering
+func NewSender(keys *Keys, subject string, ttl time.Duration) *Sender {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Sender{
+		Keys:    keys,
+		Subject: subject,
+		TTL:     ttl,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		queue:   make(chan delivery, queueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Send queues a notification for a subscription without waiting for it to be delivered
+func (s *Sender) Send(subscription *models.PushSubscription, notification Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- delivery{subscription, notification}:
+	default:
+		log.Printf("Push queue full, dropping %s notification", notification.Kind)
+	}
+}
+
+// Close stops accepting notifications and waits for queued ones to be sent
+// When ctx ends first, pending deliveries are abandoned
+func (s *Sender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		return ctx.Err()
+	}
+}
+
+// run sends queued notifications in order
+func (s *Sender) run() {
+	defer close(s.done)
+	for d := range s.queue {
+		gone, err := s.deliver(d)
+		if err != nil {
+			log.Printf("Push %s to player %s failed: %v", d.notification.Kind, d.subscription.PlayerID, err)
+		}
+		if gone && s.OnGone != nil {
+			s.OnGone(d.subscription)
+		}
+	}
+}
+
+// deliver posts one encrypted notification and reports whether the subscription no longer exists
+func (s *Sender) deliver(d delivery) (bool, error) {
+	payload, err := json.Marshal(d.notification)
+	if err != nil {
+		return false, err
+	}
+	p256dh, err := decodeBase64(d.subscription.Keys.P256dh)
+	if err != nil {
+		return false, err
+	}
+	auth, err := decodeBase64(d.subscription.Keys.Auth)
+	if err != nil {
+		return false, err
+	}
+	body, err := encrypt(payload, p256dh, auth)
+	if err != nil {
+		return false, err
+	}
+	authorization, err := s.Keys.authorization(d.subscription.Endpoint, s.Subject, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, d.subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(s.TTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return true, nil
+	default:
+		return false, fmt.Errorf("push service returned %s", resp.Status)
+	}
+}
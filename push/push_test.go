@@ -0,0 +1,188 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// testSubscription is a browser's side of a subscription: its key pair and auth secret
+type testSubscription struct {
+	key  *ecdh.PrivateKey
+	auth []byte
+}
+
+func newTestSubscription(t *testing.T) *testSubscription {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	return &testSubscription{key: key, auth: auth}
+}
+
+func (b *testSubscription) subscription(endpoint string) *models.PushSubscription {
+	return &models.PushSubscription{
+		PlayerID: "p1",
+		Endpoint: endpoint,
+		Keys: models.PushKeys{
+			P256dh: base64.RawURLEncoding.EncodeToString(b.key.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(b.auth),
+		},
+	}
+}
+
+// decrypt opens an aes128gcm body the way a browser does
+func (b *testSubscription) decrypt(t *testing.T, body []byte) []byte {
+	t.Helper()
+	salt, keyIDLength := body[:16], int(body[20])
+	if size := binary.BigEndian.Uint32(body[16:20]); size != recordSize {
+		t.Fatalf("record size %d, want %d", size, recordSize)
+	}
+	serverPublic := body[21 : 21+keyIDLength]
+	serverKey, err := ecdh.P256().NewPublicKey(serverPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, err := b.key.ECDH(serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), b.key.PublicKey().Bytes()...), serverPublic...)
+	ikm, _ := derive(shared, b.auth, keyInfo, 32)
+	contentKey, _ := derive(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce, _ := derive(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	block, _ := aes.NewCipher(contentKey)
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, body[21+keyIDLength:], nil)
+	if err != nil {
+		t.Fatalf("decrypting: %v", err)
+	}
+	if plaintext[len(plaintext)-1] != 0x02 {
+		t.Fatalf("plaintext ends in %#x, want the last record delimiter", plaintext[len(plaintext)-1])
+	}
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestEncryptedPayloadOpensWithTheBrowserKeys(t *testing.T) {
+	browser := newTestSubscription(t)
+	payload := []byte(`{"kind":"your_turn"}`)
+
+	body, err := encrypt(payload, browser.key.PublicKey().Bytes(), browser.auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := browser.decrypt(t, body); !bytes.Equal(got, payload) {
+		t.Fatalf("decrypted %q, want %q", got, payload)
+	}
+
+	if _, err := encrypt(payload, browser.key.PublicKey().Bytes(), browser.auth[:8]); err == nil {
+		t.Fatal("short auth secret accepted")
+	}
+}
+
+func TestAuthorizationCarriesASignedVAPIDToken(t *testing.T) {
+	public, private, err := GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := ParseKeys(public, private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseKeys(public, base64.RawURLEncoding.EncodeToString(make([]byte, 31))); err == nil {
+		t.Fatal("mismatched key pair accepted")
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	header, err := keys.authorization("https://push.example.com/send/abc", "mailto:ops@example.com", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, key, found := strings.Cut(strings.TrimPrefix(header, "vapid t="), ", k=")
+	if !found || key != public {
+		t.Fatalf("authorization %q, want the token and public key", header)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token %q is not a JWT", token)
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	rawClaims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	json.Unmarshal(rawClaims, &claims)
+	if claims.Aud != "https://push.example.com" || claims.Sub != "mailto:ops@example.com" || claims.Exp != now.Add(jwtLifetime).Unix() {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(&keys.private.PublicKey, digest[:], r, s) {
+		t.Fatal("token signature does not verify")
+	}
+}
+
+func TestSenderDeliversAndDropsGoneSubscriptions(t *testing.T) {
+	browser := newTestSubscription(t)
+	received := make(chan Notification, 1)
+	service := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		if r.Header.Get("Content-Encoding") != "aes128gcm" || !strings.HasPrefix(r.Header.Get("Authorization"), "vapid t=") {
+			t.Errorf("headers = %v", r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var notification Notification
+		json.Unmarshal(browser.decrypt(t, body), &notification)
+		received <- notification
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer service.Close()
+
+	public, private, _ := GenerateKeys()
+	keys, _ := ParseKeys(public, private)
+	sender := NewSender(keys, "mailto:ops@example.com", time.Minute)
+	sender.Client = service.Client()
+	gone := make(chan *models.PushSubscription, 1)
+	sender.OnGone = func(subscription *models.PushSubscription) { gone <- subscription }
+
+	sender.Send(browser.subscription(service.URL+"/ok"), Notification{Kind: KIND_YOUR_TURN, GameID: "g1"})
+	sender.Send(browser.subscription(service.URL+"/gone"), Notification{Kind: KIND_YOUR_TURN})
+	if err := sender.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := <-received; got.Kind != KIND_YOUR_TURN || got.GameID != "g1" {
+		t.Fatalf("received %+v", got)
+	}
+	if subscription := <-gone; subscription.Endpoint != service.URL+"/gone" {
+		t.Fatalf("gone %s, want the /gone subscription", subscription.Endpoint)
+	}
+}
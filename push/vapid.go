@@ -0,0 +1,95 @@
+package push
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+// jwtLifetime is how long a VAPID token is valid; push services reject tokens valid for more than a day
+const jwtLifetime = 12 * time.Hour
+
+// Keys is the server's VAPID key pair, identifying it to push services
+type Keys struct {
+	Public  string // Uncompressed P-256 point, base64url without padding; browsers pass it as applicationServerKey
+	private *ecdsa.PrivateKey
+}
+
+// ParseKeys reads a VAPID key pair given as base64url strings, as printed by GenerateKeys or web-push tools
+func ParseKeys(public, private string) (*Keys, error) {
+	rawPrivate, err := decodeBase64(private)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(rawPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID private key: %w", err)
+	}
+	if derived := base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()); derived != public {
+		return nil, errors.New("VAPID public key doesn't match the private key")
+	}
+
+	curve := elliptic.P256()
+	signer := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(rawPrivate)}
+	signer.PublicKey.Curve = curve
+	signer.PublicKey.X, signer.PublicKey.Y = curve.ScalarBaseMult(rawPrivate)
+	return &Keys{Public: public, private: signer}, nil
+}
+
+// GenerateKeys creates a new VAPID key pair as base64url strings
+func GenerateKeys() (public, private string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.Bytes()), nil
+}
+
+// authorization returns the Authorization header for a push to endpoint (RFC 8292)
+func (k *Keys) authorization(endpoint, subject string, now time.Time) (string, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": target.Scheme + "://" + target.Host,
+		"exp": now.Add(jwtLifetime).Unix(),
+		"sub": subject,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// ES256 signatures are r and s as fixed-width big-endian integers
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return "vapid t=" + token + ", k=" + k.Public, nil
+}
+
+// decodeBase64 accepts base64url with or without padding, as browsers and key tools differ
+func decodeBase64(value string) ([]byte, error) {
+	if decoded, err := base64.RawURLEncoding.DecodeString(value); err == nil {
+		return decoded, nil
+	}
+	return base64.URLEncoding.DecodeString(value)
+}
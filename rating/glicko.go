@@ -0,0 +1,118 @@
+// Package rating implements the Glicko-2 rating system, an alternative to the engine's Elo ratings
+// that tracks how certain each rating is and how erratically the player performs
+// See Glickman, "Example of the Glicko-2 system" (2013)
+package rating
+
+import "math"
+
+// Starting parameters for a player with no rated games
+const (
+	DEFAULT_DEVIATION  = 350.0 // As uncertain as a rating gets
+	DEFAULT_VOLATILITY = 0.06
+)
+
+// MIN_MIGRATED_DEVIATION is the most certain a rating converted from Elo starts out, however many games it came from
+const MIN_MIGRATED_DEVIATION = 60.0
+
+// TAU limits how quickly volatility changes; Glickman suggests 0.3 to 1.2
+const TAU = 0.5
+
+// glickoScale converts between the displayed scale and the Glicko-2 scale
+const glickoScale = 173.7178
+
+// convergence is the tolerance of the volatility iteration
+const convergence = 0.000001
+
+// Glicko is a player's Glicko-2 rating in one pool
+type Glicko struct {
+	Rating     float64 `json:"rating"`     // On the same scale as Elo ratings
+	Deviation  float64 `json:"deviation"`  // The rating is within about twice this of the player's true strength
+	Volatility float64 `json:"volatility"` // How much the player's strength is expected to fluctuate
+}
+
+// Result is one game's outcome against an opponent
+type Result struct {
+	Opponent Glicko
+	Score    float64 // 1 for a win, 0.5 for a draw, 0 for a loss
+}
+
+// FromElo converts an Elo rating into initial Glicko-2 parameters
+// The rating carries over unchanged; the deviation starts at DEFAULT_DEVIATION and narrows with the rated games
+// the Elo rating came from, down to MIN_MIGRATED_DEVIATION
+func FromElo(elo int, games int) Glicko {
+	deviation := DEFAULT_DEVIATION / math.Sqrt(1+float64(games)/5)
+	return Glicko{
+		Rating:     float64(elo),
+		Deviation:  max(deviation, MIN_MIGRATED_DEVIATION),
+		Volatility: DEFAULT_VOLATILITY,
+	}
+}
+
+// Update rates a player over one rating period's results
+// With no results only the deviation grows, as it does for an inactive player
+func (g Glicko) Update(results ...Result) Glicko {
+	mu := g.Rating / glickoScale
+	phi := g.Deviation / glickoScale
+	if len(results) == 0 {
+		return Glicko{Rating: g.Rating, Deviation: math.Sqrt(phi*phi+g.Volatility*g.Volatility) * glickoScale, Volatility: g.Volatility}
+	}
+
+	// Estimated variance of the rating from the game outcomes alone, and the improvement they indicate
+	var varianceInverse, improvement float64
+	for _, result := range results {
+		muJ := result.Opponent.Rating / glickoScale
+		gJ := reduceImpact(result.Opponent.Deviation / glickoScale)
+		expected := 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+		varianceInverse += gJ * gJ * expected * (1 - expected)
+		improvement += gJ * (result.Score - expected)
+	}
+	variance := 1 / varianceInverse
+	delta := variance * improvement
+
+	volatility := newVolatility(phi, g.Volatility, variance, delta)
+	phiStar := math.Sqrt(phi*phi + volatility*volatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/variance)
+	newMu := mu + newPhi*newPhi*improvement
+
+	return Glicko{Rating: newMu * glickoScale, Deviation: newPhi * glickoScale, Volatility: volatility}
+}
+
+// reduceImpact weighs an opponent's result down by how uncertain their rating is
+func reduceImpact(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// newVolatility finds the updated volatility with the Illinois algorithm, step 5 of the Glicko-2 system
+func newVolatility(phi, sigma, variance, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		denominator := phi*phi + variance + ex
+		return ex*(delta*delta-phi*phi-variance-ex)/(2*denominator*denominator) - (x-a)/(TAU*TAU)
+	}
+
+	lower := a
+	var upper float64
+	if delta*delta > phi*phi+variance {
+		upper = math.Log(delta*delta - phi*phi - variance)
+	} else {
+		k := 1.0
+		for f(a-k*TAU) < 0 {
+			k++
+		}
+		upper = a - k*TAU
+	}
+
+	fLower, fUpper := f(lower), f(upper)
+	for math.Abs(upper-lower) > convergence {
+		next := lower + (lower-upper)*fLower/(fUpper-fLower)
+		fNext := f(next)
+		if fNext*fUpper <= 0 {
+			lower, fLower = upper, fUpper
+		} else {
+			fLower /= 2
+		}
+		upper, fUpper = next, fNext
+	}
+	return math.Exp(lower / 2)
+}
@@ -0,0 +1,35 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGlickmanExample(t *testing.T) {
+	player := Glicko{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	updated := player.Update(
+		Result{Opponent: Glicko{Rating: 1400, Deviation: 30}, Score: 1},
+		Result{Opponent: Glicko{Rating: 1550, Deviation: 100}, Score: 0},
+		Result{Opponent: Glicko{Rating: 1700, Deviation: 300}, Score: 0},
+	)
+	if math.Abs(updated.Rating-1464.06) > 0.01 || math.Abs(updated.Deviation-151.52) > 0.01 || math.Abs(updated.Volatility-0.05999) > 0.00001 {
+		t.Errorf("updated = %+v, want 1464.06/151.52/0.05999", updated)
+	}
+
+	idle := player.Update()
+	if idle.Rating != player.Rating || idle.Deviation <= player.Deviation {
+		t.Errorf("idle period = %+v, want only the deviation to grow", idle)
+	}
+}
+
+func TestFromElo(t *testing.T) {
+	if fresh := FromElo(1000, 0); fresh.Rating != 1000 || fresh.Deviation != DEFAULT_DEVIATION || fresh.Volatility != DEFAULT_VOLATILITY {
+		t.Errorf("FromElo(1000, 0) = %+v", fresh)
+	}
+	if seasoned := FromElo(1400, 20); seasoned.Rating != 1400 || seasoned.Deviation >= DEFAULT_DEVIATION || seasoned.Deviation < MIN_MIGRATED_DEVIATION {
+		t.Errorf("FromElo(1400, 20) = %+v", seasoned)
+	}
+	if veteran := FromElo(1400, 10000); veteran.Deviation != MIN_MIGRATED_DEVIATION {
+		t.Errorf("FromElo(1400, 10000) deviation = %v, want the floor", veteran.Deviation)
+	}
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+
+	"tictactoe-server/models"
+)
+
+// AppendAdminAudit adds an entry to the end of an audit log file of JSON lines, creating it if needed
+func AppendAdminAudit(path string, entry *models.AdminAuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadAdminAudit reads the entries of an audit log file, oldest first
+// Returns nothing without an error if the file doesn't exist yet
+func ReadAdminAudit(path string) ([]*models.AdminAuditEntry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []*models.AdminAuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry models.AdminAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,743 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"sort"
+	"sync"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// MemoryStore keeps finished game records and rating history in memory
+type MemoryStore struct {
+	mutex         sync.RWMutex
+	games         []*models.GameRecord
+	playerGames   map[string][]*models.GameRecord
+	ratingHistory map[string][]models.RatingSnapshot
+	events        map[string][]models.GameEvent // Event logs keyed by game ID
+	seasons       []*models.SeasonArchive       // Finished seasons, oldest first
+	badges        map[string][]models.Badge     // Earned badges keyed by player ID, oldest first
+
+	// Aggregated stats keyed by period, oldest first
+	summaries map[string][]*models.StatsSummary
+
+	heatmaps *models.HeatmapReport // The latest move heatmaps; nil until first aggregated
+
+	puzzles     map[string]*models.Puzzle      // Daily puzzles keyed by ID
+	puzzleStats map[string]*models.PuzzleStats // Puzzle records keyed by player ID
+
+	// Compensation owed for games the server dropped, keyed by player ID
+	compensations map[string]*models.Compensation
+
+	reports []*models.Report // Player reports, oldest first
+
+	// Web Push subscriptions keyed by player ID, oldest first
+	pushSubscriptions map[string][]*models.PushSubscription
+
+	configReloads []*models.ConfigReload // Audit log of configuration reloads, oldest first
+
+	adminAudit []*models.AdminAuditEntry // Audit log of admin operations, oldest first
+
+	blocks map[string][]models.BlockedPlayer // Block lists keyed by the ID of the player who blocked, oldest first
+
+	identities map[identityKey]*models.Identity // Accounts at OAuth providers linked to players
+
+	deviceKeys map[[sha256.Size]byte]string // Player IDs keyed by a hash of the device keys they connect with
+}
+
+// identityKey names an account at an OAuth provider
+type identityKey struct {
+	provider string
+	subject  string
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		games:         make([]*models.GameRecord, 0),
+		playerGames:   make(map[string][]*models.GameRecord),
+		ratingHistory: make(map[string][]models.RatingSnapshot),
+		events:        make(map[string][]models.GameEvent),
+		badges:        make(map[string][]models.Badge),
+		summaries:     make(map[string][]*models.StatsSummary),
+		puzzles:       make(map[string]*models.Puzzle),
+		puzzleStats:   make(map[string]*models.PuzzleStats),
+		compensations: make(map[string]*models.Compensation),
+
+		pushSubscriptions: make(map[string][]*models.PushSubscription),
+
+		blocks: make(map[string][]models.BlockedPlayer),
+
+		identities: make(map[identityKey]*models.Identity),
+
+		deviceKeys: make(map[[sha256.Size]byte]string),
+	}
+}
+
+// Ping reports whether the store can serve requests; memory is always reachable
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// SaveGame stores a finished game and indexes it by every player
+func (s *MemoryStore) SaveGame(record *models.GameRecord) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.games = append(s.games, record)
+	if record.PlayerXID != "" {
+		s.playerGames[record.PlayerXID] = append(s.playerGames[record.PlayerXID], record)
+	}
+	if record.PlayerOID != "" {
+		s.playerGames[record.PlayerOID] = append(s.playerGames[record.PlayerOID], record)
+	}
+	if record.PlayerDeltaID != "" {
+		s.playerGames[record.PlayerDeltaID] = append(s.playerGames[record.PlayerDeltaID], record)
+	}
+}
+
+// GamesForPlayer returns a player's finished games, oldest first
+func (s *MemoryStore) GamesForPlayer(playerID string) []*models.GameRecord {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	games := s.playerGames[playerID]
+	result := make([]*models.GameRecord, len(games))
+	copy(result, games)
+	return result
+}
+
+// GamesEndedBetween returns the games that ended at or after start and before end, in the order they were saved
+func (s *MemoryStore) GamesEndedBetween(start, end time.Time) []*models.GameRecord {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]*models.GameRecord, 0)
+	for _, record := range s.games {
+		if !record.EndTime.Before(start) && record.EndTime.Before(end) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// AllGames returns every finished game, in the order they were saved
+func (s *MemoryStore) AllGames() []*models.GameRecord {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]*models.GameRecord, len(s.games))
+	copy(result, s.games)
+	return result
+}
+
+// AddRatingSnapshot appends a rating snapshot to a player's history
+func (s *MemoryStore) AddRatingSnapshot(playerID string, snapshot models.RatingSnapshot) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.ratingHistory[playerID] = append(s.ratingHistory[playerID], snapshot)
+}
+
+// RatingHistory returns a player's rating snapshots, oldest first
+func (s *MemoryStore) RatingHistory(playerID string) []models.RatingSnapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	history := s.ratingHistory[playerID]
+	result := make([]models.RatingSnapshot, len(history))
+	copy(result, history)
+	return result
+}
+
+// ArchiveSeason stores the final leaderboard of a finished season
+func (s *MemoryStore) ArchiveSeason(archive *models.SeasonArchive) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.seasons = append(s.seasons, archive)
+}
+
+// SeasonArchives returns finished seasons, oldest first
+func (s *MemoryStore) SeasonArchives() []*models.SeasonArchive {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]*models.SeasonArchive, len(s.seasons))
+	copy(result, s.seasons)
+	return result
+}
+
+// SeasonArchive returns a finished season by number
+func (s *MemoryStore) SeasonArchive(number int) (*models.SeasonArchive, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, archive := range s.seasons {
+		if archive.Number == number {
+			return archive, true
+		}
+	}
+	return nil, false
+}
+
+// AwardBadge gives a player a badge, reporting false if they already had it
+func (s *MemoryStore) AwardBadge(playerID string, badge models.Badge) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, earned := range s.badges[playerID] {
+		if earned.ID == badge.ID {
+			return false
+		}
+	}
+	s.badges[playerID] = append(s.badges[playerID], badge)
+	return true
+}
+
+// Badges returns a player's earned badges, oldest first
+func (s *MemoryStore) Badges(playerID string) []models.Badge {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	badges := s.badges[playerID]
+	result := make([]models.Badge, len(badges))
+	copy(result, badges)
+	return result
+}
+
+// AppendEvent adds an event to the end of a game's log, assigning its sequence number
+func (s *MemoryStore) AppendEvent(event models.GameEvent) models.GameEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	event.Seq = len(s.events[event.GameID]) + 1
+	s.events[event.GameID] = append(s.events[event.GameID], event)
+	return event
+}
+
+// GameEvents returns a game's event log in order
+func (s *MemoryStore) GameEvents(gameID string) []models.GameEvent {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	events := s.events[gameID]
+	result := make([]models.GameEvent, len(events))
+	copy(result, events)
+	return result
+}
+
+// PruneEvents drops the logs of games whose latest event is older than the cutoff
+// Returns the number of game logs removed
+func (s *MemoryStore) PruneEvents(cutoff time.Time) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pruned := 0
+	for gameID, events := range s.events {
+		if len(events) > 0 && events[len(events)-1].Timestamp.Before(cutoff) {
+			delete(s.events, gameID)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// SaveStatsSummary stores an aggregated day or week
+func (s *MemoryStore) SaveStatsSummary(summary *models.StatsSummary) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.summaries[summary.Period] = append(s.summaries[summary.Period], summary)
+}
+
+// StatsSummaries returns up to limit of a period's summaries, newest first
+func (s *MemoryStore) StatsSummaries(period string, limit int) []*models.StatsSummary {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	summaries := s.summaries[period]
+	result := make([]*models.StatsSummary, 0, min(limit, len(summaries)))
+	for i := len(summaries) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, summaries[i])
+	}
+	return result
+}
+
+// SaveHeatmaps replaces the move heatmaps with a newer aggregation
+func (s *MemoryStore) SaveHeatmaps(report *models.HeatmapReport) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.heatmaps = report
+}
+
+// Heatmaps returns the latest move heatmaps, or nil if none have been aggregated
+func (s *MemoryStore) Heatmaps() *models.HeatmapReport {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.heatmaps
+}
+
+// PlayerEvents returns every logged event a player took part in, oldest first
+func (s *MemoryStore) PlayerEvents(playerID string) []models.GameEvent {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]models.GameEvent, 0)
+	for _, events := range s.events {
+		for _, event := range events {
+			if event.PlayerID == playerID {
+				result = append(result, event)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.Before(result[j].Timestamp) })
+	return result
+}
+
+// AnonymizePlayer replaces a deleted player's name with name in every game record, event log and season archive,
+// removes the chat text and report comments they sent and forgets their rating history, badges, puzzle record and
+// any compensation owed and push subscriptions
+// IDs stay, so opponents' histories and head-to-head records still add up
+// Stored values are replaced rather than changed, since callers may still be reading the old ones
+// Returns the number of games anonymized
+func (s *MemoryStore) AnonymizePlayer(playerID, name string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	replaced := make(map[*models.GameRecord]*models.GameRecord)
+	for i, record := range s.games {
+		anonymized := *record
+		switch playerID {
+		case record.PlayerXID:
+			anonymized.PlayerXName = name
+		case record.PlayerOID:
+			anonymized.PlayerOName = name
+		case record.PlayerDeltaID:
+			anonymized.PlayerDeltaName = name
+		default:
+			continue
+		}
+		s.games[i] = &anonymized
+		replaced[record] = &anonymized
+	}
+	for _, games := range s.playerGames {
+		for i, record := range games {
+			if anonymized, exists := replaced[record]; exists {
+				games[i] = anonymized
+			}
+		}
+	}
+
+	for gameID, events := range s.events {
+		var changed []models.GameEvent
+		for i, event := range events {
+			if event.PlayerID != playerID || (event.Data["name"] == nil && event.Data["text"] == nil) {
+				continue
+			}
+			if changed == nil {
+				changed = append([]models.GameEvent(nil), events...)
+			}
+			data := make(map[string]interface{}, len(event.Data))
+			for key, value := range event.Data {
+				data[key] = value
+			}
+			if _, named := data["name"]; named {
+				data["name"] = name
+			}
+			delete(data, "text")
+			changed[i].Data = data
+		}
+		if changed != nil {
+			s.events[gameID] = changed
+		}
+	}
+
+	for i, archive := range s.seasons {
+		for j, standing := range archive.Standings {
+			if standing.PlayerID != playerID {
+				continue
+			}
+			anonymized := *archive
+			anonymized.Standings = append([]models.SeasonStanding(nil), archive.Standings...)
+			anonymized.Standings[j].Name = name
+			s.seasons[i] = &anonymized
+			break
+		}
+	}
+
+	for i, report := range s.reports {
+		if report.ReporterID == playerID && report.Comment != "" {
+			anonymized := *report
+			anonymized.Comment = ""
+			s.reports[i] = &anonymized
+		}
+	}
+
+	delete(s.ratingHistory, playerID)
+	delete(s.badges, playerID)
+	delete(s.puzzleStats, playerID)
+	delete(s.compensations, playerID)
+	delete(s.pushSubscriptions, playerID)
+	return len(replaced)
+}
+
+// SavePuzzle stores a day's puzzle
+func (s *MemoryStore) SavePuzzle(puzzle *models.Puzzle) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.puzzles[puzzle.ID] = puzzle
+}
+
+// Puzzle returns the puzzle with the given ID
+func (s *MemoryStore) Puzzle(id string) (*models.Puzzle, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	puzzle, exists := s.puzzles[id]
+	return puzzle, exists
+}
+
+// SavePuzzleStats replaces a player's puzzle record
+func (s *MemoryStore) SavePuzzleStats(stats *models.PuzzleStats) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.puzzleStats[stats.PlayerID] = stats
+}
+
+// PuzzleStats returns a player's puzzle record, empty if they never solved one
+func (s *MemoryStore) PuzzleStats(playerID string) *models.PuzzleStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if stats, exists := s.puzzleStats[playerID]; exists {
+		return stats
+	}
+	return &models.PuzzleStats{PlayerID: playerID}
+}
+
+// AllPuzzleStats returns every player's puzzle record, in no particular order
+func (s *MemoryStore) AllPuzzleStats() []*models.PuzzleStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]*models.PuzzleStats, 0, len(s.puzzleStats))
+	for _, stats := range s.puzzleStats {
+		result = append(result, stats)
+	}
+	return result
+}
+
+// AddCompensation records compensation owed to a player, replacing any still unclaimed
+func (s *MemoryStore) AddCompensation(compensation *models.Compensation) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.compensations[compensation.PlayerID] = compensation
+}
+
+// Compensation returns the compensation a player is owed, if any
+func (s *MemoryStore) Compensation(playerID string) (*models.Compensation, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	compensation, exists := s.compensations[playerID]
+	return compensation, exists
+}
+
+// ClaimCompensation removes and returns the compensation a player is owed, if any
+func (s *MemoryStore) ClaimCompensation(playerID string) (*models.Compensation, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	compensation, exists := s.compensations[playerID]
+	delete(s.compensations, playerID)
+	return compensation, exists
+}
+
+// SaveReport stores a new report, or replaces a stored one with the same ID after a review
+func (s *MemoryStore) SaveReport(report *models.Report) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, stored := range s.reports {
+		if stored.ID == report.ID {
+			s.reports[i] = report
+			return
+		}
+	}
+	s.reports = append(s.reports, report)
+}
+
+// Report returns a stored report by ID
+func (s *MemoryStore) Report(id string) (*models.Report, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, report := range s.reports {
+		if report.ID == id {
+			return report, true
+		}
+	}
+	return nil, false
+}
+
+// Reports returns every stored report, oldest first
+func (s *MemoryStore) Reports() []*models.Report {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]*models.Report(nil), s.reports...)
+}
+
+// SavePushSubscription stores a player's push subscription, replacing one with the same endpoint
+// Past MAX_PUSH_SUBSCRIPTIONS the oldest is dropped
+func (s *MemoryStore) SavePushSubscription(subscription *models.PushSubscription) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	subscriptions := s.withoutEndpoint(subscription.PlayerID, subscription.Endpoint)
+	subscriptions = append(subscriptions, subscription)
+	if len(subscriptions) > models.MAX_PUSH_SUBSCRIPTIONS {
+		subscriptions = subscriptions[len(subscriptions)-models.MAX_PUSH_SUBSCRIPTIONS:]
+	}
+	s.pushSubscriptions[subscription.PlayerID] = subscriptions
+}
+
+// PushSubscriptions returns a player's push subscriptions, oldest first
+func (s *MemoryStore) PushSubscriptions(playerID string) []*models.PushSubscription {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]*models.PushSubscription(nil), s.pushSubscriptions[playerID]...)
+}
+
+// RemovePushSubscription forgets a player's subscription by endpoint and reports whether it existed
+func (s *MemoryStore) RemovePushSubscription(playerID, endpoint string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := len(s.pushSubscriptions[playerID])
+	remaining := s.withoutEndpoint(playerID, endpoint)
+	if len(remaining) == 0 {
+		delete(s.pushSubscriptions, playerID)
+	} else {
+		s.pushSubscriptions[playerID] = remaining
+	}
+	return len(remaining) < before
+}
+
+// withoutEndpoint returns a copy of a player's subscriptions leaving out endpoint; callers hold the lock
+func (s *MemoryStore) withoutEndpoint(playerID, endpoint string) []*models.PushSubscription {
+	kept := make([]*models.PushSubscription, 0, len(s.pushSubscriptions[playerID])+1)
+	for _, subscription := range s.pushSubscriptions[playerID] {
+		if subscription.Endpoint != endpoint {
+			kept = append(kept, subscription)
+		}
+	}
+	return kept
+}
+
+// AddConfigReload records a configuration reload in the audit log
+func (s *MemoryStore) AddConfigReload(reload *models.ConfigReload) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.configReloads = append(s.configReloads, reload)
+}
+
+// ConfigReloads returns the audit log of configuration reloads, oldest first
+func (s *MemoryStore) ConfigReloads() []*models.ConfigReload {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]*models.ConfigReload{}, s.configReloads...)
+}
+
+// AddAdminAudit appends admin operations to the audit log
+func (s *MemoryStore) AddAdminAudit(entries ...*models.AdminAuditEntry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.adminAudit = append(s.adminAudit, entries...)
+}
+
+// AdminAudit returns the audit log of admin operations, oldest first
+func (s *MemoryStore) AdminAudit() []*models.AdminAuditEntry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]*models.AdminAuditEntry{}, s.adminAudit...)
+}
+
+// BlockPlayer adds a player to another's block list, reporting false if they were already on it
+func (s *MemoryStore) BlockPlayer(playerID string, blocked models.BlockedPlayer) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, existing := range s.blocks[playerID] {
+		if existing.PlayerID == blocked.PlayerID {
+			return false
+		}
+	}
+	s.blocks[playerID] = append(s.blocks[playerID], blocked)
+	return true
+}
+
+// UnblockPlayer takes a player off another's block list, reporting false if they weren't on it
+func (s *MemoryStore) UnblockPlayer(playerID, blockedID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, existing := range s.blocks[playerID] {
+		if existing.PlayerID == blockedID {
+			s.blocks[playerID] = append(s.blocks[playerID][:i:i], s.blocks[playerID][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// BlockedPlayers returns a player's block list, oldest first
+func (s *MemoryStore) BlockedPlayers(playerID string) []models.BlockedPlayer {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]models.BlockedPlayer{}, s.blocks[playerID]...)
+}
+
+// HasBlocked reports whether a player has blocked another
+func (s *MemoryStore) HasBlocked(playerID, otherID string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, existing := range s.blocks[playerID] {
+		if existing.PlayerID == otherID {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteBlocks forgets a player's block list
+func (s *MemoryStore) DeleteBlocks(playerID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.blocks, playerID)
+}
+
+// LinkIdentity links an account at an OAuth provider to a player, reporting false if the account is linked already
+// or the player has another account at that provider
+func (s *MemoryStore) LinkIdentity(identity *models.Identity) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := identityKey{identity.Provider, identity.Subject}
+	if _, exists := s.identities[key]; exists {
+		return false
+	}
+	for _, existing := range s.identities {
+		if existing.PlayerID == identity.PlayerID && existing.Provider == identity.Provider {
+			return false
+		}
+	}
+	s.identities[key] = identity
+	return true
+}
+
+// Identity returns the link of an account at an OAuth provider, if it is linked to a player
+func (s *MemoryStore) Identity(provider, subject string) (*models.Identity, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	identity, exists := s.identities[identityKey{provider, subject}]
+	return identity, exists
+}
+
+// PlayerIdentities returns the accounts at OAuth providers linked to a player, oldest first, then by provider
+func (s *MemoryStore) PlayerIdentities(playerID string) []*models.Identity {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]*models.Identity, 0)
+	for _, identity := range s.identities {
+		if identity.PlayerID == playerID {
+			result = append(result, identity)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LinkedAt.Equal(result[j].LinkedAt) {
+			return result[i].LinkedAt.Before(result[j].LinkedAt)
+		}
+		return result[i].Provider < result[j].Provider
+	})
+	return result
+}
+
+// UnlinkIdentity removes the link between a player and their account at a provider, reporting false if there was none
+func (s *MemoryStore) UnlinkIdentity(playerID, provider string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, identity := range s.identities {
+		if identity.PlayerID == playerID && identity.Provider == provider {
+			delete(s.identities, key)
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteIdentities unlinks every account at an OAuth provider from a player
+func (s *MemoryStore) DeleteIdentities(playerID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, identity := range s.identities {
+		if identity.PlayerID == playerID {
+			delete(s.identities, key)
+		}
+	}
+}
+
+// LinkDeviceKey ties a device key to a player, reporting false if it already belongs to one
+// Only a hash of the key is kept
+func (s *MemoryStore) LinkDeviceKey(key, playerID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	hash := sha256.Sum256([]byte(key))
+	if _, exists := s.deviceKeys[hash]; exists {
+		return false
+	}
+	s.deviceKeys[hash] = playerID
+	return true
+}
+
+// DevicePlayer returns the ID of the player a device key belongs to
+func (s *MemoryStore) DevicePlayer(key string) (string, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	playerID, exists := s.deviceKeys[sha256.Sum256([]byte(key))]
+	return playerID, exists
+}
+
+// DeleteDeviceKeys forgets every device key of a player
+func (s *MemoryStore) DeleteDeviceKeys(playerID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for hash, owner := range s.deviceKeys {
+		if owner == playerID {
+			delete(s.deviceKeys, hash)
+		}
+	}
+}
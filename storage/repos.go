@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"tictactoe-server/models"
+)
+
+// PlayerRepo holds every player the server knows, connected or not
+type PlayerRepo interface {
+	Player(id string) (*models.Player, bool)
+	SavePlayer(player *models.Player)
+	Players() []*models.Player // In no particular order
+}
+
+// GameRepo holds the games being played and those recently ended
+type GameRepo interface {
+	Game(id string) (*models.Game, bool)
+	SaveGame(game *models.Game)
+	RemoveGame(id string)
+	Games() []*models.Game // In no particular order
+}
+
+// QueueRepo holds the matchmaking queues: player IDs keyed by game mode, longest-waiting first
+// A player waits in at most one queue
+type QueueRepo interface {
+	Modes() []string            // Every mode with a queue, even an empty one
+	Queue(mode string) []string // The queue's player IDs, in the order they are matched
+	QueuedMode(playerID string) (string, bool)
+	QueuedAt(playerID string) (time.Time, bool)
+
+	// Enqueue inserts a player at position in a mode's queue, or at the back if position is past it,
+	// recording when they joined
+	Enqueue(mode string, position int, playerID string, at time.Time)
+
+	// Dequeue removes a player from the queue they wait in, reporting which one
+	Dequeue(playerID string) (string, bool)
+}
+
+// Repos is the state a game server runs on
+type Repos struct {
+	Players PlayerRepo
+	Games   GameRepo
+	Queues  QueueRepo
+}
+
+// NewMemoryRepos creates empty in-memory repositories with a queue for each of modes
+func NewMemoryRepos(modes ...string) Repos {
+	return Repos{
+		Players: NewMemoryPlayers(),
+		Games:   NewMemoryGames(),
+		Queues:  NewMemoryQueues(modes...),
+	}
+}
+
+// MemoryPlayers keeps players in a map
+// Like the other memory repositories it is not safe for concurrent use; the game server's hub owns it
+type MemoryPlayers struct {
+	players map[string]*models.Player
+}
+
+// NewMemoryPlayers creates an empty player repository
+func NewMemoryPlayers() *MemoryPlayers {
+	return &MemoryPlayers{players: make(map[string]*models.Player)}
+}
+
+// Player returns a player by ID
+func (r *MemoryPlayers) Player(id string) (*models.Player, bool) {
+	player, exists := r.players[id]
+	return player, exists
+}
+
+// SavePlayer adds a player, or replaces one with the same ID
+func (r *MemoryPlayers) SavePlayer(player *models.Player) {
+	r.players[player.ID] = player
+}
+
+// Players returns every player
+func (r *MemoryPlayers) Players() []*models.Player {
+	players := make([]*models.Player, 0, len(r.players))
+	for _, player := range r.players {
+		players = append(players, player)
+	}
+	return players
+}
+
+// MemoryGames keeps games in a map
+type MemoryGames struct {
+	games map[string]*models.Game
+}
+
+// NewMemoryGames creates an empty game repository
+func NewMemoryGames() *MemoryGames {
+	return &MemoryGames{games: make(map[string]*models.Game)}
+}
+
+// Game returns a game by ID
+func (r *MemoryGames) Game(id string) (*models.Game, bool) {
+	gameInstance, exists := r.games[id]
+	return gameInstance, exists
+}
+
+// SaveGame adds a game, or replaces one with the same ID
+func (r *MemoryGames) SaveGame(gameInstance *models.Game) {
+	r.games[gameInstance.ID] = gameInstance
+}
+
+// RemoveGame forgets a game
+func (r *MemoryGames) RemoveGame(id string) {
+	delete(r.games, id)
+}
+
+// Games returns every game
+func (r *MemoryGames) Games() []*models.Game {
+	games := make([]*models.Game, 0, len(r.games))
+	for _, gameInstance := range r.games {
+		games = append(games, gameInstance)
+	}
+	return games
+}
+
+// MemoryQueues keeps the matchmaking queues in slices
+type MemoryQueues struct {
+	queues   map[string][]string
+	queuedAt map[string]time.Time
+}
+
+// NewMemoryQueues creates an empty queue for each mode
+func NewMemoryQueues(modes ...string) *MemoryQueues {
+	r := &MemoryQueues{
+		queues:   make(map[string][]string, len(modes)),
+		queuedAt: make(map[string]time.Time),
+	}
+	for _, mode := range modes {
+		r.queues[mode] = []string{}
+	}
+	return r
+}
+
+// Modes returns the queued modes in alphabetical order
+func (r *MemoryQueues) Modes() []string {
+	modes := make([]string, 0, len(r.queues))
+	for mode := range r.queues {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// Queue returns a copy of a mode's queue
+func (r *MemoryQueues) Queue(mode string) []string {
+	return slices.Clone(r.queues[mode])
+}
+
+// QueuedMode returns which queue a player waits in
+func (r *MemoryQueues) QueuedMode(playerID string) (string, bool) {
+	for mode, queue := range r.queues {
+		if slices.Contains(queue, playerID) {
+			return mode, true
+		}
+	}
+	return "", false
+}
+
+// QueuedAt returns when a queued player joined their queue
+func (r *MemoryQueues) QueuedAt(playerID string) (time.Time, bool) {
+	at, queued := r.queuedAt[playerID]
+	return at, queued
+}
+
+// Enqueue inserts a player into a mode's queue
+func (r *MemoryQueues) Enqueue(mode string, position int, playerID string, at time.Time) {
+	queue := r.queues[mode]
+	r.queues[mode] = slices.Insert(queue, min(position, len(queue)), playerID)
+	r.queuedAt[playerID] = at
+}
+
+// Dequeue removes a player from their queue
+func (r *MemoryQueues) Dequeue(playerID string) (string, bool) {
+	for mode, queue := range r.queues {
+		if i := slices.Index(queue, playerID); i >= 0 {
+			r.queues[mode] = slices.Delete(slices.Clone(queue), i, i+1)
+			delete(r.queuedAt, playerID)
+			return mode, true
+		}
+	}
+	return "", false
+}
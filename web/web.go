@@ -0,0 +1,109 @@
+package web
+
+import (
+	"embed"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+//go:embed all:dist
+var embedded embed.FS
+
+// Cache lifetimes; index.html is always revalidated so a deploy takes effect on the next load
+const (
+	CACHE_INDEX     = "no-cache"
+	CACHE_IMMUTABLE = "public, max-age=31536000, immutable" // Bundler output under assets/ or static/, named by content hash
+	CACHE_DEFAULT   = "public, max-age=3600"
+)
+
+// immutableDirs hold files whose names change whenever their content does
+var immutableDirs = []string{"assets/", "static/"}
+
+// Embedded returns the frontend compiled into the binary from web/dist
+func Embedded() fs.FS {
+	dist, err := fs.Sub(embedded, "dist")
+	if err != nil {
+		panic(err) // dist is embedded at build time, so this can't fail
+	}
+	return dist
+}
+
+// Dir returns the frontend in a directory on disk, which must contain index.html
+func Dir(dir string) (fs.FS, error) {
+	fsys := os.DirFS(dir)
+	if _, err := fs.Stat(fsys, "index.html"); err != nil {
+		return nil, err
+	}
+	return fsys, nil
+}
+
+// Handler serves a single-page app from fsys
+// Files are served by path; other paths without a file extension get index.html so client-side routes
+// such as /games/123 load the app, while missing files such as /logo.png are 404s
+func Handler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "index.html"
+		}
+		err := serveFile(w, r, fsys, name)
+		if errors.Is(err, fs.ErrNotExist) && path.Ext(name) == "" {
+			err = serveFile(w, r, fsys, "index.html")
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+		} else if err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+	})
+}
+
+// serveFile writes one file with its cache policy; directories count as missing
+func serveFile(w http.ResponseWriter, r *http.Request, fsys fs.FS, name string) error {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fs.ErrNotExist
+	}
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		return errors.New("file is not seekable")
+	}
+
+	w.Header().Set("Cache-Control", cachePolicy(name))
+	// Embedded files have no modification time, so ServeContent leaves out Last-Modified for them
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return nil
+}
+
+// cachePolicy picks the Cache-Control header for a file
+func cachePolicy(name string) string {
+	if name == "index.html" {
+		return CACHE_INDEX
+	}
+	for _, dir := range immutableDirs {
+		if strings.HasPrefix(name, dir) {
+			return CACHE_IMMUTABLE
+		}
+	}
+	return CACHE_DEFAULT
+}
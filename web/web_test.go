@@ -0,0 +1,70 @@
+package web
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHandlerServesSinglePageApp(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":         {Data: []byte("<html>app</html>")},
+		"favicon.ico":        {Data: []byte("icon")},
+		"assets/app-3f9a.js": {Data: []byte("console.log(1)")},
+	}
+	handler := Handler(fsys)
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+		cache  string
+	}{
+		{"/", http.StatusOK, "<html>app</html>", CACHE_INDEX},
+		{"/assets/app-3f9a.js", http.StatusOK, "console.log(1)", CACHE_IMMUTABLE},
+		{"/favicon.ico", http.StatusOK, "icon", CACHE_DEFAULT},
+		{"/games/123", http.StatusOK, "<html>app</html>", CACHE_INDEX}, // Client-side route
+		{"/assets", http.StatusOK, "<html>app</html>", CACHE_INDEX},    // Directories aren't listed
+		{"/missing.png", http.StatusNotFound, "", ""},
+		{"/../../etc/hosts.txt", http.StatusNotFound, "", ""}, // Cleaned to etc/hosts.txt inside fsys
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.path, recorder.Code, tt.status)
+			continue
+		}
+		if tt.body != "" && recorder.Body.String() != tt.body {
+			t.Errorf("%s: body = %q, want %q", tt.path, recorder.Body.String(), tt.body)
+		}
+		if got := recorder.Header().Get("Cache-Control"); tt.cache != "" && got != tt.cache {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.path, got, tt.cache)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", recorder.Code)
+	}
+}
+
+func TestEmbeddedHasIndex(t *testing.T) {
+	index, err := fs.ReadFile(Embedded(), "index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "<html") {
+		t.Errorf("embedded index.html = %q", index)
+	}
+}
+
+func TestDirRequiresIndex(t *testing.T) {
+	if _, err := Dir(t.TempDir()); err == nil {
+		t.Error("accepted a directory without index.html")
+	}
+}
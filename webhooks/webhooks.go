@@ -0,0 +1,199 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events sent to subscribers
+const (
+	EVENT_GAME_STARTED      = "game_started"
+	EVENT_GAME_FINISHED     = "game_finished"
+	EVENT_PLAYER_REGISTERED = "player_registered"
+	EVENT_TURN_REMINDER     = "turn_reminder"
+	EVENT_STATS_REPORT      = "stats_report" // A day's or week's summary, sent to the operator's report URLs
+
+	EVENT_CORRESPONDENCE_TURN = "correspondence_turn" // A correspondence game waits on a player, who may not be connected
+)
+
+// Headers set on every delivery
+const (
+	HEADER_EVENT     = "X-Webhook-Event"
+	HEADER_ID        = "X-Webhook-Id"
+	HEADER_SIGNATURE = "X-Webhook-Signature" // "sha256=" and the hex HMAC-SHA256 of the body keyed with the secret
+)
+
+// queueSize bounds events waiting for delivery; later events are dropped while it is full
+const queueSize = 256
+
+// Event is the JSON body posted to subscribers
+type Event struct {
+	ID        string          `json:"id"` // Unique per event; retries reuse it so subscribers can drop duplicates
+	Type      string          `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Dispatcher posts events to subscriber URLs in the background, retrying failed deliveries
+type Dispatcher struct {
+	URLs        []string
+	Secret      string        // Signs each body; empty sends unsigned events
+	MaxAttempts int           // Deliveries tried per URL before an event is given up
+	Backoff     time.Duration // Wait before the first retry, doubled for each later one
+	Client      *http.Client
+
+	queue  chan *Event
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewDispatcher creates a dispatcher for the given URLs and starts delivering
+func NewDispatcher(urls []string, secret string, maxAttempts int, backoff time.Duration) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		URLs:        urls,
+		Secret:      secret,
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan *Event, queueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Send queues an event without waiting for it to be delivered
+// data is encoded before Send returns, so callers may change it afterwards
+func (d *Dispatcher) Send(eventType string, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Webhook %s not sent: %v", eventType, err)
+		return
+	}
+	event := &Event{ID: uuid.New().String(), Type: eventType, Timestamp: time.Now(), Data: body}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	select {
+	case d.queue <- event:
+	default:
+		log.Printf("Webhook queue full, dropping %s event", eventType)
+	}
+}
+
+// Close stops accepting events and waits for queued ones to be delivered
+// When ctx ends first, pending deliveries and retries are abandoned
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-d.done
+		return ctx.Err()
+	}
+}
+
+// run delivers queued events in order, to every URL at once
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for event := range d.queue {
+		body, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Webhook %s not sent: %v", event.Type, err)
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for _, url := range d.URLs {
+			wg.Add(1)
+			go func(url string) {
+				defer wg.Done()
+				if err := d.deliver(url, event, body); err != nil {
+					log.Printf("Webhook %s %s to %s failed: %v", event.Type, event.ID, url, err)
+				}
+			}(url)
+		}
+		wg.Wait()
+	}
+}
+
+// deliver posts an event to one URL, retrying network errors, 429s and 5xx answers with backoff
+func (d *Dispatcher) deliver(url string, event *Event, body []byte) error {
+	wait := d.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		retry, err = d.post(url, event, body)
+		if err == nil || !retry || attempt >= d.MaxAttempts {
+			return err
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-d.ctx.Done():
+			return err
+		}
+		wait *= 2
+	}
+}
+
+// post makes a single delivery attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) post(url string, event *Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HEADER_EVENT, event.Type)
+	req.Header.Set(HEADER_ID, event.ID)
+	if d.Secret != "" {
+		req.Header.Set(HEADER_SIGNATURE, Sign(d.Secret, body))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("subscriber returned %s", resp.Status)
+}
+
+// Sign returns the signature header value for a body; subscribers recompute it to verify deliveries
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcherSignsAndRetries(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	var delivered []Event
+
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get(HEADER_SIGNATURE); got != Sign("s3cret", body) {
+			t.Errorf("signature %q does not match the body", got)
+		}
+
+		var event Event
+		json.Unmarshal(body, &event)
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[event.Type]++
+		switch {
+		case event.Type == "rejected":
+			w.WriteHeader(http.StatusBadRequest)
+		case attempts[event.Type] < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			delivered = append(delivered, event)
+		}
+	}))
+	defer subscriber.Close()
+
+	d := NewDispatcher([]string{subscriber.URL}, "s3cret", 3, time.Millisecond)
+	d.Send(EVENT_PLAYER_REGISTERED, map[string]string{"playerId": "p1"})
+	d.Send("rejected", nil)
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	d.Send(EVENT_GAME_STARTED, nil) // Ignored after Close
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts[EVENT_PLAYER_REGISTERED] != 3 || len(delivered) != 1 {
+		t.Fatalf("attempts = %v, delivered %d events; want 3 attempts and 1 delivery", attempts, len(delivered))
+	}
+	if string(delivered[0].Data) != `{"playerId":"p1"}` || delivered[0].ID == "" {
+		t.Errorf("delivered %+v", delivered[0])
+	}
+	if attempts["rejected"] != 1 {
+		t.Errorf("4xx answer retried %d times", attempts["rejected"]-1)
+	}
+	if attempts[EVENT_GAME_STARTED] != 0 {
+		t.Error("event sent after Close was delivered")
+	}
+}